@@ -0,0 +1,27 @@
+package v1
+
+import (
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+type MailReplyListReq struct {
+	KbId   string                 `query:"kb_id" json:"kb_id" validate:"required"`
+	AppID  string                 `query:"app_id" json:"app_id"`
+	Status consts.MailReplyStatus `query:"status" json:"status"`
+	domain.Pager
+}
+
+type MailReplyListResp = domain.PaginatedResult[[]*domain.MailReply]
+
+type MailReplyUpdateReq struct {
+	KbId    string `json:"kb_id" validate:"required"`
+	ID      string `json:"id" validate:"required"`
+	Subject string `json:"subject" validate:"required"`
+	Answer  string `json:"answer" validate:"required"`
+}
+
+type MailReplyActionReq struct {
+	KbId string `json:"kb_id" validate:"required"`
+	ID   string `json:"id" validate:"required"`
+}
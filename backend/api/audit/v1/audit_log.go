@@ -0,0 +1,57 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+type AuditLogFilterReq struct {
+	KBID       string `json:"kb_id" query:"kb_id"`
+	ActorID    string `json:"actor_id" query:"actor_id"`
+	Action     string `json:"action" query:"action"`
+	TargetType string `json:"target_type" query:"target_type"`
+	TargetID   string `json:"target_id" query:"target_id"`
+	Start      int64  `json:"start" query:"start"` // unix 秒，包含
+	End        int64  `json:"end" query:"end"`     // unix 秒，不包含
+}
+
+func (r *AuditLogFilterReq) Filter() *domain.AuditLogFilter {
+	filter := &domain.AuditLogFilter{
+		KBID:       r.KBID,
+		ActorID:    r.ActorID,
+		Action:     r.Action,
+		TargetType: r.TargetType,
+		TargetID:   r.TargetID,
+	}
+	if r.Start > 0 {
+		filter.Start = time.Unix(r.Start, 0)
+	}
+	if r.End > 0 {
+		filter.End = time.Unix(r.End, 0)
+	}
+	return filter
+}
+
+type AuditLogListReq struct {
+	AuditLogFilterReq
+	domain.Pager
+}
+
+type AuditLogListResp = domain.PaginatedResult[[]domain.AuditLog]
+
+type AuditLogExportReq struct {
+	AuditLogFilterReq
+	Format string `json:"format" query:"format" validate:"omitempty,oneof=csv jsonl"` // 默认 csv
+}
+
+type AuditLogVerifyResp struct {
+	Valid    bool   `json:"valid"`
+	Checked  int64  `json:"checked"`
+	BrokenID int64  `json:"broken_id,omitempty"` // 第一条校验失败的记录
+	Reason   string `json:"reason,omitempty"`
+}
+
+type AuditLogSettingReq struct {
+	RetentionDays int `json:"retention_days" validate:"min=0"`
+}
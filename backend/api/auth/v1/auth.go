@@ -4,19 +4,25 @@ import (
 	"time"
 
 	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
 )
 
 type AuthGetReq struct {
 	KBID       string            `json:"kb_id,omitempty"  query:"kb_id"`
-	SourceType consts.SourceType `query:"source_type"  json:"source_type" validate:"required,oneof=github"`
+	SourceType consts.SourceType `query:"source_type"  json:"source_type" validate:"required,oneof=github ldap cas oauth saml dingtalk feishu wecom"`
 }
 
 type AuthGetResp struct {
-	ClientID     string            `json:"client_id"`
-	ClientSecret string            `json:"client_secret"`
-	Proxy        string            `json:"proxy"`
-	SourceType   consts.SourceType `json:"source_type"`
-	Auths        []AuthItem        `json:"auths"`
+	ClientID     string                   `json:"client_id"`
+	ClientSecret string                   `json:"client_secret"`
+	Proxy        string                   `json:"proxy"`
+	SourceType   consts.SourceType        `json:"source_type"`
+	LDAP         *domain.LDAPAuthSetting  `json:"ldap,omitempty"`
+	CAS          *domain.CASAuthSetting   `json:"cas,omitempty"`
+	OAuth        *domain.OAuthAuthSetting `json:"oauth,omitempty"`
+	SAML         *domain.SAMLAuthSetting  `json:"saml,omitempty"`
+	GroupSync    bool                     `json:"group_sync"`
+	Auths        []AuthItem               `json:"auths"`
 }
 
 type AuthItem struct {
@@ -27,14 +33,21 @@ type AuthItem struct {
 	SourceType    consts.SourceType `gorm:"column:source_type;not null" json:"source_type,omitempty"`
 	LastLoginTime time.Time         `gorm:"column:last_login_time" json:"last_login_time,omitempty"`
 	CreatedAt     time.Time         `gorm:"column:created_at;not null;default:now()" json:"created_at"`
+	Active        bool              `json:"active"`
 }
 
 type AuthSetReq struct {
 	KBID         string            `json:"kb_id,omitempty"`
-	SourceType   consts.SourceType `query:"source_type"  json:"source_type" validate:"required,oneof=github"`
+	SourceType   consts.SourceType `query:"source_type"  json:"source_type" validate:"required,oneof=github ldap cas oauth saml dingtalk feishu wecom"`
 	ClientID     string            `json:"client_id"`
 	ClientSecret string            `json:"client_secret"`
 	Proxy        string            `json:"proxy"`
+	GroupSync    bool              `json:"group_sync"` // 仅 ldap、dingtalk、feishu、wecom 支持
+
+	LDAP  *domain.LDAPAuthSetting  `json:"ldap,omitempty" validate:"required_if=SourceType ldap"`
+	CAS   *domain.CASAuthSetting   `json:"cas,omitempty" validate:"required_if=SourceType cas"`
+	OAuth *domain.OAuthAuthSetting `json:"oauth,omitempty" validate:"required_if=SourceType oauth"`
+	SAML  *domain.SAMLAuthSetting  `json:"saml,omitempty" validate:"required_if=SourceType saml"`
 }
 
 type AuthSetResp struct{}
@@ -46,3 +59,41 @@ type AuthDeleteReq struct {
 
 type AuthDeleteResp struct {
 }
+
+type AuthGroupSyncReq struct {
+	KBID       string            `json:"kb_id" validate:"required"`
+	SourceType consts.SourceType `json:"source_type" validate:"required,oneof=ldap dingtalk feishu wecom"`
+	DryRun     bool              `json:"dry_run"` // 仅计算差异，不修改用户组
+}
+
+type AuthGroupSyncRunListReq struct {
+	KBID       string            `json:"kb_id" query:"kb_id" validate:"required"`
+	SourceType consts.SourceType `json:"source_type" query:"source_type" validate:"omitempty,oneof=ldap dingtalk feishu wecom"`
+	domain.Pager
+}
+
+type AuthGroupSyncRunListResp = domain.PaginatedResult[[]domain.AuthGroupSyncRun]
+
+type AuthSCIMGetReq struct {
+	KBID string `json:"kb_id" query:"kb_id" validate:"required"`
+}
+
+type AuthSCIMGetResp struct {
+	Enabled        bool              `json:"enabled"`
+	SourceType     consts.SourceType `json:"source_type"`
+	TokenCreatedAt *time.Time        `json:"token_created_at,omitempty"`
+}
+
+type AuthSCIMSetReq struct {
+	KBID       string            `json:"kb_id" validate:"required"`
+	SourceType consts.SourceType `json:"source_type" validate:"required,oneof=ldap cas oauth saml dingtalk feishu wecom"` // 开通的读者使用的登录方式
+	ResetToken bool              `json:"reset_token"`                                                                     // 重新生成 token，旧 token 立即失效
+}
+
+type AuthSCIMSetResp struct {
+	Token string `json:"token,omitempty"` // 仅在生成时返回一次
+}
+
+type AuthSCIMDeleteReq struct {
+	KBID string `json:"kb_id" query:"kb_id" validate:"required"`
+}
@@ -0,0 +1,37 @@
+package v1
+
+import "github.com/chaitin/panda-wiki/domain"
+
+type GetEscalationSettingsReq struct {
+	KbId string `query:"kb_id" json:"kb_id" validate:"required"`
+}
+
+type GetEscalationSettingsResp = domain.EscalationSettings
+
+type UpdateEscalationSettingsReq struct {
+	KbId             string                 `json:"kb_id" validate:"required"`
+	Enabled          bool                   `json:"enabled"`
+	Keywords         []string               `json:"keywords"`
+	OnNoResult       bool                   `json:"on_no_result"`
+	DislikeThreshold int                    `json:"dislike_threshold" validate:"min=0"`
+	HandoffMessage   string                 `json:"handoff_message"`
+	Webhooks         []domain.NotifyWebhook `json:"webhooks" validate:"dive"`
+	Emails           []string               `json:"emails" validate:"dive,email"`
+}
+
+type EscalationReplyReq struct {
+	KbId           string `json:"kb_id" validate:"required"`
+	ConversationID string `json:"conversation_id" validate:"required"`
+	Content        string `json:"content" validate:"required"`
+}
+
+type EscalationReplyResp struct {
+	MessageID string `json:"message_id"`
+	// Delivered 是否已推送到用户所在渠道, 网页等无法推送的渠道用户刷新会话后可见
+	Delivered bool `json:"delivered"`
+}
+
+type ResolveEscalationReq struct {
+	KbId           string `json:"kb_id" validate:"required"`
+	ConversationID string `json:"conversation_id" validate:"required"`
+}
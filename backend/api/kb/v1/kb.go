@@ -9,16 +9,19 @@ type KBUserListReq struct {
 }
 
 type KBUserListItemResp struct {
-	ID      string                  `json:"id"`
-	Account string                  `json:"account"`
-	Role    consts.UserRole         `json:"role"`
-	Perm    consts.UserKBPermission `json:"perms"`
+	ID       string                  `json:"id"`
+	Account  string                  `json:"account"`
+	Role     consts.UserRole         `json:"role"`
+	Perm     consts.UserKBPermission `json:"perms"`
+	RoleID   string                  `json:"role_id"`
+	RoleName string                  `json:"role_name"`
 }
 
 type KBUserInviteReq struct {
 	KBId   string                  `json:"kb_id" validate:"required"`
 	UserId string                  `json:"user_id" validate:"required"`
-	Perm   consts.UserKBPermission `json:"perm" validate:"required,oneof=full_control doc_manage data_operate"`
+	Perm   consts.UserKBPermission `json:"perm" validate:"required_without=RoleID,omitempty,oneof=full_control doc_manage data_operate"`
+	RoleID string                  `json:"role_id"` // 自定义角色，设置后忽略 perm
 }
 
 type KBUserInviteResp struct {
@@ -27,7 +30,8 @@ type KBUserInviteResp struct {
 type KBUserUpdateReq struct {
 	KBId   string                  `json:"kb_id" validate:"required"`
 	UserId string                  `json:"user_id" validate:"required"`
-	Perm   consts.UserKBPermission `json:"perm" validate:"required,oneof=full_control doc_manage data_operate"`
+	Perm   consts.UserKBPermission `json:"perm" validate:"required_without=RoleID,omitempty,oneof=full_control doc_manage data_operate"`
+	RoleID string                  `json:"role_id"` // 自定义角色，设置后忽略 perm
 }
 
 type KBUserUpdateResp struct {
@@ -0,0 +1,28 @@
+package v1
+
+import (
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+type KBRoleListResp struct {
+	Roles        []domain.KBRole           `json:"roles"`
+	Capabilities []domain.KBCapabilityInfo `json:"capabilities"` // 可选的能力及内置权限包含的能力
+}
+
+type KBRoleCreateReq struct {
+	Name         string                `json:"name" validate:"required,max=64"`
+	Description  string                `json:"description" validate:"max=255"`
+	Capabilities []consts.KBCapability `json:"capabilities" validate:"required,min=1,dive,oneof=node_edit node_publish node_review node_permission app_config stat_view conversation_view conversation_ip conversation_reply comment_manage user_manage"`
+}
+
+type KBRoleUpdateReq struct {
+	ID           string                `json:"id" validate:"required"`
+	Name         string                `json:"name" validate:"required,max=64"`
+	Description  string                `json:"description" validate:"max=255"`
+	Capabilities []consts.KBCapability `json:"capabilities" validate:"required,min=1,dive,oneof=node_edit node_publish node_review node_permission app_config stat_view conversation_view conversation_ip conversation_reply comment_manage user_manage"`
+}
+
+type KBRoleDeleteReq struct {
+	ID string `json:"id" query:"id" validate:"required"`
+}
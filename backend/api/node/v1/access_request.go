@@ -0,0 +1,49 @@
+package v1
+
+import (
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+type AccessRequestListReq struct {
+	KbId   string                         `query:"kb_id" json:"kb_id" validate:"required"`
+	Status consts.NodeAccessRequestStatus `query:"status" json:"status" validate:"omitempty,oneof=pending approved rejected"`
+	domain.Pager
+}
+
+type AccessRequestItem struct {
+	domain.NodeAccessRequest
+	NodeName      string `json:"node_name"`
+	AuthName      string `json:"auth_name"`
+	AuthAvatarUrl string `json:"auth_avatar_url"`
+	AuthGroupName string `json:"auth_group_name"`
+}
+
+type AccessRequestListResp = domain.PaginatedResult[[]AccessRequestItem]
+
+type AccessRequestDecideReq struct {
+	KbId   string                         `json:"kb_id" validate:"required"`
+	ID     string                         `json:"id" validate:"required"`
+	Action consts.NodeAccessRequestAction `json:"action" validate:"required,oneof=approve reject"`
+	// GrantType 批准时必填，可问答权限只能通过用户组授予
+	GrantType   consts.NodeAccessGrantType `json:"grant_type" validate:"required_if=Action approve,omitempty,oneof=auth_group node"`
+	AuthGroupID uint                       `json:"auth_group_id" validate:"required_if=GrantType auth_group"`
+	Comment     string                     `json:"comment" validate:"max=500"`
+}
+
+type AccessGrantListReq struct {
+	KbId   string `query:"kb_id" json:"kb_id" validate:"required"`
+	NodeID string `query:"node_id" json:"node_id"`
+}
+
+type AccessGrantItem struct {
+	domain.NodeAuthGrant
+	NodeName      string `json:"node_name"`
+	AuthName      string `json:"auth_name"`
+	AuthAvatarUrl string `json:"auth_avatar_url"`
+}
+
+type AccessGrantDeleteReq struct {
+	KbId string `query:"kb_id" json:"kb_id" validate:"required"`
+	ID   uint   `query:"id" json:"id" validate:"required"`
+}
@@ -0,0 +1,49 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+type BrokenLinkListReq struct {
+	KbId string              `query:"kb_id" json:"kb_id" validate:"required"`
+	Type consts.NodeLinkType `query:"type" json:"type" validate:"omitempty,oneof=internal external"`
+	domain.Pager
+}
+
+type BrokenLinkItem struct {
+	SourceNodeID string                      `json:"source_node_id"`
+	SourceName   string                      `json:"source_name"`
+	URL          string                      `json:"url"`
+	Type         consts.NodeLinkType         `json:"type"`
+	TargetNodeID string                      `json:"target_node_id"`
+	Reason       consts.NodeLinkBrokenReason `json:"reason"`
+	StatusCode   int                         `json:"status_code"`
+	Error        string                      `json:"error"`
+	CheckedAt    *time.Time                  `json:"checked_at"`
+}
+
+type BrokenLinkListResp = domain.PaginatedResult[[]BrokenLinkItem]
+
+type NodeBacklinksReq struct {
+	KbId string `query:"kb_id" json:"kb_id" validate:"required"`
+	ID   string `query:"id" json:"id" validate:"required"`
+}
+
+type NodeBacklinkItem struct {
+	NodeID string            `json:"node_id"`
+	Name   string            `json:"name"`
+	Emoji  string            `json:"emoji"`
+	Status domain.NodeStatus `json:"status"`
+	URL    string            `json:"url"`
+}
+
+type NodeBacklinksResp struct {
+	Backlinks []NodeBacklinkItem `json:"backlinks"`
+}
+
+type CheckLinksReq struct {
+	KbId string `json:"kb_id" validate:"required"`
+}
@@ -0,0 +1,15 @@
+package v1
+
+import "github.com/chaitin/panda-wiki/domain"
+
+type NodeMetaSchemaReq struct {
+	KbId string `query:"kb_id" json:"kb_id" validate:"required"`
+}
+
+type NodeMetaSchemaResp = domain.NodeMetaSchema
+
+type NodeMetaSchemaUpdateReq struct {
+	KbId   string                 `json:"kb_id" validate:"required"`
+	Tags   []string               `json:"tags" validate:"dive,required"`
+	Fields []domain.NodeMetaField `json:"fields" validate:"dive"`
+}
@@ -24,6 +24,10 @@ type NodeDetailResp struct {
 	CreatedAt   time.Time              `json:"created_at"`
 	UpdatedAt   time.Time              `json:"updated_at"`
 	Permissions domain.NodePermissions `json:"permissions"`
+
+	OwnerID        string     `json:"owner_id"`
+	ReviewInterval int        `json:"review_interval"`
+	ReviewedAt     *time.Time `json:"reviewed_at"`
 }
 
 type NodePermissionReq struct {
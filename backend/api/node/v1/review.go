@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+type NodeReviewPolicyReq struct {
+	KbId string `query:"kb_id" json:"kb_id" validate:"required"`
+}
+
+type NodeReviewPolicyResp struct {
+	Enabled     bool     `json:"enabled"`
+	ReviewerIDs []string `json:"reviewer_ids"`
+}
+
+type NodeReviewPolicyUpdateReq struct {
+	KbId        string   `json:"kb_id" validate:"required"`
+	Enabled     bool     `json:"enabled"`
+	ReviewerIDs []string `json:"reviewer_ids"`
+}
+
+type NodeReviewSubmitReq struct {
+	KbId    string   `json:"kb_id" validate:"required"`
+	IDs     []string `json:"ids" validate:"required,min=1"`
+	Comment string   `json:"comment"`
+}
+
+type NodeReviewDecideReq struct {
+	KbId    string                  `json:"kb_id" validate:"required"`
+	ID      string                  `json:"id" validate:"required"`
+	Action  consts.NodeReviewAction `json:"action" validate:"required,oneof=approve request_changes"`
+	Comment string                  `json:"comment"`
+}
+
+type NodeReviewQueueReq struct {
+	KbId   string                  `query:"kb_id" json:"kb_id" validate:"required"`
+	Status consts.NodeReviewStatus `query:"status" json:"status" validate:"omitempty,oneof=pending approved changes_requested"`
+	domain.Pager
+}
+
+type NodeReviewQueueItem struct {
+	NodeID      string                  `json:"node_id"`
+	Name        string                  `json:"name"`
+	Type        domain.NodeType         `json:"type"`
+	Emoji       string                  `json:"emoji"`
+	Status      consts.NodeReviewStatus `json:"status"`
+	Comment     string                  `json:"comment"`
+	SubmitterID string                  `json:"submitter_id"`
+	Submitter   string                  `json:"submitter"`
+	SubmittedAt time.Time               `json:"submitted_at"`
+	EditTime    time.Time               `json:"edit_time"`
+}
+
+type NodeReviewQueueResp = domain.PaginatedResult[[]NodeReviewQueueItem]
+
+type NodeReviewDetailReq struct {
+	KbId string `query:"kb_id" json:"kb_id" validate:"required"`
+	ID   string `query:"id" json:"id" validate:"required"`
+}
+
+type NodeReviewDetailResp struct {
+	NodeID      string                  `json:"node_id"`
+	Status      consts.NodeReviewStatus `json:"status"`
+	Outdated    bool                    `json:"outdated"` // 提交审核后文档又被编辑过, 需要重新提交
+	SubmitterID string                  `json:"submitter_id"`
+	ReviewerID  string                  `json:"reviewer_id"`
+	Comment     string                  `json:"comment"`
+	SubmittedAt *time.Time              `json:"submitted_at"`
+	ReviewedAt  *time.Time              `json:"reviewed_at"`
+	Logs        []NodeReviewLogItem     `json:"logs"`
+}
+
+type NodeReviewLogItem struct {
+	ID        uint                    `json:"id"`
+	UserID    string                  `json:"user_id"`
+	Account   string                  `json:"account"`
+	Action    consts.NodeReviewAction `json:"action"`
+	Comment   string                  `json:"comment"`
+	CreatedAt time.Time               `json:"created_at"`
+}
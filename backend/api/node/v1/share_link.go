@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+type ShareLinkListReq struct {
+	KbId   string `query:"kb_id" json:"kb_id" validate:"required"`
+	NodeID string `query:"node_id" json:"node_id"`
+}
+
+type ShareLinkItem struct {
+	domain.NodeShareLink
+	HasPassword bool   `json:"has_password"`
+	Token       string `json:"token"` // 拼接到分享页地址中，撤销或过期后失效
+	Active      bool   `json:"active"`
+}
+
+type ShareLinkCreateReq struct {
+	KbId     string `json:"kb_id" validate:"required"`
+	NodeID   string `json:"node_id" validate:"required"`
+	Draft    bool   `json:"draft"`                                      // 预览未发布的内容
+	Password string `json:"password" validate:"omitempty,min=4,max=64"` // 为空时不需要密码
+	// ExpiresIn 有效时长，单位小时，最长一年
+	ExpiresIn int `json:"expires_in" validate:"required,min=1,max=8760"`
+}
+
+type ShareLinkRevokeReq struct {
+	KbId string `query:"kb_id" json:"kb_id" validate:"required"`
+	ID   string `query:"id" json:"id" validate:"required"`
+}
@@ -0,0 +1,36 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+type StaleNodeListReq struct {
+	KbId    string `query:"kb_id" json:"kb_id" validate:"required"`
+	OwnerID string `query:"owner_id" json:"owner_id"`
+	domain.Pager
+}
+
+type StaleNodeItem struct {
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	Emoji          string     `json:"emoji"`
+	OwnerID        string     `json:"owner_id"`
+	Owner          string     `json:"owner"`
+	ReviewInterval int        `json:"review_interval"`
+	EditTime       time.Time  `json:"edit_time"`
+	ReviewedAt     *time.Time `json:"reviewed_at"`
+	DueAt          *time.Time `json:"due_at" gorm:"-"`
+	// Expired 超过复查周期未编辑或复查
+	Expired bool `json:"expired"`
+	// DislikeCount 最近引用该文档的回答收到的点踩数
+	DislikeCount int `json:"dislike_count"`
+}
+
+type StaleNodeListResp = domain.PaginatedResult[[]StaleNodeItem]
+
+type MarkNodeReviewedReq struct {
+	KbId string   `json:"kb_id" validate:"required"`
+	IDs  []string `json:"ids" validate:"required,min=1"`
+}
@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+type NodeTemplateListReq struct {
+	KbId string `query:"kb_id" json:"kb_id" validate:"required"`
+}
+
+type NodeTemplateListItem struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Emoji       string            `json:"emoji"`
+	Tags        domain.StringList `json:"tags" gorm:"type:jsonb"`
+	CreatorID   string            `json:"creator_id"`
+	Creator     string            `json:"creator"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+type NodeTemplateDetailReq struct {
+	KbId string `query:"kb_id" json:"kb_id" validate:"required"`
+	ID   string `query:"id" json:"id" validate:"required"`
+}
+
+type NodeTemplateDetailResp = domain.NodeTemplate
+
+type NodeTemplateCreateReq struct {
+	KbId        string                  `json:"kb_id" validate:"required"`
+	Name        string                  `json:"name" validate:"required"`
+	Description string                  `json:"description"`
+	Title       string                  `json:"title"`
+	Content     string                  `json:"content"`
+	ContentType string                  `json:"content_type"`
+	Emoji       string                  `json:"emoji"`
+	Tags        []string                `json:"tags"`
+	Fields      map[string]string       `json:"fields"`
+	Permissions *domain.NodePermissions `json:"permissions"`
+}
+
+type NodeTemplateUpdateReq struct {
+	KbId        string                  `json:"kb_id" validate:"required"`
+	ID          string                  `json:"id" validate:"required"`
+	Name        *string                 `json:"name" validate:"omitempty,min=1"`
+	Description *string                 `json:"description"`
+	Title       *string                 `json:"title"`
+	Content     *string                 `json:"content"`
+	Emoji       *string                 `json:"emoji"`
+	Tags        *[]string               `json:"tags"`
+	Fields      *map[string]string      `json:"fields"`
+	Permissions *domain.NodePermissions `json:"permissions"`
+}
+
+type NodeTemplateDeleteReq struct {
+	KbId string `query:"kb_id" json:"kb_id" validate:"required"`
+	ID   string `query:"id" json:"id" validate:"required"`
+}
+
+// NodeTemplateFromNodeReq 将已有文档保存为模板
+type NodeTemplateFromNodeReq struct {
+	KbId        string `json:"kb_id" validate:"required"`
+	NodeID      string `json:"node_id" validate:"required"`
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+}
+
+type NodeTemplateCreateResp struct {
+	ID string `json:"id"`
+}
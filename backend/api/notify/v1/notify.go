@@ -0,0 +1,21 @@
+package v1
+
+import "github.com/chaitin/panda-wiki/domain"
+
+type GetNotifySettingsReq struct {
+	KbId string `query:"kb_id" json:"kb_id" validate:"required"`
+}
+
+type GetNotifySettingsResp = domain.NotifySettings
+
+type UpdateNotifySettingsReq struct {
+	KbId        string                    `json:"kb_id" validate:"required"`
+	SMTP        domain.SMTPSettings       `json:"smtp"`
+	Webhooks    []domain.NotifyWebhook    `json:"webhooks" validate:"dive"`
+	FeishuBotDM bool                      `json:"feishu_bot_dm"`
+	Broadcasts  []domain.ReleaseBroadcast `json:"broadcasts" validate:"dive"`
+}
+
+type TestNotifyReq struct {
+	KbId string `json:"kb_id" validate:"required"`
+}
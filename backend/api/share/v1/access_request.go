@@ -0,0 +1,25 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/chaitin/panda-wiki/consts"
+)
+
+type AccessRequestCreateReq struct {
+	NodeID string              `json:"node_id" validate:"required"`
+	Perm   consts.NodePermName `json:"perm" validate:"required,oneof=visitable answerable"`
+	Reason string              `json:"reason" validate:"max=500"`
+}
+
+type AccessRequestItem struct {
+	ID            string                         `json:"id"`
+	NodeID        string                         `json:"node_id"`
+	NodeName      string                         `json:"node_name"`
+	Perm          consts.NodePermName            `json:"perm"`
+	Reason        string                         `json:"reason"`
+	Status        consts.NodeAccessRequestStatus `json:"status"`
+	ReviewComment string                         `json:"review_comment"`
+	CreatedAt     time.Time                      `json:"created_at"`
+	ReviewedAt    *time.Time                     `json:"reviewed_at"`
+}
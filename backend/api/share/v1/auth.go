@@ -33,3 +33,50 @@ type GitHubCallbackReq struct {
 
 type GitHubCallbackResp struct {
 }
+
+type AuthLDAPReq struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type AuthCASReq struct {
+	KbID        string `json:"kb_id"`
+	RedirectUrl string `json:"redirect_url"`
+}
+
+type AuthCASResp struct {
+	Url string `json:"url"`
+}
+
+type CASCallbackReq struct {
+	Ticket string `json:"ticket" query:"ticket"`
+	State  string `json:"state" query:"state"`
+}
+
+type AuthOAuthReq struct {
+	KbID        string `json:"kb_id"`
+	RedirectUrl string `json:"redirect_url"`
+}
+
+type AuthOAuthResp struct {
+	Url string `json:"url"`
+}
+
+type OAuthCallbackReq struct {
+	Code  string `json:"code" query:"code"`
+	State string `json:"state" query:"state"`
+}
+
+type AuthSAMLReq struct {
+	KbID        string `json:"kb_id"`
+	RedirectUrl string `json:"redirect_url"`
+}
+
+type AuthSAMLResp struct {
+	Url string `json:"url"`
+}
+
+type SAMLACSReq struct {
+	SAMLResponse string `form:"SAMLResponse"`
+	RelayState   string `form:"RelayState"`
+}
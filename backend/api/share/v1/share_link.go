@@ -0,0 +1,38 @@
+package v1
+
+import (
+	"time"
+)
+
+type ShareLinkInfoReq struct {
+	Token string `query:"token" json:"token" validate:"required"`
+}
+
+type ShareLinkInfoResp struct {
+	KBID         string    `json:"kb_id"`
+	NodeID       string    `json:"node_id"`
+	Name         string    `json:"name"`
+	Draft        bool      `json:"draft"`
+	NeedPassword bool      `json:"need_password"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type ShareLinkAuthReq struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type ShareLinkAuthResp struct {
+	// Access 后续请求通过 X-Share-Access 请求头携带
+	Access string `json:"access"`
+}
+
+type ShareLinkNodeListReq struct {
+	Token string `query:"token" json:"token" validate:"required"`
+}
+
+type ShareLinkNodeDetailReq struct {
+	Token  string `query:"token" json:"token" validate:"required"`
+	ID     string `query:"id" json:"id" validate:"required"`
+	Format string `query:"format" json:"format"`
+}
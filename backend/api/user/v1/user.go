@@ -4,12 +4,14 @@ import (
 	"time"
 
 	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
 )
 
 type CreateUserReq struct {
 	Account  string          `json:"account" validate:"required"`
 	Password string          `json:"password" validate:"required,min=8"`
 	Role     consts.UserRole `json:"role" validate:"required,oneof=admin user"`
+	Email    string          `json:"email" validate:"omitempty,email"`
 }
 
 type CreateUserResp struct {
@@ -17,23 +19,27 @@ type CreateUserResp struct {
 }
 
 type UserInfoResp struct {
-	ID         string          `json:"id"`
-	Account    string          `json:"account"`
-	Role       consts.UserRole `json:"role"`
-	IsToken    bool            `json:"is_token"`
-	LastAccess *time.Time      `json:"last_access,omitempty"`
-	CreatedAt  time.Time       `json:"created_at"`
+	ID          string          `json:"id"`
+	Account     string          `json:"account"`
+	Role        consts.UserRole `json:"role"`
+	Email       string          `json:"email"`
+	IsToken     bool            `json:"is_token"`
+	TOTPEnabled bool            `json:"totp_enabled"`
+	LastAccess  *time.Time      `json:"last_access,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
 }
 
 type UserListReq struct {
 }
 
 type UserListItemResp struct {
-	ID         string          `json:"id"`
-	Account    string          `json:"account"`
-	Role       consts.UserRole `json:"role"`
-	LastAccess *time.Time      `json:"last_access"`
-	CreatedAt  *time.Time      `json:"created_at"`
+	ID          string          `json:"id"`
+	Account     string          `json:"account"`
+	Role        consts.UserRole `json:"role"`
+	Email       string          `json:"email"`
+	LastAccess  *time.Time      `json:"last_access"`
+	CreatedAt   *time.Time      `json:"created_at"`
+	TOTPEnabled bool            `json:"totp_enabled" gorm:"column:totp_enabled"`
 }
 
 type LoginReq struct {
@@ -41,8 +47,72 @@ type LoginReq struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// LoginResp 需要两步验证时不返回 token，凭 two_factor_token 继续验证或绑定
 type LoginResp struct {
-	Token string `json:"token"`
+	Token                  string `json:"token,omitempty"`
+	TwoFactorRequired      bool   `json:"two_factor_required,omitempty"`
+	TwoFactorSetupRequired bool   `json:"two_factor_setup_required,omitempty"`
+	TwoFactorToken         string `json:"two_factor_token,omitempty"`
+}
+
+type LoginTwoFactorSetupReq struct {
+	TwoFactorToken string `json:"two_factor_token" validate:"required"`
+}
+
+type LoginTwoFactorReq struct {
+	TwoFactorToken string `json:"two_factor_token" validate:"required"`
+	Code           string `json:"code" validate:"required"` // 验证码或恢复码
+}
+
+type LoginTwoFactorResp struct {
+	Token         string   `json:"token"`
+	RecoveryCodes []string `json:"recovery_codes,omitempty"` // 登录时完成绑定才返回
+}
+
+type TwoFactorSetupResp struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+}
+
+type TwoFactorCodeReq struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type TwoFactorRecoveryCodesResp struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TwoFactorResetReq struct {
+	UserID string `json:"user_id" validate:"required"`
+}
+
+type SetLoginSecurityPolicyReq struct {
+	RequireAdmin2FA bool `json:"require_admin_2fa"`
+}
+
+type UserSessionListReq struct {
+	UserID string `query:"user_id"` // 为空时查询当前用户，查询其他用户需要管理员
+}
+
+type UserSessionItem struct {
+	*domain.UserSession
+	Current bool `json:"current"`
+}
+
+type UserSessionListResp struct {
+	Sessions []UserSessionItem `json:"sessions"`
+}
+
+type RevokeUserSessionReq struct {
+	ID string `json:"id" query:"id" validate:"required"`
+}
+
+type RevokeUserSessionsReq struct {
+	UserID string `json:"user_id" query:"user_id" validate:"required"`
+}
+
+type RevokeUserSessionsResp struct {
+	Count int `json:"count"`
 }
 
 type UserListResp struct {
@@ -57,3 +127,8 @@ type ResetPasswordReq struct {
 type DeleteUserReq struct {
 	UserID string `json:"user_id" query:"user_id" validate:"required"`
 }
+
+type UpdateUserEmailReq struct {
+	ID    string `json:"id" validate:"required"`
+	Email string `json:"email" validate:"omitempty,email"`
+}
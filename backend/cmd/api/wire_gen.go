@@ -46,17 +46,22 @@ func createApp() (*App, error) {
 	if err != nil {
 		return nil, err
 	}
-	echo := http.NewEcho(logger, configConfig, readOnlyMiddleware, sessionMiddleware)
-	httpServer := &http.HTTPServer{
-		Echo: echo,
-	}
 	db, err := pg.NewDB(configConfig)
 	if err != nil {
 		return nil, err
 	}
+	auditLogRepository := pg2.NewAuditLogRepository(db, logger)
+	userRepository := pg2.NewUserRepository(db, logger)
+	auditUsecase := usecase.NewAuditUsecase(auditLogRepository, userRepository, logger)
+	auditMiddleware := middleware.NewAuditMiddleware(logger, auditUsecase)
+	echo := http.NewEcho(logger, configConfig, readOnlyMiddleware, sessionMiddleware, auditMiddleware)
+	httpServer := &http.HTTPServer{
+		Echo: echo,
+	}
 	userAccessRepository := pg2.NewUserAccessRepository(db, logger)
 	apiTokenRepo := pg2.NewAPITokenRepo(db, logger, cacheCache)
-	authMiddleware, err := middleware.NewAuthMiddleware(configConfig, logger, userAccessRepository, apiTokenRepo)
+	userSessionRepo := cache2.NewUserSessionRepo(cacheCache)
+	authMiddleware, err := middleware.NewAuthMiddleware(configConfig, logger, userAccessRepository, apiTokenRepo, userSessionRepo)
 	if err != nil {
 		return nil, err
 	}
@@ -66,57 +71,69 @@ func createApp() (*App, error) {
 	}
 	knowledgeBaseRepository := pg2.NewKnowledgeBaseRepository(db, configConfig, logger, ragService)
 	nodeRepository := pg2.NewNodeRepository(db, logger)
+	nodeReviewRepository := pg2.NewNodeReviewRepository(db, logger)
 	mqProducer, err := mq.NewMQProducer(configConfig, logger)
 	if err != nil {
 		return nil, err
 	}
 	ragRepository := mq2.NewRAGRepository(mqProducer)
-	userRepository := pg2.NewUserRepository(db, logger)
+	nodeLinkRepository := pg2.NewNodeLinkRepository(db, logger)
+	nodeLinkUsecase := usecase.NewNodeLinkUsecase(nodeLinkRepository, knowledgeBaseRepository, logger)
+	notifyRepository := pg2.NewNotifyRepository(db, logger)
+	appRepository := pg2.NewAppRepository(db, logger)
+	conversationRepository := pg2.NewConversationRepository(db, logger)
+	modelRepository := pg2.NewModelRepository(db, logger)
+	promptRepo := pg2.NewPromptRepo(db, logger)
+	llmUsecase := usecase.NewLLMUsecase(configConfig, ragService, conversationRepository, knowledgeBaseRepository, nodeRepository, modelRepository, promptRepo, logger)
+	minioClient, err := s3.NewMinioClient(configConfig)
+	if err != nil {
+		return nil, err
+	}
+	authRepo := pg2.NewAuthRepo(db, logger, cacheCache)
+	nodeTemplateRepository := pg2.NewNodeTemplateRepository(db, logger)
+	nodeTemplateUsecase := usecase.NewNodeTemplateUsecase(nodeTemplateRepository, nodeRepository, knowledgeBaseRepository, userRepository, logger)
+	nodeUsecase := usecase.NewNodeUsecase(nodeRepository, appRepository, ragRepository, knowledgeBaseRepository, llmUsecase, ragService, logger, minioClient, modelRepository, authRepo, nodeLinkUsecase, nodeTemplateUsecase)
+	releaseBroadcastUsecase := usecase.NewReleaseBroadcastUsecase(notifyRepository, appRepository, knowledgeBaseRepository, nodeRepository, nodeUsecase, logger)
+	kbRoleRepository := pg2.NewKBRoleRepository(db, logger)
 	kbRepo := cache2.NewKBRepo(cacheCache)
-	knowledgeBaseUsecase, err := usecase.NewKnowledgeBaseUsecase(knowledgeBaseRepository, nodeRepository, ragRepository, userRepository, ragService, kbRepo, logger, configConfig)
+	knowledgeBaseUsecase, err := usecase.NewKnowledgeBaseUsecase(knowledgeBaseRepository, nodeRepository, nodeReviewRepository, ragRepository, nodeLinkUsecase, releaseBroadcastUsecase, userRepository, kbRoleRepository, ragService, kbRepo, logger, configConfig)
+	if err != nil {
+		return nil, err
+	}
+	authUsecase, err := usecase.NewAuthUsecase(authRepo, logger, knowledgeBaseRepository, cacheCache)
 	if err != nil {
 		return nil, err
 	}
-	shareAuthMiddleware := middleware.NewShareAuthMiddleware(logger, knowledgeBaseUsecase)
+	shareAuthMiddleware := middleware.NewShareAuthMiddleware(logger, knowledgeBaseUsecase, authUsecase)
 	captchaCaptcha := captcha.NewCaptcha()
 	baseHandler := handler.NewBaseHandler(echo, logger, configConfig, authMiddleware, shareAuthMiddleware, captchaCaptcha)
-	userUsecase, err := usecase.NewUserUsecase(userRepository, logger, configConfig)
+	loginLimiter := usecase.NewLoginLimiter(cacheCache, logger)
+	userUsecase, err := usecase.NewUserUsecase(userRepository, userSessionRepo, logger, configConfig, cacheCache, loginLimiter)
 	if err != nil {
 		return nil, err
 	}
 	userHandler := v1.NewUserHandler(echo, baseHandler, logger, userUsecase, authMiddleware, configConfig)
-	conversationRepository := pg2.NewConversationRepository(db, logger)
-	modelRepository := pg2.NewModelRepository(db, logger)
-	promptRepo := pg2.NewPromptRepo(db, logger)
-	llmUsecase := usecase.NewLLMUsecase(configConfig, ragService, conversationRepository, knowledgeBaseRepository, nodeRepository, modelRepository, promptRepo, logger)
 	knowledgeBaseHandler := v1.NewKnowledgeBaseHandler(baseHandler, echo, knowledgeBaseUsecase, llmUsecase, authMiddleware, logger)
-	appRepository := pg2.NewAppRepository(db, logger)
-	minioClient, err := s3.NewMinioClient(configConfig)
-	if err != nil {
-		return nil, err
-	}
-	authRepo := pg2.NewAuthRepo(db, logger, cacheCache)
-	nodeUsecase := usecase.NewNodeUsecase(nodeRepository, appRepository, ragRepository, knowledgeBaseRepository, llmUsecase, ragService, logger, minioClient, modelRepository, authRepo)
 	nodeHandler := v1.NewNodeHandler(baseHandler, echo, nodeUsecase, authMiddleware, logger)
+	nodeReviewUsecase := usecase.NewNodeReviewUsecase(nodeReviewRepository, nodeRepository, userAccessRepository, logger)
+	nodeReviewHandler := v1.NewNodeReviewHandler(baseHandler, echo, nodeReviewUsecase, authMiddleware, logger)
+	notifyUsecase := usecase.NewNotifyUsecase(notifyRepository, appRepository, userRepository, logger)
+	nodeStaleUsecase := usecase.NewNodeStaleUsecase(nodeRepository, knowledgeBaseRepository, notifyUsecase, logger)
+	nodeStaleHandler := v1.NewNodeStaleHandler(baseHandler, echo, nodeStaleUsecase, authMiddleware, logger)
+	nodeLinkHandler := v1.NewNodeLinkHandler(baseHandler, echo, nodeLinkUsecase, authMiddleware, logger)
+	nodeTemplateHandler := v1.NewNodeTemplateHandler(baseHandler, echo, nodeTemplateUsecase, authMiddleware, logger)
+	notifyHandler := v1.NewNotifyHandler(baseHandler, echo, notifyUsecase, authMiddleware, logger)
 	geoRepo := cache2.NewGeoCache(cacheCache, db, logger)
 	ipdbIPDB, err := ipdb.NewIPDB(configConfig, logger)
 	if err != nil {
 		return nil, err
 	}
 	ipAddressRepo := ipdb2.NewIPAddressRepo(ipdbIPDB, logger)
-	conversationUsecase := usecase.NewConversationUsecase(conversationRepository, nodeRepository, geoRepo, logger, ipAddressRepo, authRepo)
+	escalationUsecase := usecase.NewEscalationUsecase(conversationRepository, notifyRepository, appRepository, logger)
+	conversationUsecase := usecase.NewConversationUsecase(conversationRepository, nodeRepository, geoRepo, logger, ipAddressRepo, authRepo, escalationUsecase)
 	modelUsecase := usecase.NewModelUsecase(modelRepository, nodeRepository, ragRepository, ragService, logger, configConfig, knowledgeBaseRepository)
 	blockWordRepo := pg2.NewBlockWordRepo(db, logger)
-	chatUsecase, err := usecase.NewChatUsecase(llmUsecase, knowledgeBaseRepository, conversationUsecase, modelUsecase, appRepository, blockWordRepo, authRepo, logger)
-	if err != nil {
-		return nil, err
-	}
-	appUsecase := usecase.NewAppUsecase(appRepository, authRepo, nodeRepository, nodeUsecase, logger, configConfig, chatUsecase, cacheCache)
-	appHandler := v1.NewAppHandler(echo, baseHandler, logger, authMiddleware, appUsecase, modelUsecase, conversationUsecase, configConfig)
 	fileUsecase := usecase.NewFileUsecase(logger, minioClient, configConfig)
-	fileHandler := v1.NewFileHandler(echo, baseHandler, logger, authMiddleware, minioClient, configConfig, fileUsecase)
-	modelHandler := v1.NewModelHandler(echo, baseHandler, logger, authMiddleware, modelUsecase, llmUsecase)
-	conversationHandler := v1.NewConversationHandler(echo, baseHandler, logger, authMiddleware, conversationUsecase)
 	mqConsumer, err := mq.NewMQConsumer(configConfig, logger)
 	if err != nil {
 		return nil, err
@@ -125,6 +142,20 @@ func createApp() (*App, error) {
 	if err != nil {
 		return nil, err
 	}
+	chatAttachmentUsecase := usecase.NewChatAttachmentUsecase(conversationRepository, modelRepository, fileUsecase, crawlerUsecase, logger)
+	chatUsecase, err := usecase.NewChatUsecase(llmUsecase, knowledgeBaseRepository, conversationUsecase, modelUsecase, appRepository, blockWordRepo, authRepo, escalationUsecase, chatAttachmentUsecase, logger)
+	if err != nil {
+		return nil, err
+	}
+	mailReplyRepository := pg2.NewMailReplyRepository(db, logger)
+	appUsecase := usecase.NewAppUsecase(appRepository, authRepo, nodeRepository, nodeUsecase, logger, configConfig, chatUsecase, cacheCache, mailReplyRepository)
+	appHandler := v1.NewAppHandler(echo, baseHandler, logger, authMiddleware, appUsecase, modelUsecase, conversationUsecase, configConfig)
+	mailReplyUsecase := usecase.NewMailReplyUsecase(mailReplyRepository, appUsecase, logger)
+	mailReplyHandler := v1.NewMailReplyHandler(baseHandler, echo, mailReplyUsecase, authMiddleware, logger)
+	fileHandler := v1.NewFileHandler(echo, baseHandler, logger, authMiddleware, minioClient, configConfig, fileUsecase)
+	modelHandler := v1.NewModelHandler(echo, baseHandler, logger, authMiddleware, modelUsecase, llmUsecase)
+	conversationHandler := v1.NewConversationHandler(echo, baseHandler, logger, authMiddleware, conversationUsecase)
+	conversationEscalationHandler := v1.NewConversationEscalationHandler(baseHandler, echo, escalationUsecase, appUsecase, authMiddleware, logger)
 	crawlerHandler := v1.NewCrawlerHandler(echo, baseHandler, authMiddleware, logger, configConfig, crawlerUsecase, fileUsecase)
 	creationUsecase := usecase.NewCreationUsecase(logger, llmUsecase, modelUsecase)
 	creationHandler := v1.NewCreationHandler(echo, baseHandler, logger, creationUsecase)
@@ -134,33 +165,53 @@ func createApp() (*App, error) {
 	commentRepository := pg2.NewCommentRepository(db, logger)
 	commentUsecase := usecase.NewCommentUsecase(commentRepository, logger, nodeRepository, ipAddressRepo, authRepo)
 	commentHandler := v1.NewCommentHandler(echo, baseHandler, logger, authMiddleware, commentUsecase)
-	authUsecase, err := usecase.NewAuthUsecase(authRepo, logger, knowledgeBaseRepository, cacheCache)
-	if err != nil {
-		return nil, err
-	}
-	authV1Handler := v1.NewAuthV1Handler(echo, baseHandler, logger, authUsecase)
+	authGroupSyncUsecase := usecase.NewAuthGroupSyncUsecase(authUsecase, authRepo, nodeRepository, ragRepository, cacheCache, logger)
+	scimUsecase := usecase.NewSCIMUsecase(authUsecase, authRepo, authGroupSyncUsecase, logger)
+	authV1Handler := v1.NewAuthV1Handler(echo, baseHandler, logger, authUsecase, authGroupSyncUsecase, scimUsecase)
+	auditLogHandler := v1.NewAuditLogHandler(baseHandler, echo, auditUsecase, authMiddleware, logger)
+	kbRoleUsecase := usecase.NewKBRoleUsecase(kbRoleRepository, logger)
+	kbRoleHandler := v1.NewKBRoleHandler(baseHandler, echo, kbRoleUsecase, authMiddleware, logger)
+	nodeShareLinkRepository := pg2.NewNodeShareLinkRepository(db, logger)
+	nodeShareLinkUsecase := usecase.NewNodeShareLinkUsecase(nodeShareLinkRepository, nodeRepository, nodeUsecase, loginLimiter, configConfig, logger)
+	nodeShareLinkHandler := v1.NewNodeShareLinkHandler(baseHandler, echo, nodeShareLinkUsecase, authMiddleware, logger)
+	nodeAccessRequestRepository := pg2.NewNodeAccessRequestRepository(db, logger)
+	nodeAccessRequestUsecase := usecase.NewNodeAccessRequestUsecase(nodeAccessRequestRepository, nodeRepository, authRepo, knowledgeBaseRepository, nodeUsecase, notifyUsecase, logger)
+	nodeAccessRequestHandler := v1.NewNodeAccessRequestHandler(baseHandler, echo, nodeAccessRequestUsecase, authMiddleware, logger)
 	apiHandlers := &v1.APIHandlers{
-		UserHandler:          userHandler,
-		KnowledgeBaseHandler: knowledgeBaseHandler,
-		NodeHandler:          nodeHandler,
-		AppHandler:           appHandler,
-		FileHandler:          fileHandler,
-		ModelHandler:         modelHandler,
-		ConversationHandler:  conversationHandler,
-		CrawlerHandler:       crawlerHandler,
-		CreationHandler:      creationHandler,
-		StatHandler:          statHandler,
-		CommentHandler:       commentHandler,
-		AuthV1Handler:        authV1Handler,
+		UserHandler:              userHandler,
+		KnowledgeBaseHandler:     knowledgeBaseHandler,
+		NodeHandler:              nodeHandler,
+		NodeReviewHandler:        nodeReviewHandler,
+		NodeStaleHandler:         nodeStaleHandler,
+		NodeLinkHandler:          nodeLinkHandler,
+		NodeTemplateHandler:      nodeTemplateHandler,
+		NotifyHandler:            notifyHandler,
+		AppHandler:               appHandler,
+		MailReplyHandler:         mailReplyHandler,
+		FileHandler:              fileHandler,
+		ModelHandler:             modelHandler,
+		ConversationHandler:      conversationHandler,
+		EscalationHandler:        conversationEscalationHandler,
+		CrawlerHandler:           crawlerHandler,
+		CreationHandler:          creationHandler,
+		StatHandler:              statHandler,
+		CommentHandler:           commentHandler,
+		AuthV1Handler:            authV1Handler,
+		AuditLogHandler:          auditLogHandler,
+		KBRoleHandler:            kbRoleHandler,
+		NodeShareLinkHandler:     nodeShareLinkHandler,
+		NodeAccessRequestHandler: nodeAccessRequestHandler,
 	}
 	shareNodeHandler := share.NewShareNodeHandler(baseHandler, echo, nodeUsecase, logger)
+	shareLinkHandler := share.NewShareLinkHandler(baseHandler, echo, nodeShareLinkUsecase, logger)
+	shareAccessHandler := share.NewShareAccessHandler(baseHandler, echo, nodeAccessRequestUsecase, logger)
 	shareAppHandler := share.NewShareAppHandler(echo, baseHandler, logger, appUsecase)
 	shareChatHandler := share.NewShareChatHandler(echo, baseHandler, logger, appUsecase, chatUsecase, authUsecase, conversationUsecase, modelUsecase)
 	sitemapUsecase := usecase.NewSitemapUsecase(nodeRepository, knowledgeBaseRepository, logger)
 	shareSitemapHandler := share.NewShareSitemapHandler(echo, baseHandler, sitemapUsecase, appUsecase, logger)
 	shareStatHandler := share.NewShareStatHandler(baseHandler, echo, statUseCase, logger)
 	shareCommentHandler := share.NewShareCommentHandler(echo, baseHandler, logger, commentUsecase, appUsecase)
-	shareAuthHandler := share.NewShareAuthHandler(echo, baseHandler, logger, knowledgeBaseUsecase, authUsecase)
+	shareAuthHandler := share.NewShareAuthHandler(echo, baseHandler, logger, knowledgeBaseUsecase, authUsecase, loginLimiter)
 	shareConversationHandler := share.NewShareConversationHandler(baseHandler, echo, conversationUsecase, logger)
 	wechatRepository := pg2.NewWechatRepository(db, logger)
 	wechatUsecase := usecase.NewWechatUsecase(logger, appUsecase, chatUsecase, wechatRepository, authRepo)
@@ -169,9 +220,12 @@ func createApp() (*App, error) {
 	shareWechatHandler := share.NewShareWechatHandler(echo, baseHandler, logger, appUsecase, conversationUsecase, wechatUsecase, wecomUsecase, wechatAppUsecase)
 	shareCaptchaHandler := share.NewShareCaptchaHandler(baseHandler, echo, logger)
 	openapiV1Handler := share.NewOpenapiV1Handler(echo, baseHandler, logger, authUsecase, appUsecase)
+	scimv2Handler := share.NewSCIMV2Handler(echo, baseHandler, logger, scimUsecase)
 	shareCommonHandler := share.NewShareCommonHandler(echo, baseHandler, logger, fileUsecase)
 	shareHandler := &share.ShareHandler{
 		ShareNodeHandler:         shareNodeHandler,
+		ShareLinkHandler:         shareLinkHandler,
+		ShareAccessHandler:       shareAccessHandler,
 		ShareAppHandler:          shareAppHandler,
 		ShareChatHandler:         shareChatHandler,
 		ShareSitemapHandler:      shareSitemapHandler,
@@ -182,6 +236,7 @@ func createApp() (*App, error) {
 		ShareWechatHandler:       shareWechatHandler,
 		ShareCaptchaHandler:      shareCaptchaHandler,
 		OpenapiV1Handler:         openapiV1Handler,
+		SCIMV2Handler:            scimv2Handler,
 		ShareCommonHandler:       shareCommonHandler,
 	}
 	client, err := telemetry.NewClient(logger, knowledgeBaseRepository)
@@ -80,8 +80,23 @@ func createApp() (*App, error) {
 	if err != nil {
 		return nil, err
 	}
-	nodeUsecase := usecase.NewNodeUsecase(nodeRepository, appRepository, ragRepository, knowledgeBaseRepository, llmUsecase, ragService, logger, minioClient, modelRepository, authRepo)
-	cronHandler, err := mq2.NewStatCronHandler(logger, statRepository, statUseCase, nodeUsecase)
+	nodeLinkRepository := pg2.NewNodeLinkRepository(db, logger)
+	nodeLinkUsecase := usecase.NewNodeLinkUsecase(nodeLinkRepository, knowledgeBaseRepository, logger)
+	nodeTemplateRepository := pg2.NewNodeTemplateRepository(db, logger)
+	userRepository := pg2.NewUserRepository(db, logger)
+	nodeTemplateUsecase := usecase.NewNodeTemplateUsecase(nodeTemplateRepository, nodeRepository, knowledgeBaseRepository, userRepository, logger)
+	nodeUsecase := usecase.NewNodeUsecase(nodeRepository, appRepository, ragRepository, knowledgeBaseRepository, llmUsecase, ragService, logger, minioClient, modelRepository, authRepo, nodeLinkUsecase, nodeTemplateUsecase)
+	notifyRepository := pg2.NewNotifyRepository(db, logger)
+	notifyUsecase := usecase.NewNotifyUsecase(notifyRepository, appRepository, userRepository, logger)
+	nodeStaleUsecase := usecase.NewNodeStaleUsecase(nodeRepository, knowledgeBaseRepository, notifyUsecase, logger)
+	authUsecase, err := usecase.NewAuthUsecase(authRepo, logger, knowledgeBaseRepository, cacheCache)
+	if err != nil {
+		return nil, err
+	}
+	authGroupSyncUsecase := usecase.NewAuthGroupSyncUsecase(authUsecase, authRepo, nodeRepository, ragRepository, cacheCache, logger)
+	auditLogRepository := pg2.NewAuditLogRepository(db, logger)
+	auditUsecase := usecase.NewAuditUsecase(auditLogRepository, userRepository, logger)
+	cronHandler, err := mq2.NewStatCronHandler(logger, statRepository, statUseCase, nodeUsecase, nodeStaleUsecase, nodeLinkUsecase, authGroupSyncUsecase, auditUsecase, conversationRepository)
 	if err != nil {
 		return nil, err
 	}
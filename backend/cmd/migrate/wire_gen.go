@@ -59,10 +59,18 @@ func createApp() (*App, error) {
 		return nil, err
 	}
 	authRepo := pg2.NewAuthRepo(db, logger, cacheCache)
-	nodeUsecase := usecase.NewNodeUsecase(nodeRepository, appRepository, ragRepository, knowledgeBaseRepository, llmUsecase, ragService, logger, minioClient, modelRepository, authRepo)
+	nodeLinkRepository := pg2.NewNodeLinkRepository(db, logger)
+	nodeLinkUsecase := usecase.NewNodeLinkUsecase(nodeLinkRepository, knowledgeBaseRepository, logger)
+	nodeTemplateRepository := pg2.NewNodeTemplateRepository(db, logger)
 	userRepository := pg2.NewUserRepository(db, logger)
+	nodeTemplateUsecase := usecase.NewNodeTemplateUsecase(nodeTemplateRepository, nodeRepository, knowledgeBaseRepository, userRepository, logger)
+	nodeUsecase := usecase.NewNodeUsecase(nodeRepository, appRepository, ragRepository, knowledgeBaseRepository, llmUsecase, ragService, logger, minioClient, modelRepository, authRepo, nodeLinkUsecase, nodeTemplateUsecase)
+	nodeReviewRepository := pg2.NewNodeReviewRepository(db, logger)
+	notifyRepository := pg2.NewNotifyRepository(db, logger)
+	releaseBroadcastUsecase := usecase.NewReleaseBroadcastUsecase(notifyRepository, appRepository, knowledgeBaseRepository, nodeRepository, nodeUsecase, logger)
+	kbRoleRepository := pg2.NewKBRoleRepository(db, logger)
 	kbRepo := cache2.NewKBRepo(cacheCache)
-	knowledgeBaseUsecase, err := usecase.NewKnowledgeBaseUsecase(knowledgeBaseRepository, nodeRepository, ragRepository, userRepository, ragService, kbRepo, logger, configConfig)
+	knowledgeBaseUsecase, err := usecase.NewKnowledgeBaseUsecase(knowledgeBaseRepository, nodeRepository, nodeReviewRepository, ragRepository, nodeLinkUsecase, releaseBroadcastUsecase, userRepository, kbRoleRepository, ragService, kbRepo, logger, configConfig)
 	if err != nil {
 		return nil, err
 	}
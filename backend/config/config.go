@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -29,6 +30,9 @@ type LogConfig struct {
 
 type HTTPConfig struct {
 	Port int `mapstructure:"port"`
+	// TrustedProxies CIDRs of reverse proxies allowed to set X-Forwarded-For,
+	// loopback, link-local and private addresses are always trusted
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 type PGConfig struct {
@@ -181,6 +185,9 @@ func overrideWithEnv(c *Config) {
 	if env := os.Getenv("SUBNET_PREFIX"); env != "" {
 		c.SubnetPrefix = env
 	}
+	if env := os.Getenv("HTTP_TRUSTED_PROXIES"); env != "" {
+		c.HTTP.TrustedProxies = strings.Split(env, ",")
+	}
 	// pg
 	if env := os.Getenv("PG_DSN"); env != "" {
 		c.PG.DSN = env
@@ -9,9 +9,61 @@ const (
 	UserKBPermissionDataOperate UserKBPermission = "data_operate" // 数据运营
 )
 
+// UserKBPermissions 内置权限，不含无权限
+var UserKBPermissions = []UserKBPermission{
+	UserKBPermissionFullControl,
+	UserKBPermissionDocManage,
+	UserKBPermissionDataOperate,
+}
+
 type UserRole string
 
 const (
 	UserRoleAdmin UserRole = "admin" // 管理员
 	UserRoleUser  UserRole = "user"  // 普通用户
 )
+
+// KBCapability 知识库内的细粒度能力，内置权限和自定义角色都由一组能力组成
+type KBCapability string
+
+const (
+	KBCapabilityNodeEdit          KBCapability = "node_edit"          // 编辑文档
+	KBCapabilityNodePublish       KBCapability = "node_publish"       // 发布版本
+	KBCapabilityNodeReview        KBCapability = "node_review"        // 审核文档，未指定审核人时生效
+	KBCapabilityNodePermission    KBCapability = "node_permission"    // 设置文档访问权限
+	KBCapabilityAppConfig         KBCapability = "app_config"         // 知识库及应用配置
+	KBCapabilityStatView          KBCapability = "stat_view"          // 查看统计
+	KBCapabilityConversationView  KBCapability = "conversation_view"  // 查看对话
+	KBCapabilityConversationIP    KBCapability = "conversation_ip"    // 查看对话和评论的来源 IP
+	KBCapabilityConversationReply KBCapability = "conversation_reply" // 人工回复及结束转人工对话
+	KBCapabilityCommentManage     KBCapability = "comment_manage"     // 管理评论
+	KBCapabilityUserManage        KBCapability = "user_manage"        // 管理成员及读者认证
+)
+
+var KBCapabilities = []KBCapability{
+	KBCapabilityNodeEdit,
+	KBCapabilityNodePublish,
+	KBCapabilityNodeReview,
+	KBCapabilityNodePermission,
+	KBCapabilityAppConfig,
+	KBCapabilityStatView,
+	KBCapabilityConversationView,
+	KBCapabilityConversationIP,
+	KBCapabilityConversationReply,
+	KBCapabilityCommentManage,
+	KBCapabilityUserManage,
+}
+
+// Capabilities 内置权限对应的能力
+func (p UserKBPermission) Capabilities() []KBCapability {
+	switch p {
+	case UserKBPermissionFullControl:
+		return KBCapabilities
+	case UserKBPermissionDocManage:
+		return []KBCapability{KBCapabilityNodeEdit, KBCapabilityNodePublish, KBCapabilityNodePermission}
+	case UserKBPermissionDataOperate:
+		return []KBCapability{KBCapabilityStatView, KBCapabilityConversationView, KBCapabilityConversationIP, KBCapabilityConversationReply, KBCapabilityCommentManage}
+	default:
+		return nil
+	}
+}
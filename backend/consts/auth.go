@@ -3,7 +3,7 @@ package consts
 type SourceType string
 
 var (
-	BotSourceTypes = []SourceType{SourceTypeWidget, SourceTypeDingtalkBot, SourceTypeFeishuBot, SourceTypeLarkBot, SourceTypeWechatBot, SourceTypeWechatServiceBot, SourceTypeDiscordBot, SourceTypeWechatOfficialAccount}
+	BotSourceTypes = []SourceType{SourceTypeWidget, SourceTypeDingtalkBot, SourceTypeFeishuBot, SourceTypeLarkBot, SourceTypeWechatBot, SourceTypeWechatServiceBot, SourceTypeDiscordBot, SourceTypeWechatOfficialAccount, SourceTypeSlackBot, SourceTypeTelegramBot, SourceTypeTeamsBot, SourceTypeMailBot}
 )
 
 const (
@@ -14,6 +14,8 @@ const (
 	SourceTypeGitHub                SourceType = "github"
 	SourceTypeCAS                   SourceType = "cas"
 	SourceTypeLDAP                  SourceType = "ldap"
+	SourceTypeSAML                  SourceType = "saml"
+	SourceTypeSCIM                  SourceType = "scim"
 	SourceTypeWidget                SourceType = "widget"
 	SourceTypeDingtalkBot           SourceType = "dingtalk_bot"
 	SourceTypeFeishuBot             SourceType = "feishu_bot"
@@ -24,6 +26,10 @@ const (
 	SourceTypeDiscordBot            SourceType = "discord_bot"
 	SourceTypeWechatOfficialAccount SourceType = "wechat_official_account"
 	SourceTypeOpenAIAPI             SourceType = "openai_api"
+	SourceTypeSlackBot              SourceType = "slack_bot"
+	SourceTypeTelegramBot           SourceType = "telegram_bot"
+	SourceTypeTeamsBot              SourceType = "teams_bot"
+	SourceTypeMailBot               SourceType = "mail_bot"
 )
 
 func (s SourceType) Name() string {
@@ -46,6 +52,14 @@ func (s SourceType) Name() string {
 		return "Discord 机器人"
 	case SourceTypeWechatOfficialAccount:
 		return "微信公众号"
+	case SourceTypeSlackBot:
+		return "Slack 机器人"
+	case SourceTypeTelegramBot:
+		return "Telegram 机器人"
+	case SourceTypeTeamsBot:
+		return "Teams 机器人"
+	case SourceTypeMailBot:
+		return "邮件问答"
 	default:
 		return ""
 	}
@@ -59,3 +73,23 @@ const (
 	AuthTypeEnterprise AuthType = "enterprise" // 企业认证
 
 )
+
+// GroupSyncSourceTypes 支持同步部门/用户组到读者用户组的认证方式
+var GroupSyncSourceTypes = []SourceType{SourceTypeLDAP, SourceTypeDingTalk, SourceTypeFeishu, SourceTypeWeCom}
+
+// SCIMSourceTypes SCIM 开通的读者可使用的登录方式
+var SCIMSourceTypes = []SourceType{SourceTypeLDAP, SourceTypeCAS, SourceTypeOAuth, SourceTypeSAML, SourceTypeDingTalk, SourceTypeFeishu, SourceTypeWeCom}
+
+type AuthGroupSyncStatus string
+
+const (
+	AuthGroupSyncStatusSuccess AuthGroupSyncStatus = "success"
+	AuthGroupSyncStatusFailed  AuthGroupSyncStatus = "failed"
+)
+
+type AuthGroupSyncTrigger string
+
+const (
+	AuthGroupSyncTriggerCron   AuthGroupSyncTrigger = "cron"   // 定时同步
+	AuthGroupSyncTriggerManual AuthGroupSyncTrigger = "manual" // 管理员手动触发
+)
@@ -0,0 +1,18 @@
+package consts
+
+// ConversationScope decides which group chat messages of a bot continue the same conversation
+type ConversationScope string
+
+const (
+	ConversationScopeThread   ConversationScope = "thread"    // 同一话题或回复链, 都没有时同一群内同一用户, 默认
+	ConversationScopeChatUser ConversationScope = "chat_user" // 同一群内同一用户
+	ConversationScopeChat     ConversationScope = "chat"      // 同一群内所有用户
+	ConversationScopeNone     ConversationScope = "none"      // 每条消息都是新会话
+)
+
+type AttachmentType string
+
+const (
+	AttachmentTypeImage    AttachmentType = "image"    // 视觉模型识别文字并描述
+	AttachmentTypeDocument AttachmentType = "document" // 解析为文本
+)
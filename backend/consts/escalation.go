@@ -0,0 +1,30 @@
+package consts
+
+type EscalationStatus string
+
+const (
+	EscalationStatusNone     EscalationStatus = ""         // 未转人工
+	EscalationStatusOpen     EscalationStatus = "open"     // 等待人工处理
+	EscalationStatusResolved EscalationStatus = "resolved" // 已处理
+)
+
+type EscalationReason string
+
+const (
+	EscalationReasonUserRequest EscalationReason = "user_request" // 用户要求转人工
+	EscalationReasonNoResult    EscalationReason = "no_result"    // 未检索到相关文档
+	EscalationReasonDislike     EscalationReason = "dislike"      // 回答多次被点踩
+)
+
+func (r EscalationReason) Name() string {
+	switch r {
+	case EscalationReasonUserRequest:
+		return "用户要求转人工"
+	case EscalationReasonNoResult:
+		return "未检索到相关文档"
+	case EscalationReasonDislike:
+		return "回答多次被点踩"
+	default:
+		return string(r)
+	}
+}
@@ -0,0 +1,26 @@
+package consts
+
+type MailReplyMode string
+
+const (
+	MailReplyModeAuto  MailReplyMode = "auto"  // 自动回复
+	MailReplyModeDraft MailReplyMode = "draft" // 生成草稿, 审核后发送
+)
+
+type MailReplyStatus string
+
+const (
+	MailReplyStatusPending   MailReplyStatus = "pending"   // 草稿待审核
+	MailReplyStatusSending   MailReplyStatus = "sending"   // 正在发送, 防止重复发送
+	MailReplyStatusSent      MailReplyStatus = "sent"      // 已发送
+	MailReplyStatusFailed    MailReplyStatus = "failed"    // 发送失败
+	MailReplyStatusDiscarded MailReplyStatus = "discarded" // 已丢弃
+)
+
+type MailSecurity string
+
+const (
+	MailSecurityTLS      MailSecurity = "tls"      // 隐式 TLS, 如 IMAPS 993 / SMTPS 465
+	MailSecurityStartTLS MailSecurity = "starttls" // 明文连接后升级, 如 IMAP 143 / SMTP 587
+	MailSecurityNone     MailSecurity = "none"
+)
@@ -29,3 +29,58 @@ const (
 	NodeRagStatusEnhanceFailed    NodeRagInfoStatus = "ENHANCE_FAILED"    // 增强处理失败
 	NodeRagStatusEnhanceSucceeded NodeRagInfoStatus = "ENHANCE_SUCCEEDED" // 增强处理成功
 )
+
+type NodeReviewStatus string
+
+const (
+	NodeReviewStatusNone             NodeReviewStatus = ""                  // 未提交审核
+	NodeReviewStatusPending          NodeReviewStatus = "pending"           // 待审核
+	NodeReviewStatusApproved         NodeReviewStatus = "approved"          // 审核通过
+	NodeReviewStatusChangesRequested NodeReviewStatus = "changes_requested" // 需要修改
+)
+
+type NodeReviewAction string
+
+const (
+	NodeReviewActionSubmit         NodeReviewAction = "submit"          // 提交审核
+	NodeReviewActionApprove        NodeReviewAction = "approve"         // 审核通过
+	NodeReviewActionRequestChanges NodeReviewAction = "request_changes" // 要求修改
+)
+
+type NodeAccessRequestStatus string
+
+const (
+	NodeAccessRequestStatusPending  NodeAccessRequestStatus = "pending"  // 待处理
+	NodeAccessRequestStatusApproved NodeAccessRequestStatus = "approved" // 已批准
+	NodeAccessRequestStatusRejected NodeAccessRequestStatus = "rejected" // 已拒绝
+)
+
+type NodeAccessRequestAction string
+
+const (
+	NodeAccessRequestActionApprove NodeAccessRequestAction = "approve"
+	NodeAccessRequestActionReject  NodeAccessRequestAction = "reject"
+)
+
+// NodeAccessGrantType 批准访问申请的方式
+type NodeAccessGrantType string
+
+const (
+	NodeAccessGrantTypeAuthGroup NodeAccessGrantType = "auth_group" // 加入用户组
+	NodeAccessGrantTypeNode      NodeAccessGrantType = "node"       // 仅授权该文档
+)
+
+type NodeLinkType string
+
+const (
+	NodeLinkTypeInternal NodeLinkType = "internal"
+	NodeLinkTypeExternal NodeLinkType = "external"
+)
+
+type NodeLinkBrokenReason string
+
+const (
+	NodeLinkBrokenReasonDeleted     NodeLinkBrokenReason = "deleted"     // 目标文档已删除
+	NodeLinkBrokenReasonUnpublished NodeLinkBrokenReason = "unpublished" // 目标文档未发布
+	NodeLinkBrokenReasonUnreachable NodeLinkBrokenReason = "unreachable" // 外部链接无法访问
+)
@@ -156,14 +156,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/auth/delete": {
-            "delete": {
+        "/api/v1/app/mail/reply": {
+            "get": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "删除授权信息",
+                "description": "邮件问答生成的回复, 草稿模式下待审核的回复状态为 pending",
                 "consumes": [
                     "application/json"
                 ],
@@ -171,100 +171,68 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "MailReply"
                 ],
-                "summary": "删除授权信息",
-                "operationId": "v1-OpenAuthDelete",
+                "summary": "获取邮件回复列表",
+                "operationId": "v1-GetMailReplyList",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "name": "id",
+                        "type": "string",
+                        "name": "app_id",
                         "in": "query"
                     },
                     {
                         "type": "string",
                         "name": "kb_id",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/domain.Response"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/auth/get": {
-            "get": {
-                "security": [
+                        "in": "query",
+                        "required": true
+                    },
                     {
-                        "bearerAuth": []
-                    }
-                ],
-                "description": "获取授权信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Auth"
-                ],
-                "summary": "获取授权信息",
-                "operationId": "v1-OpenAuthGet",
-                "parameters": [
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
                     {
-                        "type": "string",
-                        "name": "kb_id",
-                        "in": "query"
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "per_page",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "enum": [
-                            "dingtalk",
-                            "feishu",
-                            "wecom",
-                            "oauth",
-                            "github",
-                            "cas",
-                            "ldap",
-                            "widget",
-                            "dingtalk_bot",
-                            "feishu_bot",
-                            "lark_bot",
-                            "wechat_bot",
-                            "wecom_ai_bot",
-                            "wechat_service_bot",
-                            "discord_bot",
-                            "wechat_official_account",
-                            "openai_api"
+                            "pending",
+                            "sending",
+                            "sent",
+                            "failed",
+                            "discarded"
                         ],
                         "type": "string",
+                        "x-enum-comments": {
+                            "MailReplyStatusDiscarded": "已丢弃",
+                            "MailReplyStatusFailed": "发送失败",
+                            "MailReplyStatusPending": "草稿待审核",
+                            "MailReplyStatusSending": "正在发送, 防止重复发送",
+                            "MailReplyStatusSent": "已发送"
+                        },
+                        "x-enum-descriptions": [
+                            "草稿待审核",
+                            "正在发送, 防止重复发送",
+                            "已发送",
+                            "发送失败",
+                            "已丢弃"
+                        ],
                         "x-enum-varnames": [
-                            "SourceTypeDingTalk",
-                            "SourceTypeFeishu",
-                            "SourceTypeWeCom",
-                            "SourceTypeOAuth",
-                            "SourceTypeGitHub",
-                            "SourceTypeCAS",
-                            "SourceTypeLDAP",
-                            "SourceTypeWidget",
-                            "SourceTypeDingtalkBot",
-                            "SourceTypeFeishuBot",
-                            "SourceTypeLarkBot",
-                            "SourceTypeWechatBot",
-                            "SourceTypeWecomAIBot",
-                            "SourceTypeWechatServiceBot",
-                            "SourceTypeDiscordBot",
-                            "SourceTypeWechatOfficialAccount",
-                            "SourceTypeOpenAIAPI"
+                            "MailReplyStatusPending",
+                            "MailReplyStatusSending",
+                            "MailReplyStatusSent",
+                            "MailReplyStatusFailed",
+                            "MailReplyStatusDiscarded"
                         ],
-                        "name": "source_type",
-                        "in": "query",
-                        "required": true
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -279,7 +247,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_chaitin_panda-wiki_api_auth_v1.AuthGetResp"
+                                            "$ref": "#/definitions/v1.MailReplyListResp"
                                         }
                                     }
                                 }
@@ -287,16 +255,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/auth/set": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "设置授权信息",
+                "description": "仅未发送的回复可编辑",
                 "consumes": [
                     "application/json"
                 ],
@@ -304,10 +270,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "MailReply"
                 ],
-                "summary": "设置授权信息",
-                "operationId": "v1-OpenAuthSet",
+                "summary": "编辑邮件回复草稿",
+                "operationId": "v1-UpdateMailReply",
                 "parameters": [
                     {
                         "description": "para",
@@ -315,7 +281,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.AuthSetReq"
+                            "$ref": "#/definitions/v1.MailReplyUpdateReq"
                         }
                     }
                 ],
@@ -329,9 +295,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/comment": {
-            "get": {
-                "description": "GetCommentModeratedList",
+        "/api/v1/app/mail/reply/discard": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "丢弃后不再发送",
                 "consumes": [
                     "application/json"
                 ],
@@ -339,72 +310,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "comment"
+                    "MailReply"
                 ],
-                "summary": "GetCommentModeratedList",
+                "summary": "丢弃邮件回复草稿",
+                "operationId": "v1-DiscardMailReply",
                 "parameters": [
                     {
-                        "type": "string",
-                        "name": "kb_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "name": "per_page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "enum": [
-                            -1,
-                            0,
-                            1
-                        ],
-                        "type": "integer",
-                        "format": "int32",
-                        "x-enum-varnames": [
-                            "CommentStatusReject",
-                            "CommentStatusPending",
-                            "CommentStatusAccepted"
-                        ],
-                        "name": "status",
-                        "in": "query"
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.MailReplyActionReq"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "conversationList",
+                        "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/domain.PWResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/v1.CommentLists"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/domain.Response"
                         }
                     }
                 }
             }
         },
-        "/api/v1/comment/list": {
-            "delete": {
-                "description": "DeleteCommentList",
+        "/api/v1/app/mail/reply/send": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "发送待审核或发送失败的回复",
                 "consumes": [
                     "application/json"
                 ],
@@ -412,23 +350,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "comment"
+                    "MailReply"
                 ],
-                "summary": "DeleteCommentList",
+                "summary": "审核通过并发送邮件回复",
+                "operationId": "v1-SendMailReply",
                 "parameters": [
                     {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "csv",
-                        "name": "ids",
-                        "in": "query"
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.MailReplyActionReq"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "total",
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/domain.Response"
                         }
@@ -436,53 +375,68 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/conversation": {
+        "/api/v1/audit_log/export": {
             "get": {
-                "description": "get conversation list",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
                 ],
+                "description": "按筛选条件导出为 CSV 或 JSONL 文件，按时间正序",
                 "produces": [
-                    "application/json"
+                    "application/octet-stream"
                 ],
                 "tags": [
-                    "conversation"
+                    "AuditLog"
                 ],
-                "summary": "get conversation list",
+                "summary": "导出审计日志",
+                "operationId": "v1-ExportAuditLogs",
                 "parameters": [
                     {
                         "type": "string",
-                        "name": "app_id",
+                        "name": "action",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "name": "kb_id",
-                        "in": "query",
-                        "required": true
+                        "name": "actor_id",
+                        "in": "query"
                     },
                     {
-                        "minimum": 1,
                         "type": "integer",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
+                        "description": "unix 秒，不包含",
+                        "name": "end",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "jsonl"
+                        ],
+                        "type": "string",
+                        "description": "默认 csv",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query"
                     },
                     {
-                        "minimum": 1,
                         "type": "integer",
-                        "name": "per_page",
-                        "in": "query",
-                        "required": true
+                        "description": "unix 秒，包含",
+                        "name": "start",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "name": "remote_ip",
+                        "name": "target_id",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "name": "subject",
+                        "name": "target_type",
                         "in": "query"
                     }
                 ],
@@ -490,76 +444,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/domain.PWResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/v1.ConversationListItems"
-                                        }
-                                    }
-                                }
-                            ]
+                            "type": "file"
                         }
                     }
                 }
             }
         },
-        "/api/v1/conversation/detail": {
+        "/api/v1/audit_log/list": {
             "get": {
-                "description": "get conversation detail",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "conversation"
-                ],
-                "summary": "get conversation detail",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
-                    },
+                "security": [
                     {
-                        "type": "string",
-                        "name": "kb_id",
-                        "in": "query",
-                        "required": true
+                        "bearerAuth": []
                     }
                 ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/domain.PWResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/domain.ConversationDetailResp"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/conversation/message/detail": {
-            "get": {
-                "description": "Get message detail",
+                "description": "按知识库、操作人、操作、目标及时间筛选，按时间倒序",
                 "consumes": [
                     "application/json"
                 ],
@@ -567,64 +465,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Message"
+                    "AuditLog"
                 ],
-                "summary": "Get message detail",
+                "summary": "审计日志列表",
+                "operationId": "v1-ListAuditLogs",
                 "parameters": [
                     {
                         "type": "string",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "name": "action",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "name": "kb_id",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/domain.PWResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/domain.ConversationMessage"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/conversation/message/list": {
-            "get": {
-                "description": "GetMessageFeedBackList",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Message"
-                ],
-                "summary": "GetMessageFeedBackList",
-                "parameters": [
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "unix 秒，不包含",
+                        "name": "end",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "name": "kb_id",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     },
                     {
                         "minimum": 1,
@@ -639,11 +504,27 @@ const docTemplate = `{
                         "name": "per_page",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "unix 秒，包含",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "target_type",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "MessageList",
+                        "description": "OK",
                         "schema": {
                             "allOf": [
                                 {
@@ -653,7 +534,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/domain.PaginatedResult-array_domain_ConversationMessageListItem"
+                                            "$ref": "#/definitions/v1.AuditLogListResp"
                                         }
                                     }
                                 }
@@ -663,9 +544,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/crawler/export": {
-            "post": {
-                "description": "CrawlerExport",
+        "/api/v1/audit_log/setting": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "获取审计日志保留天数",
                 "consumes": [
                     "application/json"
                 ],
@@ -673,20 +559,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "crawler"
-                ],
-                "summary": "CrawlerExport",
-                "parameters": [
-                    {
-                        "description": "Scrape",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.CrawlerExportReq"
-                        }
-                    }
+                    "AuditLog"
                 ],
+                "summary": "获取审计日志设置",
+                "operationId": "v1-GetAuditLogSetting",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -699,7 +575,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/v1.CrawlerExportResp"
+                                            "$ref": "#/definitions/domain.AuditLogSetting"
                                         }
                                     }
                                 }
@@ -707,11 +583,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/crawler/parse": {
-            "post": {
-                "description": "解析文档树",
+            },
+            "put": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "设置审计日志保留天数，0 表示永久保留",
                 "consumes": [
                     "application/json"
                 ],
@@ -719,17 +598,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "crawler"
+                    "AuditLog"
                 ],
-                "summary": "解析文档树",
+                "summary": "更新审计日志设置",
+                "operationId": "v1-UpdateAuditLogSetting",
                 "parameters": [
                     {
-                        "description": "Scrape",
-                        "name": "body",
+                        "description": "para",
+                        "name": "param",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.CrawlerParseReq"
+                            "$ref": "#/definitions/v1.AuditLogSettingReq"
                         }
                     }
                 ],
@@ -737,27 +617,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/domain.PWResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/v1.CrawlerParseResp"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/domain.Response"
                         }
                     }
                 }
             }
         },
-        "/api/v1/crawler/result": {
+        "/api/v1/audit_log/verify": {
             "get": {
-                "description": "Retrieve the result of a previously started scraping task",
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "从最早的记录开始校验哈希链，返回第一条被篡改的记录",
                 "consumes": [
                     "application/json"
                 ],
@@ -765,20 +638,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "crawler"
-                ],
-                "summary": "Get Crawler Result",
-                "parameters": [
-                    {
-                        "description": "Crawler Result Request",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.CrawlerResultReq"
-                        }
-                    }
+                    "AuditLog"
                 ],
+                "summary": "校验审计日志",
+                "operationId": "v1-VerifyAuditLogs",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -791,7 +654,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/v1.CrawlerResultResp"
+                                            "$ref": "#/definitions/v1.AuditLogVerifyResp"
                                         }
                                     }
                                 }
@@ -801,9 +664,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/crawler/results": {
-            "post": {
-                "description": "Retrieve the results of a previously started scraping task",
+        "/api/v1/auth/delete": {
+            "delete": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "删除授权信息",
                 "consumes": [
                     "application/json"
                 ],
@@ -811,79 +679,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "crawler"
+                    "Auth"
                 ],
-                "summary": "Get Crawler Results",
+                "summary": "删除授权信息",
+                "operationId": "v1-OpenAuthDelete",
                 "parameters": [
                     {
-                        "description": "Crawler Results Request",
-                        "name": "param",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.CrawlerResultsReq"
-                        }
+                        "type": "integer",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/domain.PWResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/v1.CrawlerResultsResp"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/domain.Response"
                         }
                     }
                 }
             }
         },
-        "/api/v1/creation/tab-complete": {
-            "post": {
-                "description": "Tab-based document completion similar to AI coding's FIM (Fill in Middle)",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "creation"
-                ],
-                "summary": "Tab-based document completion",
-                "parameters": [
+        "/api/v1/auth/get": {
+            "get": {
+                "security": [
                     {
-                        "description": "tab completion request",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.CompleteReq"
-                        }
+                        "bearerAuth": []
                     }
                 ],
-                "responses": {
-                    "200": {
-                        "description": "success",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/creation/text": {
-            "post": {
-                "description": "Text creation",
+                "description": "获取授权信息",
                 "consumes": [
                     "application/json"
                 ],
@@ -891,88 +720,70 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "creation"
-                ],
-                "summary": "Text creation",
-                "parameters": [
-                    {
-                        "description": "text creation request",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.TextReq"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "success",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/file/upload": {
-            "post": {
-                "description": "Upload File",
-                "consumes": [
-                    "multipart/form-data"
-                ],
-                "tags": [
-                    "file"
+                    "Auth"
                 ],
-                "summary": "Upload File",
+                "summary": "获取授权信息",
+                "operationId": "v1-OpenAuthGet",
                 "parameters": [
-                    {
-                        "type": "file",
-                        "description": "File",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
-                    },
                     {
                         "type": "string",
-                        "description": "Knowledge Base ID",
                         "name": "kb_id",
-                        "in": "formData"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/domain.ObjectUploadResp"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/file/upload/anydoc": {
-            "post": {
-                "description": "Upload Anydoc File",
-                "consumes": [
-                    "multipart/form-data"
-                ],
-                "tags": [
-                    "file"
-                ],
-                "summary": "Upload Anydoc File",
-                "parameters": [
-                    {
-                        "type": "file",
-                        "description": "File",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
+                        "in": "query"
                     },
                     {
+                        "enum": [
+                            "dingtalk",
+                            "feishu",
+                            "wecom",
+                            "oauth",
+                            "github",
+                            "cas",
+                            "ldap",
+                            "saml",
+                            "scim",
+                            "widget",
+                            "dingtalk_bot",
+                            "feishu_bot",
+                            "lark_bot",
+                            "wechat_bot",
+                            "wecom_ai_bot",
+                            "wechat_service_bot",
+                            "discord_bot",
+                            "wechat_official_account",
+                            "openai_api",
+                            "slack_bot",
+                            "telegram_bot",
+                            "teams_bot",
+                            "mail_bot"
+                        ],
                         "type": "string",
-                        "description": "File Path",
-                        "name": "path",
-                        "in": "formData",
+                        "x-enum-varnames": [
+                            "SourceTypeDingTalk",
+                            "SourceTypeFeishu",
+                            "SourceTypeWeCom",
+                            "SourceTypeOAuth",
+                            "SourceTypeGitHub",
+                            "SourceTypeCAS",
+                            "SourceTypeLDAP",
+                            "SourceTypeSAML",
+                            "SourceTypeSCIM",
+                            "SourceTypeWidget",
+                            "SourceTypeDingtalkBot",
+                            "SourceTypeFeishuBot",
+                            "SourceTypeLarkBot",
+                            "SourceTypeWechatBot",
+                            "SourceTypeWecomAIBot",
+                            "SourceTypeWechatServiceBot",
+                            "SourceTypeDiscordBot",
+                            "SourceTypeWechatOfficialAccount",
+                            "SourceTypeOpenAIAPI",
+                            "SourceTypeSlackBot",
+                            "SourceTypeTelegramBot",
+                            "SourceTypeTeamsBot",
+                            "SourceTypeMailBot"
+                        ],
+                        "name": "source_type",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -980,15 +791,32 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.AnydocUploadResp"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_chaitin_panda-wiki_api_auth_v1.AuthGetResp"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/api/v1/knowledge_base": {
+        "/api/v1/auth/group/sync": {
             "post": {
-                "description": "CreateKnowledgeBase",
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "从 LDAP、钉钉、飞书、企业微信同步部门/用户组及成员到读者用户组，试运行仅返回差异",
                 "consumes": [
                     "application/json"
                 ],
@@ -996,17 +824,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "knowledge_base"
+                    "Auth"
                 ],
-                "summary": "CreateKnowledgeBase",
+                "summary": "同步用户组",
+                "operationId": "v1-AuthGroupSync",
                 "parameters": [
                     {
-                        "description": "CreateKnowledgeBase Request",
-                        "name": "body",
+                        "description": "para",
+                        "name": "param",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.CreateKnowledgeBaseReq"
+                            "$ref": "#/definitions/v1.AuthGroupSyncReq"
                         }
                     }
                 ],
@@ -1014,20 +843,32 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.AuthGroupSyncRun"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/api/v1/knowledge_base/detail": {
+        "/api/v1/auth/group/sync/runs": {
             "get": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "GetKnowledgeBaseDetail",
+                "description": "用户组同步的运行报告，包含每次运行的差异",
                 "consumes": [
                     "application/json"
                 ],
@@ -1035,16 +876,85 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "knowledge_base"
+                    "Auth"
                 ],
-                "summary": "GetKnowledgeBaseDetail",
+                "summary": "用户组同步记录",
+                "operationId": "v1-AuthGroupSyncRuns",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Knowledge Base ID",
-                        "name": "id",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "per_page",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "dingtalk",
+                            "feishu",
+                            "wecom",
+                            "oauth",
+                            "github",
+                            "cas",
+                            "ldap",
+                            "saml",
+                            "scim",
+                            "widget",
+                            "dingtalk_bot",
+                            "feishu_bot",
+                            "lark_bot",
+                            "wechat_bot",
+                            "wecom_ai_bot",
+                            "wechat_service_bot",
+                            "discord_bot",
+                            "wechat_official_account",
+                            "openai_api",
+                            "slack_bot",
+                            "telegram_bot",
+                            "teams_bot",
+                            "mail_bot"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "SourceTypeDingTalk",
+                            "SourceTypeFeishu",
+                            "SourceTypeWeCom",
+                            "SourceTypeOAuth",
+                            "SourceTypeGitHub",
+                            "SourceTypeCAS",
+                            "SourceTypeLDAP",
+                            "SourceTypeSAML",
+                            "SourceTypeSCIM",
+                            "SourceTypeWidget",
+                            "SourceTypeDingtalkBot",
+                            "SourceTypeFeishuBot",
+                            "SourceTypeLarkBot",
+                            "SourceTypeWechatBot",
+                            "SourceTypeWecomAIBot",
+                            "SourceTypeWechatServiceBot",
+                            "SourceTypeDiscordBot",
+                            "SourceTypeWechatOfficialAccount",
+                            "SourceTypeOpenAIAPI",
+                            "SourceTypeSlackBot",
+                            "SourceTypeTelegramBot",
+                            "SourceTypeTeamsBot",
+                            "SourceTypeMailBot"
+                        ],
+                        "name": "source_type",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1059,7 +969,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/domain.KnowledgeBaseDetail"
+                                            "$ref": "#/definitions/v1.AuthGroupSyncRunListResp"
                                         }
                                     }
                                 }
@@ -1067,41 +977,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "UpdateKnowledgeBase",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "knowledge_base"
-                ],
-                "summary": "UpdateKnowledgeBase",
-                "parameters": [
+            }
+        },
+        "/api/v1/auth/scim": {
+            "get": {
+                "security": [
                     {
-                        "description": "UpdateKnowledgeBase Request",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.UpdateKnowledgeBaseReq"
-                        }
+                        "bearerAuth": []
                     }
                 ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/domain.Response"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "DeleteKnowledgeBase",
+                "description": "获取 SCIM 配置，不返回 token",
                 "consumes": [
                     "application/json"
                 ],
@@ -1109,41 +994,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "knowledge_base"
+                    "Auth"
                 ],
-                "summary": "DeleteKnowledgeBase",
+                "summary": "获取 SCIM 配置",
+                "operationId": "v1-AuthSCIMGet",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Knowledge Base ID",
-                        "name": "id",
+                        "name": "kb_id",
                         "in": "query",
                         "required": true
                     }
                 ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/domain.Response"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/knowledge_base/list": {
-            "get": {
-                "description": "GetKnowledgeBaseList",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "knowledge_base"
-                ],
-                "summary": "GetKnowledgeBaseList",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1156,10 +1018,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/domain.KnowledgeBaseListItem"
-                                            }
+                                            "$ref": "#/definitions/v1.AuthSCIMGetResp"
                                         }
                                     }
                                 }
@@ -1167,11 +1026,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/knowledge_base/release": {
+            },
             "post": {
-                "description": "CreateKBRelease",
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "开启 SCIM 开通，首次开启或重置时返回 token，token 仅返回一次",
                 "consumes": [
                     "application/json"
                 ],
@@ -1179,51 +1041,20 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "knowledge_base"
+                    "Auth"
                 ],
-                "summary": "CreateKBRelease",
+                "summary": "设置 SCIM 配置",
+                "operationId": "v1-AuthSCIMSet",
                 "parameters": [
                     {
-                        "description": "CreateKBRelease Request",
-                        "name": "body",
+                        "description": "para",
+                        "name": "param",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.CreateKBReleaseReq"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/domain.Response"
+                            "$ref": "#/definitions/v1.AuthSCIMSetReq"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/knowledge_base/release/list": {
-            "get": {
-                "description": "GetKBReleaseList",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "knowledge_base"
-                ],
-                "summary": "GetKBReleaseList",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Knowledge Base ID",
-                        "name": "kb_id",
-                        "in": "query",
-                        "required": true
-                    }
                 ],
                 "responses": {
                     "200": {
@@ -1237,7 +1068,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/domain.GetKBReleaseListResp"
+                                            "$ref": "#/definitions/v1.AuthSCIMSetResp"
                                         }
                                     }
                                 }
@@ -1245,16 +1076,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/knowledge_base/user/delete": {
+            },
             "delete": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "Remove user from knowledge base",
+                "description": "关闭 SCIM 并使 token 失效，已开通的读者及用户组保留",
                 "consumes": [
                     "application/json"
                 ],
@@ -1262,41 +1091,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "knowledge_base"
+                    "Auth"
                 ],
-                "summary": "KBUserDelete",
+                "summary": "关闭 SCIM",
+                "operationId": "v1-AuthSCIMDelete",
                 "parameters": [
                     {
                         "type": "string",
                         "name": "kb_id",
                         "in": "query",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "name": "user_id",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Response"
+                            "$ref": "#/definitions/domain.PWResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/knowledge_base/user/invite": {
+        "/api/v1/auth/set": {
             "post": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "Invite user to knowledge base",
+                "description": "设置授权信息",
                 "consumes": [
                     "application/json"
                 ],
@@ -1304,17 +1128,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "knowledge_base"
+                    "Auth"
                 ],
-                "summary": "KBUserInvite",
+                "summary": "设置授权信息",
+                "operationId": "v1-OpenAuthSet",
                 "parameters": [
                     {
-                        "description": "Invite User Request",
+                        "description": "para",
                         "name": "param",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.KBUserInviteReq"
+                            "$ref": "#/definitions/v1.AuthSetReq"
                         }
                     }
                 ],
@@ -1328,14 +1153,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/knowledge_base/user/list": {
+        "/api/v1/comment": {
             "get": {
-                "security": [
-                    {
-                        "bearerAuth": []
-                    }
-                ],
-                "description": "KBUserList",
+                "description": "GetCommentModeratedList",
                 "consumes": [
                     "application/json"
                 ],
@@ -1343,21 +1163,50 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "knowledge_base"
+                    "comment"
                 ],
-                "summary": "KBUserList",
+                "summary": "GetCommentModeratedList",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Knowledge Base ID",
                         "name": "kb_id",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "per_page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            -1,
+                            0,
+                            1
+                        ],
+                        "type": "integer",
+                        "format": "int32",
+                        "x-enum-varnames": [
+                            "CommentStatusReject",
+                            "CommentStatusPending",
+                            "CommentStatusAccepted"
+                        ],
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "conversationList",
                         "schema": {
                             "allOf": [
                                 {
@@ -1367,10 +1216,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/v1.KBUserListItemResp"
-                                            }
+                                            "$ref": "#/definitions/v1.CommentLists"
                                         }
                                     }
                                 }
@@ -1380,14 +1226,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/knowledge_base/user/update": {
-            "patch": {
-                "security": [
-                    {
-                        "bearerAuth": []
-                    }
-                ],
-                "description": "Update user permission in knowledge base",
+        "/api/v1/comment/list": {
+            "delete": {
+                "description": "DeleteCommentList",
                 "consumes": [
                     "application/json"
                 ],
@@ -1395,23 +1236,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "knowledge_base"
+                    "comment"
                 ],
-                "summary": "KBUserUpdate",
+                "summary": "DeleteCommentList",
                 "parameters": [
                     {
-                        "description": "Update User Permission Request",
-                        "name": "param",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.KBUserUpdateReq"
-                        }
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "name": "ids",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "total",
                         "schema": {
                             "$ref": "#/definitions/domain.Response"
                         }
@@ -1419,9 +1260,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/model": {
-            "put": {
-                "description": "update model",
+        "/api/v1/conversation": {
+            "get": {
+                "description": "get conversation list",
                 "consumes": [
                     "application/json"
                 ],
@@ -1429,83 +1270,69 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "model"
+                    "conversation"
                 ],
+                "summary": "get conversation list",
                 "parameters": [
                     {
-                        "description": "update model request",
-                        "name": "model",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.UpdateModelReq"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/domain.Response"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "create model",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "model"
-                ],
-                "summary": "create model",
-                "parameters": [
+                        "type": "string",
+                        "name": "app_id",
+                        "in": "query"
+                    },
                     {
-                        "description": "create model request",
-                        "name": "model",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.CreateModelReq"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/domain.Response"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/model/check": {
-            "post": {
-                "description": "check model",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "model"
-                ],
-                "summary": "check model",
-                "parameters": [
+                        "enum": [
+                            "",
+                            "open",
+                            "resolved"
+                        ],
+                        "type": "string",
+                        "x-enum-comments": {
+                            "EscalationStatusNone": "未转人工",
+                            "EscalationStatusOpen": "等待人工处理",
+                            "EscalationStatusResolved": "已处理"
+                        },
+                        "x-enum-descriptions": [
+                            "未转人工",
+                            "等待人工处理",
+                            "已处理"
+                        ],
+                        "x-enum-varnames": [
+                            "EscalationStatusNone",
+                            "EscalationStatusOpen",
+                            "EscalationStatusResolved"
+                        ],
+                        "name": "escalation_status",
+                        "in": "query"
+                    },
                     {
-                        "description": "check model request",
-                        "name": "model",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.CheckModelReq"
-                        }
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "per_page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "remote_ip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "subject",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1514,13 +1341,13 @@ const docTemplate = `{
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/domain.Response"
+                                    "$ref": "#/definitions/domain.PWResponse"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.CheckModelResp"
+                                            "$ref": "#/definitions/v1.ConversationListItems"
                                         }
                                     }
                                 }
@@ -1530,9 +1357,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/model/list": {
+        "/api/v1/conversation/detail": {
             "get": {
-                "description": "get model list",
+                "description": "get conversation detail",
                 "consumes": [
                     "application/json"
                 ],
@@ -1540,9 +1367,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "model"
+                    "conversation"
+                ],
+                "summary": "get conversation detail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "get model list",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1555,7 +1396,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.ModelListItem"
+                                            "$ref": "#/definitions/domain.ConversationDetailResp"
                                         }
                                     }
                                 }
@@ -1565,9 +1406,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/model/provider/supported": {
+        "/api/v1/conversation/escalation/reply": {
             "post": {
-                "description": "get provider supported model list",
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "回复保存到会话中, 并发送到用户所在的渠道。钉钉、企业微信、微信客服、公众号和 OpenAI API 的会话不支持人工回复",
                 "consumes": [
                     "application/json"
                 ],
@@ -1575,17 +1421,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "model"
+                    "ConversationEscalation"
                 ],
-                "summary": "get provider supported model list",
+                "summary": "人工回复会话",
+                "operationId": "v1-ReplyEscalation",
                 "parameters": [
                     {
-                        "description": "get supported model list request",
-                        "name": "params",
+                        "description": "para",
+                        "name": "param",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.GetProviderModelListReq"
+                            "$ref": "#/definitions/v1.EscalationReplyReq"
                         }
                     }
                 ],
@@ -1601,7 +1448,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/domain.GetProviderModelListResp"
+                                            "$ref": "#/definitions/v1.EscalationReplyResp"
                                         }
                                     }
                                 }
@@ -1611,14 +1458,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/node": {
+        "/api/v1/conversation/escalation/resolve": {
             "post": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "Create Node",
+                "description": "结束后该会话的新消息重新由 AI 回答",
                 "consumes": [
                     "application/json"
                 ],
@@ -1626,17 +1473,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "node"
+                    "ConversationEscalation"
                 ],
-                "summary": "Create Node",
+                "summary": "结束人工处理",
+                "operationId": "v1-ResolveEscalation",
                 "parameters": [
                     {
-                        "description": "Node",
-                        "name": "body",
+                        "description": "para",
+                        "name": "param",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.CreateNodeReq"
+                            "$ref": "#/definitions/v1.ResolveEscalationReq"
                         }
                     }
                 ],
@@ -1644,35 +1492,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/domain.PWResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "object",
-                                            "additionalProperties": {
-                                                "type": "string"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/domain.Response"
                         }
                     }
                 }
             }
         },
-        "/api/v1/node/action": {
-            "post": {
+        "/api/v1/conversation/escalation/settings": {
+            "get": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "Node Action",
+                "description": "获取转人工触发条件及值班通知设置",
                 "consumes": [
                     "application/json"
                 ],
@@ -1680,18 +1513,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "node"
+                    "ConversationEscalation"
                 ],
-                "summary": "Node Action",
+                "summary": "获取转人工设置",
+                "operationId": "v1-GetEscalationSettings",
                 "parameters": [
                     {
-                        "description": "Action",
-                        "name": "action",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.NodeActionReq"
-                        }
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1706,10 +1537,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "object",
-                                            "additionalProperties": {
-                                                "type": "string"
-                                            }
+                                            "$ref": "#/definitions/v1.GetEscalationSettingsResp"
                                         }
                                     }
                                 }
@@ -1717,16 +1545,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/node/batch_move": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "Batch Move Node",
+                "description": "用户要求转人工, 未检索到相关文档, 或多次点踩时转人工, 并通知值班的群机器人, webhook 或邮箱",
                 "consumes": [
                     "application/json"
                 ],
@@ -1734,17 +1560,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "node"
+                    "ConversationEscalation"
                 ],
-                "summary": "Batch Move Node",
+                "summary": "更新转人工设置",
+                "operationId": "v1-UpdateEscalationSettings",
                 "parameters": [
                     {
-                        "description": "Batch Move Node",
-                        "name": "body",
+                        "description": "para",
+                        "name": "param",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.BatchMoveReq"
+                            "$ref": "#/definitions/v1.UpdateEscalationSettingsReq"
                         }
                     }
                 ],
@@ -1758,14 +1585,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/node/detail": {
+        "/api/v1/conversation/message/detail": {
             "get": {
-                "security": [
-                    {
-                        "bearerAuth": []
-                    }
-                ],
-                "description": "Get Node Detail",
+                "description": "Get message detail",
                 "consumes": [
                     "application/json"
                 ],
@@ -1773,15 +1595,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "node"
+                    "Message"
                 ],
-                "summary": "Get Node Detail",
+                "summary": "Get message detail",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "name": "format",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
                         "name": "id",
@@ -1807,7 +1624,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/v1.NodeDetailResp"
+                                            "$ref": "#/definitions/domain.ConversationMessage"
                                         }
                                     }
                                 }
@@ -1815,14 +1632,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "bearerAuth": []
-                    }
-                ],
-                "description": "Update Node Detail",
+            }
+        },
+        "/api/v1/conversation/message/list": {
+            "get": {
+                "description": "GetMessageFeedBackList",
                 "consumes": [
                     "application/json"
                 ],
@@ -1830,48 +1644,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "node"
+                    "Message"
                 ],
-                "summary": "Update Node Detail",
-                "parameters": [
-                    {
-                        "description": "Node",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.UpdateNodeReq"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/domain.Response"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/node/list": {
-            "get": {
-                "security": [
-                    {
-                        "bearerAuth": []
-                    }
-                ],
-                "description": "Get Node List",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "node"
-                ],
-                "summary": "Get Node List",
+                "summary": "GetMessageFeedBackList",
                 "parameters": [
                     {
                         "type": "string",
@@ -1880,14 +1655,23 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "name": "search",
-                        "in": "query"
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "per_page",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "MessageList",
                         "schema": {
                             "allOf": [
                                 {
@@ -1897,10 +1681,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/domain.NodeListItemResp"
-                                            }
+                                            "$ref": "#/definitions/domain.PaginatedResult-array_domain_ConversationMessageListItem"
                                         }
                                     }
                                 }
@@ -1910,14 +1691,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/node/move": {
+        "/api/v1/crawler/export": {
             "post": {
-                "security": [
-                    {
-                        "bearerAuth": []
-                    }
-                ],
-                "description": "Move Node",
+                "description": "CrawlerExport",
                 "consumes": [
                     "application/json"
                 ],
@@ -1925,17 +1701,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "node"
+                    "crawler"
                 ],
-                "summary": "Move Node",
+                "summary": "CrawlerExport",
                 "parameters": [
                     {
-                        "description": "Move Node",
+                        "description": "Scrape",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.MoveNodeReq"
+                            "$ref": "#/definitions/v1.CrawlerExportReq"
                         }
                     }
                 ],
@@ -1943,20 +1719,27 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.CrawlerExportResp"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/api/v1/node/permission": {
-            "get": {
-                "security": [
-                    {
-                        "bearerAuth": []
-                    }
-                ],
-                "description": "文档授权信息获取",
+        "/api/v1/crawler/parse": {
+            "post": {
+                "description": "解析文档树",
                 "consumes": [
                     "application/json"
                 ],
@@ -1964,22 +1747,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "NodePermission"
+                    "crawler"
                 ],
-                "summary": "文档授权信息获取",
-                "operationId": "v1-NodePermission",
+                "summary": "解析文档树",
                 "parameters": [
                     {
-                        "type": "string",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "name": "kb_id",
-                        "in": "query",
-                        "required": true
+                        "description": "Scrape",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CrawlerParseReq"
+                        }
                     }
                 ],
                 "responses": {
@@ -1988,13 +1767,13 @@ const docTemplate = `{
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/domain.Response"
+                                    "$ref": "#/definitions/domain.PWResponse"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/v1.NodePermissionResp"
+                                            "$ref": "#/definitions/v1.CrawlerParseResp"
                                         }
                                     }
                                 }
@@ -2004,14 +1783,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/node/permission/edit": {
-            "patch": {
-                "security": [
-                    {
-                        "bearerAuth": []
-                    }
-                ],
-                "description": "文档授权信息更新",
+        "/api/v1/crawler/result": {
+            "get": {
+                "description": "Retrieve the result of a previously started scraping task",
                 "consumes": [
                     "application/json"
                 ],
@@ -2019,18 +1793,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "NodePermission"
+                    "crawler"
                 ],
-                "summary": "文档授权信息更新",
-                "operationId": "v1-NodePermissionEdit",
+                "summary": "Get Crawler Result",
                 "parameters": [
                     {
-                        "description": "para",
-                        "name": "param",
+                        "description": "Crawler Result Request",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.NodePermissionEditReq"
+                            "$ref": "#/definitions/v1.CrawlerResultReq"
                         }
                     }
                 ],
@@ -2040,13 +1813,13 @@ const docTemplate = `{
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/domain.Response"
+                                    "$ref": "#/definitions/domain.PWResponse"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/v1.NodePermissionEditResp"
+                                            "$ref": "#/definitions/v1.CrawlerResultResp"
                                         }
                                     }
                                 }
@@ -2056,14 +1829,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/node/recommend_nodes": {
-            "get": {
-                "security": [
-                    {
-                        "bearerAuth": []
-                    }
-                ],
-                "description": "Recommend Nodes",
+        "/api/v1/crawler/results": {
+            "post": {
+                "description": "Retrieve the results of a previously started scraping task",
                 "consumes": [
                     "application/json"
                 ],
@@ -2071,25 +1839,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "node"
+                    "crawler"
                 ],
-                "summary": "Recommend Nodes",
+                "summary": "Get Crawler Results",
                 "parameters": [
                     {
-                        "type": "string",
-                        "name": "kb_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "csv",
-                        "name": "node_ids",
-                        "in": "query",
-                        "required": true
+                        "description": "Crawler Results Request",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CrawlerResultsReq"
+                        }
                     }
                 ],
                 "responses": {
@@ -2104,10 +1865,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/domain.RecommendNodeListResp"
-                                            }
+                                            "$ref": "#/definitions/v1.CrawlerResultsResp"
                                         }
                                     }
                                 }
@@ -2117,14 +1875,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/node/summary": {
+        "/api/v1/creation/tab-complete": {
             "post": {
-                "security": [
-                    {
-                        "bearerAuth": []
-                    }
-                ],
-                "description": "Summary Node",
+                "description": "Tab-based document completion similar to AI coding's FIM (Fill in Middle)",
                 "consumes": [
                     "application/json"
                 ],
@@ -2132,38 +1885,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "node"
+                    "creation"
                 ],
-                "summary": "Summary Node",
+                "summary": "Tab-based document completion",
                 "parameters": [
                     {
-                        "description": "Summary Node",
+                        "description": "tab completion request",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.NodeSummaryReq"
+                            "$ref": "#/definitions/domain.CompleteReq"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "success",
                         "schema": {
-                            "$ref": "#/definitions/domain.Response"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/api/v1/stat/browsers": {
-            "get": {
-                "security": [
-                    {
-                        "bearerAuth": []
-                    }
-                ],
-                "description": "客户端统计",
+        "/api/v1/creation/text": {
+            "post": {
+                "description": "Text creation",
                 "consumes": [
                     "application/json"
                 ],
@@ -2171,64 +1919,109 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "stat"
+                    "creation"
                 ],
-                "summary": "客户端统计",
+                "summary": "Text creation",
                 "parameters": [
                     {
-                        "enum": [
-                            1,
-                            7,
-                            30,
-                            90
-                        ],
-                        "type": "integer",
-                        "x-enum-varnames": [
-                            "StatDay1",
-                            "StatDay7",
-                            "StatDay30",
-                            "StatDay90"
-                        ],
-                        "name": "day",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "kb_id",
-                        "in": "query",
-                        "required": true
+                        "description": "text creation request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.TextReq"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "success",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/domain.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/domain.HotBrowser"
-                                        }
-                                    }
-                                }
-                            ]
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/api/v1/stat/conversation_distribution": {
-            "get": {
-                "security": [
+        "/api/v1/file/upload": {
+            "post": {
+                "description": "Upload File",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "file"
+                ],
+                "summary": "Upload File",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "File",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Knowledge Base ID",
+                        "name": "kb_id",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ObjectUploadResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/file/upload/anydoc": {
+            "post": {
+                "description": "Upload Anydoc File",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "file"
+                ],
+                "summary": "Upload Anydoc File",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "File",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File Path",
+                        "name": "path",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.AnydocUploadResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/kb_role": {
+            "put": {
+                "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "问答来源",
+                "description": "修改后立即对分配了该角色的成员生效",
                 "consumes": [
                     "application/json"
                 ],
@@ -2236,32 +2029,19 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "stat"
+                    "KBRole"
                 ],
-                "summary": "问答来源",
+                "summary": "修改自定义角色",
+                "operationId": "v1-UpdateKBRole",
                 "parameters": [
                     {
-                        "enum": [
-                            1,
-                            7,
-                            30,
-                            90
-                        ],
-                        "type": "integer",
-                        "x-enum-varnames": [
-                            "StatDay1",
-                            "StatDay7",
-                            "StatDay30",
-                            "StatDay90"
-                        ],
-                        "name": "day",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "kb_id",
-                        "in": "query",
-                        "required": true
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.KBRoleUpdateReq"
+                        }
                     }
                 ],
                 "responses": {
@@ -2270,16 +2050,13 @@ const docTemplate = `{
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/domain.Response"
+                                    "$ref": "#/definitions/domain.PWResponse"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/domain.ConversationDistribution"
-                                            }
+                                            "$ref": "#/definitions/domain.KBRole"
                                         }
                                     }
                                 }
@@ -2287,16 +2064,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/stat/count": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "全局统计",
+                "description": "自定义角色在所有知识库中可用，通过邀请或修改成员分配",
                 "consumes": [
                     "application/json"
                 ],
@@ -2304,32 +2079,19 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "stat"
+                    "KBRole"
                 ],
-                "summary": "全局统计",
+                "summary": "创建自定义角色",
+                "operationId": "v1-CreateKBRole",
                 "parameters": [
                     {
-                        "enum": [
-                            1,
-                            7,
-                            30,
-                            90
-                        ],
-                        "type": "integer",
-                        "x-enum-varnames": [
-                            "StatDay1",
-                            "StatDay7",
-                            "StatDay30",
-                            "StatDay90"
-                        ],
-                        "name": "day",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "kb_id",
-                        "in": "query",
-                        "required": true
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.KBRoleCreateReq"
+                        }
                     }
                 ],
                 "responses": {
@@ -2344,7 +2106,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/v1.StatCountResp"
+                                            "$ref": "#/definitions/domain.KBRole"
                                         }
                                     }
                                 }
@@ -2352,16 +2114,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/stat/geo_count": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "用户地理分布",
+                "description": "仍分配给知识库成员的角色不能删除",
                 "consumes": [
                     "application/json"
                 ],
@@ -2369,30 +2129,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "stat"
+                    "KBRole"
                 ],
-                "summary": "用户地理分布",
+                "summary": "删除自定义角色",
+                "operationId": "v1-DeleteKBRole",
                 "parameters": [
-                    {
-                        "enum": [
-                            1,
-                            7,
-                            30,
-                            90
-                        ],
-                        "type": "integer",
-                        "x-enum-varnames": [
-                            "StatDay1",
-                            "StatDay7",
-                            "StatDay30",
-                            "StatDay90"
-                        ],
-                        "name": "day",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "name": "kb_id",
+                        "name": "id",
                         "in": "query",
                         "required": true
                     }
@@ -2407,14 +2151,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/stat/hot_pages": {
+        "/api/v1/kb_role/list": {
             "get": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "热门文档",
+                "description": "返回全部自定义角色，以及可选的能力和内置权限包含的能力",
                 "consumes": [
                     "application/json"
                 ],
@@ -2422,50 +2166,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "stat"
-                ],
-                "summary": "热门文档",
-                "parameters": [
-                    {
-                        "enum": [
-                            1,
-                            7,
-                            30,
-                            90
-                        ],
-                        "type": "integer",
-                        "x-enum-varnames": [
-                            "StatDay1",
-                            "StatDay7",
-                            "StatDay30",
-                            "StatDay90"
-                        ],
-                        "name": "day",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "kb_id",
-                        "in": "query",
-                        "required": true
-                    }
+                    "KBRole"
                 ],
+                "summary": "自定义角色列表",
+                "operationId": "v1-ListKBRoles",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/domain.Response"
+                                    "$ref": "#/definitions/domain.PWResponse"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/domain.HotPage"
-                                            }
+                                            "$ref": "#/definitions/v1.KBRoleListResp"
                                         }
                                     }
                                 }
@@ -2475,14 +2192,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/stat/instant_count": {
-            "get": {
-                "security": [
-                    {
-                        "bearerAuth": []
-                    }
-                ],
-                "description": "GetInstantCount",
+        "/api/v1/knowledge_base": {
+            "post": {
+                "description": "CreateKnowledgeBase",
                 "consumes": [
                     "application/json"
                 ],
@@ -2490,50 +2202,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "stat"
+                    "knowledge_base"
                 ],
-                "summary": "GetInstantCount",
+                "summary": "CreateKnowledgeBase",
                 "parameters": [
                     {
-                        "type": "string",
-                        "name": "kb_id",
-                        "in": "query",
-                        "required": true
+                        "description": "CreateKnowledgeBase Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.CreateKnowledgeBaseReq"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/domain.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/domain.InstantCountResp"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/domain.Response"
                         }
                     }
                 }
             }
         },
-        "/api/v1/stat/instant_pages": {
+        "/api/v1/knowledge_base/detail": {
             "get": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "GetInstantPages",
+                "description": "GetKnowledgeBaseDetail",
                 "consumes": [
                     "application/json"
                 ],
@@ -2541,13 +2241,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "stat"
+                    "knowledge_base"
                 ],
-                "summary": "GetInstantPages",
+                "summary": "GetKnowledgeBaseDetail",
                 "parameters": [
                     {
                         "type": "string",
-                        "name": "kb_id",
+                        "description": "Knowledge Base ID",
+                        "name": "id",
                         "in": "query",
                         "required": true
                     }
@@ -2558,16 +2259,13 @@ const docTemplate = `{
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/domain.Response"
+                                    "$ref": "#/definitions/domain.PWResponse"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/domain.InstantPageResp"
-                                            }
+                                            "$ref": "#/definitions/domain.KnowledgeBaseDetail"
                                         }
                                     }
                                 }
@@ -2575,16 +2273,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/stat/referer_hosts": {
-            "get": {
-                "security": [
-                    {
-                        "bearerAuth": []
-                    }
-                ],
-                "description": "来源域名",
+            },
+            "put": {
+                "description": "UpdateKnowledgeBase",
                 "consumes": [
                     "application/json"
                 ],
@@ -2592,82 +2283,63 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "stat"
+                    "knowledge_base"
                 ],
-                "summary": "来源域名",
+                "summary": "UpdateKnowledgeBase",
                 "parameters": [
                     {
-                        "enum": [
-                            1,
-                            7,
-                            30,
-                            90
-                        ],
-                        "type": "integer",
-                        "x-enum-varnames": [
-                            "StatDay1",
-                            "StatDay7",
-                            "StatDay30",
-                            "StatDay90"
-                        ],
-                        "name": "day",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "kb_id",
-                        "in": "query",
-                        "required": true
+                        "description": "UpdateKnowledgeBase Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.UpdateKnowledgeBaseReq"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/domain.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/domain.HotRefererHost"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/domain.Response"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/user": {
-            "get": {
-                "description": "GetUser",
+            },
+            "delete": {
+                "description": "DeleteKnowledgeBase",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "user"
+                    "knowledge_base"
+                ],
+                "summary": "DeleteKnowledgeBase",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Knowledge Base ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "GetUser",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.UserInfoResp"
+                            "$ref": "#/definitions/domain.Response"
                         }
                     }
                 }
             }
         },
-        "/api/v1/user/create": {
-            "post": {
-                "description": "CreateUser",
+        "/api/v1/knowledge_base/list": {
+            "get": {
+                "description": "GetKnowledgeBaseList",
                 "consumes": [
                     "application/json"
                 ],
@@ -2675,33 +2347,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
-                ],
-                "summary": "CreateUser",
-                "parameters": [
-                    {
-                        "description": "CreateUser Request",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.CreateUserReq"
-                        }
-                    }
+                    "knowledge_base"
                 ],
+                "summary": "GetKnowledgeBaseList",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/domain.Response"
+                                    "$ref": "#/definitions/domain.PWResponse"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/v1.CreateUserResp"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.KnowledgeBaseListItem"
+                                            }
                                         }
                                     }
                                 }
@@ -2711,9 +2375,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user/delete": {
-            "delete": {
-                "description": "DeleteUser",
+        "/api/v1/knowledge_base/release": {
+            "post": {
+                "description": "CreateKBRelease",
                 "consumes": [
                     "application/json"
                 ],
@@ -2721,15 +2385,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "knowledge_base"
                 ],
-                "summary": "DeleteUser",
+                "summary": "CreateKBRelease",
                 "parameters": [
                     {
-                        "type": "string",
-                        "name": "user_id",
-                        "in": "query",
-                        "required": true
+                        "description": "CreateKBRelease Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.CreateKBReleaseReq"
+                        }
                     }
                 ],
                 "responses": {
@@ -2742,9 +2409,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user/list": {
+        "/api/v1/knowledge_base/release/list": {
             "get": {
-                "description": "ListUsers",
+                "description": "GetKBReleaseList",
                 "consumes": [
                     "application/json"
                 ],
@@ -2752,9 +2419,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "knowledge_base"
+                ],
+                "summary": "GetKBReleaseList",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Knowledge Base ID",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "ListUsers",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -2767,7 +2443,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/v1.UserListResp"
+                                            "$ref": "#/definitions/domain.GetKBReleaseListResp"
                                         }
                                     }
                                 }
@@ -2777,9 +2453,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user/login": {
-            "post": {
-                "description": "Login",
+        "/api/v1/knowledge_base/user/delete": {
+            "delete": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "Remove user from knowledge base",
                 "consumes": [
                     "application/json"
                 ],
@@ -2787,33 +2468,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "knowledge_base"
                 ],
-                "summary": "Login",
+                "summary": "KBUserDelete",
                 "parameters": [
                     {
-                        "description": "Login Request",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.LoginReq"
-                        }
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.LoginResp"
+                            "$ref": "#/definitions/domain.Response"
                         }
                     }
                 }
             }
         },
-        "/api/v1/user/reset_password": {
-            "put": {
-                "description": "ResetPassword",
+        "/api/v1/knowledge_base/user/invite": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "Invite user to knowledge base",
                 "consumes": [
                     "application/json"
                 ],
@@ -2821,17 +2510,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "knowledge_base"
                 ],
-                "summary": "ResetPassword",
+                "summary": "KBUserInvite",
                 "parameters": [
                     {
-                        "description": "ResetPassword Request",
-                        "name": "body",
+                        "description": "Invite User Request",
+                        "name": "param",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.ResetPasswordReq"
+                            "$ref": "#/definitions/v1.KBUserInviteReq"
                         }
                     }
                 ],
@@ -2845,9 +2534,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/share/v1/app/web/info": {
+        "/api/v1/knowledge_base/user/list": {
             "get": {
-                "description": "GetAppInfo",
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "KBUserList",
                 "consumes": [
                     "application/json"
                 ],
@@ -2855,15 +2549,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "share_app"
+                    "knowledge_base"
                 ],
-                "summary": "GetAppInfo",
+                "summary": "KBUserList",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "kb id",
-                        "name": "X-KB-ID",
-                        "in": "header",
+                        "description": "Knowledge Base ID",
+                        "name": "kb_id",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -2873,13 +2567,16 @@ const docTemplate = `{
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/domain.Response"
+                                    "$ref": "#/definitions/domain.PWResponse"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/domain.AppInfoResp"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/v1.KBUserListItemResp"
+                                            }
                                         }
                                     }
                                 }
@@ -2889,26 +2586,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/share/v1/app/wechat/service/answer": {
-            "get": {
-                "description": "GetWechatAnswer",
-                "consumes": [
-                    "application/json"
+        "/api/v1/knowledge_base/user/update": {
+            "patch": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "Update user permission in knowledge base",
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Wechat"
+                    "knowledge_base"
                 ],
-                "summary": "GetWechatAnswer",
+                "summary": "KBUserUpdate",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "conversation id",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "description": "Update User Permission Request",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.KBUserUpdateReq"
+                        }
                     }
                 ],
                 "responses": {
@@ -2921,9 +2625,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/share/v1/app/widget/info": {
-            "get": {
-                "description": "GetWidgetAppInfo",
+        "/api/v1/model": {
+            "put": {
+                "description": "update model",
                 "consumes": [
                     "application/json"
                 ],
@@ -2931,16 +2635,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "share_app"
+                    "model"
                 ],
-                "summary": "GetWidgetAppInfo",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "kb id",
-                        "name": "X-KB-ID",
-                        "in": "header",
-                        "required": true
+                        "description": "update model request",
+                        "name": "model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.UpdateModelReq"
+                        }
                     }
                 ],
                 "responses": {
@@ -2951,11 +2656,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/share/v1/auth/get": {
-            "get": {
-                "description": "AuthGet",
+            },
+            "post": {
+                "description": "create model",
                 "consumes": [
                     "application/json"
                 ],
@@ -2963,44 +2666,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "share_auth"
+                    "model"
                 ],
-                "summary": "AuthGet",
-                "operationId": "v1-AuthGet",
+                "summary": "create model",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "kb_id",
-                        "name": "X-KB-ID",
-                        "in": "header",
-                        "required": true
+                        "description": "create model request",
+                        "name": "model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.CreateModelReq"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/domain.PWResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/github_com_chaitin_panda-wiki_api_share_v1.AuthGetResp"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/domain.Response"
                         }
                     }
                 }
             }
         },
-        "/share/v1/auth/github": {
+        "/api/v1/model/check": {
             "post": {
-                "description": "GitHub登录",
+                "description": "check model",
                 "consumes": [
                     "application/json"
                 ],
@@ -3008,25 +2700,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ShareAuth"
+                    "model"
                 ],
-                "summary": "GitHub登录",
-                "operationId": "v1-AuthGitHub",
+                "summary": "check model",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "kb id",
-                        "name": "X-KB-ID",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "description": "para",
-                        "name": "param",
+                        "description": "check model request",
+                        "name": "model",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.AuthGitHubReq"
+                            "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.CheckModelReq"
                         }
                     }
                 ],
@@ -3036,13 +2720,13 @@ const docTemplate = `{
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/domain.PWResponse"
+                                    "$ref": "#/definitions/domain.Response"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/v1.AuthGitHubResp"
+                                            "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.CheckModelResp"
                                         }
                                     }
                                 }
@@ -3052,9 +2736,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/share/v1/auth/login/simple": {
-            "post": {
-                "description": "AuthLoginSimple",
+        "/api/v1/model/list": {
+            "get": {
+                "description": "get model list",
                 "consumes": [
                     "application/json"
                 ],
@@ -3062,41 +2746,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "share_auth"
-                ],
-                "summary": "AuthLoginSimple",
-                "operationId": "v1-AuthLoginSimple",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "kb_id",
-                        "name": "X-KB-ID",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "description": "para",
-                        "name": "param",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.AuthLoginSimpleReq"
-                        }
-                    }
+                    "model"
                 ],
+                "summary": "get model list",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.ModelListItem"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/share/v1/captcha/challenge": {
+        "/api/v1/model/provider/supported": {
             "post": {
-                "description": "CreateCaptcha",
+                "description": "get provider supported model list",
                 "consumes": [
                     "application/json"
                 ],
@@ -3104,31 +2781,50 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "share_captcha"
+                    "model"
                 ],
-                "summary": "CreateCaptcha",
+                "summary": "get provider supported model list",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "kb id",
-                        "name": "X-KB-ID",
-                        "in": "header",
-                        "required": true
+                        "description": "get supported model list request",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.GetProviderModelListReq"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/gocap.ChallengeData"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.GetProviderModelListResp"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/share/v1/captcha/redeem": {
+        "/api/v1/node": {
             "post": {
-                "description": "RedeemCaptcha",
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "Create Node",
                 "consumes": [
                     "application/json"
                 ],
@@ -3136,24 +2832,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "share_captcha"
+                    "node"
                 ],
-                "summary": "RedeemCaptcha",
+                "summary": "Create Node",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "kb id",
-                        "name": "X-KB-ID",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "description": "request",
+                        "description": "Node",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/consts.RedeemCaptchaReq"
+                            "$ref": "#/definitions/domain.CreateNodeReq"
                         }
                     }
                 ],
@@ -3161,15 +2850,35 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/gocap.VerificationResult"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object",
+                                            "additionalProperties": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/share/v1/chat/completions": {
+        "/api/v1/node/access_request/decide": {
             "post": {
-                "description": "OpenAI API compatible chat completions endpoint",
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "批准时将读者加入用户组，或单独授权该文档的访问；可问答权限只能通过用户组授予。处理结果通过邮件通知读者",
                 "consumes": [
                     "application/json"
                 ],
@@ -3177,24 +2886,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "share_chat"
+                    "NodeAccessRequest"
                 ],
-                "summary": "ChatCompletions",
+                "summary": "处理读者访问申请",
+                "operationId": "v1-DecideAccessRequest",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Knowledge Base ID",
-                        "name": "X-KB-ID",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "description": "OpenAI API request",
-                        "name": "request",
+                        "description": "para",
+                        "name": "param",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.OpenAICompletionsRequest"
+                            "$ref": "#/definitions/v1.AccessRequestDecideReq"
                         }
                     }
                 ],
@@ -3202,55 +2905,32 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.OpenAICompletionsResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/domain.OpenAIErrorResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.NodeAccessRequest"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/share/v1/chat/feedback": {
-            "post": {
-                "description": "Process user feedback for chat conversations",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "share_chat"
-                ],
-                "summary": "Handle chat feedback",
-                "parameters": [
+        "/api/v1/node/access_request/grant": {
+            "delete": {
+                "security": [
                     {
-                        "description": "feedback request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.FeedbackRequest"
-                        }
+                        "bearerAuth": []
                     }
                 ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/domain.Response"
-                        }
-                    }
-                }
-            }
-        },
-        "/share/v1/chat/message": {
-            "post": {
-                "description": "ChatMessage",
+                "description": "撤销后读者按用户组权限访问该文档",
                 "consumes": [
                     "application/json"
                 ],
@@ -3258,25 +2938,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "share_chat"
+                    "NodeAccessRequest"
                 ],
-                "summary": "ChatMessage",
+                "summary": "撤销文档单独授权",
+                "operationId": "v1-DeleteAccessGrant",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "app type",
-                        "name": "app_type",
+                        "type": "integer",
+                        "name": "id",
                         "in": "query",
                         "required": true
                     },
                     {
-                        "description": "request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.ChatRequest"
-                        }
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -3289,9 +2966,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/share/v1/chat/search": {
-            "post": {
-                "description": "ChatSearch",
+        "/api/v1/node/access_request/grant/list": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "通过访问申请单独授权给读者的文档",
                 "consumes": [
                     "application/json"
                 ],
@@ -3299,18 +2981,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "share_chat_search"
+                    "NodeAccessRequest"
                 ],
-                "summary": "ChatSearch",
+                "summary": "文档单独授权列表",
+                "operationId": "v1-ListAccessGrants",
                 "parameters": [
                     {
-                        "description": "request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.ChatSearchReq"
-                        }
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "node_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -3319,13 +3004,16 @@ const docTemplate = `{
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/domain.Response"
+                                    "$ref": "#/definitions/domain.PWResponse"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/domain.ChatSearchResp"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/v1.AccessGrantItem"
+                                            }
                                         }
                                     }
                                 }
@@ -3335,9 +3023,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/share/v1/chat/widget": {
-            "post": {
-                "description": "ChatWidget",
+        "/api/v1/node/access_request/list": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "待处理的申请排在前面",
                 "consumes": [
                     "application/json"
                 ],
@@ -3345,40 +3038,87 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "share_chat"
+                    "NodeAccessRequest"
                 ],
-                "summary": "ChatWidget",
+                "summary": "读者访问申请列表",
+                "operationId": "v1-ListAccessRequests",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "app type",
-                        "name": "app_type",
+                        "name": "kb_id",
                         "in": "query",
                         "required": true
                     },
                     {
-                        "description": "request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.ChatRequest"
-                        }
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "per_page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected"
+                        ],
+                        "type": "string",
+                        "x-enum-comments": {
+                            "NodeAccessRequestStatusApproved": "已批准",
+                            "NodeAccessRequestStatusPending": "待处理",
+                            "NodeAccessRequestStatusRejected": "已拒绝"
+                        },
+                        "x-enum-descriptions": [
+                            "待处理",
+                            "已批准",
+                            "已拒绝"
+                        ],
+                        "x-enum-varnames": [
+                            "NodeAccessRequestStatusPending",
+                            "NodeAccessRequestStatusApproved",
+                            "NodeAccessRequestStatusRejected"
+                        ],
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.AccessRequestListResp"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/share/v1/comment": {
+        "/api/v1/node/action": {
             "post": {
-                "description": "CreateComment",
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "Node Action",
                 "consumes": [
                     "application/json"
                 ],
@@ -3386,23 +3126,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "share_comment"
+                    "node"
                 ],
-                "summary": "CreateComment",
+                "summary": "Node Action",
                 "parameters": [
                     {
-                        "description": "Comment",
-                        "name": "comment",
+                        "description": "Action",
+                        "name": "action",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.CommentReq"
+                            "$ref": "#/definitions/domain.NodeActionReq"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "CommentID",
+                        "description": "OK",
                         "schema": {
                             "allOf": [
                                 {
@@ -3412,7 +3152,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "string"
+                                            "type": "object",
+                                            "additionalProperties": {
+                                                "type": "string"
+                                            }
                                         }
                                     }
                                 }
@@ -3422,9 +3165,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/share/v1/comment/list": {
+        "/api/v1/node/backlinks": {
             "get": {
-                "description": "GetCommentList",
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "获取链接到该文档的其他文档",
                 "consumes": [
                     "application/json"
                 ],
@@ -3432,21 +3180,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "share_comment"
+                    "NodeLink"
                 ],
-                "summary": "GetCommentList",
+                "summary": "获取文档反向链接",
+                "operationId": "v1-GetBacklinks",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "nodeID",
                         "name": "id",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "CommentList",
+                        "description": "OK",
                         "schema": {
                             "allOf": [
                                 {
@@ -3456,7 +3210,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/share.ShareCommentLists"
+                                            "$ref": "#/definitions/v1.NodeBacklinksResp"
                                         }
                                     }
                                 }
@@ -3466,68 +3220,53 @@ const docTemplate = `{
                 }
             }
         },
-        "/share/v1/common/file/upload": {
+        "/api/v1/node/batch_move": {
             "post": {
-                "description": "前台用户上传文件,目前只支持图片文件上传",
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "Batch Move Node",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "ShareFile"
+                    "node"
                 ],
-                "summary": "文件上传",
-                "operationId": "share-FileUpload",
+                "summary": "Batch Move Node",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "kb id",
-                        "name": "X-KB-ID",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "File",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "captcha_token",
-                        "name": "captcha_token",
-                        "in": "formData",
-                        "required": true
+                        "description": "Batch Move Node",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.BatchMoveReq"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/domain.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/v1.FileUploadResp"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/domain.Response"
                         }
                     }
                 }
             }
         },
-        "/share/v1/conversation/detail": {
+        "/api/v1/node/detail": {
             "get": {
-                "description": "GetConversationDetail",
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "Get Node Detail",
                 "consumes": [
                     "application/json"
                 ],
@@ -3535,23 +3274,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "share_conversation"
+                    "node"
                 ],
-                "summary": "GetConversationDetail",
+                "summary": "Get Node Detail",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "kb id",
-                        "name": "X-KB-ID",
-                        "in": "header",
-                        "required": true
+                        "name": "format",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "conversation id",
                         "name": "id",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -3566,7 +3308,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/domain.ShareConversationDetailResp"
+                                            "$ref": "#/definitions/v1.NodeDetailResp"
                                         }
                                     }
                                 }
@@ -3574,11 +3316,53 @@ const docTemplate = `{
                         }
                     }
                 }
+            },
+            "put": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "Update Node Detail",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "node"
+                ],
+                "summary": "Update Node Detail",
+                "parameters": [
+                    {
+                        "description": "Node",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.UpdateNodeReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
             }
         },
-        "/share/v1/node/detail": {
+        "/api/v1/node/links/broken": {
             "get": {
-                "description": "GetNodeDetail",
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "内部链接指向已删除或未发布的文档, 外部链接无法访问",
                 "consumes": [
                     "application/json"
                 ],
@@ -3586,45 +3370,75 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "share_node"
+                    "NodeLink"
                 ],
-                "summary": "GetNodeDetail",
+                "summary": "获取失效链接列表",
+                "operationId": "v1-GetBrokenLinks",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "kb id",
-                        "name": "X-KB-ID",
-                        "in": "header",
+                        "name": "kb_id",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "node id",
-                        "name": "id",
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
                         "in": "query",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "format",
-                        "name": "format",
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "per_page",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "internal",
+                            "external"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "NodeLinkTypeInternal",
+                            "NodeLinkTypeExternal"
+                        ],
+                        "name": "type",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.BrokenLinkListResp"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/share/v1/node/list": {
-            "get": {
-                "description": "GetNodeList",
+        "/api/v1/node/links/check": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "后台重建链接索引并检查外部链接",
                 "consumes": [
                     "application/json"
                 ],
@@ -3632,16 +3446,19 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "share_node"
+                    "NodeLink"
                 ],
-                "summary": "GetNodeList",
+                "summary": "检查知识库链接",
+                "operationId": "v1-CheckLinks",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "kb id",
-                        "name": "X-KB-ID",
-                        "in": "header",
-                        "required": true
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CheckLinksReq"
+                        }
                     }
                 ],
                 "responses": {
@@ -3654,9 +3471,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/share/v1/openapi/github/callback": {
+        "/api/v1/node/list": {
             "get": {
-                "description": "GitHub回调",
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "Get Node List",
                 "consumes": [
                     "application/json"
                 ],
@@ -3664,19 +3486,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ShareOpenapi"
+                    "node"
                 ],
-                "summary": "GitHub回调",
-                "operationId": "v1-GitHubCallback",
+                "summary": "Get Node List",
                 "parameters": [
                     {
-                        "type": "string",
-                        "name": "code",
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "key=value",
+                        "name": "fields",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "name": "state",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "name": "tags",
                         "in": "query"
                     }
                 ],
@@ -3692,7 +3533,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_chaitin_panda-wiki_api_share_v1.GitHubCallbackResp"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.NodeListItemResp"
+                                            }
                                         }
                                     }
                                 }
@@ -3702,9 +3546,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/share/v1/openapi/lark/bot/{kb_id}": {
-            "post": {
-                "description": "Lark机器人请求",
+        "/api/v1/node/meta/schema": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "获取知识库的标签词表及自定义字段定义",
                 "consumes": [
                     "application/json"
                 ],
@@ -3712,16 +3561,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ShareOpenapi"
+                    "node"
                 ],
-                "summary": "Lark机器人请求",
-                "operationId": "v1-LarkBot",
+                "summary": "获取文档标签及自定义字段定义",
+                "operationId": "v1-GetNodeMetaSchema",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "知识库ID",
                         "name": "kb_id",
-                        "in": "path",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -3729,15 +3577,30 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.PWResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.NodeMetaSchemaResp"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
-            }
-        },
-        "/share/v1/stat/page": {
-            "post": {
-                "description": "RecordPage",
+            },
+            "put": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "更新知识库的标签词表及自定义字段定义, 已有文档上的取值不受影响",
                 "consumes": [
                     "application/json"
                 ],
@@ -3745,17 +3608,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "share_stat"
+                    "node"
                 ],
-                "summary": "RecordPage",
+                "summary": "更新文档标签及自定义字段定义",
+                "operationId": "v1-UpdateNodeMetaSchema",
                 "parameters": [
                     {
-                        "description": "request",
-                        "name": "request",
+                        "description": "para",
+                        "name": "param",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.StatPageReq"
+                            "$ref": "#/definitions/v1.NodeMetaSchemaUpdateReq"
                         }
                     }
                 ],
@@ -3768,1223 +3632,9024 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "anydoc.Child": {
-            "type": "object",
-            "properties": {
-                "children": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/anydoc.Child"
-                    }
-                },
-                "value": {
-                    "$ref": "#/definitions/anydoc.Value"
-                }
-            }
         },
-        "anydoc.Value": {
-            "type": "object",
-            "properties": {
-                "file": {
-                    "type": "boolean"
-                },
-                "file_type": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
+        "/api/v1/node/move": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "Move Node",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "node"
+                ],
+                "summary": "Move Node",
+                "parameters": [
+                    {
+                        "description": "Move Node",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.MoveNodeReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/node/permission": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "文档授权信息获取",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NodePermission"
+                ],
+                "summary": "文档授权信息获取",
+                "operationId": "v1-NodePermission",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.NodePermissionResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/node/permission/edit": {
+            "patch": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "文档授权信息更新",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NodePermission"
+                ],
+                "summary": "文档授权信息更新",
+                "operationId": "v1-NodePermissionEdit",
+                "parameters": [
+                    {
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.NodePermissionEditReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.NodePermissionEditResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/node/recommend_nodes": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "Recommend Nodes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "node"
+                ],
+                "summary": "Recommend Nodes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "name": "node_ids",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.RecommendNodeListResp"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/node/review/decide": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "审核通过或要求修改",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NodeReview"
+                ],
+                "summary": "审核文档",
+                "operationId": "v1-DecideReview",
+                "parameters": [
+                    {
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.NodeReviewDecideReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/node/review/detail": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "获取文档审核状态及审核记录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NodeReview"
+                ],
+                "summary": "获取文档审核状态及审核记录",
+                "operationId": "v1-GetReviewDetail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.NodeReviewDetailResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/node/review/policy": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "获取发布审核策略",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NodeReview"
+                ],
+                "summary": "获取发布审核策略",
+                "operationId": "v1-GetReviewPolicy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.NodeReviewPolicyResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "更新发布审核策略",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NodeReview"
+                ],
+                "summary": "更新发布审核策略",
+                "operationId": "v1-UpdateReviewPolicy",
+                "parameters": [
+                    {
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.NodeReviewPolicyUpdateReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/node/review/queue": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "获取审核队列, 默认返回待审核的文档",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NodeReview"
+                ],
+                "summary": "获取审核队列",
+                "operationId": "v1-GetReviewQueue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "per_page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "",
+                            "pending",
+                            "approved",
+                            "changes_requested"
+                        ],
+                        "type": "string",
+                        "x-enum-comments": {
+                            "NodeReviewStatusApproved": "审核通过",
+                            "NodeReviewStatusChangesRequested": "需要修改",
+                            "NodeReviewStatusNone": "未提交审核",
+                            "NodeReviewStatusPending": "待审核"
+                        },
+                        "x-enum-descriptions": [
+                            "未提交审核",
+                            "待审核",
+                            "审核通过",
+                            "需要修改"
+                        ],
+                        "x-enum-varnames": [
+                            "NodeReviewStatusNone",
+                            "NodeReviewStatusPending",
+                            "NodeReviewStatusApproved",
+                            "NodeReviewStatusChangesRequested"
+                        ],
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.NodeReviewQueueResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/node/review/submit": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "提交文档审核",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NodeReview"
+                ],
+                "summary": "提交文档审核",
+                "operationId": "v1-SubmitReview",
+                "parameters": [
+                    {
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.NodeReviewSubmitReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/node/share_link": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "分享文档或文件夹，持有链接即可访问，不受文档访问权限限制；draft 为 true 时预览未发布的内容",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NodeShareLink"
+                ],
+                "summary": "创建分享链接",
+                "operationId": "v1-CreateShareLink",
+                "parameters": [
+                    {
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.ShareLinkCreateReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.ShareLinkItem"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "撤销后链接立即失效，访问记录保留",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NodeShareLink"
+                ],
+                "summary": "撤销分享链接",
+                "operationId": "v1-RevokeShareLink",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/node/share_link/list": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "知识库或指定文档的分享链接，包含已撤销和已过期的链接",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NodeShareLink"
+                ],
+                "summary": "分享链接列表",
+                "operationId": "v1-ListShareLinks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "node_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/v1.ShareLinkItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/node/stale": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "超过复查周期未更新, 或引用该文档的回答最近被点踩的文档",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NodeStale"
+                ],
+                "summary": "获取可能过时的文档列表",
+                "operationId": "v1-GetStaleNodeList",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "owner_id",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "per_page",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.StaleNodeListResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/node/stale/reviewed": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "重置文档的复查周期, 不修改文档内容",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NodeStale"
+                ],
+                "summary": "标记文档已复查",
+                "operationId": "v1-MarkNodeReviewed",
+                "parameters": [
+                    {
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.MarkNodeReviewedReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/node/summary": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "Summary Node",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "node"
+                ],
+                "summary": "Summary Node",
+                "parameters": [
+                    {
+                        "description": "Summary Node",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.NodeSummaryReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/node/template": {
+            "put": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "更新文档模板",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NodeTemplate"
+                ],
+                "summary": "更新文档模板",
+                "operationId": "v1-UpdateTemplate",
+                "parameters": [
+                    {
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.NodeTemplateUpdateReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "创建文档模板, 标题/内容/自定义字段中可使用占位符 {{date}} {{datetime}} {{author}} {{parent}} {{kb}} {{title}}",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NodeTemplate"
+                ],
+                "summary": "创建文档模板",
+                "operationId": "v1-CreateTemplate",
+                "parameters": [
+                    {
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.NodeTemplateCreateReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.NodeTemplateCreateResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "删除文档模板, 已由模板创建的文档不受影响",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NodeTemplate"
+                ],
+                "summary": "删除文档模板",
+                "operationId": "v1-DeleteTemplate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/node/template/detail": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "获取文档模板详情",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NodeTemplate"
+                ],
+                "summary": "获取文档模板详情",
+                "operationId": "v1-GetTemplateDetail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.NodeTemplateDetailResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/node/template/from_node": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "将已有文档的标题、内容、标签、自定义字段及权限保存为模板",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NodeTemplate"
+                ],
+                "summary": "将文档保存为模板",
+                "operationId": "v1-CreateTemplateFromNode",
+                "parameters": [
+                    {
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.NodeTemplateFromNodeReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.NodeTemplateCreateResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/node/template/list": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "获取文档模板列表",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NodeTemplate"
+                ],
+                "summary": "获取文档模板列表",
+                "operationId": "v1-GetTemplateList",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/v1.NodeTemplateListItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notify/settings": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "获取邮件及群机器人通知设置",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notify"
+                ],
+                "summary": "获取通知设置",
+                "operationId": "v1-GetNotifySettings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.GetNotifySettingsResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "更新邮件及群机器人通知设置",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notify"
+                ],
+                "summary": "更新通知设置",
+                "operationId": "v1-UpdateNotifySettings",
+                "parameters": [
+                    {
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateNotifySettingsReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notify/test": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "向当前用户及所有群机器人发送测试通知",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notify"
+                ],
+                "summary": "发送测试通知",
+                "operationId": "v1-TestNotify",
+                "parameters": [
+                    {
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.TestNotifyReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stat/browsers": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "客户端统计",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stat"
+                ],
+                "summary": "客户端统计",
+                "parameters": [
+                    {
+                        "enum": [
+                            1,
+                            7,
+                            30,
+                            90
+                        ],
+                        "type": "integer",
+                        "x-enum-varnames": [
+                            "StatDay1",
+                            "StatDay7",
+                            "StatDay30",
+                            "StatDay90"
+                        ],
+                        "name": "day",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.HotBrowser"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stat/conversation_distribution": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "问答来源",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stat"
+                ],
+                "summary": "问答来源",
+                "parameters": [
+                    {
+                        "enum": [
+                            1,
+                            7,
+                            30,
+                            90
+                        ],
+                        "type": "integer",
+                        "x-enum-varnames": [
+                            "StatDay1",
+                            "StatDay7",
+                            "StatDay30",
+                            "StatDay90"
+                        ],
+                        "name": "day",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.ConversationDistribution"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stat/count": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "全局统计",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stat"
+                ],
+                "summary": "全局统计",
+                "parameters": [
+                    {
+                        "enum": [
+                            1,
+                            7,
+                            30,
+                            90
+                        ],
+                        "type": "integer",
+                        "x-enum-varnames": [
+                            "StatDay1",
+                            "StatDay7",
+                            "StatDay30",
+                            "StatDay90"
+                        ],
+                        "name": "day",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.StatCountResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stat/geo_count": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "用户地理分布",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stat"
+                ],
+                "summary": "用户地理分布",
+                "parameters": [
+                    {
+                        "enum": [
+                            1,
+                            7,
+                            30,
+                            90
+                        ],
+                        "type": "integer",
+                        "x-enum-varnames": [
+                            "StatDay1",
+                            "StatDay7",
+                            "StatDay30",
+                            "StatDay90"
+                        ],
+                        "name": "day",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stat/hot_pages": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "热门文档",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stat"
+                ],
+                "summary": "热门文档",
+                "parameters": [
+                    {
+                        "enum": [
+                            1,
+                            7,
+                            30,
+                            90
+                        ],
+                        "type": "integer",
+                        "x-enum-varnames": [
+                            "StatDay1",
+                            "StatDay7",
+                            "StatDay30",
+                            "StatDay90"
+                        ],
+                        "name": "day",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.HotPage"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stat/instant_count": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "GetInstantCount",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stat"
+                ],
+                "summary": "GetInstantCount",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.InstantCountResp"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stat/instant_pages": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "GetInstantPages",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stat"
+                ],
+                "summary": "GetInstantPages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.InstantPageResp"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stat/referer_hosts": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "来源域名",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stat"
+                ],
+                "summary": "来源域名",
+                "parameters": [
+                    {
+                        "enum": [
+                            1,
+                            7,
+                            30,
+                            90
+                        ],
+                        "type": "integer",
+                        "x-enum-varnames": [
+                            "StatDay1",
+                            "StatDay7",
+                            "StatDay30",
+                            "StatDay90"
+                        ],
+                        "name": "day",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "kb_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.HotRefererHost"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user": {
+            "get": {
+                "description": "GetUser",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "GetUser",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.UserInfoResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/2fa/disable": {
+            "post": {
+                "description": "Disable two-factor authentication with a code or a recovery code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "DisableTwoFactor",
+                "parameters": [
+                    {
+                        "description": "DisableTwoFactor Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.TwoFactorCodeReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/2fa/enable": {
+            "post": {
+                "description": "Enable two-factor authentication with a code from the authenticator, returns recovery codes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "EnableTwoFactor",
+                "parameters": [
+                    {
+                        "description": "EnableTwoFactor Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.TwoFactorCodeReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.TwoFactorRecoveryCodesResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/2fa/recovery_codes": {
+            "post": {
+                "description": "Replace all recovery codes of the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "RegenerateRecoveryCodes",
+                "parameters": [
+                    {
+                        "description": "RegenerateRecoveryCodes Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.TwoFactorCodeReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.TwoFactorRecoveryCodesResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/2fa/reset": {
+            "post": {
+                "description": "Turn off two-factor authentication for a user who lost the authenticator",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "ResetTwoFactor",
+                "parameters": [
+                    {
+                        "description": "ResetTwoFactor Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.TwoFactorResetReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/2fa/setup": {
+            "post": {
+                "description": "Generate a TOTP secret for the current user, confirmed by EnableTwoFactor",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "SetupTwoFactor",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.TwoFactorSetupResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/create": {
+            "post": {
+                "description": "CreateUser",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "CreateUser",
+                "parameters": [
+                    {
+                        "description": "CreateUser Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateUserReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.CreateUserResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/delete": {
+            "delete": {
+                "description": "DeleteUser",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "DeleteUser",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/email": {
+            "put": {
+                "description": "UpdateUserEmail, used for notifications",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "UpdateUserEmail",
+                "parameters": [
+                    {
+                        "description": "UpdateUserEmail Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateUserEmailReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/list": {
+            "get": {
+                "description": "ListUsers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "ListUsers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.UserListResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/login": {
+            "post": {
+                "description": "Login",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Login",
+                "parameters": [
+                    {
+                        "description": "Login Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.LoginReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.LoginResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/login/2fa": {
+            "post": {
+                "description": "Finish login with a TOTP code or a recovery code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "LoginTwoFactor",
+                "parameters": [
+                    {
+                        "description": "LoginTwoFactor Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.LoginTwoFactorReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.LoginTwoFactorResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/login/2fa/setup": {
+            "post": {
+                "description": "Bind an authenticator during login when the security policy requires two-factor authentication",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "LoginTwoFactorSetup",
+                "parameters": [
+                    {
+                        "description": "LoginTwoFactorSetup Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.LoginTwoFactorSetupReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.TwoFactorSetupResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/reset_password": {
+            "put": {
+                "description": "ResetPassword",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "ResetPassword",
+                "parameters": [
+                    {
+                        "description": "ResetPassword Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.ResetPasswordReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/security_policy": {
+            "get": {
+                "description": "GetLoginSecurityPolicy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "GetLoginSecurityPolicy",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.LoginSecurityPolicy"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "SetLoginSecurityPolicy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "SetLoginSecurityPolicy",
+                "parameters": [
+                    {
+                        "description": "SetLoginSecurityPolicy Request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.SetLoginSecurityPolicyReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/session": {
+            "delete": {
+                "description": "Revoke one login session of the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "RevokeSession",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/sessions": {
+            "get": {
+                "description": "List login sessions of the current user, admins can list sessions of any user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "ListSessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "为空时查询当前用户，查询其他用户需要管理员",
+                        "name": "userID",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.UserSessionListResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Revoke all login sessions of a user, the current session is kept when revoking your own",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "RevokeUserSessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.RevokeUserSessionsResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/scim/v2/Groups": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "用户组列表，filter 仅支持 displayName、externalId、id 的 eq",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "用户组列表",
+                "operationId": "scim-ListGroups",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "filter",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "start index",
+                        "name": "startIndex",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "count",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "excluded attributes",
+                        "name": "excludedAttributes",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/scim.ListResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "创建用户组，上级用户组通过扩展属性 parent 指定",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "创建用户组",
+                "operationId": "scim-CreateGroup",
+                "parameters": [
+                    {
+                        "description": "group",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scim.Group"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/scim.Group"
+                        }
+                    }
+                }
+            }
+        },
+        "/scim/v2/Groups/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "用户组详情",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "用户组详情",
+                "operationId": "scim-GetGroup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/scim.Group"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "更新用户组",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "更新用户组",
+                "operationId": "scim-ReplaceGroup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "group",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scim.Group"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/scim.Group"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "删除用户组，下级用户组成为顶级用户组，相关文档的可问答权限随之更新",
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "删除用户组",
+                "operationId": "scim-DeleteGroup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "修改用户组名称、上级及成员",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "修改用户组",
+                "operationId": "scim-PatchGroup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "patch",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scim.PatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/scim.Group"
+                        }
+                    }
+                }
+            }
+        },
+        "/scim/v2/ServiceProviderConfig": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "SCIM 服务能力",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "SCIM 服务能力",
+                "operationId": "scim-ServiceProviderConfig",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/scim.ServiceProviderConfig"
+                        }
+                    }
+                }
+            }
+        },
+        "/scim/v2/Users": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "读者列表，filter 仅支持 userName、externalId、id 的 eq",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "读者列表",
+                "operationId": "scim-ListUsers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "filter",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "start index",
+                        "name": "startIndex",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "count",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/scim.ListResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "开通读者，userName 需与读者登录时的用户标识一致",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "开通读者",
+                "operationId": "scim-CreateUser",
+                "parameters": [
+                    {
+                        "description": "user",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scim.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/scim.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/scim/v2/Users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "读者详情",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "读者详情",
+                "operationId": "scim-GetUser",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/scim.User"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "更新读者，active 为 false 时停用并使已有会话失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "更新读者",
+                "operationId": "scim-ReplaceUser",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "user",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scim.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/scim.User"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "删除读者并移出所有用户组，已有会话立即失效",
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "删除读者",
+                "operationId": "scim-DeleteUser",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "修改读者，active 为 false 时停用并使已有会话失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "修改读者",
+                "operationId": "scim-PatchUser",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "patch",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scim.PatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/scim.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/access_request": {
+            "post": {
+                "description": "已登录的读者申请部分开放文档的访问或问答权限，提交后通知知识库管理者",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareAccessRequest"
+                ],
+                "summary": "申请文档权限",
+                "operationId": "share-CreateAccessRequest",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.AccessRequestCreateReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_chaitin_panda-wiki_api_share_v1.AccessRequestItem"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/access_request/list": {
+            "get": {
+                "description": "当前读者在知识库内最近的申请及处理结果",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareAccessRequest"
+                ],
+                "summary": "我的权限申请",
+                "operationId": "share-ListMyAccessRequests",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_chaitin_panda-wiki_api_share_v1.AccessRequestItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/app/web/info": {
+            "get": {
+                "description": "GetAppInfo",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share_app"
+                ],
+                "summary": "GetAppInfo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.AppInfoResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/app/wechat/service/answer": {
+            "get": {
+                "description": "GetWechatAnswer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wechat"
+                ],
+                "summary": "GetWechatAnswer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "conversation id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/app/widget/info": {
+            "get": {
+                "description": "GetWidgetAppInfo",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share_app"
+                ],
+                "summary": "GetWidgetAppInfo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/auth/cas": {
+            "post": {
+                "description": "获取CAS登录地址",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareAuth"
+                ],
+                "summary": "CAS登录",
+                "operationId": "v1-AuthCAS",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.AuthCASReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.AuthCASResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/auth/get": {
+            "get": {
+                "description": "AuthGet",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share_auth"
+                ],
+                "summary": "AuthGet",
+                "operationId": "v1-AuthGet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb_id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_chaitin_panda-wiki_api_share_v1.AuthGetResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/auth/github": {
+            "post": {
+                "description": "GitHub登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareAuth"
+                ],
+                "summary": "GitHub登录",
+                "operationId": "v1-AuthGitHub",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.AuthGitHubReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.AuthGitHubResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/auth/ldap": {
+            "post": {
+                "description": "LDAP账号密码登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareAuth"
+                ],
+                "summary": "LDAP登录",
+                "operationId": "v1-AuthLDAP",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.AuthLDAPReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/auth/login/simple": {
+            "post": {
+                "description": "AuthLoginSimple",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share_auth"
+                ],
+                "summary": "AuthLoginSimple",
+                "operationId": "v1-AuthLoginSimple",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb_id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.AuthLoginSimpleReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/auth/oauth": {
+            "post": {
+                "description": "获取OAuth2/OIDC授权地址",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareAuth"
+                ],
+                "summary": "OAuth登录",
+                "operationId": "v1-AuthOAuth",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.AuthOAuthReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.AuthOAuthResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/auth/saml": {
+            "post": {
+                "description": "获取SAML IdP登录地址",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareAuth"
+                ],
+                "summary": "SAML登录",
+                "operationId": "v1-AuthSAML",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.AuthSAMLReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.AuthSAMLResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/captcha/challenge": {
+            "post": {
+                "description": "CreateCaptcha",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share_captcha"
+                ],
+                "summary": "CreateCaptcha",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/gocap.ChallengeData"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/captcha/redeem": {
+            "post": {
+                "description": "RedeemCaptcha",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share_captcha"
+                ],
+                "summary": "RedeemCaptcha",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/consts.RedeemCaptchaReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/gocap.VerificationResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/chat/completions": {
+            "post": {
+                "description": "OpenAI API compatible chat completions endpoint",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share_chat"
+                ],
+                "summary": "ChatCompletions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Knowledge Base ID",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "OpenAI API request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.OpenAICompletionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.OpenAICompletionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/domain.OpenAIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/chat/feedback": {
+            "post": {
+                "description": "Process user feedback for chat conversations",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share_chat"
+                ],
+                "summary": "Handle chat feedback",
+                "parameters": [
+                    {
+                        "description": "feedback request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.FeedbackRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/chat/message": {
+            "post": {
+                "description": "ChatMessage",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share_chat"
+                ],
+                "summary": "ChatMessage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app type",
+                        "name": "app_type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ChatRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/chat/search": {
+            "post": {
+                "description": "ChatSearch",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share_chat_search"
+                ],
+                "summary": "ChatSearch",
+                "parameters": [
+                    {
+                        "description": "request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ChatSearchReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.ChatSearchResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/chat/widget": {
+            "post": {
+                "description": "ChatWidget",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share_chat"
+                ],
+                "summary": "ChatWidget",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "app type",
+                        "name": "app_type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ChatRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/comment": {
+            "post": {
+                "description": "CreateComment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share_comment"
+                ],
+                "summary": "CreateComment",
+                "parameters": [
+                    {
+                        "description": "Comment",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.CommentReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CommentID",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/comment/list": {
+            "get": {
+                "description": "GetCommentList",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share_comment"
+                ],
+                "summary": "GetCommentList",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "nodeID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CommentList",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/share.ShareCommentLists"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/common/file/upload": {
+            "post": {
+                "description": "前台用户上传文件,支持图片和文档,返回的 key 可作为问答的附件",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareFile"
+                ],
+                "summary": "文件上传",
+                "operationId": "share-FileUpload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "File",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "captcha_token",
+                        "name": "captcha_token",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.FileUploadResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/conversation/detail": {
+            "get": {
+                "description": "GetConversationDetail",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share_conversation"
+                ],
+                "summary": "GetConversationDetail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "conversation id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.ShareConversationDetailResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/node/detail": {
+            "get": {
+                "description": "GetNodeDetail",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share_node"
+                ],
+                "summary": "GetNodeDetail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "node id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "format",
+                        "name": "format",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/node/list": {
+            "get": {
+                "description": "GetNodeList",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share_node"
+                ],
+                "summary": "GetNodeList",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "filter by tags",
+                        "name": "tags[]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "filter by custom fields, key=value",
+                        "name": "fields[]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/openapi/cas/callback": {
+            "get": {
+                "description": "CAS回调",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareOpenapi"
+                ],
+                "summary": "CAS回调",
+                "operationId": "v1-CASCallback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "ticket",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PWResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/openapi/github/callback": {
+            "get": {
+                "description": "GitHub回调",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareOpenapi"
+                ],
+                "summary": "GitHub回调",
+                "operationId": "v1-GitHubCallback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "state",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_chaitin_panda-wiki_api_share_v1.GitHubCallbackResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/openapi/lark/bot/{kb_id}": {
+            "post": {
+                "description": "Lark机器人请求",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareOpenapi"
+                ],
+                "summary": "Lark机器人请求",
+                "operationId": "v1-LarkBot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "知识库ID",
+                        "name": "kb_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PWResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/openapi/mail/{kb_id}": {
+            "post": {
+                "description": "邮件服务器推送原始邮件 (RFC 5322), 通过 token 参数或 X-Mail-Token 请求头鉴权, 使用 IMAP 收信时无需配置",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareOpenapi"
+                ],
+                "summary": "邮件问答入站邮件",
+                "operationId": "v1-MailBot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "知识库ID",
+                        "name": "kb_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "入站令牌",
+                        "name": "token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PWResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/openapi/oauth/callback": {
+            "get": {
+                "description": "OAuth2/OIDC回调",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareOpenapi"
+                ],
+                "summary": "OAuth回调",
+                "operationId": "v1-OAuthCallback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "state",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PWResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/openapi/saml/acs/{kb_id}": {
+            "post": {
+                "description": "IdP 以 HTTP-POST 提交 SAMLResponse, 支持 SP 及 IdP 发起的登录",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareOpenapi"
+                ],
+                "summary": "SAML断言消费服务",
+                "operationId": "v1-SAMLACS",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "知识库ID",
+                        "name": "kb_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "SAMLResponse",
+                        "name": "SAMLResponse",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RelayState",
+                        "name": "RelayState",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PWResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/openapi/saml/metadata/{kb_id}": {
+            "get": {
+                "description": "SAML SP元数据, 在 IdP 中注册",
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "ShareOpenapi"
+                ],
+                "summary": "SAML SP元数据",
+                "operationId": "v1-SAMLMetadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "知识库ID",
+                        "name": "kb_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/share/v1/openapi/slack/bot/{kb_id}": {
+            "post": {
+                "description": "Slack Events API 及交互组件回调, 使用 Socket Mode 时无需配置",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareOpenapi"
+                ],
+                "summary": "Slack机器人请求",
+                "operationId": "v1-SlackBot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "知识库ID",
+                        "name": "kb_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PWResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/openapi/teams/{kb_id}": {
+            "post": {
+                "description": "Bot Framework 消息端点, 在 Azure Bot 中配置为 Messaging endpoint",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareOpenapi"
+                ],
+                "summary": "Teams机器人请求",
+                "operationId": "v1-TeamsBot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "知识库ID",
+                        "name": "kb_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PWResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/openapi/telegram/bot/{kb_id}": {
+            "post": {
+                "description": "Telegram webhook 回调, 使用长轮询时无需配置",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareOpenapi"
+                ],
+                "summary": "Telegram机器人请求",
+                "operationId": "v1-TelegramBot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "知识库ID",
+                        "name": "kb_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PWResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/share_link/auth": {
+            "post": {
+                "description": "密码正确时返回访问凭证，之后的请求通过 X-Share-Access 请求头携带",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareLink"
+                ],
+                "summary": "输入分享密码",
+                "operationId": "share-AuthShareLink",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "para",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.ShareLinkAuthReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.ShareLinkAuthResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/share_link/info": {
+            "get": {
+                "description": "返回分享的文档和是否需要密码，链接无效时返回 40010",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareLink"
+                ],
+                "summary": "获取分享链接信息",
+                "operationId": "share-GetShareLinkInfo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/v1.ShareLinkInfoResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/share_link/node/detail": {
+            "get": {
+                "description": "只能访问分享的节点及其子节点，每次访问计入链接的访问次数",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareLink"
+                ],
+                "summary": "分享的文档详情",
+                "operationId": "share-GetShareLinkNodeDetail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "access",
+                        "name": "X-Share-Access",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/share_link/node/list": {
+            "get": {
+                "description": "分享的节点及其下所有子节点，需要密码时返回 40011",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ShareLink"
+                ],
+                "summary": "分享的文档目录",
+                "operationId": "share-GetShareLinkNodeList",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kb id",
+                        "name": "X-KB-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "access",
+                        "name": "X-Share-Access",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/domain.PWResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.ShareNodeListItemResp"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/share/v1/stat/page": {
+            "post": {
+                "description": "RecordPage",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share_stat"
+                ],
+                "summary": "RecordPage",
+                "parameters": [
+                    {
+                        "description": "request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.StatPageReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "anydoc.Child": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/anydoc.Child"
+                    }
+                },
+                "value": {
+                    "$ref": "#/definitions/anydoc.Value"
+                }
+            }
+        },
+        "anydoc.Value": {
+            "type": "object",
+            "properties": {
+                "file": {
+                    "type": "boolean"
+                },
+                "file_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "consts.AuthGroupSyncStatus": {
+            "type": "string",
+            "enum": [
+                "success",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "AuthGroupSyncStatusSuccess",
+                "AuthGroupSyncStatusFailed"
+            ]
+        },
+        "consts.AuthGroupSyncTrigger": {
+            "type": "string",
+            "enum": [
+                "cron",
+                "manual"
+            ],
+            "x-enum-comments": {
+                "AuthGroupSyncTriggerCron": "定时同步",
+                "AuthGroupSyncTriggerManual": "管理员手动触发"
+            },
+            "x-enum-descriptions": [
+                "定时同步",
+                "管理员手动触发"
+            ],
+            "x-enum-varnames": [
+                "AuthGroupSyncTriggerCron",
+                "AuthGroupSyncTriggerManual"
+            ]
+        },
+        "consts.AuthType": {
+            "type": "string",
+            "enum": [
+                "",
+                "simple",
+                "enterprise"
+            ],
+            "x-enum-comments": {
+                "AuthTypeEnterprise": "企业认证",
+                "AuthTypeNull": "无认证",
+                "AuthTypeSimple": "简单口令"
+            },
+            "x-enum-descriptions": [
+                "无认证",
+                "简单口令",
+                "企业认证"
+            ],
+            "x-enum-varnames": [
+                "AuthTypeNull",
+                "AuthTypeSimple",
+                "AuthTypeEnterprise"
+            ]
+        },
+        "consts.ConversationScope": {
+            "type": "string",
+            "enum": [
+                "thread",
+                "chat_user",
+                "chat",
+                "none"
+            ],
+            "x-enum-comments": {
+                "ConversationScopeChat": "同一群内所有用户",
+                "ConversationScopeChatUser": "同一群内同一用户",
+                "ConversationScopeNone": "每条消息都是新会话",
+                "ConversationScopeThread": "同一话题或回复链, 都没有时同一群内同一用户, 默认"
+            },
+            "x-enum-descriptions": [
+                "同一话题或回复链, 都没有时同一群内同一用户, 默认",
+                "同一群内同一用户",
+                "同一群内所有用户",
+                "每条消息都是新会话"
+            ],
+            "x-enum-varnames": [
+                "ConversationScopeThread",
+                "ConversationScopeChatUser",
+                "ConversationScopeChat",
+                "ConversationScopeNone"
+            ]
+        },
+        "consts.CopySetting": {
+            "type": "string",
+            "enum": [
+                "",
+                "append",
+                "disabled"
+            ],
+            "x-enum-comments": {
+                "CopySettingAppend": "增加内容尾巴",
+                "CopySettingDisabled": "禁止复制内容",
+                "CopySettingNone": "无限制"
+            },
+            "x-enum-descriptions": [
+                "无限制",
+                "增加内容尾巴",
+                "禁止复制内容"
+            ],
+            "x-enum-varnames": [
+                "CopySettingNone",
+                "CopySettingAppend",
+                "CopySettingDisabled"
+            ]
+        },
+        "consts.CrawlerSource": {
+            "type": "string",
+            "enum": [
+                "url",
+                "rss",
+                "sitemap",
+                "notion",
+                "feishu",
+                "file",
+                "epub",
+                "yuque",
+                "siyuan",
+                "mindoc",
+                "wikijs",
+                "confluence"
+            ],
+            "x-enum-varnames": [
+                "CrawlerSourceUrl",
+                "CrawlerSourceRSS",
+                "CrawlerSourceSitemap",
+                "CrawlerSourceNotion",
+                "CrawlerSourceFeishu",
+                "CrawlerSourceFile",
+                "CrawlerSourceEpub",
+                "CrawlerSourceYuque",
+                "CrawlerSourceSiyuan",
+                "CrawlerSourceMindoc",
+                "CrawlerSourceWikijs",
+                "CrawlerSourceConfluence"
+            ]
+        },
+        "consts.CrawlerStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "in_process",
+                "completed",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "CrawlerStatusPending",
+                "CrawlerStatusInProcess",
+                "CrawlerStatusCompleted",
+                "CrawlerStatusFailed"
+            ]
+        },
+        "consts.EscalationReason": {
+            "type": "string",
+            "enum": [
+                "user_request",
+                "no_result",
+                "dislike"
+            ],
+            "x-enum-comments": {
+                "EscalationReasonDislike": "回答多次被点踩",
+                "EscalationReasonNoResult": "未检索到相关文档",
+                "EscalationReasonUserRequest": "用户要求转人工"
+            },
+            "x-enum-descriptions": [
+                "用户要求转人工",
+                "未检索到相关文档",
+                "回答多次被点踩"
+            ],
+            "x-enum-varnames": [
+                "EscalationReasonUserRequest",
+                "EscalationReasonNoResult",
+                "EscalationReasonDislike"
+            ]
+        },
+        "consts.EscalationStatus": {
+            "type": "string",
+            "enum": [
+                "",
+                "open",
+                "resolved"
+            ],
+            "x-enum-comments": {
+                "EscalationStatusNone": "未转人工",
+                "EscalationStatusOpen": "等待人工处理",
+                "EscalationStatusResolved": "已处理"
+            },
+            "x-enum-descriptions": [
+                "未转人工",
+                "等待人工处理",
+                "已处理"
+            ],
+            "x-enum-varnames": [
+                "EscalationStatusNone",
+                "EscalationStatusOpen",
+                "EscalationStatusResolved"
+            ]
+        },
+        "consts.HomePageSetting": {
+            "type": "string",
+            "enum": [
+                "doc",
+                "custom"
+            ],
+            "x-enum-comments": {
+                "HomePageSettingCustom": "自定义首页",
+                "HomePageSettingDoc": "文档页面"
+            },
+            "x-enum-descriptions": [
+                "文档页面",
+                "自定义首页"
+            ],
+            "x-enum-varnames": [
+                "HomePageSettingDoc",
+                "HomePageSettingCustom"
+            ]
+        },
+        "consts.KBCapability": {
+            "type": "string",
+            "enum": [
+                "node_edit",
+                "node_publish",
+                "node_review",
+                "node_permission",
+                "app_config",
+                "stat_view",
+                "conversation_view",
+                "conversation_ip",
+                "conversation_reply",
+                "comment_manage",
+                "user_manage"
+            ],
+            "x-enum-comments": {
+                "KBCapabilityAppConfig": "知识库及应用配置",
+                "KBCapabilityCommentManage": "管理评论",
+                "KBCapabilityConversationIP": "查看对话和评论的来源 IP",
+                "KBCapabilityConversationReply": "人工回复及结束转人工对话",
+                "KBCapabilityConversationView": "查看对话",
+                "KBCapabilityNodeEdit": "编辑文档",
+                "KBCapabilityNodePermission": "设置文档访问权限",
+                "KBCapabilityNodePublish": "发布版本",
+                "KBCapabilityNodeReview": "审核文档，未指定审核人时生效",
+                "KBCapabilityStatView": "查看统计",
+                "KBCapabilityUserManage": "管理成员及读者认证"
+            },
+            "x-enum-descriptions": [
+                "编辑文档",
+                "发布版本",
+                "审核文档，未指定审核人时生效",
+                "设置文档访问权限",
+                "知识库及应用配置",
+                "查看统计",
+                "查看对话",
+                "查看对话和评论的来源 IP",
+                "人工回复及结束转人工对话",
+                "管理评论",
+                "管理成员及读者认证"
+            ],
+            "x-enum-varnames": [
+                "KBCapabilityNodeEdit",
+                "KBCapabilityNodePublish",
+                "KBCapabilityNodeReview",
+                "KBCapabilityNodePermission",
+                "KBCapabilityAppConfig",
+                "KBCapabilityStatView",
+                "KBCapabilityConversationView",
+                "KBCapabilityConversationIP",
+                "KBCapabilityConversationReply",
+                "KBCapabilityCommentManage",
+                "KBCapabilityUserManage"
+            ]
+        },
+        "consts.LicenseEdition": {
+            "type": "integer",
+            "format": "int32",
+            "enum": [
+                0,
+                1,
+                2
+            ],
+            "x-enum-comments": {
+                "LicenseEditionContributor": "联创版",
+                "LicenseEditionEnterprise": "企业版",
+                "LicenseEditionFree": "开源版"
+            },
+            "x-enum-descriptions": [
+                "开源版",
+                "联创版",
+                "企业版"
+            ],
+            "x-enum-varnames": [
+                "LicenseEditionFree",
+                "LicenseEditionContributor",
+                "LicenseEditionEnterprise"
+            ]
+        },
+        "consts.MailReplyMode": {
+            "type": "string",
+            "enum": [
+                "auto",
+                "draft"
+            ],
+            "x-enum-comments": {
+                "MailReplyModeAuto": "自动回复",
+                "MailReplyModeDraft": "生成草稿, 审核后发送"
+            },
+            "x-enum-descriptions": [
+                "自动回复",
+                "生成草稿, 审核后发送"
+            ],
+            "x-enum-varnames": [
+                "MailReplyModeAuto",
+                "MailReplyModeDraft"
+            ]
+        },
+        "consts.MailReplyStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "sending",
+                "sent",
+                "failed",
+                "discarded"
+            ],
+            "x-enum-comments": {
+                "MailReplyStatusDiscarded": "已丢弃",
+                "MailReplyStatusFailed": "发送失败",
+                "MailReplyStatusPending": "草稿待审核",
+                "MailReplyStatusSending": "正在发送, 防止重复发送",
+                "MailReplyStatusSent": "已发送"
+            },
+            "x-enum-descriptions": [
+                "草稿待审核",
+                "正在发送, 防止重复发送",
+                "已发送",
+                "发送失败",
+                "已丢弃"
+            ],
+            "x-enum-varnames": [
+                "MailReplyStatusPending",
+                "MailReplyStatusSending",
+                "MailReplyStatusSent",
+                "MailReplyStatusFailed",
+                "MailReplyStatusDiscarded"
+            ]
+        },
+        "consts.MailSecurity": {
+            "type": "string",
+            "enum": [
+                "tls",
+                "starttls",
+                "none"
+            ],
+            "x-enum-comments": {
+                "MailSecurityStartTLS": "明文连接后升级, 如 IMAP 143 / SMTP 587",
+                "MailSecurityTLS": "隐式 TLS, 如 IMAPS 993 / SMTPS 465"
+            },
+            "x-enum-descriptions": [
+                "隐式 TLS, 如 IMAPS 993 / SMTPS 465",
+                "明文连接后升级, 如 IMAP 143 / SMTP 587"
+            ],
+            "x-enum-varnames": [
+                "MailSecurityTLS",
+                "MailSecurityStartTLS",
+                "MailSecurityNone"
+            ]
+        },
+        "consts.NodeAccessGrantType": {
+            "type": "string",
+            "enum": [
+                "auth_group",
+                "node"
+            ],
+            "x-enum-comments": {
+                "NodeAccessGrantTypeAuthGroup": "加入用户组",
+                "NodeAccessGrantTypeNode": "仅授权该文档"
+            },
+            "x-enum-descriptions": [
+                "加入用户组",
+                "仅授权该文档"
+            ],
+            "x-enum-varnames": [
+                "NodeAccessGrantTypeAuthGroup",
+                "NodeAccessGrantTypeNode"
+            ]
+        },
+        "consts.NodeAccessPerm": {
+            "type": "string",
+            "enum": [
+                "open",
+                "partial",
+                "closed"
+            ],
+            "x-enum-comments": {
+                "NodeAccessPermClosed": "完全禁止",
+                "NodeAccessPermOpen": "完全开放",
+                "NodeAccessPermPartial": "部分开放"
+            },
+            "x-enum-descriptions": [
+                "完全开放",
+                "部分开放",
+                "完全禁止"
+            ],
+            "x-enum-varnames": [
+                "NodeAccessPermOpen",
+                "NodeAccessPermPartial",
+                "NodeAccessPermClosed"
+            ]
+        },
+        "consts.NodeAccessRequestAction": {
+            "type": "string",
+            "enum": [
+                "approve",
+                "reject"
+            ],
+            "x-enum-varnames": [
+                "NodeAccessRequestActionApprove",
+                "NodeAccessRequestActionReject"
+            ]
+        },
+        "consts.NodeAccessRequestStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "rejected"
+            ],
+            "x-enum-comments": {
+                "NodeAccessRequestStatusApproved": "已批准",
+                "NodeAccessRequestStatusPending": "待处理",
+                "NodeAccessRequestStatusRejected": "已拒绝"
+            },
+            "x-enum-descriptions": [
+                "待处理",
+                "已批准",
+                "已拒绝"
+            ],
+            "x-enum-varnames": [
+                "NodeAccessRequestStatusPending",
+                "NodeAccessRequestStatusApproved",
+                "NodeAccessRequestStatusRejected"
+            ]
+        },
+        "consts.NodeLinkBrokenReason": {
+            "type": "string",
+            "enum": [
+                "deleted",
+                "unpublished",
+                "unreachable"
+            ],
+            "x-enum-comments": {
+                "NodeLinkBrokenReasonDeleted": "目标文档已删除",
+                "NodeLinkBrokenReasonUnpublished": "目标文档未发布",
+                "NodeLinkBrokenReasonUnreachable": "外部链接无法访问"
+            },
+            "x-enum-descriptions": [
+                "目标文档已删除",
+                "目标文档未发布",
+                "外部链接无法访问"
+            ],
+            "x-enum-varnames": [
+                "NodeLinkBrokenReasonDeleted",
+                "NodeLinkBrokenReasonUnpublished",
+                "NodeLinkBrokenReasonUnreachable"
+            ]
+        },
+        "consts.NodeLinkType": {
+            "type": "string",
+            "enum": [
+                "internal",
+                "external"
+            ],
+            "x-enum-varnames": [
+                "NodeLinkTypeInternal",
+                "NodeLinkTypeExternal"
+            ]
+        },
+        "consts.NodePermName": {
+            "type": "string",
+            "enum": [
+                "visible",
+                "visitable",
+                "answerable"
+            ],
+            "x-enum-comments": {
+                "NodePermNameAnswerable": "可被问答",
+                "NodePermNameVisible": "导航内可见",
+                "NodePermNameVisitable": "可被访问"
+            },
+            "x-enum-descriptions": [
+                "导航内可见",
+                "可被访问",
+                "可被问答"
+            ],
+            "x-enum-varnames": [
+                "NodePermNameVisible",
+                "NodePermNameVisitable",
+                "NodePermNameAnswerable"
+            ]
+        },
+        "consts.NodeRagInfoStatus": {
+            "type": "string",
+            "enum": [
+                "BASIC_PENDING",
+                "BASIC_RUNNING",
+                "BASIC_FAILED",
+                "BASIC_SUCCEEDED",
+                "ENHANCE_PENDING",
+                "ENHANCE_RUNNING",
+                "ENHANCE_FAILED",
+                "ENHANCE_SUCCEEDED"
+            ],
+            "x-enum-comments": {
+                "NodeRagStatusBasicFailed": "基础处理失败",
+                "NodeRagStatusBasicPending": "等待基础处理",
+                "NodeRagStatusBasicRunning": "正在进行基础处理（文本分割、向量化等）",
+                "NodeRagStatusBasicSucceeded": "基础处理成功",
+                "NodeRagStatusEnhanceFailed": "增强处理失败",
+                "NodeRagStatusEnhancePending": "基础处理完成，等待增强处理",
+                "NodeRagStatusEnhanceRunning": "正在进行增强处理（关键词提取等）",
+                "NodeRagStatusEnhanceSucceeded": "增强处理成功"
+            },
+            "x-enum-descriptions": [
+                "等待基础处理",
+                "正在进行基础处理（文本分割、向量化等）",
+                "基础处理失败",
+                "基础处理成功",
+                "基础处理完成，等待增强处理",
+                "正在进行增强处理（关键词提取等）",
+                "增强处理失败",
+                "增强处理成功"
+            ],
+            "x-enum-varnames": [
+                "NodeRagStatusBasicPending",
+                "NodeRagStatusBasicRunning",
+                "NodeRagStatusBasicFailed",
+                "NodeRagStatusBasicSucceeded",
+                "NodeRagStatusEnhancePending",
+                "NodeRagStatusEnhanceRunning",
+                "NodeRagStatusEnhanceFailed",
+                "NodeRagStatusEnhanceSucceeded"
+            ]
+        },
+        "consts.NodeReviewAction": {
+            "type": "string",
+            "enum": [
+                "submit",
+                "approve",
+                "request_changes"
+            ],
+            "x-enum-comments": {
+                "NodeReviewActionApprove": "审核通过",
+                "NodeReviewActionRequestChanges": "要求修改",
+                "NodeReviewActionSubmit": "提交审核"
+            },
+            "x-enum-descriptions": [
+                "提交审核",
+                "审核通过",
+                "要求修改"
+            ],
+            "x-enum-varnames": [
+                "NodeReviewActionSubmit",
+                "NodeReviewActionApprove",
+                "NodeReviewActionRequestChanges"
+            ]
+        },
+        "consts.NodeReviewStatus": {
+            "type": "string",
+            "enum": [
+                "",
+                "pending",
+                "approved",
+                "changes_requested"
+            ],
+            "x-enum-comments": {
+                "NodeReviewStatusApproved": "审核通过",
+                "NodeReviewStatusChangesRequested": "需要修改",
+                "NodeReviewStatusNone": "未提交审核",
+                "NodeReviewStatusPending": "待审核"
+            },
+            "x-enum-descriptions": [
+                "未提交审核",
+                "待审核",
+                "审核通过",
+                "需要修改"
+            ],
+            "x-enum-varnames": [
+                "NodeReviewStatusNone",
+                "NodeReviewStatusPending",
+                "NodeReviewStatusApproved",
+                "NodeReviewStatusChangesRequested"
+            ]
+        },
+        "consts.RedeemCaptchaReq": {
+            "type": "object",
+            "properties": {
+                "solutions": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "consts.SourceType": {
+            "type": "string",
+            "enum": [
+                "dingtalk",
+                "feishu",
+                "wecom",
+                "oauth",
+                "github",
+                "cas",
+                "ldap",
+                "saml",
+                "scim",
+                "widget",
+                "dingtalk_bot",
+                "feishu_bot",
+                "lark_bot",
+                "wechat_bot",
+                "wecom_ai_bot",
+                "wechat_service_bot",
+                "discord_bot",
+                "wechat_official_account",
+                "openai_api",
+                "slack_bot",
+                "telegram_bot",
+                "teams_bot",
+                "mail_bot"
+            ],
+            "x-enum-varnames": [
+                "SourceTypeDingTalk",
+                "SourceTypeFeishu",
+                "SourceTypeWeCom",
+                "SourceTypeOAuth",
+                "SourceTypeGitHub",
+                "SourceTypeCAS",
+                "SourceTypeLDAP",
+                "SourceTypeSAML",
+                "SourceTypeSCIM",
+                "SourceTypeWidget",
+                "SourceTypeDingtalkBot",
+                "SourceTypeFeishuBot",
+                "SourceTypeLarkBot",
+                "SourceTypeWechatBot",
+                "SourceTypeWecomAIBot",
+                "SourceTypeWechatServiceBot",
+                "SourceTypeDiscordBot",
+                "SourceTypeWechatOfficialAccount",
+                "SourceTypeOpenAIAPI",
+                "SourceTypeSlackBot",
+                "SourceTypeTelegramBot",
+                "SourceTypeTeamsBot",
+                "SourceTypeMailBot"
+            ]
+        },
+        "consts.StatDay": {
+            "type": "integer",
+            "enum": [
+                1,
+                7,
+                30,
+                90
+            ],
+            "x-enum-varnames": [
+                "StatDay1",
+                "StatDay7",
+                "StatDay30",
+                "StatDay90"
+            ]
+        },
+        "consts.UserKBPermission": {
+            "type": "string",
+            "enum": [
+                "",
+                "full_control",
+                "doc_manage",
+                "data_operate"
+            ],
+            "x-enum-comments": {
+                "UserKBPermissionDataOperate": "数据运营",
+                "UserKBPermissionDocManage": "文档管理",
+                "UserKBPermissionFullControl": "完全控制",
+                "UserKBPermissionNull": "无权限"
+            },
+            "x-enum-descriptions": [
+                "无权限",
+                "完全控制",
+                "文档管理",
+                "数据运营"
+            ],
+            "x-enum-varnames": [
+                "UserKBPermissionNull",
+                "UserKBPermissionFullControl",
+                "UserKBPermissionDocManage",
+                "UserKBPermissionDataOperate"
+            ]
+        },
+        "consts.UserRole": {
+            "type": "string",
+            "enum": [
+                "admin",
+                "user"
+            ],
+            "x-enum-comments": {
+                "UserRoleAdmin": "管理员",
+                "UserRoleUser": "普通用户"
+            },
+            "x-enum-descriptions": [
+                "管理员",
+                "普通用户"
+            ],
+            "x-enum-varnames": [
+                "UserRoleAdmin",
+                "UserRoleUser"
+            ]
+        },
+        "consts.WatermarkSetting": {
+            "type": "string",
+            "enum": [
+                "",
+                "hidden",
+                "visible"
+            ],
+            "x-enum-comments": {
+                "WatermarkDisabled": "未开启水印",
+                "WatermarkHidden": "隐形水印",
+                "WatermarkVisible": "显性水印"
+            },
+            "x-enum-descriptions": [
+                "未开启水印",
+                "隐形水印",
+                "显性水印"
+            ],
+            "x-enum-varnames": [
+                "WatermarkDisabled",
+                "WatermarkHidden",
+                "WatermarkVisible"
+            ]
+        },
+        "domain.AIFeedbackSettings": {
+            "type": "object",
+            "properties": {
+                "ai_feedback_type": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "is_enabled": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "domain.AccessSettings": {
+            "type": "object",
+            "properties": {
+                "base_url": {
+                    "type": "string"
+                },
+                "enterprise_auth": {
+                    "$ref": "#/definitions/domain.EnterpriseAuth"
+                },
+                "hosts": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "is_forbidden": {
+                    "description": "禁止访问",
+                    "type": "boolean"
+                },
+                "ports": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "private_key": {
+                    "type": "string"
+                },
+                "public_key": {
+                    "type": "string"
+                },
+                "simple_auth": {
+                    "$ref": "#/definitions/domain.SimpleAuth"
+                },
+                "source_type": {
+                    "description": "企业认证来源",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/consts.SourceType"
+                        }
+                    ]
+                },
+                "ssl_ports": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "trusted_proxies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "domain.AnydocUploadResp": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "string"
+                },
+                "err": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.AppDetailResp": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "kb_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "recommend_nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RecommendNodeListResp"
+                    }
+                },
+                "settings": {
+                    "$ref": "#/definitions/domain.AppSettingsResp"
+                },
+                "type": {
+                    "$ref": "#/definitions/domain.AppType"
+                }
+            }
+        },
+        "domain.AppInfoResp": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "recommend_nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RecommendNodeListResp"
+                    }
+                },
+                "settings": {
+                    "$ref": "#/definitions/domain.AppSettingsResp"
+                }
+            }
+        },
+        "domain.AppSettings": {
+            "type": "object",
+            "properties": {
+                "ai_feedback_settings": {
+                    "description": "AI feedback",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.AIFeedbackSettings"
+                        }
+                    ]
+                },
+                "auto_sitemap": {
+                    "type": "boolean"
+                },
+                "body_code": {
+                    "type": "string"
+                },
+                "bot_conversation_settings": {
+                    "description": "群聊机器人多轮会话",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.BotConversationSettings"
+                        }
+                    ]
+                },
+                "bot_route_settings": {
+                    "$ref": "#/definitions/domain.BotRouteSettings"
+                },
+                "btns": {
+                    "type": "array",
+                    "items": {}
+                },
+                "catalog_settings": {
+                    "description": "catalog settings",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.CatalogSettings"
+                        }
+                    ]
+                },
+                "contribute_settings": {
+                    "$ref": "#/definitions/domain.ContributeSettings"
+                },
+                "copy_setting": {
+                    "enum": [
+                        "",
+                        "append",
+                        "disabled"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/consts.CopySetting"
+                        }
+                    ]
+                },
+                "desc": {
+                    "description": "seo",
+                    "type": "string"
+                },
+                "dingtalk_bot_client_id": {
+                    "type": "string"
+                },
+                "dingtalk_bot_client_secret": {
+                    "type": "string"
+                },
+                "dingtalk_bot_is_enabled": {
+                    "description": "DingTalkBot",
+                    "type": "boolean"
+                },
+                "dingtalk_bot_template_id": {
+                    "type": "string"
+                },
+                "disclaimer_settings": {
+                    "description": "Disclaimer Settings",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.DisclaimerSettings"
+                        }
+                    ]
+                },
+                "discord_bot_is_enabled": {
+                    "description": "DisCordBot",
+                    "type": "boolean"
+                },
+                "discord_bot_token": {
+                    "type": "string"
+                },
+                "document_feedback_is_enabled": {
+                    "description": "document feedback",
+                    "type": "boolean"
+                },
+                "feishu_bot_app_id": {
+                    "type": "string"
+                },
+                "feishu_bot_app_secret": {
+                    "type": "string"
+                },
+                "feishu_bot_is_enabled": {
+                    "description": "FeishuBot",
+                    "type": "boolean"
+                },
+                "footer_settings": {
+                    "description": "footer settings",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.FooterSettings"
+                        }
+                    ]
+                },
+                "head_code": {
+                    "description": "inject code",
+                    "type": "string"
+                },
+                "home_page_setting": {
+                    "$ref": "#/definitions/consts.HomePageSetting"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "keyword": {
+                    "type": "string"
+                },
+                "lark_bot_settings": {
+                    "description": "LarkBot",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.LarkBotSettings"
+                        }
+                    ]
+                },
+                "mail_bot_settings": {
+                    "description": "MailBot",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.MailBotSettings"
+                        }
+                    ]
+                },
+                "openai_api_bot_settings": {
+                    "description": "OpenAI API Bot settings",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.OpenAIAPIBotSettings"
+                        }
+                    ]
+                },
+                "recommend_node_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "recommend_questions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "search_placeholder": {
+                    "type": "string"
+                },
+                "slack_bot_settings": {
+                    "description": "SlackBot",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.SlackBotSettings"
+                        }
+                    ]
+                },
+                "teams_bot_settings": {
+                    "description": "TeamsBot",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.TeamsBotSettings"
+                        }
+                    ]
+                },
+                "telegram_bot_settings": {
+                    "description": "TelegramBot",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.TelegramBotSettings"
+                        }
+                    ]
+                },
+                "theme_and_style": {
+                    "$ref": "#/definitions/domain.ThemeAndStyle"
+                },
+                "theme_mode": {
+                    "description": "theme",
+                    "type": "string"
+                },
+                "title": {
+                    "description": "nav",
+                    "type": "string"
+                },
+                "watermark_content": {
+                    "type": "string"
+                },
+                "watermark_setting": {
+                    "enum": [
+                        "",
+                        "hidden",
+                        "visible"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/consts.WatermarkSetting"
+                        }
+                    ]
+                },
+                "web_app_comment_settings": {
+                    "description": "webapp comment settings",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.WebAppCommentSettings"
+                        }
+                    ]
+                },
+                "web_app_custom_style": {
+                    "description": "WebAppCustomStyle",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.WebAppCustomSettings"
+                        }
+                    ]
+                },
+                "web_app_landing_configs": {
+                    "description": "WebAppLandingConfigs",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.WebAppLandingConfig"
+                    }
+                },
+                "web_app_landing_theme": {
+                    "$ref": "#/definitions/domain.WebAppLandingTheme"
+                },
+                "wechat_app_agent_id": {
+                    "type": "string"
+                },
+                "wechat_app_corpid": {
+                    "type": "string"
+                },
+                "wechat_app_encodingaeskey": {
+                    "type": "string"
+                },
+                "wechat_app_is_enabled": {
+                    "description": "WechatAppBot 企业微信机器人",
+                    "type": "boolean"
+                },
+                "wechat_app_secret": {
+                    "type": "string"
+                },
+                "wechat_app_token": {
+                    "type": "string"
+                },
+                "wechat_official_account_app_id": {
+                    "type": "string"
+                },
+                "wechat_official_account_app_secret": {
+                    "type": "string"
+                },
+                "wechat_official_account_encodingaeskey": {
+                    "type": "string"
+                },
+                "wechat_official_account_is_enabled": {
+                    "description": "WechatOfficialAccount",
+                    "type": "boolean"
+                },
+                "wechat_official_account_token": {
+                    "type": "string"
+                },
+                "wechat_service_contain_keywords": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "wechat_service_corpid": {
+                    "type": "string"
+                },
+                "wechat_service_encodingaeskey": {
+                    "type": "string"
+                },
+                "wechat_service_equal_keywords": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "wechat_service_is_enabled": {
+                    "description": "WechatServiceBot",
+                    "type": "boolean"
+                },
+                "wechat_service_secret": {
+                    "type": "string"
+                },
+                "wechat_service_token": {
+                    "type": "string"
+                },
+                "wecom_ai_bot_settings": {
+                    "description": "WecomAIBotSettings 企业微信智能机器人",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.WecomAIBotSettings"
+                        }
+                    ]
+                },
+                "welcome_str": {
+                    "description": "welcome",
+                    "type": "string"
+                },
+                "widget_bot_settings": {
+                    "description": "Widget bot settings",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.WidgetBotSettings"
+                        }
+                    ]
+                }
+            }
+        },
+        "domain.AppSettingsResp": {
+            "type": "object",
+            "properties": {
+                "ai_feedback_settings": {
+                    "description": "AI feedback",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.AIFeedbackSettings"
+                        }
+                    ]
+                },
+                "auto_sitemap": {
+                    "type": "boolean"
+                },
+                "body_code": {
+                    "type": "string"
+                },
+                "bot_conversation_settings": {
+                    "description": "群聊机器人多轮会话",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.BotConversationSettings"
+                        }
+                    ]
+                },
+                "bot_route_settings": {
+                    "$ref": "#/definitions/domain.BotRouteSettings"
+                },
+                "btns": {
+                    "type": "array",
+                    "items": {}
+                },
+                "catalog_settings": {
+                    "description": "catalog settings",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.CatalogSettings"
+                        }
+                    ]
+                },
+                "contribute_settings": {
+                    "$ref": "#/definitions/domain.ContributeSettings"
+                },
+                "copy_setting": {
+                    "$ref": "#/definitions/consts.CopySetting"
+                },
+                "desc": {
+                    "description": "seo",
+                    "type": "string"
+                },
+                "dingtalk_bot_client_id": {
+                    "type": "string"
+                },
+                "dingtalk_bot_client_secret": {
+                    "type": "string"
+                },
+                "dingtalk_bot_is_enabled": {
+                    "description": "DingTalkBot",
+                    "type": "boolean"
+                },
+                "dingtalk_bot_template_id": {
+                    "type": "string"
+                },
+                "disclaimer_settings": {
+                    "description": "Disclaimer Settings",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.DisclaimerSettings"
+                        }
+                    ]
+                },
+                "discord_bot_is_enabled": {
+                    "description": "DisCordBot",
+                    "type": "boolean"
+                },
+                "discord_bot_token": {
+                    "type": "string"
+                },
+                "document_feedback_is_enabled": {
+                    "description": "document feedback",
+                    "type": "boolean"
+                },
+                "feishu_bot_app_id": {
+                    "type": "string"
+                },
+                "feishu_bot_app_secret": {
+                    "type": "string"
+                },
+                "feishu_bot_is_enabled": {
+                    "description": "FeishuBot",
+                    "type": "boolean"
+                },
+                "footer_settings": {
+                    "description": "footer settings",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.FooterSettings"
+                        }
+                    ]
+                },
+                "head_code": {
+                    "description": "inject code",
+                    "type": "string"
+                },
+                "home_page_setting": {
+                    "$ref": "#/definitions/consts.HomePageSetting"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "keyword": {
+                    "type": "string"
+                },
+                "lark_bot_settings": {
+                    "description": "LarkBot",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.LarkBotSettings"
+                        }
+                    ]
+                },
+                "mail_bot_settings": {
+                    "description": "MailBot",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.MailBotSettings"
+                        }
+                    ]
+                },
+                "openai_api_bot_settings": {
+                    "description": "OpenAI API settings",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.OpenAIAPIBotSettings"
+                        }
+                    ]
+                },
+                "recommend_node_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "recommend_questions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "search_placeholder": {
+                    "type": "string"
+                },
+                "slack_bot_settings": {
+                    "description": "SlackBot",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.SlackBotSettings"
+                        }
+                    ]
+                },
+                "teams_bot_settings": {
+                    "description": "TeamsBot",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.TeamsBotSettings"
+                        }
+                    ]
+                },
+                "telegram_bot_settings": {
+                    "description": "TelegramBot",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.TelegramBotSettings"
+                        }
+                    ]
+                },
+                "theme_and_style": {
+                    "$ref": "#/definitions/domain.ThemeAndStyle"
+                },
+                "theme_mode": {
+                    "description": "theme",
+                    "type": "string"
+                },
+                "title": {
+                    "description": "nav",
+                    "type": "string"
+                },
+                "watermark_content": {
+                    "type": "string"
+                },
+                "watermark_setting": {
+                    "$ref": "#/definitions/consts.WatermarkSetting"
+                },
+                "web_app_comment_settings": {
+                    "description": "webapp comment settings",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.WebAppCommentSettings"
+                        }
+                    ]
+                },
+                "web_app_custom_style": {
+                    "description": "WebAppCustomStyle",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.WebAppCustomSettings"
+                        }
+                    ]
+                },
+                "web_app_landing_configs": {
+                    "description": "WebApp Landing Settings",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.WebAppLandingConfigResp"
+                    }
+                },
+                "web_app_landing_theme": {
+                    "$ref": "#/definitions/domain.WebAppLandingTheme"
+                },
+                "wechat_app_agent_id": {
+                    "type": "string"
+                },
+                "wechat_app_corpid": {
+                    "type": "string"
+                },
+                "wechat_app_encodingaeskey": {
+                    "type": "string"
+                },
+                "wechat_app_is_enabled": {
+                    "description": "WechatAppBot",
+                    "type": "boolean"
+                },
+                "wechat_app_secret": {
+                    "type": "string"
+                },
+                "wechat_app_token": {
+                    "type": "string"
+                },
+                "wechat_official_account_app_id": {
+                    "type": "string"
+                },
+                "wechat_official_account_app_secret": {
+                    "type": "string"
+                },
+                "wechat_official_account_encodingaeskey": {
+                    "type": "string"
+                },
+                "wechat_official_account_is_enabled": {
+                    "description": "WechatOfficialAccount",
+                    "type": "boolean"
+                },
+                "wechat_official_account_token": {
+                    "type": "string"
+                },
+                "wechat_service_contain_keywords": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "wechat_service_corpid": {
+                    "type": "string"
+                },
+                "wechat_service_encodingaeskey": {
+                    "type": "string"
+                },
+                "wechat_service_equal_keywords": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "wechat_service_is_enabled": {
+                    "description": "WechatServiceBot",
+                    "type": "boolean"
+                },
+                "wechat_service_secret": {
+                    "type": "string"
+                },
+                "wechat_service_token": {
+                    "type": "string"
+                },
+                "wecom_ai_bot_settings": {
+                    "$ref": "#/definitions/domain.WecomAIBotSettings"
+                },
+                "welcome_str": {
+                    "description": "welcome",
+                    "type": "string"
+                },
+                "widget_bot_settings": {
+                    "description": "WidgetBot",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.WidgetBotSettings"
+                        }
+                    ]
+                }
+            }
+        },
+        "domain.AppType": {
+            "type": "integer",
+            "format": "int32",
+            "enum": [
+                1,
+                2,
+                3,
+                4,
+                5,
+                6,
+                7,
+                8,
+                9,
+                10,
+                11,
+                12,
+                13,
+                14,
+                15
+            ],
+            "x-enum-varnames": [
+                "AppTypeWeb",
+                "AppTypeWidget",
+                "AppTypeDingTalkBot",
+                "AppTypeFeishuBot",
+                "AppTypeWechatBot",
+                "AppTypeWechatServiceBot",
+                "AppTypeDisCordBot",
+                "AppTypeWechatOfficialAccount",
+                "AppTypeOpenAIAPI",
+                "AppTypeWecomAIBot",
+                "AppTypeLarkBot",
+                "AppTypeSlackBot",
+                "AppTypeTelegramBot",
+                "AppTypeTeamsBot",
+                "AppTypeMailBot"
+            ]
+        },
+        "domain.AuditActorType": {
+            "type": "string",
+            "enum": [
+                "user",
+                "api_token",
+                "system"
+            ],
+            "x-enum-comments": {
+                "AuditActorSystem": "定时任务等"
+            },
+            "x-enum-descriptions": [
+                "定时任务等"
+            ],
+            "x-enum-varnames": [
+                "AuditActorUser",
+                "AuditActorAPIToken",
+                "AuditActorSystem"
+            ]
+        },
+        "domain.AuditChange": {
+            "type": "object",
+            "properties": {
+                "after": {},
+                "before": {},
+                "path": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.AuditLog": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "actor_name": {
+                    "type": "string"
+                },
+                "actor_type": {
+                    "$ref": "#/definitions/domain.AuditActorType"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AuditChange"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "kb_id": {
+                    "type": "string"
+                },
+                "prev_hash": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "target_type": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.AuditLogSetting": {
+            "type": "object",
+            "properties": {
+                "retention_days": {
+                    "description": "保留天数，0 表示永久保留",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.AuthGroupSyncDiff": {
+            "type": "object",
+            "properties": {
+                "add_members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AuthGroupSyncMember"
+                    }
+                },
+                "create_groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AuthGroupSyncGroup"
+                    }
+                },
+                "delete_groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AuthGroupSyncGroup"
+                    }
+                },
+                "remove_members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AuthGroupSyncMember"
+                    }
+                },
+                "unmatched_members": {
+                    "description": "目录中尚未登录过的用户，登录后下次同步加入",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "update_groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AuthGroupSyncGroup"
+                    }
+                },
+                "updated_docs": {
+                    "description": "因删除用户组而更新了可问答用户组的 RAG 文档数",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.AuthGroupSyncGroup": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "已有用户组的 ID",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "old_name": {
+                    "type": "string"
+                },
+                "old_sync_parent_id": {
+                    "type": "string"
+                },
+                "sync_id": {
+                    "type": "string"
+                },
+                "sync_parent_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.AuthGroupSyncMember": {
+            "type": "object",
+            "properties": {
+                "auth_id": {
+                    "type": "integer"
+                },
+                "group_id": {
+                    "description": "新建的用户组为 0",
+                    "type": "integer"
+                },
+                "group_name": {
+                    "type": "string"
+                },
+                "group_sync_id": {
+                    "type": "string"
+                },
+                "union_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.AuthGroupSyncRun": {
+            "type": "object",
+            "properties": {
+                "diff": {
+                    "$ref": "#/definitions/domain.AuthGroupSyncDiff"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kb_id": {
+                    "type": "string"
+                },
+                "source_type": {
+                    "$ref": "#/definitions/consts.SourceType"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/consts.AuthGroupSyncStatus"
+                },
+                "trigger": {
+                    "$ref": "#/definitions/consts.AuthGroupSyncTrigger"
+                }
+            }
+        },
+        "domain.AuthUserInfo": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.BannerConfig": {
+            "type": "object",
+            "properties": {
+                "bg_url": {
+                    "type": "string"
+                },
+                "btns": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "properties": {
+                            "href": {
+                                "type": "string"
+                            },
+                            "id": {
+                                "type": "string"
+                            },
+                            "text": {
+                                "type": "string"
+                            },
+                            "type": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "hot_search": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "placeholder": {
+                    "type": "string"
+                },
+                "subtitle": {
+                    "type": "string"
+                },
+                "subtitle_color": {
+                    "type": "string"
+                },
+                "subtitle_font_size": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "title_color": {
+                    "type": "string"
+                },
+                "title_font_size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.BasicDocConfig": {
+            "type": "object",
+            "properties": {
+                "bg_color": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "title_color": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.BatchMoveReq": {
+            "type": "object",
+            "required": [
+                "ids",
+                "kb_id"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "kb_id": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.BlockGridConfig": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "properties": {
+                            "id": {
+                                "type": "string"
+                            },
+                            "name": {
+                                "type": "string"
+                            },
+                            "url": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.BotConversationSettings": {
+            "type": "object",
+            "properties": {
+                "scope": {
+                    "description": "默认 thread",
+                    "enum": [
+                        "thread",
+                        "chat_user",
+                        "chat",
+                        "none"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/consts.ConversationScope"
+                        }
+                    ]
+                },
+                "window": {
+                    "description": "分钟, 超过该时长没有新消息则开启新会话, 默认 60",
+                    "type": "integer",
+                    "maximum": 10080,
+                    "minimum": 1
+                }
+            }
+        },
+        "domain.BotRouteRule": {
+            "type": "object",
+            "required": [
+                "kb_id"
+            ],
+            "properties": {
+                "chat_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "departments": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "description": {
+                    "description": "知识库说明, 供模型分类参考",
+                    "type": "string"
+                },
+                "kb_id": {
+                    "type": "string"
+                },
+                "keywords": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "domain.BotRouteSettings": {
+            "type": "object",
+            "properties": {
+                "classifier": {
+                    "description": "Classifier 没有规则命中时由模型根据知识库说明选择",
+                    "type": "boolean"
+                },
+                "fallback_kb_ids": {
+                    "description": "FallbackKBIDs 仍无法确定时与当前知识库合并检索",
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "is_enabled": {
+                    "type": "boolean"
+                },
+                "rules": {
+                    "description": "按顺序匹配, 命中第一条规则即分发",
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "$ref": "#/definitions/domain.BotRouteRule"
+                    }
+                }
+            }
+        },
+        "domain.BrandGroup": {
+            "type": "object",
+            "properties": {
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Link"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.BrowserCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CASAuthSetting": {
+            "type": "object",
+            "required": [
+                "server_url"
+            ],
+            "properties": {
+                "login_path": {
+                    "description": "默认 /login",
+                    "type": "string"
+                },
+                "server_url": {
+                    "description": "如 https://cas.example.com/cas",
+                    "type": "string"
+                },
+                "validate_path": {
+                    "description": "默认根据版本选择",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "协议版本，默认 3",
+                    "type": "string",
+                    "enum": [
+                        "2",
+                        "3"
+                    ]
+                }
+            }
+        },
+        "domain.CarouselConfig": {
+            "type": "object",
+            "properties": {
+                "bg_color": {
+                    "type": "string"
+                },
+                "list": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "properties": {
+                            "desc": {
+                                "type": "string"
+                            },
+                            "id": {
+                                "type": "string"
+                            },
+                            "title": {
+                                "type": "string"
+                            },
+                            "url": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CaseConfig": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "properties": {
+                            "id": {
+                                "type": "string"
+                            },
+                            "link": {
+                                "type": "string"
+                            },
+                            "name": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CatalogSettings": {
+            "type": "object",
+            "properties": {
+                "catalog_folder": {
+                    "description": "1: 展开, 2: 折叠, default: 1",
+                    "type": "integer"
+                },
+                "catalog_visible": {
+                    "description": "1: 显示, 2: 隐藏, default: 1",
+                    "type": "integer"
+                },
+                "catalog_width": {
+                    "description": "200 - 300, default: 260",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.ChatAttachment": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ChatRequest": {
+            "type": "object",
+            "required": [
+                "app_type",
+                "message"
+            ],
+            "properties": {
+                "app_type": {
+                    "enum": [
+                        1,
+                        2
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.AppType"
+                        }
+                    ]
+                },
+                "attachments": {
+                    "description": "Attachments 随问题上传的图片或文档, key 为 /share/v1/common/file/upload 返回的 key",
+                    "type": "array",
+                    "maxItems": 5,
+                    "items": {
+                        "$ref": "#/definitions/domain.ChatAttachment"
+                    }
+                },
+                "captcha_token": {
+                    "type": "string"
+                },
+                "conversation_id": {
+                    "type": "string"
+                },
+                "filter": {
+                    "description": "Filter 仅在匹配标签及自定义字段的文档中检索, 如 {\"fields\": {\"product\": \"X\", \"version\": \"3.x\"}}",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.NodeMetaFilter"
+                        }
+                    ]
+                },
+                "message": {
+                    "type": "string"
+                },
+                "nonce": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ChatSearchReq": {
+            "type": "object",
+            "required": [
+                "message"
+            ],
+            "properties": {
+                "captcha_token": {
+                    "type": "string"
+                },
+                "filter": {
+                    "$ref": "#/definitions/domain.NodeMetaFilter"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ChatSearchResp": {
+            "type": "object",
+            "properties": {
+                "node_result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.NodeContentChunkSSE"
+                    }
+                }
+            }
+        },
+        "domain.CommentConfig": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "properties": {
+                            "avatar": {
+                                "type": "string"
+                            },
+                            "comment": {
+                                "type": "string"
+                            },
+                            "id": {
+                                "type": "string"
+                            },
+                            "profession": {
+                                "type": "string"
+                            },
+                            "user_name": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CommentInfo": {
+            "type": "object",
+            "properties": {
+                "auth_user_id": {
+                    "type": "integer"
+                },
+                "avatar": {
+                    "description": "avatar",
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "remote_ip": {
+                    "type": "string"
+                },
+                "user_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CommentListItem": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "info": {
+                    "$ref": "#/definitions/domain.CommentInfo"
+                },
+                "ip_address": {
+                    "description": "ip地址",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.IPAddress"
+                        }
+                    ]
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "node_name": {
+                    "description": "文档标题",
+                    "type": "string"
+                },
+                "node_type": {
+                    "type": "integer"
+                },
+                "root_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "status : -1 reject 0 pending 1 accept",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.CommentStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "domain.CommentReq": {
+            "type": "object",
+            "required": [
+                "content",
+                "node_id",
+                "pic_urls"
+            ],
+            "properties": {
+                "captcha_token": {
+                    "type": "string"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "pic_urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "root_id": {
+                    "type": "string"
+                },
+                "user_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CommentStatus": {
+            "type": "integer",
+            "format": "int32",
+            "enum": [
+                -1,
+                0,
+                1
+            ],
+            "x-enum-varnames": [
+                "CommentStatusReject",
+                "CommentStatusPending",
+                "CommentStatusAccepted"
+            ]
+        },
+        "domain.CompleteReq": {
+            "type": "object",
+            "properties": {
+                "prefix": {
+                    "description": "For FIM (Fill in Middle) style completion",
+                    "type": "string"
+                },
+                "suffix": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ContributeSettings": {
+            "type": "object",
+            "properties": {
+                "is_enable": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "domain.ConversationDetailResp": {
+            "type": "object",
+            "properties": {
+                "app_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "escalated_at": {
+                    "type": "string"
+                },
+                "escalation_reason": {
+                    "$ref": "#/definitions/consts.EscalationReason"
+                },
+                "escalation_status": {
+                    "$ref": "#/definitions/consts.EscalationStatus"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "$ref": "#/definitions/domain.IPAddress"
+                },
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ConversationMessage"
+                    }
+                },
+                "references": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ConversationReference"
+                    }
+                },
+                "remote_ip": {
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ConversationDistribution": {
+            "type": "object",
+            "properties": {
+                "app_id": {
+                    "type": "string"
+                },
+                "app_type": {
+                    "$ref": "#/definitions/domain.AppType"
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.ConversationInfo": {
+            "type": "object",
+            "properties": {
+                "reply_route": {
+                    "description": "ReplyRoute 机器人回复该会话所需的渠道信息, 如 slack 的 channel 和 thread_ts, 用于人工回复",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "user_info": {
+                    "$ref": "#/definitions/domain.UserInfo"
+                }
+            }
+        },
+        "domain.ConversationListItem": {
+            "type": "object",
+            "properties": {
+                "app_name": {
+                    "type": "string"
+                },
+                "app_type": {
+                    "$ref": "#/definitions/domain.AppType"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "escalated_at": {
+                    "type": "string"
+                },
+                "escalation_reason": {
+                    "$ref": "#/definitions/consts.EscalationReason"
+                },
+                "escalation_status": {
+                    "$ref": "#/definitions/consts.EscalationStatus"
+                },
+                "feedback_info": {
+                    "description": "用户反馈信息",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.FeedBackInfo"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "string"
+                },
+                "info": {
+                    "description": "用户信息",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.ConversationInfo"
+                        }
+                    ]
+                },
+                "ip_address": {
+                    "$ref": "#/definitions/domain.IPAddress"
+                },
+                "remote_ip": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ConversationMessage": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "description": "AgentID the admin user who replied as a human agent, empty for ai answers",
+                    "type": "string"
+                },
+                "app_id": {
+                    "type": "string"
+                },
+                "completion_tokens": {
+                    "type": "integer"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "conversation_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "info": {
+                    "description": "feedbackinfo",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.FeedBackInfo"
+                        }
+                    ]
+                },
+                "kb_id": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "parent_id",
+                    "type": "string"
+                },
+                "prompt_tokens": {
+                    "type": "integer"
+                },
+                "provider": {
+                    "description": "model",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.ModelProvider"
+                        }
+                    ]
+                },
+                "remote_ip": {
+                    "description": "stats",
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/schema.RoleType"
+                },
+                "total_tokens": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.ConversationMessageListItem": {
+            "type": "object",
+            "properties": {
+                "app_id": {
+                    "type": "string"
+                },
+                "app_type": {
+                    "$ref": "#/definitions/domain.AppType"
+                },
+                "conversation_id": {
+                    "type": "string"
+                },
+                "conversation_info": {
+                    "description": "userInfo",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.ConversationInfo"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "info": {
+                    "description": "feedbackInfo",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.FeedBackInfo"
+                        }
+                    ]
+                },
+                "ip_address": {
+                    "$ref": "#/definitions/domain.IPAddress"
+                },
+                "question": {
+                    "type": "string"
+                },
+                "remote_ip": {
+                    "description": "stats",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ConversationReference": {
+            "type": "object",
+            "properties": {
+                "app_id": {
+                    "type": "string"
+                },
+                "conversation_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CreateKBReleaseReq": {
+            "type": "object",
+            "required": [
+                "kb_id",
+                "message",
+                "tag"
+            ],
+            "properties": {
+                "broadcast": {
+                    "description": "Broadcast 向订阅的群聊推送本次发布的文档",
+                    "type": "boolean"
+                },
+                "kb_id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "node_ids": {
+                    "description": "create release after these nodes published",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tag": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CreateKnowledgeBaseReq": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "hosts": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ports": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "private_key": {
+                    "type": "string"
+                },
+                "public_key": {
+                    "type": "string"
+                },
+                "ssl_ports": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "domain.CreateModelReq": {
+            "type": "object",
+            "required": [
+                "base_url",
+                "model",
+                "provider",
+                "type"
+            ],
+            "properties": {
+                "api_header": {
+                    "type": "string"
+                },
+                "api_key": {
+                    "type": "string"
+                },
+                "api_version": {
+                    "description": "for azure openai",
+                    "type": "string"
+                },
+                "base_url": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.ModelParam"
+                },
+                "provider": {
+                    "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.ModelProvider"
+                },
+                "type": {
+                    "enum": [
+                        "chat",
+                        "embedding",
+                        "rerank",
+                        "analysis",
+                        "analysis-vl"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.ModelType"
+                        }
+                    ]
+                }
+            }
+        },
+        "domain.CreateNodeReq": {
+            "type": "object",
+            "required": [
+                "kb_id",
+                "type"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "content_type": {
+                    "type": "string"
+                },
+                "emoji": {
+                    "type": "string"
+                },
+                "fields": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "kb_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "description": "default to creator",
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "number"
+                },
+                "review_interval": {
+                    "description": "days",
+                    "type": "integer",
+                    "maximum": 3650,
+                    "minimum": 0
+                },
                 "summary": {
                     "type": "string"
                 },
-                "title": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "template_id": {
+                    "description": "从模板创建, 未填写的字段使用模板中的值",
+                    "type": "string"
+                },
+                "type": {
+                    "enum": [
+                        1,
+                        2
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.NodeType"
+                        }
+                    ]
+                }
+            }
+        },
+        "domain.DirDocConfig": {
+            "type": "object",
+            "properties": {
+                "bg_color": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "title_color": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.DisclaimerSettings": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.EnterpriseAuth": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "domain.FaqConfig": {
+            "type": "object",
+            "properties": {
+                "bg_color": {
+                    "type": "string"
+                },
+                "list": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "properties": {
+                            "id": {
+                                "type": "string"
+                            },
+                            "link": {
+                                "type": "string"
+                            },
+                            "question": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "title_color": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.FeatureConfig": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "properties": {
+                            "desc": {
+                                "type": "string"
+                            },
+                            "id": {
+                                "type": "string"
+                            },
+                            "name": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.FeedBackInfo": {
+            "type": "object",
+            "properties": {
+                "feedback_content": {
+                    "type": "string"
+                },
+                "feedback_type": {
+                    "type": "string"
+                },
+                "score": {
+                    "$ref": "#/definitions/domain.ScoreType"
+                }
+            }
+        },
+        "domain.FeedbackRequest": {
+            "type": "object",
+            "required": [
+                "message_id"
+            ],
+            "properties": {
+                "conversation_id": {
+                    "type": "string"
+                },
+                "feedback_content": {
+                    "description": "限制内容长度",
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "message_id": {
+                    "type": "string"
+                },
+                "score": {
+                    "description": "-1 踩 ,0 1 赞成",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.ScoreType"
+                        }
+                    ]
+                },
+                "type": {
+                    "description": "内容不准确，没有帮助，.......",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.FooterSettings": {
+            "type": "object",
+            "properties": {
+                "brand_desc": {
+                    "type": "string"
+                },
+                "brand_groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.BrandGroup"
+                    }
+                },
+                "brand_logo": {
+                    "type": "string"
+                },
+                "brand_name": {
+                    "type": "string"
+                },
+                "corp_name": {
+                    "type": "string"
+                },
+                "footer_style": {
+                    "type": "string"
+                },
+                "icp": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.GetKBReleaseListResp": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.KBReleaseListItemResp"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.GetProviderModelListReq": {
+            "type": "object",
+            "required": [
+                "base_url",
+                "provider",
+                "type"
+            ],
+            "properties": {
+                "api_header": {
+                    "type": "string"
+                },
+                "api_key": {
+                    "type": "string"
+                },
+                "base_url": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "type": {
+                    "enum": [
+                        "chat",
+                        "embedding",
+                        "rerank",
+                        "analysis",
+                        "analysis-vl"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.ModelType"
+                        }
+                    ]
+                }
+            }
+        },
+        "domain.GetProviderModelListResp": {
+            "type": "object",
+            "properties": {
+                "models": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ProviderModelListItem"
+                    }
+                }
+            }
+        },
+        "domain.HotBrowser": {
+            "type": "object",
+            "properties": {
+                "browser": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.BrowserCount"
+                    }
+                },
+                "os": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.BrowserCount"
+                    }
+                }
+            }
+        },
+        "domain.HotPage": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "node_name": {
                     "type": "string"
+                },
+                "scene": {
+                    "$ref": "#/definitions/domain.StatPageScene"
                 }
             }
         },
-        "consts.AuthType": {
-            "type": "string",
-            "enum": [
-                "",
-                "simple",
-                "enterprise"
-            ],
-            "x-enum-comments": {
-                "AuthTypeEnterprise": "企业认证",
-                "AuthTypeNull": "无认证",
-                "AuthTypeSimple": "简单口令"
-            },
-            "x-enum-descriptions": [
-                "无认证",
-                "简单口令",
-                "企业认证"
-            ],
-            "x-enum-varnames": [
-                "AuthTypeNull",
-                "AuthTypeSimple",
-                "AuthTypeEnterprise"
-            ]
-        },
-        "consts.CopySetting": {
-            "type": "string",
-            "enum": [
-                "",
-                "append",
-                "disabled"
-            ],
-            "x-enum-comments": {
-                "CopySettingAppend": "增加内容尾巴",
-                "CopySettingDisabled": "禁止复制内容",
-                "CopySettingNone": "无限制"
-            },
-            "x-enum-descriptions": [
-                "无限制",
-                "增加内容尾巴",
-                "禁止复制内容"
-            ],
-            "x-enum-varnames": [
-                "CopySettingNone",
-                "CopySettingAppend",
-                "CopySettingDisabled"
-            ]
-        },
-        "consts.CrawlerSource": {
-            "type": "string",
-            "enum": [
-                "url",
-                "rss",
-                "sitemap",
-                "notion",
-                "feishu",
-                "file",
-                "epub",
-                "yuque",
-                "siyuan",
-                "mindoc",
-                "wikijs",
-                "confluence"
-            ],
-            "x-enum-varnames": [
-                "CrawlerSourceUrl",
-                "CrawlerSourceRSS",
-                "CrawlerSourceSitemap",
-                "CrawlerSourceNotion",
-                "CrawlerSourceFeishu",
-                "CrawlerSourceFile",
-                "CrawlerSourceEpub",
-                "CrawlerSourceYuque",
-                "CrawlerSourceSiyuan",
-                "CrawlerSourceMindoc",
-                "CrawlerSourceWikijs",
-                "CrawlerSourceConfluence"
-            ]
+        "domain.HotRefererHost": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "referer_host": {
+                    "type": "string"
+                }
+            }
         },
-        "consts.CrawlerStatus": {
-            "type": "string",
-            "enum": [
-                "pending",
-                "in_process",
-                "completed",
-                "failed"
-            ],
-            "x-enum-varnames": [
-                "CrawlerStatusPending",
-                "CrawlerStatusInProcess",
-                "CrawlerStatusCompleted",
-                "CrawlerStatusFailed"
-            ]
+        "domain.IPAddress": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "province": {
+                    "type": "string"
+                }
+            }
         },
-        "consts.HomePageSetting": {
-            "type": "string",
-            "enum": [
-                "doc",
-                "custom"
-            ],
-            "x-enum-comments": {
-                "HomePageSettingCustom": "自定义首页",
-                "HomePageSettingDoc": "文档页面"
-            },
-            "x-enum-descriptions": [
-                "文档页面",
-                "自定义首页"
-            ],
-            "x-enum-varnames": [
-                "HomePageSettingDoc",
-                "HomePageSettingCustom"
-            ]
+        "domain.ImgTextConfig": {
+            "type": "object",
+            "properties": {
+                "item": {
+                    "type": "object",
+                    "properties": {
+                        "desc": {
+                            "type": "string"
+                        },
+                        "name": {
+                            "type": "string"
+                        },
+                        "url": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
         },
-        "consts.LicenseEdition": {
-            "type": "integer",
-            "format": "int32",
-            "enum": [
-                0,
-                1,
-                2
-            ],
-            "x-enum-comments": {
-                "LicenseEditionContributor": "联创版",
-                "LicenseEditionEnterprise": "企业版",
-                "LicenseEditionFree": "开源版"
-            },
-            "x-enum-descriptions": [
-                "开源版",
-                "联创版",
-                "企业版"
-            ],
-            "x-enum-varnames": [
-                "LicenseEditionFree",
-                "LicenseEditionContributor",
-                "LicenseEditionEnterprise"
-            ]
+        "domain.InstantCountResp": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
         },
-        "consts.NodeAccessPerm": {
-            "type": "string",
-            "enum": [
-                "open",
-                "partial",
-                "closed"
-            ],
-            "x-enum-comments": {
-                "NodeAccessPermClosed": "完全禁止",
-                "NodeAccessPermOpen": "完全开放",
-                "NodeAccessPermPartial": "部分开放"
-            },
-            "x-enum-descriptions": [
-                "完全开放",
-                "部分开放",
-                "完全禁止"
-            ],
-            "x-enum-varnames": [
-                "NodeAccessPermOpen",
-                "NodeAccessPermPartial",
-                "NodeAccessPermClosed"
-            ]
+        "domain.InstantPageResp": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "info": {
+                    "$ref": "#/definitions/domain.AuthUserInfo"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "$ref": "#/definitions/domain.IPAddress"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "node_name": {
+                    "type": "string"
+                },
+                "scene": {
+                    "$ref": "#/definitions/domain.StatPageScene"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
         },
-        "consts.NodePermName": {
-            "type": "string",
-            "enum": [
-                "visible",
-                "visitable",
-                "answerable"
-            ],
-            "x-enum-comments": {
-                "NodePermNameAnswerable": "可被问答",
-                "NodePermNameVisible": "导航内可见",
-                "NodePermNameVisitable": "可被访问"
-            },
-            "x-enum-descriptions": [
-                "导航内可见",
-                "可被访问",
-                "可被问答"
-            ],
-            "x-enum-varnames": [
-                "NodePermNameVisible",
-                "NodePermNameVisitable",
-                "NodePermNameAnswerable"
-            ]
+        "domain.KBCapabilityInfo": {
+            "type": "object",
+            "properties": {
+                "capability": {
+                    "$ref": "#/definitions/consts.KBCapability"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/consts.UserKBPermission"
+                    }
+                }
+            }
         },
-        "consts.NodeRagInfoStatus": {
-            "type": "string",
-            "enum": [
-                "BASIC_PENDING",
-                "BASIC_RUNNING",
-                "BASIC_FAILED",
-                "BASIC_SUCCEEDED",
-                "ENHANCE_PENDING",
-                "ENHANCE_RUNNING",
-                "ENHANCE_FAILED",
-                "ENHANCE_SUCCEEDED"
-            ],
-            "x-enum-comments": {
-                "NodeRagStatusBasicFailed": "基础处理失败",
-                "NodeRagStatusBasicPending": "等待基础处理",
-                "NodeRagStatusBasicRunning": "正在进行基础处理（文本分割、向量化等）",
-                "NodeRagStatusBasicSucceeded": "基础处理成功",
-                "NodeRagStatusEnhanceFailed": "增强处理失败",
-                "NodeRagStatusEnhancePending": "基础处理完成，等待增强处理",
-                "NodeRagStatusEnhanceRunning": "正在进行增强处理（关键词提取等）",
-                "NodeRagStatusEnhanceSucceeded": "增强处理成功"
-            },
-            "x-enum-descriptions": [
-                "等待基础处理",
-                "正在进行基础处理（文本分割、向量化等）",
-                "基础处理失败",
-                "基础处理成功",
-                "基础处理完成，等待增强处理",
-                "正在进行增强处理（关键词提取等）",
-                "增强处理失败",
-                "增强处理成功"
-            ],
-            "x-enum-varnames": [
-                "NodeRagStatusBasicPending",
-                "NodeRagStatusBasicRunning",
-                "NodeRagStatusBasicFailed",
-                "NodeRagStatusBasicSucceeded",
-                "NodeRagStatusEnhancePending",
-                "NodeRagStatusEnhanceRunning",
-                "NodeRagStatusEnhanceFailed",
-                "NodeRagStatusEnhanceSucceeded"
-            ]
+        "domain.KBReleaseListItemResp": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kb_id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "tag": {
+                    "type": "string"
+                }
+            }
         },
-        "consts.RedeemCaptchaReq": {
+        "domain.KBRole": {
             "type": "object",
             "properties": {
-                "solutions": {
+                "capabilities": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "type": "string"
                     }
                 },
-                "token": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "consts.SourceType": {
-            "type": "string",
-            "enum": [
-                "dingtalk",
-                "feishu",
-                "wecom",
-                "oauth",
-                "github",
-                "cas",
-                "ldap",
-                "widget",
-                "dingtalk_bot",
-                "feishu_bot",
-                "lark_bot",
-                "wechat_bot",
-                "wecom_ai_bot",
-                "wechat_service_bot",
-                "discord_bot",
-                "wechat_official_account",
-                "openai_api"
-            ],
-            "x-enum-varnames": [
-                "SourceTypeDingTalk",
-                "SourceTypeFeishu",
-                "SourceTypeWeCom",
-                "SourceTypeOAuth",
-                "SourceTypeGitHub",
-                "SourceTypeCAS",
-                "SourceTypeLDAP",
-                "SourceTypeWidget",
-                "SourceTypeDingtalkBot",
-                "SourceTypeFeishuBot",
-                "SourceTypeLarkBot",
-                "SourceTypeWechatBot",
-                "SourceTypeWecomAIBot",
-                "SourceTypeWechatServiceBot",
-                "SourceTypeDiscordBot",
-                "SourceTypeWechatOfficialAccount",
-                "SourceTypeOpenAIAPI"
-            ]
-        },
-        "consts.StatDay": {
-            "type": "integer",
-            "enum": [
-                1,
-                7,
-                30,
-                90
-            ],
-            "x-enum-varnames": [
-                "StatDay1",
-                "StatDay7",
-                "StatDay30",
-                "StatDay90"
-            ]
-        },
-        "consts.UserKBPermission": {
-            "type": "string",
-            "enum": [
-                "",
-                "full_control",
-                "doc_manage",
-                "data_operate"
-            ],
-            "x-enum-comments": {
-                "UserKBPermissionDataOperate": "数据运营",
-                "UserKBPermissionDocManage": "文档管理",
-                "UserKBPermissionFullControl": "完全控制",
-                "UserKBPermissionNull": "无权限"
-            },
-            "x-enum-descriptions": [
-                "无权限",
-                "完全控制",
-                "文档管理",
-                "数据运营"
-            ],
-            "x-enum-varnames": [
-                "UserKBPermissionNull",
-                "UserKBPermissionFullControl",
-                "UserKBPermissionDocManage",
-                "UserKBPermissionDataOperate"
-            ]
+        "domain.KnowledgeBaseDetail": {
+            "type": "object",
+            "properties": {
+                "access_settings": {
+                    "$ref": "#/definitions/domain.AccessSettings"
+                },
+                "capabilities": {
+                    "description": "用户在知识库内的能力",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/consts.KBCapability"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dataset_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "perm": {
+                    "description": "用户对知识库的权限，自定义角色时为空",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/consts.UserKBPermission"
+                        }
+                    ]
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
         },
-        "consts.UserRole": {
-            "type": "string",
-            "enum": [
-                "admin",
-                "user"
-            ],
-            "x-enum-comments": {
-                "UserRoleAdmin": "管理员",
-                "UserRoleUser": "普通用户"
-            },
-            "x-enum-descriptions": [
-                "管理员",
-                "普通用户"
-            ],
-            "x-enum-varnames": [
-                "UserRoleAdmin",
-                "UserRoleUser"
-            ]
+        "domain.KnowledgeBaseListItem": {
+            "type": "object",
+            "properties": {
+                "access_settings": {
+                    "$ref": "#/definitions/domain.AccessSettings"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dataset_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
         },
-        "consts.WatermarkSetting": {
-            "type": "string",
-            "enum": [
-                "",
-                "hidden",
-                "visible"
-            ],
-            "x-enum-comments": {
-                "WatermarkDisabled": "未开启水印",
-                "WatermarkHidden": "隐形水印",
-                "WatermarkVisible": "显性水印"
-            },
-            "x-enum-descriptions": [
-                "未开启水印",
-                "隐形水印",
-                "显性水印"
+        "domain.LDAPAuthSetting": {
+            "type": "object",
+            "required": [
+                "bind_dn",
+                "server_url",
+                "user_base_dn"
             ],
-            "x-enum-varnames": [
-                "WatermarkDisabled",
-                "WatermarkHidden",
-                "WatermarkVisible"
-            ]
+            "properties": {
+                "bind_dn": {
+                    "description": "如 cn=admin,dc=company,dc=com",
+                    "type": "string"
+                },
+                "bind_password": {
+                    "description": "绑定密码",
+                    "type": "string"
+                },
+                "group_base_dn": {
+                    "description": "如 ou=Groups,dc=company,dc=com，同步用户组时必填",
+                    "type": "string"
+                },
+                "group_filter": {
+                    "description": "默认 groupOfNames、groupOfUniqueNames 及 posixGroup",
+                    "type": "string"
+                },
+                "group_member_attr": {
+                    "description": "默认 member",
+                    "type": "string"
+                },
+                "group_name_attr": {
+                    "description": "默认 cn",
+                    "type": "string"
+                },
+                "server_url": {
+                    "description": "如 ldap://openldap.company.com:389",
+                    "type": "string"
+                },
+                "user_base_dn": {
+                    "description": "如 ou=People,dc=company,dc=com",
+                    "type": "string"
+                },
+                "user_email_attr": {
+                    "description": "默认 mail",
+                    "type": "string"
+                },
+                "user_filter": {
+                    "description": "如 (\u0026(objectClass=person)(uid=%s))",
+                    "type": "string"
+                },
+                "user_id_attr": {
+                    "description": "默认 uid",
+                    "type": "string"
+                },
+                "user_name_attr": {
+                    "description": "默认 cn",
+                    "type": "string"
+                }
+            }
         },
-        "domain.AIFeedbackSettings": {
+        "domain.LarkBotSettings": {
             "type": "object",
             "properties": {
-                "ai_feedback_type": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "app_id": {
+                    "type": "string"
+                },
+                "app_secret": {
+                    "type": "string"
+                },
+                "encrypt_key": {
+                    "type": "string"
                 },
                 "is_enabled": {
                     "type": "boolean"
+                },
+                "verify_token": {
+                    "type": "string"
                 }
             }
         },
-        "domain.AccessSettings": {
+        "domain.Link": {
             "type": "object",
             "properties": {
-                "base_url": {
+                "name": {
                     "type": "string"
                 },
-                "enterprise_auth": {
-                    "$ref": "#/definitions/domain.EnterpriseAuth"
-                },
-                "hosts": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "is_forbidden": {
-                    "description": "禁止访问",
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.LoginSecurityPolicy": {
+            "type": "object",
+            "properties": {
+                "require_admin_2fa": {
+                    "description": "管理员必须开启两步验证，未开启的在下次登录时绑定",
                     "type": "boolean"
+                }
+            }
+        },
+        "domain.MailBotSettings": {
+            "type": "object",
+            "properties": {
+                "from_address": {
+                    "type": "string"
                 },
-                "ports": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "from_name": {
+                    "type": "string"
                 },
-                "private_key": {
+                "imap": {
+                    "description": "收信: 配置 IMAP 时轮询邮箱中的未读邮件, 否则通过 inbound 回调接收原始邮件",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.MailServerSettings"
+                        }
+                    ]
+                },
+                "inbound_token": {
+                    "description": "inbound 回调校验",
                     "type": "string"
                 },
-                "public_key": {
+                "is_enabled": {
+                    "type": "boolean"
+                },
+                "mailbox": {
+                    "description": "默认 INBOX",
                     "type": "string"
                 },
-                "simple_auth": {
-                    "$ref": "#/definitions/domain.SimpleAuth"
+                "poll_interval": {
+                    "description": "秒, 默认 60",
+                    "type": "integer"
                 },
-                "source_type": {
-                    "description": "企业认证来源",
+                "reply_mode": {
+                    "enum": [
+                        "auto",
+                        "draft"
+                    ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/consts.SourceType"
+                            "$ref": "#/definitions/consts.MailReplyMode"
                         }
                     ]
                 },
-                "ssl_ports": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
-                },
-                "trusted_proxies": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "smtp": {
+                    "description": "发信",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.MailServerSettings"
+                        }
+                    ]
                 }
             }
         },
-        "domain.AnydocUploadResp": {
+        "domain.MailReply": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "integer"
+                "answer": {
+                    "description": "markdown, rendered as text and html when sent",
+                    "type": "string"
                 },
-                "data": {
+                "app_id": {
                     "type": "string"
                 },
-                "err": {
+                "conversation_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "in_reply_to": {
+                    "type": "string"
+                },
+                "kb_id": {
+                    "type": "string"
+                },
+                "question": {
+                    "type": "string"
+                },
+                "references": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reviewer_id": {
+                    "type": "string"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/consts.MailReplyStatus"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "to_address": {
+                    "type": "string"
+                },
+                "to_name": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "domain.AppDetailResp": {
+        "domain.MailServerSettings": {
             "type": "object",
             "properties": {
-                "id": {
+                "host": {
                     "type": "string"
                 },
-                "kb_id": {
+                "password": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string"
+                "port": {
+                    "type": "integer"
                 },
-                "recommend_nodes": {
+                "security": {
+                    "enum": [
+                        "tls",
+                        "starttls",
+                        "none"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/consts.MailSecurity"
+                        }
+                    ]
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.MessageFrom": {
+            "type": "integer",
+            "enum": [
+                1,
+                2
+            ],
+            "x-enum-varnames": [
+                "MessageFromGroup",
+                "MessageFromPrivate"
+            ]
+        },
+        "domain.MetricsConfig": {
+            "type": "object",
+            "properties": {
+                "list": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/domain.RecommendNodeListResp"
+                        "type": "object",
+                        "properties": {
+                            "id": {
+                                "type": "string"
+                            },
+                            "name": {
+                                "type": "string"
+                            },
+                            "number": {
+                                "type": "string"
+                            }
+                        }
                     }
                 },
-                "settings": {
-                    "$ref": "#/definitions/domain.AppSettingsResp"
+                "title": {
+                    "type": "string"
                 },
                 "type": {
-                    "$ref": "#/definitions/domain.AppType"
+                    "type": "string"
                 }
             }
         },
-        "domain.AppInfoResp": {
+        "domain.ModelType": {
+            "type": "string",
+            "enum": [
+                "chat",
+                "embedding",
+                "rerank",
+                "analysis",
+                "analysis-vl"
+            ],
+            "x-enum-varnames": [
+                "ModelTypeChat",
+                "ModelTypeEmbedding",
+                "ModelTypeRerank",
+                "ModelTypeAnalysis",
+                "ModelTypeAnalysisVL"
+            ]
+        },
+        "domain.MoveNodeReq": {
             "type": "object",
+            "required": [
+                "id",
+                "kb_id"
+            ],
             "properties": {
-                "name": {
+                "id": {
                     "type": "string"
                 },
-                "recommend_nodes": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.RecommendNodeListResp"
-                    }
+                "kb_id": {
+                    "type": "string"
                 },
-                "settings": {
-                    "$ref": "#/definitions/domain.AppSettingsResp"
+                "next_id": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "prev_id": {
+                    "type": "string"
                 }
             }
         },
-        "domain.AppSettings": {
+        "domain.NodeAccessRequest": {
             "type": "object",
             "properties": {
-                "ai_feedback_settings": {
-                    "description": "AI feedback",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.AIFeedbackSettings"
-                        }
-                    ]
+                "auth_group_id": {
+                    "description": "GrantType 为 auth_group 时加入的用户组",
+                    "type": "integer"
                 },
-                "auto_sitemap": {
-                    "type": "boolean"
+                "auth_id": {
+                    "type": "integer"
                 },
-                "body_code": {
+                "created_at": {
                     "type": "string"
                 },
-                "btns": {
-                    "type": "array",
-                    "items": {}
-                },
-                "catalog_settings": {
-                    "description": "catalog settings",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.CatalogSettings"
-                        }
-                    ]
-                },
-                "contribute_settings": {
-                    "$ref": "#/definitions/domain.ContributeSettings"
-                },
-                "copy_setting": {
-                    "enum": [
-                        "",
-                        "append",
-                        "disabled"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/consts.CopySetting"
-                        }
-                    ]
-                },
-                "desc": {
-                    "description": "seo",
-                    "type": "string"
+                "grant_type": {
+                    "$ref": "#/definitions/consts.NodeAccessGrantType"
                 },
-                "dingtalk_bot_client_id": {
+                "id": {
                     "type": "string"
                 },
-                "dingtalk_bot_client_secret": {
+                "kb_id": {
                     "type": "string"
                 },
-                "dingtalk_bot_is_enabled": {
-                    "description": "DingTalkBot",
-                    "type": "boolean"
-                },
-                "dingtalk_bot_template_id": {
+                "node_id": {
                     "type": "string"
                 },
-                "disclaimer_settings": {
-                    "description": "Disclaimer Settings",
+                "perm": {
+                    "description": "visitable 或 answerable",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/domain.DisclaimerSettings"
+                            "$ref": "#/definitions/consts.NodePermName"
                         }
                     ]
                 },
-                "discord_bot_is_enabled": {
-                    "description": "DisCordBot",
-                    "type": "boolean"
-                },
-                "discord_bot_token": {
+                "reason": {
                     "type": "string"
                 },
-                "document_feedback_is_enabled": {
-                    "description": "document feedback",
-                    "type": "boolean"
-                },
-                "feishu_bot_app_id": {
+                "review_comment": {
                     "type": "string"
                 },
-                "feishu_bot_app_secret": {
+                "reviewed_at": {
                     "type": "string"
                 },
-                "feishu_bot_is_enabled": {
-                    "description": "FeishuBot",
-                    "type": "boolean"
+                "reviewer_id": {
+                    "type": "string"
                 },
-                "footer_settings": {
-                    "description": "footer settings",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.FooterSettings"
-                        }
+                "status": {
+                    "$ref": "#/definitions/consts.NodeAccessRequestStatus"
+                }
+            }
+        },
+        "domain.NodeActionReq": {
+            "type": "object",
+            "required": [
+                "action",
+                "ids",
+                "kb_id"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "delete"
                     ]
                 },
-                "head_code": {
-                    "description": "inject code",
-                    "type": "string"
-                },
-                "home_page_setting": {
-                    "$ref": "#/definitions/consts.HomePageSetting"
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "icon": {
+                "kb_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.NodeContentChunkSSE": {
+            "type": "object",
+            "properties": {
+                "emoji": {
                     "type": "string"
                 },
-                "keyword": {
+                "kb_id": {
                     "type": "string"
                 },
-                "lark_bot_settings": {
-                    "description": "LarkBot",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.LarkBotSettings"
-                        }
-                    ]
+                "name": {
+                    "type": "string"
                 },
-                "openai_api_bot_settings": {
-                    "description": "OpenAI API Bot settings",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.OpenAIAPIBotSettings"
-                        }
-                    ]
+                "node_id": {
+                    "type": "string"
                 },
-                "recommend_node_ids": {
+                "node_path_names": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "recommend_questions": {
+                "summary": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.NodeGroupDetail": {
+            "type": "object",
+            "properties": {
+                "auth_group_id": {
+                    "type": "integer"
+                },
+                "auth_ids": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "type": "integer"
                     }
                 },
-                "search_placeholder": {
+                "kb_id": {
                     "type": "string"
                 },
-                "theme_and_style": {
-                    "$ref": "#/definitions/domain.ThemeAndStyle"
-                },
-                "theme_mode": {
-                    "description": "theme",
+                "name": {
                     "type": "string"
                 },
-                "title": {
-                    "description": "nav",
+                "node_id": {
                     "type": "string"
                 },
-                "watermark_content": {
+                "perm": {
+                    "$ref": "#/definitions/consts.NodePermName"
+                }
+            }
+        },
+        "domain.NodeListItemResp": {
+            "type": "object",
+            "properties": {
+                "content_type": {
                     "type": "string"
                 },
-                "watermark_setting": {
-                    "enum": [
-                        "",
-                        "hidden",
-                        "visible"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/consts.WatermarkSetting"
-                        }
-                    ]
-                },
-                "web_app_comment_settings": {
-                    "description": "webapp comment settings",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.WebAppCommentSettings"
-                        }
-                    ]
-                },
-                "web_app_custom_style": {
-                    "description": "WebAppCustomStyle",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.WebAppCustomSettings"
-                        }
-                    ]
+                "created_at": {
+                    "type": "string"
                 },
-                "web_app_landing_configs": {
-                    "description": "WebAppLandingConfigs",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.WebAppLandingConfig"
-                    }
+                "creator": {
+                    "type": "string"
                 },
-                "web_app_landing_theme": {
-                    "$ref": "#/definitions/domain.WebAppLandingTheme"
+                "creator_id": {
+                    "type": "string"
                 },
-                "wechat_app_agent_id": {
+                "editor": {
                     "type": "string"
                 },
-                "wechat_app_corpid": {
+                "editor_id": {
                     "type": "string"
                 },
-                "wechat_app_encodingaeskey": {
+                "emoji": {
                     "type": "string"
                 },
-                "wechat_app_is_enabled": {
-                    "description": "WechatAppBot 企业微信机器人",
-                    "type": "boolean"
+                "fields": {
+                    "$ref": "#/definitions/domain.StringMap"
                 },
-                "wechat_app_secret": {
+                "id": {
                     "type": "string"
                 },
-                "wechat_app_token": {
+                "name": {
                     "type": "string"
                 },
-                "wechat_official_account_app_id": {
+                "owner": {
                     "type": "string"
                 },
-                "wechat_official_account_app_secret": {
+                "owner_id": {
                     "type": "string"
                 },
-                "wechat_official_account_encodingaeskey": {
+                "parent_id": {
                     "type": "string"
                 },
-                "wechat_official_account_is_enabled": {
-                    "description": "WechatOfficialAccount",
-                    "type": "boolean"
+                "permissions": {
+                    "$ref": "#/definitions/domain.NodePermissions"
                 },
-                "wechat_official_account_token": {
-                    "type": "string"
+                "position": {
+                    "type": "number"
                 },
-                "wechat_service_contain_keywords": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "rag_info": {
+                    "$ref": "#/definitions/domain.RagInfo"
                 },
-                "wechat_service_corpid": {
+                "review_interval": {
+                    "type": "integer"
+                },
+                "reviewed_at": {
                     "type": "string"
                 },
-                "wechat_service_encodingaeskey": {
+                "status": {
+                    "$ref": "#/definitions/domain.NodeStatus"
+                },
+                "summary": {
                     "type": "string"
                 },
-                "wechat_service_equal_keywords": {
+                "tags": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "wechat_service_is_enabled": {
-                    "description": "WechatServiceBot",
-                    "type": "boolean"
+                "type": {
+                    "$ref": "#/definitions/domain.NodeType"
                 },
-                "wechat_service_secret": {
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.NodeMeta": {
+            "type": "object",
+            "properties": {
+                "content_type": {
                     "type": "string"
                 },
-                "wechat_service_token": {
+                "emoji": {
                     "type": "string"
                 },
-                "wecom_ai_bot_settings": {
-                    "description": "WecomAIBotSettings 企业微信智能机器人",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.WecomAIBotSettings"
-                        }
-                    ]
+                "fields": {
+                    "description": "自定义字段, key 见 NodeMetaSchema",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
-                "welcome_str": {
-                    "description": "welcome",
+                "summary": {
                     "type": "string"
                 },
-                "widget_bot_settings": {
-                    "description": "Widget bot settings",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.WidgetBotSettings"
-                        }
-                    ]
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "domain.AppSettingsResp": {
+        "domain.NodeMetaField": {
             "type": "object",
+            "required": [
+                "key",
+                "name",
+                "type"
+            ],
             "properties": {
-                "ai_feedback_settings": {
-                    "description": "AI feedback",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.AIFeedbackSettings"
-                        }
-                    ]
-                },
-                "auto_sitemap": {
-                    "type": "boolean"
+                "key": {
+                    "type": "string"
                 },
-                "body_code": {
+                "name": {
                     "type": "string"
                 },
-                "btns": {
+                "options": {
+                    "description": "select only",
                     "type": "array",
-                    "items": {}
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "catalog_settings": {
-                    "description": "catalog settings",
+                "type": {
+                    "enum": [
+                        "text",
+                        "number",
+                        "date",
+                        "select"
+                    ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/domain.CatalogSettings"
+                            "$ref": "#/definitions/domain.NodeMetaFieldType"
                         }
                     ]
+                }
+            }
+        },
+        "domain.NodeMetaFieldType": {
+            "type": "string",
+            "enum": [
+                "text",
+                "number",
+                "date",
+                "select"
+            ],
+            "x-enum-comments": {
+                "NodeMetaFieldTypeDate": "2006-01-02"
+            },
+            "x-enum-descriptions": [
+                "2006-01-02"
+            ],
+            "x-enum-varnames": [
+                "NodeMetaFieldTypeText",
+                "NodeMetaFieldTypeNumber",
+                "NodeMetaFieldTypeDate",
+                "NodeMetaFieldTypeSelect"
+            ]
+        },
+        "domain.NodeMetaFilter": {
+            "type": "object",
+            "properties": {
+                "fields": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
-                "contribute_settings": {
-                    "$ref": "#/definitions/domain.ContributeSettings"
-                },
-                "copy_setting": {
-                    "$ref": "#/definitions/consts.CopySetting"
-                },
-                "desc": {
-                    "description": "seo",
-                    "type": "string"
-                },
-                "dingtalk_bot_client_id": {
-                    "type": "string"
-                },
-                "dingtalk_bot_client_secret": {
-                    "type": "string"
-                },
-                "dingtalk_bot_is_enabled": {
-                    "description": "DingTalkBot",
-                    "type": "boolean"
-                },
-                "dingtalk_bot_template_id": {
-                    "type": "string"
-                },
-                "disclaimer_settings": {
-                    "description": "Disclaimer Settings",
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "domain.NodePermissions": {
+            "type": "object",
+            "properties": {
+                "answerable": {
+                    "description": "可被问答",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/domain.DisclaimerSettings"
+                            "$ref": "#/definitions/consts.NodeAccessPerm"
                         }
                     ]
                 },
-                "discord_bot_is_enabled": {
-                    "description": "DisCordBot",
-                    "type": "boolean"
-                },
-                "discord_bot_token": {
-                    "type": "string"
-                },
-                "document_feedback_is_enabled": {
-                    "description": "document feedback",
-                    "type": "boolean"
-                },
-                "feishu_bot_app_id": {
-                    "type": "string"
-                },
-                "feishu_bot_app_secret": {
-                    "type": "string"
-                },
-                "feishu_bot_is_enabled": {
-                    "description": "FeishuBot",
-                    "type": "boolean"
-                },
-                "footer_settings": {
-                    "description": "footer settings",
+                "visible": {
+                    "description": "导航内可见",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/domain.FooterSettings"
+                            "$ref": "#/definitions/consts.NodeAccessPerm"
                         }
                     ]
                 },
-                "head_code": {
-                    "description": "inject code",
-                    "type": "string"
-                },
-                "home_page_setting": {
-                    "$ref": "#/definitions/consts.HomePageSetting"
-                },
-                "icon": {
-                    "type": "string"
-                },
-                "keyword": {
-                    "type": "string"
-                },
-                "lark_bot_settings": {
-                    "description": "LarkBot",
+                "visitable": {
+                    "description": "可被访问",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/domain.LarkBotSettings"
+                            "$ref": "#/definitions/consts.NodeAccessPerm"
                         }
                     ]
+                }
+            }
+        },
+        "domain.NodeStatus": {
+            "type": "integer",
+            "format": "int32",
+            "enum": [
+                1,
+                2
+            ],
+            "x-enum-varnames": [
+                "NodeStatusDraft",
+                "NodeStatusReleased"
+            ]
+        },
+        "domain.NodeSummaryReq": {
+            "type": "object",
+            "required": [
+                "ids",
+                "kb_id"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "kb_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.NodeType": {
+            "type": "integer",
+            "format": "int32",
+            "enum": [
+                1,
+                2
+            ],
+            "x-enum-varnames": [
+                "NodeTypeFolder",
+                "NodeTypeDocument"
+            ]
+        },
+        "domain.NotifyWebhook": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
                 },
-                "openai_api_bot_settings": {
-                    "description": "OpenAI API settings",
+                "secret": {
+                    "description": "加签密钥, 仅钉钉和飞书",
+                    "type": "string"
+                },
+                "type": {
+                    "enum": [
+                        "dingtalk",
+                        "feishu",
+                        "wecom",
+                        "generic"
+                    ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/domain.OpenAIAPIBotSettings"
+                            "$ref": "#/definitions/domain.NotifyWebhookType"
                         }
                     ]
                 },
-                "recommend_node_ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "recommend_questions": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "search_placeholder": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.NotifyWebhookType": {
+            "type": "string",
+            "enum": [
+                "dingtalk",
+                "feishu",
+                "wecom",
+                "generic"
+            ],
+            "x-enum-varnames": [
+                "NotifyWebhookTypeDingTalk",
+                "NotifyWebhookTypeFeishu",
+                "NotifyWebhookTypeWeCom",
+                "NotifyWebhookTypeGeneric"
+            ]
+        },
+        "domain.OAuthAuthSetting": {
+            "type": "object",
+            "properties": {
+                "authorize_url": {
                     "type": "string"
                 },
-                "theme_and_style": {
-                    "$ref": "#/definitions/domain.ThemeAndStyle"
-                },
-                "theme_mode": {
-                    "description": "theme",
+                "avatar_field": {
                     "type": "string"
                 },
-                "title": {
-                    "description": "nav",
+                "email_field": {
                     "type": "string"
                 },
-                "watermark_content": {
+                "id_field": {
                     "type": "string"
                 },
-                "watermark_setting": {
-                    "$ref": "#/definitions/consts.WatermarkSetting"
+                "issuer": {
+                    "type": "string"
                 },
-                "web_app_comment_settings": {
-                    "description": "webapp comment settings",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.WebAppCommentSettings"
-                        }
-                    ]
+                "jwks_url": {
+                    "type": "string"
                 },
-                "web_app_custom_style": {
-                    "description": "WebAppCustomStyle",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.WebAppCustomSettings"
-                        }
-                    ]
+                "name_field": {
+                    "type": "string"
                 },
-                "web_app_landing_configs": {
-                    "description": "WebApp Landing Settings",
+                "scopes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/domain.WebAppLandingConfigResp"
+                        "type": "string"
                     }
                 },
-                "web_app_landing_theme": {
-                    "$ref": "#/definitions/domain.WebAppLandingTheme"
-                },
-                "wechat_app_agent_id": {
-                    "type": "string"
-                },
-                "wechat_app_corpid": {
+                "token_url": {
                     "type": "string"
                 },
-                "wechat_app_encodingaeskey": {
+                "user_info_url": {
                     "type": "string"
-                },
-                "wechat_app_is_enabled": {
-                    "description": "WechatAppBot",
-                    "type": "boolean"
-                },
-                "wechat_app_secret": {
+                }
+            }
+        },
+        "domain.ObjectUploadResp": {
+            "type": "object",
+            "properties": {
+                "filename": {
                     "type": "string"
                 },
-                "wechat_app_token": {
+                "key": {
                     "type": "string"
+                }
+            }
+        },
+        "domain.OpenAIAPIBotSettings": {
+            "type": "object",
+            "properties": {
+                "is_enabled": {
+                    "type": "boolean"
                 },
-                "wechat_official_account_app_id": {
+                "secret_key": {
                     "type": "string"
+                }
+            }
+        },
+        "domain.OpenAIChoice": {
+            "type": "object",
+            "properties": {
+                "delta": {
+                    "description": "for streaming",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.OpenAIMessage"
+                        }
+                    ]
                 },
-                "wechat_official_account_app_secret": {
+                "finish_reason": {
                     "type": "string"
                 },
-                "wechat_official_account_encodingaeskey": {
-                    "type": "string"
+                "index": {
+                    "type": "integer"
                 },
-                "wechat_official_account_is_enabled": {
-                    "description": "WechatOfficialAccount",
-                    "type": "boolean"
+                "message": {
+                    "$ref": "#/definitions/domain.OpenAIMessage"
+                }
+            }
+        },
+        "domain.OpenAICompletionsRequest": {
+            "type": "object",
+            "required": [
+                "messages",
+                "model"
+            ],
+            "properties": {
+                "frequency_penalty": {
+                    "type": "number"
                 },
-                "wechat_official_account_token": {
-                    "type": "string"
+                "max_tokens": {
+                    "type": "integer"
                 },
-                "wechat_service_contain_keywords": {
+                "messages": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/domain.OpenAIMessage"
                     }
                 },
-                "wechat_service_corpid": {
+                "model": {
                     "type": "string"
                 },
-                "wechat_service_encodingaeskey": {
-                    "type": "string"
+                "presence_penalty": {
+                    "type": "number"
                 },
-                "wechat_service_equal_keywords": {
+                "response_format": {
+                    "$ref": "#/definitions/domain.OpenAIResponseFormat"
+                },
+                "stop": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "wechat_service_is_enabled": {
-                    "description": "WechatServiceBot",
+                "stream": {
                     "type": "boolean"
                 },
-                "wechat_service_secret": {
-                    "type": "string"
-                },
-                "wechat_service_token": {
-                    "type": "string"
-                },
-                "wecom_ai_bot_settings": {
-                    "$ref": "#/definitions/domain.WecomAIBotSettings"
+                "temperature": {
+                    "type": "number"
                 },
-                "welcome_str": {
-                    "description": "welcome",
-                    "type": "string"
+                "tool_choice": {
+                    "$ref": "#/definitions/domain.OpenAIToolChoice"
                 },
-                "widget_bot_settings": {
-                    "description": "WidgetBot",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.WidgetBotSettings"
-                        }
-                    ]
-                }
-            }
-        },
-        "domain.AppType": {
-            "type": "integer",
-            "format": "int32",
-            "enum": [
-                1,
-                2,
-                3,
-                4,
-                5,
-                6,
-                7,
-                8,
-                9,
-                10,
-                11
-            ],
-            "x-enum-varnames": [
-                "AppTypeWeb",
-                "AppTypeWidget",
-                "AppTypeDingTalkBot",
-                "AppTypeFeishuBot",
-                "AppTypeWechatBot",
-                "AppTypeWechatServiceBot",
-                "AppTypeDisCordBot",
-                "AppTypeWechatOfficialAccount",
-                "AppTypeOpenAIAPI",
-                "AppTypeWecomAIBot",
-                "AppTypeLarkBot"
-            ]
-        },
-        "domain.AuthUserInfo": {
-            "type": "object",
-            "properties": {
-                "avatar_url": {
-                    "type": "string"
+                "tools": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.OpenAITool"
+                    }
                 },
-                "email": {
-                    "type": "string"
+                "top_p": {
+                    "type": "number"
                 },
-                "username": {
+                "user": {
                     "type": "string"
                 }
             }
         },
-        "domain.BannerConfig": {
+        "domain.OpenAICompletionsResponse": {
             "type": "object",
             "properties": {
-                "bg_url": {
-                    "type": "string"
-                },
-                "btns": {
-                    "type": "array",
-                    "items": {
-                        "type": "object",
-                        "properties": {
-                            "href": {
-                                "type": "string"
-                            },
-                            "id": {
-                                "type": "string"
-                            },
-                            "text": {
-                                "type": "string"
-                            },
-                            "type": {
-                                "type": "string"
-                            }
-                        }
-                    }
-                },
-                "hot_search": {
+                "choices": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/domain.OpenAIChoice"
                     }
                 },
-                "placeholder": {
+                "created": {
+                    "type": "integer"
+                },
+                "id": {
                     "type": "string"
                 },
-                "subtitle": {
+                "model": {
                     "type": "string"
                 },
-                "subtitle_color": {
+                "object": {
                     "type": "string"
                 },
-                "subtitle_font_size": {
-                    "type": "integer"
+                "usage": {
+                    "$ref": "#/definitions/domain.OpenAIUsage"
+                }
+            }
+        },
+        "domain.OpenAIError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
                 },
-                "title": {
+                "message": {
                     "type": "string"
                 },
-                "title_color": {
+                "param": {
                     "type": "string"
                 },
-                "title_font_size": {
-                    "type": "integer"
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "domain.BasicDocConfig": {
+        "domain.OpenAIErrorResponse": {
             "type": "object",
             "properties": {
-                "bg_color": {
+                "error": {
+                    "$ref": "#/definitions/domain.OpenAIError"
+                }
+            }
+        },
+        "domain.OpenAIFunction": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
                     "type": "string"
                 },
-                "title": {
+                "name": {
                     "type": "string"
                 },
-                "title_color": {
+                "parameters": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "domain.OpenAIFunctionCall": {
+            "type": "object",
+            "required": [
+                "arguments",
+                "name"
+            ],
+            "properties": {
+                "arguments": {
+                    "type": "string"
+                },
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "domain.BatchMoveReq": {
+        "domain.OpenAIFunctionChoice": {
             "type": "object",
             "required": [
-                "ids",
-                "kb_id"
+                "name"
             ],
             "properties": {
-                "ids": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.OpenAIMessage": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "tool_call_id": {
+                    "type": "string"
+                },
+                "tool_calls": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/domain.OpenAIToolCall"
                     }
-                },
-                "kb_id": {
+                }
+            }
+        },
+        "domain.OpenAIResponseFormat": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "type": {
                     "type": "string"
+                }
+            }
+        },
+        "domain.OpenAITool": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "function": {
+                    "$ref": "#/definitions/domain.OpenAIFunction"
                 },
-                "parent_id": {
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "domain.BlockGridConfig": {
+        "domain.OpenAIToolCall": {
             "type": "object",
+            "required": [
+                "function",
+                "id",
+                "type"
+            ],
             "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "type": "object",
-                        "properties": {
-                            "id": {
-                                "type": "string"
-                            },
-                            "name": {
-                                "type": "string"
-                            },
-                            "url": {
-                                "type": "string"
-                            }
-                        }
-                    }
+                "function": {
+                    "$ref": "#/definitions/domain.OpenAIFunctionCall"
                 },
-                "title": {
+                "id": {
                     "type": "string"
                 },
                 "type": {
@@ -4992,63 +12657,69 @@ const docTemplate = `{
                 }
             }
         },
-        "domain.BrandGroup": {
+        "domain.OpenAIToolChoice": {
             "type": "object",
             "properties": {
-                "links": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.Link"
-                    }
+                "function": {
+                    "$ref": "#/definitions/domain.OpenAIFunctionChoice"
                 },
-                "name": {
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "domain.BrowserCount": {
+        "domain.OpenAIUsage": {
             "type": "object",
             "properties": {
-                "count": {
+                "completion_tokens": {
                     "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "prompt_tokens": {
+                    "type": "integer"
+                },
+                "total_tokens": {
+                    "type": "integer"
                 }
             }
         },
-        "domain.CarouselConfig": {
+        "domain.PWResponse": {
             "type": "object",
             "properties": {
-                "bg_color": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {},
+                "message": {
                     "type": "string"
                 },
-                "list": {
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "domain.PaginatedResult-array_domain_ConversationMessageListItem": {
+            "type": "object",
+            "properties": {
+                "data": {
                     "type": "array",
                     "items": {
-                        "type": "object",
-                        "properties": {
-                            "desc": {
-                                "type": "string"
-                            },
-                            "id": {
-                                "type": "string"
-                            },
-                            "title": {
-                                "type": "string"
-                            },
-                            "url": {
-                                "type": "string"
-                            }
-                        }
+                        "$ref": "#/definitions/domain.ConversationMessageListItem"
                     }
                 },
-                "title": {
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.ProviderModelListItem": {
+            "type": "object",
+            "properties": {
+                "model": {
                     "type": "string"
                 }
             }
         },
-        "domain.CaseConfig": {
+        "domain.QuestionConfig": {
             "type": "object",
             "properties": {
                 "list": {
@@ -5059,10 +12730,7 @@ const docTemplate = `{
                             "id": {
                                 "type": "string"
                             },
-                            "link": {
-                                "type": "string"
-                            },
-                            "name": {
+                            "question": {
                                 "type": "string"
                             }
                         }
@@ -5076,34 +12744,66 @@ const docTemplate = `{
                 }
             }
         },
-        "domain.CatalogSettings": {
+        "domain.RagInfo": {
             "type": "object",
             "properties": {
-                "catalog_folder": {
-                    "description": "1: 展开, 2: 折叠, default: 1",
-                    "type": "integer"
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/consts.NodeRagInfoStatus"
+                }
+            }
+        },
+        "domain.RecommendNodeListResp": {
+            "type": "object",
+            "properties": {
+                "emoji": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "$ref": "#/definitions/domain.NodePermissions"
+                },
+                "position": {
+                    "type": "number"
                 },
-                "catalog_visible": {
-                    "description": "1: 显示, 2: 隐藏, default: 1",
-                    "type": "integer"
+                "recommend_nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RecommendNodeListResp"
+                    }
                 },
-                "catalog_width": {
-                    "description": "200 - 300, default: 260",
-                    "type": "integer"
+                "summary": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/domain.NodeType"
                 }
             }
         },
-        "domain.ChatRequest": {
+        "domain.ReleaseBroadcast": {
             "type": "object",
             "required": [
-                "app_type",
-                "message"
+                "chat_id"
             ],
             "properties": {
                 "app_type": {
+                    "description": "AppType 使用该类型机器人应用的凭证发送: 钉钉 3, 飞书 4, 企业微信应用 5, Discord 7, Slack 12",
                     "enum": [
-                        1,
-                        2
+                        3,
+                        4,
+                        5,
+                        7,
+                        12
                     ],
                     "allOf": [
                         {
@@ -5111,101 +12811,136 @@ const docTemplate = `{
                         }
                     ]
                 },
-                "captcha_token": {
-                    "type": "string"
-                },
-                "conversation_id": {
+                "chat_id": {
+                    "description": "ChatID 飞书 chat_id, 钉钉 openConversationId, 企业微信群聊 chatid, Discord 及 Slack 频道 ID",
                     "type": "string"
                 },
-                "message": {
+                "name": {
                     "type": "string"
                 },
-                "nonce": {
-                    "type": "string"
+                "node_ids": {
+                    "description": "NodeIDs 仅推送这些目录下的文档, 为空时推送全部文档",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "domain.ChatSearchReq": {
+        "domain.Response": {
             "type": "object",
-            "required": [
-                "message"
-            ],
             "properties": {
-                "captcha_token": {
-                    "type": "string"
-                },
+                "data": {},
                 "message": {
                     "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
                 }
             }
         },
-        "domain.ChatSearchResp": {
+        "domain.SAMLAuthSetting": {
             "type": "object",
             "properties": {
-                "node_result": {
+                "allow_idp_initiated": {
+                    "type": "boolean"
+                },
+                "avatar_attr": {
+                    "type": "string"
+                },
+                "email_attr": {
+                    "description": "默认 email",
+                    "type": "string"
+                },
+                "entity_id": {
+                    "description": "默认为 SP 元数据地址",
+                    "type": "string"
+                },
+                "group_mappings": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/domain.NodeContentChunkSSE"
+                        "$ref": "#/definitions/domain.SAMLGroupMapping"
                     }
+                },
+                "groups_attr": {
+                    "description": "用户组属性，配合 GroupMappings 同步用户组成员",
+                    "type": "string"
+                },
+                "idp_metadata_url": {
+                    "type": "string"
+                },
+                "idp_metadata_xml": {
+                    "type": "string"
+                },
+                "sign_request": {
+                    "type": "boolean"
+                },
+                "sp_certificate": {
+                    "type": "string"
+                },
+                "sp_private_key": {
+                    "type": "string"
+                },
+                "username_attr": {
+                    "description": "默认 displayName",
+                    "type": "string"
                 }
             }
         },
-        "domain.CommentConfig": {
+        "domain.SAMLGroupMapping": {
             "type": "object",
+            "required": [
+                "group_id",
+                "value"
+            ],
             "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "type": "object",
-                        "properties": {
-                            "avatar": {
-                                "type": "string"
-                            },
-                            "comment": {
-                                "type": "string"
-                            },
-                            "id": {
-                                "type": "string"
-                            },
-                            "profession": {
-                                "type": "string"
-                            },
-                            "user_name": {
-                                "type": "string"
-                            }
-                        }
-                    }
-                },
-                "title": {
-                    "type": "string"
+                "group_id": {
+                    "type": "integer"
                 },
-                "type": {
+                "value": {
                     "type": "string"
                 }
             }
         },
-        "domain.CommentInfo": {
+        "domain.SMTPSettings": {
             "type": "object",
             "properties": {
-                "auth_user_id": {
-                    "type": "integer"
+                "enabled": {
+                    "type": "boolean"
                 },
-                "avatar": {
-                    "description": "avatar",
+                "from": {
                     "type": "string"
                 },
-                "email": {
+                "host": {
                     "type": "string"
                 },
-                "remote_ip": {
+                "password": {
                     "type": "string"
                 },
-                "user_name": {
+                "port": {
+                    "type": "integer"
+                },
+                "use_tls": {
+                    "description": "UseTLS 使用隐式 TLS 连接 (通常为 465 端口), 否则在服务端支持时使用 STARTTLS",
+                    "type": "boolean"
+                },
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "domain.CommentListItem": {
+        "domain.ScoreType": {
+            "type": "integer",
+            "enum": [
+                1,
+                -1
+            ],
+            "x-enum-varnames": [
+                "Like",
+                "DisLike"
+            ]
+        },
+        "domain.ShareCommentListItem": {
             "type": "object",
             "properties": {
                 "content": {
@@ -5228,658 +12963,845 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "kb_id": {
+                    "type": "string"
+                },
                 "node_id": {
                     "type": "string"
                 },
-                "node_name": {
-                    "description": "文档标题",
+                "parent_id": {
                     "type": "string"
                 },
-                "node_type": {
-                    "type": "integer"
+                "pic_urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
                 "root_id": {
                     "type": "string"
+                }
+            }
+        },
+        "domain.ShareConversationDetailResp": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
                 },
-                "status": {
-                    "description": "status : -1 reject 0 pending 1 accept",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.CommentStatus"
-                        }
-                    ]
+                "id": {
+                    "type": "string"
+                },
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ShareConversationMessage"
+                    }
+                },
+                "subject": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ShareConversationMessage": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/schema.RoleType"
+                }
+            }
+        },
+        "domain.ShareNodeListItemResp": {
+            "type": "object",
+            "properties": {
+                "emoji": {
+                    "type": "string"
+                },
+                "fields": {
+                    "$ref": "#/definitions/domain.StringMap"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "$ref": "#/definitions/domain.NodePermissions"
+                },
+                "position": {
+                    "type": "number"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "$ref": "#/definitions/domain.NodeType"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SimpleAuth": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SimpleDocConfig": {
+            "type": "object",
+            "properties": {
+                "bg_color": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "title_color": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SlackBotSettings": {
+            "type": "object",
+            "properties": {
+                "app_token": {
+                    "description": "xapp-, 填写后使用 Socket Mode, 否则使用 Events API 回调",
+                    "type": "string"
+                },
+                "bot_token": {
+                    "description": "xoxb-",
+                    "type": "string"
+                },
+                "is_enabled": {
+                    "type": "boolean"
+                },
+                "signing_secret": {
+                    "description": "Events API 回调签名校验",
+                    "type": "string"
                 }
             }
         },
-        "domain.CommentReq": {
+        "domain.SocialMediaAccount": {
             "type": "object",
-            "required": [
-                "content",
-                "node_id",
-                "pic_urls"
-            ],
             "properties": {
-                "captcha_token": {
+                "channel": {
                     "type": "string"
                 },
-                "content": {
+                "icon": {
                     "type": "string"
                 },
-                "node_id": {
+                "link": {
                     "type": "string"
                 },
-                "parent_id": {
+                "phone": {
                     "type": "string"
                 },
-                "pic_urls": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "root_id": {
+                "text": {
                     "type": "string"
-                },
-                "user_name": {
+                }
+            }
+        },
+        "domain.StatPageReq": {
+            "type": "object",
+            "required": [
+                "scene"
+            ],
+            "properties": {
+                "node_id": {
                     "type": "string"
+                },
+                "scene": {
+                    "enum": [
+                        1,
+                        2,
+                        3,
+                        4
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.StatPageScene"
+                        }
+                    ]
                 }
             }
         },
-        "domain.CommentStatus": {
+        "domain.StatPageScene": {
             "type": "integer",
-            "format": "int32",
             "enum": [
-                -1,
-                0,
-                1
+                1,
+                2,
+                3,
+                4
             ],
             "x-enum-varnames": [
-                "CommentStatusReject",
-                "CommentStatusPending",
-                "CommentStatusAccepted"
+                "StatPageSceneWelcome",
+                "StatPageSceneNodeDetail",
+                "StatPageSceneChat",
+                "StatPageSceneLogin"
             ]
         },
-        "domain.CompleteReq": {
+        "domain.StringMap": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "domain.TeamsBotSettings": {
             "type": "object",
             "properties": {
-                "prefix": {
-                    "description": "For FIM (Fill in Middle) style completion",
+                "app_id": {
+                    "description": "Microsoft App ID",
                     "type": "string"
                 },
-                "suffix": {
+                "app_password": {
+                    "description": "Client Secret",
+                    "type": "string"
+                },
+                "is_enabled": {
+                    "type": "boolean"
+                },
+                "tenant_id": {
+                    "description": "单租户应用填写, 多租户留空",
                     "type": "string"
                 }
             }
         },
-        "domain.ContributeSettings": {
+        "domain.TelegramBotSettings": {
             "type": "object",
             "properties": {
-                "is_enable": {
+                "bot_token": {
+                    "type": "string"
+                },
+                "is_enabled": {
                     "type": "boolean"
+                },
+                "secret_token": {
+                    "description": "webhook 模式必填, 校验请求头 X-Telegram-Bot-Api-Secret-Token",
+                    "type": "string"
+                },
+                "webhook_url": {
+                    "description": "填写后使用 webhook 模式, 否则使用长轮询",
+                    "type": "string"
                 }
             }
         },
-        "domain.ConversationDetailResp": {
+        "domain.TextConfig": {
             "type": "object",
             "properties": {
-                "app_id": {
+                "title": {
                     "type": "string"
                 },
-                "created_at": {
+                "type": {
                     "type": "string"
+                }
+            }
+        },
+        "domain.TextImgConfig": {
+            "type": "object",
+            "properties": {
+                "item": {
+                    "type": "object",
+                    "properties": {
+                        "desc": {
+                            "type": "string"
+                        },
+                        "name": {
+                            "type": "string"
+                        },
+                        "url": {
+                            "type": "string"
+                        }
+                    }
                 },
-                "id": {
+                "title": {
                     "type": "string"
                 },
-                "ip_address": {
-                    "$ref": "#/definitions/domain.IPAddress"
-                },
-                "messages": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.ConversationMessage"
-                    }
-                },
-                "references": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.ConversationReference"
-                    }
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.TextReq": {
+            "type": "object",
+            "required": [
+                "text"
+            ],
+            "properties": {
+                "action": {
+                    "description": "action: improve, summary, extend, shorten, etc.",
+                    "type": "string"
                 },
-                "remote_ip": {
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ThemeAndStyle": {
+            "type": "object",
+            "properties": {
+                "bg_image": {
                     "type": "string"
                 },
-                "subject": {
+                "doc_width": {
                     "type": "string"
                 }
             }
         },
-        "domain.ConversationDistribution": {
+        "domain.UpdateAppReq": {
             "type": "object",
             "properties": {
-                "app_id": {
+                "kb_id": {
                     "type": "string"
                 },
-                "app_type": {
-                    "$ref": "#/definitions/domain.AppType"
+                "name": {
+                    "type": "string"
                 },
-                "count": {
-                    "type": "integer"
+                "settings": {
+                    "$ref": "#/definitions/domain.AppSettings"
                 }
             }
         },
-        "domain.ConversationInfo": {
+        "domain.UpdateKnowledgeBaseReq": {
             "type": "object",
+            "required": [
+                "id"
+            ],
             "properties": {
-                "user_info": {
-                    "$ref": "#/definitions/domain.UserInfo"
+                "access_settings": {
+                    "$ref": "#/definitions/domain.AccessSettings"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
                 }
             }
         },
-        "domain.ConversationListItem": {
+        "domain.UpdateModelReq": {
             "type": "object",
+            "required": [
+                "base_url",
+                "id",
+                "model",
+                "provider",
+                "type"
+            ],
             "properties": {
-                "app_name": {
+                "api_header": {
                     "type": "string"
                 },
-                "app_type": {
-                    "$ref": "#/definitions/domain.AppType"
+                "api_key": {
+                    "type": "string"
                 },
-                "created_at": {
+                "api_version": {
+                    "description": "for azure openai",
                     "type": "string"
                 },
-                "feedback_info": {
-                    "description": "用户反馈信息",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.FeedBackInfo"
-                        }
-                    ]
+                "base_url": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "info": {
-                    "description": "用户信息",
+                "is_active": {
+                    "type": "boolean"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.ModelParam"
+                },
+                "provider": {
+                    "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.ModelProvider"
+                },
+                "type": {
+                    "enum": [
+                        "chat",
+                        "embedding",
+                        "rerank",
+                        "analysis",
+                        "analysis-vl"
+                    ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/domain.ConversationInfo"
+                            "$ref": "#/definitions/domain.ModelType"
                         }
                     ]
-                },
-                "ip_address": {
-                    "$ref": "#/definitions/domain.IPAddress"
-                },
-                "remote_ip": {
-                    "type": "string"
-                },
-                "subject": {
-                    "type": "string"
                 }
             }
         },
-        "domain.ConversationMessage": {
+        "domain.UpdateNodeReq": {
             "type": "object",
-            "properties": {
-                "app_id": {
-                    "type": "string"
-                },
-                "completion_tokens": {
-                    "type": "integer"
-                },
+            "required": [
+                "id",
+                "kb_id"
+            ],
+            "properties": {
                 "content": {
                     "type": "string"
                 },
-                "conversation_id": {
+                "content_type": {
                     "type": "string"
                 },
-                "created_at": {
+                "emoji": {
                     "type": "string"
                 },
+                "fields": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "id": {
                     "type": "string"
                 },
-                "info": {
-                    "description": "feedbackinfo",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.FeedBackInfo"
-                        }
-                    ]
-                },
                 "kb_id": {
                     "type": "string"
                 },
-                "model": {
+                "name": {
                     "type": "string"
                 },
-                "parent_id": {
-                    "description": "parent_id",
+                "owner_id": {
                     "type": "string"
                 },
-                "prompt_tokens": {
-                    "type": "integer"
+                "position": {
+                    "type": "number"
                 },
-                "provider": {
-                    "description": "model",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.ModelProvider"
-                        }
-                    ]
+                "review_interval": {
+                    "description": "days",
+                    "type": "integer",
+                    "maximum": 3650,
+                    "minimum": 0
                 },
-                "remote_ip": {
-                    "description": "stats",
+                "summary": {
                     "type": "string"
                 },
-                "role": {
-                    "$ref": "#/definitions/schema.RoleType"
-                },
-                "total_tokens": {
-                    "type": "integer"
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "domain.ConversationMessageListItem": {
+        "domain.UserInfo": {
             "type": "object",
             "properties": {
-                "app_id": {
-                    "type": "string"
-                },
-                "app_type": {
-                    "$ref": "#/definitions/domain.AppType"
+                "auth_user_id": {
+                    "type": "integer"
                 },
-                "conversation_id": {
+                "avatar": {
+                    "description": "avatar",
                     "type": "string"
                 },
-                "conversation_info": {
-                    "description": "userInfo",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.ConversationInfo"
-                        }
-                    ]
-                },
-                "created_at": {
-                    "type": "string"
+                "departments": {
+                    "description": "Departments 机器人渠道中用户所属部门 ID",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "id": {
+                "email": {
                     "type": "string"
                 },
-                "info": {
-                    "description": "feedbackInfo",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.FeedBackInfo"
-                        }
-                    ]
+                "from": {
+                    "$ref": "#/definitions/domain.MessageFrom"
                 },
-                "ip_address": {
-                    "$ref": "#/definitions/domain.IPAddress"
+                "name": {
+                    "type": "string"
                 },
-                "question": {
+                "real_name": {
                     "type": "string"
                 },
-                "remote_ip": {
-                    "description": "stats",
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "domain.ConversationReference": {
+        "domain.WebAppCommentSettings": {
             "type": "object",
             "properties": {
-                "app_id": {
-                    "type": "string"
-                },
-                "conversation_id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "node_id": {
-                    "type": "string"
+                "is_enable": {
+                    "type": "boolean"
                 },
-                "url": {
-                    "type": "string"
+                "moderation_enable": {
+                    "type": "boolean"
                 }
             }
         },
-        "domain.CreateKBReleaseReq": {
+        "domain.WebAppCustomSettings": {
             "type": "object",
-            "required": [
-                "kb_id",
-                "message",
-                "tag"
-            ],
             "properties": {
-                "kb_id": {
-                    "type": "string"
+                "allow_theme_switching": {
+                    "type": "boolean"
                 },
-                "message": {
+                "footer_show_intro": {
+                    "type": "boolean"
+                },
+                "header_search_placeholder": {
                     "type": "string"
                 },
-                "node_ids": {
-                    "description": "create release after these nodes published",
+                "show_brand_info": {
+                    "type": "boolean"
+                },
+                "social_media_accounts": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/domain.SocialMediaAccount"
                     }
-                },
-                "tag": {
-                    "type": "string"
                 }
             }
         },
-        "domain.CreateKnowledgeBaseReq": {
+        "domain.WebAppLandingConfig": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "hosts": {
+                "banner_config": {
+                    "$ref": "#/definitions/domain.BannerConfig"
+                },
+                "basic_doc_config": {
+                    "$ref": "#/definitions/domain.BasicDocConfig"
+                },
+                "block_grid_config": {
+                    "$ref": "#/definitions/domain.BlockGridConfig"
+                },
+                "carousel_config": {
+                    "$ref": "#/definitions/domain.CarouselConfig"
+                },
+                "case_config": {
+                    "$ref": "#/definitions/domain.CaseConfig"
+                },
+                "com_config_order": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "name": {
-                    "type": "string"
+                "comment_config": {
+                    "$ref": "#/definitions/domain.CommentConfig"
                 },
-                "ports": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "dir_doc_config": {
+                    "$ref": "#/definitions/domain.DirDocConfig"
                 },
-                "private_key": {
-                    "type": "string"
+                "faq_config": {
+                    "$ref": "#/definitions/domain.FaqConfig"
                 },
-                "public_key": {
-                    "type": "string"
+                "feature_config": {
+                    "$ref": "#/definitions/domain.FeatureConfig"
                 },
-                "ssl_ports": {
+                "img_text_config": {
+                    "$ref": "#/definitions/domain.ImgTextConfig"
+                },
+                "metrics_config": {
+                    "$ref": "#/definitions/domain.MetricsConfig"
+                },
+                "node_ids": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "type": "string"
                     }
-                }
-            }
-        },
-        "domain.CreateModelReq": {
-            "type": "object",
-            "required": [
-                "base_url",
-                "model",
-                "provider",
-                "type"
-            ],
-            "properties": {
-                "api_header": {
-                    "type": "string"
-                },
-                "api_key": {
-                    "type": "string"
-                },
-                "api_version": {
-                    "description": "for azure openai",
-                    "type": "string"
                 },
-                "base_url": {
-                    "type": "string"
+                "question_config": {
+                    "$ref": "#/definitions/domain.QuestionConfig"
                 },
-                "model": {
-                    "type": "string"
+                "simple_doc_config": {
+                    "$ref": "#/definitions/domain.SimpleDocConfig"
                 },
-                "parameters": {
-                    "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.ModelParam"
+                "text_config": {
+                    "$ref": "#/definitions/domain.TextConfig"
                 },
-                "provider": {
-                    "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.ModelProvider"
+                "text_img_config": {
+                    "$ref": "#/definitions/domain.TextImgConfig"
                 },
                 "type": {
-                    "enum": [
-                        "chat",
-                        "embedding",
-                        "rerank",
-                        "analysis",
-                        "analysis-vl"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.ModelType"
-                        }
-                    ]
+                    "type": "string"
                 }
             }
         },
-        "domain.CreateNodeReq": {
+        "domain.WebAppLandingConfigResp": {
             "type": "object",
-            "required": [
-                "kb_id",
-                "name",
-                "type"
-            ],
             "properties": {
-                "content": {
-                    "type": "string"
+                "banner_config": {
+                    "$ref": "#/definitions/domain.BannerConfig"
+                },
+                "basic_doc_config": {
+                    "$ref": "#/definitions/domain.BasicDocConfig"
+                },
+                "block_grid_config": {
+                    "$ref": "#/definitions/domain.BlockGridConfig"
+                },
+                "carousel_config": {
+                    "$ref": "#/definitions/domain.CarouselConfig"
                 },
-                "content_type": {
-                    "type": "string"
+                "case_config": {
+                    "$ref": "#/definitions/domain.CaseConfig"
                 },
-                "emoji": {
-                    "type": "string"
+                "com_config_order": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "kb_id": {
-                    "type": "string"
+                "comment_config": {
+                    "$ref": "#/definitions/domain.CommentConfig"
                 },
-                "name": {
-                    "type": "string"
+                "dir_doc_config": {
+                    "$ref": "#/definitions/domain.DirDocConfig"
                 },
-                "parent_id": {
-                    "type": "string"
+                "faq_config": {
+                    "$ref": "#/definitions/domain.FaqConfig"
                 },
-                "position": {
-                    "type": "number"
+                "feature_config": {
+                    "$ref": "#/definitions/domain.FeatureConfig"
                 },
-                "summary": {
-                    "type": "string"
+                "img_text_config": {
+                    "$ref": "#/definitions/domain.ImgTextConfig"
                 },
-                "type": {
-                    "enum": [
-                        1,
-                        2
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.NodeType"
-                        }
-                    ]
-                }
-            }
-        },
-        "domain.DirDocConfig": {
-            "type": "object",
-            "properties": {
-                "bg_color": {
-                    "type": "string"
+                "metrics_config": {
+                    "$ref": "#/definitions/domain.MetricsConfig"
                 },
-                "title": {
-                    "type": "string"
+                "node_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "title_color": {
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RecommendNodeListResp"
+                    }
+                },
+                "question_config": {
+                    "$ref": "#/definitions/domain.QuestionConfig"
+                },
+                "simple_doc_config": {
+                    "$ref": "#/definitions/domain.SimpleDocConfig"
+                },
+                "text_config": {
+                    "$ref": "#/definitions/domain.TextConfig"
+                },
+                "text_img_config": {
+                    "$ref": "#/definitions/domain.TextImgConfig"
+                },
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "domain.DisclaimerSettings": {
+        "domain.WebAppLandingTheme": {
             "type": "object",
             "properties": {
-                "content": {
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "domain.EnterpriseAuth": {
+        "domain.WecomAIBotSettings": {
             "type": "object",
             "properties": {
-                "enabled": {
+                "encodingaeskey": {
+                    "type": "string"
+                },
+                "is_enabled": {
                     "type": "boolean"
+                },
+                "token": {
+                    "type": "string"
                 }
             }
         },
-        "domain.FaqConfig": {
+        "domain.WidgetBotSettings": {
             "type": "object",
             "properties": {
-                "bg_color": {
+                "btn_logo": {
                     "type": "string"
                 },
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "type": "object",
-                        "properties": {
-                            "id": {
-                                "type": "string"
-                            },
-                            "link": {
-                                "type": "string"
-                            },
-                            "question": {
-                                "type": "string"
-                            }
-                        }
-                    }
-                },
-                "title": {
+                "btn_text": {
                     "type": "string"
                 },
-                "title_color": {
+                "is_open": {
+                    "type": "boolean"
+                },
+                "theme_mode": {
                     "type": "string"
                 }
             }
         },
-        "domain.FeatureConfig": {
+        "github_com_chaitin_panda-wiki_api_auth_v1.AuthGetResp": {
             "type": "object",
             "properties": {
-                "list": {
+                "auths": {
                     "type": "array",
                     "items": {
-                        "type": "object",
-                        "properties": {
-                            "desc": {
-                                "type": "string"
-                            },
-                            "id": {
-                                "type": "string"
-                            },
-                            "name": {
-                                "type": "string"
-                            }
-                        }
+                        "$ref": "#/definitions/v1.AuthItem"
                     }
                 },
-                "title": {
+                "cas": {
+                    "$ref": "#/definitions/domain.CASAuthSetting"
+                },
+                "client_id": {
                     "type": "string"
                 },
-                "type": {
+                "client_secret": {
+                    "type": "string"
+                },
+                "group_sync": {
+                    "type": "boolean"
+                },
+                "ldap": {
+                    "$ref": "#/definitions/domain.LDAPAuthSetting"
+                },
+                "oauth": {
+                    "$ref": "#/definitions/domain.OAuthAuthSetting"
+                },
+                "proxy": {
                     "type": "string"
+                },
+                "saml": {
+                    "$ref": "#/definitions/domain.SAMLAuthSetting"
+                },
+                "source_type": {
+                    "$ref": "#/definitions/consts.SourceType"
                 }
             }
         },
-        "domain.FeedBackInfo": {
+        "github_com_chaitin_panda-wiki_api_node_v1.AccessRequestItem": {
             "type": "object",
             "properties": {
-                "feedback_content": {
+                "auth_avatar_url": {
                     "type": "string"
                 },
-                "feedback_type": {
+                "auth_group_id": {
+                    "description": "GrantType 为 auth_group 时加入的用户组",
+                    "type": "integer"
+                },
+                "auth_group_name": {
                     "type": "string"
                 },
-                "score": {
-                    "$ref": "#/definitions/domain.ScoreType"
-                }
-            }
-        },
-        "domain.FeedbackRequest": {
-            "type": "object",
-            "required": [
-                "message_id"
-            ],
-            "properties": {
-                "conversation_id": {
+                "auth_id": {
+                    "type": "integer"
+                },
+                "auth_name": {
                     "type": "string"
                 },
-                "feedback_content": {
-                    "description": "限制内容长度",
-                    "type": "string",
-                    "maxLength": 200
+                "created_at": {
+                    "type": "string"
                 },
-                "message_id": {
+                "grant_type": {
+                    "$ref": "#/definitions/consts.NodeAccessGrantType"
+                },
+                "id": {
                     "type": "string"
                 },
-                "score": {
-                    "description": "-1 踩 ,0 1 赞成",
+                "kb_id": {
+                    "type": "string"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "node_name": {
+                    "type": "string"
+                },
+                "perm": {
+                    "description": "visitable 或 answerable",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/domain.ScoreType"
+                            "$ref": "#/definitions/consts.NodePermName"
                         }
                     ]
                 },
-                "type": {
-                    "description": "内容不准确，没有帮助，.......",
+                "reason": {
+                    "type": "string"
+                },
+                "review_comment": {
                     "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewer_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/consts.NodeAccessRequestStatus"
                 }
             }
         },
-        "domain.FooterSettings": {
+        "github_com_chaitin_panda-wiki_api_share_v1.AccessRequestItem": {
             "type": "object",
             "properties": {
-                "brand_desc": {
+                "created_at": {
                     "type": "string"
                 },
-                "brand_groups": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.BrandGroup"
-                    }
+                "id": {
+                    "type": "string"
                 },
-                "brand_logo": {
+                "node_id": {
                     "type": "string"
                 },
-                "brand_name": {
+                "node_name": {
                     "type": "string"
                 },
-                "corp_name": {
+                "perm": {
+                    "$ref": "#/definitions/consts.NodePermName"
+                },
+                "reason": {
                     "type": "string"
                 },
-                "footer_style": {
+                "review_comment": {
                     "type": "string"
                 },
-                "icp": {
+                "reviewed_at": {
                     "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/consts.NodeAccessRequestStatus"
                 }
             }
         },
-        "domain.GetKBReleaseListResp": {
+        "github_com_chaitin_panda-wiki_api_share_v1.AuthGetResp": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.KBReleaseListItemResp"
-                    }
+                "auth_type": {
+                    "$ref": "#/definitions/consts.AuthType"
                 },
-                "total": {
-                    "type": "integer"
+                "license_edition": {
+                    "$ref": "#/definitions/consts.LicenseEdition"
+                },
+                "source_type": {
+                    "$ref": "#/definitions/consts.SourceType"
                 }
             }
         },
-        "domain.GetProviderModelListReq": {
+        "github_com_chaitin_panda-wiki_api_share_v1.GitHubCallbackResp": {
+            "type": "object"
+        },
+        "github_com_chaitin_panda-wiki_domain.CheckModelReq": {
             "type": "object",
             "required": [
                 "base_url",
+                "model",
                 "provider",
                 "type"
             ],
@@ -5887,14 +13809,24 @@ const docTemplate = `{
                 "api_header": {
                     "type": "string"
                 },
-                "api_key": {
+                "api_key": {
+                    "type": "string"
+                },
+                "api_version": {
+                    "description": "for azure openai",
+                    "type": "string"
+                },
+                "base_url": {
                     "type": "string"
                 },
-                "base_url": {
+                "model": {
                     "type": "string"
                 },
+                "parameters": {
+                    "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.ModelParam"
+                },
                 "provider": {
-                    "type": "string"
+                    "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.ModelProvider"
                 },
                 "type": {
                     "enum": [
@@ -5912,857 +13844,955 @@ const docTemplate = `{
                 }
             }
         },
-        "domain.GetProviderModelListResp": {
+        "github_com_chaitin_panda-wiki_domain.CheckModelResp": {
             "type": "object",
             "properties": {
-                "models": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.ProviderModelListItem"
-                    }
+                "content": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
                 }
             }
         },
-        "domain.HotBrowser": {
+        "github_com_chaitin_panda-wiki_domain.ModelListItem": {
             "type": "object",
             "properties": {
-                "browser": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.BrowserCount"
-                    }
+                "api_header": {
+                    "type": "string"
                 },
-                "os": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.BrowserCount"
-                    }
+                "api_key": {
+                    "type": "string"
+                },
+                "api_version": {
+                    "description": "for azure openai",
+                    "type": "string"
+                },
+                "base_url": {
+                    "type": "string"
+                },
+                "completion_tokens": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.ModelParam"
+                },
+                "prompt_tokens": {
+                    "type": "integer"
+                },
+                "provider": {
+                    "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.ModelProvider"
+                },
+                "total_tokens": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/domain.ModelType"
                 }
             }
         },
-        "domain.HotPage": {
+        "github_com_chaitin_panda-wiki_domain.ModelParam": {
             "type": "object",
             "properties": {
-                "count": {
+                "context_window": {
                     "type": "integer"
                 },
-                "node_id": {
-                    "type": "string"
+                "max_tokens": {
+                    "type": "integer"
                 },
-                "node_name": {
-                    "type": "string"
+                "r1_enabled": {
+                    "type": "boolean"
                 },
-                "scene": {
-                    "$ref": "#/definitions/domain.StatPageScene"
+                "support_computer_use": {
+                    "type": "boolean"
+                },
+                "support_images": {
+                    "type": "boolean"
+                },
+                "support_prompt_cache": {
+                    "type": "boolean"
+                },
+                "temperature": {
+                    "type": "number"
                 }
             }
         },
-        "domain.HotRefererHost": {
+        "github_com_chaitin_panda-wiki_domain.ModelProvider": {
+            "type": "string",
+            "enum": [
+                "BaiZhiCloud"
+            ],
+            "x-enum-varnames": [
+                "ModelProviderBrandBaiZhiCloud"
+            ]
+        },
+        "gocap.ChallengeData": {
             "type": "object",
             "properties": {
-                "count": {
+                "challenge": {
+                    "$ref": "#/definitions/gocap.ChallengeItem"
+                },
+                "expires": {
+                    "description": "过期时间,毫秒级时间戳",
                     "type": "integer"
                 },
-                "referer_host": {
+                "token": {
+                    "description": "质询令牌",
                     "type": "string"
                 }
             }
         },
-        "domain.IPAddress": {
+        "gocap.ChallengeItem": {
             "type": "object",
             "properties": {
-                "city": {
-                    "type": "string"
-                },
-                "country": {
-                    "type": "string"
+                "c": {
+                    "description": "质询数量",
+                    "type": "integer"
                 },
-                "ip": {
-                    "type": "string"
+                "d": {
+                    "description": "质询难度",
+                    "type": "integer"
                 },
-                "province": {
-                    "type": "string"
+                "s": {
+                    "description": "质询大小",
+                    "type": "integer"
                 }
             }
         },
-        "domain.ImgTextConfig": {
+        "gocap.VerificationResult": {
             "type": "object",
             "properties": {
-                "item": {
-                    "type": "object",
-                    "properties": {
-                        "desc": {
-                            "type": "string"
-                        },
-                        "name": {
-                            "type": "string"
-                        },
-                        "url": {
-                            "type": "string"
-                        }
-                    }
+                "expires": {
+                    "description": "过期时间,毫秒级时间戳",
+                    "type": "integer"
                 },
-                "title": {
+                "message": {
                     "type": "string"
                 },
-                "type": {
+                "success": {
+                    "type": "boolean"
+                },
+                "token": {
+                    "description": "验证令牌",
                     "type": "string"
                 }
             }
         },
-        "domain.InstantCountResp": {
+        "schema.RoleType": {
+            "type": "string",
+            "enum": [
+                "assistant",
+                "user",
+                "system",
+                "tool"
+            ],
+            "x-enum-varnames": [
+                "Assistant",
+                "User",
+                "System",
+                "Tool"
+            ]
+        },
+        "scim.AuthenticationScheme": {
             "type": "object",
             "properties": {
-                "count": {
-                    "type": "integer"
+                "description": {
+                    "type": "string"
                 },
-                "time": {
+                "name": {
+                    "type": "string"
+                },
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "domain.InstantPageResp": {
+        "scim.Group": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "displayName": {
                     "type": "string"
                 },
-                "info": {
-                    "$ref": "#/definitions/domain.AuthUserInfo"
-                },
-                "ip": {
+                "externalId": {
                     "type": "string"
                 },
-                "ip_address": {
-                    "$ref": "#/definitions/domain.IPAddress"
-                },
-                "node_id": {
+                "id": {
                     "type": "string"
                 },
-                "node_name": {
-                    "type": "string"
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scim.MultiValue"
+                    }
                 },
-                "scene": {
-                    "$ref": "#/definitions/domain.StatPageScene"
+                "meta": {
+                    "$ref": "#/definitions/scim.Meta"
                 },
-                "user_id": {
-                    "type": "integer"
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "urn:ietf:params:scim:schemas:extension:pandawiki:2.0:Group": {
+                    "$ref": "#/definitions/scim.GroupExtension"
                 }
             }
         },
-        "domain.KBReleaseListItemResp": {
+        "scim.GroupExtension": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
+                "parent": {
                     "type": "string"
+                }
+            }
+        },
+        "scim.ListResponse": {
+            "type": "object",
+            "properties": {
+                "Resources": {},
+                "itemsPerPage": {
+                    "type": "integer"
                 },
-                "kb_id": {
-                    "type": "string"
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "message": {
-                    "type": "string"
+                "startIndex": {
+                    "type": "integer"
                 },
-                "tag": {
-                    "type": "string"
+                "totalResults": {
+                    "type": "integer"
                 }
             }
         },
-        "domain.KnowledgeBaseDetail": {
+        "scim.Meta": {
             "type": "object",
             "properties": {
-                "access_settings": {
-                    "$ref": "#/definitions/domain.AccessSettings"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "dataset_id": {
+                "created": {
                     "type": "string"
                 },
-                "id": {
+                "lastModified": {
                     "type": "string"
                 },
-                "name": {
+                "location": {
                     "type": "string"
                 },
-                "perm": {
-                    "description": "用户对知识库的权限",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/consts.UserKBPermission"
-                        }
-                    ]
-                },
-                "updated_at": {
+                "resourceType": {
                     "type": "string"
                 }
             }
         },
-        "domain.KnowledgeBaseListItem": {
+        "scim.MultiValue": {
             "type": "object",
             "properties": {
-                "access_settings": {
-                    "$ref": "#/definitions/domain.AccessSettings"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "dataset_id": {
+                "display": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
+                "primary": {
+                    "type": "boolean"
                 },
-                "name": {
+                "type": {
                     "type": "string"
                 },
-                "updated_at": {
+                "value": {
                     "type": "string"
                 }
             }
         },
-        "domain.LarkBotSettings": {
+        "scim.Name": {
             "type": "object",
             "properties": {
-                "app_id": {
-                    "type": "string"
-                },
-                "app_secret": {
+                "familyName": {
                     "type": "string"
                 },
-                "encrypt_key": {
+                "formatted": {
                     "type": "string"
                 },
-                "is_enabled": {
-                    "type": "boolean"
-                },
-                "verify_token": {
+                "givenName": {
                     "type": "string"
                 }
             }
         },
-        "domain.Link": {
+        "scim.PatchOperation": {
             "type": "object",
             "properties": {
-                "name": {
+                "op": {
                     "type": "string"
                 },
-                "url": {
+                "path": {
                     "type": "string"
+                },
+                "value": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "domain.MessageFrom": {
-            "type": "integer",
-            "enum": [
-                1,
-                2
-            ],
-            "x-enum-varnames": [
-                "MessageFromGroup",
-                "MessageFromPrivate"
-            ]
-        },
-        "domain.MetricsConfig": {
+        "scim.PatchRequest": {
             "type": "object",
             "properties": {
-                "list": {
+                "Operations": {
                     "type": "array",
                     "items": {
-                        "type": "object",
-                        "properties": {
-                            "id": {
-                                "type": "string"
-                            },
-                            "name": {
-                                "type": "string"
-                            },
-                            "number": {
-                                "type": "string"
-                            }
-                        }
+                        "$ref": "#/definitions/scim.PatchOperation"
                     }
                 },
-                "title": {
-                    "type": "string"
-                },
-                "type": {
-                    "type": "string"
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "domain.ModelType": {
-            "type": "string",
-            "enum": [
-                "chat",
-                "embedding",
-                "rerank",
-                "analysis",
-                "analysis-vl"
-            ],
-            "x-enum-varnames": [
-                "ModelTypeChat",
-                "ModelTypeEmbedding",
-                "ModelTypeRerank",
-                "ModelTypeAnalysis",
-                "ModelTypeAnalysisVL"
-            ]
-        },
-        "domain.MoveNodeReq": {
+        "scim.ServiceProviderConfig": {
             "type": "object",
-            "required": [
-                "id",
-                "kb_id"
-            ],
             "properties": {
-                "id": {
-                    "type": "string"
+                "authenticationSchemes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scim.AuthenticationScheme"
+                    }
                 },
-                "kb_id": {
-                    "type": "string"
+                "bulk": {
+                    "$ref": "#/definitions/scim.bulkSupported"
                 },
-                "next_id": {
-                    "type": "string"
+                "changePassword": {
+                    "$ref": "#/definitions/scim.supported"
                 },
-                "parent_id": {
-                    "type": "string"
+                "etag": {
+                    "$ref": "#/definitions/scim.supported"
                 },
-                "prev_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "domain.NodeActionReq": {
-            "type": "object",
-            "required": [
-                "action",
-                "ids",
-                "kb_id"
-            ],
-            "properties": {
-                "action": {
-                    "type": "string",
-                    "enum": [
-                        "delete"
-                    ]
+                "filter": {
+                    "$ref": "#/definitions/scim.filterSupported"
                 },
-                "ids": {
+                "patch": {
+                    "$ref": "#/definitions/scim.supported"
+                },
+                "schemas": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "kb_id": {
-                    "type": "string"
+                "sort": {
+                    "$ref": "#/definitions/scim.supported"
                 }
             }
         },
-        "domain.NodeContentChunkSSE": {
+        "scim.User": {
             "type": "object",
             "properties": {
-                "emoji": {
+                "active": {
+                    "description": "未提供时视为启用",
+                    "type": "boolean"
+                },
+                "displayName": {
                     "type": "string"
                 },
-                "name": {
+                "emails": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scim.MultiValue"
+                    }
+                },
+                "externalId": {
                     "type": "string"
                 },
-                "node_id": {
+                "id": {
                     "type": "string"
                 },
-                "node_path_names": {
+                "meta": {
+                    "$ref": "#/definitions/scim.Meta"
+                },
+                "name": {
+                    "$ref": "#/definitions/scim.Name"
+                },
+                "schemas": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "summary": {
+                "userName": {
                     "type": "string"
                 }
             }
         },
-        "domain.NodeGroupDetail": {
+        "scim.bulkSupported": {
             "type": "object",
             "properties": {
-                "auth_group_id": {
+                "maxOperations": {
                     "type": "integer"
                 },
-                "auth_ids": {
+                "maxPayloadSize": {
+                    "type": "integer"
+                },
+                "supported": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "scim.filterSupported": {
+            "type": "object",
+            "properties": {
+                "maxResults": {
+                    "type": "integer"
+                },
+                "supported": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "scim.supported": {
+            "type": "object",
+            "properties": {
+                "supported": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "share.ShareCommentLists": {
+            "type": "object",
+            "properties": {
+                "data": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/domain.ShareCommentListItem"
                     }
                 },
-                "kb_id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "node_id": {
-                    "type": "string"
-                },
-                "perm": {
-                    "$ref": "#/definitions/consts.NodePermName"
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "domain.NodeListItemResp": {
+        "v1.AccessGrantItem": {
             "type": "object",
             "properties": {
-                "content_type": {
+                "auth_avatar_url": {
                     "type": "string"
                 },
-                "created_at": {
-                    "type": "string"
-                },
-                "creator": {
-                    "type": "string"
-                },
-                "creator_id": {
-                    "type": "string"
+                "auth_id": {
+                    "type": "integer"
                 },
-                "editor": {
+                "auth_name": {
                     "type": "string"
                 },
-                "editor_id": {
+                "created_at": {
                     "type": "string"
                 },
-                "emoji": {
+                "created_by": {
                     "type": "string"
                 },
                 "id": {
-                    "type": "string"
+                    "type": "integer"
                 },
-                "name": {
+                "kb_id": {
                     "type": "string"
                 },
-                "parent_id": {
+                "node_id": {
                     "type": "string"
                 },
-                "permissions": {
-                    "$ref": "#/definitions/domain.NodePermissions"
-                },
-                "position": {
-                    "type": "number"
-                },
-                "rag_info": {
-                    "$ref": "#/definitions/domain.RagInfo"
-                },
-                "status": {
-                    "$ref": "#/definitions/domain.NodeStatus"
-                },
-                "summary": {
+                "node_name": {
                     "type": "string"
                 },
-                "type": {
-                    "$ref": "#/definitions/domain.NodeType"
+                "perm": {
+                    "$ref": "#/definitions/consts.NodePermName"
                 },
-                "updated_at": {
+                "request_id": {
                     "type": "string"
                 }
             }
         },
-        "domain.NodeMeta": {
+        "v1.AccessRequestCreateReq": {
             "type": "object",
+            "required": [
+                "node_id",
+                "perm"
+            ],
             "properties": {
-                "content_type": {
-                    "type": "string"
-                },
-                "emoji": {
+                "node_id": {
                     "type": "string"
                 },
-                "summary": {
-                    "type": "string"
-                }
-            }
-        },
-        "domain.NodePermissions": {
-            "type": "object",
-            "properties": {
-                "answerable": {
-                    "description": "可被问答",
+                "perm": {
+                    "enum": [
+                        "visitable",
+                        "answerable"
+                    ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/consts.NodeAccessPerm"
+                            "$ref": "#/definitions/consts.NodePermName"
                         }
                     ]
                 },
-                "visible": {
-                    "description": "导航内可见",
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "v1.AccessRequestDecideReq": {
+            "type": "object",
+            "required": [
+                "action",
+                "id",
+                "kb_id"
+            ],
+            "properties": {
+                "action": {
+                    "enum": [
+                        "approve",
+                        "reject"
+                    ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/consts.NodeAccessPerm"
+                            "$ref": "#/definitions/consts.NodeAccessRequestAction"
                         }
                     ]
                 },
-                "visitable": {
-                    "description": "可被访问",
+                "auth_group_id": {
+                    "type": "integer"
+                },
+                "comment": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "grant_type": {
+                    "description": "GrantType 批准时必填，可问答权限只能通过用户组授予",
+                    "enum": [
+                        "auth_group",
+                        "node"
+                    ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/consts.NodeAccessPerm"
+                            "$ref": "#/definitions/consts.NodeAccessGrantType"
                         }
                     ]
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kb_id": {
+                    "type": "string"
                 }
             }
         },
-        "domain.NodeStatus": {
-            "type": "integer",
-            "format": "int32",
-            "enum": [
-                1,
-                2
-            ],
-            "x-enum-varnames": [
-                "NodeStatusDraft",
-                "NodeStatusReleased"
-            ]
+        "v1.AccessRequestListResp": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_chaitin_panda-wiki_api_node_v1.AccessRequestItem"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
         },
-        "domain.NodeSummaryReq": {
+        "v1.AuditLogListResp": {
             "type": "object",
-            "required": [
-                "ids",
-                "kb_id"
-            ],
             "properties": {
-                "ids": {
+                "data": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/domain.AuditLog"
                     }
                 },
-                "kb_id": {
-                    "type": "string"
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "domain.NodeType": {
-            "type": "integer",
-            "format": "int32",
-            "enum": [
-                1,
-                2
-            ],
-            "x-enum-varnames": [
-                "NodeTypeFolder",
-                "NodeTypeDocument"
-            ]
+        "v1.AuditLogSettingReq": {
+            "type": "object",
+            "properties": {
+                "retention_days": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
         },
-        "domain.ObjectUploadResp": {
+        "v1.AuditLogVerifyResp": {
             "type": "object",
             "properties": {
-                "filename": {
-                    "type": "string"
+                "broken_id": {
+                    "description": "第一条校验失败的记录",
+                    "type": "integer"
                 },
-                "key": {
+                "checked": {
+                    "type": "integer"
+                },
+                "reason": {
                     "type": "string"
+                },
+                "valid": {
+                    "type": "boolean"
                 }
             }
         },
-        "domain.OpenAIAPIBotSettings": {
+        "v1.AuthCASReq": {
             "type": "object",
             "properties": {
-                "is_enabled": {
-                    "type": "boolean"
+                "kb_id": {
+                    "type": "string"
                 },
-                "secret_key": {
+                "redirect_url": {
                     "type": "string"
                 }
             }
         },
-        "domain.OpenAIChoice": {
+        "v1.AuthCASResp": {
             "type": "object",
             "properties": {
-                "delta": {
-                    "description": "for streaming",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.OpenAIMessage"
-                        }
-                    ]
-                },
-                "finish_reason": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.AuthGitHubReq": {
+            "type": "object",
+            "properties": {
+                "kb_id": {
                     "type": "string"
                 },
-                "index": {
-                    "type": "integer"
-                },
-                "message": {
-                    "$ref": "#/definitions/domain.OpenAIMessage"
+                "redirect_url": {
+                    "type": "string"
                 }
             }
         },
-        "domain.OpenAICompletionsRequest": {
+        "v1.AuthGitHubResp": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.AuthGroupSyncReq": {
             "type": "object",
             "required": [
-                "messages",
-                "model"
+                "kb_id",
+                "source_type"
             ],
             "properties": {
-                "frequency_penalty": {
-                    "type": "number"
-                },
-                "max_tokens": {
-                    "type": "integer"
-                },
-                "messages": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.OpenAIMessage"
-                    }
-                },
-                "model": {
-                    "type": "string"
-                },
-                "presence_penalty": {
-                    "type": "number"
-                },
-                "response_format": {
-                    "$ref": "#/definitions/domain.OpenAIResponseFormat"
-                },
-                "stop": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "stream": {
+                "dry_run": {
+                    "description": "仅计算差异，不修改用户组",
                     "type": "boolean"
                 },
-                "temperature": {
-                    "type": "number"
-                },
-                "tool_choice": {
-                    "$ref": "#/definitions/domain.OpenAIToolChoice"
-                },
-                "tools": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.OpenAITool"
-                    }
-                },
-                "top_p": {
-                    "type": "number"
-                },
-                "user": {
+                "kb_id": {
                     "type": "string"
+                },
+                "source_type": {
+                    "enum": [
+                        "ldap",
+                        "dingtalk",
+                        "feishu",
+                        "wecom"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/consts.SourceType"
+                        }
+                    ]
                 }
             }
         },
-        "domain.OpenAICompletionsResponse": {
+        "v1.AuthGroupSyncRunListResp": {
             "type": "object",
             "properties": {
-                "choices": {
+                "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/domain.OpenAIChoice"
+                        "$ref": "#/definitions/domain.AuthGroupSyncRun"
                     }
                 },
-                "created": {
+                "total": {
                     "type": "integer"
+                }
+            }
+        },
+        "v1.AuthItem": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
                 },
-                "id": {
+                "avatar_url": {
                     "type": "string"
                 },
-                "model": {
+                "created_at": {
                     "type": "string"
                 },
-                "object": {
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
                     "type": "string"
                 },
-                "usage": {
-                    "$ref": "#/definitions/domain.OpenAIUsage"
+                "last_login_time": {
+                    "type": "string"
+                },
+                "source_type": {
+                    "$ref": "#/definitions/consts.SourceType"
+                },
+                "username": {
+                    "type": "string"
                 }
             }
         },
-        "domain.OpenAIError": {
+        "v1.AuthLDAPReq": {
             "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
             "properties": {
-                "code": {
-                    "type": "string"
-                },
-                "message": {
-                    "type": "string"
-                },
-                "param": {
+                "password": {
                     "type": "string"
                 },
-                "type": {
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "domain.OpenAIErrorResponse": {
+        "v1.AuthLoginSimpleReq": {
             "type": "object",
+            "required": [
+                "password"
+            ],
             "properties": {
-                "error": {
-                    "$ref": "#/definitions/domain.OpenAIError"
+                "password": {
+                    "type": "string"
                 }
             }
         },
-        "domain.OpenAIFunction": {
+        "v1.AuthOAuthReq": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "description": {
+                "kb_id": {
                     "type": "string"
                 },
-                "name": {
+                "redirect_url": {
                     "type": "string"
-                },
-                "parameters": {
-                    "type": "object",
-                    "additionalProperties": true
                 }
             }
         },
-        "domain.OpenAIFunctionCall": {
+        "v1.AuthOAuthResp": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.AuthSAMLReq": {
             "type": "object",
-            "required": [
-                "arguments",
-                "name"
-            ],
             "properties": {
-                "arguments": {
+                "kb_id": {
                     "type": "string"
                 },
-                "name": {
+                "redirect_url": {
                     "type": "string"
                 }
             }
         },
-        "domain.OpenAIFunctionChoice": {
+        "v1.AuthSAMLResp": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "name": {
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "domain.OpenAIMessage": {
+        "v1.AuthSCIMGetResp": {
             "type": "object",
-            "required": [
-                "role"
-            ],
             "properties": {
-                "content": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
+                "enabled": {
+                    "type": "boolean"
                 },
-                "role": {
-                    "type": "string"
+                "source_type": {
+                    "$ref": "#/definitions/consts.SourceType"
                 },
-                "tool_call_id": {
+                "token_created_at": {
                     "type": "string"
-                },
-                "tool_calls": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.OpenAIToolCall"
-                    }
                 }
             }
         },
-        "domain.OpenAIResponseFormat": {
+        "v1.AuthSCIMSetReq": {
             "type": "object",
             "required": [
-                "type"
+                "kb_id",
+                "source_type"
             ],
             "properties": {
-                "type": {
+                "kb_id": {
                     "type": "string"
+                },
+                "reset_token": {
+                    "description": "重新生成 token，旧 token 立即失效",
+                    "type": "boolean"
+                },
+                "source_type": {
+                    "description": "开通的读者使用的登录方式",
+                    "enum": [
+                        "ldap",
+                        "cas",
+                        "oauth",
+                        "saml",
+                        "dingtalk",
+                        "feishu",
+                        "wecom"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/consts.SourceType"
+                        }
+                    ]
                 }
             }
         },
-        "domain.OpenAITool": {
+        "v1.AuthSCIMSetResp": {
             "type": "object",
-            "required": [
-                "type"
-            ],
             "properties": {
-                "function": {
-                    "$ref": "#/definitions/domain.OpenAIFunction"
-                },
-                "type": {
+                "token": {
+                    "description": "仅在生成时返回一次",
                     "type": "string"
                 }
             }
         },
-        "domain.OpenAIToolCall": {
+        "v1.AuthSetReq": {
             "type": "object",
             "required": [
-                "function",
-                "id",
-                "type"
+                "source_type"
             ],
             "properties": {
-                "function": {
-                    "$ref": "#/definitions/domain.OpenAIFunctionCall"
+                "cas": {
+                    "$ref": "#/definitions/domain.CASAuthSetting"
                 },
-                "id": {
+                "client_id": {
                     "type": "string"
                 },
-                "type": {
+                "client_secret": {
+                    "type": "string"
+                },
+                "group_sync": {
+                    "description": "仅 ldap、dingtalk、feishu、wecom 支持",
+                    "type": "boolean"
+                },
+                "kb_id": {
+                    "type": "string"
+                },
+                "ldap": {
+                    "$ref": "#/definitions/domain.LDAPAuthSetting"
+                },
+                "oauth": {
+                    "$ref": "#/definitions/domain.OAuthAuthSetting"
+                },
+                "proxy": {
                     "type": "string"
+                },
+                "saml": {
+                    "$ref": "#/definitions/domain.SAMLAuthSetting"
+                },
+                "source_type": {
+                    "enum": [
+                        "github",
+                        "ldap",
+                        "cas",
+                        "oauth",
+                        "saml",
+                        "dingtalk",
+                        "feishu",
+                        "wecom"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/consts.SourceType"
+                        }
+                    ]
                 }
             }
         },
-        "domain.OpenAIToolChoice": {
+        "v1.BrokenLinkItem": {
             "type": "object",
             "properties": {
-                "function": {
-                    "$ref": "#/definitions/domain.OpenAIFunctionChoice"
+                "checked_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "reason": {
+                    "$ref": "#/definitions/consts.NodeLinkBrokenReason"
+                },
+                "source_name": {
+                    "type": "string"
+                },
+                "source_node_id": {
+                    "type": "string"
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "target_node_id": {
+                    "type": "string"
                 },
                 "type": {
+                    "$ref": "#/definitions/consts.NodeLinkType"
+                },
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "domain.OpenAIUsage": {
+        "v1.BrokenLinkListResp": {
             "type": "object",
             "properties": {
-                "completion_tokens": {
-                    "type": "integer"
-                },
-                "prompt_tokens": {
-                    "type": "integer"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.BrokenLinkItem"
+                    }
                 },
-                "total_tokens": {
+                "total": {
                     "type": "integer"
                 }
             }
         },
-        "domain.PWResponse": {
+        "v1.CheckLinksReq": {
             "type": "object",
+            "required": [
+                "kb_id"
+            ],
             "properties": {
-                "code": {
-                    "type": "integer"
-                },
-                "data": {},
-                "message": {
+                "kb_id": {
                     "type": "string"
-                },
-                "success": {
-                    "type": "boolean"
                 }
             }
         },
-        "domain.PaginatedResult-array_domain_ConversationMessageListItem": {
+        "v1.CommentLists": {
             "type": "object",
             "properties": {
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/domain.ConversationMessageListItem"
+                        "$ref": "#/definitions/domain.CommentListItem"
                     }
                 },
                 "total": {
@@ -6770,937 +14800,977 @@ const docTemplate = `{
                 }
             }
         },
-        "domain.ProviderModelListItem": {
+        "v1.ConversationListItems": {
             "type": "object",
             "properties": {
-                "model": {
-                    "type": "string"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ConversationListItem"
+                    }
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "domain.QuestionConfig": {
+        "v1.CrawlerExportReq": {
             "type": "object",
+            "required": [
+                "doc_id",
+                "id",
+                "kb_id"
+            ],
             "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "type": "object",
-                        "properties": {
-                            "id": {
-                                "type": "string"
-                            },
-                            "question": {
-                                "type": "string"
-                            }
-                        }
-                    }
+                "doc_id": {
+                    "type": "string"
                 },
-                "title": {
+                "file_type": {
                     "type": "string"
                 },
-                "type": {
+                "id": {
+                    "type": "string"
+                },
+                "kb_id": {
+                    "type": "string"
+                },
+                "space_id": {
                     "type": "string"
                 }
             }
         },
-        "domain.RagInfo": {
+        "v1.CrawlerExportResp": {
             "type": "object",
             "properties": {
-                "message": {
+                "task_id": {
                     "type": "string"
-                },
-                "status": {
-                    "$ref": "#/definitions/consts.NodeRagInfoStatus"
                 }
             }
         },
-        "domain.RecommendNodeListResp": {
+        "v1.CrawlerParseReq": {
             "type": "object",
+            "required": [
+                "crawler_source",
+                "kb_id"
+            ],
             "properties": {
-                "emoji": {
-                    "type": "string"
+                "crawler_source": {
+                    "$ref": "#/definitions/consts.CrawlerSource"
                 },
-                "id": {
-                    "type": "string"
+                "feishu_setting": {
+                    "$ref": "#/definitions/v1.FeishuSetting"
                 },
-                "name": {
+                "filename": {
                     "type": "string"
                 },
-                "parent_id": {
+                "kb_id": {
                     "type": "string"
                 },
-                "permissions": {
-                    "$ref": "#/definitions/domain.NodePermissions"
+                "key": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.CrawlerParseResp": {
+            "type": "object",
+            "properties": {
+                "docs": {
+                    "$ref": "#/definitions/anydoc.Child"
                 },
-                "position": {
-                    "type": "number"
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.CrawlerResultItem": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
                 },
-                "recommend_nodes": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.RecommendNodeListResp"
-                    }
+                "status": {
+                    "$ref": "#/definitions/consts.CrawlerStatus"
                 },
-                "summary": {
+                "task_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.CrawlerResultReq": {
+            "type": "object",
+            "required": [
+                "task_id"
+            ],
+            "properties": {
+                "task_id": {
                     "type": "string"
-                },
-                "type": {
-                    "$ref": "#/definitions/domain.NodeType"
                 }
             }
         },
-        "domain.Response": {
+        "v1.CrawlerResultResp": {
             "type": "object",
+            "required": [
+                "status"
+            ],
             "properties": {
-                "data": {},
-                "message": {
+                "content": {
                     "type": "string"
                 },
-                "success": {
-                    "type": "boolean"
+                "status": {
+                    "$ref": "#/definitions/consts.CrawlerStatus"
                 }
             }
         },
-        "domain.ScoreType": {
-            "type": "integer",
-            "enum": [
-                1,
-                -1
-            ],
-            "x-enum-varnames": [
-                "Like",
-                "DisLike"
-            ]
-        },
-        "domain.ShareCommentListItem": {
+        "v1.CrawlerResultsReq": {
             "type": "object",
+            "required": [
+                "task_ids"
+            ],
             "properties": {
-                "content": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "info": {
-                    "$ref": "#/definitions/domain.CommentInfo"
-                },
-                "ip_address": {
-                    "description": "ip地址",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.IPAddress"
-                        }
-                    ]
-                },
-                "kb_id": {
-                    "type": "string"
-                },
-                "node_id": {
-                    "type": "string"
-                },
-                "parent_id": {
-                    "type": "string"
-                },
-                "pic_urls": {
+                "task_ids": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
-                },
-                "root_id": {
-                    "type": "string"
                 }
             }
         },
-        "domain.ShareConversationDetailResp": {
+        "v1.CrawlerResultsResp": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "messages": {
+                "list": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/domain.ShareConversationMessage"
+                        "$ref": "#/definitions/v1.CrawlerResultItem"
                     }
                 },
-                "subject": {
-                    "type": "string"
+                "status": {
+                    "$ref": "#/definitions/consts.CrawlerStatus"
                 }
             }
         },
-        "domain.ShareConversationMessage": {
+        "v1.CreateUserReq": {
             "type": "object",
+            "required": [
+                "account",
+                "password",
+                "role"
+            ],
             "properties": {
-                "content": {
+                "account": {
                     "type": "string"
                 },
-                "created_at": {
+                "email": {
                     "type": "string"
                 },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                },
                 "role": {
-                    "$ref": "#/definitions/schema.RoleType"
+                    "enum": [
+                        "admin",
+                        "user"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/consts.UserRole"
+                        }
+                    ]
                 }
             }
         },
-        "domain.SimpleAuth": {
+        "v1.CreateUserResp": {
             "type": "object",
             "properties": {
-                "enabled": {
-                    "type": "boolean"
-                },
-                "password": {
+                "id": {
                     "type": "string"
                 }
             }
         },
-        "domain.SimpleDocConfig": {
+        "v1.EscalationReplyReq": {
             "type": "object",
+            "required": [
+                "content",
+                "conversation_id",
+                "kb_id"
+            ],
             "properties": {
-                "bg_color": {
+                "content": {
                     "type": "string"
                 },
-                "title": {
+                "conversation_id": {
                     "type": "string"
                 },
-                "title_color": {
+                "kb_id": {
                     "type": "string"
                 }
             }
         },
-        "domain.SocialMediaAccount": {
+        "v1.EscalationReplyResp": {
             "type": "object",
             "properties": {
-                "channel": {
-                    "type": "string"
+                "delivered": {
+                    "description": "Delivered 是否已推送到用户所在渠道, 网页等无法推送的渠道用户刷新会话后可见",
+                    "type": "boolean"
                 },
-                "icon": {
+                "message_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.FeishuSetting": {
+            "type": "object",
+            "properties": {
+                "app_id": {
                     "type": "string"
                 },
-                "link": {
+                "app_secret": {
                     "type": "string"
                 },
-                "phone": {
+                "space_id": {
                     "type": "string"
                 },
-                "text": {
+                "user_access_token": {
                     "type": "string"
                 }
             }
         },
-        "domain.StatPageReq": {
+        "v1.FileUploadResp": {
             "type": "object",
-            "required": [
-                "scene"
-            ],
             "properties": {
-                "node_id": {
+                "key": {
                     "type": "string"
-                },
-                "scene": {
-                    "enum": [
-                        1,
-                        2,
-                        3,
-                        4
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.StatPageScene"
-                        }
-                    ]
                 }
             }
         },
-        "domain.StatPageScene": {
-            "type": "integer",
-            "enum": [
-                1,
-                2,
-                3,
-                4
-            ],
-            "x-enum-varnames": [
-                "StatPageSceneWelcome",
-                "StatPageSceneNodeDetail",
-                "StatPageSceneChat",
-                "StatPageSceneLogin"
-            ]
-        },
-        "domain.TextConfig": {
+        "v1.GetEscalationSettingsResp": {
             "type": "object",
             "properties": {
-                "title": {
-                    "type": "string"
+                "dislike_threshold": {
+                    "description": "DislikeThreshold 同一用户 24 小时内点踩次数达到该值时转人工, 0 表示不启用",
+                    "type": "integer"
                 },
-                "type": {
+                "emails": {
+                    "description": "Emails 值班邮箱, 使用通知设置中的 SMTP 发送",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "handoff_message": {
+                    "description": "HandoffMessage 转人工后回复用户的消息",
                     "type": "string"
+                },
+                "keywords": {
+                    "description": "Keywords 用户消息包含任一关键词时转人工",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "on_no_result": {
+                    "description": "OnNoResult 未检索到相关文档时转人工",
+                    "type": "boolean"
+                },
+                "webhooks": {
+                    "description": "值班通知, 群机器人或通用 webhook",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.NotifyWebhook"
+                    }
                 }
             }
         },
-        "domain.TextImgConfig": {
+        "v1.GetNotifySettingsResp": {
             "type": "object",
             "properties": {
-                "item": {
-                    "type": "object",
-                    "properties": {
-                        "desc": {
-                            "type": "string"
-                        },
-                        "name": {
-                            "type": "string"
-                        },
-                        "url": {
-                            "type": "string"
-                        }
+                "broadcasts": {
+                    "description": "Broadcasts 发布版本时通过已配置的机器人向群聊推送文档更新",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ReleaseBroadcast"
                     }
                 },
-                "title": {
-                    "type": "string"
+                "feishu_bot_dm": {
+                    "description": "FeishuBotDM 通过已配置的飞书机器人按邮箱私信通知用户",
+                    "type": "boolean"
                 },
-                "type": {
-                    "type": "string"
+                "smtp": {
+                    "$ref": "#/definitions/domain.SMTPSettings"
+                },
+                "webhooks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.NotifyWebhook"
+                    }
                 }
             }
         },
-        "domain.TextReq": {
+        "v1.KBRoleCreateReq": {
             "type": "object",
             "required": [
-                "text"
+                "capabilities",
+                "name"
             ],
             "properties": {
-                "action": {
-                    "description": "action: improve, summary, extend, shorten, etc.",
-                    "type": "string"
+                "capabilities": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/consts.KBCapability"
+                    }
                 },
-                "text": {
-                    "type": "string"
-                }
-            }
-        },
-        "domain.ThemeAndStyle": {
-            "type": "object",
-            "properties": {
-                "bg_image": {
-                    "type": "string"
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
                 },
-                "doc_width": {
-                    "type": "string"
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
                 }
             }
         },
-        "domain.UpdateAppReq": {
+        "v1.KBRoleListResp": {
             "type": "object",
             "properties": {
-                "kb_id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
+                "capabilities": {
+                    "description": "可选的能力及内置权限包含的能力",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.KBCapabilityInfo"
+                    }
                 },
-                "settings": {
-                    "$ref": "#/definitions/domain.AppSettings"
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.KBRole"
+                    }
                 }
             }
         },
-        "domain.UpdateKnowledgeBaseReq": {
+        "v1.KBRoleUpdateReq": {
             "type": "object",
             "required": [
-                "id"
+                "capabilities",
+                "id",
+                "name"
             ],
             "properties": {
-                "access_settings": {
-                    "$ref": "#/definitions/domain.AccessSettings"
+                "capabilities": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/consts.KBCapability"
+                    }
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
                 },
                 "id": {
                     "type": "string"
                 },
                 "name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 64
                 }
             }
         },
-        "domain.UpdateModelReq": {
+        "v1.KBUserInviteReq": {
             "type": "object",
             "required": [
-                "base_url",
-                "id",
-                "model",
-                "provider",
-                "type"
+                "kb_id",
+                "user_id"
             ],
             "properties": {
-                "api_header": {
+                "kb_id": {
                     "type": "string"
                 },
-                "api_key": {
-                    "type": "string"
+                "perm": {
+                    "enum": [
+                        "full_control",
+                        "doc_manage",
+                        "data_operate"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/consts.UserKBPermission"
+                        }
+                    ]
                 },
-                "api_version": {
-                    "description": "for azure openai",
+                "role_id": {
+                    "description": "自定义角色，设置后忽略 perm",
                     "type": "string"
                 },
-                "base_url": {
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.KBUserListItemResp": {
+            "type": "object",
+            "properties": {
+                "account": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "is_active": {
-                    "type": "boolean"
+                "perms": {
+                    "$ref": "#/definitions/consts.UserKBPermission"
                 },
-                "model": {
-                    "type": "string"
+                "role": {
+                    "$ref": "#/definitions/consts.UserRole"
                 },
-                "parameters": {
-                    "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.ModelParam"
+                "role_id": {
+                    "type": "string"
                 },
-                "provider": {
-                    "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.ModelProvider"
+                "role_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.KBUserUpdateReq": {
+            "type": "object",
+            "required": [
+                "kb_id",
+                "user_id"
+            ],
+            "properties": {
+                "kb_id": {
+                    "type": "string"
                 },
-                "type": {
+                "perm": {
                     "enum": [
-                        "chat",
-                        "embedding",
-                        "rerank",
-                        "analysis",
-                        "analysis-vl"
+                        "full_control",
+                        "doc_manage",
+                        "data_operate"
                     ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/domain.ModelType"
+                            "$ref": "#/definitions/consts.UserKBPermission"
                         }
                     ]
+                },
+                "role_id": {
+                    "description": "自定义角色，设置后忽略 perm",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "domain.UpdateNodeReq": {
+        "v1.LoginReq": {
             "type": "object",
             "required": [
-                "id",
-                "kb_id"
+                "account",
+                "password"
             ],
             "properties": {
-                "content": {
-                    "type": "string"
-                },
-                "content_type": {
-                    "type": "string"
-                },
-                "emoji": {
+                "account": {
                     "type": "string"
                 },
-                "id": {
+                "password": {
                     "type": "string"
-                },
-                "kb_id": {
+                }
+            }
+        },
+        "v1.LoginResp": {
+            "type": "object",
+            "properties": {
+                "token": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string"
+                "two_factor_required": {
+                    "type": "boolean"
                 },
-                "position": {
-                    "type": "number"
+                "two_factor_setup_required": {
+                    "type": "boolean"
                 },
-                "summary": {
+                "two_factor_token": {
                     "type": "string"
                 }
             }
         },
-        "domain.UserInfo": {
+        "v1.LoginTwoFactorReq": {
             "type": "object",
+            "required": [
+                "code",
+                "two_factor_token"
+            ],
             "properties": {
-                "auth_user_id": {
-                    "type": "integer"
-                },
-                "avatar": {
-                    "description": "avatar",
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "from": {
-                    "$ref": "#/definitions/domain.MessageFrom"
-                },
-                "name": {
+                "code": {
+                    "description": "验证码或恢复码",
                     "type": "string"
                 },
-                "real_name": {
+                "two_factor_token": {
                     "type": "string"
+                }
+            }
+        },
+        "v1.LoginTwoFactorResp": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "description": "登录时完成绑定才返回",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "user_id": {
+                "token": {
                     "type": "string"
                 }
             }
         },
-        "domain.WebAppCommentSettings": {
+        "v1.LoginTwoFactorSetupReq": {
             "type": "object",
+            "required": [
+                "two_factor_token"
+            ],
             "properties": {
-                "is_enable": {
-                    "type": "boolean"
-                },
-                "moderation_enable": {
-                    "type": "boolean"
+                "two_factor_token": {
+                    "type": "string"
                 }
             }
         },
-        "domain.WebAppCustomSettings": {
+        "v1.MailReplyActionReq": {
             "type": "object",
+            "required": [
+                "id",
+                "kb_id"
+            ],
             "properties": {
-                "allow_theme_switching": {
-                    "type": "boolean"
-                },
-                "footer_show_intro": {
-                    "type": "boolean"
-                },
-                "header_search_placeholder": {
+                "id": {
                     "type": "string"
                 },
-                "show_brand_info": {
-                    "type": "boolean"
-                },
-                "social_media_accounts": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.SocialMediaAccount"
-                    }
+                "kb_id": {
+                    "type": "string"
                 }
             }
         },
-        "domain.WebAppLandingConfig": {
+        "v1.MailReplyListResp": {
             "type": "object",
             "properties": {
-                "banner_config": {
-                    "$ref": "#/definitions/domain.BannerConfig"
-                },
-                "basic_doc_config": {
-                    "$ref": "#/definitions/domain.BasicDocConfig"
-                },
-                "block_grid_config": {
-                    "$ref": "#/definitions/domain.BlockGridConfig"
-                },
-                "carousel_config": {
-                    "$ref": "#/definitions/domain.CarouselConfig"
-                },
-                "case_config": {
-                    "$ref": "#/definitions/domain.CaseConfig"
-                },
-                "com_config_order": {
+                "data": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/domain.MailReply"
                     }
                 },
-                "comment_config": {
-                    "$ref": "#/definitions/domain.CommentConfig"
-                },
-                "dir_doc_config": {
-                    "$ref": "#/definitions/domain.DirDocConfig"
-                },
-                "faq_config": {
-                    "$ref": "#/definitions/domain.FaqConfig"
-                },
-                "feature_config": {
-                    "$ref": "#/definitions/domain.FeatureConfig"
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.MailReplyUpdateReq": {
+            "type": "object",
+            "required": [
+                "answer",
+                "id",
+                "kb_id",
+                "subject"
+            ],
+            "properties": {
+                "answer": {
+                    "type": "string"
                 },
-                "img_text_config": {
-                    "$ref": "#/definitions/domain.ImgTextConfig"
+                "id": {
+                    "type": "string"
                 },
-                "metrics_config": {
-                    "$ref": "#/definitions/domain.MetricsConfig"
+                "kb_id": {
+                    "type": "string"
                 },
-                "node_ids": {
+                "subject": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.MarkNodeReviewedReq": {
+            "type": "object",
+            "required": [
+                "ids",
+                "kb_id"
+            ],
+            "properties": {
+                "ids": {
                     "type": "array",
+                    "minItems": 1,
                     "items": {
                         "type": "string"
                     }
                 },
-                "question_config": {
-                    "$ref": "#/definitions/domain.QuestionConfig"
-                },
-                "simple_doc_config": {
-                    "$ref": "#/definitions/domain.SimpleDocConfig"
-                },
-                "text_config": {
-                    "$ref": "#/definitions/domain.TextConfig"
-                },
-                "text_img_config": {
-                    "$ref": "#/definitions/domain.TextImgConfig"
-                },
-                "type": {
+                "kb_id": {
                     "type": "string"
                 }
             }
         },
-        "domain.WebAppLandingConfigResp": {
+        "v1.NodeBacklinkItem": {
             "type": "object",
             "properties": {
-                "banner_config": {
-                    "$ref": "#/definitions/domain.BannerConfig"
-                },
-                "basic_doc_config": {
-                    "$ref": "#/definitions/domain.BasicDocConfig"
+                "emoji": {
+                    "type": "string"
                 },
-                "block_grid_config": {
-                    "$ref": "#/definitions/domain.BlockGridConfig"
+                "name": {
+                    "type": "string"
                 },
-                "carousel_config": {
-                    "$ref": "#/definitions/domain.CarouselConfig"
+                "node_id": {
+                    "type": "string"
                 },
-                "case_config": {
-                    "$ref": "#/definitions/domain.CaseConfig"
+                "status": {
+                    "$ref": "#/definitions/domain.NodeStatus"
                 },
-                "com_config_order": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.NodeBacklinksResp": {
+            "type": "object",
+            "properties": {
+                "backlinks": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/v1.NodeBacklinkItem"
                     }
+                }
+            }
+        },
+        "v1.NodeDetailResp": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
                 },
-                "comment_config": {
-                    "$ref": "#/definitions/domain.CommentConfig"
-                },
-                "dir_doc_config": {
-                    "$ref": "#/definitions/domain.DirDocConfig"
+                "created_at": {
+                    "type": "string"
                 },
-                "faq_config": {
-                    "$ref": "#/definitions/domain.FaqConfig"
+                "id": {
+                    "type": "string"
                 },
-                "feature_config": {
-                    "$ref": "#/definitions/domain.FeatureConfig"
+                "kb_id": {
+                    "type": "string"
                 },
-                "img_text_config": {
-                    "$ref": "#/definitions/domain.ImgTextConfig"
+                "meta": {
+                    "$ref": "#/definitions/domain.NodeMeta"
                 },
-                "metrics_config": {
-                    "$ref": "#/definitions/domain.MetricsConfig"
+                "name": {
+                    "type": "string"
                 },
-                "node_ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "owner_id": {
+                    "type": "string"
                 },
-                "nodes": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.RecommendNodeListResp"
-                    }
+                "parent_id": {
+                    "type": "string"
                 },
-                "question_config": {
-                    "$ref": "#/definitions/domain.QuestionConfig"
+                "permissions": {
+                    "$ref": "#/definitions/domain.NodePermissions"
                 },
-                "simple_doc_config": {
-                    "$ref": "#/definitions/domain.SimpleDocConfig"
+                "review_interval": {
+                    "type": "integer"
                 },
-                "text_config": {
-                    "$ref": "#/definitions/domain.TextConfig"
+                "reviewed_at": {
+                    "type": "string"
                 },
-                "text_img_config": {
-                    "$ref": "#/definitions/domain.TextImgConfig"
+                "status": {
+                    "$ref": "#/definitions/domain.NodeStatus"
                 },
                 "type": {
+                    "$ref": "#/definitions/domain.NodeType"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "domain.WebAppLandingTheme": {
-            "type": "object",
-            "properties": {
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "domain.WecomAIBotSettings": {
+        "v1.NodeMetaSchemaResp": {
             "type": "object",
             "properties": {
-                "encodingaeskey": {
-                    "type": "string"
-                },
-                "is_enabled": {
-                    "type": "boolean"
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.NodeMetaField"
+                    }
                 },
-                "token": {
-                    "type": "string"
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "domain.WidgetBotSettings": {
+        "v1.NodeMetaSchemaUpdateReq": {
             "type": "object",
+            "required": [
+                "kb_id",
+                "tags"
+            ],
             "properties": {
-                "btn_logo": {
-                    "type": "string"
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.NodeMetaField"
+                    }
                 },
-                "btn_text": {
+                "kb_id": {
                     "type": "string"
                 },
-                "is_open": {
-                    "type": "boolean"
-                },
-                "theme_mode": {
-                    "type": "string"
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "github_com_chaitin_panda-wiki_api_auth_v1.AuthGetResp": {
+        "v1.NodePermissionEditReq": {
             "type": "object",
+            "required": [
+                "ids",
+                "kb_id"
+            ],
             "properties": {
-                "auths": {
+                "answerable_groups": {
+                    "description": "可被问答",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v1.AuthItem"
+                        "type": "integer"
                     }
                 },
-                "client_id": {
-                    "type": "string"
-                },
-                "client_secret": {
-                    "type": "string"
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "proxy": {
+                "kb_id": {
                     "type": "string"
                 },
-                "source_type": {
-                    "$ref": "#/definitions/consts.SourceType"
-                }
-            }
-        },
-        "github_com_chaitin_panda-wiki_api_share_v1.AuthGetResp": {
-            "type": "object",
-            "properties": {
-                "auth_type": {
-                    "$ref": "#/definitions/consts.AuthType"
+                "permissions": {
+                    "$ref": "#/definitions/domain.NodePermissions"
                 },
-                "license_edition": {
-                    "$ref": "#/definitions/consts.LicenseEdition"
+                "visible_groups": {
+                    "description": "导航内可见",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "source_type": {
-                    "$ref": "#/definitions/consts.SourceType"
+                "visitable_groups": {
+                    "description": "可被访问",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "github_com_chaitin_panda-wiki_api_share_v1.GitHubCallbackResp": {
+        "v1.NodePermissionEditResp": {
             "type": "object"
         },
-        "github_com_chaitin_panda-wiki_domain.CheckModelReq": {
+        "v1.NodePermissionResp": {
             "type": "object",
-            "required": [
-                "base_url",
-                "model",
-                "provider",
-                "type"
-            ],
             "properties": {
-                "api_header": {
-                    "type": "string"
-                },
-                "api_key": {
-                    "type": "string"
-                },
-                "api_version": {
-                    "description": "for azure openai",
-                    "type": "string"
-                },
-                "base_url": {
-                    "type": "string"
+                "answerable_groups": {
+                    "description": "可被问答",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.NodeGroupDetail"
+                    }
                 },
-                "model": {
+                "id": {
                     "type": "string"
                 },
-                "parameters": {
-                    "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.ModelParam"
+                "permissions": {
+                    "$ref": "#/definitions/domain.NodePermissions"
                 },
-                "provider": {
-                    "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.ModelProvider"
+                "visible_groups": {
+                    "description": "导航内可见",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.NodeGroupDetail"
+                    }
                 },
-                "type": {
+                "visitable_groups": {
+                    "description": "可被访问",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.NodeGroupDetail"
+                    }
+                }
+            }
+        },
+        "v1.NodeReviewDecideReq": {
+            "type": "object",
+            "required": [
+                "action",
+                "id",
+                "kb_id"
+            ],
+            "properties": {
+                "action": {
                     "enum": [
-                        "chat",
-                        "embedding",
-                        "rerank",
-                        "analysis",
-                        "analysis-vl"
+                        "approve",
+                        "request_changes"
                     ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/domain.ModelType"
+                            "$ref": "#/definitions/consts.NodeReviewAction"
                         }
                     ]
-                }
-            }
-        },
-        "github_com_chaitin_panda-wiki_domain.CheckModelResp": {
-            "type": "object",
-            "properties": {
-                "content": {
+                },
+                "comment": {
                     "type": "string"
                 },
-                "error": {
+                "id": {
+                    "type": "string"
+                },
+                "kb_id": {
                     "type": "string"
                 }
             }
         },
-        "github_com_chaitin_panda-wiki_domain.ModelListItem": {
+        "v1.NodeReviewDetailResp": {
             "type": "object",
             "properties": {
-                "api_header": {
-                    "type": "string"
-                },
-                "api_key": {
-                    "type": "string"
-                },
-                "api_version": {
-                    "description": "for azure openai",
-                    "type": "string"
-                },
-                "base_url": {
+                "comment": {
                     "type": "string"
                 },
-                "completion_tokens": {
-                    "type": "integer"
+                "logs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.NodeReviewLogItem"
+                    }
                 },
-                "id": {
+                "node_id": {
                     "type": "string"
                 },
-                "is_active": {
+                "outdated": {
+                    "description": "提交审核后文档又被编辑过, 需要重新提交",
                     "type": "boolean"
                 },
-                "model": {
+                "reviewed_at": {
                     "type": "string"
                 },
-                "parameters": {
-                    "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.ModelParam"
-                },
-                "prompt_tokens": {
-                    "type": "integer"
+                "reviewer_id": {
+                    "type": "string"
                 },
-                "provider": {
-                    "$ref": "#/definitions/github_com_chaitin_panda-wiki_domain.ModelProvider"
+                "status": {
+                    "$ref": "#/definitions/consts.NodeReviewStatus"
                 },
-                "total_tokens": {
-                    "type": "integer"
+                "submitted_at": {
+                    "type": "string"
                 },
-                "type": {
-                    "$ref": "#/definitions/domain.ModelType"
+                "submitter_id": {
+                    "type": "string"
                 }
             }
         },
-        "github_com_chaitin_panda-wiki_domain.ModelParam": {
+        "v1.NodeReviewLogItem": {
             "type": "object",
             "properties": {
-                "context_window": {
-                    "type": "integer"
-                },
-                "max_tokens": {
-                    "type": "integer"
+                "account": {
+                    "type": "string"
                 },
-                "r1_enabled": {
-                    "type": "boolean"
+                "action": {
+                    "$ref": "#/definitions/consts.NodeReviewAction"
                 },
-                "support_computer_use": {
-                    "type": "boolean"
+                "comment": {
+                    "type": "string"
                 },
-                "support_images": {
-                    "type": "boolean"
+                "created_at": {
+                    "type": "string"
                 },
-                "support_prompt_cache": {
-                    "type": "boolean"
+                "id": {
+                    "type": "integer"
                 },
-                "temperature": {
-                    "type": "number"
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "github_com_chaitin_panda-wiki_domain.ModelProvider": {
-            "type": "string",
-            "enum": [
-                "BaiZhiCloud"
-            ],
-            "x-enum-varnames": [
-                "ModelProviderBrandBaiZhiCloud"
-            ]
-        },
-        "gocap.ChallengeData": {
+        "v1.NodeReviewPolicyResp": {
             "type": "object",
             "properties": {
-                "challenge": {
-                    "$ref": "#/definitions/gocap.ChallengeItem"
-                },
-                "expires": {
-                    "description": "过期时间,毫秒级时间戳",
-                    "type": "integer"
+                "enabled": {
+                    "type": "boolean"
                 },
-                "token": {
-                    "description": "质询令牌",
-                    "type": "string"
+                "reviewer_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "gocap.ChallengeItem": {
+        "v1.NodeReviewPolicyUpdateReq": {
             "type": "object",
+            "required": [
+                "kb_id"
+            ],
             "properties": {
-                "c": {
-                    "description": "质询数量",
-                    "type": "integer"
+                "enabled": {
+                    "type": "boolean"
                 },
-                "d": {
-                    "description": "质询难度",
-                    "type": "integer"
+                "kb_id": {
+                    "type": "string"
                 },
-                "s": {
-                    "description": "质询大小",
-                    "type": "integer"
+                "reviewer_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "gocap.VerificationResult": {
+        "v1.NodeReviewQueueItem": {
             "type": "object",
             "properties": {
-                "expires": {
-                    "description": "过期时间,毫秒级时间戳",
-                    "type": "integer"
+                "comment": {
+                    "type": "string"
                 },
-                "message": {
+                "edit_time": {
                     "type": "string"
                 },
-                "success": {
-                    "type": "boolean"
+                "emoji": {
+                    "type": "string"
                 },
-                "token": {
-                    "description": "验证令牌",
+                "name": {
+                    "type": "string"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/consts.NodeReviewStatus"
+                },
+                "submitted_at": {
+                    "type": "string"
+                },
+                "submitter": {
                     "type": "string"
+                },
+                "submitter_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/domain.NodeType"
                 }
             }
         },
-        "schema.RoleType": {
-            "type": "string",
-            "enum": [
-                "assistant",
-                "user",
-                "system",
-                "tool"
-            ],
-            "x-enum-varnames": [
-                "Assistant",
-                "User",
-                "System",
-                "Tool"
-            ]
-        },
-        "share.ShareCommentLists": {
+        "v1.NodeReviewQueueResp": {
             "type": "object",
             "properties": {
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/domain.ShareCommentListItem"
+                        "$ref": "#/definitions/v1.NodeReviewQueueItem"
                     }
                 },
                 "total": {
@@ -7708,557 +15778,617 @@ const docTemplate = `{
                 }
             }
         },
-        "v1.AuthGitHubReq": {
+        "v1.NodeReviewSubmitReq": {
             "type": "object",
+            "required": [
+                "ids",
+                "kb_id"
+            ],
             "properties": {
-                "kb_id": {
+                "comment": {
                     "type": "string"
                 },
-                "redirect_url": {
+                "ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "kb_id": {
                     "type": "string"
                 }
             }
         },
-        "v1.AuthGitHubResp": {
+        "v1.NodeTemplateCreateReq": {
             "type": "object",
+            "required": [
+                "kb_id",
+                "name"
+            ],
             "properties": {
-                "url": {
+                "content": {
                     "type": "string"
-                }
-            }
-        },
-        "v1.AuthItem": {
-            "type": "object",
-            "properties": {
-                "avatar_url": {
+                },
+                "content_type": {
                     "type": "string"
                 },
-                "created_at": {
+                "description": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "emoji": {
+                    "type": "string"
                 },
-                "ip": {
+                "fields": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "kb_id": {
                     "type": "string"
                 },
-                "last_login_time": {
+                "name": {
                     "type": "string"
                 },
-                "source_type": {
-                    "$ref": "#/definitions/consts.SourceType"
+                "permissions": {
+                    "$ref": "#/definitions/domain.NodePermissions"
                 },
-                "username": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "v1.AuthLoginSimpleReq": {
+        "v1.NodeTemplateCreateResp": {
             "type": "object",
-            "required": [
-                "password"
-            ],
             "properties": {
-                "password": {
+                "id": {
                     "type": "string"
                 }
             }
         },
-        "v1.AuthSetReq": {
+        "v1.NodeTemplateDetailResp": {
             "type": "object",
-            "required": [
-                "source_type"
-            ],
             "properties": {
-                "client_id": {
+                "content": {
+                    "type": "string"
+                },
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "creator_id": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "emoji": {
                     "type": "string"
                 },
-                "client_secret": {
+                "fields": {
+                    "$ref": "#/definitions/domain.StringMap"
+                },
+                "id": {
                     "type": "string"
                 },
                 "kb_id": {
                     "type": "string"
                 },
-                "proxy": {
+                "name": {
                     "type": "string"
                 },
-                "source_type": {
-                    "enum": [
-                        "github"
-                    ],
+                "permissions": {
+                    "description": "未设置时使用默认权限",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/consts.SourceType"
+                            "$ref": "#/definitions/domain.NodePermissions"
                         }
                     ]
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "description": "新文档默认标题, 支持占位符",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "v1.CommentLists": {
+        "v1.NodeTemplateFromNodeReq": {
             "type": "object",
+            "required": [
+                "kb_id",
+                "name",
+                "node_id"
+            ],
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.CommentListItem"
-                    }
+                "description": {
+                    "type": "string"
                 },
-                "total": {
-                    "type": "integer"
+                "kb_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "node_id": {
+                    "type": "string"
                 }
             }
         },
-        "v1.ConversationListItems": {
+        "v1.NodeTemplateListItem": {
             "type": "object",
             "properties": {
-                "data": {
+                "creator": {
+                    "type": "string"
+                },
+                "creator_id": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "emoji": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "tags": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/domain.ConversationListItem"
+                        "type": "string"
                     }
                 },
-                "total": {
-                    "type": "integer"
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "v1.CrawlerExportReq": {
+        "v1.NodeTemplateUpdateReq": {
             "type": "object",
             "required": [
-                "doc_id",
                 "id",
                 "kb_id"
             ],
             "properties": {
-                "doc_id": {
+                "content": {
                     "type": "string"
                 },
-                "file_type": {
+                "description": {
+                    "type": "string"
+                },
+                "emoji": {
                     "type": "string"
                 },
+                "fields": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "id": {
                     "type": "string"
                 },
                 "kb_id": {
                     "type": "string"
                 },
-                "space_id": {
+                "name": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "permissions": {
+                    "$ref": "#/definitions/domain.NodePermissions"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "v1.CrawlerExportResp": {
+        "v1.ResetPasswordReq": {
             "type": "object",
+            "required": [
+                "id",
+                "new_password"
+            ],
             "properties": {
-                "task_id": {
+                "id": {
                     "type": "string"
+                },
+                "new_password": {
+                    "type": "string",
+                    "minLength": 8
                 }
             }
         },
-        "v1.CrawlerParseReq": {
+        "v1.ResolveEscalationReq": {
             "type": "object",
             "required": [
-                "crawler_source",
+                "conversation_id",
                 "kb_id"
             ],
             "properties": {
-                "crawler_source": {
-                    "$ref": "#/definitions/consts.CrawlerSource"
-                },
-                "feishu_setting": {
-                    "$ref": "#/definitions/v1.FeishuSetting"
-                },
-                "filename": {
+                "conversation_id": {
                     "type": "string"
                 },
                 "kb_id": {
                     "type": "string"
-                },
-                "key": {
-                    "type": "string"
                 }
             }
         },
-        "v1.CrawlerParseResp": {
+        "v1.RevokeUserSessionsResp": {
             "type": "object",
             "properties": {
-                "docs": {
-                    "$ref": "#/definitions/anydoc.Child"
-                },
-                "id": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
                 }
             }
         },
-        "v1.CrawlerResultItem": {
+        "v1.SetLoginSecurityPolicyReq": {
             "type": "object",
             "properties": {
-                "content": {
-                    "type": "string"
-                },
-                "status": {
-                    "$ref": "#/definitions/consts.CrawlerStatus"
-                },
-                "task_id": {
-                    "type": "string"
+                "require_admin_2fa": {
+                    "type": "boolean"
                 }
             }
         },
-        "v1.CrawlerResultReq": {
+        "v1.ShareLinkAuthReq": {
             "type": "object",
             "required": [
-                "task_id"
+                "password",
+                "token"
             ],
             "properties": {
-                "task_id": {
+                "password": {
+                    "type": "string"
+                },
+                "token": {
                     "type": "string"
                 }
             }
         },
-        "v1.CrawlerResultResp": {
+        "v1.ShareLinkAuthResp": {
             "type": "object",
-            "required": [
-                "status"
-            ],
             "properties": {
-                "content": {
+                "access": {
+                    "description": "Access 后续请求通过 X-Share-Access 请求头携带",
                     "type": "string"
-                },
-                "status": {
-                    "$ref": "#/definitions/consts.CrawlerStatus"
                 }
             }
         },
-        "v1.CrawlerResultsReq": {
+        "v1.ShareLinkCreateReq": {
             "type": "object",
             "required": [
-                "task_ids"
+                "expires_in",
+                "kb_id",
+                "node_id"
             ],
             "properties": {
-                "task_ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                }
-            }
-        },
-        "v1.CrawlerResultsResp": {
-            "type": "object",
-            "properties": {
-                "list": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v1.CrawlerResultItem"
-                    }
+                "draft": {
+                    "description": "预览未发布的内容",
+                    "type": "boolean"
                 },
-                "status": {
-                    "$ref": "#/definitions/consts.CrawlerStatus"
+                "expires_in": {
+                    "description": "ExpiresIn 有效时长，单位小时，最长一年",
+                    "type": "integer",
+                    "maximum": 8760,
+                    "minimum": 1
+                },
+                "kb_id": {
+                    "type": "string"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "password": {
+                    "description": "为空时不需要密码",
+                    "type": "string",
+                    "maxLength": 64,
+                    "minLength": 4
                 }
             }
         },
-        "v1.CreateUserReq": {
+        "v1.ShareLinkInfoResp": {
             "type": "object",
-            "required": [
-                "account",
-                "password",
-                "role"
-            ],
             "properties": {
-                "account": {
+                "draft": {
+                    "type": "boolean"
+                },
+                "expires_at": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 8
+                "kb_id": {
+                    "type": "string"
                 },
-                "role": {
-                    "enum": [
-                        "admin",
-                        "user"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/consts.UserRole"
-                        }
-                    ]
+                "name": {
+                    "type": "string"
+                },
+                "need_password": {
+                    "type": "boolean"
+                },
+                "node_id": {
+                    "type": "string"
                 }
             }
         },
-        "v1.CreateUserResp": {
+        "v1.ShareLinkItem": {
             "type": "object",
             "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "draft": {
+                    "description": "预览未发布的内容",
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "has_password": {
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "string"
+                },
+                "kb_id": {
+                    "type": "string"
+                },
+                "last_viewed_at": {
+                    "type": "string"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "token": {
+                    "description": "拼接到分享页地址中，撤销或过期后失效",
+                    "type": "string"
+                },
+                "view_count": {
+                    "type": "integer"
                 }
             }
         },
-        "v1.FeishuSetting": {
+        "v1.StaleNodeItem": {
             "type": "object",
             "properties": {
-                "app_id": {
+                "dislike_count": {
+                    "description": "DislikeCount 最近引用该文档的回答收到的点踩数",
+                    "type": "integer"
+                },
+                "due_at": {
+                    "type": "string"
+                },
+                "edit_time": {
+                    "type": "string"
+                },
+                "emoji": {
+                    "type": "string"
+                },
+                "expired": {
+                    "description": "Expired 超过复查周期未编辑或复查",
+                    "type": "boolean"
+                },
+                "id": {
                     "type": "string"
                 },
-                "app_secret": {
+                "name": {
                     "type": "string"
                 },
-                "space_id": {
+                "owner": {
                     "type": "string"
                 },
-                "user_access_token": {
+                "owner_id": {
                     "type": "string"
-                }
-            }
-        },
-        "v1.FileUploadResp": {
-            "type": "object",
-            "properties": {
-                "key": {
+                },
+                "review_interval": {
+                    "type": "integer"
+                },
+                "reviewed_at": {
                     "type": "string"
                 }
             }
         },
-        "v1.KBUserInviteReq": {
+        "v1.StaleNodeListResp": {
             "type": "object",
-            "required": [
-                "kb_id",
-                "perm",
-                "user_id"
-            ],
             "properties": {
-                "kb_id": {
-                    "type": "string"
-                },
-                "perm": {
-                    "enum": [
-                        "full_control",
-                        "doc_manage",
-                        "data_operate"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/consts.UserKBPermission"
-                        }
-                    ]
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.StaleNodeItem"
+                    }
                 },
-                "user_id": {
-                    "type": "string"
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "v1.KBUserListItemResp": {
+        "v1.StatCountResp": {
             "type": "object",
             "properties": {
-                "account": {
-                    "type": "string"
+                "conversation_count": {
+                    "type": "integer"
                 },
-                "id": {
-                    "type": "string"
+                "ip_count": {
+                    "type": "integer"
                 },
-                "perms": {
-                    "$ref": "#/definitions/consts.UserKBPermission"
+                "page_visit_count": {
+                    "type": "integer"
                 },
-                "role": {
-                    "$ref": "#/definitions/consts.UserRole"
+                "session_count": {
+                    "type": "integer"
                 }
             }
         },
-        "v1.KBUserUpdateReq": {
+        "v1.TestNotifyReq": {
             "type": "object",
             "required": [
-                "kb_id",
-                "perm",
-                "user_id"
+                "kb_id"
             ],
             "properties": {
                 "kb_id": {
                     "type": "string"
-                },
-                "perm": {
-                    "enum": [
-                        "full_control",
-                        "doc_manage",
-                        "data_operate"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/consts.UserKBPermission"
-                        }
-                    ]
-                },
-                "user_id": {
-                    "type": "string"
                 }
             }
         },
-        "v1.LoginReq": {
+        "v1.TwoFactorCodeReq": {
             "type": "object",
             "required": [
-                "account",
-                "password"
+                "code"
             ],
             "properties": {
-                "account": {
-                    "type": "string"
-                },
-                "password": {
+                "code": {
                     "type": "string"
                 }
             }
         },
-        "v1.LoginResp": {
+        "v1.TwoFactorRecoveryCodesResp": {
             "type": "object",
             "properties": {
-                "token": {
-                    "type": "string"
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "v1.NodeDetailResp": {
+        "v1.TwoFactorResetReq": {
             "type": "object",
+            "required": [
+                "user_id"
+            ],
             "properties": {
-                "content": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "kb_id": {
-                    "type": "string"
-                },
-                "meta": {
-                    "$ref": "#/definitions/domain.NodeMeta"
-                },
-                "name": {
+                "user_id": {
                     "type": "string"
-                },
-                "parent_id": {
+                }
+            }
+        },
+        "v1.TwoFactorSetupResp": {
+            "type": "object",
+            "properties": {
+                "otpauth_url": {
                     "type": "string"
                 },
-                "permissions": {
-                    "$ref": "#/definitions/domain.NodePermissions"
-                },
-                "status": {
-                    "$ref": "#/definitions/domain.NodeStatus"
-                },
-                "type": {
-                    "$ref": "#/definitions/domain.NodeType"
-                },
-                "updated_at": {
+                "secret": {
                     "type": "string"
                 }
             }
         },
-        "v1.NodePermissionEditReq": {
+        "v1.UpdateEscalationSettingsReq": {
             "type": "object",
             "required": [
-                "ids",
                 "kb_id"
             ],
             "properties": {
-                "answerable_groups": {
-                    "description": "可被问答",
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "dislike_threshold": {
+                    "type": "integer",
+                    "minimum": 0
                 },
-                "ids": {
+                "emails": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "kb_id": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "handoff_message": {
                     "type": "string"
                 },
-                "permissions": {
-                    "$ref": "#/definitions/domain.NodePermissions"
+                "kb_id": {
+                    "type": "string"
                 },
-                "visible_groups": {
-                    "description": "导航内可见",
+                "keywords": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "type": "string"
                     }
                 },
-                "visitable_groups": {
-                    "description": "可被访问",
+                "on_no_result": {
+                    "type": "boolean"
+                },
+                "webhooks": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/domain.NotifyWebhook"
                     }
                 }
             }
         },
-        "v1.NodePermissionEditResp": {
-            "type": "object"
-        },
-        "v1.NodePermissionResp": {
+        "v1.UpdateNotifySettingsReq": {
             "type": "object",
+            "required": [
+                "kb_id"
+            ],
             "properties": {
-                "answerable_groups": {
-                    "description": "可被问答",
+                "broadcasts": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/domain.NodeGroupDetail"
+                        "$ref": "#/definitions/domain.ReleaseBroadcast"
                     }
                 },
-                "id": {
-                    "type": "string"
+                "feishu_bot_dm": {
+                    "type": "boolean"
                 },
-                "permissions": {
-                    "$ref": "#/definitions/domain.NodePermissions"
+                "kb_id": {
+                    "type": "string"
                 },
-                "visible_groups": {
-                    "description": "导航内可见",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.NodeGroupDetail"
-                    }
+                "smtp": {
+                    "$ref": "#/definitions/domain.SMTPSettings"
                 },
-                "visitable_groups": {
-                    "description": "可被访问",
+                "webhooks": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/domain.NodeGroupDetail"
+                        "$ref": "#/definitions/domain.NotifyWebhook"
                     }
                 }
             }
         },
-        "v1.ResetPasswordReq": {
+        "v1.UpdateUserEmailReq": {
             "type": "object",
             "required": [
-                "id",
-                "new_password"
+                "id"
             ],
             "properties": {
-                "id": {
+                "email": {
                     "type": "string"
                 },
-                "new_password": {
-                    "type": "string",
-                    "minLength": 8
-                }
-            }
-        },
-        "v1.StatCountResp": {
-            "type": "object",
-            "properties": {
-                "conversation_count": {
-                    "type": "integer"
-                },
-                "ip_count": {
-                    "type": "integer"
-                },
-                "page_visit_count": {
-                    "type": "integer"
-                },
-                "session_count": {
-                    "type": "integer"
+                "id": {
+                    "type": "string"
                 }
             }
         },
@@ -8271,6 +16401,9 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
+                "email": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -8282,6 +16415,9 @@ const docTemplate = `{
                 },
                 "role": {
                     "$ref": "#/definitions/consts.UserRole"
+                },
+                "totp_enabled": {
+                    "type": "boolean"
                 }
             }
         },
@@ -8294,6 +16430,9 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
+                "email": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -8302,6 +16441,9 @@ const docTemplate = `{
                 },
                 "role": {
                     "$ref": "#/definitions/consts.UserRole"
+                },
+                "totp_enabled": {
+                    "type": "boolean"
                 }
             }
         },
@@ -8315,6 +16457,46 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "v1.UserSessionItem": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "last_active_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.UserSessionListResp": {
+            "type": "object",
+            "properties": {
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.UserSessionItem"
+                    }
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/chaitin/panda-wiki/consts"
@@ -27,6 +28,13 @@ type CtxAuthInfo struct {
 	Permission consts.UserKBPermission
 	UserId     string
 	KBId       string
+	SessionID  string // JWT 登录时的会话 ID
+	// Capabilities 当前知识库内的能力，由 ValidateKBCapability 填充
+	Capabilities []consts.KBCapability
+}
+
+func (a *CtxAuthInfo) HasCapability(capability consts.KBCapability) bool {
+	return slices.Contains(a.Capabilities, capability)
 }
 
 type contextKey string
@@ -24,6 +24,10 @@ const (
 	AppTypeOpenAIAPI
 	AppTypeWecomAIBot
 	AppTypeLarkBot
+	AppTypeSlackBot
+	AppTypeTelegramBot
+	AppTypeTeamsBot
+	AppTypeMailBot
 )
 
 var AppTypes = []AppType{
@@ -38,6 +42,10 @@ var AppTypes = []AppType{
 	AppTypeOpenAIAPI,
 	AppTypeWecomAIBot,
 	AppTypeLarkBot,
+	AppTypeSlackBot,
+	AppTypeTelegramBot,
+	AppTypeTeamsBot,
+	AppTypeMailBot,
 }
 
 func (t AppType) ToSourceType() consts.SourceType {
@@ -64,11 +72,34 @@ func (t AppType) ToSourceType() consts.SourceType {
 		return consts.SourceTypeOpenAIAPI
 	case AppTypeLarkBot:
 		return consts.SourceTypeLarkBot
+	case AppTypeSlackBot:
+		return consts.SourceTypeSlackBot
+	case AppTypeTelegramBot:
+		return consts.SourceTypeTelegramBot
+	case AppTypeTeamsBot:
+		return consts.SourceTypeTeamsBot
+	case AppTypeMailBot:
+		return consts.SourceTypeMailBot
 	default:
 		return ""
 	}
 }
 
+// SupportsEscalation reports whether replies of human agents reach the users of the channel.
+// Bots push them to the chat, the web pages show them once the conversation is reloaded.
+// DingTalk, WeCom and the official account only answer the callback they receive,
+// the WeChat customer service hands over with its own transfer instead.
+func (t AppType) SupportsEscalation() bool {
+	switch t {
+	case AppTypeWeb, AppTypeWidget,
+		AppTypeFeishuBot, AppTypeLarkBot, AppTypeDisCordBot,
+		AppTypeSlackBot, AppTypeTelegramBot, AppTypeTeamsBot, AppTypeMailBot:
+		return true
+	default:
+		return false
+	}
+}
+
 type App struct {
 	ID   string  `json:"id" gorm:"primaryKey"`
 	KBID string  `json:"kb_id"`
@@ -109,6 +140,17 @@ type AppSettings struct {
 	FeishuBotAppSecret string `json:"feishu_bot_app_secret,omitempty"`
 	// LarkBot
 	LarkBotSettings LarkBotSettings `json:"lark_bot_settings,omitempty"`
+	// SlackBot
+	SlackBotSettings SlackBotSettings `json:"slack_bot_settings,omitempty"`
+	// TelegramBot
+	TelegramBotSettings TelegramBotSettings `json:"telegram_bot_settings,omitempty"`
+	// TeamsBot
+	TeamsBotSettings TeamsBotSettings `json:"teams_bot_settings,omitempty"`
+	// MailBot
+	MailBotSettings MailBotSettings `json:"mail_bot_settings,omitempty"`
+	// 群聊机器人多轮会话
+	BotConversationSettings BotConversationSettings `json:"bot_conversation_settings"`
+	BotRouteSettings        BotRouteSettings        `json:"bot_route_settings"`
 	// WechatAppBot 企业微信机器人
 	WeChatAppIsEnabled      *bool  `json:"wechat_app_is_enabled,omitempty"`
 	WeChatAppToken          string `json:"wechat_app_token,omitempty"`
@@ -180,6 +222,88 @@ type LarkBotSettings struct {
 	EncryptKey  string `json:"encrypt_key"`
 }
 
+type SlackBotSettings struct {
+	IsEnabled     *bool  `json:"is_enabled"`
+	BotToken      string `json:"bot_token"`      // xoxb-
+	AppToken      string `json:"app_token"`      // xapp-, 填写后使用 Socket Mode, 否则使用 Events API 回调
+	SigningSecret string `json:"signing_secret"` // Events API 回调签名校验
+}
+
+type TelegramBotSettings struct {
+	IsEnabled   *bool  `json:"is_enabled"`
+	BotToken    string `json:"bot_token"`
+	WebhookURL  string `json:"webhook_url"`  // 填写后使用 webhook 模式, 否则使用长轮询
+	SecretToken string `json:"secret_token"` // webhook 模式必填, 校验请求头 X-Telegram-Bot-Api-Secret-Token
+}
+
+type TeamsBotSettings struct {
+	IsEnabled   *bool  `json:"is_enabled"`
+	AppID       string `json:"app_id"`       // Microsoft App ID
+	AppPassword string `json:"app_password"` // Client Secret
+	TenantID    string `json:"tenant_id"`    // 单租户应用填写, 多租户留空
+}
+
+type MailBotSettings struct {
+	IsEnabled *bool `json:"is_enabled"`
+	// 收信: 配置 IMAP 时轮询邮箱中的未读邮件, 否则通过 inbound 回调接收原始邮件
+	IMAP         MailServerSettings `json:"imap"`
+	Mailbox      string             `json:"mailbox"`       // 默认 INBOX
+	PollInterval int                `json:"poll_interval"` // 秒, 默认 60
+	InboundToken string             `json:"inbound_token"` // inbound 回调校验
+	// 发信
+	SMTP        MailServerSettings   `json:"smtp"`
+	FromAddress string               `json:"from_address"`
+	FromName    string               `json:"from_name"`
+	ReplyMode   consts.MailReplyMode `json:"reply_mode" validate:"omitempty,oneof=auto draft"`
+}
+
+const (
+	DefaultBotConversationWindow = 60 * time.Minute
+	// keys older than the largest window allowed in the settings are never matched again
+	MaxBotConversationWindow = 7 * 24 * time.Hour
+)
+
+// BotConversationSettings 决定机器人收到的消息延续哪个会话
+type BotConversationSettings struct {
+	Scope  consts.ConversationScope `json:"scope" validate:"omitempty,oneof=thread chat_user chat none"` // 默认 thread
+	Window int                      `json:"window" validate:"omitempty,min=1,max=10080"`                 // 分钟, 超过该时长没有新消息则开启新会话, 默认 60
+}
+
+// BotRouteSettings 将机器人收到的问题分发到其他知识库, 机器人的凭证仍由当前应用持有
+type BotRouteSettings struct {
+	IsEnabled bool           `json:"is_enabled"`
+	Rules     []BotRouteRule `json:"rules" validate:"max=50,dive"` // 按顺序匹配, 命中第一条规则即分发
+	// Classifier 没有规则命中时由模型根据知识库说明选择
+	Classifier bool `json:"classifier"`
+	// FallbackKBIDs 仍无法确定时与当前知识库合并检索
+	FallbackKBIDs []string `json:"fallback_kb_ids" validate:"max=10"`
+}
+
+// BotRouteRule 群聊 ID、用户部门 ID、关键词任一命中即分发到 KBID
+type BotRouteRule struct {
+	KBID        string   `json:"kb_id" validate:"required"`
+	Description string   `json:"description"` // 知识库说明, 供模型分类参考
+	ChatIDs     []string `json:"chat_ids"`
+	Departments []string `json:"departments"`
+	Keywords    []string `json:"keywords"`
+}
+
+// BotRoute 问题的目标知识库, 会话保存在 KBID 下, MergeKBIDs 与其合并检索.
+// AppID 为收到问题的机器人应用, 分发到其他知识库的会话仍属于该应用, 人工回复经它发送
+type BotRoute struct {
+	KBID       string
+	MergeKBIDs []string
+	AppID      string
+}
+
+type MailServerSettings struct {
+	Host     string              `json:"host"`
+	Port     int                 `json:"port"`
+	Security consts.MailSecurity `json:"security" validate:"omitempty,oneof=tls starttls none"`
+	Username string              `json:"username"`
+	Password string              `json:"password"`
+}
+
 type BannerConfig struct {
 	Title            string   `json:"title"`
 	TitleColor       string   `json:"title_color"`
@@ -467,6 +591,17 @@ type AppSettingsResp struct {
 	FeishuBotAppSecret string `json:"feishu_bot_app_secret,omitempty"`
 	// LarkBot
 	LarkBotSettings LarkBotSettings `json:"lark_bot_settings,omitempty"`
+	// SlackBot
+	SlackBotSettings SlackBotSettings `json:"slack_bot_settings,omitempty"`
+	// TelegramBot
+	TelegramBotSettings TelegramBotSettings `json:"telegram_bot_settings,omitempty"`
+	// TeamsBot
+	TeamsBotSettings TeamsBotSettings `json:"teams_bot_settings,omitempty"`
+	// MailBot
+	MailBotSettings MailBotSettings `json:"mail_bot_settings,omitempty"`
+	// 群聊机器人多轮会话
+	BotConversationSettings BotConversationSettings `json:"bot_conversation_settings"`
+	BotRouteSettings        BotRouteSettings        `json:"bot_route_settings"`
 	// WechatAppBot
 	WeChatAppIsEnabled      *bool  `json:"wechat_app_is_enabled,omitempty"`
 	WeChatAppToken          string `json:"wechat_app_token,omitempty"`
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type AuditActorType string
+
+const (
+	AuditActorUser     AuditActorType = "user"
+	AuditActorAPIToken AuditActorType = "api_token"
+	AuditActorSystem   AuditActorType = "system" // 定时任务等
+)
+
+const (
+	// AuditChangeKey handler 通过 echo.Context 向审计中间件提供目标和变更前后的数据
+	AuditChangeKey = "audit_change"
+	// ResponseErrorKey 已返回错误响应，审计中间件据此跳过失败的请求
+	ResponseErrorKey = "response_error"
+)
+
+// table: audit_logs
+//
+// 只追加，每条记录的 hash 包含上一条的 hash，任何修改或删除中间记录都会使校验失败
+type AuditLog struct {
+	ID         int64          `json:"id" gorm:"primaryKey"`
+	ActorType  AuditActorType `json:"actor_type"`
+	ActorID    string         `json:"actor_id"`
+	ActorName  string         `json:"actor_name"`
+	KBID       string         `json:"kb_id" gorm:"column:kb_id"`
+	Action     string         `json:"action"`
+	TargetType string         `json:"target_type"`
+	TargetID   string         `json:"target_id"`
+	Changes    AuditChanges   `json:"changes" gorm:"type:jsonb"`
+	IP         string         `json:"ip"`
+	UserAgent  string         `json:"user_agent"`
+	CreatedAt  time.Time      `json:"created_at"`
+	PrevHash   string         `json:"prev_hash"`
+	Hash       string         `json:"hash"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// ComputeHash 对除 ID 和 Hash 外的全部字段计算 sha256，CreatedAt 精确到微秒以与数据库一致
+func (l *AuditLog) ComputeHash() (string, error) {
+	changes := l.Changes
+	if changes == nil {
+		// 与数据库中的空数组一致
+		changes = AuditChanges{}
+	}
+	content, err := json.Marshal(struct {
+		PrevHash   string         `json:"prev_hash"`
+		CreatedAt  string         `json:"created_at"`
+		ActorType  AuditActorType `json:"actor_type"`
+		ActorID    string         `json:"actor_id"`
+		ActorName  string         `json:"actor_name"`
+		KBID       string         `json:"kb_id"`
+		Action     string         `json:"action"`
+		TargetType string         `json:"target_type"`
+		TargetID   string         `json:"target_id"`
+		Changes    AuditChanges   `json:"changes"`
+		IP         string         `json:"ip"`
+		UserAgent  string         `json:"user_agent"`
+	}{
+		PrevHash:   l.PrevHash,
+		CreatedAt:  l.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+		ActorType:  l.ActorType,
+		ActorID:    l.ActorID,
+		ActorName:  l.ActorName,
+		KBID:       l.KBID,
+		Action:     l.Action,
+		TargetType: l.TargetType,
+		TargetID:   l.TargetID,
+		Changes:    changes,
+		IP:         l.IP,
+		UserAgent:  l.UserAgent,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+type AuditChange struct {
+	Path   string `json:"path"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+type AuditChanges []AuditChange
+
+func (c *AuditChanges) Scan(value any) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New(fmt.Sprint("invalid audit changes type:", value))
+	}
+	return json.Unmarshal(bytes, c)
+}
+
+func (c AuditChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(c)
+}
+
+// AuditTarget handler 提供的审计目标，Before/After 为变更前后的完整数据
+type AuditTarget struct {
+	KBID       string
+	TargetType string
+	TargetID   string
+	Before     any
+	After      any
+}
+
+// AuditLogSetting 全局审计日志设置
+type AuditLogSetting struct {
+	RetentionDays int `json:"retention_days"` // 保留天数，0 表示永久保留
+}
+
+type AuditLogFilter struct {
+	KBID       string
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	Start      time.Time
+	End        time.Time
+}
@@ -28,6 +28,8 @@ type Auth struct {
 	CreatedAt     time.Time         `gorm:"column:created_at;not null;default:now()" json:"created_at"`       // Timestamp when the record was created
 	UpdatedAt     time.Time         `gorm:"column:updated_at;not null;default:now()" json:"updated_at"`       // Timestamp when the record was last updated
 	UserInfo      AuthUserInfo      `json:"user_info" gorm:"type:jsonb"`
+	ExternalID    string            `gorm:"column:external_id;not null" json:"external_id,omitempty"` // SCIM 开通时 IdP 中的用户 ID
+	Active        bool              `gorm:"column:active;not null;default:true" json:"active"`        // 停用后不能登录，已有会话立即失效
 }
 
 func (Auth) TableName() string {
@@ -82,9 +84,81 @@ func (AuthConfig) TableName() string {
 }
 
 type AuthSetting struct {
-	ClientID     string `json:"client_id,omitempty"`
-	ClientSecret string `json:"client_secret,omitempty"`
-	Proxy        string `json:"proxy,omitempty"`
+	ClientID     string            `json:"client_id,omitempty"`
+	ClientSecret string            `json:"client_secret,omitempty"`
+	Proxy        string            `json:"proxy,omitempty"`
+	LDAP         *LDAPAuthSetting  `json:"ldap,omitempty"`
+	CAS          *CASAuthSetting   `json:"cas,omitempty"`
+	OAuth        *OAuthAuthSetting `json:"oauth,omitempty"`
+	SAML         *SAMLAuthSetting  `json:"saml,omitempty"`
+	GroupSync    bool              `json:"group_sync,omitempty"` // 每小时同步部门/用户组到读者用户组
+	SCIM         *SCIMAuthSetting  `json:"scim,omitempty"`
+}
+
+// SCIMAuthSetting SCIM 开通配置，只保存 token 的 sha256，开通的读者使用 SourceType 登录
+type SCIMAuthSetting struct {
+	TokenHash      string            `json:"token_hash"`
+	TokenCreatedAt time.Time         `json:"token_created_at"`
+	SourceType     consts.SourceType `json:"source_type"`
+}
+
+type LDAPAuthSetting struct {
+	ServerURL     string `json:"server_url" validate:"required"`   // 如 ldap://openldap.company.com:389
+	BindDN        string `json:"bind_dn" validate:"required"`      // 如 cn=admin,dc=company,dc=com
+	BindPassword  string `json:"bind_password"`                    // 绑定密码
+	UserBaseDN    string `json:"user_base_dn" validate:"required"` // 如 ou=People,dc=company,dc=com
+	UserFilter    string `json:"user_filter,omitempty"`            // 如 (&(objectClass=person)(uid=%s))
+	UserIDAttr    string `json:"user_id_attr,omitempty"`           // 默认 uid
+	UserNameAttr  string `json:"user_name_attr,omitempty"`         // 默认 cn
+	UserEmailAttr string `json:"user_email_attr,omitempty"`        // 默认 mail
+
+	GroupBaseDN     string `json:"group_base_dn,omitempty"`     // 如 ou=Groups,dc=company,dc=com，同步用户组时必填
+	GroupFilter     string `json:"group_filter,omitempty"`      // 默认 groupOfNames、groupOfUniqueNames 及 posixGroup
+	GroupNameAttr   string `json:"group_name_attr,omitempty"`   // 默认 cn
+	GroupMemberAttr string `json:"group_member_attr,omitempty"` // 默认 member
+}
+
+type CASAuthSetting struct {
+	ServerURL    string `json:"server_url" validate:"required"`                   // 如 https://cas.example.com/cas
+	Version      string `json:"version,omitempty" validate:"omitempty,oneof=2 3"` // 协议版本，默认 3
+	LoginPath    string `json:"login_path,omitempty"`                             // 默认 /login
+	ValidatePath string `json:"validate_path,omitempty"`                          // 默认根据版本选择
+}
+
+// OAuthAuthSetting 通用 OAuth2 配置，填写 Issuer 时按 OIDC 从发现文档获取端点并校验 ID Token
+type OAuthAuthSetting struct {
+	Issuer       string   `json:"issuer,omitempty"`
+	AuthorizeURL string   `json:"authorize_url,omitempty" validate:"required_without=Issuer"`
+	TokenURL     string   `json:"token_url,omitempty" validate:"required_without=Issuer"`
+	UserInfoURL  string   `json:"user_info_url,omitempty" validate:"required_without=Issuer"`
+	JWKSURL      string   `json:"jwks_url,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+	IDField      string   `json:"id_field,omitempty" validate:"required_without=Issuer"`
+	NameField    string   `json:"name_field,omitempty"`
+	AvatarField  string   `json:"avatar_field,omitempty"`
+	EmailField   string   `json:"email_field,omitempty"`
+}
+
+// SAMLAuthSetting SAML 2.0 SP 配置，未提供 SP 证书时自动生成
+type SAMLAuthSetting struct {
+	IdPMetadataURL    string             `json:"idp_metadata_url,omitempty" validate:"required_without=IdPMetadataXML"`
+	IdPMetadataXML    string             `json:"idp_metadata_xml,omitempty"`
+	EntityID          string             `json:"entity_id,omitempty"` // 默认为 SP 元数据地址
+	SPCertificate     string             `json:"sp_certificate,omitempty"`
+	SPPrivateKey      string             `json:"sp_private_key,omitempty"`
+	SignRequest       bool               `json:"sign_request"`
+	AllowIDPInitiated bool               `json:"allow_idp_initiated"`
+	UsernameAttr      string             `json:"username_attr,omitempty"` // 默认 displayName
+	EmailAttr         string             `json:"email_attr,omitempty"`    // 默认 email
+	AvatarAttr        string             `json:"avatar_attr,omitempty"`
+	GroupsAttr        string             `json:"groups_attr,omitempty"` // 用户组属性，配合 GroupMappings 同步用户组成员
+	GroupMappings     []SAMLGroupMapping `json:"group_mappings,omitempty" validate:"dive"`
+}
+
+// SAMLGroupMapping 用户组属性值为 Value 的用户加入 GroupID 用户组
+type SAMLGroupMapping struct {
+	Value   string `json:"value" validate:"required"`
+	GroupID uint   `json:"group_id" validate:"required"`
 }
 
 type AuthInfo struct {
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chaitin/panda-wiki/consts"
+)
+
+// table: auth_group_sync_runs
+type AuthGroupSyncRun struct {
+	ID         uint                        `json:"id" gorm:"primaryKey"`
+	KBID       string                      `json:"kb_id" gorm:"column:kb_id"`
+	SourceType consts.SourceType           `json:"source_type"`
+	DryRun     bool                        `json:"dry_run"`
+	Trigger    consts.AuthGroupSyncTrigger `json:"trigger"`
+	Status     consts.AuthGroupSyncStatus  `json:"status"`
+	Diff       AuthGroupSyncDiff           `json:"diff" gorm:"type:jsonb"`
+	Error      string                      `json:"error"`
+	StartedAt  time.Time                   `json:"started_at"`
+	FinishedAt time.Time                   `json:"finished_at"`
+}
+
+func (AuthGroupSyncRun) TableName() string {
+	return "auth_group_sync_runs"
+}
+
+// AuthGroupSyncDiff 目录与已同步用户组的差异，试运行时仅记录不应用
+type AuthGroupSyncDiff struct {
+	CreateGroups     []AuthGroupSyncGroup  `json:"create_groups"`
+	UpdateGroups     []AuthGroupSyncGroup  `json:"update_groups"`
+	DeleteGroups     []AuthGroupSyncGroup  `json:"delete_groups"`
+	AddMembers       []AuthGroupSyncMember `json:"add_members"`
+	RemoveMembers    []AuthGroupSyncMember `json:"remove_members"`
+	UnmatchedMembers []string              `json:"unmatched_members"` // 目录中尚未登录过的用户，登录后下次同步加入
+	UpdatedDocs      int                   `json:"updated_docs"`      // 因删除用户组而更新了可问答用户组的 RAG 文档数
+}
+
+func (d AuthGroupSyncDiff) Empty() bool {
+	return len(d.CreateGroups) == 0 && len(d.UpdateGroups) == 0 && len(d.DeleteGroups) == 0 &&
+		len(d.AddMembers) == 0 && len(d.RemoveMembers) == 0
+}
+
+func (d *AuthGroupSyncDiff) Scan(value any) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New(fmt.Sprint("invalid AuthGroupSyncDiff type:", value))
+	}
+	return json.Unmarshal(bytes, d)
+}
+
+func (d AuthGroupSyncDiff) Value() (driver.Value, error) {
+	return json.Marshal(d)
+}
+
+type AuthGroupSyncGroup struct {
+	ID              uint   `json:"id,omitempty"` // 已有用户组的 ID
+	SyncID          string `json:"sync_id"`
+	SyncParentID    string `json:"sync_parent_id"`
+	Name            string `json:"name"`
+	OldName         string `json:"old_name,omitempty"`
+	OldSyncParentID string `json:"old_sync_parent_id,omitempty"`
+}
+
+type AuthGroupSyncMember struct {
+	GroupID     uint   `json:"group_id,omitempty"` // 新建的用户组为 0
+	GroupSyncID string `json:"group_sync_id"`
+	GroupName   string `json:"group_name"`
+	AuthID      uint   `json:"auth_id"`
+	UnionID     string `json:"union_id"`
+}
@@ -14,17 +14,40 @@ type ChatRequest struct {
 	AppType        AppType `json:"app_type" validate:"required,oneof=1 2"`
 	CaptchaToken   string  `json:"captcha_token"`
 
-	KBID  string `json:"-" validate:"required"`
+	// Filter 仅在匹配标签及自定义字段的文档中检索, 如 {"fields": {"product": "X", "version": "3.x"}}
+	Filter *NodeMetaFilter `json:"filter,omitempty"`
+
+	// Attachments 随问题上传的图片或文档, key 为 /share/v1/common/file/upload 返回的 key
+	Attachments []ChatAttachment `json:"attachments,omitempty" validate:"max=5,dive"`
+
+	KBID string `json:"-" validate:"required"`
+	// AppID 机器人分发到其他知识库的问题仍记在收到问题的应用下, 为空时使用 KBID 下 AppType 的应用
 	AppID string `json:"-"`
+	// MergeKBIDs 与 KBID 合并检索的知识库, 由机器人分发设置指定
+	MergeKBIDs []string `json:"-"`
 
 	ModelInfo *Model `json:"-"`
 
 	RemoteIP string           `json:"-"`
 	Info     ConversationInfo `json:"-"`
+
+	// ReuseConversation 会话 id 由机器人渠道指定, 首条消息时创建会话, 之后延续该会话
+	ReuseConversation bool `json:"-"`
+}
+
+type ChatAttachment struct {
+	Key  string `json:"key" validate:"required_without=Data"`
+	Name string `json:"name"`
+
+	// Data 机器人下载的媒体文件, 不经过对象存储
+	Data        []byte `json:"-"`
+	ContentType string `json:"-"`
 }
 
 type ConversationInfo struct {
 	UserInfo UserInfo `json:"user_info"`
+	// ReplyRoute 机器人回复该会话所需的渠道信息, 如 slack 的 channel 和 thread_ts, 用于人工回复
+	ReplyRoute map[string]string `json:"reply_route,omitempty"`
 }
 
 type UserInfo struct {
@@ -35,6 +58,8 @@ type UserInfo struct {
 	RealName   string      `json:"real_name"`
 	Email      string      `json:"email"`
 	Avatar     string      `json:"avatar"` // avatar
+	// Departments 机器人渠道中用户所属部门 ID
+	Departments []string `json:"departments,omitempty"`
 }
 
 func (s *ConversationInfo) Scan(value any) error {
@@ -71,6 +96,8 @@ type ChatSearchReq struct {
 	Message      string `json:"message" validate:"required"`
 	CaptchaToken string `json:"captcha_token"`
 
+	Filter *NodeMetaFilter `json:"filter,omitempty"`
+
 	KBID string `json:"-" validate:"required"`
 
 	RemoteIP   string `json:"-"`
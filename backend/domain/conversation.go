@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/cloudwego/eino/schema"
+
+	"github.com/chaitin/panda-wiki/consts"
 )
 
 type Conversation struct {
@@ -21,6 +23,34 @@ type Conversation struct {
 	RemoteIP  string           `json:"remote_ip"`
 	Info      ConversationInfo `json:"info" gorm:"type:jsonb"`
 	CreatedAt time.Time        `json:"created_at"`
+
+	// escalation to human agents
+	EscalationStatus consts.EscalationStatus `json:"escalation_status"`
+	EscalationReason consts.EscalationReason `json:"escalation_reason"`
+	EscalatedAt      *time.Time              `json:"escalated_at"`
+	ResolvedAt       *time.Time              `json:"resolved_at"`
+}
+
+// ConversationAttachment is the text extracted from a file the user attached to a question,
+// it is context for the rest of the conversation and never indexed into the knowledge base
+type ConversationAttachment struct {
+	ID             string                `json:"id" gorm:"primaryKey"`
+	ConversationID string                `json:"conversation_id"`
+	MessageID      string                `json:"message_id"`
+	KBID           string                `json:"kb_id"`
+	Name           string                `json:"name"`
+	Type           consts.AttachmentType `json:"type"`
+	Content        string                `json:"content"`
+	Error          string                `json:"error"`
+	CreatedAt      time.Time             `json:"created_at"`
+}
+
+// ConversationKey maps a thread, reply chain or chat of a bot channel to the conversation it continues
+type ConversationKey struct {
+	AppID          string    `json:"app_id" gorm:"primaryKey"`
+	Key            string    `json:"key" gorm:"primaryKey"`
+	ConversationID string    `json:"conversation_id"`
+	UpdatedAt      time.Time `json:"updated_at"`
 }
 
 type ConversationMessage struct {
@@ -48,6 +78,9 @@ type ConversationMessage struct {
 
 	// parent_id
 	ParentID string `json:"parent_id"`
+
+	// AgentID the admin user who replied as a human agent, empty for ai answers
+	AgentID string `json:"agent_id"`
 }
 
 type FeedBackInfo struct {
@@ -85,6 +118,8 @@ type ConversationListReq struct {
 
 	RemoteIP *string `json:"remote_ip" query:"remote_ip"`
 
+	EscalationStatus *consts.EscalationStatus `json:"escalation_status" query:"escalation_status"`
+
 	Pager
 }
 
@@ -102,6 +137,10 @@ type ConversationListItem struct {
 	CreatedAt time.Time `json:"created_at"`
 
 	FeedBackInfo *FeedBackInfo `json:"feedback_info" gorm:"-"` // 用户反馈信息
+
+	EscalationStatus consts.EscalationStatus `json:"escalation_status"`
+	EscalationReason consts.EscalationReason `json:"escalation_reason"`
+	EscalatedAt      *time.Time              `json:"escalated_at"`
 }
 
 type ConversationDetailResp struct {
@@ -116,6 +155,11 @@ type ConversationDetailResp struct {
 	IPAddress *IPAddress `json:"ip_address" gorm:"-"`
 
 	CreatedAt time.Time `json:"created_at"`
+
+	EscalationStatus consts.EscalationStatus `json:"escalation_status"`
+	EscalationReason consts.EscalationReason `json:"escalation_reason"`
+	EscalatedAt      *time.Time              `json:"escalated_at"`
+	ResolvedAt       *time.Time              `json:"resolved_at"`
 }
 
 type MessageListReq struct {
@@ -1,6 +1,9 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var ErrModelNotConfigured = errors.New("model not configured")
 
@@ -15,3 +18,53 @@ var ErrPermissionDenied = errors.New("permission denied")
 var ErrInternalServerError = errors.New("internal server error")
 
 var ErrMaxNodeLimitReached = errors.New("max node limit reached")
+
+var ErrNodeReviewRequired = errors.New("node review required")
+
+var ErrNodeReviewOutdated = errors.New("node changed after review submitted")
+
+var ErrNodeReviewStatusInvalid = errors.New("node review status invalid")
+
+var ErrNodeReviewSelfDecide = fmt.Errorf("%w: submitter can not review own submission", ErrPermissionDenied)
+
+var ErrNodeMetaInvalid = errors.New("node meta invalid")
+
+var ErrNodeTemplateTypeInvalid = errors.New("node template can only create documents")
+
+var ErrMailReplyStatusInvalid = errors.New("mail reply is not a pending draft")
+
+var ErrMailBotNotRunning = errors.New("mail bot is not enabled")
+
+var ErrAuthInactive = errors.New("auth is deactivated")
+
+var ErrAuthExists = errors.New("auth already exists")
+
+var ErrMaxAuthLimitReached = errors.New("max auth limit reached")
+
+var ErrLoginLocked = errors.New("too many failed login attempts")
+
+var ErrLoginChallengeExpired = errors.New("login challenge expired")
+
+var ErrTwoFactorCodeInvalid = errors.New("invalid two-factor code")
+
+var ErrTwoFactorRequired = errors.New("two-factor authentication is required for admins")
+
+var ErrKBRoleInUse = errors.New("kb role is assigned to users")
+
+var ErrShareLinkInvalid = errors.New("share link is invalid, expired or revoked")
+
+var ErrShareLinkPasswordRequired = errors.New("share link password is required")
+
+var ErrShareLinkPasswordIncorrect = errors.New("share link password is incorrect")
+
+var ErrAccessRequestPending = errors.New("an access request for this node is already pending")
+
+var ErrAccessRequestNotNeeded = errors.New("the reader already has access to this node")
+
+var ErrAccessRequestStatusInvalid = errors.New("access request is not pending")
+
+var ErrAccessGrantRequiresGroup = errors.New("answerable access can only be granted through an auth group")
+
+var ErrAuthGroupSynced = errors.New("auth group is managed by directory sync")
+
+var ErrEscalationUnsupported = errors.New("the channel of the conversation does not support human replies")
@@ -0,0 +1,25 @@
+package domain
+
+// EscalationSettings 转人工设置, stored in settings table.
+// Only channels of AppType.SupportsEscalation are handed over, the others keep getting ai answers,
+// WeChat customer service transfers to its own servicers with the keywords instead
+type EscalationSettings struct {
+	Enabled bool `json:"enabled"`
+	// Keywords 用户消息包含任一关键词时转人工
+	Keywords []string `json:"keywords"`
+	// OnNoResult 未检索到相关文档时转人工
+	OnNoResult bool `json:"on_no_result"`
+	// DislikeThreshold 同一用户 24 小时内点踩次数达到该值时转人工, 0 表示不启用
+	DislikeThreshold int `json:"dislike_threshold"`
+	// HandoffMessage 转人工后回复用户的消息
+	HandoffMessage string `json:"handoff_message"`
+
+	// 值班通知, 群机器人或通用 webhook
+	Webhooks []NotifyWebhook `json:"webhooks"`
+	// Emails 值班邮箱, 使用通知设置中的 SMTP 发送
+	Emails []string `json:"emails"`
+}
+
+const DefaultHandoffMessage = "已为您转接人工客服, 请耐心等待回复。"
+
+var DefaultEscalationKeywords = []string{"转人工", "人工客服", "人工服务", "human agent", "talk to a human"}
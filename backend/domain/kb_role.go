@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/chaitin/panda-wiki/consts"
+)
+
+// table: kb_roles
+//
+// 自定义角色在整个实例内共享，按知识库分配给成员
+type KBRole struct {
+	ID           string         `json:"id" gorm:"primaryKey"`
+	Name         string         `json:"name"`
+	Description  string         `json:"description"`
+	Capabilities pq.StringArray `json:"capabilities" gorm:"type:text[]"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+func (KBRole) TableName() string {
+	return "kb_roles"
+}
+
+func (r *KBRole) KBCapabilities() []consts.KBCapability {
+	capabilities := make([]consts.KBCapability, 0, len(r.Capabilities))
+	for _, c := range r.Capabilities {
+		capabilities = append(capabilities, consts.KBCapability(c))
+	}
+	return capabilities
+}
+
+// KBCapabilityInfo 能力说明及拥有该能力的内置权限
+type KBCapabilityInfo struct {
+	Capability  consts.KBCapability       `json:"capability"`
+	Permissions []consts.UserKBPermission `json:"permissions"`
+}
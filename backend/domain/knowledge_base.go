@@ -103,7 +103,8 @@ type KnowledgeBaseDetail struct {
 	Name string `json:"name"`
 
 	DatasetID      string                  `json:"dataset_id"`
-	Perm           consts.UserKBPermission `json:"perm"` // 用户对知识库的权限
+	Perm           consts.UserKBPermission `json:"perm"`         // 用户对知识库的权限，自定义角色时为空
+	Capabilities   []consts.KBCapability   `json:"capabilities"` // 用户在知识库内的能力
 	AccessSettings AccessSettings          `json:"access_settings" gorm:"type:jsonb"`
 
 	CreatedAt time.Time `json:"created_at"`
@@ -134,6 +135,8 @@ type CreateKBReleaseReq struct {
 	Message string   `json:"message" validate:"required"`
 	Tag     string   `json:"tag" validate:"required"`
 	NodeIDs []string `json:"node_ids"` // create release after these nodes published
+	// Broadcast 向订阅的群聊推送本次发布的文档
+	Broadcast bool `json:"broadcast"`
 }
 
 type KBReleaseListItemResp struct {
@@ -3,7 +3,11 @@ package domain
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
+	"unicode/utf8"
+
+	"github.com/chaitin/panda-wiki/consts"
 )
 
 var SystemPrompt = `
@@ -63,8 +67,38 @@ var UserQuestionFormatter = `
 <documents>
 {{.Documents}}
 </documents>
+{{- if .Attachments}}
+
+<attachments>
+以下是用户在本次会话中上传的附件，图片为识别出的文字和描述，请结合附件理解用户的问题：
+{{.Attachments}}
+</attachments>
+{{- end}}
 `
 
+// FormatAttachments renders the attachments of a conversation for the prompt, the newest are kept within limit runes
+func FormatAttachments(attachments []*ConversationAttachment, limit int) string {
+	parts := make([]string, 0, len(attachments))
+	for i := len(attachments) - 1; i >= 0; i-- {
+		attachment := attachments[i]
+		kind := "文档"
+		if attachment.Type == consts.AttachmentTypeImage {
+			kind = "图片"
+		}
+		content := attachment.Content
+		if attachment.Error != "" {
+			content = fmt.Sprintf("（%s，无法读取附件内容）", attachment.Error)
+		}
+		part := fmt.Sprintf("<attachment>\n名称: %s\n类型: %s\n内容:\n%s\n</attachment>", attachment.Name, kind, content)
+		if limit -= utf8.RuneCountInString(part); limit < 0 {
+			break
+		}
+		parts = append(parts, part)
+	}
+	slices.Reverse(parts)
+	return strings.Join(parts, "\n")
+}
+
 // processContentWithBaseURL adds baseURL prefix to static-file URLs in content
 func processContentWithBaseURL(content, baseURL string) string {
 	if baseURL == "" {
@@ -111,9 +145,11 @@ func processContentWithBaseURL(content, baseURL string) string {
 	return processedContent
 }
 
-func FormatNodeChunks(nodeChunks []*RankedNodeChunks, baseURL string) string {
+// FormatNodeChunks writes the documents of the prompt, baseURLs are the site urls by knowledge base id
+func FormatNodeChunks(nodeChunks []*RankedNodeChunks, baseURLs map[string]string) string {
 	documents := make([]string, 0)
 	for _, result := range nodeChunks {
+		baseURL := baseURLs[result.KBID]
 		document := strings.Builder{}
 		document.WriteString(fmt.Sprintf("<document>\nID: %s\n标题: %s\nURL: %s\n内容:\n", result.NodeID, result.NodeName, result.GetURL(baseURL)))
 		for _, chunk := range result.Chunks {
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/chaitin/panda-wiki/consts"
+)
+
+// table: mail_replies
+// every answer of the mail bot is recorded, replies awaiting approval are kept as pending drafts
+type MailReply struct {
+	ID             string                 `json:"id" gorm:"primaryKey"`
+	KBID           string                 `json:"kb_id"`
+	AppID          string                 `json:"app_id"`
+	ConversationID string                 `json:"conversation_id"`
+	ToAddress      string                 `json:"to_address"`
+	ToName         string                 `json:"to_name"`
+	Subject        string                 `json:"subject"`
+	InReplyTo      string                 `json:"in_reply_to"`
+	References     StringList             `json:"references" gorm:"type:jsonb"`
+	Question       string                 `json:"question"`
+	Answer         string                 `json:"answer"` // markdown, rendered as text and html when sent
+	Status         consts.MailReplyStatus `json:"status"`
+	Error          string                 `json:"error"`
+	ReviewerID     string                 `json:"reviewer_id"`
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+	SentAt         *time.Time             `json:"sent_at"`
+}
+
+func (MailReply) TableName() string {
+	return "mail_replies"
+}
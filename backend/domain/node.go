@@ -55,6 +55,11 @@ type Node struct {
 	Permissions NodePermissions `json:"permissions" gorm:"type:jsonb"`
 	CreatedAt   time.Time       `json:"created_at"`
 	UpdatedAt   time.Time       `json:"updated_at"`
+
+	OwnerID         string     `json:"owner_id"`
+	ReviewInterval  int        `json:"review_interval"` // days, 0 means never expires
+	ReviewedAt      *time.Time `json:"reviewed_at"`
+	StaleNotifiedAt *time.Time `json:"stale_notified_at"`
 }
 
 func (Node) TableName() string {
@@ -118,9 +123,11 @@ type NodeGroupDetail struct {
 }
 
 type NodeMeta struct {
-	Summary     string `json:"summary"`
-	Emoji       string `json:"emoji"`
-	ContentType string `json:"content_type"`
+	Summary     string            `json:"summary"`
+	Emoji       string            `json:"emoji"`
+	ContentType string            `json:"content_type"`
+	Tags        []string          `json:"tags,omitempty"`
+	Fields      map[string]string `json:"fields,omitempty"` // 自定义字段, key 见 NodeMetaSchema
 }
 
 func (d *NodeMeta) Value() (driver.Value, error) {
@@ -140,7 +147,7 @@ type CreateNodeReq struct {
 	ParentID string   `json:"parent_id"`
 	Type     NodeType `json:"type" validate:"required,oneof=1 2"`
 
-	Name    string `json:"name" validate:"required"`
+	Name    string `json:"name" validate:"required_without=TemplateID"`
 	Content string `json:"content"`
 
 	Emoji       string  `json:"emoji"`
@@ -150,11 +157,23 @@ type CreateNodeReq struct {
 	MaxNode int `json:"-"`
 
 	Position *float64 `json:"position"`
+
+	OwnerID        string `json:"owner_id"`                                  // default to creator
+	ReviewInterval int    `json:"review_interval" validate:"min=0,max=3650"` // days
+
+	Tags   []string          `json:"tags"`
+	Fields map[string]string `json:"fields"`
+
+	TemplateID  string           `json:"template_id"` // 从模板创建, 未填写的字段使用模板中的值
+	Permissions *NodePermissions `json:"-"`
 }
 
 type GetNodeListReq struct {
 	KBID   string `json:"kb_id" query:"kb_id" validate:"required"`
 	Search string `json:"search" query:"search"`
+
+	Tags   []string `json:"tags" query:"tags[]"`
+	Fields []string `json:"fields" query:"fields[]"` // key=value
 }
 
 type NodeListItemResp struct {
@@ -175,6 +194,14 @@ type NodeListItemResp struct {
 	Creator     string          `json:"creator"`
 	Editor      string          `json:"editor"`
 	Permissions NodePermissions `json:"permissions" gorm:"type:jsonb"`
+
+	OwnerID        string     `json:"owner_id"`
+	Owner          string     `json:"owner"`
+	ReviewInterval int        `json:"review_interval"`
+	ReviewedAt     *time.Time `json:"reviewed_at"`
+
+	Tags   StringList `json:"tags" gorm:"type:jsonb"`
+	Fields StringMap  `json:"fields" gorm:"type:jsonb"`
 }
 
 type NodeContentChunk struct {
@@ -188,6 +215,7 @@ type NodeContentChunk struct {
 }
 
 type RankedNodeChunks struct {
+	KBID          string
 	NodeID        string
 	NodeName      string
 	NodeSummary   string
@@ -208,6 +236,7 @@ type ChunkListItemResp struct {
 }
 
 type NodeContentChunkSSE struct {
+	KBID          string   `json:"kb_id"`
 	NodeID        string   `json:"node_id"`
 	Name          string   `json:"name"`
 	Summary       string   `json:"summary"`
@@ -242,6 +271,12 @@ type UpdateNodeReq struct {
 	Summary     *string  `json:"summary"`
 	Position    *float64 `json:"position"`
 	ContentType *string  `json:"content_type"`
+
+	OwnerID        *string `json:"owner_id"`
+	ReviewInterval *int    `json:"review_interval" validate:"omitempty,min=0,max=3650"` // days
+
+	Tags   *[]string          `json:"tags"`
+	Fields *map[string]string `json:"fields"`
 }
 
 type ShareNodeListItemResp struct {
@@ -253,6 +288,8 @@ type ShareNodeListItemResp struct {
 	Emoji       string          `json:"emoji"`
 	UpdatedAt   time.Time       `json:"updated_at"`
 	Permissions NodePermissions `json:"permissions" gorm:"type:jsonb"`
+	Tags        StringList      `json:"tags" gorm:"type:jsonb"`
+	Fields      StringMap       `json:"fields" gorm:"type:jsonb"`
 }
 
 func (n *ShareNodeListItemResp) GetURL(baseURL string) string {
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/chaitin/panda-wiki/consts"
+)
+
+// table: node_access_requests
+//
+// 读者申请访问部分开放的文档，由知识库管理者批准或拒绝
+type NodeAccessRequest struct {
+	ID            string                         `json:"id" gorm:"primaryKey"`
+	KBID          string                         `json:"kb_id" gorm:"column:kb_id"`
+	NodeID        string                         `json:"node_id"`
+	AuthID        uint                           `json:"auth_id"`
+	Perm          consts.NodePermName            `json:"perm"` // visitable 或 answerable
+	Reason        string                         `json:"reason"`
+	Status        consts.NodeAccessRequestStatus `json:"status"`
+	GrantType     consts.NodeAccessGrantType     `json:"grant_type"`
+	AuthGroupID   uint                           `json:"auth_group_id"` // GrantType 为 auth_group 时加入的用户组
+	ReviewerID    string                         `json:"reviewer_id"`
+	ReviewComment string                         `json:"review_comment"`
+	CreatedAt     time.Time                      `json:"created_at"`
+	ReviewedAt    *time.Time                     `json:"reviewed_at"`
+}
+
+func (NodeAccessRequest) TableName() string {
+	return "node_access_requests"
+}
+
+// table: node_auth_grants
+//
+// 单个读者对单个文档的访问授权，与用户组权限同时生效，仅在文档部分开放时有意义。
+// 可问答权限依赖写入向量库的用户组，不能按读者单独授权
+type NodeAuthGrant struct {
+	ID        uint                `json:"id" gorm:"primaryKey"`
+	KBID      string              `json:"kb_id" gorm:"column:kb_id"`
+	NodeID    string              `json:"node_id"`
+	AuthID    uint                `json:"auth_id"`
+	Perm      consts.NodePermName `json:"perm"`
+	RequestID string              `json:"request_id"`
+	CreatedBy string              `json:"created_by"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+func (NodeAuthGrant) TableName() string {
+	return "node_auth_grants"
+}
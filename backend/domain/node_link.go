@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/chaitin/panda-wiki/consts"
+)
+
+// table: node_links
+type NodeLink struct {
+	ID           uint                `json:"id" gorm:"primaryKey"`
+	KBID         string              `json:"kb_id"`
+	SourceNodeID string              `json:"source_node_id"`
+	URL          string              `json:"url"`
+	Type         consts.NodeLinkType `json:"type"`
+	TargetNodeID string              `json:"target_node_id"` // internal links only
+	CreatedAt    time.Time           `json:"created_at"`
+}
+
+func (NodeLink) TableName() string {
+	return "node_links"
+}
+
+// table: external_link_checks
+type ExternalLinkCheck struct {
+	URL        string    `json:"url" gorm:"primaryKey"`
+	StatusCode int       `json:"status_code"`
+	Error      string    `json:"error"`
+	Broken     bool      `json:"broken"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+func (ExternalLinkCheck) TableName() string {
+	return "external_link_checks"
+}
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type NodeMetaFieldType string
+
+const (
+	NodeMetaFieldTypeText   NodeMetaFieldType = "text"
+	NodeMetaFieldTypeNumber NodeMetaFieldType = "number"
+	NodeMetaFieldTypeDate   NodeMetaFieldType = "date" // 2006-01-02
+	NodeMetaFieldTypeSelect NodeMetaFieldType = "select"
+)
+
+var nodeMetaFieldKeyRe = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// NodeMetaField 自定义字段定义
+type NodeMetaField struct {
+	Key     string            `json:"key" validate:"required"`
+	Name    string            `json:"name" validate:"required"`
+	Type    NodeMetaFieldType `json:"type" validate:"required,oneof=text number date select"`
+	Options []string          `json:"options"` // select only
+}
+
+// NodeMetaSchema 知识库标签词表及自定义字段, stored in settings table
+type NodeMetaSchema struct {
+	Tags   []string        `json:"tags"`
+	Fields []NodeMetaField `json:"fields"`
+}
+
+func (s *NodeMetaSchema) Check() error {
+	keys := make(map[string]struct{}, len(s.Fields))
+	for _, field := range s.Fields {
+		if !nodeMetaFieldKeyRe.MatchString(field.Key) {
+			return fmt.Errorf("%w: invalid field key %q", ErrNodeMetaInvalid, field.Key)
+		}
+		if _, ok := keys[field.Key]; ok {
+			return fmt.Errorf("%w: duplicated field key %q", ErrNodeMetaInvalid, field.Key)
+		}
+		keys[field.Key] = struct{}{}
+		if field.Type == NodeMetaFieldTypeSelect && len(field.Options) == 0 {
+			return fmt.Errorf("%w: select field %q has no options", ErrNodeMetaInvalid, field.Key)
+		}
+	}
+	return nil
+}
+
+// ValidateNodeMeta checks tags against the vocabulary and field values against their types
+func (s *NodeMetaSchema) ValidateNodeMeta(tags []string, fields map[string]string) error {
+	for _, tag := range tags {
+		if !slices.Contains(s.Tags, tag) {
+			return fmt.Errorf("%w: tag %q is not in the vocabulary", ErrNodeMetaInvalid, tag)
+		}
+	}
+	for key, value := range fields {
+		idx := slices.IndexFunc(s.Fields, func(f NodeMetaField) bool { return f.Key == key })
+		if idx < 0 {
+			return fmt.Errorf("%w: field %q is not defined", ErrNodeMetaInvalid, key)
+		}
+		if value == "" {
+			continue
+		}
+		field := s.Fields[idx]
+		switch field.Type {
+		case NodeMetaFieldTypeNumber:
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return fmt.Errorf("%w: field %q must be a number", ErrNodeMetaInvalid, key)
+			}
+		case NodeMetaFieldTypeDate:
+			if _, err := time.Parse(time.DateOnly, value); err != nil {
+				return fmt.Errorf("%w: field %q must be a date like 2006-01-02", ErrNodeMetaInvalid, key)
+			}
+		case NodeMetaFieldTypeSelect:
+			if !slices.Contains(field.Options, value) {
+				return fmt.Errorf("%w: field %q must be one of %v", ErrNodeMetaInvalid, key, field.Options)
+			}
+		}
+	}
+	return nil
+}
+
+// NodeMetaFilter 按标签和自定义字段筛选文档, 所有条件需同时满足, 目录不参与筛选
+type NodeMetaFilter struct {
+	Tags   []string          `json:"tags"`
+	Fields map[string]string `json:"fields"`
+}
+
+func (f *NodeMetaFilter) IsEmpty() bool {
+	return f == nil || (len(f.Tags) == 0 && len(f.Fields) == 0)
+}
+
+// ParseNodeMetaFilter builds a filter from query params, fields are given as key=value
+func ParseNodeMetaFilter(tags, fields []string) (*NodeMetaFilter, error) {
+	filter := &NodeMetaFilter{Tags: tags, Fields: make(map[string]string, len(fields))}
+	for _, kv := range fields {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%w: field filter %q must be key=value", ErrNodeMetaInvalid, kv)
+		}
+		filter.Fields[key] = value
+	}
+	return filter, nil
+}
+
+// StringList is a json array column such as meta->'tags'
+type StringList []string
+
+func (l *StringList) Scan(value any) error {
+	if value == nil {
+		*l = StringList{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New(fmt.Sprint("invalid string list type:", value))
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+func (l StringList) Value() (driver.Value, error) {
+	return json.Marshal(l)
+}
+
+// StringMap is a json object column such as meta->'fields'
+type StringMap map[string]string
+
+func (m *StringMap) Scan(value any) error {
+	if value == nil {
+		*m = StringMap{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New(fmt.Sprint("invalid string map type:", value))
+	}
+	return json.Unmarshal(bytes, m)
+}
+
+func (m StringMap) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
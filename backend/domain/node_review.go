@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/chaitin/panda-wiki/consts"
+)
+
+// table: node_reviews
+type NodeReview struct {
+	ID          string                  `json:"id" gorm:"primaryKey"`
+	KBID        string                  `json:"kb_id"`
+	NodeID      string                  `json:"node_id" gorm:"uniqueIndex"`
+	Status      consts.NodeReviewStatus `json:"status"`
+	SubmitterID string                  `json:"submitter_id"`
+	ReviewerID  string                  `json:"reviewer_id"`
+	Comment     string                  `json:"comment"`
+	SubmittedAt time.Time               `json:"submitted_at"`
+	ReviewedAt  *time.Time              `json:"reviewed_at"`
+	CreatedAt   time.Time               `json:"created_at"`
+	UpdatedAt   time.Time               `json:"updated_at"`
+}
+
+func (NodeReview) TableName() string {
+	return "node_reviews"
+}
+
+// IsOutdated reports whether the node was edited after it was submitted for review
+func (r *NodeReview) IsOutdated(editTime time.Time) bool {
+	return editTime.After(r.SubmittedAt)
+}
+
+// table: node_review_logs
+type NodeReviewLog struct {
+	ID        uint                    `json:"id" gorm:"primaryKey"`
+	KBID      string                  `json:"kb_id"`
+	NodeID    string                  `json:"node_id"`
+	UserID    string                  `json:"user_id"`
+	Action    consts.NodeReviewAction `json:"action"`
+	Comment   string                  `json:"comment"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+func (NodeReviewLog) TableName() string {
+	return "node_review_logs"
+}
+
+// NodeReviewPolicy 知识库发布审核策略, 存储于 settings 表
+type NodeReviewPolicy struct {
+	Enabled     bool     `json:"enabled"`
+	ReviewerIDs []string `json:"reviewer_ids"` // 为空时由拥有审核能力的用户审核
+}
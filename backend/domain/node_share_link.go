@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// table: node_share_links
+//
+// 分享链接授权访问一个文档或文件夹及其下所有文档，不受文档访问权限和读者认证限制
+type NodeShareLink struct {
+	ID           string     `json:"id" gorm:"primaryKey"`
+	KBID         string     `json:"kb_id" gorm:"column:kb_id"`
+	NodeID       string     `json:"node_id"`
+	Draft        bool       `json:"draft"` // 预览未发布的内容
+	PasswordHash string     `json:"-"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	ViewCount    int64      `json:"view_count"`
+	LastViewedAt *time.Time `json:"last_viewed_at"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	CreatedBy    string     `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+func (NodeShareLink) TableName() string {
+	return "node_share_links"
+}
+
+// Active 未撤销且未过期
+func (l *NodeShareLink) Active(now time.Time) bool {
+	return l.RevokedAt == nil && now.Before(l.ExpiresAt)
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// table: node_templates
+type NodeTemplate struct {
+	ID          string          `json:"id" gorm:"primaryKey"`
+	KBID        string          `json:"kb_id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Title       string          `json:"title"` // 新文档默认标题, 支持占位符
+	Content     string          `json:"content"`
+	ContentType string          `json:"content_type"`
+	Emoji       string          `json:"emoji"`
+	Tags        StringList      `json:"tags" gorm:"type:jsonb"`
+	Fields      StringMap       `json:"fields" gorm:"type:jsonb"`
+	Permissions NodePermissions `json:"permissions" gorm:"type:jsonb"` // 未设置时使用默认权限
+	CreatorID   string          `json:"creator_id"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+func (NodeTemplate) TableName() string {
+	return "node_templates"
+}
+
+func (t *NodeTemplate) HasPermissions() bool {
+	return t.Permissions.Answerable != "" && t.Permissions.Visitable != "" && t.Permissions.Visible != ""
+}
+
+// template placeholders, filled on the server side when a node is created from a template
+const (
+	TemplatePlaceholderDate     = "date"     // 2006-01-02
+	TemplatePlaceholderDatetime = "datetime" // 2006-01-02 15:04
+	TemplatePlaceholderAuthor   = "author"
+	TemplatePlaceholderParent   = "parent"
+	TemplatePlaceholderKB       = "kb"
+	TemplatePlaceholderTitle    = "title"
+)
+
+var templatePlaceholderRe = regexp.MustCompile(`\{\{\s*([a-z_]+)\s*\}\}`)
+
+// FillTemplatePlaceholders replaces {{name}} placeholders, unknown placeholders are kept as is
+func FillTemplatePlaceholders(s string, vars map[string]string) string {
+	if !strings.Contains(s, "{{") {
+		return s
+	}
+	return templatePlaceholderRe.ReplaceAllStringFunc(s, func(m string) string {
+		name := templatePlaceholderRe.FindStringSubmatch(m)[1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return m
+	})
+}
@@ -0,0 +1,74 @@
+package domain
+
+import "github.com/chaitin/panda-wiki/consts"
+
+type NotifyWebhookType string
+
+const (
+	NotifyWebhookTypeDingTalk NotifyWebhookType = "dingtalk"
+	NotifyWebhookTypeFeishu   NotifyWebhookType = "feishu"
+	NotifyWebhookTypeWeCom    NotifyWebhookType = "wecom"
+	NotifyWebhookTypeGeneric  NotifyWebhookType = "generic"
+)
+
+// NotifySettings 知识库通知设置, stored in settings table
+type NotifySettings struct {
+	SMTP     SMTPSettings    `json:"smtp"`
+	Webhooks []NotifyWebhook `json:"webhooks"`
+	// FeishuBotDM 通过已配置的飞书机器人按邮箱私信通知用户
+	FeishuBotDM bool `json:"feishu_bot_dm"`
+	// Broadcasts 发布版本时通过已配置的机器人向群聊推送文档更新
+	Broadcasts []ReleaseBroadcast `json:"broadcasts"`
+}
+
+// ReleaseBroadcast 订阅文档更新的群聊
+type ReleaseBroadcast struct {
+	Name string `json:"name"`
+	// AppType 使用该类型机器人应用的凭证发送: 钉钉 3, 飞书 4, 企业微信应用 5, Discord 7, Slack 12
+	AppType AppType `json:"app_type" validate:"oneof=3 4 5 7 12"`
+	// ChatID 飞书 chat_id, 钉钉 openConversationId, 企业微信群聊 chatid, Discord 及 Slack 频道 ID
+	ChatID string `json:"chat_id" validate:"required"`
+	// NodeIDs 仅推送这些目录下的文档, 为空时推送全部文档
+	NodeIDs []string `json:"node_ids"`
+}
+
+// ReleaseDigestNode 版本中发布的文档
+type ReleaseDigestNode struct {
+	ID          string
+	Name        string
+	Summary     string
+	Visitable   consts.NodeAccessPerm
+	AncestorIDs []string
+	IsNew       bool
+}
+
+type SMTPSettings struct {
+	Enabled  bool   `json:"enabled"`
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	From     string `json:"from"`
+	// UseTLS 使用隐式 TLS 连接 (通常为 465 端口), 否则在服务端支持时使用 STARTTLS
+	UseTLS bool `json:"use_tls"`
+}
+
+// NotifyWebhook 群机器人 webhook
+type NotifyWebhook struct {
+	Name   string            `json:"name"`
+	Type   NotifyWebhookType `json:"type" validate:"oneof=dingtalk feishu wecom generic"`
+	URL    string            `json:"url" validate:"required,url"`
+	Secret string            `json:"secret"` // 加签密钥, 仅钉钉和飞书
+}
+
+type NotifyMessage struct {
+	Title   string `json:"title"`
+	Content string `json:"content"` // markdown
+}
+
+// NotifyRecipient 通知接收人
+type NotifyRecipient struct {
+	UserID  string `json:"user_id"`
+	Account string `json:"account"`
+	Email   string `json:"email"`
+}
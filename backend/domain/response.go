@@ -14,4 +14,7 @@ var (
 	ErrCodePermissionDenied = PWResponseErrCode{"Permission Denied", false, nil, 40003}
 	ErrCodeNotFound         = PWResponseErrCode{"Not Found", false, nil, 40004}
 	ErrCodeInternalError    = PWResponseErrCode{"Internal Error", false, nil, 50001}
+
+	ErrCodeShareLinkInvalid          = PWResponseErrCode{"Share Link Invalid", false, nil, 40010}
+	ErrCodeShareLinkPasswordRequired = PWResponseErrCode{"Share Link Password Required", false, nil, 40011}
 )
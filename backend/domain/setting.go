@@ -6,8 +6,14 @@ import (
 )
 
 const (
-	SettingKeySystemPrompt = "system_prompt"
-	SettingBlockWords      = "block_words"
+	SettingKeySystemPrompt  = "system_prompt"
+	SettingBlockWords       = "block_words"
+	SettingNodeReviewPolicy = "node_review_policy"
+	SettingNotify           = "notify_settings"
+	SettingNodeMetaSchema   = "node_meta_schema"
+	SettingEscalation       = "escalation_settings"
+	SettingLoginSecurity    = "login_security_policy"
+	SettingAuditLog         = "audit_log_settings"
 )
 
 // table: settings
@@ -3,6 +3,8 @@ package domain
 import (
 	"time"
 
+	"github.com/lib/pq"
+
 	"github.com/chaitin/panda-wiki/consts"
 )
 
@@ -11,8 +13,18 @@ type User struct {
 	Account    string          `json:"account" gorm:"uniqueIndex"`
 	Password   string          `json:"password"`
 	Role       consts.UserRole `json:"role" gorm:"default:'user'"`
+	Email      string          `json:"email"`
 	CreatedAt  time.Time       `json:"created_at"`
 	LastAccess time.Time       `json:"last_access" gorm:"default:null"`
+
+	TOTPSecret    string         `json:"-" gorm:"column:totp_secret"`
+	TOTPEnabled   bool           `json:"totp_enabled" gorm:"column:totp_enabled"`
+	RecoveryCodes pq.StringArray `json:"-" gorm:"column:recovery_codes;type:text[]"` // 恢复码的 sha256，使用后移除
+}
+
+// LoginSecurityPolicy 全局登录安全策略，保存在 kb_id 为空的设置中
+type LoginSecurityPolicy struct {
+	RequireAdmin2FA bool `json:"require_admin_2fa"` // 管理员必须开启两步验证，未开启的在下次登录时绑定
 }
 
 // KBUsers 知识库用户关联表（多对多关系）
@@ -21,6 +33,7 @@ type KBUsers struct {
 	KBId      string                  `json:"kb_id" gorm:"uniqueIndex:idx_uniq_kb_users_kb_id_user_id"`
 	UserId    string                  `json:"user_id" gorm:"uniqueIndex:idx_uniq_kb_users_kb_id_user_id"`
 	Perm      consts.UserKBPermission `json:"perm"`
+	RoleID    string                  `json:"role_id"` // 自定义角色，不为空时 Perm 不生效
 	CreatedAt time.Time               `json:"created_at"`
 }
 
@@ -32,3 +45,14 @@ type UserAccessTime struct {
 	UserID    string    `json:"user_id"`
 	Timestamp time.Time `json:"timestamp"`
 }
+
+// UserSession 管理后台登录会话，JWT 中的 sid 对应该会话，删除后 token 立即失效
+type UserSession struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"user_id"`
+	UserAgent    string    `json:"user_agent"`
+	IP           string    `json:"ip"`
+	CreatedAt    time.Time `json:"created_at"`
+	LastActiveAt time.Time `json:"last_active_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
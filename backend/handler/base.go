@@ -45,6 +45,9 @@ func (h *BaseHandler) NewResponseWithData(c echo.Context, data any) error {
 }
 
 func (h *BaseHandler) NewResponseWithErrCode(c echo.Context, resp domain.PWResponseErrCode) error {
+	if !resp.Success {
+		c.Set(domain.ResponseErrorKey, true)
+	}
 	return c.JSON(http.StatusOK, resp)
 }
 
@@ -57,6 +60,7 @@ func (h *BaseHandler) NewResponseWithError(c echo.Context, msg string, err error
 	} else {
 		traceID = uuid.New().String()
 	}
+	c.Set(domain.ResponseErrorKey, true)
 	h.baseLogger.LogAttrs(c.Request().Context(), slog.LevelError, msg, slog.String("trace_id", traceID), slog.Any("error", err))
 	return c.JSON(http.StatusOK, domain.PWResponse{
 		Success: false,
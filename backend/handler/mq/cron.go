@@ -2,27 +2,41 @@ package mq
 
 import (
 	"context"
+	"time"
 
 	"github.com/robfig/cron/v3"
 
+	"github.com/chaitin/panda-wiki/domain"
 	"github.com/chaitin/panda-wiki/log"
 	"github.com/chaitin/panda-wiki/repo/pg"
 	"github.com/chaitin/panda-wiki/usecase"
 )
 
 type CronHandler struct {
-	logger      *log.Logger
-	statRepo    *pg.StatRepository
-	statUseCase *usecase.StatUseCase
-	nodeUseCase *usecase.NodeUsecase
+	logger       *log.Logger
+	statRepo     *pg.StatRepository
+	statUseCase  *usecase.StatUseCase
+	nodeUseCase  *usecase.NodeUsecase
+	staleUseCase *usecase.NodeStaleUsecase
+	linkUseCase  *usecase.NodeLinkUsecase
+	syncUseCase  *usecase.AuthGroupSyncUsecase
+	auditUseCase *usecase.AuditUsecase
+
+	conversationRepo *pg.ConversationRepository
 }
 
-func NewStatCronHandler(logger *log.Logger, statRepo *pg.StatRepository, statUseCase *usecase.StatUseCase, nodeUseCase *usecase.NodeUsecase) (*CronHandler, error) {
+func NewStatCronHandler(logger *log.Logger, statRepo *pg.StatRepository, statUseCase *usecase.StatUseCase, nodeUseCase *usecase.NodeUsecase, staleUseCase *usecase.NodeStaleUsecase, linkUseCase *usecase.NodeLinkUsecase, syncUseCase *usecase.AuthGroupSyncUsecase, auditUseCase *usecase.AuditUsecase, conversationRepo *pg.ConversationRepository) (*CronHandler, error) {
 	h := &CronHandler{
-		statRepo:    statRepo,
-		statUseCase: statUseCase,
-		nodeUseCase: nodeUseCase,
-		logger:      logger.WithModule("handler.mq.cron"),
+		statRepo:     statRepo,
+		statUseCase:  statUseCase,
+		nodeUseCase:  nodeUseCase,
+		staleUseCase: staleUseCase,
+		linkUseCase:  linkUseCase,
+		syncUseCase:  syncUseCase,
+		auditUseCase: auditUseCase,
+
+		conversationRepo: conversationRepo,
+		logger:           logger.WithModule("handler.mq.cron"),
 	}
 	cron := cron.New()
 
@@ -59,6 +73,41 @@ func NewStatCronHandler(logger *log.Logger, statRepo *pg.StatRepository, statUse
 	}
 	h.logger.Info("add cron job", log.String("cron_id", "sync_rag_node_status"))
 
+	// 每天9点通知负责人复查过时文档
+	if _, err := cron.AddFunc("0 9 * * *", h.NotifyStaleNodes); err != nil {
+		h.logger.Error("failed to add cron job for notifying stale nodes", log.Error(err))
+		return nil, err
+	}
+	h.logger.Info("add cron job", log.String("cron_id", "notify_stale_nodes"))
+
+	// 每天4点重建链接索引并检查外部链接
+	if _, err := cron.AddFunc("17 4 * * *", h.CheckNodeLinks); err != nil {
+		h.logger.Error("failed to add cron job for checking node links", log.Error(err))
+		return nil, err
+	}
+	h.logger.Info("add cron job", log.String("cron_id", "check_node_links"))
+
+	// 每天5点清理已过期的机器人会话映射
+	if _, err := cron.AddFunc("29 5 * * *", h.CleanupConversationKeys); err != nil {
+		h.logger.Error("failed to add cron job for cleaning up conversation keys", log.Error(err))
+		return nil, err
+	}
+	h.logger.Info("add cron job", log.String("cron_id", "cleanup_conversation_keys"))
+
+	// 每小时同步目录中的部门/用户组到读者用户组
+	if _, err := cron.AddFunc("41 * * * *", h.SyncAuthGroups); err != nil {
+		h.logger.Error("failed to add cron job for syncing auth groups", log.Error(err))
+		return nil, err
+	}
+	h.logger.Info("add cron job", log.String("cron_id", "sync_auth_groups"))
+
+	// 每天3点清理超过保留天数的审计日志
+	if _, err := cron.AddFunc("47 3 * * *", h.PruneAuditLogs); err != nil {
+		h.logger.Error("failed to add cron job for pruning audit logs", log.Error(err))
+		return nil, err
+	}
+	h.logger.Info("add cron job", log.String("cron_id", "prune_audit_logs"))
+
 	cron.Start()
 	h.logger.Info("start cron jobs")
 	return h, nil
@@ -102,3 +151,53 @@ func (h *CronHandler) SyncRagNodeStatus() {
 	}
 	h.logger.Info("sync rag node status successful")
 }
+
+func (h *CronHandler) NotifyStaleNodes() {
+	h.logger.Info("notify stale nodes start")
+	err := h.staleUseCase.NotifyStaleNodes(context.Background())
+	if err != nil {
+		h.logger.Error("notify stale nodes failed", log.Error(err))
+		return
+	}
+	h.logger.Info("notify stale nodes successful")
+}
+
+func (h *CronHandler) CheckNodeLinks() {
+	h.logger.Info("check node links start")
+	err := h.linkUseCase.CheckAllLinks(context.Background())
+	if err != nil {
+		h.logger.Error("check node links failed", log.Error(err))
+		return
+	}
+	h.logger.Info("check node links successful")
+}
+
+func (h *CronHandler) CleanupConversationKeys() {
+	h.logger.Info("cleanup conversation keys start")
+	err := h.conversationRepo.DeleteConversationKeysBefore(context.Background(), time.Now().Add(-domain.MaxBotConversationWindow))
+	if err != nil {
+		h.logger.Error("cleanup conversation keys failed", log.Error(err))
+		return
+	}
+	h.logger.Info("cleanup conversation keys successful")
+}
+
+func (h *CronHandler) SyncAuthGroups() {
+	h.logger.Info("sync auth groups start")
+	err := h.syncUseCase.SyncAll(context.Background())
+	if err != nil {
+		h.logger.Error("sync auth groups failed", log.Error(err))
+		return
+	}
+	h.logger.Info("sync auth groups successful")
+}
+
+func (h *CronHandler) PruneAuditLogs() {
+	h.logger.Info("prune audit logs start")
+	err := h.auditUseCase.PruneAuditLogs(context.Background())
+	if err != nil {
+		h.logger.Error("prune audit logs failed", log.Error(err))
+		return
+	}
+	h.logger.Info("prune audit logs successful")
+}
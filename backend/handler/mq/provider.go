@@ -27,6 +27,13 @@ var ProviderSet = wire.NewSet(
 	usecase.NewLLMUsecase,
 	usecase.NewStatUseCase,
 	usecase.NewNodeUsecase,
+	usecase.NewNodeLinkUsecase,
+	usecase.NewNodeTemplateUsecase,
+	usecase.NewNotifyUsecase,
+	usecase.NewNodeStaleUsecase,
+	usecase.NewAuthUsecase,
+	usecase.NewAuthGroupSyncUsecase,
+	usecase.NewAuditUsecase,
 
 	NewRAGMQHandler,
 	NewRagDocUpdateHandler,
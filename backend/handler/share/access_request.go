@@ -0,0 +1,111 @@
+package share
+
+import (
+	"errors"
+
+	"github.com/labstack/echo/v4"
+
+	v1 "github.com/chaitin/panda-wiki/api/share/v1"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/handler"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/usecase"
+)
+
+type ShareAccessHandler struct {
+	*handler.BaseHandler
+	logger  *log.Logger
+	usecase *usecase.NodeAccessRequestUsecase
+}
+
+func NewShareAccessHandler(
+	baseHandler *handler.BaseHandler,
+	echo *echo.Echo,
+	usecase *usecase.NodeAccessRequestUsecase,
+	logger *log.Logger,
+) *ShareAccessHandler {
+	h := &ShareAccessHandler{
+		BaseHandler: baseHandler,
+		logger:      logger.WithModule("handler.share.access_request"),
+		usecase:     usecase,
+	}
+
+	group := echo.Group("share/v1/access_request", h.ShareAuthMiddleware.Authorize)
+	group.POST("", h.CreateAccessRequest)
+	group.GET("/list", h.ListMyAccessRequests)
+
+	return h
+}
+
+// CreateAccessRequest 申请文档权限
+//
+//	@Tags			ShareAccessRequest
+//	@Summary		申请文档权限
+//	@Description	已登录的读者申请部分开放文档的访问或问答权限，提交后通知知识库管理者
+//	@ID				share-CreateAccessRequest
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-KB-ID	header		string						true	"kb id"
+//	@Param			param	body		v1.AccessRequestCreateReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.AccessRequestItem}
+//	@Router			/share/v1/access_request [post]
+func (h *ShareAccessHandler) CreateAccessRequest(c echo.Context) error {
+	kbID := c.Request().Header.Get("X-KB-ID")
+	if kbID == "" {
+		return h.NewResponseWithError(c, "kb_id is required", nil)
+	}
+	authID := domain.GetAuthID(c)
+	if authID == 0 {
+		return h.NewResponseWithError(c, "请登录后申请", domain.ErrPermissionDenied)
+	}
+
+	var req v1.AccessRequestCreateReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+
+	item, err := h.usecase.CreateRequest(c.Request().Context(), kbID, authID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrAccessRequestPending):
+			return h.NewResponseWithError(c, "已提交过申请，请等待处理", err)
+		case errors.Is(err, domain.ErrAccessRequestNotNeeded):
+			return h.NewResponseWithError(c, "你已拥有该文档的权限", err)
+		case errors.Is(err, domain.ErrPermissionDenied):
+			return h.NewResponseWithErrCode(c, domain.ErrCodePermissionDenied)
+		}
+		return h.NewResponseWithError(c, "create access request failed", err)
+	}
+	return h.NewResponseWithData(c, item)
+}
+
+// ListMyAccessRequests 我的权限申请
+//
+//	@Tags			ShareAccessRequest
+//	@Summary		我的权限申请
+//	@Description	当前读者在知识库内最近的申请及处理结果
+//	@ID				share-ListMyAccessRequests
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-KB-ID	header		string	true	"kb id"
+//	@Success		200		{object}	domain.PWResponse{data=[]v1.AccessRequestItem}
+//	@Router			/share/v1/access_request/list [get]
+func (h *ShareAccessHandler) ListMyAccessRequests(c echo.Context) error {
+	kbID := c.Request().Header.Get("X-KB-ID")
+	if kbID == "" {
+		return h.NewResponseWithError(c, "kb_id is required", nil)
+	}
+	authID := domain.GetAuthID(c)
+	if authID == 0 {
+		return h.NewResponseWithError(c, "请登录后查看", domain.ErrPermissionDenied)
+	}
+
+	items, err := h.usecase.ListMyRequests(c.Request().Context(), kbID, authID)
+	if err != nil {
+		return h.NewResponseWithError(c, "list access requests failed", err)
+	}
+	return h.NewResponseWithData(c, items)
+}
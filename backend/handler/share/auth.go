@@ -2,6 +2,8 @@ package share
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
 
 	"github.com/gorilla/sessions"
 	"github.com/labstack/echo/v4"
@@ -20,6 +22,7 @@ type ShareAuthHandler struct {
 	logger      *log.Logger
 	kbUsecase   *usecase.KnowledgeBaseUsecase
 	authUsecase *usecase.AuthUsecase
+	limiter     *usecase.LoginLimiter
 }
 
 func NewShareAuthHandler(
@@ -28,20 +31,26 @@ func NewShareAuthHandler(
 	logger *log.Logger,
 	kbUsecase *usecase.KnowledgeBaseUsecase,
 	authUsecase *usecase.AuthUsecase,
+	limiter *usecase.LoginLimiter,
 ) *ShareAuthHandler {
 	h := &ShareAuthHandler{
 		BaseHandler: baseHandler,
 		logger:      logger.WithModule("handler.share.auth"),
 		kbUsecase:   kbUsecase,
 		authUsecase: authUsecase,
+		limiter:     limiter,
 	}
 
-	shareAuthMiddleware := middleware.NewShareAuthMiddleware(logger, kbUsecase)
+	shareAuthMiddleware := middleware.NewShareAuthMiddleware(logger, kbUsecase, authUsecase)
 
 	share := e.Group("share/v1/auth", shareAuthMiddleware.CheckForbidden)
 	share.GET("/get", h.AuthGet)
 	share.POST("/login/simple", h.AuthLoginSimple)
 	share.POST("/github", h.AuthGitHub)
+	share.POST("/ldap", h.AuthLDAP)
+	share.POST("/cas", h.AuthCAS)
+	share.POST("/oauth", h.AuthOAuth)
+	share.POST("/saml", h.AuthSAML)
 	return h
 }
 
@@ -113,9 +122,24 @@ func (h *ShareAuthHandler) AuthLoginSimple(c echo.Context) error {
 		return h.NewResponseWithError(c, "simple auth is not enabled", nil)
 	}
 
-	if req.Password != kb.AccessSettings.SimpleAuth.Password {
+	// 按知识库和来源 IP 计数，防止暴力猜解口令
+	limitKey := "simple:" + kb.ID + ":" + c.RealIP()
+	if err := h.limiter.Check(ctx, limitKey); err != nil {
+		if errors.Is(err, domain.ErrLoginLocked) {
+			return h.NewResponseWithError(c, "登录失败次数过多，请稍后再试", err)
+		}
+		return h.NewResponseWithError(c, "check login limit failed", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(req.Password), []byte(kb.AccessSettings.SimpleAuth.Password)) != 1 {
+		if err := h.limiter.Fail(ctx, limitKey); err != nil {
+			h.logger.Error("record login failure failed", log.Error(err))
+		}
 		return h.NewResponseWithError(c, "simple auth password is incorrect", nil)
 	}
+	if err := h.limiter.Reset(ctx, limitKey); err != nil {
+		h.logger.Error("reset login failures failed", log.Error(err))
+	}
 
 	s := c.Get(domain.SessionCacheKey)
 	if s == nil {
@@ -181,3 +205,167 @@ func (h *ShareAuthHandler) AuthGitHub(c echo.Context) error {
 		Url: url,
 	})
 }
+
+// AuthLDAP LDAP登录
+//
+//	@Tags			ShareAuth
+//	@Summary		LDAP登录
+//	@Description	LDAP账号密码登录
+//	@ID				v1-AuthLDAP
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-KB-ID	header		string			true	"kb id"
+//	@Param			param	body		v1.AuthLDAPReq	true	"para"
+//	@Success		200		{object}	domain.Response
+//	@Router			/share/v1/auth/ldap [post]
+func (h *ShareAuthHandler) AuthLDAP(c echo.Context) error {
+	ctx := context.WithValue(c.Request().Context(), consts.ContextKeyEdition, consts.GetLicenseEdition(c))
+
+	var req v1.AuthLDAPReq
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	kbID := c.Request().Header.Get("X-KB-ID")
+	if kbID == "" {
+		return h.NewResponseWithError(c, "kb_id is required", nil)
+	}
+
+	auth, err := h.authUsecase.LDAPLogin(ctx, kbID, req)
+	if err != nil {
+		h.logger.Warn("ldap login failed", log.String("kb_id", kbID), log.String("username", req.Username), log.Error(err))
+		return h.NewResponseWithError(c, "ldap login failed", nil)
+	}
+
+	if err := h.authUsecase.SaveNewSession(c, auth); err != nil {
+		return h.NewResponseWithError(c, "save session failed", err)
+	}
+
+	return h.NewResponseWithData(c, nil)
+}
+
+// AuthCAS CAS登录
+//
+//	@Tags			ShareAuth
+//	@Summary		CAS登录
+//	@Description	获取CAS登录地址
+//	@ID				v1-AuthCAS
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-KB-ID	header		string			true	"kb id"
+//	@Param			param	body		v1.AuthCASReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.AuthCASResp}
+//	@Router			/share/v1/auth/cas [post]
+func (h *ShareAuthHandler) AuthCAS(c echo.Context) error {
+	ctx := context.WithValue(c.Request().Context(), consts.ContextKeyEdition, consts.GetLicenseEdition(c))
+
+	var req v1.AuthCASReq
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	kbID := c.Request().Header.Get("X-KB-ID")
+	if kbID == "" {
+		return h.NewResponseWithError(c, "kb_id is required", nil)
+	}
+	req.KbID = kbID
+
+	valid, err := h.authUsecase.ValidateRedirectUrl(ctx, req.KbID, req.RedirectUrl)
+	if err != nil || !valid {
+		return h.NewResponseWithError(c, "invalid redirect url", err)
+	}
+
+	url, err := h.authUsecase.GenerateCASAuthUrl(ctx, req)
+	if err != nil {
+		return h.NewResponseWithError(c, "GenerateCASAuthUrl failed", err)
+	}
+
+	return h.NewResponseWithData(c, v1.AuthCASResp{
+		Url: url,
+	})
+}
+
+// AuthOAuth OAuth登录
+//
+//	@Tags			ShareAuth
+//	@Summary		OAuth登录
+//	@Description	获取OAuth2/OIDC授权地址
+//	@ID				v1-AuthOAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-KB-ID	header		string			true	"kb id"
+//	@Param			param	body		v1.AuthOAuthReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.AuthOAuthResp}
+//	@Router			/share/v1/auth/oauth [post]
+func (h *ShareAuthHandler) AuthOAuth(c echo.Context) error {
+	ctx := context.WithValue(c.Request().Context(), consts.ContextKeyEdition, consts.GetLicenseEdition(c))
+
+	var req v1.AuthOAuthReq
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	kbID := c.Request().Header.Get("X-KB-ID")
+	if kbID == "" {
+		return h.NewResponseWithError(c, "kb_id is required", nil)
+	}
+	req.KbID = kbID
+
+	valid, err := h.authUsecase.ValidateRedirectUrl(ctx, req.KbID, req.RedirectUrl)
+	if err != nil || !valid {
+		return h.NewResponseWithError(c, "invalid redirect url", err)
+	}
+
+	url, err := h.authUsecase.GenerateOAuthAuthUrl(ctx, req)
+	if err != nil {
+		return h.NewResponseWithError(c, "GenerateOAuthAuthUrl failed", err)
+	}
+
+	return h.NewResponseWithData(c, v1.AuthOAuthResp{
+		Url: url,
+	})
+}
+
+// AuthSAML SAML登录
+//
+//	@Tags			ShareAuth
+//	@Summary		SAML登录
+//	@Description	获取SAML IdP登录地址
+//	@ID				v1-AuthSAML
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-KB-ID	header		string			true	"kb id"
+//	@Param			param	body		v1.AuthSAMLReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.AuthSAMLResp}
+//	@Router			/share/v1/auth/saml [post]
+func (h *ShareAuthHandler) AuthSAML(c echo.Context) error {
+	ctx := context.WithValue(c.Request().Context(), consts.ContextKeyEdition, consts.GetLicenseEdition(c))
+
+	var req v1.AuthSAMLReq
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	kbID := c.Request().Header.Get("X-KB-ID")
+	if kbID == "" {
+		return h.NewResponseWithError(c, "kb_id is required", nil)
+	}
+	req.KbID = kbID
+
+	valid, err := h.authUsecase.ValidateRedirectUrl(ctx, req.KbID, req.RedirectUrl)
+	if err != nil || !valid {
+		return h.NewResponseWithError(c, "invalid redirect url", err)
+	}
+
+	url, err := h.authUsecase.GenerateSAMLAuthUrl(ctx, req)
+	if err != nil {
+		return h.NewResponseWithError(c, "GenerateSAMLAuthUrl failed", err)
+	}
+
+	return h.NewResponseWithData(c, v1.AuthSAMLResp{
+		Url: url,
+	})
+}
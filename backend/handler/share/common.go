@@ -51,7 +51,7 @@ func NewShareCommonHandler(
 //
 //	@Tags			ShareFile
 //	@Summary		文件上传
-//	@Description	前台用户上传文件,目前只支持图片文件上传
+//	@Description	前台用户上传文件,支持图片和文档,返回的 key 可作为问答的附件
 //	@ID				share-FileUpload
 //	@Accept			multipart/form-data
 //	@Produce		json
@@ -73,8 +73,8 @@ func (h *ShareCommonHandler) FileUpload(c echo.Context) error {
 		return h.NewResponseWithError(c, "failed to get file", err)
 	}
 
-	if !utils.IsImageFile(file.Filename) {
-		return h.NewResponseWithError(c, "只支持图片文件上传", fmt.Errorf("unsupported file type: %s", file.Filename))
+	if !utils.IsImageFile(file.Filename) && !utils.IsDocumentFile(file.Filename) {
+		return h.NewResponseWithError(c, "只支持图片和文档上传", fmt.Errorf("unsupported file type: %s", file.Filename))
 	}
 
 	// validate captcha token
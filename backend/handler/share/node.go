@@ -43,8 +43,10 @@ func NewShareNodeHandler(
 //	@Tags			share_node
 //	@Accept			json
 //	@Produce		json
-//	@Param			X-KB-ID	header		string	true	"kb id"
-//	@Success		200		{object}	domain.Response
+//	@Param			X-KB-ID		header		string		true	"kb id"
+//	@Param			tags[]		query		[]string	false	"filter by tags"
+//	@Param			fields[]	query		[]string	false	"filter by custom fields, key=value"
+//	@Success		200			{object}	domain.Response
 //	@Router			/share/v1/node/list [get]
 func (h *ShareNodeHandler) GetNodeList(c echo.Context) error {
 	kbID := c.Request().Header.Get("X-KB-ID")
@@ -52,7 +54,13 @@ func (h *ShareNodeHandler) GetNodeList(c echo.Context) error {
 		return h.NewResponseWithError(c, "kb_id is required", nil)
 	}
 
-	nodes, err := h.usecase.GetNodeReleaseListByKBID(c.Request().Context(), kbID, domain.GetAuthID(c))
+	params := c.QueryParams()
+	filter, err := domain.ParseNodeMetaFilter(params["tags[]"], params["fields[]"])
+	if err != nil {
+		return h.NewResponseWithError(c, err.Error(), err)
+	}
+
+	nodes, err := h.usecase.GetNodeReleaseListByKBID(c.Request().Context(), kbID, domain.GetAuthID(c), filter)
 	if err != nil {
 		return h.NewResponseWithError(c, "failed to get node list", err)
 	}
@@ -42,10 +42,26 @@ func NewOpenapiV1Handler(
 	OpenapiGroup := e.Group("/share/v1/openapi")
 
 	OpenapiGroup.Any("/github/callback", h.GitHubCallback)
+	OpenapiGroup.Any("/cas/callback", h.CASCallback)
+	OpenapiGroup.Any("/oauth/callback", h.OAuthCallback)
+	OpenapiGroup.GET("/saml/metadata/:kb_id", h.SAMLMetadata)
+	OpenapiGroup.POST("/saml/acs/:kb_id", h.SAMLACS)
 
 	// lark机器人
 	OpenapiGroup.POST("/lark/bot/:kb_id", h.LarkBot)
 
+	// slack机器人 Events API 及交互回调
+	OpenapiGroup.POST("/slack/bot/:kb_id", h.SlackBot)
+
+	// telegram机器人 webhook
+	OpenapiGroup.POST("/telegram/bot/:kb_id", h.TelegramBot)
+
+	// teams机器人 Bot Framework 消息端点
+	OpenapiGroup.POST("/teams/:kb_id", h.TeamsBot)
+
+	// 邮件问答 入站邮件推送
+	OpenapiGroup.POST("/mail/:kb_id", h.MailBot)
+
 	return h
 }
 
@@ -83,6 +99,140 @@ func (h *OpenapiV1Handler) GitHubCallback(c echo.Context) error {
 	return c.Redirect(http.StatusFound, redirectUrl)
 }
 
+// CASCallback CAS回调
+//
+//	@Tags			ShareOpenapi
+//	@Summary		CAS回调
+//	@Description	CAS回调
+//	@ID				v1-CASCallback
+//	@Accept			json
+//	@Produce		json
+//	@Param			param	query		v1.CASCallbackReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse
+//	@Router			/share/v1/openapi/cas/callback [get]
+func (h *OpenapiV1Handler) CASCallback(c echo.Context) error {
+	ctx := context.WithValue(c.Request().Context(), consts.ContextKeyEdition, consts.GetLicenseEdition(c))
+
+	var req v1.CASCallbackReq
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if req.Ticket == "" {
+		return h.NewResponseWithError(c, "ticket is required", nil)
+	}
+
+	auth, redirectUrl, err := h.authUseCase.CASCallback(ctx, req)
+	if err != nil {
+		return h.NewResponseWithError(c, "handle callback failed", err)
+	}
+
+	if err := h.authUseCase.SaveNewSession(c, auth); err != nil {
+		return h.NewResponseWithError(c, "save session failed", err)
+	}
+
+	return c.Redirect(http.StatusFound, redirectUrl)
+}
+
+// OAuthCallback OAuth回调
+//
+//	@Tags			ShareOpenapi
+//	@Summary		OAuth回调
+//	@Description	OAuth2/OIDC回调
+//	@ID				v1-OAuthCallback
+//	@Accept			json
+//	@Produce		json
+//	@Param			param	query		v1.OAuthCallbackReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse
+//	@Router			/share/v1/openapi/oauth/callback [get]
+func (h *OpenapiV1Handler) OAuthCallback(c echo.Context) error {
+	ctx := context.WithValue(c.Request().Context(), consts.ContextKeyEdition, consts.GetLicenseEdition(c))
+
+	var req v1.OAuthCallbackReq
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if req.Code == "" {
+		return h.NewResponseWithError(c, "code is required", nil)
+	}
+
+	auth, redirectUrl, err := h.authUseCase.OAuthCallback(ctx, req)
+	if err != nil {
+		return h.NewResponseWithError(c, "handle callback failed", err)
+	}
+
+	if err := h.authUseCase.SaveNewSession(c, auth); err != nil {
+		return h.NewResponseWithError(c, "save session failed", err)
+	}
+
+	return c.Redirect(http.StatusFound, redirectUrl)
+}
+
+// SAMLMetadata SAML SP元数据
+//
+//	@Tags			ShareOpenapi
+//	@Summary		SAML SP元数据
+//	@Description	SAML SP元数据, 在 IdP 中注册
+//	@ID				v1-SAMLMetadata
+//	@Produce		xml
+//	@Param			kb_id	path	string	true	"知识库ID"
+//	@Success		200
+//	@Router			/share/v1/openapi/saml/metadata/{kb_id} [get]
+func (h *OpenapiV1Handler) SAMLMetadata(c echo.Context) error {
+	ctx := context.WithValue(c.Request().Context(), consts.ContextKeyEdition, consts.GetLicenseEdition(c))
+
+	kbID := c.Param("kb_id")
+	if kbID == "" {
+		return h.NewResponseWithError(c, "kb_id is required", nil)
+	}
+
+	metadata, err := h.authUseCase.SAMLMetadata(ctx, kbID, c.Scheme()+"://"+c.Request().Host)
+	if err != nil {
+		return h.NewResponseWithError(c, "get saml metadata failed", err)
+	}
+
+	return c.Blob(http.StatusOK, "application/samlmetadata+xml", metadata)
+}
+
+// SAMLACS SAML断言消费服务
+//
+//	@Tags			ShareOpenapi
+//	@Summary		SAML断言消费服务
+//	@Description	IdP 以 HTTP-POST 提交 SAMLResponse, 支持 SP 及 IdP 发起的登录
+//	@ID				v1-SAMLACS
+//	@Accept			x-www-form-urlencoded
+//	@Produce		json
+//	@Param			kb_id			path		string	true	"知识库ID"
+//	@Param			SAMLResponse	formData	string	true	"SAMLResponse"
+//	@Param			RelayState		formData	string	false	"RelayState"
+//	@Success		200				{object}	domain.PWResponse
+//	@Router			/share/v1/openapi/saml/acs/{kb_id} [post]
+func (h *OpenapiV1Handler) SAMLACS(c echo.Context) error {
+	ctx := context.WithValue(c.Request().Context(), consts.ContextKeyEdition, consts.GetLicenseEdition(c))
+
+	kbID := c.Param("kb_id")
+	if kbID == "" {
+		return h.NewResponseWithError(c, "kb_id is required", nil)
+	}
+	req := v1.SAMLACSReq{
+		SAMLResponse: c.FormValue("SAMLResponse"),
+		RelayState:   c.FormValue("RelayState"),
+	}
+	if req.SAMLResponse == "" {
+		return h.NewResponseWithError(c, "SAMLResponse is required", nil)
+	}
+
+	auth, redirectUrl, err := h.authUseCase.SAMLACS(ctx, kbID, c.Scheme()+"://"+c.Request().Host, req)
+	if err != nil {
+		return h.NewResponseWithError(c, "handle saml response failed", err)
+	}
+
+	if err := h.authUseCase.SaveNewSession(c, auth); err != nil {
+		return h.NewResponseWithError(c, "save session failed", err)
+	}
+
+	return c.Redirect(http.StatusFound, redirectUrl)
+}
+
 // LarkBot Lark机器人请求
 //
 //	@Tags			ShareOpenapi
@@ -155,3 +305,179 @@ func (h *OpenapiV1Handler) LarkBot(c echo.Context) error {
 
 	return c.JSONBlob(eventResp.StatusCode, eventResp.Body)
 }
+
+// SlackBot Slack机器人请求
+//
+//	@Tags			ShareOpenapi
+//	@Summary		Slack机器人请求
+//	@Description	Slack Events API 及交互组件回调, 使用 Socket Mode 时无需配置
+//	@ID				v1-SlackBot
+//	@Accept			json
+//	@Produce		json
+//	@Param			kb_id	path		string	true	"知识库ID"
+//	@Success		200		{object}	domain.PWResponse
+//	@Router			/share/v1/openapi/slack/bot/{kb_id} [post]
+func (h *OpenapiV1Handler) SlackBot(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	kbID := c.Param("kb_id")
+	if kbID == "" {
+		return h.NewResponseWithError(c, "kb_id is required", nil)
+	}
+
+	appInfo, err := h.appCase.GetAppDetailByKBIDAndAppType(ctx, kbID, domain.AppTypeSlackBot)
+	if err != nil {
+		h.logger.Error("failed to get app detail", log.Error(err), log.String("kb_id", kbID))
+		return h.NewResponseWithError(c, "failed to get app detail", err)
+	}
+	if appInfo.Settings.SlackBotSettings.IsEnabled == nil || !*appInfo.Settings.SlackBotSettings.IsEnabled {
+		return h.NewResponseWithError(c, "slack bot is not enabled", nil)
+	}
+	client, ok := h.appCase.GetSlackBotClient(appInfo.ID)
+	if !ok {
+		return h.NewResponseWithError(c, "slack bot is not running", nil)
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return h.NewResponseWithError(c, "failed to read request body", err)
+	}
+	defer c.Request().Body.Close()
+
+	status, resp := client.HandleHTTP(c.Request().Header, body)
+	if resp == nil {
+		return c.NoContent(status)
+	}
+	return c.JSONBlob(status, resp)
+}
+
+// TelegramBot Telegram机器人请求
+//
+//	@Tags			ShareOpenapi
+//	@Summary		Telegram机器人请求
+//	@Description	Telegram webhook 回调, 使用长轮询时无需配置
+//	@ID				v1-TelegramBot
+//	@Accept			json
+//	@Produce		json
+//	@Param			kb_id	path		string	true	"知识库ID"
+//	@Success		200		{object}	domain.PWResponse
+//	@Router			/share/v1/openapi/telegram/bot/{kb_id} [post]
+func (h *OpenapiV1Handler) TelegramBot(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	kbID := c.Param("kb_id")
+	if kbID == "" {
+		return h.NewResponseWithError(c, "kb_id is required", nil)
+	}
+
+	appInfo, err := h.appCase.GetAppDetailByKBIDAndAppType(ctx, kbID, domain.AppTypeTelegramBot)
+	if err != nil {
+		h.logger.Error("failed to get app detail", log.Error(err), log.String("kb_id", kbID))
+		return h.NewResponseWithError(c, "failed to get app detail", err)
+	}
+	if appInfo.Settings.TelegramBotSettings.IsEnabled == nil || !*appInfo.Settings.TelegramBotSettings.IsEnabled {
+		return h.NewResponseWithError(c, "telegram bot is not enabled", nil)
+	}
+	client, ok := h.appCase.GetTelegramBotClient(appInfo.ID)
+	if !ok || !client.Webhook() {
+		return h.NewResponseWithError(c, "telegram bot webhook is not running", nil)
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return h.NewResponseWithError(c, "failed to read request body", err)
+	}
+	defer c.Request().Body.Close()
+
+	return c.NoContent(client.HandleWebhook(c.Request().Header, body))
+}
+
+// TeamsBot Teams机器人请求
+//
+//	@Tags			ShareOpenapi
+//	@Summary		Teams机器人请求
+//	@Description	Bot Framework 消息端点, 在 Azure Bot 中配置为 Messaging endpoint
+//	@ID				v1-TeamsBot
+//	@Accept			json
+//	@Produce		json
+//	@Param			kb_id	path		string	true	"知识库ID"
+//	@Success		200		{object}	domain.PWResponse
+//	@Router			/share/v1/openapi/teams/{kb_id} [post]
+func (h *OpenapiV1Handler) TeamsBot(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	kbID := c.Param("kb_id")
+	if kbID == "" {
+		return h.NewResponseWithError(c, "kb_id is required", nil)
+	}
+
+	appInfo, err := h.appCase.GetAppDetailByKBIDAndAppType(ctx, kbID, domain.AppTypeTeamsBot)
+	if err != nil {
+		h.logger.Error("failed to get app detail", log.Error(err), log.String("kb_id", kbID))
+		return h.NewResponseWithError(c, "failed to get app detail", err)
+	}
+	if appInfo.Settings.TeamsBotSettings.IsEnabled == nil || !*appInfo.Settings.TeamsBotSettings.IsEnabled {
+		return h.NewResponseWithError(c, "teams bot is not enabled", nil)
+	}
+	client, ok := h.appCase.GetTeamsBotClient(appInfo.ID)
+	if !ok {
+		return h.NewResponseWithError(c, "teams bot is not running", nil)
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return h.NewResponseWithError(c, "failed to read request body", err)
+	}
+	defer c.Request().Body.Close()
+
+	return c.NoContent(client.HandleActivity(ctx, c.Request().Header.Get("Authorization"), body))
+}
+
+// maxInboundMailSize limits the raw mail pushed to the inbound hook
+const maxInboundMailSize = 25 << 20
+
+// MailBot 邮件问答入站邮件
+//
+//	@Tags			ShareOpenapi
+//	@Summary		邮件问答入站邮件
+//	@Description	邮件服务器推送原始邮件 (RFC 5322), 通过 token 参数或 X-Mail-Token 请求头鉴权, 使用 IMAP 收信时无需配置
+//	@ID				v1-MailBot
+//	@Accept			plain
+//	@Produce		json
+//	@Param			kb_id	path		string	true	"知识库ID"
+//	@Param			token	query		string	false	"入站令牌"
+//	@Success		200		{object}	domain.PWResponse
+//	@Router			/share/v1/openapi/mail/{kb_id} [post]
+func (h *OpenapiV1Handler) MailBot(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	kbID := c.Param("kb_id")
+	if kbID == "" {
+		return h.NewResponseWithError(c, "kb_id is required", nil)
+	}
+
+	appInfo, err := h.appCase.GetAppDetailByKBIDAndAppType(ctx, kbID, domain.AppTypeMailBot)
+	if err != nil {
+		h.logger.Error("failed to get app detail", log.Error(err), log.String("kb_id", kbID))
+		return h.NewResponseWithError(c, "failed to get app detail", err)
+	}
+	if appInfo.Settings.MailBotSettings.IsEnabled == nil || !*appInfo.Settings.MailBotSettings.IsEnabled {
+		return h.NewResponseWithError(c, "mail bot is not enabled", nil)
+	}
+	client, ok := h.appCase.GetMailBotClient(appInfo.ID)
+	if !ok {
+		return h.NewResponseWithError(c, "mail bot is not running", nil)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxInboundMailSize))
+	if err != nil {
+		return h.NewResponseWithError(c, "failed to read request body", err)
+	}
+	defer c.Request().Body.Close()
+
+	token := c.QueryParam("token")
+	if token == "" {
+		token = c.Request().Header.Get("X-Mail-Token")
+	}
+	return c.NoContent(client.HandleInbound(token, body))
+}
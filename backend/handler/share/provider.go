@@ -8,6 +8,8 @@ import (
 
 type ShareHandler struct {
 	ShareNodeHandler         *ShareNodeHandler
+	ShareLinkHandler         *ShareLinkHandler
+	ShareAccessHandler       *ShareAccessHandler
 	ShareAppHandler          *ShareAppHandler
 	ShareChatHandler         *ShareChatHandler
 	ShareSitemapHandler      *ShareSitemapHandler
@@ -18,6 +20,7 @@ type ShareHandler struct {
 	ShareWechatHandler       *ShareWechatHandler
 	ShareCaptchaHandler      *ShareCaptchaHandler
 	OpenapiV1Handler         *OpenapiV1Handler
+	SCIMV2Handler            *SCIMV2Handler
 	ShareCommonHandler       *ShareCommonHandler
 }
 
@@ -25,6 +28,8 @@ var ProviderSet = wire.NewSet(
 	captcha.NewCaptcha,
 
 	NewShareNodeHandler,
+	NewShareLinkHandler,
+	NewShareAccessHandler,
 	NewShareAppHandler,
 	NewShareChatHandler,
 	NewShareSitemapHandler,
@@ -36,6 +41,7 @@ var ProviderSet = wire.NewSet(
 	NewShareCaptchaHandler,
 	NewShareCommonHandler,
 	NewOpenapiV1Handler,
+	NewSCIMV2Handler,
 
 	wire.Struct(new(ShareHandler), "*"),
 )
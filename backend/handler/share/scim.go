@@ -0,0 +1,438 @@
+package share
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/handler"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/scim"
+	"github.com/chaitin/panda-wiki/usecase"
+)
+
+const scimAuthConfigKey = "scim_auth_config"
+
+type SCIMV2Handler struct {
+	*handler.BaseHandler
+	logger      *log.Logger
+	scimUsecase *usecase.SCIMUsecase
+}
+
+func NewSCIMV2Handler(
+	e *echo.Echo,
+	baseHandler *handler.BaseHandler,
+	logger *log.Logger,
+	scimUsecase *usecase.SCIMUsecase,
+) *SCIMV2Handler {
+	h := &SCIMV2Handler{
+		BaseHandler: baseHandler,
+		logger:      logger.WithModule("handler.share.scim"),
+		scimUsecase: scimUsecase,
+	}
+
+	// SCIM 2.0 开通接口，知识库由 Bearer Token 确定
+	group := e.Group("/scim/v2", h.Authorize)
+	group.GET("/ServiceProviderConfig", h.ServiceProviderConfig)
+
+	group.GET("/Users", h.ListUsers)
+	group.POST("/Users", h.CreateUser)
+	group.GET("/Users/:id", h.GetUser)
+	group.PUT("/Users/:id", h.ReplaceUser)
+	group.PATCH("/Users/:id", h.PatchUser)
+	group.DELETE("/Users/:id", h.DeleteUser)
+
+	group.GET("/Groups", h.ListGroups)
+	group.POST("/Groups", h.CreateGroup)
+	group.GET("/Groups/:id", h.GetGroup)
+	group.PUT("/Groups/:id", h.ReplaceGroup)
+	group.PATCH("/Groups/:id", h.PatchGroup)
+	group.DELETE("/Groups/:id", h.DeleteGroup)
+
+	return h
+}
+
+func (h *SCIMV2Handler) Authorize(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+		if !ok {
+			return h.respondError(c, scim.NewError(http.StatusUnauthorized, "", "bearer token is required"))
+		}
+		authConfig, err := h.scimUsecase.Authenticate(c.Request().Context(), strings.TrimSpace(token))
+		if err != nil {
+			return h.respondError(c, err)
+		}
+		c.Set(scimAuthConfigKey, authConfig)
+
+		ctx := context.WithValue(c.Request().Context(), consts.ContextKeyEdition, consts.GetLicenseEdition(c))
+		c.SetRequest(c.Request().WithContext(ctx))
+		return next(c)
+	}
+}
+
+func (h *SCIMV2Handler) authConfig(c echo.Context) *domain.AuthConfig {
+	authConfig, _ := c.Get(scimAuthConfigKey).(*domain.AuthConfig)
+	return authConfig
+}
+
+func (h *SCIMV2Handler) baseURL(c echo.Context) string {
+	return c.Scheme() + "://" + c.Request().Host + "/scim/v2"
+}
+
+func (h *SCIMV2Handler) respond(c echo.Context, status int, data any) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return c.Blob(status, scim.ContentType, body)
+}
+
+func (h *SCIMV2Handler) respondError(c echo.Context, err error) error {
+	var scimErr *scim.Error
+	if !errors.As(err, &scimErr) {
+		h.logger.Error("scim request failed", log.String("path", c.Path()), log.Error(err))
+		scimErr = scim.NewError(http.StatusInternalServerError, "", "internal server error")
+	}
+	return h.respond(c, scimErr.Status, scimErr)
+}
+
+// bind IdP 使用 application/scim+json，不经过 echo 的 Bind
+func (h *SCIMV2Handler) bind(c echo.Context, v any) error {
+	if err := json.NewDecoder(c.Request().Body).Decode(v); err != nil {
+		return scim.NewError(http.StatusBadRequest, scim.ErrInvalidSyntax, "invalid request body")
+	}
+	return nil
+}
+
+// listParams 解析 filter、startIndex 及 count
+func (h *SCIMV2Handler) listParams(c echo.Context) (*scim.Filter, int, int, error) {
+	filter, err := scim.ParseFilter(c.QueryParam("filter"))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	startIndex, count := 1, usecase.SCIMMaxResults
+	if v := c.QueryParam("startIndex"); v != "" {
+		if startIndex, err = strconv.Atoi(v); err != nil {
+			return nil, 0, 0, scim.NewError(http.StatusBadRequest, scim.ErrInvalidValue, "invalid startIndex")
+		}
+	}
+	if v := c.QueryParam("count"); v != "" {
+		if count, err = strconv.Atoi(v); err != nil {
+			return nil, 0, 0, scim.NewError(http.StatusBadRequest, scim.ErrInvalidValue, "invalid count")
+		}
+	}
+	return filter, startIndex, count, nil
+}
+
+func (h *SCIMV2Handler) withUserLocation(c echo.Context, user *scim.User) *scim.User {
+	user.Meta.Location = h.baseURL(c) + "/Users/" + user.ID
+	return user
+}
+
+func (h *SCIMV2Handler) withGroupLocation(c echo.Context, group *scim.Group) *scim.Group {
+	group.Meta.Location = h.baseURL(c) + "/Groups/" + group.ID
+	return group
+}
+
+// ServiceProviderConfig SCIM 服务能力
+//
+//	@Tags			SCIM
+//	@Summary		SCIM 服务能力
+//	@Description	SCIM 服务能力
+//	@ID				scim-ServiceProviderConfig
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Success		200	{object}	scim.ServiceProviderConfig
+//	@Router			/scim/v2/ServiceProviderConfig [get]
+func (h *SCIMV2Handler) ServiceProviderConfig(c echo.Context) error {
+	return h.respond(c, http.StatusOK, scim.NewServiceProviderConfig(usecase.SCIMMaxResults))
+}
+
+// ListUsers 读者列表
+//
+//	@Tags			SCIM
+//	@Summary		读者列表
+//	@Description	读者列表，filter 仅支持 userName、externalId、id 的 eq
+//	@ID				scim-ListUsers
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			filter		query		string	false	"filter"
+//	@Param			startIndex	query		int		false	"start index"
+//	@Param			count		query		int		false	"count"
+//	@Success		200			{object}	scim.ListResponse
+//	@Router			/scim/v2/Users [get]
+func (h *SCIMV2Handler) ListUsers(c echo.Context) error {
+	filter, startIndex, count, err := h.listParams(c)
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	resp, err := h.scimUsecase.ListUsers(c.Request().Context(), h.authConfig(c), filter, startIndex, count)
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	for _, user := range resp.Resources.([]*scim.User) {
+		h.withUserLocation(c, user)
+	}
+	return h.respond(c, http.StatusOK, resp)
+}
+
+// GetUser 读者详情
+//
+//	@Tags			SCIM
+//	@Summary		读者详情
+//	@Description	读者详情
+//	@ID				scim-GetUser
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			id	path		string	true	"id"
+//	@Success		200	{object}	scim.User
+//	@Router			/scim/v2/Users/{id} [get]
+func (h *SCIMV2Handler) GetUser(c echo.Context) error {
+	user, err := h.scimUsecase.GetUser(c.Request().Context(), h.authConfig(c), c.Param("id"))
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	return h.respond(c, http.StatusOK, h.withUserLocation(c, user))
+}
+
+// CreateUser 开通读者
+//
+//	@Tags			SCIM
+//	@Summary		开通读者
+//	@Description	开通读者，userName 需与读者登录时的用户标识一致
+//	@ID				scim-CreateUser
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			body	body		scim.User	true	"user"
+//	@Success		201		{object}	scim.User
+//	@Router			/scim/v2/Users [post]
+func (h *SCIMV2Handler) CreateUser(c echo.Context) error {
+	var req scim.User
+	if err := h.bind(c, &req); err != nil {
+		return h.respondError(c, err)
+	}
+	user, err := h.scimUsecase.CreateUser(c.Request().Context(), h.authConfig(c), &req)
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	h.withUserLocation(c, user)
+	c.Response().Header().Set(echo.HeaderLocation, user.Meta.Location)
+	return h.respond(c, http.StatusCreated, user)
+}
+
+// ReplaceUser 更新读者
+//
+//	@Tags			SCIM
+//	@Summary		更新读者
+//	@Description	更新读者，active 为 false 时停用并使已有会话失效
+//	@ID				scim-ReplaceUser
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			id		path		string		true	"id"
+//	@Param			body	body		scim.User	true	"user"
+//	@Success		200		{object}	scim.User
+//	@Router			/scim/v2/Users/{id} [put]
+func (h *SCIMV2Handler) ReplaceUser(c echo.Context) error {
+	var req scim.User
+	if err := h.bind(c, &req); err != nil {
+		return h.respondError(c, err)
+	}
+	user, err := h.scimUsecase.ReplaceUser(c.Request().Context(), h.authConfig(c), c.Param("id"), &req)
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	return h.respond(c, http.StatusOK, h.withUserLocation(c, user))
+}
+
+// PatchUser 修改读者
+//
+//	@Tags			SCIM
+//	@Summary		修改读者
+//	@Description	修改读者，active 为 false 时停用并使已有会话失效
+//	@ID				scim-PatchUser
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			id		path		string				true	"id"
+//	@Param			body	body		scim.PatchRequest	true	"patch"
+//	@Success		200		{object}	scim.User
+//	@Router			/scim/v2/Users/{id} [patch]
+func (h *SCIMV2Handler) PatchUser(c echo.Context) error {
+	var req scim.PatchRequest
+	if err := h.bind(c, &req); err != nil {
+		return h.respondError(c, err)
+	}
+	user, err := h.scimUsecase.PatchUser(c.Request().Context(), h.authConfig(c), c.Param("id"), req.Operations)
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	return h.respond(c, http.StatusOK, h.withUserLocation(c, user))
+}
+
+// DeleteUser 删除读者
+//
+//	@Tags			SCIM
+//	@Summary		删除读者
+//	@Description	删除读者并移出所有用户组，已有会话立即失效
+//	@ID				scim-DeleteUser
+//	@Security		bearerAuth
+//	@Param			id	path	string	true	"id"
+//	@Success		204
+//	@Router			/scim/v2/Users/{id} [delete]
+func (h *SCIMV2Handler) DeleteUser(c echo.Context) error {
+	if err := h.scimUsecase.DeleteUser(c.Request().Context(), h.authConfig(c), c.Param("id")); err != nil {
+		return h.respondError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListGroups 用户组列表
+//
+//	@Tags			SCIM
+//	@Summary		用户组列表
+//	@Description	用户组列表，filter 仅支持 displayName、externalId、id 的 eq
+//	@ID				scim-ListGroups
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			filter				query		string	false	"filter"
+//	@Param			startIndex			query		int		false	"start index"
+//	@Param			count				query		int		false	"count"
+//	@Param			excludedAttributes	query		string	false	"excluded attributes"
+//	@Success		200					{object}	scim.ListResponse
+//	@Router			/scim/v2/Groups [get]
+func (h *SCIMV2Handler) ListGroups(c echo.Context) error {
+	filter, startIndex, count, err := h.listParams(c)
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	withMembers := !strings.Contains(strings.ToLower(c.QueryParam("excludedAttributes")), "members")
+	resp, err := h.scimUsecase.ListGroups(c.Request().Context(), h.authConfig(c), filter, startIndex, count, withMembers)
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	for _, group := range resp.Resources.([]*scim.Group) {
+		h.withGroupLocation(c, group)
+	}
+	return h.respond(c, http.StatusOK, resp)
+}
+
+// GetGroup 用户组详情
+//
+//	@Tags			SCIM
+//	@Summary		用户组详情
+//	@Description	用户组详情
+//	@ID				scim-GetGroup
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			id	path		string	true	"id"
+//	@Success		200	{object}	scim.Group
+//	@Router			/scim/v2/Groups/{id} [get]
+func (h *SCIMV2Handler) GetGroup(c echo.Context) error {
+	group, err := h.scimUsecase.GetGroup(c.Request().Context(), h.authConfig(c), c.Param("id"))
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	return h.respond(c, http.StatusOK, h.withGroupLocation(c, group))
+}
+
+// CreateGroup 创建用户组
+//
+//	@Tags			SCIM
+//	@Summary		创建用户组
+//	@Description	创建用户组，上级用户组通过扩展属性 parent 指定
+//	@ID				scim-CreateGroup
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			body	body		scim.Group	true	"group"
+//	@Success		201		{object}	scim.Group
+//	@Router			/scim/v2/Groups [post]
+func (h *SCIMV2Handler) CreateGroup(c echo.Context) error {
+	var req scim.Group
+	if err := h.bind(c, &req); err != nil {
+		return h.respondError(c, err)
+	}
+	group, err := h.scimUsecase.CreateGroup(c.Request().Context(), h.authConfig(c), &req)
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	h.withGroupLocation(c, group)
+	c.Response().Header().Set(echo.HeaderLocation, group.Meta.Location)
+	return h.respond(c, http.StatusCreated, group)
+}
+
+// ReplaceGroup 更新用户组
+//
+//	@Tags			SCIM
+//	@Summary		更新用户组
+//	@Description	更新用户组
+//	@ID				scim-ReplaceGroup
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			id		path		string		true	"id"
+//	@Param			body	body		scim.Group	true	"group"
+//	@Success		200		{object}	scim.Group
+//	@Router			/scim/v2/Groups/{id} [put]
+func (h *SCIMV2Handler) ReplaceGroup(c echo.Context) error {
+	var req scim.Group
+	if err := h.bind(c, &req); err != nil {
+		return h.respondError(c, err)
+	}
+	group, err := h.scimUsecase.ReplaceGroup(c.Request().Context(), h.authConfig(c), c.Param("id"), &req)
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	return h.respond(c, http.StatusOK, h.withGroupLocation(c, group))
+}
+
+// PatchGroup 修改用户组
+//
+//	@Tags			SCIM
+//	@Summary		修改用户组
+//	@Description	修改用户组名称、上级及成员
+//	@ID				scim-PatchGroup
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			id		path		string				true	"id"
+//	@Param			body	body		scim.PatchRequest	true	"patch"
+//	@Success		200		{object}	scim.Group
+//	@Router			/scim/v2/Groups/{id} [patch]
+func (h *SCIMV2Handler) PatchGroup(c echo.Context) error {
+	var req scim.PatchRequest
+	if err := h.bind(c, &req); err != nil {
+		return h.respondError(c, err)
+	}
+	group, err := h.scimUsecase.PatchGroup(c.Request().Context(), h.authConfig(c), c.Param("id"), req.Operations)
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	return h.respond(c, http.StatusOK, h.withGroupLocation(c, group))
+}
+
+// DeleteGroup 删除用户组
+//
+//	@Tags			SCIM
+//	@Summary		删除用户组
+//	@Description	删除用户组，下级用户组成为顶级用户组，相关文档的可问答权限随之更新
+//	@ID				scim-DeleteGroup
+//	@Security		bearerAuth
+//	@Param			id	path	string	true	"id"
+//	@Success		204
+//	@Router			/scim/v2/Groups/{id} [delete]
+func (h *SCIMV2Handler) DeleteGroup(c echo.Context) error {
+	if err := h.scimUsecase.DeleteGroup(c.Request().Context(), h.authConfig(c), c.Param("id")); err != nil {
+		return h.respondError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
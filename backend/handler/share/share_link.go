@@ -0,0 +1,175 @@
+package share
+
+import (
+	"errors"
+
+	"github.com/labstack/echo/v4"
+
+	v1 "github.com/chaitin/panda-wiki/api/share/v1"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/handler"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/usecase"
+)
+
+// shareAccessHeader 输入分享密码后获得的访问凭证
+const shareAccessHeader = "X-Share-Access"
+
+type ShareLinkHandler struct {
+	*handler.BaseHandler
+	logger  *log.Logger
+	usecase *usecase.NodeShareLinkUsecase
+}
+
+func NewShareLinkHandler(
+	baseHandler *handler.BaseHandler,
+	echo *echo.Echo,
+	usecase *usecase.NodeShareLinkUsecase,
+	logger *log.Logger,
+) *ShareLinkHandler {
+	h := &ShareLinkHandler{
+		BaseHandler: baseHandler,
+		logger:      logger.WithModule("handler.share.share_link"),
+		usecase:     usecase,
+	}
+
+	// 分享链接不需要读者登录，只检查知识库是否被禁止访问
+	group := echo.Group("share/v1/share_link", h.ShareAuthMiddleware.CheckForbidden)
+	group.GET("/info", h.GetShareLinkInfo)
+	group.POST("/auth", h.AuthShareLink)
+	group.GET("/node/list", h.GetShareLinkNodeList)
+	group.GET("/node/detail", h.GetShareLinkNodeDetail)
+
+	return h
+}
+
+func (h *ShareLinkHandler) shareLinkError(c echo.Context, msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrShareLinkInvalid):
+		return h.NewResponseWithErrCode(c, domain.ErrCodeShareLinkInvalid)
+	case errors.Is(err, domain.ErrShareLinkPasswordRequired):
+		return h.NewResponseWithErrCode(c, domain.ErrCodeShareLinkPasswordRequired)
+	case errors.Is(err, domain.ErrShareLinkPasswordIncorrect):
+		return h.NewResponseWithError(c, "分享密码错误", err)
+	case errors.Is(err, domain.ErrLoginLocked):
+		return h.NewResponseWithError(c, "密码错误次数过多，请稍后再试", err)
+	case errors.Is(err, domain.ErrPermissionDenied):
+		return h.NewResponseWithErrCode(c, domain.ErrCodePermissionDenied)
+	default:
+		return h.NewResponseWithError(c, msg, err)
+	}
+}
+
+// GetShareLinkInfo 获取分享链接信息
+//
+//	@Tags			ShareLink
+//	@Summary		获取分享链接信息
+//	@Description	返回分享的文档和是否需要密码，链接无效时返回 40010
+//	@ID				share-GetShareLinkInfo
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-KB-ID	header		string				true	"kb id"
+//	@Param			param	query		v1.ShareLinkInfoReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.ShareLinkInfoResp}
+//	@Router			/share/v1/share_link/info [get]
+func (h *ShareLinkHandler) GetShareLinkInfo(c echo.Context) error {
+	var req v1.ShareLinkInfoReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	info, err := h.usecase.GetLinkInfo(c.Request().Context(), c.Request().Header.Get("X-KB-ID"), req.Token)
+	if err != nil {
+		return h.shareLinkError(c, "get share link failed", err)
+	}
+	return h.NewResponseWithData(c, info)
+}
+
+// AuthShareLink 输入分享密码
+//
+//	@Tags			ShareLink
+//	@Summary		输入分享密码
+//	@Description	密码正确时返回访问凭证，之后的请求通过 X-Share-Access 请求头携带
+//	@ID				share-AuthShareLink
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-KB-ID	header		string				true	"kb id"
+//	@Param			param	body		v1.ShareLinkAuthReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.ShareLinkAuthResp}
+//	@Router			/share/v1/share_link/auth [post]
+func (h *ShareLinkHandler) AuthShareLink(c echo.Context) error {
+	var req v1.ShareLinkAuthReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+
+	resp, err := h.usecase.Authenticate(c.Request().Context(), c.Request().Header.Get("X-KB-ID"), &req, c.RealIP())
+	if err != nil {
+		return h.shareLinkError(c, "auth share link failed", err)
+	}
+	return h.NewResponseWithData(c, resp)
+}
+
+// GetShareLinkNodeList 分享的文档目录
+//
+//	@Tags			ShareLink
+//	@Summary		分享的文档目录
+//	@Description	分享的节点及其下所有子节点，需要密码时返回 40011
+//	@ID				share-GetShareLinkNodeList
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-KB-ID			header		string					true	"kb id"
+//	@Param			X-Share-Access	header		string					false	"access"
+//	@Param			param			query		v1.ShareLinkNodeListReq	true	"para"
+//	@Success		200				{object}	domain.PWResponse{data=[]domain.ShareNodeListItemResp}
+//	@Router			/share/v1/share_link/node/list [get]
+func (h *ShareLinkHandler) GetShareLinkNodeList(c echo.Context) error {
+	var req v1.ShareLinkNodeListReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	nodes, err := h.usecase.GetNodeList(c.Request().Context(), c.Request().Header.Get("X-KB-ID"), req.Token, c.Request().Header.Get(shareAccessHeader))
+	if err != nil {
+		return h.shareLinkError(c, "get share link nodes failed", err)
+	}
+	return h.NewResponseWithData(c, nodes)
+}
+
+// GetShareLinkNodeDetail 分享的文档详情
+//
+//	@Tags			ShareLink
+//	@Summary		分享的文档详情
+//	@Description	只能访问分享的节点及其子节点，每次访问计入链接的访问次数
+//	@ID				share-GetShareLinkNodeDetail
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-KB-ID			header		string						true	"kb id"
+//	@Param			X-Share-Access	header		string						false	"access"
+//	@Param			param			query		v1.ShareLinkNodeDetailReq	true	"para"
+//	@Success		200				{object}	domain.Response
+//	@Router			/share/v1/share_link/node/detail [get]
+func (h *ShareLinkHandler) GetShareLinkNodeDetail(c echo.Context) error {
+	var req v1.ShareLinkNodeDetailReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	node, err := h.usecase.GetNodeDetail(c.Request().Context(), c.Request().Header.Get("X-KB-ID"), &req, c.Request().Header.Get(shareAccessHeader))
+	if err != nil {
+		return h.shareLinkError(c, "get share link node failed", err)
+	}
+	return h.NewResponseWithData(c, node)
+}
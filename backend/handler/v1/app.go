@@ -35,7 +35,7 @@ func NewAppHandler(e *echo.Echo, baseHandler *handler.BaseHandler, logger *log.L
 		config:              config,
 	}
 
-	group := e.Group("/api/v1/app", h.auth.Authorize, h.auth.ValidateKBUserPerm(consts.UserKBPermissionFullControl))
+	group := e.Group("/api/v1/app", h.auth.Authorize, h.auth.ValidateKBCapability(consts.KBCapabilityAppConfig))
 	group.GET("/detail", h.GetAppDetail)
 	group.PUT("", h.UpdateApp)
 	group.DELETE("", h.DeleteApp)
@@ -105,9 +105,22 @@ func (h *AppHandler) UpdateApp(c echo.Context) error {
 		return h.NewResponseWithErrCode(c, domain.ErrCodePermissionDenied)
 	}
 
+	before, err := h.usecase.GetApp(ctx, id)
+	if err != nil {
+		return h.NewResponseWithError(c, "get app failed", err)
+	}
 	if err := h.usecase.UpdateApp(ctx, id, &appRequest); err != nil {
 		return h.NewResponseWithError(c, "update app failed", err)
 	}
+	if after, err := h.usecase.GetApp(ctx, id); err == nil {
+		middleware.SetAuditChange(c, &domain.AuditTarget{
+			KBID:       before.KBID,
+			TargetType: "app",
+			TargetID:   id,
+			Before:     before,
+			After:      after,
+		})
+	}
 
 	return h.NewResponseWithData(c, nil)
 }
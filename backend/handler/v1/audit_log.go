@@ -0,0 +1,188 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	v1 "github.com/chaitin/panda-wiki/api/audit/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/handler"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/middleware"
+	"github.com/chaitin/panda-wiki/usecase"
+)
+
+type AuditLogHandler struct {
+	*handler.BaseHandler
+	logger  *log.Logger
+	usecase *usecase.AuditUsecase
+	auth    middleware.AuthMiddleware
+}
+
+func NewAuditLogHandler(
+	baseHandler *handler.BaseHandler,
+	echo *echo.Echo,
+	usecase *usecase.AuditUsecase,
+	auth middleware.AuthMiddleware,
+	logger *log.Logger,
+) *AuditLogHandler {
+	h := &AuditLogHandler{
+		BaseHandler: baseHandler,
+		logger:      logger.WithModule("handler.v1.audit_log"),
+		usecase:     usecase,
+		auth:        auth,
+	}
+
+	group := echo.Group("/api/v1/audit_log", h.auth.Authorize, h.auth.ValidateUserRole(consts.UserRoleAdmin))
+	group.GET("/list", h.ListAuditLogs)
+	group.GET("/export", h.ExportAuditLogs)
+	group.GET("/verify", h.VerifyAuditLogs)
+	group.GET("/setting", h.GetAuditLogSetting)
+	group.PUT("/setting", h.UpdateAuditLogSetting)
+
+	return h
+}
+
+// ListAuditLogs 审计日志列表
+//
+//	@Tags			AuditLog
+//	@Summary		审计日志列表
+//	@Description	按知识库、操作人、操作、目标及时间筛选，按时间倒序
+//	@ID				v1-ListAuditLogs
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.AuditLogListReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.AuditLogListResp}
+//	@Router			/api/v1/audit_log/list [get]
+func (h *AuditLogHandler) ListAuditLogs(c echo.Context) error {
+	var req v1.AuditLogListReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	resp, err := h.usecase.ListAuditLogs(c.Request().Context(), &req)
+	if err != nil {
+		return h.NewResponseWithError(c, "list audit logs failed", err)
+	}
+	return h.NewResponseWithData(c, resp)
+}
+
+// ExportAuditLogs 导出审计日志
+//
+//	@Tags			AuditLog
+//	@Summary		导出审计日志
+//	@Description	按筛选条件导出为 CSV 或 JSONL 文件，按时间正序
+//	@ID				v1-ExportAuditLogs
+//	@Produce		octet-stream
+//	@Security		bearerAuth
+//	@Param			param	query	v1.AuditLogExportReq	true	"para"
+//	@Success		200		{file}	file
+//	@Router			/api/v1/audit_log/export [get]
+func (h *AuditLogHandler) ExportAuditLogs(c echo.Context) error {
+	var req v1.AuditLogExportReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	contentType, ext := "text/csv; charset=utf-8", "csv"
+	if req.Format == "jsonl" {
+		contentType, ext = "application/x-ndjson", "jsonl"
+	}
+	filename := fmt.Sprintf("audit_logs_%s.%s", time.Now().Format("20060102150405"), ext)
+	c.Response().Header().Set(echo.HeaderContentType, contentType)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	c.Response().WriteHeader(http.StatusOK)
+
+	// 响应头已发送，出错时只能中断输出
+	if err := h.usecase.ExportAuditLogs(c.Request().Context(), &req, c.Response()); err != nil {
+		h.logger.Error("export audit logs failed", log.Error(err))
+		return err
+	}
+	return nil
+}
+
+// VerifyAuditLogs 校验审计日志哈希链
+//
+//	@Tags			AuditLog
+//	@Summary		校验审计日志
+//	@Description	从最早的记录开始校验哈希链，返回第一条被篡改的记录
+//	@ID				v1-VerifyAuditLogs
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Success		200	{object}	domain.PWResponse{data=v1.AuditLogVerifyResp}
+//	@Router			/api/v1/audit_log/verify [get]
+func (h *AuditLogHandler) VerifyAuditLogs(c echo.Context) error {
+	resp, err := h.usecase.VerifyAuditLogs(c.Request().Context())
+	if err != nil {
+		return h.NewResponseWithError(c, "verify audit logs failed", err)
+	}
+	return h.NewResponseWithData(c, resp)
+}
+
+// GetAuditLogSetting 获取审计日志设置
+//
+//	@Tags			AuditLog
+//	@Summary		获取审计日志设置
+//	@Description	获取审计日志保留天数
+//	@ID				v1-GetAuditLogSetting
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Success		200	{object}	domain.PWResponse{data=domain.AuditLogSetting}
+//	@Router			/api/v1/audit_log/setting [get]
+func (h *AuditLogHandler) GetAuditLogSetting(c echo.Context) error {
+	setting, err := h.usecase.GetSetting(c.Request().Context())
+	if err != nil {
+		return h.NewResponseWithError(c, "get audit log setting failed", err)
+	}
+	return h.NewResponseWithData(c, setting)
+}
+
+// UpdateAuditLogSetting 更新审计日志设置
+//
+//	@Tags			AuditLog
+//	@Summary		更新审计日志设置
+//	@Description	设置审计日志保留天数，0 表示永久保留
+//	@ID				v1-UpdateAuditLogSetting
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.AuditLogSettingReq	true	"para"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/audit_log/setting [put]
+func (h *AuditLogHandler) UpdateAuditLogSetting(c echo.Context) error {
+	var req v1.AuditLogSettingReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	ctx := c.Request().Context()
+	before, err := h.usecase.GetSetting(ctx)
+	if err != nil {
+		return h.NewResponseWithError(c, "get audit log setting failed", err)
+	}
+	if err := h.usecase.UpdateSetting(ctx, &req); err != nil {
+		return h.NewResponseWithError(c, "update audit log setting failed", err)
+	}
+	middleware.SetAuditChange(c, &domain.AuditTarget{
+		TargetType: "audit_log",
+		Before:     before,
+		After:      domain.AuditLogSetting{RetentionDays: req.RetentionDays},
+	})
+	return h.NewResponseWithData(c, nil)
+}
@@ -5,8 +5,10 @@ import (
 
 	v1 "github.com/chaitin/panda-wiki/api/auth/v1"
 	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
 	"github.com/chaitin/panda-wiki/handler"
 	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/middleware"
 	"github.com/chaitin/panda-wiki/usecase"
 )
 
@@ -14,6 +16,8 @@ type AuthV1Handler struct {
 	*handler.BaseHandler
 	logger      *log.Logger
 	authUseCase *usecase.AuthUsecase
+	syncUseCase *usecase.AuthGroupSyncUsecase
+	scimUseCase *usecase.SCIMUsecase
 }
 
 func NewAuthV1Handler(
@@ -21,21 +25,30 @@ func NewAuthV1Handler(
 	baseHandler *handler.BaseHandler,
 	logger *log.Logger,
 	authUseCase *usecase.AuthUsecase,
+	syncUseCase *usecase.AuthGroupSyncUsecase,
+	scimUseCase *usecase.SCIMUsecase,
 ) *AuthV1Handler {
 	h := &AuthV1Handler{
 		BaseHandler: baseHandler,
 		logger:      logger,
 		authUseCase: authUseCase,
+		syncUseCase: syncUseCase,
+		scimUseCase: scimUseCase,
 	}
 
 	AuthGroup := e.Group(
 		"/api/v1/auth",
 		h.V1Auth.Authorize,
-		h.V1Auth.ValidateKBUserPerm(consts.UserKBPermissionFullControl),
+		h.V1Auth.ValidateKBCapability(consts.KBCapabilityUserManage),
 	)
 	AuthGroup.GET("/get", h.OpenAuthGet)
 	AuthGroup.POST("/set", h.OpenAuthSet)
 	AuthGroup.DELETE("/delete", h.OpenAuthDelete)
+	AuthGroup.POST("/group/sync", h.AuthGroupSync)
+	AuthGroup.GET("/group/sync/runs", h.AuthGroupSyncRuns)
+	AuthGroup.GET("/scim", h.AuthSCIMGet)
+	AuthGroup.POST("/scim", h.AuthSCIMSet)
+	AuthGroup.DELETE("/scim", h.AuthSCIMDelete)
 
 	return h
 }
@@ -94,9 +107,23 @@ func (h *AuthV1Handler) OpenAuthSet(c echo.Context) error {
 		return h.NewResponseWithError(c, "validate request params failed", err)
 	}
 
-	if err := h.authUseCase.SetAuth(c.Request().Context(), req); err != nil {
+	ctx := c.Request().Context()
+	before, err := h.authUseCase.GetAuthConfig(ctx, req.KBID, req.SourceType)
+	if err != nil {
+		return h.NewResponseWithError(c, "failed to get Auth", err)
+	}
+	if err := h.authUseCase.SetAuth(ctx, req); err != nil {
 		return h.NewResponseWithError(c, "failed to set Auth", err)
 	}
+	if after, err := h.authUseCase.GetAuthConfig(ctx, req.KBID, req.SourceType); err == nil {
+		middleware.SetAuditChange(c, &domain.AuditTarget{
+			KBID:       req.KBID,
+			TargetType: "auth_config",
+			TargetID:   string(req.SourceType),
+			Before:     before,
+			After:      after,
+		})
+	}
 
 	return h.NewResponseWithData(c, nil)
 }
@@ -130,3 +157,157 @@ func (h *AuthV1Handler) OpenAuthDelete(c echo.Context) error {
 
 	return h.NewResponseWithData(c, nil)
 }
+
+// AuthGroupSync 同步用户组
+//
+//	@Tags			Auth
+//	@Summary		同步用户组
+//	@Description	从 LDAP、钉钉、飞书、企业微信同步部门/用户组及成员到读者用户组，试运行仅返回差异
+//	@ID				v1-AuthGroupSync
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.AuthGroupSyncReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=domain.AuthGroupSyncRun}
+//	@Router			/api/v1/auth/group/sync [post]
+func (h *AuthV1Handler) AuthGroupSync(c echo.Context) error {
+
+	var req v1.AuthGroupSyncReq
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	run, err := h.syncUseCase.Sync(c.Request().Context(), req.KBID, req.SourceType, req.DryRun, consts.AuthGroupSyncTriggerManual)
+	if err != nil {
+		return h.NewResponseWithError(c, "failed to sync auth groups", err)
+	}
+
+	return h.NewResponseWithData(c, run)
+}
+
+// AuthGroupSyncRuns 用户组同步记录
+//
+//	@Tags			Auth
+//	@Summary		用户组同步记录
+//	@Description	用户组同步的运行报告，包含每次运行的差异
+//	@ID				v1-AuthGroupSyncRuns
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.AuthGroupSyncRunListReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.AuthGroupSyncRunListResp}
+//	@Router			/api/v1/auth/group/sync/runs [get]
+func (h *AuthV1Handler) AuthGroupSyncRuns(c echo.Context) error {
+
+	var req v1.AuthGroupSyncRunListReq
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	resp, err := h.syncUseCase.ListRuns(c.Request().Context(), &req)
+	if err != nil {
+		return h.NewResponseWithError(c, "failed to list auth group sync runs", err)
+	}
+
+	return h.NewResponseWithData(c, resp)
+}
+
+// AuthSCIMGet 获取 SCIM 配置
+//
+//	@Tags			Auth
+//	@Summary		获取 SCIM 配置
+//	@Description	获取 SCIM 配置，不返回 token
+//	@ID				v1-AuthSCIMGet
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.AuthSCIMGetReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.AuthSCIMGetResp}
+//	@Router			/api/v1/auth/scim [get]
+func (h *AuthV1Handler) AuthSCIMGet(c echo.Context) error {
+
+	var req v1.AuthSCIMGetReq
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	resp, err := h.scimUseCase.GetConfig(c.Request().Context(), req.KBID)
+	if err != nil {
+		return h.NewResponseWithError(c, "failed to get scim config", err)
+	}
+
+	return h.NewResponseWithData(c, resp)
+}
+
+// AuthSCIMSet 设置 SCIM 配置
+//
+//	@Tags			Auth
+//	@Summary		设置 SCIM 配置
+//	@Description	开启 SCIM 开通，首次开启或重置时返回 token，token 仅返回一次
+//	@ID				v1-AuthSCIMSet
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.AuthSCIMSetReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.AuthSCIMSetResp}
+//	@Router			/api/v1/auth/scim [post]
+func (h *AuthV1Handler) AuthSCIMSet(c echo.Context) error {
+
+	var req v1.AuthSCIMSetReq
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	resp, err := h.scimUseCase.SetConfig(c.Request().Context(), req)
+	if err != nil {
+		return h.NewResponseWithError(c, "failed to set scim config", err)
+	}
+
+	return h.NewResponseWithData(c, resp)
+}
+
+// AuthSCIMDelete 关闭 SCIM
+//
+//	@Tags			Auth
+//	@Summary		关闭 SCIM
+//	@Description	关闭 SCIM 并使 token 失效，已开通的读者及用户组保留
+//	@ID				v1-AuthSCIMDelete
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.AuthSCIMDeleteReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse
+//	@Router			/api/v1/auth/scim [delete]
+func (h *AuthV1Handler) AuthSCIMDelete(c echo.Context) error {
+
+	var req v1.AuthSCIMDeleteReq
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	if err := h.scimUseCase.DeleteConfig(c.Request().Context(), req.KBID); err != nil {
+		return h.NewResponseWithError(c, "failed to delete scim config", err)
+	}
+
+	return h.NewResponseWithData(c, nil)
+}
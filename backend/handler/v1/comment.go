@@ -27,7 +27,7 @@ func NewCommentHandler(e *echo.Echo, baseHandler *handler.BaseHandler, logger *l
 		usecase:     usecase,
 	}
 
-	group := e.Group("/api/v1/comment", h.auth.Authorize, h.auth.ValidateKBUserPerm(consts.UserKBPermissionDataOperate))
+	group := e.Group("/api/v1/comment", h.auth.Authorize, h.auth.ValidateKBCapability(consts.KBCapabilityCommentManage))
 	group.GET("", h.GetCommentModeratedList)
 	group.DELETE("/list", h.DeleteCommentList)
 
@@ -61,6 +61,11 @@ func (h *CommentHandler) GetCommentModeratedList(c echo.Context) error {
 	if err != nil {
 		return h.NewResponseWithError(c, "failed to get comment list KBID", err)
 	}
+	if hideRemoteIP(c) {
+		for _, item := range commentList.Data {
+			item.Info.RemoteIP, item.IPAddress = "", nil
+		}
+	}
 	return h.NewResponseWithData(c, commentList)
 }
 
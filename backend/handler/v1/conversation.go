@@ -26,7 +26,7 @@ func NewConversationHandler(echo *echo.Echo, baseHandler *handler.BaseHandler, l
 		auth:        auth,
 		usecase:     usecase,
 	}
-	group := echo.Group("/api/v1/conversation", handler.auth.Authorize, handler.auth.ValidateKBUserPerm(consts.UserKBPermissionDataOperate))
+	group := echo.Group("/api/v1/conversation", handler.auth.Authorize, handler.auth.ValidateKBCapability(consts.KBCapabilityConversationView))
 	group.GET("", handler.GetConversationList)
 	group.GET("/detail", handler.GetConversationDetail)
 	group.GET("/message/list", handler.GetMessageFeedBackList)
@@ -54,11 +54,20 @@ func (h *ConversationHandler) GetConversationList(c echo.Context) error {
 	}
 
 	ctx := c.Request().Context()
+	hideIP := hideRemoteIP(c)
+	if hideIP {
+		request.RemoteIP = nil
+	}
 
 	conversationList, err := h.usecase.GetConversationList(ctx, &request)
 	if err != nil {
 		return h.NewResponseWithError(c, "failed to get conversation list", err)
 	}
+	if hideIP {
+		for _, item := range conversationList.Data {
+			item.RemoteIP, item.IPAddress = "", nil
+		}
+	}
 
 	return h.NewResponseWithData(c, conversationList)
 }
@@ -87,6 +96,9 @@ func (h *ConversationHandler) GetConversationDetail(c echo.Context) error {
 	if err != nil {
 		return h.NewResponseWithError(c, "failed to get conversation detail", err)
 	}
+	if hideRemoteIP(c) {
+		conversation.RemoteIP, conversation.IPAddress = "", nil
+	}
 
 	return h.NewResponseWithData(c, conversation)
 }
@@ -113,6 +125,11 @@ func (h *ConversationHandler) GetMessageFeedBackList(c echo.Context) error {
 	if err != nil {
 		return h.NewResponseWithError(c, "failed to get message list", err)
 	}
+	if hideRemoteIP(c) {
+		for _, item := range messages.Data {
+			item.RemoteIP, item.IPAddress = "", nil
+		}
+	}
 	return h.NewResponseWithData(c, messages)
 }
 
@@ -139,6 +156,15 @@ func (h *ConversationHandler) GetMessageDetail(c echo.Context) error {
 	if err != nil {
 		return h.NewResponseWithError(c, "failed to get message detail", err)
 	}
+	if hideRemoteIP(c) {
+		message.RemoteIP = ""
+	}
 
 	return h.NewResponseWithData(c, message)
 }
+
+// hideRemoteIP 没有查看来源 IP 的能力时隐藏 IP 及归属地
+func hideRemoteIP(c echo.Context) bool {
+	authInfo := domain.GetAuthInfoFromCtx(c.Request().Context())
+	return authInfo == nil || !authInfo.HasCapability(consts.KBCapabilityConversationIP)
+}
@@ -0,0 +1,178 @@
+package v1
+
+import (
+	"errors"
+
+	"github.com/labstack/echo/v4"
+
+	v1 "github.com/chaitin/panda-wiki/api/conversation/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/handler"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/middleware"
+	"github.com/chaitin/panda-wiki/usecase"
+)
+
+type ConversationEscalationHandler struct {
+	*handler.BaseHandler
+	logger     *log.Logger
+	usecase    *usecase.EscalationUsecase
+	appUsecase *usecase.AppUsecase
+	auth       middleware.AuthMiddleware
+}
+
+func NewConversationEscalationHandler(
+	baseHandler *handler.BaseHandler,
+	echo *echo.Echo,
+	usecase *usecase.EscalationUsecase,
+	appUsecase *usecase.AppUsecase,
+	auth middleware.AuthMiddleware,
+	logger *log.Logger,
+) *ConversationEscalationHandler {
+	h := &ConversationEscalationHandler{
+		BaseHandler: baseHandler,
+		logger:      logger.WithModule("handler.v1.conversation_escalation"),
+		usecase:     usecase,
+		appUsecase:  appUsecase,
+		auth:        auth,
+	}
+
+	settings := echo.Group("/api/v1/conversation/escalation/settings", h.auth.Authorize, h.auth.ValidateKBCapability(consts.KBCapabilityAppConfig))
+	settings.GET("", h.GetEscalationSettings)
+	settings.PUT("", h.UpdateEscalationSettings)
+
+	// replies reach the users on their channel, viewing conversations is not enough
+	group := echo.Group("/api/v1/conversation/escalation", h.auth.Authorize, h.auth.ValidateKBCapability(consts.KBCapabilityConversationReply))
+	group.POST("/reply", h.ReplyEscalation)
+	group.POST("/resolve", h.ResolveEscalation)
+
+	return h
+}
+
+// GetEscalationSettings 获取转人工设置
+//
+//	@Tags			ConversationEscalation
+//	@Summary		获取转人工设置
+//	@Description	获取转人工触发条件及值班通知设置
+//	@ID				v1-GetEscalationSettings
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.GetEscalationSettingsReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.GetEscalationSettingsResp}
+//	@Router			/api/v1/conversation/escalation/settings [get]
+func (h *ConversationEscalationHandler) GetEscalationSettings(c echo.Context) error {
+	var req v1.GetEscalationSettingsReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	settings, err := h.usecase.GetSettings(c.Request().Context(), req.KbId)
+	if err != nil {
+		return h.NewResponseWithError(c, "get escalation settings failed", err)
+	}
+	return h.NewResponseWithData(c, settings)
+}
+
+// UpdateEscalationSettings 更新转人工设置
+//
+//	@Tags			ConversationEscalation
+//	@Summary		更新转人工设置
+//	@Description	用户要求转人工, 未检索到相关文档, 或多次点踩时转人工, 并通知值班的群机器人, webhook 或邮箱
+//	@ID				v1-UpdateEscalationSettings
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.UpdateEscalationSettingsReq	true	"para"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/conversation/escalation/settings [put]
+func (h *ConversationEscalationHandler) UpdateEscalationSettings(c echo.Context) error {
+	var req v1.UpdateEscalationSettingsReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+
+	if err := h.usecase.UpdateSettings(c.Request().Context(), &req); err != nil {
+		return h.NewResponseWithError(c, "update escalation settings failed", err)
+	}
+	return h.NewResponseWithData(c, nil)
+}
+
+// ReplyEscalation 人工回复会话
+//
+//	@Tags			ConversationEscalation
+//	@Summary		人工回复会话
+//	@Description	回复保存到会话中, 并发送到用户所在的渠道。钉钉、企业微信、微信客服、公众号和 OpenAI API 的会话不支持人工回复
+//	@ID				v1-ReplyEscalation
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.EscalationReplyReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.EscalationReplyResp}
+//	@Router			/api/v1/conversation/escalation/reply [post]
+func (h *ConversationEscalationHandler) ReplyEscalation(c echo.Context) error {
+	ctx := c.Request().Context()
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return h.NewResponseWithError(c, "authInfo not found in context", nil)
+	}
+
+	var req v1.EscalationReplyReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+
+	conversation, messageID, err := h.usecase.Reply(ctx, &req, authInfo.UserId)
+	if err != nil {
+		if errors.Is(err, domain.ErrEscalationUnsupported) {
+			return h.NewResponseWithError(c, "该会话所在渠道不支持人工回复", err)
+		}
+		return h.NewResponseWithError(c, "reply conversation failed", err)
+	}
+	delivered, err := h.appUsecase.PushHumanReply(ctx, conversation, req.Content)
+	if err != nil {
+		h.logger.Error("push human reply failed", log.String("conversation_id", conversation.ID), log.Error(err))
+		return h.NewResponseWithError(c, "reply is saved but sending it to the channel failed", err)
+	}
+	return h.NewResponseWithData(c, v1.EscalationReplyResp{
+		MessageID: messageID,
+		Delivered: delivered,
+	})
+}
+
+// ResolveEscalation 结束人工处理
+//
+//	@Tags			ConversationEscalation
+//	@Summary		结束人工处理
+//	@Description	结束后该会话的新消息重新由 AI 回答
+//	@ID				v1-ResolveEscalation
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.ResolveEscalationReq	true	"para"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/conversation/escalation/resolve [post]
+func (h *ConversationEscalationHandler) ResolveEscalation(c echo.Context) error {
+	var req v1.ResolveEscalationReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+
+	if err := h.usecase.Resolve(c.Request().Context(), &req); err != nil {
+		return h.NewResponseWithError(c, "resolve escalation failed", err)
+	}
+	return h.NewResponseWithData(c, nil)
+}
@@ -0,0 +1,182 @@
+package v1
+
+import (
+	"errors"
+
+	"github.com/labstack/echo/v4"
+
+	v1 "github.com/chaitin/panda-wiki/api/kb/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/handler"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/middleware"
+	"github.com/chaitin/panda-wiki/usecase"
+)
+
+type KBRoleHandler struct {
+	*handler.BaseHandler
+	logger  *log.Logger
+	usecase *usecase.KBRoleUsecase
+	auth    middleware.AuthMiddleware
+}
+
+func NewKBRoleHandler(
+	baseHandler *handler.BaseHandler,
+	echo *echo.Echo,
+	usecase *usecase.KBRoleUsecase,
+	auth middleware.AuthMiddleware,
+	logger *log.Logger,
+) *KBRoleHandler {
+	h := &KBRoleHandler{
+		BaseHandler: baseHandler,
+		logger:      logger.WithModule("handler.v1.kb_role"),
+		usecase:     usecase,
+		auth:        auth,
+	}
+
+	group := echo.Group("/api/v1/kb_role", h.auth.Authorize, h.auth.ValidateUserRole(consts.UserRoleAdmin))
+	group.GET("/list", h.ListKBRoles)
+	group.POST("", h.CreateKBRole)
+	group.PUT("", h.UpdateKBRole)
+	group.DELETE("", h.DeleteKBRole)
+
+	return h
+}
+
+// ListKBRoles 自定义角色列表
+//
+//	@Tags			KBRole
+//	@Summary		自定义角色列表
+//	@Description	返回全部自定义角色，以及可选的能力和内置权限包含的能力
+//	@ID				v1-ListKBRoles
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Success		200	{object}	domain.PWResponse{data=v1.KBRoleListResp}
+//	@Router			/api/v1/kb_role/list [get]
+func (h *KBRoleHandler) ListKBRoles(c echo.Context) error {
+	resp, err := h.usecase.ListRoles(c.Request().Context())
+	if err != nil {
+		return h.NewResponseWithError(c, "list kb roles failed", err)
+	}
+	return h.NewResponseWithData(c, resp)
+}
+
+// CreateKBRole 创建自定义角色
+//
+//	@Tags			KBRole
+//	@Summary		创建自定义角色
+//	@Description	自定义角色在所有知识库中可用，通过邀请或修改成员分配
+//	@ID				v1-CreateKBRole
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.KBRoleCreateReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=domain.KBRole}
+//	@Router			/api/v1/kb_role [post]
+func (h *KBRoleHandler) CreateKBRole(c echo.Context) error {
+	var req v1.KBRoleCreateReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+	if consts.GetLicenseEdition(c) != consts.LicenseEditionEnterprise {
+		return h.NewResponseWithError(c, "非企业版本不支持自定义角色", nil)
+	}
+
+	role, err := h.usecase.CreateRole(c.Request().Context(), &req)
+	if err != nil {
+		return h.NewResponseWithError(c, "create kb role failed", err)
+	}
+	middleware.SetAuditChange(c, &domain.AuditTarget{
+		TargetType: "kb_role",
+		TargetID:   role.ID,
+		After:      role,
+	})
+	return h.NewResponseWithData(c, role)
+}
+
+// UpdateKBRole 修改自定义角色
+//
+//	@Tags			KBRole
+//	@Summary		修改自定义角色
+//	@Description	修改后立即对分配了该角色的成员生效
+//	@ID				v1-UpdateKBRole
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.KBRoleUpdateReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=domain.KBRole}
+//	@Router			/api/v1/kb_role [put]
+func (h *KBRoleHandler) UpdateKBRole(c echo.Context) error {
+	var req v1.KBRoleUpdateReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+	if consts.GetLicenseEdition(c) != consts.LicenseEditionEnterprise {
+		return h.NewResponseWithError(c, "非企业版本不支持自定义角色", nil)
+	}
+
+	ctx := c.Request().Context()
+	before, err := h.usecase.GetRole(ctx, req.ID)
+	if err != nil {
+		return h.NewResponseWithError(c, "get kb role failed", err)
+	}
+	role, err := h.usecase.UpdateRole(ctx, &req)
+	if err != nil {
+		return h.NewResponseWithError(c, "update kb role failed", err)
+	}
+	middleware.SetAuditChange(c, &domain.AuditTarget{
+		TargetType: "kb_role",
+		TargetID:   role.ID,
+		Before:     before,
+		After:      role,
+	})
+	return h.NewResponseWithData(c, role)
+}
+
+// DeleteKBRole 删除自定义角色
+//
+//	@Tags			KBRole
+//	@Summary		删除自定义角色
+//	@Description	仍分配给知识库成员的角色不能删除
+//	@ID				v1-DeleteKBRole
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.KBRoleDeleteReq	true	"para"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/kb_role [delete]
+func (h *KBRoleHandler) DeleteKBRole(c echo.Context) error {
+	var req v1.KBRoleDeleteReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	ctx := c.Request().Context()
+	before, err := h.usecase.GetRole(ctx, req.ID)
+	if err != nil {
+		return h.NewResponseWithError(c, "get kb role failed", err)
+	}
+	if err := h.usecase.DeleteRole(ctx, req.ID); err != nil {
+		if errors.Is(err, domain.ErrKBRoleInUse) {
+			return h.NewResponseWithError(c, "角色仍分配给知识库成员，请先调整成员权限", err)
+		}
+		return h.NewResponseWithError(c, "delete kb role failed", err)
+	}
+	middleware.SetAuditChange(c, &domain.AuditTarget{
+		TargetType: "kb_role",
+		TargetID:   req.ID,
+		Before:     before,
+	})
+	return h.NewResponseWithData(c, nil)
+}
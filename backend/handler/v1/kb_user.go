@@ -2,9 +2,12 @@ package v1
 
 import (
 	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
 
 	v1 "github.com/chaitin/panda-wiki/api/kb/v1"
 	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/middleware"
 )
 
 // KBUserList
@@ -55,7 +58,7 @@ func (h *KnowledgeBaseHandler) KBUserInvite(c echo.Context) error {
 		return h.NewResponseWithError(c, "validate request failed", err)
 	}
 
-	if consts.GetLicenseEdition(c) != consts.LicenseEditionEnterprise && req.Perm != consts.UserKBPermissionFullControl {
+	if consts.GetLicenseEdition(c) != consts.LicenseEditionEnterprise && (req.RoleID != "" || req.Perm != consts.UserKBPermissionFullControl) {
 		return h.NewResponseWithError(c, "非企业版本只能使用完全控制权限", nil)
 	}
 
@@ -87,14 +90,26 @@ func (h *KnowledgeBaseHandler) KBUserUpdate(c echo.Context) error {
 		return h.NewResponseWithError(c, "validate request failed", err)
 	}
 
-	if consts.GetLicenseEdition(c) != consts.LicenseEditionEnterprise && req.Perm != consts.UserKBPermissionFullControl {
+	if consts.GetLicenseEdition(c) != consts.LicenseEditionEnterprise && (req.RoleID != "" || req.Perm != consts.UserKBPermissionFullControl) {
 		return h.NewResponseWithError(c, "非企业版本只能使用完全控制权限", nil)
 	}
 
-	err := h.usecase.UpdateUserKB(c.Request().Context(), req)
+	ctx := c.Request().Context()
+	before, err := h.usecase.GetKBUser(ctx, req.KBId, req.UserId)
+	if err != nil {
+		return h.NewResponseWithError(c, "get user kb permission failed", err)
+	}
+	err = h.usecase.UpdateUserKB(ctx, req)
 	if err != nil {
 		return h.NewResponseWithError(c, "update user kb permission failed", err)
 	}
+	middleware.SetAuditChange(c, &domain.AuditTarget{
+		KBID:       req.KBId,
+		TargetType: "kb_user",
+		TargetID:   req.UserId,
+		Before:     map[string]any{"perm": before.Perm, "role_id": before.RoleID},
+		After:      map[string]any{"perm": lo.Ternary(req.RoleID == "", req.Perm, consts.UserKBPermissionNull), "role_id": req.RoleID},
+	})
 
 	return h.NewResponseWithData(c, nil)
 }
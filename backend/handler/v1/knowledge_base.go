@@ -2,6 +2,7 @@ package v1
 
 import (
 	"errors"
+	"slices"
 
 	"github.com/labstack/echo/v4"
 	"github.com/samber/lo"
@@ -42,18 +43,18 @@ func NewKnowledgeBaseHandler(
 	group.POST("", h.CreateKnowledgeBase, h.auth.ValidateUserRole(consts.UserRoleAdmin))
 	group.GET("/list", h.GetKnowledgeBaseList)
 	group.GET("/detail", h.GetKnowledgeBaseDetail)
-	group.PUT("/detail", h.UpdateKnowledgeBase, h.auth.ValidateKBUserPerm(consts.UserKBPermissionFullControl))
+	group.PUT("/detail", h.UpdateKnowledgeBase, h.auth.ValidateKBCapability(consts.KBCapabilityAppConfig))
 	group.DELETE("/detail", h.DeleteKnowledgeBase, h.auth.ValidateUserRole(consts.UserRoleAdmin))
 
 	// user management
-	userGroup := group.Group("/user", h.auth.ValidateKBUserPerm(consts.UserKBPermissionFullControl))
+	userGroup := group.Group("/user", h.auth.ValidateKBCapability(consts.KBCapabilityUserManage))
 	userGroup.GET("/list", h.KBUserList)
 	userGroup.POST("/invite", h.KBUserInvite)
 	userGroup.PATCH("/update", h.KBUserUpdate)
 	userGroup.DELETE("/delete", h.KBUserDelete)
 
 	// release
-	releaseGroup := group.Group("/release", h.auth.ValidateKBUserPerm(consts.UserKBPermissionDocManage))
+	releaseGroup := group.Group("/release", h.auth.ValidateKBCapability(consts.KBCapabilityNodePublish))
 	releaseGroup.POST("", h.CreateKBRelease)
 	releaseGroup.GET("/list", h.GetKBReleaseList)
 
@@ -152,7 +153,12 @@ func (h *KnowledgeBaseHandler) UpdateKnowledgeBase(c echo.Context) error {
 		return h.NewResponseWithError(c, "invalid request", err)
 	}
 
-	err := h.usecase.UpdateKnowledgeBase(c.Request().Context(), &req)
+	ctx := c.Request().Context()
+	before, err := h.usecase.GetKnowledgeBase(ctx, req.ID)
+	if err != nil {
+		return h.NewResponseWithError(c, "failed to get knowledge base detail", err)
+	}
+	err = h.usecase.UpdateKnowledgeBase(ctx, &req)
 	if err != nil {
 		if errors.Is(err, domain.ErrPortHostAlreadyExists) {
 			return h.NewResponseWithError(c, "端口或域名已被其他知识库占用", nil)
@@ -162,6 +168,15 @@ func (h *KnowledgeBaseHandler) UpdateKnowledgeBase(c echo.Context) error {
 		}
 		return h.NewResponseWithError(c, "failed to update knowledge base", err)
 	}
+	if after, err := h.usecase.GetKnowledgeBase(ctx, req.ID); err == nil {
+		middleware.SetAuditChange(c, &domain.AuditTarget{
+			KBID:       req.ID,
+			TargetType: "knowledge_base",
+			TargetID:   req.ID,
+			Before:     before,
+			After:      after,
+		})
+	}
 
 	return h.NewResponseWithData(c, nil)
 }
@@ -192,8 +207,12 @@ func (h *KnowledgeBaseHandler) GetKnowledgeBaseDetail(c echo.Context) error {
 	if err != nil {
 		return h.NewResponseWithError(c, "failed to get knowledge base permission", err)
 	}
+	capabilities, err := h.usecase.GetKnowledgeBaseCapabilities(c.Request().Context(), kbID)
+	if err != nil {
+		return h.NewResponseWithError(c, "failed to get knowledge base permission", err)
+	}
 
-	if perm != consts.UserKBPermissionFullControl {
+	if !slices.Contains(capabilities, consts.KBCapabilityAppConfig) {
 		kb.AccessSettings.PrivateKey = ""
 		kb.AccessSettings.PublicKey = ""
 	}
@@ -203,6 +222,7 @@ func (h *KnowledgeBaseHandler) GetKnowledgeBaseDetail(c echo.Context) error {
 		Name:           kb.Name,
 		DatasetID:      kb.DatasetID,
 		Perm:           perm,
+		Capabilities:   capabilities,
 		AccessSettings: kb.AccessSettings,
 		CreatedAt:      kb.CreatedAt,
 		UpdatedAt:      kb.UpdatedAt,
@@ -254,6 +274,9 @@ func (h *KnowledgeBaseHandler) CreateKBRelease(c echo.Context) error {
 
 	id, err := h.usecase.CreateKBRelease(c.Request().Context(), req)
 	if err != nil {
+		if errors.Is(err, domain.ErrNodeReviewRequired) {
+			return h.NewResponseWithError(c, "知识库已开启发布审核，只能发布审核通过的文档", err)
+		}
 		return h.NewResponseWithError(c, "create kb release failed", err)
 	}
 
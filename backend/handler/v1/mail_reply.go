@@ -0,0 +1,170 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+
+	v1 "github.com/chaitin/panda-wiki/api/app/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/handler"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/middleware"
+	"github.com/chaitin/panda-wiki/usecase"
+)
+
+type MailReplyHandler struct {
+	*handler.BaseHandler
+	logger  *log.Logger
+	usecase *usecase.MailReplyUsecase
+	auth    middleware.AuthMiddleware
+}
+
+func NewMailReplyHandler(
+	baseHandler *handler.BaseHandler,
+	echo *echo.Echo,
+	usecase *usecase.MailReplyUsecase,
+	auth middleware.AuthMiddleware,
+	logger *log.Logger,
+) *MailReplyHandler {
+	h := &MailReplyHandler{
+		BaseHandler: baseHandler,
+		logger:      logger.WithModule("handler.v1.mail_reply"),
+		usecase:     usecase,
+		auth:        auth,
+	}
+
+	group := echo.Group("/api/v1/app/mail/reply", h.auth.Authorize, h.auth.ValidateKBCapability(consts.KBCapabilityAppConfig))
+	group.GET("", h.GetMailReplyList)
+	group.PUT("", h.UpdateMailReply)
+	group.POST("/send", h.SendMailReply)
+	group.POST("/discard", h.DiscardMailReply)
+
+	return h
+}
+
+// GetMailReplyList 获取邮件回复列表
+//
+//	@Tags			MailReply
+//	@Summary		获取邮件回复列表
+//	@Description	邮件问答生成的回复, 草稿模式下待审核的回复状态为 pending
+//	@ID				v1-GetMailReplyList
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.MailReplyListReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.MailReplyListResp}
+//	@Router			/api/v1/app/mail/reply [get]
+func (h *MailReplyHandler) GetMailReplyList(c echo.Context) error {
+	var req v1.MailReplyListReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	resp, err := h.usecase.GetList(c.Request().Context(), &req)
+	if err != nil {
+		return h.NewResponseWithError(c, "get mail reply list failed", err)
+	}
+	return h.NewResponseWithData(c, resp)
+}
+
+// UpdateMailReply 编辑邮件回复草稿
+//
+//	@Tags			MailReply
+//	@Summary		编辑邮件回复草稿
+//	@Description	仅未发送的回复可编辑
+//	@ID				v1-UpdateMailReply
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.MailReplyUpdateReq	true	"para"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/app/mail/reply [put]
+func (h *MailReplyHandler) UpdateMailReply(c echo.Context) error {
+	ctx := c.Request().Context()
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return h.NewResponseWithError(c, "authInfo not found in context", nil)
+	}
+
+	var req v1.MailReplyUpdateReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+
+	if err := h.usecase.Update(ctx, &req, authInfo.UserId); err != nil {
+		return h.NewResponseWithError(c, "update mail reply failed", err)
+	}
+	return h.NewResponseWithData(c, nil)
+}
+
+// SendMailReply 审核通过并发送邮件回复
+//
+//	@Tags			MailReply
+//	@Summary		审核通过并发送邮件回复
+//	@Description	发送待审核或发送失败的回复
+//	@ID				v1-SendMailReply
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.MailReplyActionReq	true	"para"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/app/mail/reply/send [post]
+func (h *MailReplyHandler) SendMailReply(c echo.Context) error {
+	ctx := c.Request().Context()
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return h.NewResponseWithError(c, "authInfo not found in context", nil)
+	}
+
+	var req v1.MailReplyActionReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+
+	if err := h.usecase.Send(ctx, &req, authInfo.UserId); err != nil {
+		return h.NewResponseWithError(c, "send mail reply failed", err)
+	}
+	return h.NewResponseWithData(c, nil)
+}
+
+// DiscardMailReply 丢弃邮件回复草稿
+//
+//	@Tags			MailReply
+//	@Summary		丢弃邮件回复草稿
+//	@Description	丢弃后不再发送
+//	@ID				v1-DiscardMailReply
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.MailReplyActionReq	true	"para"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/app/mail/reply/discard [post]
+func (h *MailReplyHandler) DiscardMailReply(c echo.Context) error {
+	ctx := c.Request().Context()
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return h.NewResponseWithError(c, "authInfo not found in context", nil)
+	}
+
+	var req v1.MailReplyActionReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+
+	if err := h.usecase.Discard(ctx, &req, authInfo.UserId); err != nil {
+		return h.NewResponseWithError(c, "discard mail reply failed", err)
+	}
+	return h.NewResponseWithData(c, nil)
+}
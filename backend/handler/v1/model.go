@@ -132,9 +132,21 @@ func (h *ModelHandler) UpdateModel(c echo.Context) error {
 		return h.NewResponseWithError(c, "联创版只能使用百智云模型哦~", nil)
 	}
 	ctx := c.Request().Context()
+	before, err := h.usecase.GetModel(ctx, req.ID)
+	if err != nil {
+		return h.NewResponseWithError(c, "get model failed", err)
+	}
 	if err := h.usecase.Update(ctx, &req); err != nil {
 		return h.NewResponseWithError(c, "update model failed", err)
 	}
+	if after, err := h.usecase.GetModel(ctx, req.ID); err == nil {
+		middleware.SetAuditChange(c, &domain.AuditTarget{
+			TargetType: "model",
+			TargetID:   req.ID,
+			Before:     before,
+			After:      after,
+		})
+	}
 	return h.NewResponseWithData(c, nil)
 }
 
@@ -1,7 +1,9 @@
 package v1
 
 import (
+	"context"
 	"errors"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 
@@ -35,7 +37,7 @@ func NewNodeHandler(
 		auth:        auth,
 	}
 
-	group := echo.Group("/api/v1/node", h.auth.Authorize, h.auth.ValidateKBUserPerm(consts.UserKBPermissionDocManage))
+	group := echo.Group("/api/v1/node", h.auth.Authorize, h.auth.ValidateKBCapability(consts.KBCapabilityNodeEdit))
 	group.GET("/list", h.GetNodeList)
 	group.POST("", h.CreateNode)
 	group.GET("/detail", h.GetNodeDetail)
@@ -50,7 +52,11 @@ func NewNodeHandler(
 
 	// node permission
 	group.GET("/permission", h.NodePermission)
-	group.PATCH("/permission/edit", h.NodePermissionEdit)
+	group.PATCH("/permission/edit", h.NodePermissionEdit, h.auth.ValidateKBCapability(consts.KBCapabilityNodePermission))
+
+	// tags and custom fields
+	group.GET("/meta/schema", h.GetNodeMetaSchema)
+	group.PUT("/meta/schema", h.UpdateNodeMetaSchema, h.auth.ValidateKBCapability(consts.KBCapabilityAppConfig))
 
 	return h
 }
@@ -90,6 +96,12 @@ func (h *NodeHandler) CreateNode(c echo.Context) error {
 		if errors.Is(err, domain.ErrMaxNodeLimitReached) {
 			return h.NewResponseWithError(c, "已达到最大文档数量限制，请升级到联创版或企业版", nil)
 		}
+		if errors.Is(err, domain.ErrNodeMetaInvalid) {
+			return h.NewResponseWithError(c, err.Error(), err)
+		}
+		if errors.Is(err, domain.ErrNodeTemplateTypeInvalid) {
+			return h.NewResponseWithError(c, "模板只能用于创建文档", err)
+		}
 		return h.NewResponseWithError(c, "create node failed", err)
 	}
 	return h.NewResponseWithData(c, map[string]any{
@@ -205,6 +217,9 @@ func (h *NodeHandler) UpdateNodeDetail(c echo.Context) error {
 	}
 
 	if err := h.usecase.Update(ctx, req, authInfo.UserId); err != nil {
+		if errors.Is(err, domain.ErrNodeMetaInvalid) {
+			return h.NewResponseWithError(c, err.Error(), err)
+		}
 		return h.NewResponseWithError(c, "update node detail failed", err)
 	}
 	return h.NewResponseWithData(c, nil)
@@ -378,9 +393,96 @@ func (h *NodeHandler) NodePermissionEdit(c echo.Context) error {
 	}
 
 	ctx := c.Request().Context()
-	err := h.usecase.NodePermissionsEdit(ctx, req)
+	before, err := h.nodePermissions(ctx, req.KbId, req.IDs)
+	if err != nil {
+		return h.NewResponseWithError(c, "get node permission detail failed", err)
+	}
+	err = h.usecase.NodePermissionsEdit(ctx, req)
 	if err != nil {
 		return h.NewResponseWithError(c, "update node permission failed", err)
 	}
+	if after, err := h.nodePermissions(ctx, req.KbId, req.IDs); err == nil {
+		middleware.SetAuditChange(c, &domain.AuditTarget{
+			KBID:       req.KbId,
+			TargetType: "node_permission",
+			TargetID:   strings.Join(req.IDs, ","),
+			Before:     before,
+			After:      after,
+		})
+	}
+	return h.NewResponseWithData(c, nil)
+}
+
+// nodePermissions 按文档 ID 汇总权限，用于审计日志
+func (h *NodeHandler) nodePermissions(ctx context.Context, kbID string, ids []string) (map[string]*v1.NodePermissionResp, error) {
+	permissions := make(map[string]*v1.NodePermissionResp, len(ids))
+	for _, id := range ids {
+		permission, err := h.usecase.GetNodePermissionsByID(ctx, id, kbID)
+		if err != nil {
+			return nil, err
+		}
+		permissions[id] = permission
+	}
+	return permissions, nil
+}
+
+// GetNodeMetaSchema 获取文档标签及自定义字段定义
+//
+//	@Tags			node
+//	@Summary		获取文档标签及自定义字段定义
+//	@Description	获取知识库的标签词表及自定义字段定义
+//	@ID				v1-GetNodeMetaSchema
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.NodeMetaSchemaReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.NodeMetaSchemaResp}
+//	@Router			/api/v1/node/meta/schema [get]
+func (h *NodeHandler) GetNodeMetaSchema(c echo.Context) error {
+	var req v1.NodeMetaSchemaReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	schema, err := h.usecase.GetMetaSchema(c.Request().Context(), req.KbId)
+	if err != nil {
+		return h.NewResponseWithError(c, "get node meta schema failed", err)
+	}
+	return h.NewResponseWithData(c, schema)
+}
+
+// UpdateNodeMetaSchema 更新文档标签及自定义字段定义
+//
+//	@Tags			node
+//	@Summary		更新文档标签及自定义字段定义
+//	@Description	更新知识库的标签词表及自定义字段定义, 已有文档上的取值不受影响
+//	@ID				v1-UpdateNodeMetaSchema
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.NodeMetaSchemaUpdateReq	true	"para"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/node/meta/schema [put]
+func (h *NodeHandler) UpdateNodeMetaSchema(c echo.Context) error {
+	var req v1.NodeMetaSchemaUpdateReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+
+	if err := h.usecase.UpdateMetaSchema(c.Request().Context(), req.KbId, &domain.NodeMetaSchema{
+		Tags:   req.Tags,
+		Fields: req.Fields,
+	}); err != nil {
+		if errors.Is(err, domain.ErrNodeMetaInvalid) {
+			return h.NewResponseWithError(c, err.Error(), err)
+		}
+		return h.NewResponseWithError(c, "update node meta schema failed", err)
+	}
 	return h.NewResponseWithData(c, nil)
 }
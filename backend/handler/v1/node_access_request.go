@@ -0,0 +1,205 @@
+package v1
+
+import (
+	"errors"
+
+	"github.com/labstack/echo/v4"
+
+	v1 "github.com/chaitin/panda-wiki/api/node/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/handler"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/middleware"
+	"github.com/chaitin/panda-wiki/usecase"
+)
+
+type NodeAccessRequestHandler struct {
+	*handler.BaseHandler
+	logger  *log.Logger
+	usecase *usecase.NodeAccessRequestUsecase
+	auth    middleware.AuthMiddleware
+}
+
+func NewNodeAccessRequestHandler(
+	baseHandler *handler.BaseHandler,
+	echo *echo.Echo,
+	usecase *usecase.NodeAccessRequestUsecase,
+	auth middleware.AuthMiddleware,
+	logger *log.Logger,
+) *NodeAccessRequestHandler {
+	h := &NodeAccessRequestHandler{
+		BaseHandler: baseHandler,
+		logger:      logger.WithModule("handler.v1.node_access_request"),
+		usecase:     usecase,
+		auth:        auth,
+	}
+
+	group := echo.Group("/api/v1/node/access_request", h.auth.Authorize, h.auth.ValidateKBCapability(consts.KBCapabilityUserManage))
+	group.GET("/list", h.ListAccessRequests)
+	group.POST("/decide", h.DecideAccessRequest)
+	group.GET("/grant/list", h.ListAccessGrants)
+	group.DELETE("/grant", h.DeleteAccessGrant)
+
+	return h
+}
+
+// ListAccessRequests 读者访问申请列表
+//
+//	@Tags			NodeAccessRequest
+//	@Summary		读者访问申请列表
+//	@Description	待处理的申请排在前面
+//	@ID				v1-ListAccessRequests
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.AccessRequestListReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.AccessRequestListResp}
+//	@Router			/api/v1/node/access_request/list [get]
+func (h *NodeAccessRequestHandler) ListAccessRequests(c echo.Context) error {
+	var req v1.AccessRequestListReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	resp, err := h.usecase.ListRequests(c.Request().Context(), &req)
+	if err != nil {
+		return h.NewResponseWithError(c, "list access requests failed", err)
+	}
+	return h.NewResponseWithData(c, resp)
+}
+
+// DecideAccessRequest 处理读者访问申请
+//
+//	@Tags			NodeAccessRequest
+//	@Summary		处理读者访问申请
+//	@Description	批准时将读者加入用户组，或单独授权该文档的访问；可问答权限只能通过用户组授予。处理结果通过邮件通知读者
+//	@ID				v1-DecideAccessRequest
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.AccessRequestDecideReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=domain.NodeAccessRequest}
+//	@Router			/api/v1/node/access_request/decide [post]
+func (h *NodeAccessRequestHandler) DecideAccessRequest(c echo.Context) error {
+	ctx := c.Request().Context()
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return h.NewResponseWithError(c, "authInfo not found in context", nil)
+	}
+
+	var req v1.AccessRequestDecideReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+	// 单独授权文档等同于修改文档访问权限
+	if req.Action == consts.NodeAccessRequestActionApprove && req.GrantType == consts.NodeAccessGrantTypeNode &&
+		!authInfo.HasCapability(consts.KBCapabilityNodePermission) {
+		return h.NewResponseWithError(c, "没有设置文档访问权限的权限", domain.ErrPermissionDenied)
+	}
+
+	before, err := h.usecase.GetRequest(ctx, req.KbId, req.ID)
+	if err != nil {
+		return h.NewResponseWithError(c, "get access request failed", err)
+	}
+	after, err := h.usecase.Decide(ctx, &req, authInfo.UserId)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrAccessRequestStatusInvalid):
+			return h.NewResponseWithError(c, "申请已被处理", err)
+		case errors.Is(err, domain.ErrAccessGrantRequiresGroup):
+			return h.NewResponseWithError(c, "问答权限只能通过加入用户组授予", err)
+		case errors.Is(err, domain.ErrAuthGroupSynced):
+			return h.NewResponseWithError(c, "该用户组由目录同步管理，请选择其他用户组", err)
+		}
+		return h.NewResponseWithError(c, "decide access request failed", err)
+	}
+	middleware.SetAuditChange(c, &domain.AuditTarget{
+		KBID:       req.KbId,
+		TargetType: "node_access_request",
+		TargetID:   req.ID,
+		Before:     before,
+		After:      after,
+	})
+	return h.NewResponseWithData(c, after)
+}
+
+// ListAccessGrants 文档单独授权列表
+//
+//	@Tags			NodeAccessRequest
+//	@Summary		文档单独授权列表
+//	@Description	通过访问申请单独授权给读者的文档
+//	@ID				v1-ListAccessGrants
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.AccessGrantListReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=[]v1.AccessGrantItem}
+//	@Router			/api/v1/node/access_request/grant/list [get]
+func (h *NodeAccessRequestHandler) ListAccessGrants(c echo.Context) error {
+	var req v1.AccessGrantListReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	grants, err := h.usecase.ListGrants(c.Request().Context(), &req)
+	if err != nil {
+		return h.NewResponseWithError(c, "list access grants failed", err)
+	}
+	return h.NewResponseWithData(c, grants)
+}
+
+// DeleteAccessGrant 撤销文档单独授权
+//
+//	@Tags			NodeAccessRequest
+//	@Summary		撤销文档单独授权
+//	@Description	撤销后读者按用户组权限访问该文档
+//	@ID				v1-DeleteAccessGrant
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.AccessGrantDeleteReq	true	"para"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/node/access_request/grant [delete]
+func (h *NodeAccessRequestHandler) DeleteAccessGrant(c echo.Context) error {
+	ctx := c.Request().Context()
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return h.NewResponseWithError(c, "authInfo not found in context", nil)
+	}
+	if !authInfo.HasCapability(consts.KBCapabilityNodePermission) {
+		return h.NewResponseWithError(c, "没有设置文档访问权限的权限", domain.ErrPermissionDenied)
+	}
+
+	var req v1.AccessGrantDeleteReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	before, err := h.usecase.GetGrant(ctx, req.KbId, req.ID)
+	if err != nil {
+		return h.NewResponseWithError(c, "get access grant failed", err)
+	}
+	if err := h.usecase.DeleteGrant(ctx, &req); err != nil {
+		return h.NewResponseWithError(c, "delete access grant failed", err)
+	}
+	middleware.SetAuditChange(c, &domain.AuditTarget{
+		KBID:       req.KbId,
+		TargetType: "node_auth_grant",
+		TargetID:   before.NodeID,
+		Before:     before,
+	})
+	return h.NewResponseWithData(c, nil)
+}
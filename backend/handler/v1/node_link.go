@@ -0,0 +1,128 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+
+	v1 "github.com/chaitin/panda-wiki/api/node/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/handler"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/middleware"
+	"github.com/chaitin/panda-wiki/usecase"
+)
+
+type NodeLinkHandler struct {
+	*handler.BaseHandler
+	logger  *log.Logger
+	usecase *usecase.NodeLinkUsecase
+	auth    middleware.AuthMiddleware
+}
+
+func NewNodeLinkHandler(
+	baseHandler *handler.BaseHandler,
+	echo *echo.Echo,
+	usecase *usecase.NodeLinkUsecase,
+	auth middleware.AuthMiddleware,
+	logger *log.Logger,
+) *NodeLinkHandler {
+	h := &NodeLinkHandler{
+		BaseHandler: baseHandler,
+		logger:      logger.WithModule("handler.v1.node_link"),
+		usecase:     usecase,
+		auth:        auth,
+	}
+
+	group := echo.Group("/api/v1/node", h.auth.Authorize, h.auth.ValidateKBCapability(consts.KBCapabilityNodeEdit))
+	group.GET("/links/broken", h.GetBrokenLinks)
+	group.POST("/links/check", h.CheckLinks)
+	group.GET("/backlinks", h.GetBacklinks)
+
+	return h
+}
+
+// GetBrokenLinks 获取失效链接列表
+//
+//	@Tags			NodeLink
+//	@Summary		获取失效链接列表
+//	@Description	内部链接指向已删除或未发布的文档, 外部链接无法访问
+//	@ID				v1-GetBrokenLinks
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.BrokenLinkListReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.BrokenLinkListResp}
+//	@Router			/api/v1/node/links/broken [get]
+func (h *NodeLinkHandler) GetBrokenLinks(c echo.Context) error {
+	var req v1.BrokenLinkListReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	resp, err := h.usecase.GetBrokenLinks(c.Request().Context(), &req)
+	if err != nil {
+		return h.NewResponseWithError(c, "get broken links failed", err)
+	}
+	return h.NewResponseWithData(c, resp)
+}
+
+// CheckLinks 检查知识库链接
+//
+//	@Tags			NodeLink
+//	@Summary		检查知识库链接
+//	@Description	后台重建链接索引并检查外部链接
+//	@ID				v1-CheckLinks
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.CheckLinksReq	true	"para"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/node/links/check [post]
+func (h *NodeLinkHandler) CheckLinks(c echo.Context) error {
+	var req v1.CheckLinksReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+
+	go func() {
+		if err := h.usecase.CheckKBLinks(context.Background(), req.KbId); err != nil {
+			h.logger.Error("check kb links failed", log.String("kb_id", req.KbId), log.Error(err))
+		}
+	}()
+	return h.NewResponseWithData(c, nil)
+}
+
+// GetBacklinks 获取文档反向链接
+//
+//	@Tags			NodeLink
+//	@Summary		获取文档反向链接
+//	@Description	获取链接到该文档的其他文档
+//	@ID				v1-GetBacklinks
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.NodeBacklinksReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.NodeBacklinksResp}
+//	@Router			/api/v1/node/backlinks [get]
+func (h *NodeLinkHandler) GetBacklinks(c echo.Context) error {
+	var req v1.NodeBacklinksReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	resp, err := h.usecase.GetBacklinks(c.Request().Context(), &req)
+	if err != nil {
+		return h.NewResponseWithError(c, "get backlinks failed", err)
+	}
+	return h.NewResponseWithData(c, resp)
+}
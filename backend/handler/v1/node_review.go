@@ -0,0 +1,234 @@
+package v1
+
+import (
+	"errors"
+
+	"github.com/labstack/echo/v4"
+
+	v1 "github.com/chaitin/panda-wiki/api/node/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/handler"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/middleware"
+	"github.com/chaitin/panda-wiki/usecase"
+)
+
+type NodeReviewHandler struct {
+	*handler.BaseHandler
+	logger  *log.Logger
+	usecase *usecase.NodeReviewUsecase
+	auth    middleware.AuthMiddleware
+}
+
+func NewNodeReviewHandler(
+	baseHandler *handler.BaseHandler,
+	echo *echo.Echo,
+	usecase *usecase.NodeReviewUsecase,
+	auth middleware.AuthMiddleware,
+	logger *log.Logger,
+) *NodeReviewHandler {
+	h := &NodeReviewHandler{
+		BaseHandler: baseHandler,
+		logger:      logger.WithModule("handler.v1.node_review"),
+		usecase:     usecase,
+		auth:        auth,
+	}
+
+	group := echo.Group("/api/v1/node/review", h.auth.Authorize, h.auth.ValidateKBCapability(consts.KBCapabilityNodeEdit))
+	group.GET("/policy", h.GetReviewPolicy)
+	group.PUT("/policy", h.UpdateReviewPolicy, h.auth.ValidateKBCapability(consts.KBCapabilityAppConfig))
+	group.POST("/submit", h.SubmitReview)
+	group.POST("/decide", h.DecideReview)
+	group.GET("/queue", h.GetReviewQueue)
+	group.GET("/detail", h.GetReviewDetail)
+
+	return h
+}
+
+// GetReviewPolicy 获取发布审核策略
+//
+//	@Tags			NodeReview
+//	@Summary		获取发布审核策略
+//	@Description	获取发布审核策略
+//	@ID				v1-GetReviewPolicy
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.NodeReviewPolicyReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.NodeReviewPolicyResp}
+//	@Router			/api/v1/node/review/policy [get]
+func (h *NodeReviewHandler) GetReviewPolicy(c echo.Context) error {
+	var req v1.NodeReviewPolicyReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	resp, err := h.usecase.GetPolicy(c.Request().Context(), req.KbId)
+	if err != nil {
+		return h.NewResponseWithError(c, "get review policy failed", err)
+	}
+	return h.NewResponseWithData(c, resp)
+}
+
+// UpdateReviewPolicy 更新发布审核策略
+//
+//	@Tags			NodeReview
+//	@Summary		更新发布审核策略
+//	@Description	更新发布审核策略
+//	@ID				v1-UpdateReviewPolicy
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.NodeReviewPolicyUpdateReq	true	"para"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/node/review/policy [put]
+func (h *NodeReviewHandler) UpdateReviewPolicy(c echo.Context) error {
+	var req v1.NodeReviewPolicyUpdateReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+
+	if err := h.usecase.UpdatePolicy(c.Request().Context(), &req); err != nil {
+		return h.NewResponseWithError(c, "update review policy failed", err)
+	}
+	return h.NewResponseWithData(c, nil)
+}
+
+// SubmitReview 提交文档审核
+//
+//	@Tags			NodeReview
+//	@Summary		提交文档审核
+//	@Description	提交文档审核
+//	@ID				v1-SubmitReview
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.NodeReviewSubmitReq	true	"para"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/node/review/submit [post]
+func (h *NodeReviewHandler) SubmitReview(c echo.Context) error {
+	ctx := c.Request().Context()
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return h.NewResponseWithError(c, "authInfo not found in context", nil)
+	}
+
+	var req v1.NodeReviewSubmitReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+
+	if err := h.usecase.Submit(ctx, &req, authInfo.UserId); err != nil {
+		return h.NewResponseWithError(c, "submit review failed", err)
+	}
+	return h.NewResponseWithData(c, nil)
+}
+
+// DecideReview 审核文档
+//
+//	@Tags			NodeReview
+//	@Summary		审核文档
+//	@Description	审核通过或要求修改
+//	@ID				v1-DecideReview
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.NodeReviewDecideReq	true	"para"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/node/review/decide [post]
+func (h *NodeReviewHandler) DecideReview(c echo.Context) error {
+	ctx := c.Request().Context()
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return h.NewResponseWithError(c, "authInfo not found in context", nil)
+	}
+
+	var req v1.NodeReviewDecideReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+
+	if err := h.usecase.Decide(ctx, &req, authInfo); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNodeReviewSelfDecide):
+			return h.NewResponseWithError(c, "不能审核自己提交的文档", err)
+		case errors.Is(err, domain.ErrPermissionDenied):
+			return h.NewResponseWithError(c, "当前用户不是该知识库的审核人", err)
+		case errors.Is(err, domain.ErrNodeReviewOutdated):
+			return h.NewResponseWithError(c, "文档在提交审核后已被修改，请重新提交审核", err)
+		case errors.Is(err, domain.ErrNodeReviewStatusInvalid):
+			return h.NewResponseWithError(c, "文档不在待审核状态", err)
+		}
+		return h.NewResponseWithError(c, "decide review failed", err)
+	}
+	return h.NewResponseWithData(c, nil)
+}
+
+// GetReviewQueue 获取审核队列
+//
+//	@Tags			NodeReview
+//	@Summary		获取审核队列
+//	@Description	获取审核队列, 默认返回待审核的文档
+//	@ID				v1-GetReviewQueue
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.NodeReviewQueueReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.NodeReviewQueueResp}
+//	@Router			/api/v1/node/review/queue [get]
+func (h *NodeReviewHandler) GetReviewQueue(c echo.Context) error {
+	var req v1.NodeReviewQueueReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	resp, err := h.usecase.GetQueue(c.Request().Context(), &req)
+	if err != nil {
+		return h.NewResponseWithError(c, "get review queue failed", err)
+	}
+	return h.NewResponseWithData(c, resp)
+}
+
+// GetReviewDetail 获取文档审核状态及审核记录
+//
+//	@Tags			NodeReview
+//	@Summary		获取文档审核状态及审核记录
+//	@Description	获取文档审核状态及审核记录
+//	@ID				v1-GetReviewDetail
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.NodeReviewDetailReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.NodeReviewDetailResp}
+//	@Router			/api/v1/node/review/detail [get]
+func (h *NodeReviewHandler) GetReviewDetail(c echo.Context) error {
+	var req v1.NodeReviewDetailReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	resp, err := h.usecase.GetDetail(c.Request().Context(), &req)
+	if err != nil {
+		return h.NewResponseWithError(c, "get review detail failed", err)
+	}
+	return h.NewResponseWithData(c, resp)
+}
@@ -0,0 +1,153 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+
+	v1 "github.com/chaitin/panda-wiki/api/node/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/handler"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/middleware"
+	"github.com/chaitin/panda-wiki/usecase"
+)
+
+type NodeShareLinkHandler struct {
+	*handler.BaseHandler
+	logger  *log.Logger
+	usecase *usecase.NodeShareLinkUsecase
+	auth    middleware.AuthMiddleware
+}
+
+func NewNodeShareLinkHandler(
+	baseHandler *handler.BaseHandler,
+	echo *echo.Echo,
+	usecase *usecase.NodeShareLinkUsecase,
+	auth middleware.AuthMiddleware,
+	logger *log.Logger,
+) *NodeShareLinkHandler {
+	h := &NodeShareLinkHandler{
+		BaseHandler: baseHandler,
+		logger:      logger.WithModule("handler.v1.node_share_link"),
+		usecase:     usecase,
+		auth:        auth,
+	}
+
+	group := echo.Group("/api/v1/node/share_link", h.auth.Authorize, h.auth.ValidateKBCapability(consts.KBCapabilityNodeEdit))
+	group.GET("/list", h.ListShareLinks)
+	group.POST("", h.CreateShareLink)
+	group.DELETE("", h.RevokeShareLink)
+
+	return h
+}
+
+// ListShareLinks 分享链接列表
+//
+//	@Tags			NodeShareLink
+//	@Summary		分享链接列表
+//	@Description	知识库或指定文档的分享链接，包含已撤销和已过期的链接
+//	@ID				v1-ListShareLinks
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.ShareLinkListReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=[]v1.ShareLinkItem}
+//	@Router			/api/v1/node/share_link/list [get]
+func (h *NodeShareLinkHandler) ListShareLinks(c echo.Context) error {
+	var req v1.ShareLinkListReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	links, err := h.usecase.ListLinks(c.Request().Context(), &req)
+	if err != nil {
+		return h.NewResponseWithError(c, "list share links failed", err)
+	}
+	return h.NewResponseWithData(c, links)
+}
+
+// CreateShareLink 创建分享链接
+//
+//	@Tags			NodeShareLink
+//	@Summary		创建分享链接
+//	@Description	分享文档或文件夹，持有链接即可访问，不受文档访问权限限制；draft 为 true 时预览未发布的内容
+//	@ID				v1-CreateShareLink
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.ShareLinkCreateReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.ShareLinkItem}
+//	@Router			/api/v1/node/share_link [post]
+func (h *NodeShareLinkHandler) CreateShareLink(c echo.Context) error {
+	ctx := c.Request().Context()
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return h.NewResponseWithError(c, "authInfo not found in context", nil)
+	}
+
+	var req v1.ShareLinkCreateReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+	// 已发布内容的分享绕过访问权限，需要权限设置能力；预览草稿只需编辑能力
+	if !req.Draft && !authInfo.HasCapability(consts.KBCapabilityNodePermission) {
+		return h.NewResponseWithError(c, "没有设置文档访问权限的权限", domain.ErrPermissionDenied)
+	}
+
+	link, err := h.usecase.CreateLink(ctx, &req, authInfo.UserId)
+	if err != nil {
+		return h.NewResponseWithError(c, "create share link failed", err)
+	}
+	middleware.SetAuditChange(c, &domain.AuditTarget{
+		KBID:       req.KbId,
+		TargetType: "node_share_link",
+		TargetID:   link.ID,
+		After:      link.NodeShareLink,
+	})
+	return h.NewResponseWithData(c, link)
+}
+
+// RevokeShareLink 撤销分享链接
+//
+//	@Tags			NodeShareLink
+//	@Summary		撤销分享链接
+//	@Description	撤销后链接立即失效，访问记录保留
+//	@ID				v1-RevokeShareLink
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.ShareLinkRevokeReq	true	"para"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/node/share_link [delete]
+func (h *NodeShareLinkHandler) RevokeShareLink(c echo.Context) error {
+	var req v1.ShareLinkRevokeReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	ctx := c.Request().Context()
+	before, err := h.usecase.GetLink(ctx, req.KbId, req.ID)
+	if err != nil {
+		return h.NewResponseWithError(c, "get share link failed", err)
+	}
+	if err := h.usecase.RevokeLink(ctx, &req); err != nil {
+		return h.NewResponseWithError(c, "revoke share link failed", err)
+	}
+	middleware.SetAuditChange(c, &domain.AuditTarget{
+		KBID:       req.KbId,
+		TargetType: "node_share_link",
+		TargetID:   req.ID,
+		Before:     map[string]any{"node_id": before.NodeID, "revoked": before.RevokedAt != nil},
+		After:      map[string]any{"node_id": before.NodeID, "revoked": true},
+	})
+	return h.NewResponseWithData(c, nil)
+}
@@ -0,0 +1,95 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+
+	v1 "github.com/chaitin/panda-wiki/api/node/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/handler"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/middleware"
+	"github.com/chaitin/panda-wiki/usecase"
+)
+
+type NodeStaleHandler struct {
+	*handler.BaseHandler
+	logger  *log.Logger
+	usecase *usecase.NodeStaleUsecase
+	auth    middleware.AuthMiddleware
+}
+
+func NewNodeStaleHandler(
+	baseHandler *handler.BaseHandler,
+	echo *echo.Echo,
+	usecase *usecase.NodeStaleUsecase,
+	auth middleware.AuthMiddleware,
+	logger *log.Logger,
+) *NodeStaleHandler {
+	h := &NodeStaleHandler{
+		BaseHandler: baseHandler,
+		logger:      logger.WithModule("handler.v1.node_stale"),
+		usecase:     usecase,
+		auth:        auth,
+	}
+
+	group := echo.Group("/api/v1/node/stale", h.auth.Authorize, h.auth.ValidateKBCapability(consts.KBCapabilityNodeEdit))
+	group.GET("", h.GetStaleNodeList)
+	group.POST("/reviewed", h.MarkNodeReviewed)
+
+	return h
+}
+
+// GetStaleNodeList 获取可能过时的文档列表
+//
+//	@Tags			NodeStale
+//	@Summary		获取可能过时的文档列表
+//	@Description	超过复查周期未更新, 或引用该文档的回答最近被点踩的文档
+//	@ID				v1-GetStaleNodeList
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.StaleNodeListReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.StaleNodeListResp}
+//	@Router			/api/v1/node/stale [get]
+func (h *NodeStaleHandler) GetStaleNodeList(c echo.Context) error {
+	var req v1.StaleNodeListReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	resp, err := h.usecase.GetStaleList(c.Request().Context(), &req)
+	if err != nil {
+		return h.NewResponseWithError(c, "get stale node list failed", err)
+	}
+	return h.NewResponseWithData(c, resp)
+}
+
+// MarkNodeReviewed 标记文档已复查
+//
+//	@Tags			NodeStale
+//	@Summary		标记文档已复查
+//	@Description	重置文档的复查周期, 不修改文档内容
+//	@ID				v1-MarkNodeReviewed
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.MarkNodeReviewedReq	true	"para"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/node/stale/reviewed [post]
+func (h *NodeStaleHandler) MarkNodeReviewed(c echo.Context) error {
+	var req v1.MarkNodeReviewedReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+
+	if err := h.usecase.MarkReviewed(c.Request().Context(), &req); err != nil {
+		return h.NewResponseWithError(c, "mark node reviewed failed", err)
+	}
+	return h.NewResponseWithData(c, nil)
+}
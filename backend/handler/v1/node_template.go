@@ -0,0 +1,234 @@
+package v1
+
+import (
+	"errors"
+
+	"github.com/labstack/echo/v4"
+
+	v1 "github.com/chaitin/panda-wiki/api/node/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/handler"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/middleware"
+	"github.com/chaitin/panda-wiki/usecase"
+)
+
+type NodeTemplateHandler struct {
+	*handler.BaseHandler
+	logger  *log.Logger
+	usecase *usecase.NodeTemplateUsecase
+	auth    middleware.AuthMiddleware
+}
+
+func NewNodeTemplateHandler(
+	baseHandler *handler.BaseHandler,
+	echo *echo.Echo,
+	usecase *usecase.NodeTemplateUsecase,
+	auth middleware.AuthMiddleware,
+	logger *log.Logger,
+) *NodeTemplateHandler {
+	h := &NodeTemplateHandler{
+		BaseHandler: baseHandler,
+		logger:      logger.WithModule("handler.v1.node_template"),
+		usecase:     usecase,
+		auth:        auth,
+	}
+
+	group := echo.Group("/api/v1/node/template", h.auth.Authorize, h.auth.ValidateKBCapability(consts.KBCapabilityNodeEdit))
+	group.GET("/list", h.GetTemplateList)
+	group.GET("/detail", h.GetTemplateDetail)
+	group.POST("", h.CreateTemplate)
+	group.PUT("", h.UpdateTemplate)
+	group.DELETE("", h.DeleteTemplate)
+	group.POST("/from_node", h.CreateTemplateFromNode)
+
+	return h
+}
+
+// GetTemplateList 获取文档模板列表
+//
+//	@Tags			NodeTemplate
+//	@Summary		获取文档模板列表
+//	@Description	获取文档模板列表
+//	@ID				v1-GetTemplateList
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.NodeTemplateListReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=[]v1.NodeTemplateListItem}
+//	@Router			/api/v1/node/template/list [get]
+func (h *NodeTemplateHandler) GetTemplateList(c echo.Context) error {
+	var req v1.NodeTemplateListReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	templates, err := h.usecase.GetList(c.Request().Context(), req.KbId)
+	if err != nil {
+		return h.NewResponseWithError(c, "get template list failed", err)
+	}
+	return h.NewResponseWithData(c, templates)
+}
+
+// GetTemplateDetail 获取文档模板详情
+//
+//	@Tags			NodeTemplate
+//	@Summary		获取文档模板详情
+//	@Description	获取文档模板详情
+//	@ID				v1-GetTemplateDetail
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.NodeTemplateDetailReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.NodeTemplateDetailResp}
+//	@Router			/api/v1/node/template/detail [get]
+func (h *NodeTemplateHandler) GetTemplateDetail(c echo.Context) error {
+	var req v1.NodeTemplateDetailReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	template, err := h.usecase.GetDetail(c.Request().Context(), req.KbId, req.ID)
+	if err != nil {
+		return h.NewResponseWithError(c, "get template detail failed", err)
+	}
+	return h.NewResponseWithData(c, template)
+}
+
+// CreateTemplate 创建文档模板
+//
+//	@Tags			NodeTemplate
+//	@Summary		创建文档模板
+//	@Description	创建文档模板, 标题/内容/自定义字段中可使用占位符 {{date}} {{datetime}} {{author}} {{parent}} {{kb}} {{title}}
+//	@ID				v1-CreateTemplate
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.NodeTemplateCreateReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.NodeTemplateCreateResp}
+//	@Router			/api/v1/node/template [post]
+func (h *NodeTemplateHandler) CreateTemplate(c echo.Context) error {
+	ctx := c.Request().Context()
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return h.NewResponseWithError(c, "authInfo not found in context", nil)
+	}
+
+	var req v1.NodeTemplateCreateReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+
+	id, err := h.usecase.Create(ctx, &req, authInfo.UserId)
+	if err != nil {
+		if errors.Is(err, domain.ErrNodeMetaInvalid) {
+			return h.NewResponseWithError(c, err.Error(), err)
+		}
+		return h.NewResponseWithError(c, "create template failed", err)
+	}
+	return h.NewResponseWithData(c, v1.NodeTemplateCreateResp{ID: id})
+}
+
+// UpdateTemplate 更新文档模板
+//
+//	@Tags			NodeTemplate
+//	@Summary		更新文档模板
+//	@Description	更新文档模板
+//	@ID				v1-UpdateTemplate
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.NodeTemplateUpdateReq	true	"para"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/node/template [put]
+func (h *NodeTemplateHandler) UpdateTemplate(c echo.Context) error {
+	var req v1.NodeTemplateUpdateReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+
+	if err := h.usecase.Update(c.Request().Context(), &req); err != nil {
+		if errors.Is(err, domain.ErrNodeMetaInvalid) {
+			return h.NewResponseWithError(c, err.Error(), err)
+		}
+		return h.NewResponseWithError(c, "update template failed", err)
+	}
+	return h.NewResponseWithData(c, nil)
+}
+
+// DeleteTemplate 删除文档模板
+//
+//	@Tags			NodeTemplate
+//	@Summary		删除文档模板
+//	@Description	删除文档模板, 已由模板创建的文档不受影响
+//	@ID				v1-DeleteTemplate
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.NodeTemplateDeleteReq	true	"para"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/node/template [delete]
+func (h *NodeTemplateHandler) DeleteTemplate(c echo.Context) error {
+	var req v1.NodeTemplateDeleteReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	if err := h.usecase.Delete(c.Request().Context(), req.KbId, req.ID); err != nil {
+		return h.NewResponseWithError(c, "delete template failed", err)
+	}
+	return h.NewResponseWithData(c, nil)
+}
+
+// CreateTemplateFromNode 将文档保存为模板
+//
+//	@Tags			NodeTemplate
+//	@Summary		将文档保存为模板
+//	@Description	将已有文档的标题、内容、标签、自定义字段及权限保存为模板
+//	@ID				v1-CreateTemplateFromNode
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.NodeTemplateFromNodeReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.NodeTemplateCreateResp}
+//	@Router			/api/v1/node/template/from_node [post]
+func (h *NodeTemplateHandler) CreateTemplateFromNode(c echo.Context) error {
+	ctx := c.Request().Context()
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return h.NewResponseWithError(c, "authInfo not found in context", nil)
+	}
+
+	var req v1.NodeTemplateFromNodeReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+
+	id, err := h.usecase.CreateFromNode(ctx, &req, authInfo.UserId)
+	if err != nil {
+		if errors.Is(err, domain.ErrNodeTemplateTypeInvalid) {
+			return h.NewResponseWithError(c, "只能将文档保存为模板", err)
+		}
+		return h.NewResponseWithError(c, "create template from node failed", err)
+	}
+	return h.NewResponseWithData(c, v1.NodeTemplateCreateResp{ID: id})
+}
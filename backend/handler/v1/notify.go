@@ -0,0 +1,130 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+
+	v1 "github.com/chaitin/panda-wiki/api/notify/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/handler"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/middleware"
+	"github.com/chaitin/panda-wiki/usecase"
+)
+
+type NotifyHandler struct {
+	*handler.BaseHandler
+	logger  *log.Logger
+	usecase *usecase.NotifyUsecase
+	auth    middleware.AuthMiddleware
+}
+
+func NewNotifyHandler(
+	baseHandler *handler.BaseHandler,
+	echo *echo.Echo,
+	usecase *usecase.NotifyUsecase,
+	auth middleware.AuthMiddleware,
+	logger *log.Logger,
+) *NotifyHandler {
+	h := &NotifyHandler{
+		BaseHandler: baseHandler,
+		logger:      logger.WithModule("handler.v1.notify"),
+		usecase:     usecase,
+		auth:        auth,
+	}
+
+	group := echo.Group("/api/v1/notify", h.auth.Authorize, h.auth.ValidateKBCapability(consts.KBCapabilityAppConfig))
+	group.GET("/settings", h.GetNotifySettings)
+	group.PUT("/settings", h.UpdateNotifySettings)
+	group.POST("/test", h.TestNotify)
+
+	return h
+}
+
+// GetNotifySettings 获取通知设置
+//
+//	@Tags			Notify
+//	@Summary		获取通知设置
+//	@Description	获取邮件及群机器人通知设置
+//	@ID				v1-GetNotifySettings
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	query		v1.GetNotifySettingsReq	true	"para"
+//	@Success		200		{object}	domain.PWResponse{data=v1.GetNotifySettingsResp}
+//	@Router			/api/v1/notify/settings [get]
+func (h *NotifyHandler) GetNotifySettings(c echo.Context) error {
+	var req v1.GetNotifySettingsReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request params is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request params failed", err)
+	}
+
+	resp, err := h.usecase.GetSettings(c.Request().Context(), req.KbId)
+	if err != nil {
+		return h.NewResponseWithError(c, "get notify settings failed", err)
+	}
+	return h.NewResponseWithData(c, resp)
+}
+
+// UpdateNotifySettings 更新通知设置
+//
+//	@Tags			Notify
+//	@Summary		更新通知设置
+//	@Description	更新邮件及群机器人通知设置
+//	@ID				v1-UpdateNotifySettings
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.UpdateNotifySettingsReq	true	"para"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/notify/settings [put]
+func (h *NotifyHandler) UpdateNotifySettings(c echo.Context) error {
+	var req v1.UpdateNotifySettingsReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+
+	if err := h.usecase.UpdateSettings(c.Request().Context(), &req); err != nil {
+		return h.NewResponseWithError(c, "update notify settings failed", err)
+	}
+	return h.NewResponseWithData(c, nil)
+}
+
+// TestNotify 发送测试通知
+//
+//	@Tags			Notify
+//	@Summary		发送测试通知
+//	@Description	向当前用户及所有群机器人发送测试通知
+//	@ID				v1-TestNotify
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			param	body		v1.TestNotifyReq	true	"para"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/notify/test [post]
+func (h *NotifyHandler) TestNotify(c echo.Context) error {
+	ctx := c.Request().Context()
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return h.NewResponseWithError(c, "authInfo not found in context", nil)
+	}
+
+	var req v1.TestNotifyReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "request body is invalid", err)
+	}
+	if err := c.Validate(req); err != nil {
+		return h.NewResponseWithError(c, "validate request body failed", err)
+	}
+
+	if err := h.usecase.Test(ctx, req.KbId, authInfo.UserId); err != nil {
+		return h.NewResponseWithError(c, "send test notify failed", err)
+	}
+	return h.NewResponseWithData(c, nil)
+}
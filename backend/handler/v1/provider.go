@@ -9,18 +9,29 @@ import (
 )
 
 type APIHandlers struct {
-	UserHandler          *UserHandler
-	KnowledgeBaseHandler *KnowledgeBaseHandler
-	NodeHandler          *NodeHandler
-	AppHandler           *AppHandler
-	FileHandler          *FileHandler
-	ModelHandler         *ModelHandler
-	ConversationHandler  *ConversationHandler
-	CrawlerHandler       *CrawlerHandler
-	CreationHandler      *CreationHandler
-	StatHandler          *StatHandler
-	CommentHandler       *CommentHandler
-	AuthV1Handler        *AuthV1Handler
+	UserHandler              *UserHandler
+	KnowledgeBaseHandler     *KnowledgeBaseHandler
+	NodeHandler              *NodeHandler
+	NodeReviewHandler        *NodeReviewHandler
+	NodeStaleHandler         *NodeStaleHandler
+	NodeLinkHandler          *NodeLinkHandler
+	NodeTemplateHandler      *NodeTemplateHandler
+	NotifyHandler            *NotifyHandler
+	AppHandler               *AppHandler
+	MailReplyHandler         *MailReplyHandler
+	FileHandler              *FileHandler
+	ModelHandler             *ModelHandler
+	ConversationHandler      *ConversationHandler
+	EscalationHandler        *ConversationEscalationHandler
+	CrawlerHandler           *CrawlerHandler
+	CreationHandler          *CreationHandler
+	StatHandler              *StatHandler
+	CommentHandler           *CommentHandler
+	AuthV1Handler            *AuthV1Handler
+	AuditLogHandler          *AuditLogHandler
+	KBRoleHandler            *KBRoleHandler
+	NodeShareLinkHandler     *NodeShareLinkHandler
+	NodeAccessRequestHandler *NodeAccessRequestHandler
 }
 
 var ProviderSet = wire.NewSet(
@@ -29,8 +40,15 @@ var ProviderSet = wire.NewSet(
 
 	handler.NewBaseHandler,
 	NewNodeHandler,
+	NewNodeReviewHandler,
+	NewNodeStaleHandler,
+	NewNodeLinkHandler,
+	NewNodeTemplateHandler,
+	NewNotifyHandler,
 	NewAppHandler,
+	NewMailReplyHandler,
 	NewConversationHandler,
+	NewConversationEscalationHandler,
 	NewUserHandler,
 	NewFileHandler,
 	NewModelHandler,
@@ -40,6 +58,10 @@ var ProviderSet = wire.NewSet(
 	NewStatHandler,
 	NewCommentHandler,
 	NewAuthV1Handler,
+	NewAuditLogHandler,
+	NewKBRoleHandler,
+	NewNodeShareLinkHandler,
+	NewNodeAccessRequestHandler,
 
 	wire.Struct(new(APIHandlers), "*"),
 )
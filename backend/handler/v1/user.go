@@ -1,6 +1,8 @@
 package v1
 
 import (
+	"errors"
+
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 
@@ -32,13 +34,30 @@ func NewUserHandler(e *echo.Echo, baseHandler *handler.BaseHandler, logger *log.
 	}
 	group := e.Group("/api/v1/user")
 	group.POST("/login", h.Login)
+	group.POST("/login/2fa", h.LoginTwoFactor)
+	group.POST("/login/2fa/setup", h.LoginTwoFactorSetup)
 
 	group.GET("", h.GetUserInfo, h.auth.Authorize)
 	group.GET("/list", h.ListUsers, h.auth.Authorize)
 	group.POST("/create", h.CreateUser, h.auth.Authorize, h.auth.ValidateUserRole(consts.UserRoleAdmin))
 	group.PUT("/reset_password", h.ResetPassword, h.auth.Authorize)
+	group.PUT("/email", h.UpdateUserEmail, h.auth.Authorize)
 	group.DELETE("/delete", h.DeleteUser, h.auth.Authorize, h.auth.ValidateUserRole(consts.UserRoleAdmin))
 
+	// login sessions
+	group.GET("/sessions", h.ListSessions, h.auth.Authorize)
+	group.DELETE("/session", h.RevokeSession, h.auth.Authorize)
+	group.DELETE("/sessions", h.RevokeUserSessions, h.auth.Authorize, h.auth.ValidateUserRole(consts.UserRoleAdmin))
+
+	// two-factor authentication
+	group.POST("/2fa/setup", h.SetupTwoFactor, h.auth.Authorize)
+	group.POST("/2fa/enable", h.EnableTwoFactor, h.auth.Authorize)
+	group.POST("/2fa/disable", h.DisableTwoFactor, h.auth.Authorize)
+	group.POST("/2fa/recovery_codes", h.RegenerateRecoveryCodes, h.auth.Authorize)
+	group.POST("/2fa/reset", h.ResetTwoFactor, h.auth.Authorize, h.auth.ValidateUserRole(consts.UserRoleAdmin))
+	group.GET("/security_policy", h.GetLoginSecurityPolicy, h.auth.Authorize, h.auth.ValidateUserRole(consts.UserRoleAdmin))
+	group.PUT("/security_policy", h.SetLoginSecurityPolicy, h.auth.Authorize, h.auth.ValidateUserRole(consts.UserRoleAdmin))
+
 	return h
 }
 
@@ -68,6 +87,7 @@ func (h *UserHandler) CreateUser(c echo.Context) error {
 		Account:  req.Account,
 		Password: req.Password,
 		Role:     req.Role,
+		Email:    req.Email,
 	}, consts.GetLicenseEdition(c))
 	if err != nil {
 		return h.NewResponseWithError(c, "failed to create user", err)
@@ -96,12 +116,91 @@ func (h *UserHandler) Login(c echo.Context) error {
 		return h.NewResponseWithError(c, "invalid request", err)
 	}
 
-	token, err := h.usecase.VerifyUserAndGenerateToken(c.Request().Context(), req)
+	resp, err := h.usecase.VerifyUserAndGenerateToken(c.Request().Context(), req, sessionClient(c))
+	if err != nil {
+		return h.loginError(c, "failed to login", err)
+	}
+
+	return h.NewResponseWithData(c, resp)
+}
+
+// LoginTwoFactorSetup
+//
+//	@Summary		LoginTwoFactorSetup
+//	@Description	Bind an authenticator during login when the security policy requires two-factor authentication
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		v1.LoginTwoFactorSetupReq	true	"LoginTwoFactorSetup Request"
+//	@Success		200		{object}	domain.PWResponse{data=v1.TwoFactorSetupResp}
+//	@Router			/api/v1/user/login/2fa/setup [post]
+func (h *UserHandler) LoginTwoFactorSetup(c echo.Context) error {
+	var req v1.LoginTwoFactorSetupReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "invalid request", err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "invalid request", err)
+	}
+
+	resp, err := h.usecase.SetupLoginTwoFactor(c.Request().Context(), &req)
 	if err != nil {
-		return h.NewResponseWithError(c, "failed to login", err)
+		return h.loginError(c, "failed to setup two-factor authentication", err)
 	}
 
-	return h.NewResponseWithData(c, v1.LoginResp{Token: token})
+	return h.NewResponseWithData(c, resp)
+}
+
+// LoginTwoFactor
+//
+//	@Summary		LoginTwoFactor
+//	@Description	Finish login with a TOTP code or a recovery code
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		v1.LoginTwoFactorReq	true	"LoginTwoFactor Request"
+//	@Success		200		{object}	domain.PWResponse{data=v1.LoginTwoFactorResp}
+//	@Router			/api/v1/user/login/2fa [post]
+func (h *UserHandler) LoginTwoFactor(c echo.Context) error {
+	var req v1.LoginTwoFactorReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "invalid request", err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "invalid request", err)
+	}
+
+	resp, err := h.usecase.VerifyLoginTwoFactor(c.Request().Context(), &req, sessionClient(c))
+	if err != nil {
+		return h.loginError(c, "failed to login", err)
+	}
+
+	return h.NewResponseWithData(c, resp)
+}
+
+// sessionClient 登录会话记录的设备和 IP
+func sessionClient(c echo.Context) *domain.UserSession {
+	return &domain.UserSession{
+		IP:        c.RealIP(),
+		UserAgent: c.Request().UserAgent(),
+	}
+}
+
+// loginError 登录相关的已知错误返回具体提示
+func (h *UserHandler) loginError(c echo.Context, msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrLoginLocked):
+		return h.NewResponseWithError(c, "登录失败次数过多，请稍后再试", err)
+	case errors.Is(err, domain.ErrTwoFactorCodeInvalid):
+		return h.NewResponseWithError(c, "验证码错误", err)
+	case errors.Is(err, domain.ErrLoginChallengeExpired):
+		return h.NewResponseWithError(c, "登录已过期，请重新登录", err)
+	case errors.Is(err, domain.ErrTwoFactorRequired):
+		return h.NewResponseWithError(c, "安全策略要求管理员开启两步验证", err)
+	}
+	return h.NewResponseWithError(c, msg, err)
 }
 
 // GetUserInfo
@@ -125,12 +224,14 @@ func (h *UserHandler) GetUserInfo(c echo.Context) error {
 	}
 
 	userInfo := &v1.UserInfoResp{
-		ID:         user.ID,
-		Account:    user.Account,
-		Role:       user.Role,
-		IsToken:    authInfo.IsToken,
-		LastAccess: &user.LastAccess,
-		CreatedAt:  user.CreatedAt,
+		ID:          user.ID,
+		Account:     user.Account,
+		Role:        user.Role,
+		Email:       user.Email,
+		IsToken:     authInfo.IsToken,
+		TOTPEnabled: user.TOTPEnabled,
+		LastAccess:  &user.LastAccess,
+		CreatedAt:   user.CreatedAt,
 	}
 
 	return h.NewResponseWithData(c, userInfo)
@@ -198,7 +299,12 @@ func (h *UserHandler) ResetPassword(c echo.Context) error {
 	if user.Account != "admin" && authInfo.UserId != req.ID {
 		return h.NewResponseWithError(c, "只有管理员可以重置其他用户密码", nil)
 	}
-	err = h.usecase.ResetPassword(c.Request().Context(), &req)
+	// 修改自己的密码时保留当前会话
+	keepSessionID := ""
+	if authInfo.UserId == req.ID {
+		keepSessionID = authInfo.SessionID
+	}
+	err = h.usecase.ResetPassword(c.Request().Context(), &req, keepSessionID)
 	if err != nil {
 		return h.NewResponseWithError(c, "failed to reset password", err)
 	}
@@ -206,6 +312,50 @@ func (h *UserHandler) ResetPassword(c echo.Context) error {
 	return h.NewResponseWithData(c, nil)
 }
 
+// UpdateUserEmail
+//
+//	@Summary		UpdateUserEmail
+//	@Description	UpdateUserEmail, used for notifications
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		v1.UpdateUserEmailReq	true	"UpdateUserEmail Request"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/user/email [put]
+func (h *UserHandler) UpdateUserEmail(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req v1.UpdateUserEmailReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "invalid request", err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "invalid request", err)
+	}
+
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return h.NewResponseWithError(c, "authInfo not found in context", nil)
+	}
+
+	if authInfo.IsToken {
+		return h.NewResponseWithError(c, "this api not support token call", nil)
+	}
+
+	user, err := h.usecase.GetUser(ctx, authInfo.UserId)
+	if err != nil {
+		return h.NewResponseWithError(c, "failed to get user", err)
+	}
+	if user.Role != consts.UserRoleAdmin && authInfo.UserId != req.ID {
+		return h.NewResponseWithError(c, "只有管理员可以修改其他用户邮箱", nil)
+	}
+	if err := h.usecase.UpdateUserEmail(ctx, &req); err != nil {
+		return h.NewResponseWithError(c, "failed to update email", err)
+	}
+
+	return h.NewResponseWithData(c, nil)
+}
+
 // DeleteUser
 //
 //	@Summary		DeleteUser
@@ -253,3 +403,344 @@ func (h *UserHandler) DeleteUser(c echo.Context) error {
 
 	return h.NewResponseWithData(c, nil)
 }
+
+// SetupTwoFactor
+//
+//	@Summary		SetupTwoFactor
+//	@Description	Generate a TOTP secret for the current user, confirmed by EnableTwoFactor
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	domain.PWResponse{data=v1.TwoFactorSetupResp}
+//	@Router			/api/v1/user/2fa/setup [post]
+func (h *UserHandler) SetupTwoFactor(c echo.Context) error {
+	ctx := c.Request().Context()
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return h.NewResponseWithError(c, "authInfo not found in context", nil)
+	}
+
+	if authInfo.IsToken {
+		return h.NewResponseWithError(c, "this api not support token call", nil)
+	}
+
+	resp, err := h.usecase.SetupTwoFactor(ctx, authInfo.UserId)
+	if err != nil {
+		return h.NewResponseWithError(c, "failed to setup two-factor authentication", err)
+	}
+
+	return h.NewResponseWithData(c, resp)
+}
+
+// EnableTwoFactor
+//
+//	@Summary		EnableTwoFactor
+//	@Description	Enable two-factor authentication with a code from the authenticator, returns recovery codes
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		v1.TwoFactorCodeReq	true	"EnableTwoFactor Request"
+//	@Success		200		{object}	domain.PWResponse{data=v1.TwoFactorRecoveryCodesResp}
+//	@Router			/api/v1/user/2fa/enable [post]
+func (h *UserHandler) EnableTwoFactor(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req v1.TwoFactorCodeReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "invalid request", err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "invalid request", err)
+	}
+
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return h.NewResponseWithError(c, "authInfo not found in context", nil)
+	}
+
+	if authInfo.IsToken {
+		return h.NewResponseWithError(c, "this api not support token call", nil)
+	}
+
+	resp, err := h.usecase.EnableTwoFactor(ctx, authInfo.UserId, req.Code)
+	if err != nil {
+		return h.loginError(c, "failed to enable two-factor authentication", err)
+	}
+
+	return h.NewResponseWithData(c, resp)
+}
+
+// DisableTwoFactor
+//
+//	@Summary		DisableTwoFactor
+//	@Description	Disable two-factor authentication with a code or a recovery code
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		v1.TwoFactorCodeReq	true	"DisableTwoFactor Request"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/user/2fa/disable [post]
+func (h *UserHandler) DisableTwoFactor(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req v1.TwoFactorCodeReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "invalid request", err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "invalid request", err)
+	}
+
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return h.NewResponseWithError(c, "authInfo not found in context", nil)
+	}
+
+	if authInfo.IsToken {
+		return h.NewResponseWithError(c, "this api not support token call", nil)
+	}
+
+	if err := h.usecase.DisableTwoFactor(ctx, authInfo.UserId, req.Code); err != nil {
+		return h.loginError(c, "failed to disable two-factor authentication", err)
+	}
+
+	return h.NewResponseWithData(c, nil)
+}
+
+// RegenerateRecoveryCodes
+//
+//	@Summary		RegenerateRecoveryCodes
+//	@Description	Replace all recovery codes of the current user
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		v1.TwoFactorCodeReq	true	"RegenerateRecoveryCodes Request"
+//	@Success		200		{object}	domain.PWResponse{data=v1.TwoFactorRecoveryCodesResp}
+//	@Router			/api/v1/user/2fa/recovery_codes [post]
+func (h *UserHandler) RegenerateRecoveryCodes(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req v1.TwoFactorCodeReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "invalid request", err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "invalid request", err)
+	}
+
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return h.NewResponseWithError(c, "authInfo not found in context", nil)
+	}
+
+	if authInfo.IsToken {
+		return h.NewResponseWithError(c, "this api not support token call", nil)
+	}
+
+	resp, err := h.usecase.RegenerateRecoveryCodes(ctx, authInfo.UserId, req.Code)
+	if err != nil {
+		return h.loginError(c, "failed to regenerate recovery codes", err)
+	}
+
+	return h.NewResponseWithData(c, resp)
+}
+
+// ResetTwoFactor
+//
+//	@Summary		ResetTwoFactor
+//	@Description	Turn off two-factor authentication for a user who lost the authenticator
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		v1.TwoFactorResetReq	true	"ResetTwoFactor Request"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/user/2fa/reset [post]
+func (h *UserHandler) ResetTwoFactor(c echo.Context) error {
+	var req v1.TwoFactorResetReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "invalid request", err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "invalid request", err)
+	}
+
+	if err := h.usecase.ResetTwoFactor(c.Request().Context(), req.UserID); err != nil {
+		return h.NewResponseWithError(c, "failed to reset two-factor authentication", err)
+	}
+
+	return h.NewResponseWithData(c, nil)
+}
+
+// GetLoginSecurityPolicy
+//
+//	@Summary		GetLoginSecurityPolicy
+//	@Description	GetLoginSecurityPolicy
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	domain.PWResponse{data=domain.LoginSecurityPolicy}
+//	@Router			/api/v1/user/security_policy [get]
+func (h *UserHandler) GetLoginSecurityPolicy(c echo.Context) error {
+	policy, err := h.usecase.GetLoginSecurityPolicy(c.Request().Context())
+	if err != nil {
+		return h.NewResponseWithError(c, "failed to get login security policy", err)
+	}
+
+	return h.NewResponseWithData(c, policy)
+}
+
+// SetLoginSecurityPolicy
+//
+//	@Summary		SetLoginSecurityPolicy
+//	@Description	SetLoginSecurityPolicy
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		v1.SetLoginSecurityPolicyReq	true	"SetLoginSecurityPolicy Request"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/user/security_policy [put]
+func (h *UserHandler) SetLoginSecurityPolicy(c echo.Context) error {
+	var req v1.SetLoginSecurityPolicyReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "invalid request", err)
+	}
+
+	ctx := c.Request().Context()
+	before, err := h.usecase.GetLoginSecurityPolicy(ctx)
+	if err != nil {
+		return h.NewResponseWithError(c, "failed to get login security policy", err)
+	}
+	if err := h.usecase.SetLoginSecurityPolicy(ctx, &req); err != nil {
+		return h.NewResponseWithError(c, "failed to set login security policy", err)
+	}
+	middleware.SetAuditChange(c, &domain.AuditTarget{
+		TargetType: "login_security_policy",
+		Before:     before,
+		After:      domain.LoginSecurityPolicy{RequireAdmin2FA: req.RequireAdmin2FA},
+	})
+
+	return h.NewResponseWithData(c, nil)
+}
+
+// ListSessions
+//
+//	@Summary		ListSessions
+//	@Description	List login sessions of the current user, admins can list sessions of any user
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Param			params	query		v1.UserSessionListReq	false	"ListSessions Request"
+//	@Success		200		{object}	domain.PWResponse{data=v1.UserSessionListResp}
+//	@Router			/api/v1/user/sessions [get]
+func (h *UserHandler) ListSessions(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req v1.UserSessionListReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "invalid request", err)
+	}
+
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return h.NewResponseWithError(c, "authInfo not found in context", nil)
+	}
+
+	if authInfo.IsToken {
+		return h.NewResponseWithError(c, "this api not support token call", nil)
+	}
+
+	if req.UserID == "" {
+		req.UserID = authInfo.UserId
+	}
+	if req.UserID != authInfo.UserId {
+		user, err := h.usecase.GetUser(ctx, authInfo.UserId)
+		if err != nil {
+			return h.NewResponseWithError(c, "failed to get user", err)
+		}
+		if user.Role != consts.UserRoleAdmin {
+			return h.NewResponseWithError(c, "只有管理员可以查看其他用户的会话", nil)
+		}
+	}
+
+	resp, err := h.usecase.ListSessions(ctx, req.UserID, authInfo.SessionID)
+	if err != nil {
+		return h.NewResponseWithError(c, "failed to list sessions", err)
+	}
+
+	return h.NewResponseWithData(c, resp)
+}
+
+// RevokeSession
+//
+//	@Summary		RevokeSession
+//	@Description	Revoke one login session of the current user
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Param			params	query		v1.RevokeUserSessionReq	true	"RevokeSession Request"
+//	@Success		200		{object}	domain.Response
+//	@Router			/api/v1/user/session [delete]
+func (h *UserHandler) RevokeSession(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req v1.RevokeUserSessionReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "invalid request", err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "invalid request", err)
+	}
+
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return h.NewResponseWithError(c, "authInfo not found in context", nil)
+	}
+
+	if authInfo.IsToken {
+		return h.NewResponseWithError(c, "this api not support token call", nil)
+	}
+
+	if err := h.usecase.RevokeSession(ctx, authInfo.UserId, req.ID); err != nil {
+		return h.NewResponseWithError(c, "failed to revoke session", err)
+	}
+
+	return h.NewResponseWithData(c, nil)
+}
+
+// RevokeUserSessions
+//
+//	@Summary		RevokeUserSessions
+//	@Description	Revoke all login sessions of a user, the current session is kept when revoking your own
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Param			params	query		v1.RevokeUserSessionsReq	true	"RevokeUserSessions Request"
+//	@Success		200		{object}	domain.PWResponse{data=v1.RevokeUserSessionsResp}
+//	@Router			/api/v1/user/sessions [delete]
+func (h *UserHandler) RevokeUserSessions(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req v1.RevokeUserSessionsReq
+	if err := c.Bind(&req); err != nil {
+		return h.NewResponseWithError(c, "invalid request", err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return h.NewResponseWithError(c, "invalid request", err)
+	}
+
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return h.NewResponseWithError(c, "authInfo not found in context", nil)
+	}
+
+	keepSessionID := ""
+	if req.UserID == authInfo.UserId {
+		keepSessionID = authInfo.SessionID
+	}
+	count, err := h.usecase.RevokeUserSessions(ctx, req.UserID, keepSessionID)
+	if err != nil {
+		return h.NewResponseWithError(c, "failed to revoke sessions", err)
+	}
+
+	return h.NewResponseWithData(c, v1.RevokeUserSessionsResp{Count: count})
+}
@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/usecase"
+)
+
+// auditMaxBodySize 超过该大小的请求体不记录内容
+const auditMaxBodySize = 64 << 10
+
+// auditSkipPaths 不修改数据的 POST 接口及登录接口
+var auditSkipPaths = map[string]bool{
+	"/api/v1/user/login":               true,
+	"/api/v1/user/login/2fa":           true,
+	"/api/v1/user/login/2fa/setup":     true,
+	"/api/v1/creation/text":            true,
+	"/api/v1/creation/tab-complete":    true,
+	"/api/v1/crawler/parse":            true,
+	"/api/v1/crawler/results":          true,
+	"/api/v1/model/check":              true,
+	"/api/v1/model/provider/supported": true,
+	"/api/v1/node/links/check":         true,
+	"/api/v1/node/summary":             true,
+	"/api/v1/notify/test":              true,
+}
+
+type AuditMiddleware struct {
+	logger       *log.Logger
+	auditUsecase *usecase.AuditUsecase
+}
+
+func NewAuditMiddleware(logger *log.Logger, auditUsecase *usecase.AuditUsecase) *AuditMiddleware {
+	return &AuditMiddleware{
+		logger:       logger.WithModule("middleware.audit"),
+		auditUsecase: auditUsecase,
+	}
+}
+
+// SetAuditChange handler 提供审计目标和变更前后的完整数据，未调用时记录请求参数
+func SetAuditChange(c echo.Context, target *domain.AuditTarget) {
+	c.Set(domain.AuditChangeKey, target)
+}
+
+// Audit 记录 /api/v1 下所有成功的修改请求
+func (m *AuditMiddleware) Audit(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		if !strings.HasPrefix(req.URL.Path, "/api/v1/") || isReadOnlyMethod(req.Method) {
+			return next(c)
+		}
+		body := readAuditBody(c)
+
+		err := next(c)
+		if err != nil || c.Response().Status >= 400 || c.Get(domain.ResponseErrorKey) != nil || auditSkipPaths[c.Path()] {
+			return err
+		}
+		ctx := c.Request().Context()
+		authInfo := domain.GetAuthInfoFromCtx(ctx)
+		if authInfo == nil {
+			return nil
+		}
+
+		entry := &domain.AuditLog{
+			ActorType:  domain.AuditActorUser,
+			ActorID:    authInfo.UserId,
+			Action:     req.Method + " " + c.Path(),
+			TargetType: auditTargetType(c.Path()),
+			IP:         c.RealIP(),
+			UserAgent:  req.UserAgent(),
+		}
+		if authInfo.IsToken {
+			entry.ActorType = domain.AuditActorAPIToken
+			entry.KBID = authInfo.KBId
+		}
+
+		var before, after any
+		if target, ok := c.Get(domain.AuditChangeKey).(*domain.AuditTarget); ok {
+			if target.KBID != "" {
+				entry.KBID = target.KBID
+			}
+			if target.TargetType != "" {
+				entry.TargetType = target.TargetType
+			}
+			entry.TargetID = target.TargetID
+			before, after = target.Before, target.After
+		} else {
+			params := auditParams(c, body)
+			if kbID, _ := params["kb_id"].(string); kbID != "" {
+				entry.KBID = kbID
+			} else if kbID, _ := params["id"].(string); kbID != "" && entry.TargetType == "knowledge_base" {
+				entry.KBID = kbID
+			}
+			for _, key := range []string{"id", "user_id", "node_id"} {
+				if id, _ := params[key].(string); id != "" {
+					entry.TargetID = id
+					break
+				}
+			}
+			after = params
+		}
+
+		// 请求已完成，记录不受客户端断开影响
+		if err := m.auditUsecase.Record(context.WithoutCancel(ctx), entry, before, after); err != nil {
+			m.logger.Error("record audit log failed", log.String("action", entry.Action), log.Error(err))
+		}
+		return nil
+	}
+}
+
+func readAuditBody(c echo.Context) []byte {
+	req := c.Request()
+	if req.Body == nil || req.ContentLength > auditMaxBodySize ||
+		!strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(req.Body, auditMaxBodySize+1))
+	// 读取的内容放回，不影响后续绑定
+	req.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), req.Body))
+	if err != nil || len(body) > auditMaxBodySize {
+		return nil
+	}
+	return body
+}
+
+// auditParams JSON 请求体与 query 参数合并
+func auditParams(c echo.Context, body []byte) map[string]any {
+	params := make(map[string]any)
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &params); err != nil {
+			params = map[string]any{}
+		}
+	}
+	for key, values := range c.QueryParams() {
+		if _, ok := params[key]; ok || len(values) == 0 {
+			continue
+		}
+		if len(values) == 1 {
+			params[key] = values[0]
+		} else {
+			params[key] = values
+		}
+	}
+	return params
+}
+
+// auditTargetType /api/v1/knowledge_base/detail -> knowledge_base
+func auditTargetType(path string) string {
+	path = strings.TrimPrefix(path, "/api/v1/")
+	if i := strings.Index(path, "/"); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
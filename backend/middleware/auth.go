@@ -8,21 +8,22 @@ import (
 	"github.com/chaitin/panda-wiki/config"
 	"github.com/chaitin/panda-wiki/consts"
 	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/repo/cache"
 	"github.com/chaitin/panda-wiki/repo/pg"
 )
 
 type AuthMiddleware interface {
 	Authorize(next echo.HandlerFunc) echo.HandlerFunc
 	ValidateUserRole(role consts.UserRole) echo.MiddlewareFunc
-	ValidateKBUserPerm(role consts.UserKBPermission) echo.MiddlewareFunc
+	ValidateKBCapability(capability consts.KBCapability) echo.MiddlewareFunc
 	ValidateLicenseEdition(edition consts.LicenseEdition) echo.MiddlewareFunc
 	MustGetUserID(c echo.Context) (string, bool)
 }
 
-func NewAuthMiddleware(config *config.Config, logger *log.Logger, userAccessRepo *pg.UserAccessRepository, apiTokenRepo *pg.APITokenRepo) (AuthMiddleware, error) {
+func NewAuthMiddleware(config *config.Config, logger *log.Logger, userAccessRepo *pg.UserAccessRepository, apiTokenRepo *pg.APITokenRepo, sessionRepo *cache.UserSessionRepo) (AuthMiddleware, error) {
 	switch config.Auth.Type {
 	case "jwt":
-		return NewJWTMiddleware(config, logger, userAccessRepo, apiTokenRepo), nil
+		return NewJWTMiddleware(config, logger, userAccessRepo, apiTokenRepo, sessionRepo), nil
 	default:
 		return nil, fmt.Errorf("invalid auth type: %s", config.Auth.Type)
 	}
@@ -16,6 +16,7 @@ import (
 	"github.com/chaitin/panda-wiki/consts"
 	"github.com/chaitin/panda-wiki/domain"
 	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/repo/cache"
 	"github.com/chaitin/panda-wiki/repo/pg"
 )
 
@@ -25,9 +26,10 @@ type JWTMiddleware struct {
 	logger         *log.Logger
 	userAccessRepo *pg.UserAccessRepository
 	apiTokenRepo   *pg.APITokenRepo
+	sessionRepo    *cache.UserSessionRepo
 }
 
-func NewJWTMiddleware(config *config.Config, logger *log.Logger, userAccessRepo *pg.UserAccessRepository, apiTokenRepo *pg.APITokenRepo) *JWTMiddleware {
+func NewJWTMiddleware(config *config.Config, logger *log.Logger, userAccessRepo *pg.UserAccessRepository, apiTokenRepo *pg.APITokenRepo, sessionRepo *cache.UserSessionRepo) *JWTMiddleware {
 	jwtMiddleware := echoMiddleware.WithConfig(echoMiddleware.Config{
 		SigningKey: []byte(config.Auth.JWT.Secret),
 		ErrorHandler: func(c echo.Context, err error) error {
@@ -44,6 +46,7 @@ func NewJWTMiddleware(config *config.Config, logger *log.Logger, userAccessRepo
 		logger:         logger.WithModule("middleware.jwt"),
 		userAccessRepo: userAccessRepo,
 		apiTokenRepo:   apiTokenRepo,
+		sessionRepo:    sessionRepo,
 	}
 }
 
@@ -60,10 +63,27 @@ func (m *JWTMiddleware) Authorize(next echo.HandlerFunc) echo.HandlerFunc {
 
 		return m.jwtMiddleware(func(c echo.Context) error {
 			if userID, ok := m.MustGetUserID(c); ok {
+				// 会话被注销后 token 立即失效
+				sessionID, _ := m.getClaim(c, "sid")
+				session, err := m.sessionRepo.GetSession(c.Request().Context(), sessionID)
+				if err != nil {
+					m.logger.Error("get user session failed", log.Error(err))
+				}
+				if session == nil || session.UserID != userID {
+					return c.JSON(http.StatusUnauthorized, domain.PWResponse{
+						Success: false,
+						Message: "Unauthorized",
+					})
+				}
+				if err := m.sessionRepo.TouchSession(c.Request().Context(), session, c.RealIP()); err != nil {
+					m.logger.Error("touch user session failed", log.Error(err))
+				}
+
 				ctx := context.WithValue(c.Request().Context(), domain.CtxAuthInfoKey, &domain.CtxAuthInfo{
 					IsToken:    false,
 					Permission: consts.UserKBPermissionNull,
 					UserId:     userID,
+					SessionID:  sessionID,
 				})
 
 				req := c.Request().WithContext(ctx)
@@ -144,7 +164,8 @@ func (m *JWTMiddleware) ValidateUserRole(role consts.UserRole) echo.MiddlewareFu
 	}
 }
 
-func (m *JWTMiddleware) ValidateKBUserPerm(perm consts.UserKBPermission) echo.MiddlewareFunc {
+// ValidateKBCapability 校验当前用户或 API Token 在知识库内拥有指定能力，并把能力写入 authInfo
+func (m *JWTMiddleware) ValidateKBCapability(capability consts.KBCapability) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			authInfo := domain.GetAuthInfoFromCtx(c.Request().Context())
@@ -157,31 +178,23 @@ func (m *JWTMiddleware) ValidateKBUserPerm(perm consts.UserKBPermission) echo.Mi
 
 			kbId, _ := GetKbID(c)
 
+			var capabilities []consts.KBCapability
 			if authInfo.IsToken {
 
 				if authInfo.KBId != kbId {
-					m.logger.Error("ValidateKBUserPerm ValidateTokenKBPerm kbId", "authInfo.KBId", authInfo.KBId, "kbId", kbId)
+					m.logger.Error("ValidateKBCapability ValidateTokenKBPerm kbId", "authInfo.KBId", authInfo.KBId, "kbId", kbId)
 					return c.JSON(http.StatusForbidden, domain.PWResponse{
 						Success: false,
 						Message: "Unauthorized ValidateTokenKBPerm kbId",
 					})
 				}
-
-				if authInfo.Permission != consts.UserKBPermissionFullControl && authInfo.Permission != perm {
-					return c.JSON(http.StatusForbidden, domain.PWResponse{
-						Success: false,
-						Message: "Unauthorized ValidateTokenKBPerm",
-					})
-				}
+				capabilities = authInfo.Permission.Capabilities()
 			} else {
 				// 正常用户请求
-				valid, err := m.userAccessRepo.ValidateKBPerm(kbId, authInfo.UserId, perm)
-				if err != nil || !valid {
-					if err != nil {
-						m.logger.Error("ValidateKBUserPerm ValidateKBPerm failed", log.Error(err))
-					} else {
-						m.logger.Info("ValidateKBUserPerm ValidateKBPerm failed", log.String("kb_id", kbId), log.String("user_id", authInfo.UserId))
-					}
+				var err error
+				capabilities, err = m.userAccessRepo.GetKBCapabilities(kbId, authInfo.UserId)
+				if err != nil {
+					m.logger.Error("ValidateKBCapability GetKBCapabilities failed", log.Error(err))
 					return c.JSON(http.StatusForbidden, domain.PWResponse{
 						Success: false,
 						Message: "Unauthorized ValidateKBPerm",
@@ -189,6 +202,15 @@ func (m *JWTMiddleware) ValidateKBUserPerm(perm consts.UserKBPermission) echo.Mi
 				}
 			}
 
+			authInfo.Capabilities = capabilities
+			if !authInfo.HasCapability(capability) {
+				m.logger.Info("ValidateKBCapability denied", log.String("kb_id", kbId), log.String("user_id", authInfo.UserId), log.String("capability", string(capability)))
+				return c.JSON(http.StatusForbidden, domain.PWResponse{
+					Success: false,
+					Message: "Unauthorized ValidateKBPerm",
+				})
+			}
+
 			return next(c)
 		}
 	}
@@ -219,6 +241,10 @@ func (m *JWTMiddleware) ValidateLicenseEdition(needEdition consts.LicenseEdition
 }
 
 func (m *JWTMiddleware) MustGetUserID(c echo.Context) (string, bool) {
+	return m.getClaim(c, "id")
+}
+
+func (m *JWTMiddleware) getClaim(c echo.Context, key string) (string, bool) {
 	user, ok := c.Get("user").(*jwt.Token)
 	if !ok || user == nil {
 		return "", false
@@ -227,8 +253,8 @@ func (m *JWTMiddleware) MustGetUserID(c echo.Context) (string, bool) {
 	if !ok {
 		return "", false
 	}
-	id, ok := claims["id"].(string)
-	return id, ok
+	value, ok := claims[key].(string)
+	return value, ok
 }
 
 func GetKbID(c echo.Context) (string, error) {
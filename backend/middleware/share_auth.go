@@ -13,14 +13,16 @@ import (
 )
 
 type ShareAuthMiddleware struct {
-	logger    *log.Logger
-	kbUsecase *usecase.KnowledgeBaseUsecase
+	logger      *log.Logger
+	kbUsecase   *usecase.KnowledgeBaseUsecase
+	authUsecase *usecase.AuthUsecase
 }
 
-func NewShareAuthMiddleware(logger *log.Logger, kbUsecase *usecase.KnowledgeBaseUsecase) *ShareAuthMiddleware {
+func NewShareAuthMiddleware(logger *log.Logger, kbUsecase *usecase.KnowledgeBaseUsecase, authUsecase *usecase.AuthUsecase) *ShareAuthMiddleware {
 	return &ShareAuthMiddleware{
-		logger:    logger.WithModule("middleware.share_auth"),
-		kbUsecase: kbUsecase,
+		logger:      logger.WithModule("middleware.share_auth"),
+		kbUsecase:   kbUsecase,
+		authUsecase: authUsecase,
 	}
 }
 
@@ -118,6 +120,16 @@ func (h *ShareAuthMiddleware) Authorize(next echo.HandlerFunc) echo.HandlerFunc
 					Message: "Unauthorized",
 				})
 			}
+			// 读者被删除或停用后已有会话立即失效
+			loginAt, _ := sess.Values["login_at"].(int64)
+			revoked, err := h.authUsecase.IsShareSessionRevoked(c.Request().Context(), userId, loginAt)
+			if err != nil || revoked {
+				h.logger.Warn("session revoked", log.Any("user_id", userId), log.Error(err))
+				return c.JSON(http.StatusUnauthorized, domain.PWResponse{
+					Success: false,
+					Message: "Unauthorized",
+				})
+			}
 			c.Set("user_id", userId)
 			return next(c)
 		}
@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// MaxAttachmentSize is the largest media file a bot downloads for a question
+const MaxAttachmentSize = 20 << 20
+
+// DefaultAttachmentQuestion is asked when the user only sends a file
+const DefaultAttachmentQuestion = "请根据附件内容，结合知识库解答我的问题"
+
+// Attachment is an image or file the user sent along with the question
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+type attachmentsKey struct{}
+
+// WithAttachments passes the media downloaded by a bot to GetQAFun
+func WithAttachments(ctx context.Context, attachments []Attachment) context.Context {
+	return context.WithValue(ctx, attachmentsKey{}, attachments)
+}
+
+func AttachmentsFromContext(ctx context.Context) []Attachment {
+	attachments, _ := ctx.Value(attachmentsKey{}).([]Attachment)
+	return attachments
+}
+
+// DownloadAttachment fetches the media of a message, requests carry the credentials the platform needs
+func DownloadAttachment(client *http.Client, req *http.Request, name string) (*Attachment, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download %s failed: %w", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s failed: status %d", name, resp.StatusCode)
+	}
+	if resp.ContentLength > MaxAttachmentSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", name, MaxAttachmentSize)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxAttachmentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("download %s failed: %w", name, err)
+	}
+	if len(data) > MaxAttachmentSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", name, MaxAttachmentSize)
+	}
+	return &Attachment{Name: name, ContentType: resp.Header.Get("Content-Type"), Data: data}, nil
+}
@@ -7,3 +7,82 @@ import (
 )
 
 type GetQAFun func(ctx context.Context, msg string, info domain.ConversationInfo, ConversationID string) (chan string, error)
+
+// HumanReplier is implemented by bot clients that can push the reply of a human agent
+// back to a conversation, route is the ConversationInfo.ReplyRoute the client recorded
+type HumanReplier interface {
+	SendHumanReply(ctx context.Context, route map[string]string, content string) error
+}
+
+// FeedbackFun records a reader's vote on an answer message
+type FeedbackFun func(ctx context.Context, messageID string, score domain.ScoreType) error
+
+// FeedbackHook receives the answer message id when ai feedback is enabled.
+// Bots rendering their own feedback controls set it on the GetQAFun context,
+// the markdown feedback links are not appended to the answer then.
+type FeedbackHook func(messageID string)
+
+type feedbackHookKey struct{}
+
+func WithFeedbackHook(ctx context.Context, hook FeedbackHook) context.Context {
+	return context.WithValue(ctx, feedbackHookKey{}, hook)
+}
+
+func FeedbackHookFromContext(ctx context.Context) FeedbackHook {
+	hook, _ := ctx.Value(feedbackHookKey{}).(FeedbackHook)
+	return hook
+}
+
+// Source is a document the answer is based on
+type Source struct {
+	Title string
+	URL   string
+}
+
+// SourcesHook receives the documents referenced by the answer before the answer channel is closed
+type SourcesHook func(sources []Source)
+
+type sourcesHookKey struct{}
+
+func WithSourcesHook(ctx context.Context, hook SourcesHook) context.Context {
+	return context.WithValue(ctx, sourcesHookKey{}, hook)
+}
+
+func SourcesHookFromContext(ctx context.Context) SourcesHook {
+	hook, _ := ctx.Value(sourcesHookKey{}).(SourcesHook)
+	return hook
+}
+
+// ConversationKey describes where a group chat message was sent. Bots set it on the GetQAFun context
+// with an empty conversation id, the app settings decide which conversation the message continues.
+type ConversationKey struct {
+	Chat    string // group, channel or private chat
+	User    string // sender
+	Thread  string // thread or topic the message belongs to
+	ReplyTo string // message the user replied to
+	// bind is set by GetQAFun, see BindMessage
+	bind func(messageID string)
+}
+
+// SetBinder is called by GetQAFun with the function registering answer messages
+func (k *ConversationKey) SetBinder(bind func(messageID string)) {
+	k.bind = bind
+}
+
+// BindMessage registers a message sent by the bot, replies to it continue the conversation
+func (k *ConversationKey) BindMessage(messageID string) {
+	if k != nil && k.bind != nil && messageID != "" {
+		k.bind(messageID)
+	}
+}
+
+type conversationKeyKey struct{}
+
+func WithConversationKey(ctx context.Context, key *ConversationKey) context.Context {
+	return context.WithValue(ctx, conversationKeyKey{}, key)
+}
+
+func ConversationKeyFromContext(ctx context.Context) *ConversationKey {
+	key, _ := ctx.Value(conversationKeyKey{}).(*ConversationKey)
+	return key
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/chaitin/panda-wiki/domain"
 	"github.com/chaitin/panda-wiki/log"
 	"github.com/chaitin/panda-wiki/pkg/bot"
+	"github.com/chaitin/panda-wiki/pkg/bot/render"
 )
 
 type DingTalkClient struct {
@@ -232,7 +233,12 @@ func (c *DingTalkClient) OnChatBotMessageReceived(ctx context.Context, data *cha
 		convInfo.UserInfo.From = domain.MessageFromPrivate
 	}
 
-	contentCh, err := c.getQA(ctx, question, *convInfo, "")
+	// dingtalk has no threads, follow-ups are matched by the chat and the sender
+	key := &bot.ConversationKey{Chat: data.ConversationId, User: data.SenderStaffId}
+	if key.User == "" {
+		key.User = data.SenderId
+	}
+	contentCh, err := c.getQA(bot.WithConversationKey(ctx, key), question, *convInfo, "")
 	if err != nil {
 		c.logger.Error("dingtalk client failed to get answer", log.Error(err))
 		if err := c.UpdateAIStreamCard(trackID, "出错了，请稍后再试", true); err != nil {
@@ -244,13 +250,18 @@ func (c *DingTalkClient) OnChatBotMessageReceived(ctx context.Context, data *cha
 	updateTicker := time.NewTicker(1500 * time.Millisecond)
 	defer updateTicker.Stop()
 
-	ans := fmt.Sprintf("**%s**\n\n", question)
-	fullContent := fmt.Sprintf("**%s**\n\n", question)
+	// the card streams the whole answer into a single message, what exceeds the limit is cut off
+	title := fmt.Sprintf("**%s**\n\n", question)
+	var answer, lastContent string
 	for {
 		select {
 		case content, ok := <-contentCh:
 			if !ok {
-				if err := c.UpdateAIStreamCard(trackID, fullContent, true); err != nil {
+				fullContent := render.DingTalk.Preview(answer)
+				if strings.TrimSpace(fullContent) == "" {
+					fullContent = "抱歉，没有找到相关的答案"
+				}
+				if err := c.UpdateAIStreamCard(trackID, title+fullContent, true); err != nil {
 					c.logger.Error("UpdateInteractiveCard in contentCh", log.Error(err))
 					if err := c.UpdateAIStreamCard(trackID, "出错了，请稍后再试", true); err != nil {
 						c.logger.Error("UpdateInteractiveCard in contentCh failed", log.Error(err))
@@ -258,12 +269,14 @@ func (c *DingTalkClient) OnChatBotMessageReceived(ctx context.Context, data *cha
 				}
 				return []byte(""), nil
 			}
-			fullContent += content
+			answer += content
 		case <-updateTicker.C:
-			if fullContent == ans {
+			fullContent := render.DingTalk.Preview(answer)
+			if strings.TrimSpace(fullContent) == "" || fullContent == lastContent {
 				continue
 			}
-			if err := c.UpdateAIStreamCard(trackID, fullContent, false); err != nil {
+			lastContent = fullContent
+			if err := c.UpdateAIStreamCard(trackID, title+fullContent, false); err != nil {
 				c.logger.Error("UpdateInteractiveCard in ticker", log.Error(err))
 				if err := c.UpdateAIStreamCard(trackID, "出错了，请稍后再试", true); err != nil {
 					c.logger.Error("UpdateInteractiveCard in ticker failed", log.Error(err))
@@ -2,12 +2,15 @@ package discord
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/chaitin/panda-wiki/domain"
 	"github.com/chaitin/panda-wiki/log"
 	"github.com/chaitin/panda-wiki/pkg/bot"
+	"github.com/chaitin/panda-wiki/pkg/bot/render"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -74,7 +77,26 @@ func (d *DiscordClient) handleMessage(s *discordgo.Session, m *discordgo.Message
 
 	d.logger.Debug("消息来自", log.String("用户名", m.Author.Username), log.String("ID", m.Author.ID), log.String("内容", content))
 	d.logger.Debug("消息来自频道", log.String("名称", m.ChannelID))
-	qaChan, err := d.getQA(context.Background(), content, info, "")
+	key := &bot.ConversationKey{
+		Chat: m.ChannelID,
+		User: m.Author.ID,
+	}
+	// threads are channels of their own
+	if ch, err := s.State.Channel(m.ChannelID); err == nil && ch.IsThread() {
+		key.Thread = ch.ID
+	}
+	if m.MessageReference != nil {
+		key.ReplyTo = m.MessageReference.MessageID
+	}
+	ctx := bot.WithConversationKey(context.Background(), key)
+	if attachments := d.downloadAttachments(m.Attachments); len(attachments) > 0 {
+		ctx = bot.WithAttachments(ctx, attachments)
+		if strings.TrimSpace(content) == "" {
+			content = bot.DefaultAttachmentQuestion
+		}
+	}
+	info.ReplyRoute = map[string]string{"channel_id": m.ChannelID, "message_id": m.ID}
+	qaChan, err := d.getQA(ctx, content, info, "")
 	if err != nil {
 		d.logger.Error("failed to get QA", log.String("error", err.Error()))
 		return
@@ -85,14 +107,77 @@ func (d *DiscordClient) handleMessage(s *discordgo.Session, m *discordgo.Message
 		d.logger.Error("failed to send message to discord", log.String("error", err.Error()))
 		return
 	}
+	key.BindMessage(message.ID)
 	go func() {
 		buf := strings.Builder{}
 		for qa := range qaChan {
 			buf.WriteString(qa)
 		}
-		_, err := s.ChannelMessageEdit(message.ChannelID, message.ID, buf.String())
-		if err != nil {
-			d.logger.Error("failed to edit message to discord", log.String("error", err.Error()))
+		parts := render.Discord.Render(render.Answer{Markdown: buf.String()})
+		if len(parts) == 1 && strings.TrimSpace(parts[0]) == "" {
+			parts[0] = "抱歉，没有找到相关的答案"
+		}
+		// the rest of a long answer follows in messages of its own
+		for i, part := range parts {
+			if i == 0 {
+				if _, err := s.ChannelMessageEdit(message.ChannelID, message.ID, part); err != nil {
+					d.logger.Error("failed to edit message to discord", log.String("error", err.Error()))
+				}
+				continue
+			}
+			sent, err := s.ChannelMessageSend(message.ChannelID, part)
+			if err != nil {
+				d.logger.Error("failed to send message to discord", log.String("error", err.Error()))
+				continue
+			}
+			key.BindMessage(sent.ID)
 		}
 	}()
 }
+
+// SendHumanReply sends the reply of a human agent as a reply to the question
+func (d *DiscordClient) SendHumanReply(ctx context.Context, route map[string]string, content string) error {
+	if route["channel_id"] == "" {
+		return errors.New("discord reply route has no channel")
+	}
+	parts := render.Discord.Render(render.Answer{Markdown: "**人工回复**\n" + content})
+	for i, part := range parts {
+		var err error
+		if i == 0 && route["message_id"] != "" {
+			_, err = d.dg.ChannelMessageSendReply(route["channel_id"], part, &discordgo.MessageReference{
+				MessageID: route["message_id"],
+				ChannelID: route["channel_id"],
+			}, discordgo.WithContext(ctx))
+		} else {
+			_, err = d.dg.ChannelMessageSend(route["channel_id"], part, discordgo.WithContext(ctx))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// downloadAttachments fetches the files of the message from the discord cdn
+func (d *DiscordClient) downloadAttachments(files []*discordgo.MessageAttachment) []bot.Attachment {
+	attachments := make([]bot.Attachment, 0, len(files))
+	for _, f := range files {
+		if f.Size > bot.MaxAttachmentSize {
+			continue
+		}
+		req, err := http.NewRequest(http.MethodGet, f.URL, nil)
+		if err != nil {
+			continue
+		}
+		attachment, err := bot.DownloadAttachment(d.dg.Client, req, f.Filename)
+		if err != nil {
+			d.logger.Warn("failed to download discord attachment", log.String("name", f.Filename), log.Error(err))
+			continue
+		}
+		if f.ContentType != "" {
+			attachment.ContentType = f.ContentType
+		}
+		attachments = append(attachments, *attachment)
+	}
+	return attachments
+}
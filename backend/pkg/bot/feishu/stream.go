@@ -3,12 +3,16 @@ package feishu
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	lark "github.com/larksuite/oapi-sdk-go/v3"
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
 	"github.com/larksuite/oapi-sdk-go/v3/event/dispatcher"
 	larkcardkit "github.com/larksuite/oapi-sdk-go/v3/service/cardkit/v1"
 	larkcontact "github.com/larksuite/oapi-sdk-go/v3/service/contact/v3"
@@ -18,6 +22,7 @@ import (
 	"github.com/chaitin/panda-wiki/domain"
 	"github.com/chaitin/panda-wiki/log"
 	"github.com/chaitin/panda-wiki/pkg/bot"
+	"github.com/chaitin/panda-wiki/pkg/bot/render"
 )
 
 type FeishuBotLogger struct {
@@ -155,6 +160,7 @@ func (c *FeishuClient) sendQACard(ctx context.Context, receiveIdType string, rec
 			if userinfo.Avatar != nil && userinfo.Avatar.AvatarOrigin != nil {
 				convInfo.UserInfo.Avatar = *userinfo.Avatar.AvatarOrigin
 			}
+			convInfo.UserInfo.Departments = userinfo.DepartmentIds
 			c.logger.Info("get user info success", log.Any("user_info", userinfo))
 		}
 		convInfo.UserInfo.From = domain.MessageFromPrivate // 私聊
@@ -173,47 +179,183 @@ func (c *FeishuClient) sendQACard(ctx context.Context, receiveIdType string, rec
 			if userinfo.Avatar != nil && userinfo.Avatar.AvatarOrigin != nil {
 				convInfo.UserInfo.Avatar = *userinfo.Avatar.AvatarOrigin
 			}
+			convInfo.UserInfo.Departments = userinfo.DepartmentIds
 			c.logger.Info("get chat user info success", log.Any("user_info", userinfo))
 		}
 		convInfo.UserInfo.From = domain.MessageFromGroup // 群聊
 	}
 
+	convInfo.ReplyRoute = map[string]string{"receive_id_type": receiveIdType, "receive_id": receiveId}
 	answerCh, err := c.getQA(ctx, question, convInfo, "")
 	if err != nil {
 		c.logger.Error("get QA failed", log.Error(err))
 		return
 	}
+	// replies to the answer cards continue the conversation
+	key := bot.ConversationKeyFromContext(ctx)
+	key.BindMessage(larkcore.StringValue(res.Data.MessageId))
 
-	answer := ""
+	cardID := larkcore.StringValue(resp.Data.CardId)
+	answer, preview := "", ""
 	seq := 1
 	for chunk := range answerCh {
-		seq += 1
 		answer += chunk
 		// update card content streaming
-		updateReq := larkcardkit.NewContentCardElementReqBuilder().
-			CardId(*resp.Data.CardId).
-			ElementId(`markdown_1`).
-			Body(larkcardkit.NewContentCardElementReqBodyBuilder().
-				Uuid(uuid.New().String()).
-				Content(answer).
-				Sequence(seq).
-				Build()).
-			Build()
-		updateResp, err := c.client.Cardkit.V1.CardElement.Content(ctx, updateReq)
-		if err != nil {
+		content := render.Feishu.Preview(answer)
+		if strings.TrimSpace(content) == "" || content == preview {
+			continue
+		}
+		preview = content
+		seq += 1
+		if err := c.updateCard(ctx, cardID, content, seq); err != nil {
 			c.logger.Error("failed to update card", log.Error(err))
 			return
 		}
-		if !updateResp.Success() {
-			c.logger.Error("failed to update card", log.String("request_id", updateResp.RequestId()), log.Any("code_error", updateResp.CodeError))
+	}
+	parts := render.Feishu.Render(render.Answer{Markdown: answer})
+	if parts[0] != preview && strings.TrimSpace(parts[0]) != "" {
+		if err := c.updateCard(ctx, cardID, parts[0], seq+1); err != nil {
+			c.logger.Error("failed to update card", log.Error(err))
 			return
 		}
 	}
+	// the rest of a long answer follows in cards of its own
+	for _, part := range parts[1:] {
+		messageID, err := c.sendMarkdownCard(ctx, receiveIdType, receiveId, part)
+		if err != nil {
+			c.logger.Error("failed to send answer card", log.Error(err))
+			continue
+		}
+		key.BindMessage(messageID)
+	}
 	c.logger.Info("start processing QA", log.String("message_id", *res.Data.MessageId))
 }
 
+func (c *FeishuClient) updateCard(ctx context.Context, cardID, content string, seq int) error {
+	updateReq := larkcardkit.NewContentCardElementReqBuilder().
+		CardId(cardID).
+		ElementId(`markdown_1`).
+		Body(larkcardkit.NewContentCardElementReqBodyBuilder().
+			Uuid(uuid.New().String()).
+			Content(content).
+			Sequence(seq).
+			Build()).
+		Build()
+	updateResp, err := c.client.Cardkit.V1.CardElement.Content(ctx, updateReq)
+	if err != nil {
+		return err
+	}
+	if !updateResp.Success() {
+		return fmt.Errorf("update card failed: %d %s, request id %s", updateResp.Code, updateResp.Msg, updateResp.RequestId())
+	}
+	return nil
+}
+
+// SendHumanReply sends the reply of a human agent to the chat or user the question came from
+func (c *FeishuClient) SendHumanReply(ctx context.Context, route map[string]string, content string) error {
+	if route["receive_id_type"] == "" || route["receive_id"] == "" {
+		return errors.New("reply route has no receive id")
+	}
+	parts := render.Feishu.Render(render.Answer{Markdown: "**人工回复**\n" + content})
+	for _, part := range parts {
+		if _, err := c.sendMarkdownCard(ctx, route["receive_id_type"], route["receive_id"], part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendMarkdownCard sends a card with a single markdown element and returns the message id
+func (c *FeishuClient) sendMarkdownCard(ctx context.Context, receiveIdType, receiveId, content string) (string, error) {
+	card, err := json.Marshal(map[string]any{
+		"schema": "2.0",
+		"body": map[string]any{
+			"elements": []any{map[string]any{"tag": "markdown", "content": content}},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	res, err := c.client.Im.Message.Create(ctx, larkim.NewCreateMessageReqBuilder().
+		ReceiveIdType(receiveIdType).
+		Body(larkim.NewCreateMessageReqBodyBuilder().
+			MsgType("interactive").
+			ReceiveId(receiveId).
+			Content(string(card)).
+			Build()).
+		Build())
+	if err != nil {
+		return "", err
+	}
+	if !res.Success() {
+		return "", fmt.Errorf("create message failed: %d %s, request id %s", res.Code, res.Msg, res.RequestId())
+	}
+	return larkcore.StringValue(res.Data.MessageId), nil
+}
+
+// conversationKey locates the message in its chat, topics and reply chains continue their conversation
+func conversationKey(msg *larkim.EventMessage, openID string) *bot.ConversationKey {
+	return &bot.ConversationKey{
+		Chat:    larkcore.StringValue(msg.ChatId),
+		User:    openID,
+		Thread:  larkcore.StringValue(msg.ThreadId),
+		ReplyTo: larkcore.StringValue(msg.ParentId),
+	}
+}
+
 type Message struct {
-	Text string `json:"text"`
+	Text     string `json:"text"`
+	ImageKey string `json:"image_key"`
+	FileKey  string `json:"file_key"`
+	FileName string `json:"file_name"`
+}
+
+// parseMessage reads the question of text messages and downloads the media of image and file messages
+func (c *FeishuClient) parseMessage(ctx context.Context, msg *larkim.EventMessage) (string, []bot.Attachment, bool) {
+	var message Message
+	if err := json.Unmarshal([]byte(larkcore.StringValue(msg.Content)), &message); err != nil {
+		c.logger.Error("failed to unmarshal message", log.Error(err))
+		return "", nil, false
+	}
+	var fileKey, name string
+	switch larkcore.StringValue(msg.MessageType) {
+	case "text":
+		return message.Text, nil, true
+	case "image":
+		fileKey, name = message.ImageKey, "image"
+	case "file":
+		fileKey, name = message.FileKey, message.FileName
+	default:
+		return "", nil, false
+	}
+	attachment, err := c.downloadResource(ctx, larkcore.StringValue(msg.MessageId), fileKey, larkcore.StringValue(msg.MessageType), name)
+	if err != nil {
+		c.logger.Warn("failed to download message resource", log.String("name", name), log.Error(err))
+		return "", nil, false
+	}
+	return bot.DefaultAttachmentQuestion, []bot.Attachment{*attachment}, true
+}
+
+func (c *FeishuClient) downloadResource(ctx context.Context, messageID, fileKey, resourceType, name string) (*bot.Attachment, error) {
+	req := larkim.NewGetMessageResourceReqBuilder().MessageId(messageID).FileKey(fileKey).Type(resourceType).Build()
+	resp, err := c.client.Im.MessageResource.Get(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success() {
+		return nil, fmt.Errorf("get message resource failed: %d %s", resp.Code, resp.Msg)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.File, bot.MaxAttachmentSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > bot.MaxAttachmentSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", name, bot.MaxAttachmentSize)
+	}
+	if resp.FileName != "" {
+		name = resp.FileName
+	}
+	return &bot.Attachment{Name: name, Data: data}, nil
 }
 
 func (c *FeishuClient) Start() error {
@@ -229,25 +371,20 @@ func (c *FeishuClient) Start() error {
 			}
 			c.msgMap.Store(messageId, time.Now().Unix())
 			c.logger.Info("received message from feishu bot", log.String("message_id", messageId))
-			// only handle text type
-			if *event.Event.Message.MessageType != "text" {
+			question, attachments, ok := c.parseMessage(ctx, event.Event.Message)
+			if !ok {
 				return nil
 			}
+			key := conversationKey(event.Event.Message, *event.Event.Sender.SenderId.OpenId)
+			ctx = bot.WithConversationKey(ctx, key)
+			if len(attachments) > 0 {
+				ctx = bot.WithAttachments(ctx, attachments)
+			}
 			switch *event.Event.Message.ChatType {
 			case "group":
-				var message Message
-				if err := json.Unmarshal([]byte(*event.Event.Message.Content), &message); err != nil {
-					c.logger.Error("failed to unmarshal message", log.Error(err))
-					return nil
-				}
-				c.sendQACard(ctx, "chat_id", *event.Event.Message.ChatId, message.Text, *event.Event.Sender.SenderId.OpenId)
+				c.sendQACard(ctx, "chat_id", *event.Event.Message.ChatId, question, *event.Event.Sender.SenderId.OpenId)
 			case "p2p":
-				var message Message
-				if err := json.Unmarshal([]byte(*event.Event.Message.Content), &message); err != nil {
-					c.logger.Error("failed to unmarshal message", log.Error(err))
-					return nil
-				}
-				c.sendQACard(ctx, "open_id", *event.Event.Sender.SenderId.OpenId, message.Text, *event.Event.Message.ChatId)
+				c.sendQACard(ctx, "open_id", *event.Event.Sender.SenderId.OpenId, question, *event.Event.Message.ChatId)
 			default:
 				c.logger.Warn("unsupported chat type", log.String("chat_type", *event.Event.Message.ChatType))
 			}
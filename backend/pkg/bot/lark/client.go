@@ -3,13 +3,16 @@ package lark
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	lark "github.com/larksuite/oapi-sdk-go/v3"
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
 	"github.com/larksuite/oapi-sdk-go/v3/event/dispatcher"
 	larkcardkit "github.com/larksuite/oapi-sdk-go/v3/service/cardkit/v1"
 	larkcontact "github.com/larksuite/oapi-sdk-go/v3/service/contact/v3"
@@ -18,6 +21,7 @@ import (
 	"github.com/chaitin/panda-wiki/domain"
 	"github.com/chaitin/panda-wiki/log"
 	"github.com/chaitin/panda-wiki/pkg/bot"
+	"github.com/chaitin/panda-wiki/pkg/bot/render"
 )
 
 // LarkBotLogger implements Lark SDK logger interface
@@ -116,26 +120,11 @@ func (c *LarkClient) setupEventHandler() {
 			}
 			c.msgMap.Store(messageId, time.Now().Unix())
 			c.logger.Info("received message from lark bot", log.String("message_id", messageId))
-			if *event.Event.Message.MessageType != "text" {
-				return nil
-			}
 			switch *event.Event.Message.ChatType {
 			case "group":
-				var message Message
-				if err := json.Unmarshal([]byte(*event.Event.Message.Content), &message); err != nil {
-					c.logger.Error("failed to unmarshal message", log.Error(err))
-					return nil
-				}
-				// Replace mention placeholders with actual user names
-				questionText := c.replaceMentions(message.Text, event.Event.Message.Mentions)
-				go c.sendQACard(c.ctx, "chat_id", *event.Event.Message.ChatId, questionText, *event.Event.Sender.SenderId.OpenId)
+				go c.handleMessage(event.Event, "chat_id", *event.Event.Message.ChatId, *event.Event.Sender.SenderId.OpenId)
 			case "p2p":
-				var message Message
-				if err := json.Unmarshal([]byte(*event.Event.Message.Content), &message); err != nil {
-					c.logger.Error("failed to unmarshal message", log.Error(err))
-					return nil
-				}
-				go c.sendQACard(c.ctx, "open_id", *event.Event.Sender.SenderId.OpenId, message.Text, *event.Event.Message.ChatId)
+				go c.handleMessage(event.Event, "open_id", *event.Event.Sender.SenderId.OpenId, *event.Event.Message.ChatId)
 			default:
 				c.logger.Warn("unsupported chat type", log.String("chat_type", *event.Event.Message.ChatType))
 			}
@@ -218,6 +207,7 @@ func (c *LarkClient) sendQACard(ctx context.Context, receiveIdType string, recei
 			if userinfo.Avatar != nil && userinfo.Avatar.AvatarOrigin != nil {
 				convInfo.UserInfo.Avatar = *userinfo.Avatar.AvatarOrigin
 			}
+			convInfo.UserInfo.Departments = userinfo.DepartmentIds
 			c.logger.Info("get user info success", log.Any("user_info", userinfo))
 		}
 		convInfo.UserInfo.From = domain.MessageFromPrivate
@@ -235,50 +225,200 @@ func (c *LarkClient) sendQACard(ctx context.Context, receiveIdType string, recei
 			if userinfo.Avatar != nil && userinfo.Avatar.AvatarOrigin != nil {
 				convInfo.UserInfo.Avatar = *userinfo.Avatar.AvatarOrigin
 			}
+			convInfo.UserInfo.Departments = userinfo.DepartmentIds
 			c.logger.Info("get chat user info success", log.Any("user_info", userinfo))
 		}
 		convInfo.UserInfo.From = domain.MessageFromGroup
 	}
 
+	convInfo.ReplyRoute = map[string]string{"receive_id_type": receiveIdType, "receive_id": receiveId}
 	answerCh, err := c.getQA(ctx, question, convInfo, "")
 	if err != nil {
 		c.logger.Error("lark client failed to get answer", log.Error(err))
 		return
 	}
+	// replies to the answer cards continue the conversation
+	key := bot.ConversationKeyFromContext(ctx)
+	key.BindMessage(larkcore.StringValue(res.Data.MessageId))
 
-	answer := ""
+	cardID := larkcore.StringValue(resp.Data.CardId)
+	answer, preview := "", ""
 	seq := 1
 	for chunk := range answerCh {
-		seq += 1
 		answer += chunk
 		// update card content streaming
-		updateReq := larkcardkit.NewContentCardElementReqBuilder().
-			CardId(*resp.Data.CardId).
-			ElementId(`markdown_1`).
-			Body(larkcardkit.NewContentCardElementReqBodyBuilder().
-				Uuid(uuid.New().String()).
-				Content(answer).
-				Sequence(seq).
-				Build()).
-			Build()
-		updateResp, err := c.client.Cardkit.V1.CardElement.Content(ctx, updateReq)
-		if err != nil {
+		content := render.Feishu.Preview(answer)
+		if strings.TrimSpace(content) == "" || content == preview {
+			continue
+		}
+		preview = content
+		seq += 1
+		if err := c.updateCard(ctx, cardID, content, seq); err != nil {
 			c.logger.Error("failed to update card", log.Error(err))
 			return
 		}
-		if !updateResp.Success() {
-			c.logger.Error("failed to update card", log.String("request_id", updateResp.RequestId()), log.Any("code_error", updateResp.CodeError))
+	}
+	parts := render.Feishu.Render(render.Answer{Markdown: answer})
+	if parts[0] != preview && strings.TrimSpace(parts[0]) != "" {
+		if err := c.updateCard(ctx, cardID, parts[0], seq+1); err != nil {
+			c.logger.Error("failed to update card", log.Error(err))
 			return
 		}
 	}
+	// the rest of a long answer follows in cards of its own
+	for _, part := range parts[1:] {
+		messageID, err := c.sendMarkdownCard(ctx, receiveIdType, receiveId, part)
+		if err != nil {
+			c.logger.Error("failed to send answer card", log.Error(err))
+			continue
+		}
+		key.BindMessage(messageID)
+	}
 	c.logger.Info("start processing QA", log.String("message_id", *res.Data.MessageId))
 }
 
+func (c *LarkClient) updateCard(ctx context.Context, cardID, content string, seq int) error {
+	updateReq := larkcardkit.NewContentCardElementReqBuilder().
+		CardId(cardID).
+		ElementId(`markdown_1`).
+		Body(larkcardkit.NewContentCardElementReqBodyBuilder().
+			Uuid(uuid.New().String()).
+			Content(content).
+			Sequence(seq).
+			Build()).
+		Build()
+	updateResp, err := c.client.Cardkit.V1.CardElement.Content(ctx, updateReq)
+	if err != nil {
+		return err
+	}
+	if !updateResp.Success() {
+		return fmt.Errorf("update card failed: %d %s, request id %s", updateResp.Code, updateResp.Msg, updateResp.RequestId())
+	}
+	return nil
+}
+
+// SendHumanReply sends the reply of a human agent to the chat or user the question came from
+func (c *LarkClient) SendHumanReply(ctx context.Context, route map[string]string, content string) error {
+	if route["receive_id_type"] == "" || route["receive_id"] == "" {
+		return errors.New("reply route has no receive id")
+	}
+	parts := render.Feishu.Render(render.Answer{Markdown: "**人工回复**\n" + content})
+	for _, part := range parts {
+		if _, err := c.sendMarkdownCard(ctx, route["receive_id_type"], route["receive_id"], part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendMarkdownCard sends a card with a single markdown element and returns the message id
+func (c *LarkClient) sendMarkdownCard(ctx context.Context, receiveIdType, receiveId, content string) (string, error) {
+	card, err := json.Marshal(map[string]any{
+		"schema": "2.0",
+		"body": map[string]any{
+			"elements": []any{map[string]any{"tag": "markdown", "content": content}},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	res, err := c.client.Im.Message.Create(ctx, larkim.NewCreateMessageReqBuilder().
+		ReceiveIdType(receiveIdType).
+		Body(larkim.NewCreateMessageReqBodyBuilder().
+			MsgType("interactive").
+			ReceiveId(receiveId).
+			Content(string(card)).
+			Build()).
+		Build())
+	if err != nil {
+		return "", err
+	}
+	if !res.Success() {
+		return "", fmt.Errorf("create message failed: %d %s, request id %s", res.Code, res.Msg, res.RequestId())
+	}
+	return larkcore.StringValue(res.Data.MessageId), nil
+}
+
 type Message struct {
-	Text string `json:"text"`
+	Text     string `json:"text"`
+	ImageKey string `json:"image_key"`
+	FileKey  string `json:"file_key"`
+	FileName string `json:"file_name"`
+}
+
+func (c *LarkClient) handleMessage(event *larkim.P2MessageReceiveV1Data, receiveIdType, receiveId, additionalInfo string) {
+	question, attachments, ok := c.parseMessage(c.ctx, event.Message)
+	if !ok {
+		return
+	}
+	key := conversationKey(event.Message, *event.Sender.SenderId.OpenId)
+	ctx := bot.WithConversationKey(c.ctx, key)
+	if len(attachments) > 0 {
+		ctx = bot.WithAttachments(ctx, attachments)
+	}
+	c.sendQACard(ctx, receiveIdType, receiveId, question, additionalInfo)
+}
+
+// parseMessage reads the question of text messages and downloads the media of image and file messages
+func (c *LarkClient) parseMessage(ctx context.Context, msg *larkim.EventMessage) (string, []bot.Attachment, bool) {
+	var message Message
+	if err := json.Unmarshal([]byte(larkcore.StringValue(msg.Content)), &message); err != nil {
+		c.logger.Error("failed to unmarshal message", log.Error(err))
+		return "", nil, false
+	}
+	var fileKey, name string
+	switch larkcore.StringValue(msg.MessageType) {
+	case "text":
+		// Replace mention placeholders with actual user names
+		return c.replaceMentions(message.Text, msg.Mentions), nil, true
+	case "image":
+		fileKey, name = message.ImageKey, "image"
+	case "file":
+		fileKey, name = message.FileKey, message.FileName
+	default:
+		return "", nil, false
+	}
+	attachment, err := c.downloadResource(ctx, larkcore.StringValue(msg.MessageId), fileKey, larkcore.StringValue(msg.MessageType), name)
+	if err != nil {
+		c.logger.Warn("failed to download message resource", log.String("name", name), log.Error(err))
+		return "", nil, false
+	}
+	return bot.DefaultAttachmentQuestion, []bot.Attachment{*attachment}, true
+}
+
+func (c *LarkClient) downloadResource(ctx context.Context, messageID, fileKey, resourceType, name string) (*bot.Attachment, error) {
+	req := larkim.NewGetMessageResourceReqBuilder().MessageId(messageID).FileKey(fileKey).Type(resourceType).Build()
+	resp, err := c.client.Im.MessageResource.Get(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success() {
+		return nil, fmt.Errorf("get message resource failed: %d %s", resp.Code, resp.Msg)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.File, bot.MaxAttachmentSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > bot.MaxAttachmentSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", name, bot.MaxAttachmentSize)
+	}
+	if resp.FileName != "" {
+		name = resp.FileName
+	}
+	return &bot.Attachment{Name: name, Data: data}, nil
 }
 
 // replaceMentions replaces mention placeholders like @_user_1 with actual user names
+// conversationKey locates the message in its chat, topics and reply chains continue their conversation
+func conversationKey(msg *larkim.EventMessage, openID string) *bot.ConversationKey {
+	return &bot.ConversationKey{
+		Chat:    larkcore.StringValue(msg.ChatId),
+		User:    openID,
+		Thread:  larkcore.StringValue(msg.ThreadId),
+		ReplyTo: larkcore.StringValue(msg.ParentId),
+	}
+}
+
 func (c *LarkClient) replaceMentions(text string, mentions []*larkim.MentionEvent) string {
 	if len(mentions) == 0 {
 		return text
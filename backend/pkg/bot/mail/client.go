@@ -0,0 +1,351 @@
+package mail
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/bot"
+	"github.com/chaitin/panda-wiki/pkg/bot/render"
+)
+
+const (
+	defaultMailbox      = "INBOX"
+	defaultPollInterval = 60 * time.Second
+	// mails are answered by the llm, limit the number fetched in one poll
+	maxMailsPerPoll = 20
+)
+
+var ErrInvalidInboundToken = errors.New("invalid mail inbound token")
+
+// SaveReplyFun records an answer, either sent or pending approval
+type SaveReplyFun func(ctx context.Context, reply *domain.MailReply) error
+
+// MailClient answers mails received from an IMAP mailbox or pushed to HandleInbound,
+// every mail thread is one conversation
+type MailClient struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	kbID      string
+	appID     string
+	settings  domain.MailBotSettings
+	logger    *log.Logger
+	msgMap    sync.Map
+	getQA     bot.GetQAFun
+	saveReply SaveReplyFun
+}
+
+func NewMailClient(ctx context.Context, cancel context.CancelFunc, kbID, appID string, settings domain.MailBotSettings, logger *log.Logger, getQA bot.GetQAFun, saveReply SaveReplyFun) *MailClient {
+	c := &MailClient{
+		ctx:       ctx,
+		cancel:    cancel,
+		kbID:      kbID,
+		appID:     appID,
+		settings:  settings,
+		logger:    logger.WithModule("bot.mail"),
+		getQA:     getQA,
+		saveReply: saveReply,
+	}
+	go func() {
+		ticker := time.NewTicker(10 * time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				c.msgMap.Range(func(key, value any) bool {
+					// remove message id if it is older than a day
+					if time.Now().Unix()-value.(int64) > 24*60*60 {
+						c.msgMap.Delete(key)
+					}
+					return true
+				})
+			}
+		}
+	}()
+	return c
+}
+
+// Start polls the mailbox until the client is stopped, without IMAP it only waits for inbound mails
+func (c *MailClient) Start() error {
+	if !c.PollIMAP() {
+		c.logger.Info("mail bot client initialized (inbound mode)")
+		<-c.ctx.Done()
+		return nil
+	}
+	interval := defaultPollInterval
+	if c.settings.PollInterval > 0 {
+		interval = time.Duration(c.settings.PollInterval) * time.Second
+	}
+	c.logger.Info("mail bot client initialized (imap mode)", log.String("host", c.settings.IMAP.Host), log.Any("interval", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.poll(c.ctx); err != nil && c.ctx.Err() == nil {
+			c.logger.Warn("poll imap mailbox failed", log.Error(err))
+		}
+		select {
+		case <-c.ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *MailClient) Stop() {
+	c.cancel()
+}
+
+// PollIMAP reports whether mails are fetched from an IMAP mailbox
+func (c *MailClient) PollIMAP() bool {
+	return c.settings.IMAP.Host != ""
+}
+
+// poll fetches the unseen mails, they are marked as seen before being answered
+// so that a failing answer is not retried forever
+func (c *MailClient) poll(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+	client, err := dialIMAP(ctx, c.settings.IMAP)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if err := client.Login(c.settings.IMAP.Username, c.settings.IMAP.Password); err != nil {
+		return err
+	}
+	mailbox := c.settings.Mailbox
+	if mailbox == "" {
+		mailbox = defaultMailbox
+	}
+	if err := client.Select(mailbox); err != nil {
+		return err
+	}
+	uids, err := client.SearchUnseen()
+	if err != nil {
+		return err
+	}
+	if len(uids) > maxMailsPerPoll {
+		uids = uids[:maxMailsPerPoll]
+	}
+	for _, uid := range uids {
+		raw, err := client.Fetch(uid)
+		if err != nil {
+			return err
+		}
+		if err := client.MarkSeen(uid); err != nil {
+			return err
+		}
+		go c.process(raw)
+	}
+	return client.Logout()
+}
+
+// HandleInbound accepts a raw RFC 5322 message pushed by a mail server hook,
+// it returns the status code to reply with
+func (c *MailClient) HandleInbound(token string, raw []byte) int {
+	if c.settings.InboundToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(c.settings.InboundToken)) != 1 {
+		c.logger.Warn("reject inbound mail", log.Error(ErrInvalidInboundToken))
+		return http.StatusUnauthorized
+	}
+	if _, err := parseMail(raw); err != nil {
+		return http.StatusBadRequest
+	}
+	go c.process(raw)
+	return http.StatusOK
+}
+
+func (c *MailClient) fromAddress() mail.Address {
+	address := c.settings.FromAddress
+	if address == "" && strings.Contains(c.settings.SMTP.Username, "@") {
+		address = c.settings.SMTP.Username
+	}
+	return mail.Address{Name: c.settings.FromName, Address: address}
+}
+
+// ConversationID maps a mail thread to a stable conversation id
+func ConversationID(appID, threadRoot string) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte("mail:"+appID+":"+threadRoot)).String()
+}
+
+func (c *MailClient) process(raw []byte) {
+	m, err := parseMail(raw)
+	if err != nil {
+		c.logger.Warn("failed to parse mail", log.Error(err))
+		return
+	}
+	sender := m.ReplyTo
+	if sender == nil {
+		sender = m.From
+	}
+	own := c.fromAddress().Address
+	if m.Automated || sender == nil || strings.EqualFold(sender.Address, own) ||
+		(m.From != nil && strings.EqualFold(m.From.Address, own)) {
+		c.logger.Info("skip mail", log.String("message_id", m.MessageID), log.Any("automated", m.Automated))
+		return
+	}
+	if m.MessageID != "" {
+		if _, loaded := c.msgMap.LoadOrStore(m.MessageID, time.Now().Unix()); loaded {
+			return
+		}
+	}
+	question := m.question()
+	if question == "" {
+		return
+	}
+	c.logger.Info("received mail", log.String("message_id", m.MessageID), log.String("from", sender.Address))
+
+	conversationID := ConversationID(c.appID, m.threadRoot())
+	references := m.References
+	if m.MessageID != "" {
+		references = append(references, m.MessageID)
+	}
+	info := domain.ConversationInfo{
+		UserInfo: domain.UserInfo{
+			UserID:   sender.Address,
+			NickName: sender.Name,
+			Email:    sender.Address,
+			From:     domain.MessageFromPrivate,
+		},
+		ReplyRoute: map[string]string{
+			"conversation_id": conversationID,
+			"to_address":      sender.Address,
+			"to_name":         sender.Name,
+			"subject":         replySubject(m.Subject),
+			"in_reply_to":     m.MessageID,
+			"references":      strings.Join(references, " "),
+		},
+	}
+	var sources []bot.Source
+	qaCtx := bot.WithSourcesHook(c.ctx, func(s []bot.Source) {
+		sources = s
+	})
+	answerCh, err := c.getQA(qaCtx, question, info, conversationID)
+	if err != nil {
+		c.logger.Error("mail client failed to get answer", log.Error(err))
+		return
+	}
+	var sb strings.Builder
+	for chunk := range answerCh {
+		sb.WriteString(chunk)
+	}
+	answer := strings.TrimSpace(render.StripThink(sb.String()))
+	if answer == "" {
+		c.logger.Warn("empty answer, mail is not replied", log.String("message_id", m.MessageID))
+		return
+	}
+
+	now := time.Now()
+	reply := &domain.MailReply{
+		ID:             uuid.New().String(),
+		KBID:           c.kbID,
+		AppID:          c.appID,
+		ConversationID: conversationID,
+		ToAddress:      sender.Address,
+		ToName:         sender.Name,
+		Subject:        replySubject(m.Subject),
+		InReplyTo:      m.MessageID,
+		References:     domain.StringList(references),
+		Question:       question,
+		Answer:         answer + formatSources(answer, sources),
+		Status:         consts.MailReplyStatusPending,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	ctx, cancel := context.WithTimeout(c.ctx, time.Minute)
+	defer cancel()
+	if c.settings.ReplyMode != consts.MailReplyModeDraft {
+		if err := c.Send(ctx, reply); err != nil {
+			c.logger.Error("failed to send mail reply", log.String("message_id", m.MessageID), log.Error(err))
+		}
+	}
+	if c.saveReply != nil {
+		if err := c.saveReply(ctx, reply); err != nil {
+			c.logger.Error("failed to save mail reply", log.Error(err))
+		}
+	}
+}
+
+// formatSources lists the documents below the answer, unless the answer has a reference list already
+func formatSources(answer string, sources []bot.Source) string {
+	if len(sources) == 0 || render.HasReferences(answer) {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\n\n### 参考文档\n")
+	seen := make(map[string]bool, len(sources))
+	for _, source := range sources {
+		if seen[source.URL] {
+			continue
+		}
+		seen[source.URL] = true
+		fmt.Fprintf(&sb, "\n> [%d]. [%s](%s)", len(seen), source.Title, source.URL)
+	}
+	return sb.String()
+}
+
+// Send delivers the reply over SMTP and updates its status
+func (c *MailClient) Send(ctx context.Context, reply *domain.MailReply) error {
+	from := c.fromAddress()
+	msg := &outgoingMail{
+		From:       from,
+		To:         mail.Address{Name: reply.ToName, Address: reply.ToAddress},
+		Subject:    reply.Subject,
+		InReplyTo:  reply.InReplyTo,
+		References: reply.References,
+		Markdown:   reply.Answer,
+	}
+	now := time.Now()
+	reply.UpdatedAt = now
+	if err := sendMail(ctx, c.settings.SMTP, from.Address, []string{reply.ToAddress}, msg.bytes()); err != nil {
+		reply.Status = consts.MailReplyStatusFailed
+		reply.Error = err.Error()
+		return err
+	}
+	reply.Status = consts.MailReplyStatusSent
+	reply.Error = ""
+	reply.SentAt = &now
+	return nil
+}
+
+// SendHumanReply mails the reply of a human agent into the thread, it is recorded like an answer
+func (c *MailClient) SendHumanReply(ctx context.Context, route map[string]string, content string) error {
+	if route["to_address"] == "" {
+		return errors.New("mail reply route has no recipient")
+	}
+	now := time.Now()
+	reply := &domain.MailReply{
+		ID:             uuid.New().String(),
+		KBID:           c.kbID,
+		AppID:          c.appID,
+		ConversationID: route["conversation_id"],
+		ToAddress:      route["to_address"],
+		ToName:         route["to_name"],
+		Subject:        route["subject"],
+		InReplyTo:      route["in_reply_to"],
+		References:     domain.StringList(strings.Fields(route["references"])),
+		Answer:         content,
+		Status:         consts.MailReplyStatusPending,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	sendErr := c.Send(ctx, reply)
+	if c.saveReply != nil {
+		if err := c.saveReply(ctx, reply); err != nil {
+			c.logger.Error("failed to save mail reply", log.Error(err))
+		}
+	}
+	return sendErr
+}
@@ -0,0 +1,212 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+// imapClient implements the small subset of IMAP4rev1 (RFC 3501) needed to fetch unseen mails
+type imapClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+	tag  int
+}
+
+// imapResponse is an untagged response line, literals are cut out of the text and kept in order
+type imapResponse struct {
+	text     string
+	literals [][]byte
+}
+
+func dialServer(ctx context.Context, server domain.MailServerSettings, defaultPort int) (net.Conn, error) {
+	port := server.Port
+	if port == 0 {
+		port = defaultPort
+	}
+	addr := net.JoinHostPort(server.Host, strconv.Itoa(port))
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	if server.Security == consts.MailSecurityTLS {
+		return (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: server.Host}}).DialContext(ctx, "tcp", addr)
+	}
+	return dialer.DialContext(ctx, "tcp", addr)
+}
+
+func dialIMAP(ctx context.Context, server domain.MailServerSettings) (*imapClient, error) {
+	defaultPort := 143
+	if server.Security == consts.MailSecurityTLS {
+		defaultPort = 993
+	}
+	conn, err := dialServer(ctx, server, defaultPort)
+	if err != nil {
+		return nil, fmt.Errorf("dial imap server failed: %w", err)
+	}
+	c := &imapClient{conn: conn, r: bufio.NewReader(conn)}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	greeting, err := c.r.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("read imap greeting failed: %w", err)
+	}
+	if !strings.HasPrefix(greeting, "* OK") && !strings.HasPrefix(greeting, "* PREAUTH") {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected imap greeting: %s", strings.TrimSpace(greeting))
+	}
+	if server.Security == consts.MailSecurityStartTLS {
+		if _, err := c.cmd("STARTTLS"); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: server.Host})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("imap starttls failed: %w", err)
+		}
+		c.conn, c.r = tlsConn, bufio.NewReader(tlsConn)
+	}
+	return c, nil
+}
+
+func (c *imapClient) Close() error {
+	return c.conn.Close()
+}
+
+// quote renders a string argument as an IMAP quoted string
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// cmd sends a command and returns its untagged responses, a NO or BAD completion is an error
+func (c *imapClient) cmd(format string, args ...any) ([]imapResponse, error) {
+	c.tag++
+	tag := fmt.Sprintf("A%03d", c.tag)
+	command := fmt.Sprintf(format, args...)
+	if _, err := fmt.Fprintf(c.conn, "%s %s\r\n", tag, command); err != nil {
+		return nil, err
+	}
+	verb := strings.SplitN(command, " ", 2)[0]
+	var responses []imapResponse
+	for {
+		resp, err := c.readResponse()
+		if err != nil {
+			return nil, fmt.Errorf("imap %s failed: %w", verb, err)
+		}
+		if strings.HasPrefix(resp.text, tag+" ") {
+			status := strings.TrimPrefix(resp.text, tag+" ")
+			if !strings.HasPrefix(status, "OK") {
+				return nil, fmt.Errorf("imap %s failed: %s", verb, status)
+			}
+			return responses, nil
+		}
+		if strings.HasPrefix(resp.text, "* ") {
+			responses = append(responses, resp)
+		}
+	}
+}
+
+// readResponse reads one response line including the literals it announces with {n}
+func (c *imapClient) readResponse() (imapResponse, error) {
+	var resp imapResponse
+	var sb strings.Builder
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			return resp, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		n, ok := literalSize(line)
+		if !ok {
+			sb.WriteString(line)
+			resp.text = sb.String()
+			return resp, nil
+		}
+		sb.WriteString(line[:strings.LastIndexByte(line, '{')])
+		literal := make([]byte, n)
+		if _, err := io.ReadFull(c.r, literal); err != nil {
+			return resp, err
+		}
+		resp.literals = append(resp.literals, literal)
+	}
+}
+
+func literalSize(line string) (int, bool) {
+	if !strings.HasSuffix(line, "}") {
+		return 0, false
+	}
+	start := strings.LastIndexByte(line, '{')
+	if start < 0 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(line[start+1:len(line)-1], "+"))
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+func (c *imapClient) Login(username, password string) error {
+	_, err := c.cmd("LOGIN %s %s", quote(username), quote(password))
+	return err
+}
+
+func (c *imapClient) Select(mailbox string) error {
+	_, err := c.cmd("SELECT %s", quote(mailbox))
+	return err
+}
+
+// SearchUnseen returns the uids of unseen messages
+func (c *imapClient) SearchUnseen() ([]uint32, error) {
+	responses, err := c.cmd("UID SEARCH UNSEEN")
+	if err != nil {
+		return nil, err
+	}
+	var uids []uint32
+	for _, resp := range responses {
+		fields := strings.Fields(resp.text)
+		if len(fields) < 2 || !strings.EqualFold(fields[1], "SEARCH") {
+			continue
+		}
+		for _, field := range fields[2:] {
+			if uid, err := strconv.ParseUint(field, 10, 32); err == nil {
+				uids = append(uids, uint32(uid))
+			}
+		}
+	}
+	return uids, nil
+}
+
+// Fetch returns the raw message, BODY.PEEK does not set the \Seen flag
+func (c *imapClient) Fetch(uid uint32) ([]byte, error) {
+	responses, err := c.cmd("UID FETCH %d (BODY.PEEK[])", uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, resp := range responses {
+		if strings.Contains(strings.ToUpper(resp.text), "FETCH") && len(resp.literals) > 0 {
+			return resp.literals[0], nil
+		}
+	}
+	return nil, errors.New("imap fetch returned no message body")
+}
+
+func (c *imapClient) MarkSeen(uid uint32) error {
+	_, err := c.cmd(`UID STORE %d +FLAGS.SILENT (\Seen)`, uid)
+	return err
+}
+
+func (c *imapClient) Logout() error {
+	_, err := c.cmd("LOGOUT")
+	return err
+}
@@ -0,0 +1,356 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chaitin/panda-wiki/config"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/bot"
+)
+
+const questionMail = "From: Alice <alice@example.com>\r\n" +
+	"To: wiki@example.com\r\n" +
+	"Subject: =?utf-8?B?5aaC5L2V6YOo572y?=\r\n" +
+	"Message-ID: <q1@example.com>\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"=E9=9C=80=E8=A6=81=E5=93=AA=E4=BA=9B=E7=AB=AF=E5=8F=A3?\r\n"
+
+const followUpMail = "From: Alice <alice@example.com>\r\n" +
+	"To: wiki@example.com\r\n" +
+	"Subject: Re: 如何部署\r\n" +
+	"Message-ID: <q2@example.com>\r\n" +
+	"In-Reply-To: <r1@example.com>\r\n" +
+	"References: <q1@example.com> <r1@example.com>\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<div>And https?</div><div>On Mon, Bob wrote:</div><blockquote>old answer</blockquote>\r\n"
+
+func newTestLogger() *log.Logger {
+	cfg, _ := config.NewConfig()
+	return log.NewLogger(cfg)
+}
+
+type qaCall struct {
+	question       string
+	conversationID string
+	email          string
+}
+
+func stubQA(calls chan<- qaCall) bot.GetQAFun {
+	return func(ctx context.Context, msg string, info domain.ConversationInfo, conversationID string) (chan string, error) {
+		calls <- qaCall{question: msg, conversationID: conversationID, email: info.UserInfo.Email}
+		ch := make(chan string, 2)
+		go func() {
+			defer close(ch)
+			ch <- "开放 **8080** 端口"
+			if hook := bot.SourcesHookFromContext(ctx); hook != nil {
+				hook([]bot.Source{{Title: "部署指南", URL: "https://wiki.example.com/node/n1"}})
+			}
+		}()
+		return ch, nil
+	}
+}
+
+// stubIMAP serves one scripted session with a single unseen mail and records the commands
+type stubIMAP struct {
+	ln       net.Listener
+	mu       sync.Mutex
+	commands []string
+}
+
+func newStubIMAP(t *testing.T, raw string) *stubIMAP {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &stubIMAP{ln: ln}
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		fmt.Fprint(conn, "* OK IMAP4rev1 ready\r\n")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			tag, command, _ := strings.Cut(line, " ")
+			s.mu.Lock()
+			s.commands = append(s.commands, command)
+			s.mu.Unlock()
+			switch {
+			case strings.HasPrefix(command, "LOGIN"):
+				if command != `LOGIN "wiki@example.com" "p\"w"` {
+					fmt.Fprintf(conn, "%s NO bad credentials\r\n", tag)
+					continue
+				}
+			case strings.HasPrefix(command, "SELECT"):
+				fmt.Fprint(conn, "* 1 EXISTS\r\n* FLAGS (\\Seen)\r\n")
+			case command == "UID SEARCH UNSEEN":
+				fmt.Fprint(conn, "* SEARCH 7\r\n")
+			case strings.HasPrefix(command, "UID FETCH 7"):
+				fmt.Fprintf(conn, "* 1 FETCH (UID 7 BODY[] {%d}\r\n%s)\r\n", len(raw), raw)
+			case command == "LOGOUT":
+				fmt.Fprintf(conn, "* BYE\r\n%s OK LOGOUT completed\r\n", tag)
+				return
+			}
+			fmt.Fprintf(conn, "%s OK done\r\n", tag)
+		}
+	}()
+	return s
+}
+
+// stubSMTP accepts messages and hands the DATA of each to the channel
+func newStubSMTP(t *testing.T) (net.Listener, chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := make(chan string, 4)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				fmt.Fprint(conn, "220 stub ESMTP\r\n")
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					verb := strings.ToUpper(strings.Fields(line + " x")[0])
+					switch verb {
+					case "EHLO":
+						fmt.Fprint(conn, "250-stub\r\n250 8BITMIME\r\n")
+					case "DATA":
+						fmt.Fprint(conn, "354 go ahead\r\n")
+						var sb strings.Builder
+						for {
+							l, err := r.ReadString('\n')
+							if err != nil {
+								return
+							}
+							if l == ".\r\n" {
+								break
+							}
+							sb.WriteString(l)
+						}
+						messages <- sb.String()
+						fmt.Fprint(conn, "250 queued\r\n")
+					case "QUIT":
+						fmt.Fprint(conn, "221 bye\r\n")
+						return
+					default:
+						fmt.Fprint(conn, "250 ok\r\n")
+					}
+				}
+			}(conn)
+		}
+	}()
+	return ln, messages
+}
+
+func serverSettings(t *testing.T, ln net.Listener) domain.MailServerSettings {
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return domain.MailServerSettings{Host: host, Port: p, Security: consts.MailSecurityNone}
+}
+
+func TestParseMail(t *testing.T) {
+	m, err := parseMail([]byte(followUpMail))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.threadRoot() != "<q1@example.com>" {
+		t.Fatalf("thread root = %q", m.threadRoot())
+	}
+	if q := m.question(); q != "And https?" {
+		t.Fatalf("question = %q", q)
+	}
+	if got := replySubject("RE: 回复: 如何部署"); got != "Re: 如何部署" {
+		t.Fatalf("reply subject = %q", got)
+	}
+
+	auto, err := parseMail([]byte(strings.Replace(questionMail, "Message-ID", "Auto-Submitted: auto-replied\r\nMessage-ID", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !auto.Automated {
+		t.Fatal("auto replies must be flagged")
+	}
+}
+
+func TestPollAndAutoReply(t *testing.T) {
+	imap := newStubIMAP(t, questionMail)
+	defer imap.ln.Close()
+	smtpLn, messages := newStubSMTP(t)
+	defer smtpLn.Close()
+
+	imapSettings := serverSettings(t, imap.ln)
+	imapSettings.Username, imapSettings.Password = "wiki@example.com", `p"w`
+	settings := domain.MailBotSettings{
+		IMAP:        imapSettings,
+		SMTP:        serverSettings(t, smtpLn),
+		FromAddress: "wiki@example.com",
+		FromName:    "PandaWiki",
+		ReplyMode:   consts.MailReplyModeAuto,
+	}
+	calls := make(chan qaCall, 1)
+	saved := make(chan *domain.MailReply, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := NewMailClient(ctx, cancel, "kb1", "app1", settings, newTestLogger(), stubQA(calls),
+		func(ctx context.Context, reply *domain.MailReply) error {
+			saved <- reply
+			return nil
+		})
+
+	if err := c.poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	imap.mu.Lock()
+	commands := strings.Join(imap.commands, "\n")
+	imap.mu.Unlock()
+	if !strings.Contains(commands, `UID STORE 7 +FLAGS.SILENT (\Seen)`) {
+		t.Fatalf("mail was not marked seen:\n%s", commands)
+	}
+
+	call := <-calls
+	if call.question != "如何部署\n\n需要哪些端口?" || call.email != "alice@example.com" {
+		t.Fatalf("unexpected question %+v", call)
+	}
+	if call.conversationID != ConversationID("app1", "<q1@example.com>") {
+		t.Fatalf("conversation id = %q", call.conversationID)
+	}
+
+	var msg string
+	select {
+	case msg = <-messages:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reply was sent")
+	}
+	for _, want := range []string{
+		"In-Reply-To: <q1@example.com>",
+		"References: <q1@example.com>",
+		"Subject: =?utf-8?q?Re:_",
+		"Auto-Submitted: auto-replied",
+		"multipart/alternative",
+		"https://wiki.example.com/node/n1",
+		"<b>8080</b>",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("reply misses %q:\n%s", want, msg)
+		}
+	}
+
+	reply := <-saved
+	if reply.Status != consts.MailReplyStatusSent || reply.SentAt == nil || reply.ToAddress != "alice@example.com" {
+		t.Fatalf("unexpected saved reply %+v", reply)
+	}
+}
+
+func TestInboundDraft(t *testing.T) {
+	settings := domain.MailBotSettings{
+		InboundToken: "secret",
+		// drafts are never delivered, the server is unreachable on purpose
+		SMTP:        domain.MailServerSettings{Host: "127.0.0.1", Port: 1},
+		FromAddress: "wiki@example.com",
+		ReplyMode:   consts.MailReplyModeDraft,
+	}
+	calls := make(chan qaCall, 2)
+	saved := make(chan *domain.MailReply, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := NewMailClient(ctx, cancel, "kb1", "app1", settings, newTestLogger(), stubQA(calls),
+		func(ctx context.Context, reply *domain.MailReply) error {
+			saved <- reply
+			return nil
+		})
+
+	if status := c.HandleInbound("wrong", []byte(followUpMail)); status != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", status)
+	}
+	if status := c.HandleInbound("secret", []byte(followUpMail)); status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	// a redelivered mail is answered once
+	c.HandleInbound("secret", []byte(followUpMail))
+
+	call := <-calls
+	if call.conversationID != ConversationID("app1", "<q1@example.com>") {
+		t.Fatalf("follow-up is not in the thread conversation: %q", call.conversationID)
+	}
+	reply := <-saved
+	if reply.Status != consts.MailReplyStatusPending || reply.SentAt != nil {
+		t.Fatalf("draft must stay pending, got %+v", reply)
+	}
+	if reply.InReplyTo != "<q2@example.com>" || len(reply.References) != 3 {
+		t.Fatalf("unexpected threading headers %q %v", reply.InReplyTo, reply.References)
+	}
+	if !strings.Contains(reply.Answer, "[1]. [部署指南](https://wiki.example.com/node/n1)") {
+		t.Fatalf("answer misses sources: %q", reply.Answer)
+	}
+	select {
+	case <-calls:
+		t.Fatal("duplicate mail was answered twice")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestSendHumanReply(t *testing.T) {
+	smtpLn, messages := newStubSMTP(t)
+	defer smtpLn.Close()
+
+	saved := make(chan *domain.MailReply, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := NewMailClient(ctx, cancel, "kb1", "app1", domain.MailBotSettings{
+		SMTP:        serverSettings(t, smtpLn),
+		FromAddress: "wiki@example.com",
+	}, newTestLogger(), nil, func(ctx context.Context, reply *domain.MailReply) error {
+		saved <- reply
+		return nil
+	})
+
+	err := c.SendHumanReply(ctx, map[string]string{
+		"conversation_id": "c1",
+		"to_address":      "alice@example.com",
+		"subject":         "Re: 如何部署",
+		"in_reply_to":     "<q2@example.com>",
+		"references":      "<q1@example.com> <q2@example.com>",
+	}, "请开放 443 端口")
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := <-messages
+	if !strings.Contains(msg, "References: <q1@example.com> <q2@example.com>") || !strings.Contains(msg, "In-Reply-To: <q2@example.com>") {
+		t.Fatalf("human reply is not threaded:\n%s", msg)
+	}
+	if reply := <-saved; reply.Status != consts.MailReplyStatusSent || reply.ConversationID != "c1" {
+		t.Fatalf("unexpected saved reply %+v", reply)
+	}
+}
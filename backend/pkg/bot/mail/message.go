@@ -0,0 +1,296 @@
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/encoding/htmlindex"
+
+	"github.com/chaitin/panda-wiki/pkg/bot/render"
+)
+
+var (
+	wordDecoder = &mime.WordDecoder{CharsetReader: charsetReader}
+
+	replyPrefixRe = regexp.MustCompile(`(?i)^\s*((re|fw|fwd|回复|答复|转发)\s*[:：]\s*)+`)
+	// the first line of a quoted reply, as written by common mail clients
+	quoteHeaderRe = regexp.MustCompile(`(?i)^(on\s.+wrote:|在.+写道[:：]|-{2,}\s*(original message|原始邮件)\s*-{2,}|_{10,})\s*$`)
+	htmlBlockRe   = regexp.MustCompile(`(?i)<\s*(br|/p|/div|/li|/tr|/h[1-6])[^>]*>`)
+	htmlDropRe    = regexp.MustCompile(`(?is)<(script|style|head)[^>]*>.*?</(script|style|head)>`)
+	htmlTagRe     = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankLinesRe  = regexp.MustCompile(`\n{3,}`)
+)
+
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, err
+	}
+	return enc.NewDecoder().Reader(input), nil
+}
+
+// inboundMail is the part of a received message the bot works with
+type inboundMail struct {
+	MessageID  string
+	InReplyTo  string
+	References []string
+	From       *mail.Address
+	ReplyTo    *mail.Address
+	Subject    string
+	Text       string
+	// auto replies, bounces and mailing list traffic are never answered
+	Automated bool
+}
+
+// threadRoot identifies the mail thread, it is the first message of the References chain
+func (m *inboundMail) threadRoot() string {
+	if len(m.References) > 0 {
+		return m.References[0]
+	}
+	if m.InReplyTo != "" {
+		return m.InReplyTo
+	}
+	return m.MessageID
+}
+
+// question is the text the answer is generated for, quoted history is already stripped
+func (m *inboundMail) question() string {
+	text := strings.TrimSpace(m.Text)
+	subject := strings.TrimSpace(replyPrefixRe.ReplaceAllString(m.Subject, ""))
+	// follow-ups keep the subject of the thread, the body carries the question
+	if m.InReplyTo != "" || subject == "" {
+		if text == "" {
+			return subject
+		}
+		return text
+	}
+	if text == "" || strings.Contains(text, subject) {
+		return firstNonEmpty(text, subject)
+	}
+	return subject + "\n\n" + text
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func msgIDList(value string) []string {
+	var ids []string
+	for _, field := range strings.Fields(value) {
+		if strings.HasPrefix(field, "<") && strings.HasSuffix(field, ">") {
+			ids = append(ids, field)
+		}
+	}
+	return ids
+}
+
+func parseAddress(value string) *mail.Address {
+	if value == "" {
+		return nil
+	}
+	parser := &mail.AddressParser{WordDecoder: wordDecoder}
+	addr, err := parser.Parse(value)
+	if err != nil {
+		return nil
+	}
+	return addr
+}
+
+func parseMail(raw []byte) (*inboundMail, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("parse mail failed: %w", err)
+	}
+	header := msg.Header
+	m := &inboundMail{
+		MessageID:  strings.TrimSpace(header.Get("Message-Id")),
+		InReplyTo:  firstNonEmpty(msgIDList(header.Get("In-Reply-To"))...),
+		References: msgIDList(header.Get("References")),
+		From:       parseAddress(header.Get("From")),
+		ReplyTo:    parseAddress(header.Get("Reply-To")),
+	}
+	if subject, err := wordDecoder.DecodeHeader(header.Get("Subject")); err == nil {
+		m.Subject = subject
+	} else {
+		m.Subject = header.Get("Subject")
+	}
+	autoSubmitted := strings.ToLower(header.Get("Auto-Submitted"))
+	precedence := strings.ToLower(header.Get("Precedence"))
+	m.Automated = (autoSubmitted != "" && autoSubmitted != "no") ||
+		precedence == "bulk" || precedence == "junk" || precedence == "list" ||
+		header.Get("List-Id") != "" || header.Get("X-Autoreply") != "" ||
+		(m.From != nil && strings.HasPrefix(strings.ToLower(m.From.Address), "mailer-daemon@"))
+
+	text, isHTML, err := extractText(header, msg.Body)
+	if err != nil {
+		return nil, err
+	}
+	if isHTML {
+		text = htmlToText(text)
+	}
+	m.Text = stripQuoted(text)
+	return m, nil
+}
+
+// headerGetter is implemented by the headers of both net/mail and mime/multipart
+type headerGetter interface {
+	Get(key string) string
+}
+
+// extractText returns the text/plain body, or the text/html one when there is no plain part
+func extractText(header headerGetter, body io.Reader) (string, bool, error) {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		var htmlText string
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", false, fmt.Errorf("read mail part failed: %w", err)
+			}
+			if strings.HasPrefix(strings.ToLower(part.Header.Get("Content-Disposition")), "attachment") {
+				continue
+			}
+			text, isHTML, err := extractText(part.Header, part)
+			if err != nil {
+				return "", false, err
+			}
+			if !isHTML && text != "" {
+				return text, false, nil
+			}
+			if isHTML && htmlText == "" {
+				htmlText = text
+			}
+		}
+		return htmlText, htmlText != "", nil
+	}
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return "", false, nil
+	}
+	var reader io.Reader = body
+	switch strings.ToLower(header.Get("Content-Transfer-Encoding")) {
+	case "quoted-printable":
+		reader = quotedprintable.NewReader(reader)
+	case "base64":
+		// line breaks are ignored by the decoder
+		reader = base64.NewDecoder(base64.StdEncoding, reader)
+	}
+	if charset := strings.ToLower(params["charset"]); charset != "" && charset != "utf-8" && charset != "us-ascii" {
+		if decoded, err := charsetReader(charset, reader); err == nil {
+			reader = decoded
+		}
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", false, fmt.Errorf("decode mail body failed: %w", err)
+	}
+	return strings.ReplaceAll(string(data), "\r\n", "\n"), mediaType == "text/html", nil
+}
+
+func htmlToText(s string) string {
+	s = htmlDropRe.ReplaceAllString(s, "")
+	s = htmlBlockRe.ReplaceAllString(s, "\n")
+	s = html.UnescapeString(htmlTagRe.ReplaceAllString(s, ""))
+	return strings.TrimSpace(blankLinesRe.ReplaceAllString(s, "\n\n"))
+}
+
+// stripQuoted removes the quoted history and the signature of a reply
+func stripQuoted(text string) string {
+	lines := strings.Split(text, "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if quoteHeaderRe.MatchString(trimmed) || line == "-- " {
+			break
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+		out = append(out, strings.TrimRight(line, " \t"))
+	}
+	return strings.TrimSpace(blankLinesRe.ReplaceAllString(strings.Join(out, "\n"), "\n\n"))
+}
+
+// outgoingMail is a reply ready to be sent
+type outgoingMail struct {
+	From       mail.Address
+	To         mail.Address
+	Subject    string
+	InReplyTo  string
+	References []string
+	Markdown   string
+}
+
+func replySubject(subject string) string {
+	subject = strings.TrimSpace(replyPrefixRe.ReplaceAllString(subject, ""))
+	if subject == "" {
+		return "Re: 您的问题"
+	}
+	return "Re: " + subject
+}
+
+func writeQuotedPrintable(buf *bytes.Buffer, s string) {
+	w := quotedprintable.NewWriter(buf)
+	_, _ = w.Write([]byte(strings.ReplaceAll(s, "\n", "\r\n")))
+	_ = w.Close()
+	buf.WriteString("\r\n")
+}
+
+// bytes renders the reply as a multipart/alternative message with a text and a html part
+func (m *outgoingMail) bytes() []byte {
+	domainPart := "pandawiki.local"
+	if at := strings.LastIndexByte(m.From.Address, '@'); at >= 0 {
+		domainPart = m.From.Address[at+1:]
+	}
+	boundary := "pandawiki-" + uuid.New().String()
+
+	var buf bytes.Buffer
+	header := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+		}
+	}
+	header("From", m.From.String())
+	header("To", m.To.String())
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", fmt.Sprintf("<%s@%s>", uuid.New().String(), domainPart))
+	header("In-Reply-To", m.InReplyTo)
+	header("References", strings.Join(m.References, " "))
+	// keep other auto responders from answering the answer
+	header("Auto-Submitted", "auto-replied")
+	header("X-Auto-Response-Suppress", "All")
+	header("MIME-Version", "1.0")
+	header("Content-Type", fmt.Sprintf(`multipart/alternative; boundary="%s"`, boundary))
+	buf.WriteString("\r\n")
+
+	fmt.Fprintf(&buf, "--%s\r\nContent-Type: text/plain; charset=utf-8\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n", boundary)
+	answer := render.Answer{Markdown: m.Markdown}
+	writeQuotedPrintable(&buf, strings.Join(render.PlainText.Render(answer), "\n"))
+	fmt.Fprintf(&buf, "--%s\r\nContent-Type: text/html; charset=utf-8\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n", boundary)
+	writeQuotedPrintable(&buf, `<!DOCTYPE html><html><head><meta charset="utf-8"></head><body>`+strings.Join(render.Mail.Render(answer), "\n")+"</body></html>")
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+	return buf.Bytes()
+}
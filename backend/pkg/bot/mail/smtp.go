@@ -0,0 +1,66 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/smtp"
+
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+// sendMail delivers msg to the recipients through the configured submission server
+func sendMail(ctx context.Context, server domain.MailServerSettings, from string, to []string, msg []byte) error {
+	defaultPort := 25
+	switch server.Security {
+	case consts.MailSecurityTLS:
+		defaultPort = 465
+	case consts.MailSecurityStartTLS:
+		defaultPort = 587
+	}
+	conn, err := dialServer(ctx, server, defaultPort)
+	if err != nil {
+		return fmt.Errorf("dial smtp server failed: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, server.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake failed: %w", err)
+	}
+	defer c.Close()
+
+	if server.Security == consts.MailSecurityStartTLS {
+		if err := c.StartTLS(&tls.Config{ServerName: server.Host}); err != nil {
+			return fmt.Errorf("smtp starttls failed: %w", err)
+		}
+	}
+	if server.Username != "" {
+		// PlainAuth refuses to send the password over an unencrypted connection to a remote host
+		if err := c.Auth(smtp.PlainAuth("", server.Username, server.Password, server.Host)); err != nil {
+			return fmt.Errorf("smtp auth failed: %w", err)
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("smtp RCPT TO %s failed: %w", rcpt, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("smtp write message failed: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	return c.Quit()
+}
@@ -0,0 +1,122 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// CardVersion is the Adaptive Card schema the elements of Card need
+const CardVersion = "1.5"
+
+// TextBlock returns an Adaptive Card text block with extra properties
+func TextBlock(text string, props map[string]any) map[string]any {
+	block := map[string]any{"type": "TextBlock", "text": text, "wrap": true}
+	for k, v := range props {
+		block[k] = v
+	}
+	return block
+}
+
+// Card renders the answer as the body elements of an Adaptive Card. Text blocks only display
+// emphasis, lists and links, code blocks, tables and the references get elements of their own.
+// Elements beyond the limit of the channel are cut off.
+func (c Channel) Card(answer Answer) []any {
+	_, md, thinking := splitThink(answer.Markdown)
+	if thinking {
+		return nil
+	}
+	c.Format = FormatMarkdown
+	blocks := c.lower(withSources(parse(md), answer.Sources))
+	r := markdown{}
+
+	var (
+		body  []any
+		lines []string
+		size  int
+	)
+	flush := func() {
+		for len(lines) > 0 && lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		if len(lines) > 0 {
+			body = append(body, TextBlock(strings.Join(lines, "\n"), nil))
+		}
+		lines = nil
+	}
+	for _, b := range blocks {
+		s := r.block(b)
+		if b.kind == blockCode {
+			s = b.code
+		}
+		if n := utf8.RuneCountInString(s); c.Limit > 0 && size+n > c.Limit {
+			lines = append(lines, "...")
+			break
+		} else {
+			size += n
+		}
+		switch b.kind {
+		case blockCode:
+			flush()
+			body = append(body, map[string]any{
+				"type":  "Container",
+				"style": "emphasis",
+				"items": []any{TextBlock(b.code, map[string]any{"fontType": "Monospace"})},
+			})
+		case blockTable:
+			flush()
+			body = append(body, r.cardTable(b))
+		case blockReferences:
+			flush()
+			refs := make([]string, 0, len(b.refs))
+			for i, ref := range b.refs {
+				refs = append(refs, fmt.Sprintf("%d. [%s](%s)", i+1, ref.Title, ref.URL))
+			}
+			body = append(body,
+				TextBlock(referencesTitle, map[string]any{"weight": "Bolder", "separator": true, "spacing": "Medium"}),
+				TextBlock(strings.Join(refs, "\r"), map[string]any{"size": "Small"}),
+			)
+		case blockBlank:
+			if len(lines) > 0 {
+				lines = append(lines, "")
+			}
+		default:
+			lines = append(lines, s)
+		}
+	}
+	flush()
+	return body
+}
+
+func (r markdown) cardTable(b *block) map[string]any {
+	columns := make([]any, len(b.header))
+	for i := range columns {
+		columns[i] = map[string]any{"width": 1}
+	}
+	row := func(cells [][]inline, header bool) map[string]any {
+		// every row has a cell per column
+		cells = append(cells, make([][]inline, len(columns))...)[:len(columns)]
+		items := make([]any, 0, len(cells))
+		for _, cell := range cells {
+			props := map[string]any(nil)
+			if header {
+				props = map[string]any{"weight": "Bolder"}
+			}
+			items = append(items, map[string]any{
+				"type":  "TableCell",
+				"items": []any{TextBlock(r.inline(cell), props)},
+			})
+		}
+		return map[string]any{"type": "TableRow", "cells": items}
+	}
+	rows := []any{row(b.header, true)}
+	for _, cells := range b.rows {
+		rows = append(rows, row(cells, false))
+	}
+	return map[string]any{
+		"type":             "Table",
+		"columns":          columns,
+		"rows":             rows,
+		"firstRowAsHeader": true,
+	}
+}
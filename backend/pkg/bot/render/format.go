@@ -0,0 +1,312 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// renderer writes blocks in the markup of one format
+type renderer interface {
+	block(b *block) string
+}
+
+func rendererOf(f Format) renderer {
+	switch f {
+	case FormatMarkdown:
+		return markdown{}
+	case FormatMarkdownV2:
+		return markdownV2{}
+	case FormatHTML:
+		return htmlFormat{}
+	default:
+		return text{}
+	}
+}
+
+const referencesTitle = "参考文档"
+
+// text drops all markup, links are followed by their url
+type text struct{}
+
+func (r text) inline(nodes []inline) string {
+	var sb strings.Builder
+	for _, n := range nodes {
+		switch n.kind {
+		case inlineText, inlineCode:
+			sb.WriteString(n.text)
+		case inlineCitation:
+			sb.WriteString("[" + n.text + "]")
+		case inlineLink, inlineImage:
+			label := r.inline(n.children)
+			if n.kind == inlineImage {
+				label = n.text
+			}
+			if label == "" || label == n.url {
+				sb.WriteString(n.url)
+			} else {
+				sb.WriteString(label + " (" + n.url + ")")
+			}
+		default:
+			sb.WriteString(r.inline(n.children))
+		}
+	}
+	return sb.String()
+}
+
+func (r text) block(b *block) string {
+	switch b.kind {
+	case blockListItem:
+		if b.marker != "" {
+			return b.indent + b.marker + ". " + r.inline(b.text)
+		}
+		return b.indent + "• " + r.inline(b.text)
+	case blockQuote:
+		return "> " + r.inline(b.text)
+	case blockCode:
+		return b.code
+	case blockRule:
+		return "─────────"
+	case blockBlank:
+		return ""
+	case blockReferences:
+		lines := []string{referencesTitle + "："}
+		for i, ref := range b.refs {
+			lines = append(lines, fmt.Sprintf("[%d] %s %s", i+1, ref.Title, ref.URL))
+		}
+		return strings.Join(lines, "\n")
+	default:
+		return r.inline(b.text)
+	}
+}
+
+// markdown is the common dialect of chat platforms, text keeps the escapes of the llm
+type markdown struct{}
+
+func codeSpan(code string) string {
+	if strings.Contains(code, "`") {
+		return "`` " + code + " ``"
+	}
+	return "`" + code + "`"
+}
+
+func (r markdown) inline(nodes []inline) string {
+	var sb strings.Builder
+	for _, n := range nodes {
+		switch n.kind {
+		case inlineText:
+			sb.WriteString(n.raw)
+		case inlineBold:
+			sb.WriteString("**" + r.inline(n.children) + "**")
+		case inlineItalic:
+			sb.WriteString("*" + r.inline(n.children) + "*")
+		case inlineStrike:
+			sb.WriteString("~~" + r.inline(n.children) + "~~")
+		case inlineCode:
+			sb.WriteString(codeSpan(n.text))
+		case inlineLink:
+			sb.WriteString("[" + r.inline(n.children) + "](" + n.url + ")")
+		case inlineImage:
+			sb.WriteString("![" + n.text + "](" + n.url + ")")
+		case inlineCitation:
+			sb.WriteString("[[" + n.text + "](" + n.url + ")]")
+		}
+	}
+	return sb.String()
+}
+
+func (r markdown) row(cells [][]inline) string {
+	out := make([]string, len(cells))
+	for i, cell := range cells {
+		out[i] = strings.ReplaceAll(r.inline(cell), "|", "\\|")
+	}
+	return "| " + strings.Join(out, " | ") + " |"
+}
+
+func (r markdown) block(b *block) string {
+	switch b.kind {
+	case blockHeading:
+		return strings.Repeat("#", b.level) + " " + r.inline(b.text)
+	case blockListItem:
+		if b.marker != "" {
+			return b.indent + b.marker + ". " + r.inline(b.text)
+		}
+		return b.indent + "- " + r.inline(b.text)
+	case blockQuote:
+		return "> " + r.inline(b.text)
+	case blockCode:
+		return "```" + b.lang + "\n" + b.code + "\n```"
+	case blockTable:
+		lines := []string{r.row(b.header), "|" + strings.Repeat(" --- |", len(b.header))}
+		for _, row := range b.rows {
+			lines = append(lines, r.row(row))
+		}
+		return strings.Join(lines, "\n")
+	case blockRule:
+		return "---"
+	case blockBlank:
+		return ""
+	case blockReferences:
+		lines := []string{"**" + referencesTitle + "**"}
+		for i, ref := range b.refs {
+			lines = append(lines, fmt.Sprintf("%d. [%s](%s)", i+1, ref.Title, ref.URL))
+		}
+		return strings.Join(lines, "\n")
+	default:
+		return r.inline(b.text)
+	}
+}
+
+// characters that must be escaped everywhere outside of code entities in MarkdownV2
+const specialChars = "_*[]()~`>#+-=|{}.!\\"
+
+func escapeV2(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for _, r := range s {
+		if r < 128 && strings.ContainsRune(specialChars, r) {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// escapeV2Code escapes the content of pre and code entities
+func escapeV2Code(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "`", "\\`").Replace(s)
+}
+
+// escapeV2URL escapes the url part of inline links
+func escapeV2URL(s string) string {
+	return strings.NewReplacer("\\", "\\\\", ")", "\\)").Replace(s)
+}
+
+// markdownV2 is Telegram's dialect, every special character outside of markup is escaped
+type markdownV2 struct{}
+
+func (r markdownV2) inline(nodes []inline) string {
+	var sb strings.Builder
+	for _, n := range nodes {
+		switch n.kind {
+		case inlineText:
+			sb.WriteString(escapeV2(n.text))
+		case inlineBold:
+			sb.WriteString("*" + r.inline(n.children) + "*")
+		case inlineItalic:
+			sb.WriteString("_" + r.inline(n.children) + "_")
+		case inlineStrike:
+			sb.WriteString("~" + r.inline(n.children) + "~")
+		case inlineCode:
+			sb.WriteString("`" + escapeV2Code(n.text) + "`")
+		case inlineLink:
+			sb.WriteString("[" + r.inline(n.children) + "](" + escapeV2URL(n.url) + ")")
+		case inlineImage:
+			sb.WriteString("[" + escapeV2(n.text) + "](" + escapeV2URL(n.url) + ")")
+		case inlineCitation:
+			sb.WriteString("[\\[" + n.text + "\\]](" + escapeV2URL(n.url) + ")")
+		}
+	}
+	return sb.String()
+}
+
+func (r markdownV2) block(b *block) string {
+	switch b.kind {
+	case blockListItem:
+		if b.marker != "" {
+			return b.indent + b.marker + "\\. " + r.inline(b.text)
+		}
+		return b.indent + "• " + r.inline(b.text)
+	case blockQuote:
+		return ">" + r.inline(b.text)
+	case blockCode:
+		return "```" + b.lang + "\n" + escapeV2Code(b.code) + "\n```"
+	case blockRule:
+		return "——————"
+	case blockBlank:
+		return ""
+	case blockReferences:
+		lines := []string{"*" + referencesTitle + "*"}
+		for i, ref := range b.refs {
+			lines = append(lines, fmt.Sprintf("%d\\. [%s](%s)", i+1, escapeV2(ref.Title), escapeV2URL(ref.URL)))
+		}
+		return strings.Join(lines, "\n")
+	default:
+		return r.inline(b.text)
+	}
+}
+
+// htmlFormat renders the tags mail clients display, every line is a block of its own
+type htmlFormat struct{}
+
+func (r htmlFormat) inline(nodes []inline) string {
+	var sb strings.Builder
+	for _, n := range nodes {
+		switch n.kind {
+		case inlineText:
+			sb.WriteString(html.EscapeString(n.text))
+		case inlineBold:
+			sb.WriteString("<b>" + r.inline(n.children) + "</b>")
+		case inlineItalic:
+			sb.WriteString("<i>" + r.inline(n.children) + "</i>")
+		case inlineStrike:
+			sb.WriteString("<s>" + r.inline(n.children) + "</s>")
+		case inlineCode:
+			sb.WriteString("<code>" + html.EscapeString(n.text) + "</code>")
+		case inlineLink:
+			sb.WriteString(`<a href="` + html.EscapeString(n.url) + `">` + r.inline(n.children) + "</a>")
+		case inlineImage:
+			sb.WriteString(`<img src="` + html.EscapeString(n.url) + `" alt="` + html.EscapeString(n.text) + `" style="max-width:100%">`)
+		case inlineCitation:
+			sb.WriteString(`<a href="` + html.EscapeString(n.url) + `">[` + n.text + "]</a>")
+		}
+	}
+	return sb.String()
+}
+
+func (r htmlFormat) block(b *block) string {
+	switch b.kind {
+	case blockHeading:
+		return fmt.Sprintf("<h%d>%s</h%d>", b.level, r.inline(b.text), b.level)
+	case blockListItem:
+		marker := "•"
+		if b.marker != "" {
+			marker = b.marker + "."
+		}
+		return fmt.Sprintf(`<div style="padding-left:%dem">%s %s</div>`, len(b.indent)/2+1, marker, r.inline(b.text))
+	case blockQuote:
+		return `<blockquote style="margin:0;padding-left:1em;border-left:3px solid #ddd;color:#666">` + r.inline(b.text) + "</blockquote>"
+	case blockCode:
+		return `<pre style="background:#f6f8fa;padding:8px;overflow:auto"><code>` + html.EscapeString(b.code) + "</code></pre>"
+	case blockTable:
+		var sb strings.Builder
+		sb.WriteString(`<table style="border-collapse:collapse" border="1" cellpadding="4"><tr>`)
+		for _, cell := range b.header {
+			sb.WriteString("<th>" + r.inline(cell) + "</th>")
+		}
+		sb.WriteString("</tr>")
+		for _, row := range b.rows {
+			sb.WriteString("<tr>")
+			for _, cell := range row {
+				sb.WriteString("<td>" + r.inline(cell) + "</td>")
+			}
+			sb.WriteString("</tr>")
+		}
+		sb.WriteString("</table>")
+		return sb.String()
+	case blockRule:
+		return "<hr>"
+	case blockBlank:
+		return "<br>"
+	case blockReferences:
+		var sb strings.Builder
+		sb.WriteString("<p><b>" + referencesTitle + "</b></p><ol>")
+		for _, ref := range b.refs {
+			sb.WriteString(`<li><a href="` + html.EscapeString(ref.URL) + `">` + html.EscapeString(ref.Title) + "</a></li>")
+		}
+		sb.WriteString("</ol>")
+		return sb.String()
+	default:
+		return "<div>" + r.inline(b.text) + "</div>"
+	}
+}
@@ -0,0 +1,448 @@
+package render
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/chaitin/panda-wiki/pkg/bot"
+)
+
+type blockKind int
+
+const (
+	blockParagraph blockKind = iota
+	blockBlank
+	blockHeading
+	blockListItem
+	blockQuote
+	blockCode
+	blockTable
+	blockRule
+	blockReferences
+)
+
+// block is one line of the answer, or a code block, table or reference list spanning several lines
+type block struct {
+	kind   blockKind
+	level  int    // heading level
+	indent string // leading spaces of nested list items
+	marker string // number of ordered list items, empty for bullets
+	text   []inline
+	lang   string
+	code   string
+	header [][]inline
+	rows   [][][]inline
+	refs   []bot.Source
+}
+
+type inlineKind int
+
+const (
+	inlineText inlineKind = iota
+	inlineBold
+	inlineItalic
+	inlineStrike
+	inlineCode
+	inlineLink
+	inlineImage
+	inlineCitation
+)
+
+type inline struct {
+	kind inlineKind
+	// text of text and code spans, alt of images and number of citations
+	text string
+	// source of text spans, markdown output keeps the escapes of the llm
+	raw      string
+	url      string
+	children []inline
+}
+
+var (
+	headingRe      = regexp.MustCompile(`^\s{0,3}(#{1,6})\s+(.*?)\s*#*\s*$`)
+	bulletRe       = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	orderedRe      = regexp.MustCompile(`^(\s*)(\d+)[.)]\s+(.*)$`)
+	quoteRe        = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+	ruleRe         = regexp.MustCompile(`^\s{0,3}([-*_])(\s*[-*_]){2,}\s*$`)
+	fenceRe        = regexp.MustCompile("^\\s{0,3}(```|~~~)\\s*([\\w+#.-]*)")
+	tableDividerRe = regexp.MustCompile(`^\s*\|?(\s*:?-+:?\s*\|)+\s*:?-*:?\s*\|?\s*$`)
+	// the reference list the system prompt asks for, see domain.SystemPrompt
+	referencesTitleRe = regexp.MustCompile(`^\s{0,3}#{1,6}\s*(引用列表|参考文档|参考资料)\s*$`)
+	referenceRe       = regexp.MustCompile(`^\s{0,3}>?\s*\[(\d+)\]\.?\s*\[([^\]]*)\]\(([^)\s]+)\)\s*$`)
+	citationRe        = regexp.MustCompile(`^\[\[(\d+)\]\(([^)\s]+)\)\]`)
+)
+
+// parse splits the markdown into blocks, unterminated code blocks of a streamed answer are kept open
+func parse(md string) []*block {
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+	var blocks []*block
+	add := func(b *block) {
+		// consecutive blank lines are one paragraph break
+		if b.kind == blockBlank && (len(blocks) == 0 || blocks[len(blocks)-1].kind == blockBlank) {
+			return
+		}
+		blocks = append(blocks, b)
+	}
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if m := fenceRe.FindStringSubmatch(line); m != nil {
+			var code []string
+			for i++; i < len(lines); i++ {
+				if strings.HasPrefix(strings.TrimSpace(lines[i]), m[1]) {
+					break
+				}
+				code = append(code, lines[i])
+			}
+			add(&block{kind: blockCode, lang: m[2], code: strings.Join(code, "\n")})
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			add(&block{kind: blockBlank})
+			continue
+		}
+		if referencesTitleRe.MatchString(line) {
+			refs, end := parseReferences(lines, i+1)
+			if len(refs) > 0 {
+				// the list is framed by rules
+				for len(blocks) > 0 && (blocks[len(blocks)-1].kind == blockRule || blocks[len(blocks)-1].kind == blockBlank) {
+					blocks = blocks[:len(blocks)-1]
+				}
+				add(&block{kind: blockBlank})
+				add(&block{kind: blockReferences, refs: refs})
+				i = end - 1
+				continue
+			}
+		}
+		if ruleRe.MatchString(line) {
+			add(&block{kind: blockRule})
+			continue
+		}
+		if strings.Contains(line, "|") && i+1 < len(lines) && tableDividerRe.MatchString(lines[i+1]) && strings.Contains(lines[i+1], "-") {
+			table := &block{kind: blockTable, header: parseRow(line)}
+			for i += 2; i < len(lines) && strings.Contains(lines[i], "|") && strings.TrimSpace(lines[i]) != ""; i++ {
+				table.rows = append(table.rows, parseRow(lines[i]))
+			}
+			i--
+			add(table)
+			continue
+		}
+		if m := headingRe.FindStringSubmatch(line); m != nil {
+			add(&block{kind: blockHeading, level: len(m[1]), text: parseInline(m[2])})
+			continue
+		}
+		if m := quoteRe.FindStringSubmatch(line); m != nil {
+			add(&block{kind: blockQuote, text: parseInline(m[1])})
+			continue
+		}
+		if m := bulletRe.FindStringSubmatch(line); m != nil {
+			add(&block{kind: blockListItem, indent: m[1], text: parseInline(m[2])})
+			continue
+		}
+		if m := orderedRe.FindStringSubmatch(line); m != nil {
+			add(&block{kind: blockListItem, indent: m[1], marker: m[2], text: parseInline(m[3])})
+			continue
+		}
+		add(&block{kind: blockParagraph, text: parseInline(line)})
+	}
+	for len(blocks) > 0 && blocks[len(blocks)-1].kind == blockBlank {
+		blocks = blocks[:len(blocks)-1]
+	}
+	if len(blocks) > 0 && blocks[0].kind == blockBlank {
+		blocks = blocks[1:]
+	}
+	return blocks
+}
+
+// parseReferences reads the entries of a reference list starting at line from, end is the first line after the list
+func parseReferences(lines []string, from int) (refs []bot.Source, end int) {
+	end = from
+	for i := from; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if m := referenceRe.FindStringSubmatch(line); m != nil {
+			refs = append(refs, bot.Source{Title: m[2], URL: m[3]})
+			end = i + 1
+			continue
+		}
+		if line == "" || line == ">" {
+			continue
+		}
+		// the closing rule belongs to the list
+		if ruleRe.MatchString(line) && len(refs) > 0 {
+			end = i + 1
+		}
+		break
+	}
+	return refs, end
+}
+
+func parseRow(line string) [][]inline {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, "\\|") {
+		line = line[:len(line)-1]
+	}
+	var (
+		cells [][]inline
+		cell  strings.Builder
+	)
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' && i+1 < len(line) && line[i+1] == '|' {
+			cell.WriteByte('|')
+			i++
+			continue
+		}
+		if line[i] == '|' {
+			cells = append(cells, parseInline(strings.TrimSpace(cell.String())))
+			cell.Reset()
+			continue
+		}
+		cell.WriteByte(line[i])
+	}
+	return append(cells, parseInline(strings.TrimSpace(cell.String())))
+}
+
+// characters markdown allows to escape with a backslash
+const escapable = "\\`*_{}[]()#+-.!|~<>"
+
+func isWordRune(r rune) bool {
+	return r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+// findClosing returns the index of the closing delimiter in runes starting at from, or -1
+func findClosing(runes []rune, from int, delim string) int {
+	d := []rune(delim)
+	for i := from; i+len(d) <= len(runes); i++ {
+		if runes[i] == '\\' {
+			i++
+			continue
+		}
+		if string(runes[i:i+len(d)]) != delim {
+			continue
+		}
+		// closing delimiters must follow a non-space character
+		if i == from || runes[i-1] == ' ' {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+func indexRune(runes []rune, from int, target rune) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == target {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseLink parses [text](url) starting at the opening bracket, end is the index of the closing parenthesis
+func parseLink(runes []rune, start int) (text, url string, end int, ok bool) {
+	closeText := indexRune(runes, start+1, ']')
+	if closeText < 0 || closeText+1 >= len(runes) || runes[closeText+1] != '(' {
+		return "", "", 0, false
+	}
+	closeURL := indexRune(runes, closeText+2, ')')
+	if closeURL < 0 {
+		return "", "", 0, false
+	}
+	url = strings.TrimSpace(string(runes[closeText+2 : closeURL]))
+	// drop the optional link title
+	if idx := strings.IndexAny(url, " \t"); idx > 0 {
+		url = url[:idx]
+	}
+	if url == "" {
+		return "", "", 0, false
+	}
+	return string(runes[start+1 : closeText]), url, closeURL, true
+}
+
+func parseInline(s string) []inline {
+	runes := []rune(s)
+	var nodes []inline
+	appendText := func(text, raw string) {
+		if n := len(nodes); n > 0 && nodes[n-1].kind == inlineText {
+			nodes[n-1].text += text
+			nodes[n-1].raw += raw
+			return
+		}
+		nodes = append(nodes, inline{kind: inlineText, text: text, raw: raw})
+	}
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		rest := string(runes[i:])
+		switch {
+		case r == '\\' && i+1 < len(runes) && strings.ContainsRune(escapable, runes[i+1]):
+			appendText(string(runes[i+1]), string(runes[i:i+2]))
+			i++
+			continue
+		case r == '`':
+			if end := indexRune(runes, i+1, '`'); end > i+1 {
+				nodes = append(nodes, inline{kind: inlineCode, text: string(runes[i+1 : end])})
+				i = end
+				continue
+			}
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if end := findClosing(runes, i+2, rest[:2]); end > 0 {
+				nodes = append(nodes, inline{kind: inlineBold, children: parseInline(string(runes[i+2 : end]))})
+				i = end + 1
+				continue
+			}
+		case strings.HasPrefix(rest, "~~"):
+			if end := findClosing(runes, i+2, "~~"); end > 0 {
+				nodes = append(nodes, inline{kind: inlineStrike, children: parseInline(string(runes[i+2 : end]))})
+				i = end + 1
+				continue
+			}
+		case r == '*' || r == '_':
+			// snake_case identifiers are not emphasis
+			if (i == 0 || !isWordRune(runes[i-1])) && i+1 < len(runes) && runes[i+1] != ' ' {
+				if end := findClosing(runes, i+1, string(r)); end > 0 && (end+1 == len(runes) || !isWordRune(runes[end+1])) {
+					nodes = append(nodes, inline{kind: inlineItalic, children: parseInline(string(runes[i+1 : end]))})
+					i = end
+					continue
+				}
+			}
+		case r == '[':
+			if m := citationRe.FindStringSubmatch(rest); m != nil {
+				nodes = append(nodes, inline{kind: inlineCitation, text: m[1], url: m[2]})
+				i += len([]rune(m[0])) - 1
+				continue
+			}
+			if text, url, end, ok := parseLink(runes, i); ok {
+				children := parseInline(text)
+				if text == "" {
+					children = []inline{{kind: inlineText, text: url, raw: url}}
+				}
+				nodes = append(nodes, inline{kind: inlineLink, url: url, children: children})
+				i = end
+				continue
+			}
+		case r == '!' && i+1 < len(runes) && runes[i+1] == '[':
+			if text, url, end, ok := parseLink(runes, i+1); ok {
+				nodes = append(nodes, inline{kind: inlineImage, text: text, url: url})
+				i = end
+				continue
+			}
+		}
+		appendText(string(r), string(r))
+	}
+	return nodes
+}
+
+// withSources appends the documents the answer is based on, unless the answer lists its references itself
+func withSources(blocks []*block, sources []bot.Source) []*block {
+	if len(sources) == 0 {
+		return blocks
+	}
+	for _, b := range blocks {
+		if b.kind == blockReferences {
+			return blocks
+		}
+	}
+	refs := make([]bot.Source, 0, len(sources))
+	seen := make(map[string]bool, len(sources))
+	for _, source := range sources {
+		if seen[source.URL] {
+			continue
+		}
+		seen[source.URL] = true
+		refs = append(refs, source)
+	}
+	if len(blocks) > 0 {
+		blocks = append(blocks, &block{kind: blockBlank})
+	}
+	return append(blocks, &block{kind: blockReferences, refs: refs})
+}
+
+// plain returns the text of inline nodes without any markup
+func plain(nodes []inline) string {
+	var sb strings.Builder
+	for _, n := range nodes {
+		switch n.kind {
+		case inlineText, inlineCode:
+			sb.WriteString(n.text)
+		case inlineCitation:
+			sb.WriteString("[" + n.text + "]")
+		case inlineImage:
+			sb.WriteString(n.text)
+		default:
+			sb.WriteString(plain(n.children))
+		}
+	}
+	return sb.String()
+}
+
+// unwrap replaces the nodes of kind by their children, markup of the same kind can not be nested
+func unwrap(nodes []inline, kind inlineKind) []inline {
+	out := make([]inline, 0, len(nodes))
+	for _, n := range nodes {
+		if n.kind == kind {
+			out = append(out, unwrap(n.children, kind)...)
+			continue
+		}
+		n.children = unwrap(n.children, kind)
+		out = append(out, n)
+	}
+	return out
+}
+
+func textNode(s string) inline {
+	return inline{kind: inlineText, text: s, raw: s}
+}
+
+// lower rewrites the markup the channel can not display into markup it can
+func (c Channel) lower(blocks []*block) []*block {
+	out := make([]*block, 0, len(blocks))
+	for _, b := range blocks {
+		b := *b
+		if !c.Images {
+			b.text = lowerImages(b.text)
+		}
+		switch {
+		case b.kind == blockHeading && !c.Headings:
+			b.kind = blockParagraph
+			b.text = []inline{{kind: inlineBold, children: unwrap(b.text, inlineBold)}}
+		case b.kind == blockTable && !c.Tables:
+			// every row becomes a list item naming the column of each cell
+			for _, row := range b.rows {
+				item := &block{kind: blockListItem}
+				for i, cell := range row {
+					if i > 0 {
+						item.text = append(item.text, textNode("；"))
+					}
+					if i < len(b.header) && plain(b.header[i]) != "" {
+						item.text = append(item.text, b.header[i]...)
+						item.text = append(item.text, textNode(": "))
+					}
+					item.text = append(item.text, cell...)
+				}
+				if !c.Images {
+					item.text = lowerImages(item.text)
+				}
+				out = append(out, item)
+			}
+			continue
+		}
+		out = append(out, &b)
+	}
+	return out
+}
+
+func lowerImages(nodes []inline) []inline {
+	out := make([]inline, 0, len(nodes))
+	for _, n := range nodes {
+		if n.kind == inlineImage {
+			alt := n.text
+			if alt == "" {
+				alt = "图片"
+			}
+			out = append(out, inline{kind: inlineLink, url: n.url, children: []inline{textNode(alt)}})
+			continue
+		}
+		n.children = lowerImages(n.children)
+		out = append(out, n)
+	}
+	return out
+}
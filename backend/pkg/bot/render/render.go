@@ -0,0 +1,244 @@
+// Package render turns the markdown answer of the llm into the markup of each bot channel.
+// Reasoning is dropped, the reference list and inline citations are rendered consistently
+// and long answers are split into messages the platform accepts.
+package render
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chaitin/panda-wiki/pkg/bot"
+)
+
+// Format is the markup a channel displays
+type Format int
+
+const (
+	FormatText Format = iota
+	FormatMarkdown
+	FormatMarkdownV2
+	FormatHTML
+)
+
+// Channel describes how a platform displays answers
+type Channel struct {
+	Format Format
+	// Limit is the size of one message in runes, longer answers are split. Platforms
+	// limiting bytes are given a third of it, which holds for chinese text. 0 means no limit.
+	Limit int
+	// Tables, Headings and Images are set when the channel displays them,
+	// tables become lists, headings bold text and images links otherwise
+	Tables   bool
+	Headings bool
+	Images   bool
+	// Think keeps the reasoning of the model in <think> tags for channels that fold it
+	Think bool
+}
+
+var (
+	PlainText = Channel{Format: FormatText}
+	// Discord counts utf-16 units, the limit leaves room for emoji
+	Discord = Channel{Format: FormatMarkdown, Limit: 1900, Headings: true}
+	// markdown blocks hold 12000 characters
+	Slack = Channel{Format: FormatMarkdown, Limit: 12000, Headings: true}
+	// the 4096 limit applies to the text without markup
+	Telegram = Channel{Format: FormatMarkdownV2, Limit: 4000}
+	// Adaptive Cards are limited to 28KB, see Card
+	Teams = Channel{Format: FormatMarkdown, Limit: 20000, Tables: true}
+	// card json is limited to 30KB
+	Feishu   = Channel{Format: FormatMarkdown, Limit: 9000, Headings: true}
+	DingTalk = Channel{Format: FormatMarkdown, Limit: 6000, Headings: true}
+	// stream messages are limited to 20480 bytes
+	WeCom                 = Channel{Format: FormatMarkdown, Limit: 6000, Headings: true, Think: true}
+	WechatOfficialAccount = Channel{Format: FormatText}
+	Mail                  = Channel{Format: FormatHTML, Tables: true, Headings: true, Images: true}
+)
+
+// Answer is the streamed answer of the llm, Sources are listed below it
+// unless the answer contains a reference list of its own
+type Answer struct {
+	Markdown string
+	Sources  []bot.Source
+}
+
+// WithFormat returns the channel rendering another format, e.g. plain text when markup is rejected
+func (c Channel) WithFormat(format Format) Channel {
+	c.Format = format
+	return c
+}
+
+// Render converts the answer to the markup of the channel, split into messages within the limit
+func (c Channel) Render(answer Answer) []string {
+	return c.render(answer, c.Limit)
+}
+
+// Preview renders a partial answer into one message while it is streamed,
+// the rest is cut off. It is empty while the model is reasoning.
+func (c Channel) Preview(md string) string {
+	limit := c.Limit
+	if limit > 0 {
+		limit -= 2
+	}
+	parts := c.render(Answer{Markdown: md}, limit)
+	if len(parts) > 1 {
+		return parts[0] + "\n…"
+	}
+	return parts[0]
+}
+
+func (c Channel) render(answer Answer, limit int) []string {
+	think, md, thinking := splitThink(answer.Markdown)
+	var prefix string
+	if c.Think && strings.TrimSpace(think) != "" {
+		// the reasoning takes at most half of the message
+		if limit > 0 {
+			think = cut(think, limit/2)
+		}
+		prefix = "<think>" + think + "</think>\n"
+		if limit > 0 {
+			limit -= utf8.RuneCountInString(prefix)
+		}
+	}
+	var parts []string
+	if !thinking {
+		parts = c.pack(c.lower(withSources(parse(md), answer.Sources)), limit)
+	}
+	if len(parts) == 0 {
+		parts = []string{""}
+	}
+	parts[0] = prefix + parts[0]
+	return parts
+}
+
+// splitThink separates the reasoning of the model from the answer, thinking is set while the reasoning is streamed
+func splitThink(md string) (think, answer string, thinking bool) {
+	trimmed := strings.TrimLeft(md, " \r\n")
+	if !strings.HasPrefix(trimmed, "<think>") {
+		return "", md, false
+	}
+	trimmed = strings.TrimPrefix(trimmed, "<think>")
+	end := strings.Index(trimmed, "</think>")
+	if end < 0 {
+		return trimmed, "", true
+	}
+	return trimmed[:end], trimmed[end+len("</think>"):], false
+}
+
+// StripThink removes the reasoning of the model from the answer
+func StripThink(md string) string {
+	_, answer, _ := splitThink(md)
+	return answer
+}
+
+// HasReferences reports whether the answer lists the documents it is based on
+func HasReferences(md string) bool {
+	for _, b := range parse(md) {
+		if b.kind == blockReferences {
+			return true
+		}
+	}
+	return false
+}
+
+// cut returns the first limit runes of s
+func cut(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit])
+}
+
+// pack joins the rendered blocks into messages of at most limit runes
+func (c Channel) pack(blocks []*block, limit int) []string {
+	r := rendererOf(c.Format)
+	var (
+		parts []string
+		cur   []string
+		blank []bool
+		size  int
+	)
+	flush := func() {
+		// parts neither start nor end with an empty line
+		for len(cur) > 0 && blank[len(cur)-1] {
+			cur, blank = cur[:len(cur)-1], blank[:len(blank)-1]
+		}
+		if len(cur) > 0 {
+			parts = append(parts, strings.Join(cur, "\n"))
+		}
+		cur, blank, size = nil, nil, 0
+	}
+	for _, b := range blocks {
+		isBlank := b.kind == blockBlank
+		for _, s := range c.fit(r, b, limit) {
+			n := utf8.RuneCountInString(s)
+			if limit > 0 && len(cur) > 0 && size+1+n > limit {
+				flush()
+			}
+			if len(cur) == 0 && isBlank {
+				continue
+			}
+			if len(cur) > 0 {
+				size++
+			}
+			cur, blank, size = append(cur, s), append(blank, isBlank), size+n
+		}
+	}
+	flush()
+	return parts
+}
+
+// fit renders the block, blocks longer than limit are split into several
+func (c Channel) fit(r renderer, b *block, limit int) []string {
+	s := r.block(b)
+	if limit <= 0 || utf8.RuneCountInString(s) <= limit {
+		return []string{s}
+	}
+	var out []string
+	switch b.kind {
+	case blockCode:
+		// code is split at line boundaries, every chunk is a code block of its own
+		var chunk []string
+		render := func(lines []string) string {
+			part := *b
+			part.code = strings.Join(lines, "\n")
+			return r.block(&part)
+		}
+		for _, line := range strings.Split(b.code, "\n") {
+			if len(chunk) > 0 && utf8.RuneCountInString(render(append(chunk, line))) > limit {
+				out = append(out, render(chunk))
+				chunk = nil
+			}
+			chunk = append(chunk, line)
+		}
+		out = append(out, render(chunk))
+	case blockTable:
+		// rows are split into tables repeating the header
+		var rows [][][]inline
+		render := func(rows [][][]inline) string {
+			part := *b
+			part.rows = rows
+			return r.block(&part)
+		}
+		for _, row := range b.rows {
+			if len(rows) > 0 && utf8.RuneCountInString(render(append(rows, row))) > limit {
+				out = append(out, render(rows))
+				rows = nil
+			}
+			rows = append(rows, row)
+		}
+		out = append(out, render(rows))
+	default:
+		out = []string{s}
+	}
+	// whatever is still too long is cut
+	var fitted []string
+	for _, s := range out {
+		runes := []rune(s)
+		for len(runes) > limit {
+			fitted = append(fitted, string(runes[:limit]))
+			runes = runes[limit:]
+		}
+		fitted = append(fitted, string(runes))
+	}
+	return fitted
+}
@@ -0,0 +1,135 @@
+package render
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/chaitin/panda-wiki/pkg/bot"
+)
+
+func TestMarkdownV2(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"escape", "Version 1.2 (beta)!", `Version 1\.2 \(beta\)\!`},
+		{"bold", "**PandaWiki** is ok", `*PandaWiki* is ok`},
+		{"italic", "an *italic* word", `an _italic_ word`},
+		{"snake case", "set max_token_size now", `set max\_token\_size now`},
+		{"strike", "~~old~~", `~old~`},
+		{"code", "run `a_b.sh -x`", "run `a_b.sh -x`"},
+		{"link", "see [v1.2 docs](https://example.com/a_b?x=1 \"title\")", `see [v1\.2 docs](https://example.com/a_b?x=1)`},
+		{"heading", "## **Install** guide", `*Install guide*`},
+		{"list", "- item.one\n  1. sub", "• item\\.one\n  1\\. sub"},
+		{"quote", "> note", `>note`},
+		{"rule", "---  ", "——————"},
+		{"fence", "```go\nfmt.Println(\"`x`\")\n```", "```go\nfmt.Println(\"\\`x\\`\")\n```"},
+		{"unmatched", "2 * 3 = 6_", `2 \* 3 \= 6\_`},
+		{"citation", "ok[[1](https://a.com/x)].", `ok[\[1\]](https://a.com/x)\.`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Telegram.Render(Answer{Markdown: tc.in}); len(got) != 1 || got[0] != tc.want {
+				t.Fatalf("Render(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+const answer = "<think>\nlooking at the docs\n</think>\n## 安装\n\n1. 下载 **安装包**[[1](https://wiki/node/1)]。\n2. 运行 `install.sh`\n\n" +
+	"| 参数 | 说明 |\n| --- | --- |\n| port | 端口 |\n\n" +
+	"---\n### 引用列表\n> [1]. [安装指南](https://wiki/node/1)\n---"
+
+func TestText(t *testing.T) {
+	got := PlainText.Render(Answer{Markdown: answer})
+	want := "安装\n\n1. 下载 安装包[1]。\n2. 运行 install.sh\n\n• 参数: port；说明: 端口\n\n参考文档：\n[1] 安装指南 https://wiki/node/1"
+	if len(got) != 1 || got[0] != want {
+		t.Fatalf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	got := Slack.Render(Answer{Markdown: answer})
+	want := "## 安装\n\n1. 下载 **安装包**[[1](https://wiki/node/1)]。\n2. 运行 `install.sh`\n\n- 参数: port；说明: 端口\n\n**参考文档**\n1. [安装指南](https://wiki/node/1)"
+	if len(got) != 1 || got[0] != want {
+		t.Fatalf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	got := Mail.Render(Answer{Markdown: "a < b [link](https://x.com/?a=1&b=2)\n\n| k | v |\n|---|---|\n| 1 | 2 |"})
+	want := `<div>a &lt; b <a href="https://x.com/?a=1&amp;b=2">link</a></div>` + "\n<br>\n" +
+		`<table style="border-collapse:collapse" border="1" cellpadding="4"><tr><th>k</th><th>v</th></tr><tr><td>1</td><td>2</td></tr></table>`
+	if len(got) != 1 || got[0] != want {
+		t.Fatalf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestThink(t *testing.T) {
+	if got := Discord.Preview("<think>still reasoning"); got != "" {
+		t.Fatalf("Preview() = %q while reasoning", got)
+	}
+	if got := WeCom.Render(Answer{Markdown: "<think>why</think>\nanswer"}); got[0] != "<think>why</think>\nanswer" {
+		t.Fatalf("Render() = %q, reasoning is not kept", got[0])
+	}
+	if got := Discord.Render(Answer{Markdown: "<think>why</think>\nanswer"}); got[0] != "answer" {
+		t.Fatalf("Render() = %q, reasoning is not dropped", got[0])
+	}
+}
+
+func TestSources(t *testing.T) {
+	sources := []bot.Source{{Title: "A", URL: "https://a"}, {Title: "A", URL: "https://a"}, {Title: "B", URL: "https://b"}}
+	got := Slack.Render(Answer{Markdown: "answer", Sources: sources})
+	want := "answer\n\n**参考文档**\n1. [A](https://a)\n2. [B](https://b)"
+	if got[0] != want {
+		t.Fatalf("Render() = %q, want %q", got[0], want)
+	}
+	// the reference list of the answer takes precedence
+	got = Slack.Render(Answer{Markdown: answer, Sources: sources})
+	if strings.Contains(got[0], "https://b") {
+		t.Fatalf("sources are listed besides the references of the answer: %q", got[0])
+	}
+}
+
+func TestSplit(t *testing.T) {
+	md := "intro\n```\n" + strings.Repeat("line\n", 10) + "```\nend\n\n| k | v |\n|---|---|\n" + strings.Repeat("| key | value |\n", 10)
+	for _, channel := range []Channel{Telegram, Slack} {
+		channel.Limit = 40
+		parts := channel.Render(Answer{Markdown: md})
+		if len(parts) < 3 {
+			t.Fatalf("expected several parts, got %d", len(parts))
+		}
+		for _, part := range parts {
+			if n := utf8.RuneCountInString(part); n > 40 {
+				t.Fatalf("part too long (%d): %q", n, part)
+			}
+			if strings.Count(part, "```")%2 != 0 {
+				t.Fatalf("unbalanced code fence in %q", part)
+			}
+			if strings.HasPrefix(part, "\n") || strings.HasSuffix(part, "\n") {
+				t.Fatalf("part starts or ends with an empty line: %q", part)
+			}
+		}
+	}
+	if got := (Channel{Format: FormatText, Limit: 10}).Preview(strings.Repeat("x\n", 20)); utf8.RuneCountInString(got) > 10 || !strings.HasSuffix(got, "…") {
+		t.Fatalf("Preview() = %q", got)
+	}
+}
+
+func TestCard(t *testing.T) {
+	body := Teams.Card(Answer{Markdown: answer})
+	data, _ := json.Marshal(body)
+	types := make([]string, 0, len(body))
+	for _, element := range body {
+		types = append(types, element.(map[string]any)["type"].(string))
+	}
+	if got := strings.Join(types, ","); got != "TextBlock,Table,TextBlock,TextBlock" {
+		t.Fatalf("card elements %s: %s", got, data)
+	}
+	if !strings.Contains(string(data), `"text":"**安装**\n\n1. 下载 **安装包**[[1](https://wiki/node/1)]。\n2. 运行 `+"`install.sh`"+`"`) {
+		t.Fatalf("unexpected text block: %s", data)
+	}
+}
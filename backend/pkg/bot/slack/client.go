@@ -0,0 +1,343 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/bot"
+	"github.com/chaitin/panda-wiki/pkg/bot/render"
+)
+
+const (
+	defaultAPIURL = "https://slack.com/api"
+
+	feedbackBlockID   = "pandawiki_feedback"
+	feedbackLikeID    = "pandawiki_feedback_like"
+	feedbackDislikeID = "pandawiki_feedback_dislike"
+
+	// chat.update is rate limited, answers are flushed at most once per interval
+	updateInterval = 1500 * time.Millisecond
+)
+
+var leadingMentionRe = regexp.MustCompile(`^(\s*<@[A-Z0-9]+>)+\s*`)
+
+type Option func(*SlackClient)
+
+// WithAPIURL points the client to another Web API base url, e.g. a local stub server
+func WithAPIURL(apiURL string) Option {
+	return func(c *SlackClient) {
+		c.apiURL = strings.TrimRight(apiURL, "/")
+	}
+}
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *SlackClient) {
+		c.httpClient = client
+	}
+}
+
+// SlackClient answers mentions and direct messages.
+// Events are received over Socket Mode when an app-level token is configured,
+// otherwise they are pushed to HandleHTTP by the Events API request url.
+type SlackClient struct {
+	ctx           context.Context
+	cancel        context.CancelFunc
+	botToken      string
+	appToken      string
+	signingSecret string
+	apiURL        string
+	httpClient    *http.Client
+	logger        *log.Logger
+	msgMap        sync.Map
+	getQA         bot.GetQAFun
+	feedback      bot.FeedbackFun
+}
+
+func NewSlackClient(ctx context.Context, cancel context.CancelFunc, botToken, appToken, signingSecret string, logger *log.Logger, getQA bot.GetQAFun, feedback bot.FeedbackFun, opts ...Option) *SlackClient {
+	c := &SlackClient{
+		ctx:           ctx,
+		cancel:        cancel,
+		botToken:      botToken,
+		appToken:      appToken,
+		signingSecret: signingSecret,
+		apiURL:        defaultAPIURL,
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		logger:        logger.WithModule("bot.slack"),
+		getQA:         getQA,
+		feedback:      feedback,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	go func() {
+		ticker := time.NewTicker(1 * time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				c.msgMap.Range(func(key, value any) bool {
+					// remove event id if it is older than 5 minutes
+					if time.Now().Unix()-value.(int64) > 5*60 {
+						c.msgMap.Delete(key)
+					}
+					return true
+				})
+			}
+		}
+	}()
+	return c
+}
+
+// Start blocks until the client is stopped
+func (c *SlackClient) Start() error {
+	if c.appToken == "" {
+		c.logger.Info("slack bot client initialized (events api mode)")
+		<-c.ctx.Done()
+		return nil
+	}
+	c.logger.Info("slack bot client initialized (socket mode)")
+	return c.runSocketMode()
+}
+
+func (c *SlackClient) Stop() {
+	c.cancel()
+}
+
+// SocketMode reports whether events are received over a websocket
+func (c *SlackClient) SocketMode() bool {
+	return c.appToken != ""
+}
+
+type apiResponse struct {
+	OK       bool   `json:"ok"`
+	Error    string `json:"error"`
+	Warning  string `json:"warning"`
+	Metadata struct {
+		Messages []string `json:"messages"`
+	} `json:"response_metadata"`
+}
+
+// call invokes a Web API method, body is sent as json unless it is url.Values
+func (c *SlackClient) call(ctx context.Context, token, method string, body any, out any) error {
+	var (
+		reader      io.Reader
+		contentType string
+	)
+	switch v := body.(type) {
+	case url.Values:
+		reader = strings.NewReader(v.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+		contentType = "application/json; charset=utf-8"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+"/"+method, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack %s failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack %s failed: status %d: %s", method, resp.StatusCode, string(data))
+	}
+	var apiResp apiResponse
+	if err := json.Unmarshal(data, &apiResp); err != nil {
+		return fmt.Errorf("slack %s returned invalid body: %w", method, err)
+	}
+	if !apiResp.OK {
+		return fmt.Errorf("slack %s failed: %s %v", method, apiResp.Error, apiResp.Metadata.Messages)
+	}
+	if out != nil {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
+
+type postMessageResp struct {
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+}
+
+func (c *SlackClient) postMessage(ctx context.Context, channel, threadTS, text string, blocks []any) (*postMessageResp, error) {
+	body := map[string]any{
+		"channel":   channel,
+		"thread_ts": threadTS,
+		"text":      text,
+	}
+	if blocks != nil {
+		body["blocks"] = blocks
+	}
+	var resp postMessageResp
+	if err := c.call(ctx, c.botToken, "chat.postMessage", body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *SlackClient) updateMessage(ctx context.Context, channel, ts, text string, blocks []any) error {
+	body := map[string]any{
+		"channel": channel,
+		"ts":      ts,
+		"text":    text,
+	}
+	if blocks != nil {
+		body["blocks"] = blocks
+	}
+	return c.call(ctx, c.botToken, "chat.update", body, nil)
+}
+
+type userInfoResp struct {
+	User struct {
+		ID       string `json:"id"`
+		Name     string `json:"name"`
+		RealName string `json:"real_name"`
+		Profile  struct {
+			DisplayName string `json:"display_name"`
+			Email       string `json:"email"`
+			Image192    string `json:"image_192"`
+		} `json:"profile"`
+	} `json:"user"`
+}
+
+func (c *SlackClient) getUserInfo(ctx context.Context, userID string) (*userInfoResp, error) {
+	var resp userInfoResp
+	if err := c.call(ctx, c.botToken, "users.info", url.Values{"user": {userID}}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// answerBlocks puts a rendered part of the answer into a markdown block, followed by feedback buttons
+func answerBlocks(text, messageID string) []any {
+	blocks := []any{
+		map[string]any{"type": "markdown", "text": text},
+	}
+	if messageID == "" {
+		return blocks
+	}
+	return append(blocks,
+		map[string]any{
+			"type": "context",
+			"elements": []any{
+				map[string]any{"type": "mrkdwn", "text": "本回答由 PandaWiki 基于 AI 生成，仅供参考。"},
+			},
+		},
+		map[string]any{
+			"type":     "actions",
+			"block_id": feedbackBlockID,
+			"elements": []any{
+				map[string]any{
+					"type":      "button",
+					"action_id": feedbackLikeID,
+					"text":      map[string]any{"type": "plain_text", "text": "👍 满意", "emoji": true},
+					"value":     messageID,
+				},
+				map[string]any{
+					"type":      "button",
+					"action_id": feedbackDislikeID,
+					"text":      map[string]any{"type": "plain_text", "text": "👎 不满意", "emoji": true},
+					"value":     messageID,
+				},
+			},
+		},
+	)
+}
+
+// answer posts a placeholder reply in the thread and streams the answer into it
+func (c *SlackClient) answer(ctx context.Context, channel, threadTS, question string, info domain.ConversationInfo) {
+	placeholder, err := c.postMessage(ctx, channel, threadTS, "稍等，让我想一想...", nil)
+	if err != nil {
+		c.logger.Error("failed to post placeholder message", log.Error(err))
+		return
+	}
+
+	var messageID string
+	qaCtx := bot.WithFeedbackHook(ctx, func(id string) {
+		messageID = id
+	})
+	info.ReplyRoute = map[string]string{"channel": channel, "thread_ts": threadTS}
+	answerCh, err := c.getQA(qaCtx, question, info, "")
+	if err != nil {
+		c.logger.Error("slack client failed to get answer", log.Error(err))
+		if err := c.updateMessage(ctx, placeholder.Channel, placeholder.TS, "出错了，请稍后再试", nil); err != nil {
+			c.logger.Error("failed to update message", log.Error(err))
+		}
+		return
+	}
+	// the answer is posted in the thread of the question, replies there continue the conversation
+	bot.ConversationKeyFromContext(ctx).BindMessage(threadTS)
+
+	var sb strings.Builder
+	lastUpdate := time.Now()
+	for chunk := range answerCh {
+		sb.WriteString(chunk)
+		if time.Since(lastUpdate) < updateInterval {
+			continue
+		}
+		lastUpdate = time.Now()
+		text := render.Slack.Preview(sb.String())
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if err := c.updateMessage(ctx, placeholder.Channel, placeholder.TS, text, answerBlocks(text, "")); err != nil {
+			c.logger.Warn("failed to update streaming message", log.Error(err))
+		}
+	}
+	parts := render.Slack.Render(render.Answer{Markdown: sb.String()})
+	if len(parts) == 1 && strings.TrimSpace(parts[0]) == "" {
+		parts[0] = "抱歉，没有找到相关的答案"
+	}
+	// the rest of a long answer follows in the thread, the last part carries the feedback buttons.
+	// messageID is set by the feedback hook before the answer channel is closed
+	for i, part := range parts {
+		feedbackID := ""
+		if i == len(parts)-1 {
+			feedbackID = messageID
+		}
+		if i == 0 {
+			err = c.updateMessage(ctx, placeholder.Channel, placeholder.TS, part, answerBlocks(part, feedbackID))
+		} else {
+			_, err = c.postMessage(ctx, placeholder.Channel, threadTS, part, answerBlocks(part, feedbackID))
+		}
+		if err != nil {
+			c.logger.Error("failed to send answer message", log.Error(err))
+		}
+	}
+}
+
+// SendHumanReply posts the reply of a human agent to the thread the question was asked in
+func (c *SlackClient) SendHumanReply(ctx context.Context, route map[string]string, content string) error {
+	if route["channel"] == "" {
+		return errors.New("slack reply route has no channel")
+	}
+	text := "**人工回复**\n" + render.Slack.Preview(content)
+	_, err := c.postMessage(ctx, route["channel"], route["thread_ts"], text, answerBlocks(text, ""))
+	return err
+}
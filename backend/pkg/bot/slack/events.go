@@ -0,0 +1,274 @@
+package slack
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/bot"
+)
+
+var ErrInvalidSignature = errors.New("invalid slack request signature")
+
+type eventCallback struct {
+	Type      string          `json:"type"`
+	Challenge string          `json:"challenge"`
+	EventID   string          `json:"event_id"`
+	Event     json.RawMessage `json:"event"`
+}
+
+type messageEvent struct {
+	Type        string `json:"type"`
+	Subtype     string `json:"subtype"`
+	ChannelType string `json:"channel_type"`
+	Channel     string `json:"channel"`
+	User        string `json:"user"`
+	BotID       string `json:"bot_id"`
+	Text        string `json:"text"`
+	TS          string `json:"ts"`
+	ThreadTS    string `json:"thread_ts"`
+	Files       []file `json:"files"`
+}
+
+type file struct {
+	Name               string `json:"name"`
+	Mimetype           string `json:"mimetype"`
+	Size               int64  `json:"size"`
+	URLPrivateDownload string `json:"url_private_download"`
+}
+
+type interactivePayload struct {
+	Type string `json:"type"`
+	User struct {
+		ID string `json:"id"`
+	} `json:"user"`
+	Channel struct {
+		ID string `json:"id"`
+	} `json:"channel"`
+	Message struct {
+		TS     string            `json:"ts"`
+		Text   string            `json:"text"`
+		Blocks []json.RawMessage `json:"blocks"`
+	} `json:"message"`
+	Actions []struct {
+		ActionID string `json:"action_id"`
+		BlockID  string `json:"block_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+}
+
+// HandleHTTP serves the Events API and interactivity request url,
+// it returns the status code and body to reply with
+func (c *SlackClient) HandleHTTP(header http.Header, body []byte) (int, []byte) {
+	if err := c.verifySignature(header, body, time.Now()); err != nil {
+		c.logger.Warn("reject slack request", log.Error(err))
+		return http.StatusUnauthorized, nil
+	}
+	// interactivity payloads are form encoded
+	if strings.HasPrefix(header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return http.StatusBadRequest, nil
+		}
+		c.handleInteractive(json.RawMessage(form.Get("payload")))
+		return http.StatusOK, nil
+	}
+
+	var callback eventCallback
+	if err := json.Unmarshal(body, &callback); err != nil {
+		return http.StatusBadRequest, nil
+	}
+	switch callback.Type {
+	case "url_verification":
+		resp, _ := json.Marshal(map[string]string{"challenge": callback.Challenge})
+		return http.StatusOK, resp
+	case "event_callback":
+		c.handleEventCallback(&callback)
+	}
+	return http.StatusOK, nil
+}
+
+// verifySignature checks the X-Slack-Signature header signed with the signing secret
+func (c *SlackClient) verifySignature(header http.Header, body []byte, now time.Time) error {
+	if c.signingSecret == "" {
+		return ErrInvalidSignature
+	}
+	ts := header.Get("X-Slack-Request-Timestamp")
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	// reject replayed requests
+	if d := now.Sub(time.Unix(sec, 0)); d > 5*time.Minute || d < -5*time.Minute {
+		return ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(c.signingSecret))
+	mac.Write([]byte("v0:" + ts + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature"))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (c *SlackClient) handleEventCallback(callback *eventCallback) {
+	// slack retries events that are not acknowledged in time
+	if callback.EventID != "" {
+		if _, loaded := c.msgMap.LoadOrStore(callback.EventID, time.Now().Unix()); loaded {
+			return
+		}
+	}
+	var event messageEvent
+	if err := json.Unmarshal(callback.Event, &event); err != nil {
+		c.logger.Error("failed to unmarshal slack event", log.Error(err))
+		return
+	}
+	// ignore bot messages, including our own replies, and edits
+	if event.BotID != "" || (event.Subtype != "" && event.Subtype != "file_share") || event.User == "" {
+		return
+	}
+
+	info := domain.ConversationInfo{}
+	switch {
+	case event.Type == "app_mention":
+		info.UserInfo.From = domain.MessageFromGroup
+	case event.Type == "message" && event.ChannelType == "im":
+		info.UserInfo.From = domain.MessageFromPrivate
+	default:
+		return
+	}
+	question := strings.TrimSpace(leadingMentionRe.ReplaceAllString(event.Text, ""))
+	if question == "" && len(event.Files) == 0 {
+		return
+	}
+	threadTS := event.ThreadTS
+	if threadTS == "" {
+		threadTS = event.TS
+	}
+	c.logger.Info("received message from slack bot", log.String("channel", event.Channel), log.String("ts", event.TS))
+
+	go func() {
+		info.UserInfo.UserID = event.User
+		if user, err := c.getUserInfo(c.ctx, event.User); err != nil {
+			c.logger.Warn("get slack user info failed", log.Error(err))
+		} else {
+			info.UserInfo.NickName = user.User.Profile.DisplayName
+			if info.UserInfo.NickName == "" {
+				info.UserInfo.NickName = user.User.Name
+			}
+			info.UserInfo.RealName = user.User.RealName
+			info.UserInfo.Email = user.User.Profile.Email
+			info.UserInfo.Avatar = user.User.Profile.Image192
+		}
+		// mentions continue their thread, direct messages the private chat or the thread of an earlier answer
+		key := &bot.ConversationKey{Chat: event.Channel, User: event.User}
+		if info.UserInfo.From == domain.MessageFromPrivate {
+			key.ReplyTo = event.ThreadTS
+		} else {
+			key.Thread = threadTS
+		}
+		ctx := bot.WithConversationKey(c.ctx, key)
+		if attachments := c.downloadFiles(c.ctx, event.Files); len(attachments) > 0 {
+			ctx = bot.WithAttachments(ctx, attachments)
+			if question == "" {
+				question = bot.DefaultAttachmentQuestion
+			}
+		}
+		if question == "" {
+			return
+		}
+		c.answer(ctx, event.Channel, threadTS, question, info)
+	}()
+}
+
+// downloadFiles fetches the files shared with the question, the bot token needs the files:read scope
+func (c *SlackClient) downloadFiles(ctx context.Context, files []file) []bot.Attachment {
+	attachments := make([]bot.Attachment, 0, len(files))
+	for _, f := range files {
+		if f.URLPrivateDownload == "" || f.Size > bot.MaxAttachmentSize {
+			continue
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.URLPrivateDownload, nil)
+		if err != nil {
+			continue
+		}
+		req.Header.Set("Authorization", "Bearer "+c.botToken)
+		attachment, err := bot.DownloadAttachment(c.httpClient, req, f.Name)
+		if err != nil {
+			c.logger.Warn("failed to download slack file", log.String("name", f.Name), log.Error(err))
+			continue
+		}
+		if f.Mimetype != "" {
+			attachment.ContentType = f.Mimetype
+		}
+		attachments = append(attachments, *attachment)
+	}
+	return attachments
+}
+
+func (c *SlackClient) handleInteractive(raw json.RawMessage) {
+	var payload interactivePayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		c.logger.Error("failed to unmarshal slack interactive payload", log.Error(err))
+		return
+	}
+	if payload.Type != "block_actions" {
+		return
+	}
+	for _, action := range payload.Actions {
+		var score domain.ScoreType
+		switch action.ActionID {
+		case feedbackLikeID:
+			score = domain.Like
+		case feedbackDislikeID:
+			score = domain.DisLike
+		default:
+			continue
+		}
+		go c.handleFeedback(&payload, action.Value, score)
+		return
+	}
+}
+
+// handleFeedback records the vote and replaces the buttons with a thank-you note
+func (c *SlackClient) handleFeedback(payload *interactivePayload, messageID string, score domain.ScoreType) {
+	ctx, cancel := context.WithTimeout(c.ctx, 30*time.Second)
+	defer cancel()
+	if c.feedback == nil {
+		return
+	}
+	note := "感谢您的反馈"
+	if err := c.feedback(ctx, messageID, score); err != nil {
+		c.logger.Warn("failed to save slack feedback", log.String("message_id", messageID), log.Error(err))
+		note = "您已经评价过该回答"
+	}
+
+	blocks := make([]any, 0, len(payload.Message.Blocks))
+	for _, raw := range payload.Message.Blocks {
+		var block struct {
+			BlockID string `json:"block_id"`
+		}
+		if err := json.Unmarshal(raw, &block); err == nil && block.BlockID == feedbackBlockID {
+			blocks = append(blocks, map[string]any{
+				"type":     "context",
+				"elements": []any{map[string]any{"type": "mrkdwn", "text": note}},
+			})
+			continue
+		}
+		blocks = append(blocks, raw)
+	}
+	if err := c.updateMessage(ctx, payload.Channel.ID, payload.Message.TS, payload.Message.Text, blocks); err != nil {
+		c.logger.Warn("failed to update feedback message", log.Error(err))
+	}
+}
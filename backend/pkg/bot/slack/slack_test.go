@@ -0,0 +1,182 @@
+package slack
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chaitin/panda-wiki/config"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/bot"
+)
+
+const testSecret = "secret"
+
+// stubAPI records Web API calls made by the client
+type stubAPI struct {
+	mu    sync.Mutex
+	calls map[string][]map[string]any
+}
+
+func (s *stubAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := strings.TrimPrefix(r.URL.Path, "/")
+	body, _ := io.ReadAll(r.Body)
+	args := map[string]any{}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		_ = json.Unmarshal(body, &args)
+	} else if form, err := url.ParseQuery(string(body)); err == nil {
+		for k := range form {
+			args[k] = form.Get(k)
+		}
+	}
+	s.mu.Lock()
+	s.calls[method] = append(s.calls[method], args)
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch method {
+	case "chat.postMessage":
+		_, _ = w.Write([]byte(`{"ok":true,"channel":"C1","ts":"200.1"}`))
+	case "users.info":
+		_, _ = w.Write([]byte(`{"ok":true,"user":{"id":"U1","name":"alice","profile":{"email":"alice@example.com"}}}`))
+	default:
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}
+}
+
+func (s *stubAPI) get(method string) []map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+func signedHeader(body []byte, contentType string) http.Header {
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte("v0:" + ts + ":"))
+	mac.Write(body)
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+	header.Set("X-Slack-Request-Timestamp", ts)
+	header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return header
+}
+
+func newTestClient(t *testing.T, stub *stubAPI, feedback bot.FeedbackFun) *SlackClient {
+	srv := httptest.NewServer(stub)
+	t.Cleanup(srv.Close)
+	cfg, _ := config.NewConfig()
+	getQA := func(ctx context.Context, msg string, info domain.ConversationInfo, conversationID string) (chan string, error) {
+		if key := bot.ConversationKeyFromContext(ctx); key == nil || key.Thread != info.ReplyRoute["thread_ts"] {
+			return nil, fmt.Errorf("conversation key %+v does not match the reply thread", key)
+		}
+		ch := make(chan string, 2)
+		ch <- "hello "
+		ch <- msg
+		bot.FeedbackHookFromContext(ctx)("msg-1")
+		close(ch)
+		return ch, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return NewSlackClient(ctx, cancel, "xoxb-test", "", testSecret, log.NewLogger(cfg), getQA, feedback, WithAPIURL(srv.URL))
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHandleHTTPSignature(t *testing.T) {
+	c := newTestClient(t, &stubAPI{calls: map[string][]map[string]any{}}, nil)
+
+	body := []byte(`{"type":"url_verification","challenge":"abc"}`)
+	status, resp := c.HandleHTTP(signedHeader(body, "application/json"), body)
+	if status != http.StatusOK || !strings.Contains(string(resp), `"challenge":"abc"`) {
+		t.Fatalf("url_verification = %d %s", status, resp)
+	}
+
+	header := signedHeader(body, "application/json")
+	header.Set("X-Slack-Signature", "v0=bad")
+	if status, _ := c.HandleHTTP(header, body); status != http.StatusUnauthorized {
+		t.Fatalf("bad signature status = %d, want 401", status)
+	}
+}
+
+func TestMentionAndFeedback(t *testing.T) {
+	stub := &stubAPI{calls: map[string][]map[string]any{}}
+	var (
+		mu    sync.Mutex
+		votes []domain.ScoreType
+	)
+	c := newTestClient(t, stub, func(ctx context.Context, messageID string, score domain.ScoreType) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if messageID == "msg-1" {
+			votes = append(votes, score)
+		}
+		return nil
+	})
+
+	body := []byte(`{"type":"event_callback","event_id":"Ev1","event":{"type":"app_mention","user":"U1","text":"<@UBOT> how to deploy","ts":"100.1","channel":"C1"}}`)
+	// the retried event must be answered only once
+	for range 2 {
+		if status, _ := c.HandleHTTP(signedHeader(body, "application/json"), body); status != http.StatusOK {
+			t.Fatalf("event status = %d", status)
+		}
+	}
+	waitFor(t, func() bool { return len(stub.get("chat.update")) > 0 })
+
+	posts := stub.get("chat.postMessage")
+	if len(posts) != 1 || posts[0]["thread_ts"] != "100.1" {
+		t.Fatalf("chat.postMessage calls = %v, want one reply in thread 100.1", posts)
+	}
+	updates := stub.get("chat.update")
+	final := updates[len(updates)-1]
+	if final["text"] != "hello how to deploy" {
+		t.Fatalf("final answer = %v", final["text"])
+	}
+	blocks, _ := json.Marshal(final["blocks"])
+	if !strings.Contains(string(blocks), feedbackLikeID) || !strings.Contains(string(blocks), `"value":"msg-1"`) {
+		t.Fatalf("feedback buttons missing: %s", blocks)
+	}
+
+	payload, _ := json.Marshal(map[string]any{
+		"type":    "block_actions",
+		"user":    map[string]any{"id": "U1"},
+		"channel": map[string]any{"id": "C1"},
+		"message": map[string]any{"ts": "200.1", "text": "hello how to deploy", "blocks": final["blocks"]},
+		"actions": []any{map[string]any{"action_id": feedbackDislikeID, "block_id": feedbackBlockID, "value": "msg-1"}},
+	})
+	form := []byte(url.Values{"payload": {string(payload)}}.Encode())
+	if status, _ := c.HandleHTTP(signedHeader(form, "application/x-www-form-urlencoded"), form); status != http.StatusOK {
+		t.Fatalf("interactive status = %d", status)
+	}
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(votes) == 1
+	})
+	if votes[0] != domain.DisLike {
+		t.Fatalf("vote = %d, want %d", votes[0], domain.DisLike)
+	}
+}
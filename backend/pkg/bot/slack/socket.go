@@ -0,0 +1,107 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/chaitin/panda-wiki/log"
+)
+
+type socketEnvelope struct {
+	EnvelopeID string          `json:"envelope_id"`
+	Type       string          `json:"type"`
+	Reason     string          `json:"reason"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// runSocketMode keeps a Socket Mode connection open until the client is stopped
+func (c *SlackClient) runSocketMode() error {
+	backoff := time.Second
+	for {
+		err := c.serveSocket()
+		if c.ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			c.logger.Warn("slack socket mode connection closed", log.Error(err), log.Any("retry_in", backoff))
+		}
+		select {
+		case <-c.ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		if err != nil {
+			backoff = min(backoff*2, time.Minute)
+		} else {
+			backoff = time.Second
+		}
+	}
+}
+
+func (c *SlackClient) openConnection(ctx context.Context) (string, error) {
+	var resp struct {
+		URL string `json:"url"`
+	}
+	if err := c.call(ctx, c.appToken, "apps.connections.open", map[string]any{}, &resp); err != nil {
+		return "", err
+	}
+	return resp.URL, nil
+}
+
+// serveSocket returns nil when slack asks the client to reconnect
+func (c *SlackClient) serveSocket() error {
+	wsURL, err := c.openConnection(c.ctx)
+	if err != nil {
+		return err
+	}
+	conn, _, err := websocket.DefaultDialer.DialContext(c.ctx, wsURL, nil)
+	if err != nil {
+		return fmt.Errorf("dial socket mode url failed: %w", err)
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-c.ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		var envelope socketEnvelope
+		if err := conn.ReadJSON(&envelope); err != nil {
+			return err
+		}
+		// acknowledge first, slack redelivers envelopes that are not acknowledged within 3 seconds
+		if envelope.EnvelopeID != "" {
+			if err := conn.WriteJSON(map[string]string{"envelope_id": envelope.EnvelopeID}); err != nil {
+				return err
+			}
+		}
+		switch envelope.Type {
+		case "hello":
+			c.logger.Info("slack socket mode connected")
+		case "disconnect":
+			c.logger.Info("slack socket mode disconnect requested", log.String("reason", envelope.Reason))
+			return nil
+		case "events_api":
+			var callback eventCallback
+			if err := json.Unmarshal(envelope.Payload, &callback); err != nil {
+				c.logger.Error("failed to unmarshal slack event", log.Error(err))
+				continue
+			}
+			if callback.Type == "event_callback" {
+				c.handleEventCallback(&callback)
+			}
+		case "interactive":
+			c.handleInteractive(envelope.Payload)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package teams
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultOpenIDURL = "https://login.botframework.com/v1/.well-known/openidconfiguration"
+	// tokens sent by the Bot Connector service
+	botFrameworkIssuer = "https://api.botframework.com"
+	// signing keys are rotated, refresh them at least once a day
+	keysTTL = 24 * time.Hour
+)
+
+var ErrUnauthorized = errors.New("invalid bot framework token")
+
+type jsonWebKey struct {
+	Kty          string   `json:"kty"`
+	Kid          string   `json:"kid"`
+	N            string   `json:"n"`
+	E            string   `json:"e"`
+	Endorsements []string `json:"endorsements"`
+}
+
+type signingKey struct {
+	key          *rsa.PublicKey
+	endorsements []string
+}
+
+// keySet caches the signing keys published in the Bot Framework OpenID metadata
+type keySet struct {
+	openIDURL  string
+	httpClient *http.Client
+	mu         sync.Mutex
+	keys       map[string]signingKey
+	fetchedAt  time.Time
+}
+
+func (s *keySet) get(ctx context.Context, kid string) (signingKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok := s.keys[kid]; ok && time.Since(s.fetchedAt) < keysTTL {
+		return key, nil
+	}
+	// unknown key ids trigger a refresh, but not more than once a minute
+	if time.Since(s.fetchedAt) > time.Minute {
+		if err := s.refresh(ctx); err != nil {
+			return signingKey{}, err
+		}
+	}
+	key, ok := s.keys[kid]
+	if !ok {
+		return signingKey{}, fmt.Errorf("%w: unknown signing key %s", ErrUnauthorized, kid)
+	}
+	return key, nil
+}
+
+func (s *keySet) refresh(ctx context.Context) error {
+	var metadata struct {
+		JwksURI string `json:"jwks_uri"`
+	}
+	if err := s.getJSON(ctx, s.openIDURL, &metadata); err != nil {
+		return fmt.Errorf("get openid metadata failed: %w", err)
+	}
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := s.getJSON(ctx, metadata.JwksURI, &jwks); err != nil {
+		return fmt.Errorf("get signing keys failed: %w", err)
+	}
+	keys := make(map[string]signingKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = signingKey{
+			key:          &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())},
+			endorsements: k.Endorsements,
+		}
+	}
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	return nil
+}
+
+func (s *keySet) getJSON(ctx context.Context, rawURL string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+type connectorClaims struct {
+	ServiceURL string `json:"serviceurl"`
+	jwt.RegisteredClaims
+}
+
+// validateToken checks the bearer token of an incoming activity as described in
+// https://learn.microsoft.com/azure/bot-service/rest-api/bot-framework-rest-connector-authentication
+func (c *TeamsClient) validateToken(ctx context.Context, authHeader string, activity *Activity) error {
+	tokenString, ok := strings.CutPrefix(authHeader, "Bearer ")
+	if !ok || tokenString == "" {
+		return fmt.Errorf("%w: missing bearer token", ErrUnauthorized)
+	}
+	var endorsements []string
+	claims := &connectorClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := c.keys.get(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		endorsements = key.endorsements
+		return key.key, nil
+	},
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(botFrameworkIssuer),
+		jwt.WithAudience(c.appID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(5*time.Minute),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+	// the key must be endorsed for the channel the activity comes from
+	if len(endorsements) > 0 && !slices.Contains(endorsements, activity.ChannelID) {
+		return fmt.Errorf("%w: key is not endorsed for channel %s", ErrUnauthorized, activity.ChannelID)
+	}
+	// replies are posted to the service url, it must be the one the token was issued for
+	if claims.ServiceURL == "" || strings.TrimRight(claims.ServiceURL, "/") != strings.TrimRight(activity.ServiceURL, "/") {
+		return fmt.Errorf("%w: service url mismatch", ErrUnauthorized)
+	}
+	return nil
+}
+
+// accessToken returns a cached Bot Connector token obtained with the app credentials
+func (c *TeamsClient) accessToken(ctx context.Context) (string, error) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	if c.token != "" && time.Now().Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {c.appID},
+		"client_secret": {c.appPassword},
+		"scope":         {"https://api.botframework.com/.default"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("get teams access token failed: %w", err)
+	}
+	defer resp.Body.Close()
+	var tokenResp struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("get teams access token failed: status %d", resp.StatusCode)
+	}
+	if tokenResp.AccessToken == "" {
+		return "", fmt.Errorf("get teams access token failed: %s %s", tokenResp.Error, tokenResp.ErrorDescription)
+	}
+	c.token = tokenResp.AccessToken
+	// renew the token a few minutes before it expires
+	c.tokenExpiry = time.Now().Add(time.Duration(tokenResp.ExpiresIn)*time.Second - 5*time.Minute)
+	return c.token, nil
+}
@@ -0,0 +1,498 @@
+package teams
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/bot"
+	"github.com/chaitin/panda-wiki/pkg/bot/render"
+)
+
+const (
+	defaultTenant   = "botframework.com"
+	tokenURLPattern = "https://login.microsoftonline.com/%s/oauth2/v2.0/token"
+
+	feedbackAction = "pandawiki_feedback"
+
+	adaptiveCardContentType     = "application/vnd.microsoft.card.adaptive"
+	fileDownloadInfoContentType = "application/vnd.microsoft.teams.file.download.info"
+	// answers can be rated for a day, the card is rendered again without the actions then
+	answerTTL = 24 * time.Hour
+)
+
+var mentionRe = regexp.MustCompile(`<at[^>]*>.*?</at>`)
+
+type Option func(*TeamsClient)
+
+// WithOpenIDURL points token validation to another OpenID metadata url, e.g. a local stub server
+func WithOpenIDURL(openIDURL string) Option {
+	return func(c *TeamsClient) {
+		c.keys.openIDURL = openIDURL
+	}
+}
+
+// WithTokenURL points the client credentials flow to another token endpoint
+func WithTokenURL(tokenURL string) Option {
+	return func(c *TeamsClient) {
+		c.tokenURL = tokenURL
+	}
+}
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *TeamsClient) {
+		c.httpClient = client
+		c.keys.httpClient = client
+	}
+}
+
+// TeamsClient implements the Bot Framework messaging endpoint for Microsoft Teams,
+// activities are pushed to HandleActivity and answered through the Bot Connector api
+type TeamsClient struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
+	appID       string
+	appPassword string
+	tokenURL    string
+	httpClient  *http.Client
+	keys        *keySet
+	logger      *log.Logger
+	msgMap      sync.Map
+	answers     sync.Map
+	getQA       bot.GetQAFun
+	feedback    bot.FeedbackFun
+
+	tokenMu     sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+func NewTeamsClient(ctx context.Context, cancel context.CancelFunc, appID, appPassword, tenantID string, logger *log.Logger, getQA bot.GetQAFun, feedback bot.FeedbackFun, opts ...Option) *TeamsClient {
+	if tenantID == "" {
+		tenantID = defaultTenant
+	}
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	c := &TeamsClient{
+		ctx:         ctx,
+		cancel:      cancel,
+		appID:       appID,
+		appPassword: appPassword,
+		tokenURL:    fmt.Sprintf(tokenURLPattern, tenantID),
+		httpClient:  httpClient,
+		keys:        &keySet{openIDURL: defaultOpenIDURL, httpClient: httpClient},
+		logger:      logger.WithModule("bot.teams"),
+		getQA:       getQA,
+		feedback:    feedback,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	go func() {
+		ticker := time.NewTicker(1 * time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				c.msgMap.Range(func(key, value any) bool {
+					// remove activity id if it is older than 5 minutes
+					if time.Now().Unix()-value.(int64) > 5*60 {
+						c.msgMap.Delete(key)
+					}
+					return true
+				})
+				c.answers.Range(func(key, value any) bool {
+					if time.Since(value.(*sentAnswer).at) > answerTTL {
+						c.answers.Delete(key)
+					}
+					return true
+				})
+			}
+		}
+	}()
+	return c
+}
+
+func (c *TeamsClient) Stop() {
+	c.cancel()
+}
+
+type ChannelAccount struct {
+	ID          string `json:"id"`
+	Name        string `json:"name,omitempty"`
+	AADObjectID string `json:"aadObjectId,omitempty"`
+}
+
+type ConversationAccount struct {
+	ID               string `json:"id"`
+	ConversationType string `json:"conversationType,omitempty"`
+	TenantID         string `json:"tenantId,omitempty"`
+}
+
+type Attachment struct {
+	ContentType string `json:"contentType"`
+	ContentURL  string `json:"contentUrl,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Content     any    `json:"content,omitempty"`
+}
+
+// fileDownloadInfo is the content of a file sent to the bot in a personal chat
+type fileDownloadInfo struct {
+	DownloadURL string `json:"downloadUrl"`
+	FileType    string `json:"fileType"`
+}
+
+type Activity struct {
+	Type         string              `json:"type"`
+	ID           string              `json:"id,omitempty"`
+	Timestamp    string              `json:"timestamp,omitempty"`
+	ServiceURL   string              `json:"serviceUrl,omitempty"`
+	ChannelID    string              `json:"channelId,omitempty"`
+	From         ChannelAccount      `json:"from"`
+	Conversation ConversationAccount `json:"conversation"`
+	Recipient    ChannelAccount      `json:"recipient"`
+	Text         string              `json:"text,omitempty"`
+	TextFormat   string              `json:"textFormat,omitempty"`
+	ReplyToID    string              `json:"replyToId,omitempty"`
+	Attachments  []Attachment        `json:"attachments,omitempty"`
+	Value        json.RawMessage     `json:"value,omitempty"`
+	ChannelData  json.RawMessage     `json:"channelData,omitempty"`
+	Entities     []map[string]any    `json:"entities,omitempty"`
+}
+
+// sentAnswer is kept to render the card again once it has been rated
+type sentAnswer struct {
+	answer  string
+	sources []bot.Source
+	at      time.Time
+}
+
+type feedbackValue struct {
+	Action    string           `json:"action"`
+	MessageID string           `json:"message_id"`
+	Score     domain.ScoreType `json:"score"`
+}
+
+// HandleActivity serves the messaging endpoint, it returns the status code to reply with.
+// Activities are processed asynchronously, the connector only waits for the acknowledgement.
+func (c *TeamsClient) HandleActivity(ctx context.Context, authHeader string, body []byte) int {
+	var activity Activity
+	if err := json.Unmarshal(body, &activity); err != nil {
+		return http.StatusBadRequest
+	}
+	if err := c.validateToken(ctx, authHeader, &activity); err != nil {
+		c.logger.Warn("reject teams activity", log.Error(err))
+		return http.StatusUnauthorized
+	}
+	if activity.ID != "" {
+		if _, loaded := c.msgMap.LoadOrStore(activity.ID, time.Now().Unix()); loaded {
+			return http.StatusOK
+		}
+	}
+	if activity.Type != "message" {
+		return http.StatusOK
+	}
+
+	// Action.Submit sends the card data as the value of a message activity
+	if len(activity.Value) > 0 {
+		var value feedbackValue
+		if err := json.Unmarshal(activity.Value, &value); err == nil && value.Action == feedbackAction {
+			go c.handleFeedback(&activity, &value)
+		}
+		return http.StatusOK
+	}
+
+	question := strings.TrimSpace(mentionRe.ReplaceAllString(activity.Text, ""))
+	if question == "" && !hasFiles(activity.Attachments) {
+		return http.StatusOK
+	}
+	info := domain.ConversationInfo{
+		UserInfo: domain.UserInfo{
+			UserID:   activity.From.ID,
+			NickName: activity.From.Name,
+			RealName: activity.From.Name,
+		},
+	}
+	if activity.From.AADObjectID != "" {
+		info.UserInfo.UserID = activity.From.AADObjectID
+	}
+	// teams only delivers channel and group chat messages that mention the bot
+	if activity.Conversation.ConversationType == "personal" {
+		info.UserInfo.From = domain.MessageFromPrivate
+	} else {
+		info.UserInfo.From = domain.MessageFromGroup
+	}
+	c.logger.Info("received message from teams bot", log.String("conversation_id", activity.Conversation.ID), log.String("activity_id", activity.ID))
+
+	// replies in a channel thread carry the root message in the conversation id
+	chat, thread, _ := strings.Cut(activity.Conversation.ID, ";messageid=")
+	key := &bot.ConversationKey{
+		Chat:    chat,
+		User:    info.UserInfo.UserID,
+		Thread:  thread,
+		ReplyTo: activity.ReplyToID,
+	}
+	go func() {
+		ctx := bot.WithConversationKey(c.ctx, key)
+		if attachments := c.downloadAttachments(ctx, activity.Attachments); len(attachments) > 0 {
+			ctx = bot.WithAttachments(ctx, attachments)
+			if question == "" {
+				question = bot.DefaultAttachmentQuestion
+			}
+		}
+		if question == "" {
+			return
+		}
+		c.answer(ctx, &activity, question, info)
+	}()
+	return http.StatusOK
+}
+
+func hasFiles(attachments []Attachment) bool {
+	for _, a := range attachments {
+		if a.ContentType == fileDownloadInfoContentType || (strings.HasPrefix(a.ContentType, "image/") && a.ContentURL != "") {
+			return true
+		}
+	}
+	return false
+}
+
+// downloadAttachments fetches the images and files of the activity, inline images are
+// served by the connector and need the bot token while file download urls are pre-signed
+func (c *TeamsClient) downloadAttachments(ctx context.Context, attachments []Attachment) []bot.Attachment {
+	var files []bot.Attachment
+	for _, a := range attachments {
+		var (
+			req *http.Request
+			err error
+		)
+		switch {
+		case a.ContentType == fileDownloadInfoContentType:
+			var info fileDownloadInfo
+			raw, _ := json.Marshal(a.Content)
+			if err = json.Unmarshal(raw, &info); err != nil || info.DownloadURL == "" {
+				continue
+			}
+			req, err = http.NewRequestWithContext(ctx, http.MethodGet, info.DownloadURL, nil)
+		case strings.HasPrefix(a.ContentType, "image/") && a.ContentURL != "":
+			req, err = http.NewRequestWithContext(ctx, http.MethodGet, a.ContentURL, nil)
+			if err == nil {
+				var token string
+				if token, err = c.accessToken(ctx); err == nil {
+					req.Header.Set("Authorization", "Bearer "+token)
+				}
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			c.logger.Warn("failed to download teams attachment", log.String("name", a.Name), log.Error(err))
+			continue
+		}
+		name := a.Name
+		if name == "" {
+			name = "image"
+		}
+		file, err := bot.DownloadAttachment(c.httpClient, req, name)
+		if err != nil {
+			c.logger.Warn("failed to download teams attachment", log.String("name", name), log.Error(err))
+			continue
+		}
+		files = append(files, *file)
+	}
+	return files
+}
+
+// call invokes a Bot Connector api relative to the service url of the activity
+func (c *TeamsClient) call(ctx context.Context, method, serviceURL, path string, body any, out any) error {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(serviceURL, "/")+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("teams %s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+	respData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("teams %s %s failed: status %d: %s", method, path, resp.StatusCode, string(respData))
+	}
+	if out != nil && len(respData) > 0 {
+		return json.Unmarshal(respData, out)
+	}
+	return nil
+}
+
+// reply posts an activity into the conversation of the incoming activity
+func (c *TeamsClient) reply(ctx context.Context, in *Activity, out *Activity) (string, error) {
+	out.From = in.Recipient
+	out.Recipient = in.From
+	out.Conversation = in.Conversation
+	out.ReplyToID = in.ID
+	path := fmt.Sprintf("/v3/conversations/%s/activities/%s", url.PathEscape(in.Conversation.ID), url.PathEscape(in.ID))
+	var resp struct {
+		ID string `json:"id"`
+	}
+	if err := c.call(ctx, http.MethodPost, in.ServiceURL, path, out, &resp); err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+// update replaces a previously sent activity
+func (c *TeamsClient) update(ctx context.Context, in *Activity, activityID string, out *Activity) error {
+	out.ID = activityID
+	out.From = in.Recipient
+	out.Recipient = in.From
+	out.Conversation = in.Conversation
+	path := fmt.Sprintf("/v3/conversations/%s/activities/%s", url.PathEscape(in.Conversation.ID), url.PathEscape(activityID))
+	return c.call(ctx, http.MethodPut, in.ServiceURL, path, out, nil)
+}
+
+// answerCard renders the answer as an Adaptive Card with the referenced documents,
+// followed by the feedback actions when messageID is set, or by note otherwise
+func answerCard(answer string, sources []bot.Source, messageID, note string) *Activity {
+	body := render.Teams.Card(render.Answer{Markdown: answer, Sources: sources})
+	if len(body) == 0 {
+		body = []any{render.TextBlock("抱歉，没有找到相关的答案", nil)}
+	}
+	card := map[string]any{
+		"type":    "AdaptiveCard",
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"version": render.CardVersion,
+		"msteams": map[string]any{"width": "Full"},
+		"body":    body,
+	}
+	if note != "" {
+		card["body"] = append(body, render.TextBlock(note, map[string]any{"isSubtle": true, "size": "Small", "separator": true}))
+	}
+	if messageID != "" {
+		card["body"] = append(body, render.TextBlock("本回答由 PandaWiki 基于 AI 生成，仅供参考。", map[string]any{"isSubtle": true, "size": "Small", "separator": true}))
+		card["actions"] = []any{
+			map[string]any{"type": "Action.Submit", "title": "👍 满意", "data": feedbackValue{Action: feedbackAction, MessageID: messageID, Score: domain.Like}},
+			map[string]any{"type": "Action.Submit", "title": "👎 不满意", "data": feedbackValue{Action: feedbackAction, MessageID: messageID, Score: domain.DisLike}},
+		}
+	}
+	return &Activity{
+		Type:        "message",
+		Attachments: []Attachment{{ContentType: adaptiveCardContentType, Content: card}},
+	}
+}
+
+// answer shows the typing indicator while the answer is generated and replies with an Adaptive Card
+func (c *TeamsClient) answer(ctx context.Context, in *Activity, question string, info domain.ConversationInfo) {
+	if _, err := c.reply(ctx, in, &Activity{Type: "typing"}); err != nil {
+		c.logger.Warn("failed to send typing activity", log.Error(err))
+	}
+
+	var (
+		messageID string
+		sources   []bot.Source
+	)
+	qaCtx := bot.WithFeedbackHook(ctx, func(id string) {
+		messageID = id
+	})
+	qaCtx = bot.WithSourcesHook(qaCtx, func(s []bot.Source) {
+		sources = s
+	})
+	info.ReplyRoute = map[string]string{
+		"service_url":     in.ServiceURL,
+		"conversation_id": in.Conversation.ID,
+		"activity_id":     in.ID,
+		"bot_id":          in.Recipient.ID,
+		"user_id":         in.From.ID,
+	}
+	answerCh, err := c.getQA(qaCtx, question, info, "")
+	if err != nil {
+		c.logger.Error("teams client failed to get answer", log.Error(err))
+		if _, err := c.reply(ctx, in, &Activity{Type: "message", Text: "出错了，请稍后再试"}); err != nil {
+			c.logger.Error("failed to send message", log.Error(err))
+		}
+		return
+	}
+	var sb strings.Builder
+	for chunk := range answerCh {
+		sb.WriteString(chunk)
+	}
+	answer := strings.TrimSpace(sb.String())
+	if answer == "" {
+		answer = "抱歉，没有找到相关的答案"
+	}
+	// messageID and sources are set by the hooks before the answer channel is closed
+	activityID, err := c.reply(ctx, in, answerCard(answer, sources, messageID, ""))
+	if err != nil {
+		c.logger.Error("failed to send answer card", log.Error(err))
+		return
+	}
+	bot.ConversationKeyFromContext(ctx).BindMessage(activityID)
+	if messageID != "" && activityID != "" {
+		c.answers.Store(activityID, &sentAnswer{answer: answer, sources: sources, at: time.Now()})
+	}
+}
+
+// handleFeedback records the vote and replaces the card actions with a thank-you note
+func (c *TeamsClient) handleFeedback(in *Activity, value *feedbackValue) {
+	ctx, cancel := context.WithTimeout(c.ctx, 30*time.Second)
+	defer cancel()
+	if c.feedback == nil {
+		return
+	}
+	note := "感谢您的反馈"
+	if err := c.feedback(ctx, value.MessageID, value.Score); err != nil {
+		c.logger.Warn("failed to save teams feedback", log.String("message_id", value.MessageID), log.Error(err))
+		note = "您已经评价过该回答"
+	}
+	// replyToId of the submit activity is the id of the card
+	if sent, ok := c.answers.LoadAndDelete(in.ReplyToID); ok && in.ReplyToID != "" {
+		answer := sent.(*sentAnswer)
+		if err := c.update(ctx, in, in.ReplyToID, answerCard(answer.answer, answer.sources, "", note)); err != nil {
+			c.logger.Warn("failed to update answer card", log.Error(err))
+		}
+		return
+	}
+	if _, err := c.reply(ctx, in, &Activity{Type: "message", Text: note}); err != nil {
+		c.logger.Warn("failed to send feedback note", log.Error(err))
+	}
+}
+
+// SendHumanReply replies to the question activity with the message of a human agent
+func (c *TeamsClient) SendHumanReply(ctx context.Context, route map[string]string, content string) error {
+	if route["service_url"] == "" || route["conversation_id"] == "" {
+		return errors.New("teams reply route has no conversation")
+	}
+	in := &Activity{
+		ID:           route["activity_id"],
+		ServiceURL:   route["service_url"],
+		From:         ChannelAccount{ID: route["user_id"]},
+		Recipient:    ChannelAccount{ID: route["bot_id"]},
+		Conversation: ConversationAccount{ID: route["conversation_id"]},
+	}
+	_, err := c.reply(ctx, in, &Activity{Type: "message", TextFormat: "markdown", Text: "**人工回复**\n\n" + render.Teams.Preview(content)})
+	return err
+}
@@ -0,0 +1,202 @@
+package teams
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/chaitin/panda-wiki/config"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/bot"
+)
+
+const testAppID = "app-id"
+
+type call struct {
+	method string
+	path   string
+	body   map[string]any
+}
+
+// stubFramework serves the openid metadata, the token endpoint and the connector api
+type stubFramework struct {
+	key   *rsa.PrivateKey
+	url   string
+	mu    sync.Mutex
+	calls []call
+}
+
+func (s *stubFramework) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/openid":
+		_ = json.NewEncoder(w).Encode(map[string]string{"jwks_uri": s.url + "/keys"})
+	case "/keys":
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []any{map[string]any{
+			"kty":          "RSA",
+			"kid":          "k1",
+			"n":            base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+			"e":            base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+			"endorsements": []string{"msteams"},
+		}}})
+	case "/token":
+		_, _ = w.Write([]byte(`{"access_token":"connector-token","expires_in":3600}`))
+	default:
+		if r.Header.Get("Authorization") != "Bearer connector-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body := map[string]any{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		s.mu.Lock()
+		s.calls = append(s.calls, call{method: r.Method, path: r.URL.Path, body: body})
+		s.mu.Unlock()
+		_, _ = w.Write([]byte(`{"id":"card-1"}`))
+	}
+}
+
+func (s *stubFramework) connectorCalls() []call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]call(nil), s.calls...)
+}
+
+func (s *stubFramework) sign(t *testing.T, aud, serviceURL string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":        botFrameworkIssuer,
+		"aud":        aud,
+		"exp":        time.Now().Add(time.Hour).Unix(),
+		"serviceurl": serviceURL,
+	})
+	token.Header["kid"] = "k1"
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "Bearer " + signed
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTeamsActivity(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stub := &stubFramework{key: key}
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+	stub.url = srv.URL
+
+	var (
+		mu    sync.Mutex
+		votes []domain.ScoreType
+	)
+	getQA := func(ctx context.Context, msg string, info domain.ConversationInfo, conversationID string) (chan string, error) {
+		if msg != "how to deploy" || info.UserInfo.From != domain.MessageFromGroup {
+			t.Errorf("unexpected question %q from %d", msg, info.UserInfo.From)
+		}
+		ch := make(chan string, 1)
+		ch <- "Use docker compose."
+		bot.SourcesHookFromContext(ctx)([]bot.Source{{Title: "Install", URL: "https://wiki.example.com/node/1"}})
+		bot.FeedbackHookFromContext(ctx)("msg-1")
+		close(ch)
+		return ch, nil
+	}
+	feedback := func(ctx context.Context, messageID string, score domain.ScoreType) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if messageID == "msg-1" {
+			votes = append(votes, score)
+		}
+		return nil
+	}
+	cfg, _ := config.NewConfig()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := NewTeamsClient(ctx, cancel, testAppID, "secret", "", log.NewLogger(cfg), getQA, feedback,
+		WithOpenIDURL(srv.URL+"/openid"), WithTokenURL(srv.URL+"/token"))
+
+	activity, _ := json.Marshal(map[string]any{
+		"type":         "message",
+		"id":           "act-1",
+		"serviceUrl":   srv.URL,
+		"channelId":    "msteams",
+		"from":         map[string]any{"id": "29:user", "name": "Ann"},
+		"recipient":    map[string]any{"id": "28:bot", "name": "PandaWiki"},
+		"conversation": map[string]any{"id": "19:conv", "conversationType": "channel"},
+		"text":         "<at>PandaWiki</at> how to deploy",
+	})
+
+	for name, auth := range map[string]string{
+		"missing token":    "",
+		"wrong audience":   stub.sign(t, "other-app", srv.URL),
+		"wrong serviceurl": stub.sign(t, testAppID, "https://evil.example.com"),
+	} {
+		if status := c.HandleActivity(ctx, auth, activity); status != http.StatusUnauthorized {
+			t.Fatalf("%s: status = %d, want 401", name, status)
+		}
+	}
+
+	if status := c.HandleActivity(ctx, stub.sign(t, testAppID, srv.URL), activity); status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	waitFor(t, func() bool { return len(stub.connectorCalls()) == 2 })
+	calls := stub.connectorCalls()
+	if calls[0].body["type"] != "typing" || calls[1].path != "/v3/conversations/19:conv/activities/act-1" {
+		t.Fatalf("connector calls = %+v", calls)
+	}
+	card, _ := json.Marshal(calls[1].body["attachments"])
+	for _, want := range []string{"Use docker compose.", "https://wiki.example.com/node/1", "Action.Submit", `"message_id":"msg-1"`} {
+		if !strings.Contains(string(card), want) {
+			t.Fatalf("card %s does not contain %s", card, want)
+		}
+	}
+
+	submit, _ := json.Marshal(map[string]any{
+		"type":         "message",
+		"id":           "act-2",
+		"serviceUrl":   srv.URL,
+		"channelId":    "msteams",
+		"replyToId":    "card-1",
+		"from":         map[string]any{"id": "29:user"},
+		"recipient":    map[string]any{"id": "28:bot"},
+		"conversation": map[string]any{"id": "19:conv"},
+		"value":        map[string]any{"action": feedbackAction, "message_id": "msg-1", "score": domain.Like},
+	})
+	if status := c.HandleActivity(ctx, stub.sign(t, testAppID, srv.URL), submit); status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	waitFor(t, func() bool { return len(stub.connectorCalls()) == 3 })
+	mu.Lock()
+	if len(votes) != 1 || votes[0] != domain.Like {
+		t.Fatalf("votes = %v", votes)
+	}
+	mu.Unlock()
+	update := stub.connectorCalls()[2]
+	card, _ = json.Marshal(update.body["attachments"])
+	if update.method != http.MethodPut || update.path != "/v3/conversations/19:conv/activities/card-1" || strings.Contains(string(card), "Action.Submit") {
+		t.Fatalf("card update = %s %s %s", update.method, update.path, card)
+	}
+}
@@ -0,0 +1,640 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf16"
+
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/bot"
+	"github.com/chaitin/panda-wiki/pkg/bot/render"
+)
+
+const (
+	defaultAPIURL = "https://api.telegram.org"
+
+	secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+	// editMessageText is rate limited, answers are flushed at most once per interval
+	updateInterval = 1500 * time.Millisecond
+	pollTimeout    = 30
+)
+
+var ErrInvalidSecretToken = errors.New("invalid telegram secret token")
+
+// ErrSecretTokenRequired webhook requests can only be verified with the secret token
+var ErrSecretTokenRequired = errors.New("telegram webhook mode requires a secret token")
+
+// plainText is sent while the answer is streamed and when the markdown is rejected
+var plainText = render.Telegram.WithFormat(render.FormatText)
+
+type Option func(*TelegramClient)
+
+// WithAPIURL points the client to another Bot API server, e.g. a local stub server
+func WithAPIURL(apiURL string) Option {
+	return func(c *TelegramClient) {
+		c.apiURL = strings.TrimRight(apiURL, "/")
+	}
+}
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *TelegramClient) {
+		c.httpClient = client
+	}
+}
+
+// TelegramClient answers private chats and group mentions.
+// Updates are pushed to HandleWebhook when a webhook url is configured,
+// otherwise they are fetched by long polling.
+type TelegramClient struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
+	token       string
+	webhookURL  string
+	secretToken string
+	apiURL      string
+	httpClient  *http.Client
+	logger      *log.Logger
+	msgMap      sync.Map
+	getQA       bot.GetQAFun
+	me          atomic.Pointer[User]
+}
+
+func NewTelegramClient(ctx context.Context, cancel context.CancelFunc, token, webhookURL, secretToken string, logger *log.Logger, getQA bot.GetQAFun, opts ...Option) *TelegramClient {
+	c := &TelegramClient{
+		ctx:         ctx,
+		cancel:      cancel,
+		token:       token,
+		webhookURL:  webhookURL,
+		secretToken: secretToken,
+		apiURL:      defaultAPIURL,
+		httpClient:  &http.Client{Timeout: (pollTimeout + 30) * time.Second},
+		logger:      logger.WithModule("bot.telegram"),
+		getQA:       getQA,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	go func() {
+		ticker := time.NewTicker(1 * time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				c.msgMap.Range(func(key, value any) bool {
+					// remove update id if it is older than 5 minutes
+					if time.Now().Unix()-value.(int64) > 5*60 {
+						c.msgMap.Delete(key)
+					}
+					return true
+				})
+			}
+		}
+	}()
+	return c
+}
+
+// Start registers the webhook or runs long polling, it blocks until the client is stopped
+func (c *TelegramClient) Start() error {
+	if c.webhookURL != "" && c.secretToken == "" {
+		return ErrSecretTokenRequired
+	}
+	var me User
+	if err := c.call(c.ctx, "getMe", map[string]any{}, &me); err != nil {
+		return err
+	}
+	c.me.Store(&me)
+
+	if c.webhookURL != "" {
+		if err := c.call(c.ctx, "setWebhook", map[string]any{
+			"url":             c.webhookURL,
+			"secret_token":    c.secretToken,
+			"allowed_updates": []string{"message"},
+		}, nil); err != nil {
+			return err
+		}
+		c.logger.Info("telegram bot client initialized (webhook mode)", log.String("username", me.Username))
+		<-c.ctx.Done()
+		return nil
+	}
+
+	// getUpdates does not work while a webhook is set
+	if err := c.call(c.ctx, "deleteWebhook", map[string]any{}, nil); err != nil {
+		return err
+	}
+	c.logger.Info("telegram bot client initialized (polling mode)", log.String("username", me.Username))
+	return c.poll()
+}
+
+func (c *TelegramClient) Stop() {
+	c.cancel()
+}
+
+// Webhook reports whether updates are pushed to HandleWebhook
+func (c *TelegramClient) Webhook() bool {
+	return c.webhookURL != ""
+}
+
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+// call invokes a Bot API method and decodes its result into out
+func (c *TelegramClient) call(ctx context.Context, method string, body any, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+"/bot"+c.token+"/"+method, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// the token is part of the url, do not leak it into the logs
+		var urlErr interface{ Unwrap() error }
+		if errors.As(err, &urlErr) {
+			err = urlErr.Unwrap()
+		}
+		return fmt.Errorf("telegram %s failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+	respData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var apiResp apiResponse
+	if err := json.Unmarshal(respData, &apiResp); err != nil {
+		return fmt.Errorf("telegram %s returned invalid body: status %d", method, resp.StatusCode)
+	}
+	if !apiResp.OK {
+		return &APIError{Method: method, Code: apiResp.ErrorCode, Description: apiResp.Description, RetryAfter: apiResp.Parameters.RetryAfter}
+	}
+	if out != nil {
+		return json.Unmarshal(apiResp.Result, out)
+	}
+	return nil
+}
+
+type APIError struct {
+	Method      string
+	Code        int
+	Description string
+	RetryAfter  int
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram %s failed: %d %s", e.Method, e.Code, e.Description)
+}
+
+// poll fetches updates until the client is stopped
+func (c *TelegramClient) poll() error {
+	var offset int64
+	backoff := time.Second
+	for {
+		var updates []Update
+		err := c.call(c.ctx, "getUpdates", map[string]any{
+			"offset":          offset,
+			"timeout":         pollTimeout,
+			"allowed_updates": []string{"message"},
+		}, &updates)
+		if c.ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			c.logger.Warn("telegram get updates failed", log.Error(err), log.Any("retry_in", backoff))
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+				backoff = time.Duration(apiErr.RetryAfter) * time.Second
+			}
+			select {
+			case <-c.ctx.Done():
+				return nil
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, time.Minute)
+			continue
+		}
+		backoff = time.Second
+		for i := range updates {
+			offset = max(offset, updates[i].UpdateID+1)
+			c.handleUpdate(&updates[i])
+		}
+	}
+}
+
+// HandleWebhook serves the webhook url, it returns the status code to reply with.
+// Requests are rejected when no secret token is configured, e.g. in polling mode
+func (c *TelegramClient) HandleWebhook(header http.Header, body []byte) int {
+	if c.secretToken == "" || subtle.ConstantTimeCompare([]byte(header.Get(secretTokenHeader)), []byte(c.secretToken)) != 1 {
+		c.logger.Warn("reject telegram request", log.Error(ErrInvalidSecretToken))
+		return http.StatusUnauthorized
+	}
+	// telegram redelivers the update until the bot is ready
+	if c.me.Load() == nil {
+		return http.StatusServiceUnavailable
+	}
+	var update Update
+	if err := json.Unmarshal(body, &update); err != nil {
+		return http.StatusBadRequest
+	}
+	c.handleUpdate(&update)
+	return http.StatusOK
+}
+
+type User struct {
+	ID        int64  `json:"id"`
+	IsBot     bool   `json:"is_bot"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Username  string `json:"username"`
+}
+
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+type MessageEntity struct {
+	Type   string `json:"type"`
+	Offset int    `json:"offset"`
+	Length int    `json:"length"`
+}
+
+type PhotoSize struct {
+	FileID   string `json:"file_id"`
+	FileSize int64  `json:"file_size"`
+}
+
+type Document struct {
+	FileID   string `json:"file_id"`
+	FileName string `json:"file_name"`
+	MimeType string `json:"mime_type"`
+	FileSize int64  `json:"file_size"`
+}
+
+type File struct {
+	FileID   string `json:"file_id"`
+	FilePath string `json:"file_path"`
+}
+
+type Message struct {
+	MessageID       int64           `json:"message_id"`
+	MessageThreadID int64           `json:"message_thread_id"`
+	From            *User           `json:"from"`
+	Chat            Chat            `json:"chat"`
+	Text            string          `json:"text"`
+	Entities        []MessageEntity `json:"entities"`
+	ReplyToMessage  *Message        `json:"reply_to_message"`
+	// media messages carry their text in the caption
+	Caption         string          `json:"caption"`
+	CaptionEntities []MessageEntity `json:"caption_entities"`
+	Photo           []PhotoSize     `json:"photo"`
+	Document        *Document       `json:"document"`
+}
+
+func (m *Message) hasMedia() bool {
+	return len(m.Photo) > 0 || m.Document != nil
+}
+
+type Update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *Message `json:"message"`
+}
+
+func (c *TelegramClient) handleUpdate(update *Update) {
+	if _, loaded := c.msgMap.LoadOrStore(update.UpdateID, time.Now().Unix()); loaded {
+		return
+	}
+	msg := update.Message
+	if msg == nil || msg.From == nil || msg.From.IsBot {
+		return
+	}
+	if msg.Text == "" {
+		msg.Text, msg.Entities = msg.Caption, msg.CaptionEntities
+	}
+	if msg.Text == "" && !msg.hasMedia() {
+		return
+	}
+	me := c.me.Load()
+	if me == nil {
+		return
+	}
+
+	info := domain.ConversationInfo{}
+	var question string
+	switch msg.Chat.Type {
+	case "private":
+		info.UserInfo.From = domain.MessageFromPrivate
+		question = stripBotAddress(msg, me, true)
+	case "group", "supergroup":
+		info.UserInfo.From = domain.MessageFromGroup
+		if !addressedToBot(msg, me) {
+			return
+		}
+		question = stripBotAddress(msg, me, false)
+	default:
+		return
+	}
+	c.logger.Info("received message from telegram bot", log.Int64("chat_id", msg.Chat.ID), log.Int64("message_id", msg.MessageID))
+
+	go func() {
+		// a question about a screenshot may be a reply to it
+		media := msg
+		if !media.hasMedia() && msg.ReplyToMessage != nil && msg.ReplyToMessage.hasMedia() {
+			media = msg.ReplyToMessage
+		}
+		ctx := c.ctx
+		if media.hasMedia() {
+			attachment, err := c.downloadMedia(c.ctx, media)
+			if err != nil {
+				c.logger.Warn("failed to download telegram media", log.Error(err))
+			} else {
+				ctx = bot.WithAttachments(ctx, []bot.Attachment{*attachment})
+				if question == "" {
+					question = bot.DefaultAttachmentQuestion
+				}
+			}
+		}
+		if question == "" {
+			if _, err := c.sendMessage(c.ctx, msg, "你好，请直接发送你的问题", ""); err != nil {
+				c.logger.Warn("failed to send welcome message", log.Error(err))
+			}
+			return
+		}
+		info.UserInfo.UserID = fmt.Sprintf("%d", msg.From.ID)
+		info.UserInfo.NickName = msg.From.Username
+		info.UserInfo.RealName = strings.TrimSpace(msg.From.FirstName + " " + msg.From.LastName)
+		if info.UserInfo.NickName == "" {
+			info.UserInfo.NickName = info.UserInfo.RealName
+		}
+		key := &bot.ConversationKey{
+			Chat: strconv.FormatInt(msg.Chat.ID, 10),
+			User: info.UserInfo.UserID,
+		}
+		if msg.MessageThreadID != 0 {
+			key.Thread = strconv.FormatInt(msg.MessageThreadID, 10)
+		}
+		if msg.ReplyToMessage != nil {
+			key.ReplyTo = strconv.FormatInt(msg.ReplyToMessage.MessageID, 10)
+		}
+		c.answer(bot.WithConversationKey(ctx, key), msg, question, info)
+	}()
+}
+
+// downloadMedia fetches the largest size of a photo or the document of a message
+func (c *TelegramClient) downloadMedia(ctx context.Context, msg *Message) (*bot.Attachment, error) {
+	fileID, name, size := "", "photo.jpg", int64(0)
+	if len(msg.Photo) > 0 {
+		// sizes are sorted from the smallest to the largest
+		photo := msg.Photo[len(msg.Photo)-1]
+		fileID, size = photo.FileID, photo.FileSize
+	} else {
+		fileID, name, size = msg.Document.FileID, msg.Document.FileName, msg.Document.FileSize
+	}
+	if size > bot.MaxAttachmentSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", name, bot.MaxAttachmentSize)
+	}
+	var file File
+	if err := c.call(ctx, "getFile", map[string]any{"file_id": fileID}, &file); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+"/file/bot"+c.token+"/"+file.FilePath, nil)
+	if err != nil {
+		return nil, err
+	}
+	attachment, err := bot.DownloadAttachment(c.httpClient, req, name)
+	if err != nil {
+		// the token is part of the url, do not leak it into the logs
+		return nil, fmt.Errorf("download telegram file %s failed", name)
+	}
+	if msg.Document != nil && msg.Document.MimeType != "" {
+		attachment.ContentType = msg.Document.MimeType
+	}
+	return attachment, nil
+}
+
+// entityText returns the text of an entity, offsets are counted in utf-16 code units
+func entityText(text []uint16, e MessageEntity) string {
+	if e.Offset < 0 || e.Offset+e.Length > len(text) {
+		return ""
+	}
+	return string(utf16.Decode(text[e.Offset : e.Offset+e.Length]))
+}
+
+// addressedToBot reports whether a group message mentions the bot, replies to it or is a command for it
+func addressedToBot(msg *Message, me *User) bool {
+	if msg.ReplyToMessage != nil && msg.ReplyToMessage.From != nil && msg.ReplyToMessage.From.ID == me.ID {
+		return true
+	}
+	text := utf16.Encode([]rune(msg.Text))
+	for _, e := range msg.Entities {
+		switch e.Type {
+		case "mention":
+			if strings.EqualFold(entityText(text, e), "@"+me.Username) {
+				return true
+			}
+		case "bot_command":
+			if e.Offset == 0 && strings.HasSuffix(strings.ToLower(entityText(text, e)), "@"+strings.ToLower(me.Username)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// stripBotAddress removes the bot mentions and a leading command from the message text
+func stripBotAddress(msg *Message, me *User, private bool) string {
+	text := utf16.Encode([]rune(msg.Text))
+	keep := make([]bool, len(text))
+	for i := range keep {
+		keep[i] = true
+	}
+	for _, e := range msg.Entities {
+		if e.Offset < 0 || e.Offset+e.Length > len(text) {
+			continue
+		}
+		drop := false
+		switch e.Type {
+		case "mention":
+			drop = strings.EqualFold(entityText(text, e), "@"+me.Username)
+		case "bot_command":
+			cmd := strings.ToLower(entityText(text, e))
+			drop = e.Offset == 0 && (private || strings.HasSuffix(cmd, "@"+strings.ToLower(me.Username)))
+		}
+		if drop {
+			for i := e.Offset; i < e.Offset+e.Length; i++ {
+				keep[i] = false
+			}
+		}
+	}
+	out := make([]uint16, 0, len(text))
+	for i, u := range text {
+		if keep[i] {
+			out = append(out, u)
+		}
+	}
+	return strings.TrimSpace(string(utf16.Decode(out)))
+}
+
+func (c *TelegramClient) sendMessage(ctx context.Context, replyTo *Message, text, parseMode string) (*Message, error) {
+	body := map[string]any{
+		"chat_id": replyTo.Chat.ID,
+		"text":    text,
+		"reply_parameters": map[string]any{
+			"message_id":                  replyTo.MessageID,
+			"allow_sending_without_reply": true,
+		},
+	}
+	if replyTo.MessageThreadID != 0 {
+		body["message_thread_id"] = replyTo.MessageThreadID
+	}
+	if parseMode != "" {
+		body["parse_mode"] = parseMode
+	}
+	var msg Message
+	if err := c.call(ctx, "sendMessage", body, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+func (c *TelegramClient) editMessage(ctx context.Context, msg *Message, text, parseMode string) error {
+	body := map[string]any{
+		"chat_id":    msg.Chat.ID,
+		"message_id": msg.MessageID,
+		"text":       text,
+		"link_preview_options": map[string]any{
+			"is_disabled": true,
+		},
+	}
+	if parseMode != "" {
+		body["parse_mode"] = parseMode
+	}
+	err := c.call(ctx, "editMessageText", body, nil)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && strings.Contains(apiErr.Description, "message is not modified") {
+		return nil
+	}
+	return err
+}
+
+// answer posts a placeholder reply and streams the answer into it by editing the message
+func (c *TelegramClient) answer(ctx context.Context, msg *Message, question string, info domain.ConversationInfo) {
+	placeholder, err := c.sendMessage(ctx, msg, "稍等，让我想一想...", "")
+	if err != nil {
+		c.logger.Error("failed to send placeholder message", log.Error(err))
+		return
+	}
+
+	info.ReplyRoute = map[string]string{
+		"chat_id":    strconv.FormatInt(msg.Chat.ID, 10),
+		"message_id": strconv.FormatInt(msg.MessageID, 10),
+	}
+	if msg.MessageThreadID != 0 {
+		info.ReplyRoute["message_thread_id"] = strconv.FormatInt(msg.MessageThreadID, 10)
+	}
+	answerCh, err := c.getQA(ctx, question, info, "")
+	if err != nil {
+		c.logger.Error("telegram client failed to get answer", log.Error(err))
+		if err := c.editMessage(ctx, placeholder, "出错了，请稍后再试", ""); err != nil {
+			c.logger.Error("failed to edit message", log.Error(err))
+		}
+		return
+	}
+	// replying to any part of the answer continues the conversation
+	key := bot.ConversationKeyFromContext(ctx)
+	key.BindMessage(strconv.FormatInt(placeholder.MessageID, 10))
+
+	var (
+		sb         strings.Builder
+		lastUpdate = time.Now()
+		lastText   string
+	)
+	for chunk := range answerCh {
+		sb.WriteString(chunk)
+		if time.Since(lastUpdate) < updateInterval {
+			continue
+		}
+		lastUpdate = time.Now()
+		// partial markdown may not parse, stream plain text and render it once complete
+		text := plainText.Preview(sb.String())
+		if strings.TrimSpace(text) == "" || text == lastText {
+			continue
+		}
+		lastText = text
+		if err := c.editMessage(ctx, placeholder, text, ""); err != nil {
+			c.logger.Warn("failed to edit streaming message", log.Error(err))
+		}
+	}
+	answer := render.Answer{Markdown: sb.String()}
+	parts := render.Telegram.Render(answer)
+	if len(parts) == 1 && strings.TrimSpace(parts[0]) == "" {
+		answer.Markdown = "抱歉，没有找到相关的答案"
+		parts = render.Telegram.Render(answer)
+	}
+
+	var plain []string
+	for i, part := range parts {
+		send := func(text, parseMode string) error {
+			if i == 0 {
+				return c.editMessage(ctx, placeholder, text, parseMode)
+			}
+			sent, err := c.sendMessage(ctx, msg, text, parseMode)
+			if err == nil {
+				key.BindMessage(strconv.FormatInt(sent.MessageID, 10))
+			}
+			return err
+		}
+		if err := send(part, "MarkdownV2"); err != nil {
+			c.logger.Warn("failed to send markdown answer, fallback to plain text", log.Error(err))
+			if plain == nil {
+				plain = plainText.Render(answer)
+			}
+			if i < len(plain) {
+				part = plain[i]
+			}
+			if err := send(part, ""); err != nil {
+				c.logger.Error("failed to send answer message", log.Error(err))
+			}
+		}
+	}
+}
+
+// SendHumanReply sends the reply of a human agent as a reply to the question
+func (c *TelegramClient) SendHumanReply(ctx context.Context, route map[string]string, content string) error {
+	chatID, err := strconv.ParseInt(route["chat_id"], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid telegram reply route: %w", err)
+	}
+	question := &Message{Chat: Chat{ID: chatID}}
+	question.MessageID, _ = strconv.ParseInt(route["message_id"], 10, 64)
+	question.MessageThreadID, _ = strconv.ParseInt(route["message_thread_id"], 10, 64)
+	text := "*人工回复*\n" + render.Telegram.Preview(content)
+	if _, err := c.sendMessage(ctx, question, text, "MarkdownV2"); err != nil {
+		_, err = c.sendMessage(ctx, question, "人工回复\n"+plainText.Preview(content), "")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chaitin/panda-wiki/config"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/bot"
+)
+
+type stubAPI struct {
+	mu    sync.Mutex
+	calls map[string][]map[string]any
+}
+
+func (s *stubAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	args := map[string]any{}
+	_ = json.NewDecoder(r.Body).Decode(&args)
+	s.mu.Lock()
+	s.calls[method] = append(s.calls[method], args)
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch method {
+	case "getMe":
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"id":42,"is_bot":true,"first_name":"Panda","username":"panda_bot"}}`))
+	case "getFile":
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"file_id":"big","file_path":"photos/file_1.jpg"}}`))
+	case "file_1.jpg":
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write([]byte("jpeg-bytes"))
+	case "sendMessage":
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":100,"chat":{"id":-1,"type":"group"}}}`))
+	default:
+		_, _ = w.Write([]byte(`{"ok":true,"result":true}`))
+	}
+}
+
+func (s *stubAPI) get(method string) []map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+func TestWebhookGroupMention(t *testing.T) {
+	stub := &stubAPI{calls: map[string][]map[string]any{}}
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+
+	cfg, _ := config.NewConfig()
+	var (
+		mu       sync.Mutex
+		question string
+	)
+	getQA := func(ctx context.Context, msg string, info domain.ConversationInfo, conversationID string) (chan string, error) {
+		mu.Lock()
+		question = msg
+		mu.Unlock()
+		ch := make(chan string, 1)
+		ch <- "**Yes**, v1.2"
+		close(ch)
+		return ch, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := NewTelegramClient(ctx, cancel, "123:abc", "https://wiki.example.com/hook", "s3cret", log.NewLogger(cfg), getQA, WithAPIURL(srv.URL))
+	go func() { _ = c.Start() }()
+	deadline := time.Now().Add(5 * time.Second)
+	for c.me.Load() == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("client did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if hooks := stub.get("setWebhook"); len(hooks) != 1 || hooks[0]["secret_token"] != "s3cret" {
+		t.Fatalf("setWebhook calls = %v", hooks)
+	}
+
+	header := http.Header{}
+	ignored := []byte(`{"update_id":1,"message":{"message_id":7,"from":{"id":5,"first_name":"Ann"},"chat":{"id":-1,"type":"group"},"text":"hello all"}}`)
+	if status := c.HandleWebhook(header, ignored); status != http.StatusUnauthorized {
+		t.Fatalf("missing secret token status = %d, want 401", status)
+	}
+	header.Set(secretTokenHeader, "wrong")
+	if status := c.HandleWebhook(header, ignored); status != http.StatusUnauthorized {
+		t.Fatalf("wrong secret token status = %d, want 401", status)
+	}
+	header.Set(secretTokenHeader, "s3cret")
+	if status := c.HandleWebhook(header, ignored); status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+
+	// "@panda_bot" is 10 utf-16 code units after the 2 units long emoji and a space
+	mention := []byte(`{"update_id":2,"message":{"message_id":8,"from":{"id":5,"first_name":"Ann"},"chat":{"id":-1,"type":"group"},"text":"🐼 @panda_bot is it released?","entities":[{"type":"mention","offset":3,"length":10}]}}`)
+	if status := c.HandleWebhook(header, mention); status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	deadline = time.Now().Add(5 * time.Second)
+	for len(stub.get("editMessageText")) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("answer was not sent")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	sends := stub.get("sendMessage")
+	if len(sends) != 1 {
+		t.Fatalf("sendMessage calls = %v, want only the reply to the mention", sends)
+	}
+	if reply, _ := sends[0]["reply_parameters"].(map[string]any); reply["message_id"] != float64(8) {
+		t.Fatalf("reply_parameters = %v", sends[0]["reply_parameters"])
+	}
+	mu.Lock()
+	if question != "🐼  is it released?" {
+		t.Fatalf("question = %q", question)
+	}
+	mu.Unlock()
+	edits := stub.get("editMessageText")
+	final := edits[len(edits)-1]
+	if final["parse_mode"] != "MarkdownV2" || final["text"] != `*Yes*, v1\.2` {
+		t.Fatalf("final edit = %v", final)
+	}
+}
+
+func TestWebhookRequiresSecretToken(t *testing.T) {
+	stub := &stubAPI{calls: map[string][]map[string]any{}}
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+
+	cfg, _ := config.NewConfig()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	getQA := func(ctx context.Context, msg string, info domain.ConversationInfo, conversationID string) (chan string, error) {
+		t.Error("forged update reached the qa function")
+		return nil, nil
+	}
+	c := NewTelegramClient(ctx, cancel, "123:abc", "https://wiki.example.com/hook", "", log.NewLogger(cfg), getQA, WithAPIURL(srv.URL))
+	if err := c.Start(); !errors.Is(err, ErrSecretTokenRequired) {
+		t.Fatalf("Start() error = %v, want %v", err, ErrSecretTokenRequired)
+	}
+	if calls := stub.get("setWebhook"); len(calls) != 0 {
+		t.Fatalf("setWebhook calls = %v, want none", calls)
+	}
+
+	// a client without secret token, e.g. in polling mode, accepts no webhook requests
+	c.me.Store(&User{ID: 1, Username: "panda_bot"})
+	update := []byte(`{"update_id":1,"message":{"message_id":7,"from":{"id":5,"first_name":"Ann"},"chat":{"id":5,"type":"private"},"text":"hello"}}`)
+	if status := c.HandleWebhook(http.Header{}, update); status != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", status)
+	}
+}
+
+func TestPrivatePhoto(t *testing.T) {
+	stub := &stubAPI{calls: map[string][]map[string]any{}}
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+
+	cfg, _ := config.NewConfig()
+	type asked struct {
+		question    string
+		attachments []bot.Attachment
+	}
+	askedCh := make(chan asked, 1)
+	getQA := func(ctx context.Context, msg string, info domain.ConversationInfo, conversationID string) (chan string, error) {
+		askedCh <- asked{question: msg, attachments: bot.AttachmentsFromContext(ctx)}
+		ch := make(chan string, 1)
+		ch <- "restart the service"
+		close(ch)
+		return ch, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := NewTelegramClient(ctx, cancel, "123:abc", "https://wiki.example.com/hook", "s3cret", log.NewLogger(cfg), getQA, WithAPIURL(srv.URL))
+	c.me.Store(&User{ID: 42, Username: "panda_bot"})
+	header := http.Header{}
+	header.Set(secretTokenHeader, "s3cret")
+
+	photo := []byte(`{"update_id":3,"message":{"message_id":9,"from":{"id":5,"first_name":"Ann"},"chat":{"id":5,"type":"private"},"photo":[{"file_id":"small","file_size":10},{"file_id":"big","file_size":100}]}}`)
+	if status := c.HandleWebhook(header, photo); status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	select {
+	case got := <-askedCh:
+		if got.question != bot.DefaultAttachmentQuestion {
+			t.Fatalf("question = %q", got.question)
+		}
+		if len(got.attachments) != 1 || string(got.attachments[0].Data) != "jpeg-bytes" || got.attachments[0].ContentType != "image/jpeg" {
+			t.Fatalf("attachments = %+v", got.attachments)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("photo was not answered")
+	}
+	if files := stub.get("getFile"); len(files) != 1 || files[0]["file_id"] != "big" {
+		t.Fatalf("getFile calls = %v, want the largest size", files)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -73,11 +74,17 @@ func (cfg *WechatConfig) ProcessMessage(msg ReceivedMessage, GetQA bot.GetQAFun,
 	}
 	conversationID := id.String()
 
+	departments := make([]string, 0, len(userinfo.Department))
+	for _, department := range userinfo.Department {
+		departments = append(departments, strconv.Itoa(department))
+	}
+
 	wccontent, err := GetQA(cfg.Ctx, msg.Content, domain.ConversationInfo{
 		UserInfo: domain.UserInfo{
-			UserID:   userinfo.UserID,
-			NickName: userinfo.Name,
-			From:     domain.MessageFromPrivate,
+			UserID:      userinfo.UserID,
+			NickName:    userinfo.Name,
+			From:        domain.MessageFromPrivate,
+			Departments: departments,
 		}}, conversationID)
 
 	if err != nil {
@@ -4,7 +4,7 @@ import (
 	"context"
 
 	"github.com/chaitin/panda-wiki/pkg/bot"
-	"github.com/chaitin/panda-wiki/pkg/bot/wechatservice"
+	"github.com/chaitin/panda-wiki/pkg/bot/render"
 	"github.com/silenceper/wechat/v2/officialaccount/user"
 
 	"github.com/chaitin/panda-wiki/domain"
@@ -26,7 +26,5 @@ func Wechat(ctx context.Context, GetQA bot.GetQAFun, userinfo *user.Info, conten
 	for v := range wccontent {
 		response += v
 	}
-	response = wechatservice.MarkdowntoText(response)
-
-	return response, nil
+	return render.WechatOfficialAccount.Preview(response), nil
 }
@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/chaitin/panda-wiki/consts"
 	"github.com/chaitin/panda-wiki/log"
 )
 
@@ -72,7 +73,8 @@ const (
 	defaultLoginPath        = "/login"
 	defaultValidatePathCAS2 = "/serviceValidate"
 	defaultValidatePathCAS3 = "/p3/serviceValidate"
-	callbackPath            = "/share/pro/v1/openapi/cas/callback"
+	callbackPath            = "/share/v1/openapi/cas/callback"
+	callbackPathPro         = "/share/pro/v1/openapi/cas/callback"
 )
 
 // NewClient 创建CAS客户端
@@ -106,6 +108,10 @@ func NewClient(ctx context.Context, logger *log.Logger, config Config) (*Client,
 			return nil, fmt.Errorf("invalid service URL: %w", err)
 		}
 		serviceURL.Path = callbackPath
+		serviceURL.RawQuery, serviceURL.Fragment = "", ""
+		if edition, _ := ctx.Value(consts.ContextKeyEdition).(consts.LicenseEdition); edition > consts.LicenseEditionFree {
+			serviceURL.Path = callbackPathPro
+		}
 		config.ServiceURL = serviceURL.String()
 	}
 
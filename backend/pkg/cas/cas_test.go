@@ -0,0 +1,118 @@
+package cas
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/chaitin/panda-wiki/config"
+	"github.com/chaitin/panda-wiki/log"
+)
+
+const serviceURL = "https://wiki.example.com/share/v1/openapi/cas/callback"
+
+// newStubCAS answers ticket validation of the CAS 2 and CAS 3 endpoints, ST-good is the only valid ticket
+func newStubCAS(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	validate := func(success string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			if q.Get("service") != serviceURL+"?state=state-1" {
+				http.Error(w, "service mismatch", http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/xml")
+			if q.Get("ticket") != "ST-good" {
+				_, _ = w.Write([]byte(`<cas:serviceResponse xmlns:cas="http://www.yale.edu/tp/cas">
+  <cas:authenticationFailure code="INVALID_TICKET">Ticket not recognized</cas:authenticationFailure>
+</cas:serviceResponse>`))
+				return
+			}
+			_, _ = w.Write([]byte(success))
+		}
+	}
+	mux.HandleFunc("/cas/serviceValidate", validate(`<cas:serviceResponse xmlns:cas="http://www.yale.edu/tp/cas">
+  <cas:authenticationSuccess><cas:user>alice</cas:user></cas:authenticationSuccess>
+</cas:serviceResponse>`))
+	mux.HandleFunc("/cas/p3/serviceValidate", validate(`<cas:serviceResponse xmlns:cas="http://www.yale.edu/tp/cas">
+  <cas:authenticationSuccess>
+    <cas:user>alice</cas:user>
+    <cas:attributes>
+      <cas:email>alice@example.com</cas:email>
+      <cas:name>Alice</cas:name>
+    </cas:attributes>
+  </cas:authenticationSuccess>
+</cas:serviceResponse>`))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestClient(t *testing.T, serverURL, version string) *Client {
+	t.Helper()
+	cfg, _ := config.NewConfig()
+	client, err := NewClient(context.Background(), log.NewLogger(cfg), Config{
+		ServerURL:  serverURL + "/cas",
+		ServiceURL: "https://wiki.example.com/node/1?from=share",
+		Version:    version,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return client
+}
+
+func TestGetLoginURL(t *testing.T) {
+	client := newTestClient(t, "https://cas.example.com", "3")
+	loginURL, err := url.Parse(client.GetLoginURL("state-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loginURL.Host != "cas.example.com" || loginURL.Path != "/cas/login" {
+		t.Errorf("login url = %s, want https://cas.example.com/cas/login", loginURL)
+	}
+	if got := loginURL.Query().Get("service"); got != serviceURL+"?state=state-1" {
+		t.Errorf("service = %q, want %q", got, serviceURL+"?state=state-1")
+	}
+}
+
+func TestValidateTicket(t *testing.T) {
+	srv := newStubCAS(t)
+
+	cases := []struct {
+		version string
+		ticket  string
+		want    *UserInfo
+	}{
+		{"2", "ST-good", &UserInfo{Username: "alice", Attributes: map[string]string{"name": "alice"}}},
+		{"3", "ST-good", &UserInfo{Username: "alice", Attributes: map[string]string{"name": "Alice", "email": "alice@example.com", "avatar_url": ""}}},
+		{"2", "ST-bad", nil},
+		{"3", "ST-bad", nil},
+	}
+	for _, tc := range cases {
+		t.Run("cas"+tc.version+"/"+tc.ticket, func(t *testing.T) {
+			client := newTestClient(t, srv.URL, tc.version)
+			got, err := client.ValidateTicket(tc.ticket, "state-1")
+			if tc.want == nil {
+				if err == nil {
+					t.Fatalf("ValidateTicket() = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateTicket() error = %v", err)
+			}
+			if got.Username != tc.want.Username {
+				t.Errorf("Username = %q, want %q", got.Username, tc.want.Username)
+			}
+			for k, v := range tc.want.Attributes {
+				if got.Attributes[k] != v {
+					t.Errorf("Attributes[%s] = %q, want %q", k, got.Attributes[k], v)
+				}
+			}
+		})
+	}
+}
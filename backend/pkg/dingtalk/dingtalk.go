@@ -64,8 +64,9 @@ type DepartmentListRsp struct {
 type GetUserListResp struct {
 	Errcode int `json:"errcode"`
 	Result  struct {
-		HasMore bool         `json:"has_more"`
-		List    []UserDetail `json:"list"`
+		HasMore    bool         `json:"has_more"`
+		NextCursor int64        `json:"next_cursor"`
+		List       []UserDetail `json:"list"`
 	} `json:"result"`
 	Errmsg string `json:"errmsg"`
 }
@@ -276,8 +277,9 @@ func (c *Client) GetAllUserList(deptID int) ([]UserDetail, error) {
 	const maxDepth = 10
 
 	userList := make([]UserDetail, 0)
+	var cursor int64
 	for depth < maxDepth {
-		resp, err := c.GetUserList(deptID)
+		resp, err := c.GetUserList(deptID, cursor)
 		if err != nil {
 			return nil, err
 		}
@@ -287,12 +289,14 @@ func (c *Client) GetAllUserList(deptID int) ([]UserDetail, error) {
 		if !resp.Result.HasMore {
 			break
 		}
+		cursor = resp.Result.NextCursor
 		depth++
 	}
 	return userList, nil
 }
 
-func (c *Client) GetUserList(deptID int) (*GetUserListResp, error) {
+// GetUserList 获取部门直属用户的一页，cursor 为上一页返回的 next_cursor
+func (c *Client) GetUserList(deptID int, cursor int64) (*GetUserListResp, error) {
 	accessToken, err := c.GetAccessToken()
 	if err != nil {
 		return nil, err
@@ -305,7 +309,7 @@ func (c *Client) GetUserList(deptID int) (*GetUserListResp, error) {
 	bodyMap := map[string]interface{}{
 		"dept_id": deptID,
 		"size":    100,
-		"cursor":  0,
+		"cursor":  cursor,
 	}
 
 	jsonData, err := json.Marshal(bodyMap)
@@ -0,0 +1,160 @@
+package dirsync
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+// maxGroupNameLen auth_groups.name 的长度限制
+const maxGroupNameLen = 100
+
+// Group 目录中的部门或用户组，ID 为目录内的唯一标识
+type Group struct {
+	ID        string
+	ParentID  string
+	Name      string
+	MemberIDs []string // 直属成员在目录中的 ID，与读者认证的 union_id 一致
+}
+
+// Normalize 去掉重复及没有 ID 的用户组，找不到上级或上级成环时作为顶级用户组
+func Normalize(groups []Group) []Group {
+	result := make([]Group, 0, len(groups))
+	index := make(map[string]int, len(groups))
+	for _, g := range groups {
+		if g.ID == "" {
+			continue
+		}
+		if _, ok := index[g.ID]; ok {
+			continue
+		}
+		g.Name = strings.TrimSpace(g.Name)
+		if g.Name == "" {
+			g.Name = g.ID
+		}
+		if name := []rune(g.Name); len(name) > maxGroupNameLen {
+			g.Name = string(name[:maxGroupNameLen])
+		}
+		index[g.ID] = len(result)
+		result = append(result, g)
+	}
+	for i := range result {
+		if _, ok := index[result[i].ParentID]; !ok || result[i].ParentID == result[i].ID {
+			result[i].ParentID = ""
+		}
+	}
+	// 沿上级查找，回到已走过的用户组即成环，断开当前用户组与上级的关系
+	for i := range result {
+		seen := map[string]bool{result[i].ID: true}
+		for parent := result[i].ParentID; parent != ""; parent = result[index[parent]].ParentID {
+			if seen[parent] {
+				result[i].ParentID = ""
+				break
+			}
+			seen[parent] = true
+		}
+	}
+	return result
+}
+
+// Plan 对比目录与已同步的用户组得出变更。existing 为同一知识库、同一来源已同步的用户组，
+// members 为已登录过的读者 union_id 到 auth id 的映射，未登录的成员记入 UnmatchedMembers
+func Plan(existing []domain.AuthGroup, groups []Group, members map[string]uint) *domain.AuthGroupSyncDiff {
+	groups = Normalize(groups)
+	diff := &domain.AuthGroupSyncDiff{}
+
+	existingBySyncID := make(map[string]*domain.AuthGroup, len(existing))
+	for i := range existing {
+		if existing[i].SyncId != "" {
+			existingBySyncID[existing[i].SyncId] = &existing[i]
+		}
+	}
+
+	unmatched := make(map[string]bool)
+	inDirectory := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		inDirectory[g.ID] = true
+
+		want := make([]uint, 0, len(g.MemberIDs))
+		unionIDs := make(map[uint]string, len(g.MemberIDs))
+		for _, memberID := range g.MemberIDs {
+			authID, ok := members[memberID]
+			if !ok {
+				if memberID != "" {
+					unmatched[memberID] = true
+				}
+				continue
+			}
+			want = append(want, authID)
+			unionIDs[authID] = memberID
+		}
+		slices.Sort(want)
+		want = slices.Compact(want)
+
+		var groupID uint
+		var have []uint
+		if old, ok := existingBySyncID[g.ID]; ok {
+			groupID = old.ID
+			for _, id := range old.AuthIDs {
+				have = append(have, uint(id))
+			}
+			if old.Name != g.Name || old.SyncParentId != g.ParentID {
+				diff.UpdateGroups = append(diff.UpdateGroups, domain.AuthGroupSyncGroup{
+					ID:              old.ID,
+					SyncID:          g.ID,
+					SyncParentID:    g.ParentID,
+					Name:            g.Name,
+					OldName:         old.Name,
+					OldSyncParentID: old.SyncParentId,
+				})
+			}
+		} else {
+			diff.CreateGroups = append(diff.CreateGroups, domain.AuthGroupSyncGroup{
+				SyncID:       g.ID,
+				SyncParentID: g.ParentID,
+				Name:         g.Name,
+			})
+		}
+
+		for _, authID := range want {
+			if !slices.Contains(have, authID) {
+				diff.AddMembers = append(diff.AddMembers, domain.AuthGroupSyncMember{
+					GroupID:     groupID,
+					GroupSyncID: g.ID,
+					GroupName:   g.Name,
+					AuthID:      authID,
+					UnionID:     unionIDs[authID],
+				})
+			}
+		}
+		for _, authID := range have {
+			if !slices.Contains(want, authID) {
+				diff.RemoveMembers = append(diff.RemoveMembers, domain.AuthGroupSyncMember{
+					GroupID:     groupID,
+					GroupSyncID: g.ID,
+					GroupName:   g.Name,
+					AuthID:      authID,
+				})
+			}
+		}
+	}
+
+	for _, old := range existing {
+		if old.SyncId == "" || inDirectory[old.SyncId] {
+			continue
+		}
+		diff.DeleteGroups = append(diff.DeleteGroups, domain.AuthGroupSyncGroup{
+			ID:           old.ID,
+			SyncID:       old.SyncId,
+			SyncParentID: old.SyncParentId,
+			Name:         old.Name,
+		})
+	}
+
+	for memberID := range unmatched {
+		diff.UnmatchedMembers = append(diff.UnmatchedMembers, memberID)
+	}
+	slices.Sort(diff.UnmatchedMembers)
+	return diff
+}
@@ -0,0 +1,82 @@
+package dirsync
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+func TestNormalize(t *testing.T) {
+	groups := Normalize([]Group{
+		{ID: "1", Name: "Company"},
+		{ID: "2", ParentID: "1", Name: " R&D "},
+		{ID: "2", ParentID: "1", Name: "duplicate"},
+		{ID: "", Name: "no id"},
+		{ID: "3", ParentID: "missing", Name: ""},
+		{ID: "4", ParentID: "5", Name: "loop a"},
+		{ID: "5", ParentID: "4", Name: "loop b"},
+		{ID: "6", ParentID: "6", Name: strings.Repeat("名", 120)},
+	})
+
+	if len(groups) != 6 {
+		t.Fatalf("Normalize() = %d groups, want 6: %+v", len(groups), groups)
+	}
+	if groups[1].Name != "R&D" || groups[1].ParentID != "1" {
+		t.Errorf("group 2 = %+v, want trimmed name under 1", groups[1])
+	}
+	if groups[2].Name != "3" || groups[2].ParentID != "" {
+		t.Errorf("group 3 = %+v, want named by id at the top level", groups[2])
+	}
+	if groups[3].ParentID != "" && groups[4].ParentID != "" {
+		t.Errorf("loop is kept: %+v %+v", groups[3], groups[4])
+	}
+	if groups[5].ParentID != "" || len([]rune(groups[5].Name)) != maxGroupNameLen {
+		t.Errorf("group 6 = %q under %q, want a truncated top level group", groups[5].Name, groups[5].ParentID)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	existing := []domain.AuthGroup{
+		{ID: 10, Name: "Company", SyncId: "1", AuthIDs: pq.Int64Array{1}},
+		{ID: 11, Name: "Sales", SyncId: "2", SyncParentId: "1", AuthIDs: pq.Int64Array{2, 3}},
+		{ID: 12, Name: "Legacy", SyncId: "9", AuthIDs: pq.Int64Array{3}},
+	}
+	members := map[string]uint{"alice": 1, "bob": 2, "carol": 3}
+	groups := []Group{
+		{ID: "1", Name: "Company", MemberIDs: []string{"alice"}},
+		{ID: "2", ParentID: "3", Name: "Sales & Marketing", MemberIDs: []string{"bob", "dave"}},
+		{ID: "3", ParentID: "1", Name: "Business", MemberIDs: []string{"carol", "carol", "erin"}},
+	}
+
+	diff := Plan(existing, groups, members)
+
+	if len(diff.CreateGroups) != 1 || diff.CreateGroups[0].SyncID != "3" || diff.CreateGroups[0].SyncParentID != "1" {
+		t.Errorf("CreateGroups = %+v, want Business under Company", diff.CreateGroups)
+	}
+	if len(diff.UpdateGroups) != 1 {
+		t.Fatalf("UpdateGroups = %+v, want Sales renamed and moved", diff.UpdateGroups)
+	}
+	if u := diff.UpdateGroups[0]; u.ID != 11 || u.Name != "Sales & Marketing" || u.OldName != "Sales" || u.SyncParentID != "3" || u.OldSyncParentID != "1" {
+		t.Errorf("UpdateGroups[0] = %+v", u)
+	}
+	if len(diff.DeleteGroups) != 1 || diff.DeleteGroups[0].ID != 12 {
+		t.Errorf("DeleteGroups = %+v, want Legacy", diff.DeleteGroups)
+	}
+	if len(diff.AddMembers) != 1 || diff.AddMembers[0].GroupSyncID != "3" || diff.AddMembers[0].AuthID != 3 || diff.AddMembers[0].UnionID != "carol" {
+		t.Errorf("AddMembers = %+v, want carol to Business", diff.AddMembers)
+	}
+	if len(diff.RemoveMembers) != 1 || diff.RemoveMembers[0].GroupID != 11 || diff.RemoveMembers[0].AuthID != 3 {
+		t.Errorf("RemoveMembers = %+v, want carol out of Sales", diff.RemoveMembers)
+	}
+	if !slices.Equal(diff.UnmatchedMembers, []string{"dave", "erin"}) {
+		t.Errorf("UnmatchedMembers = %v", diff.UnmatchedMembers)
+	}
+
+	if diff := Plan(existing[:1], groups[:1], members); !diff.Empty() {
+		t.Errorf("Plan() of an unchanged directory = %+v, want empty", diff)
+	}
+}
@@ -0,0 +1,124 @@
+package feishu
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const contactPageSize = "50"
+
+// Department 部门，ID 为 open_department_id
+type Department struct {
+	ID       string `json:"open_department_id"`
+	ParentID string `json:"parent_department_id"`
+	Name     string `json:"name"`
+}
+
+// ContactUser 通讯录用户
+type ContactUser struct {
+	UnionID string `json:"union_id"`
+	OpenID  string `json:"open_id"`
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+}
+
+type apiResponse[T any] struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+	Data T      `json:"data"`
+}
+
+type pageData[T any] struct {
+	HasMore   bool   `json:"has_more"`
+	PageToken string `json:"page_token"`
+	Items     []T    `json:"items"`
+}
+
+// GetTenantAccessToken 获取自建应用的 tenant_access_token，通讯录接口需要应用开通通讯录权限
+func (c *Client) GetTenantAccessToken(ctx context.Context) (string, error) {
+	body, err := json.Marshal(map[string]string{
+		"app_id":     c.oauthConfig.ClientID,
+		"app_secret": c.oauthConfig.ClientSecret,
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+"/auth/v3/tenant_access_token/internal", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get tenant access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var r struct {
+		Code              int    `json:"code"`
+		Msg               string `json:"msg"`
+		TenantAccessToken string `json:"tenant_access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return "", fmt.Errorf("failed to decode tenant access token: %w", err)
+	}
+	if r.Code != 0 {
+		return "", fmt.Errorf("failed to get tenant access token: %s", r.Msg)
+	}
+	return r.TenantAccessToken, nil
+}
+
+// ListDepartments 获取根部门下的全部部门
+func (c *Client) ListDepartments(ctx context.Context, token string) ([]Department, error) {
+	params := url.Values{}
+	params.Set("fetch_child", "true")
+	params.Set("department_id_type", "open_department_id")
+	return listAll[Department](ctx, c, token, "/contact/v3/departments/"+RootDepartmentID+"/children", params)
+}
+
+// ListDepartmentUsers 获取部门的直属用户，用户 ID 为 union_id
+func (c *Client) ListDepartmentUsers(ctx context.Context, token, departmentID string) ([]ContactUser, error) {
+	params := url.Values{}
+	params.Set("department_id", departmentID)
+	params.Set("department_id_type", "open_department_id")
+	params.Set("user_id_type", "union_id")
+	return listAll[ContactUser](ctx, c, token, "/contact/v3/users/find_by_department", params)
+}
+
+// listAll 按 page_token 翻页读取全部结果
+func listAll[T any](ctx context.Context, c *Client, token, path string, params url.Values) ([]T, error) {
+	items := make([]T, 0)
+	params.Set("page_size", contactPageSize)
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+path+"?"+params.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to request %s: %w", path, err)
+		}
+		var r apiResponse[pageData[T]]
+		err = json.NewDecoder(resp.Body).Decode(&r)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s response: %w", path, err)
+		}
+		if r.Code != 0 {
+			return nil, fmt.Errorf("feishu API %s error: code=%d, msg=%s", path, r.Code, r.Msg)
+		}
+
+		items = append(items, r.Data.Items...)
+		if !r.Data.HasMore || r.Data.PageToken == "" {
+			return items, nil
+		}
+		params.Set("page_token", r.Data.PageToken)
+	}
+}
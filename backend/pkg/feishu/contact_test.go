@@ -0,0 +1,99 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chaitin/panda-wiki/config"
+	"github.com/chaitin/panda-wiki/log"
+)
+
+// newStubContact serves the tenant token and a department list split into two pages
+func newStubContact(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/v3/tenant_access_token/internal", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["app_id"] != "app-1" || body["app_secret"] != "secret" {
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 10014, "msg": "app secret invalid"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "tenant_access_token": "t-1", "expire": 7200})
+	})
+	mux.HandleFunc("/contact/v3/departments/0/children", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t-1" || r.URL.Query().Get("fetch_child") != "true" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		data := map[string]any{"has_more": true, "page_token": "p2", "items": []map[string]string{
+			{"open_department_id": "od-1", "parent_department_id": "0", "name": "R&D"},
+		}}
+		if r.URL.Query().Get("page_token") == "p2" {
+			data = map[string]any{"has_more": false, "items": []map[string]string{
+				{"open_department_id": "od-2", "parent_department_id": "od-1", "name": "Platform"},
+			}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": data})
+	})
+	mux.HandleFunc("/contact/v3/users/find_by_department", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("department_id") != "od-2" || r.URL.Query().Get("user_id_type") != "union_id" {
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 40011, "msg": "bad department"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"items": []map[string]string{
+			{"union_id": "on-1", "name": "Alice"},
+		}}})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestClient(t *testing.T, apiURL, secret string) *Client {
+	t.Helper()
+	cfg, _ := config.NewConfig()
+	client, err := NewClient(context.Background(), log.NewLogger(cfg), "app-1", secret, "https://wiki.example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.apiURL = apiURL
+	return client
+}
+
+func TestListContact(t *testing.T) {
+	srv := newStubContact(t)
+	ctx := context.Background()
+
+	if _, err := newTestClient(t, srv.URL, "wrong").GetTenantAccessToken(ctx); err == nil {
+		t.Error("GetTenantAccessToken() with a wrong secret succeeded")
+	}
+
+	client := newTestClient(t, srv.URL, "secret")
+	token, err := client.GetTenantAccessToken(ctx)
+	if err != nil {
+		t.Fatalf("GetTenantAccessToken() error = %v", err)
+	}
+
+	departments, err := client.ListDepartments(ctx, token)
+	if err != nil {
+		t.Fatalf("ListDepartments() error = %v", err)
+	}
+	if len(departments) != 2 || departments[1].ID != "od-2" || departments[1].ParentID != "od-1" {
+		t.Errorf("ListDepartments() = %+v, want both pages", departments)
+	}
+
+	users, err := client.ListDepartmentUsers(ctx, token, "od-2")
+	if err != nil {
+		t.Fatalf("ListDepartmentUsers() error = %v", err)
+	}
+	if len(users) != 1 || users[0].UnionID != "on-1" {
+		t.Errorf("ListDepartmentUsers() = %+v", users)
+	}
+	if _, err := client.ListDepartmentUsers(ctx, token, "od-9"); err == nil {
+		t.Error("ListDepartmentUsers() ignored the API error")
+	}
+}
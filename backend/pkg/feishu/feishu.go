@@ -17,6 +17,10 @@ const (
 	TokenURL     = "https://open.feishu.cn/open-apis/authen/v2/oauth/token"
 	UserInfoURL  = "https://open.feishu.cn/open-apis/authen/v1/user_info"
 	callbackPath = "/share/pro/v1/openapi/feishu/callback"
+	// OpenAPIURL 通讯录等服务端接口 https://open.feishu.cn/document/server-docs/contact-v3/department/children
+	OpenAPIURL = "https://open.feishu.cn/open-apis"
+	// RootDepartmentID 根部门
+	RootDepartmentID = "0"
 )
 
 var oauthEndpoint = oauth2.Endpoint{
@@ -29,6 +33,7 @@ type Client struct {
 	context     context.Context
 	oauthConfig *oauth2.Config
 	logger      *log.Logger
+	apiURL      string
 }
 
 type Response struct {
@@ -70,6 +75,7 @@ func NewClient(ctx context.Context, logger *log.Logger, appID, appSecret, redire
 		context:     ctx,
 		logger:      logger.WithModule("feishu.client"),
 		oauthConfig: oauthConfig,
+		apiURL:      OpenAPIURL,
 	}, nil
 }
 
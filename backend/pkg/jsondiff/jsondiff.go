@@ -0,0 +1,88 @@
+// Package jsondiff 比较两个值 JSON 序列化后的差异，对象按字段展开，数组整体比较
+package jsondiff
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// Change 一个字段的变化，Path 为以 . 分隔的字段路径，新增或删除的字段另一侧为 nil
+type Change struct {
+	Path   string `json:"path"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+// Diff 返回 before 到 after 的字段变化，按路径排序；before 或 after 可以为 nil
+func Diff(before, after any) ([]Change, error) {
+	b, err := normalize(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := normalize(after)
+	if err != nil {
+		return nil, err
+	}
+	beforeFields := map[string]any{}
+	afterFields := map[string]any{}
+	flatten("", b, beforeFields)
+	flatten("", a, afterFields)
+
+	changes := make([]Change, 0)
+	for path, bv := range beforeFields {
+		av, ok := afterFields[path]
+		if !ok {
+			changes = append(changes, Change{Path: path, Before: bv})
+			continue
+		}
+		if !reflect.DeepEqual(bv, av) {
+			changes = append(changes, Change{Path: path, Before: bv, After: av})
+		}
+	}
+	for path, av := range afterFields {
+		if _, ok := beforeFields[path]; !ok {
+			changes = append(changes, Change{Path: path, After: av})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+// normalize 转成 json.Unmarshal 得到的通用结构，便于比较
+func normalize(v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func flatten(prefix string, v any, out map[string]any) {
+	switch value := v.(type) {
+	case nil:
+		// 空值不作为字段，与不存在等价
+	case map[string]any:
+		for key, child := range value {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			flatten(path, child, out)
+		}
+	default:
+		if prefix == "" {
+			prefix = "$"
+		}
+		out[prefix] = value
+	}
+}
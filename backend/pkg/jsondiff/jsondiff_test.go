@@ -0,0 +1,56 @@
+package jsondiff
+
+import (
+	"reflect"
+	"testing"
+)
+
+type settings struct {
+	Title   string            `json:"title"`
+	Enabled bool              `json:"enabled"`
+	Tags    []string          `json:"tags"`
+	Extra   map[string]string `json:"extra,omitempty"`
+}
+
+func TestDiff(t *testing.T) {
+	before := settings{Title: "a", Enabled: true, Tags: []string{"x"}, Extra: map[string]string{"k": "1", "gone": "2"}}
+	after := &settings{Title: "b", Enabled: true, Tags: []string{"x", "y"}, Extra: map[string]string{"k": "1", "new": "3"}}
+
+	got, err := Diff(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Change{
+		{Path: "extra.gone", Before: "2"},
+		{Path: "extra.new", After: "3"},
+		{Path: "tags", Before: []any{"x"}, After: []any{"x", "y"}},
+		{Path: "title", Before: "a", After: "b"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %#v, want %#v", got, want)
+	}
+}
+
+func TestDiffNil(t *testing.T) {
+	got, err := Diff(nil, map[string]any{"id": "1", "n": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Change{{Path: "id", After: "1"}, {Path: "n", After: float64(2)}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff(nil, map) = %#v", got)
+	}
+
+	got, err = Diff("old", "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Path != "$" {
+		t.Errorf("Diff(scalar) = %#v", got)
+	}
+
+	got, err = Diff(settings{Title: "a"}, settings{Title: "a"})
+	if err != nil || len(got) != 0 {
+		t.Errorf("Diff(equal) = %#v, %v", got, err)
+	}
+}
@@ -25,6 +25,11 @@ type Config struct {
 	UserIDAttr    string `json:"user_id_attr"`    // 用户ID属性，默认 uid
 	UserNameAttr  string `json:"user_name_attr"`  // 用户名属性，默认 cn
 	UserEmailAttr string `json:"user_email_attr"` // 用户邮箱属性，默认 mail
+
+	GroupBaseDN     string `json:"group_base_dn"`     // 用户组基础DN，如 ou=Groups,dc=company,dc=com，同步用户组时必填
+	GroupFilter     string `json:"group_filter"`      // 用户组查询过滤器
+	GroupNameAttr   string `json:"group_name_attr"`   // 用户组名称属性，默认 cn
+	GroupMemberAttr string `json:"group_member_attr"` // 用户组成员属性，默认 member，值为成员 DN 或用户 ID
 }
 
 type UserInfo struct {
@@ -34,11 +39,25 @@ type UserInfo struct {
 	DN       string `json:"dn"` // Distinguished Name
 }
 
+// Group 用户组，成员为其他用户组时作为其下级
+type Group struct {
+	DN        string   `json:"dn"`
+	Name      string   `json:"name"`
+	ParentDN  string   `json:"parent_dn"`
+	MemberIDs []string `json:"member_ids"` // 直属成员的用户 ID
+}
+
 const (
 	defaultUserIDAttr    = "uid"
 	defaultUserNameAttr  = "cn"
 	defaultUserEmailAttr = "mail"
 	defaultUserFilter    = "(&(objectClass=person)(uid=%s))"
+
+	defaultGroupFilter     = "(|(objectClass=groupOfNames)(objectClass=groupOfUniqueNames)(objectClass=posixGroup))"
+	defaultGroupNameAttr   = "cn"
+	defaultGroupMemberAttr = "member"
+
+	searchPageSize = 500
 )
 
 // NewClient 创建LDAP客户端
@@ -56,6 +75,15 @@ func NewClient(ctx context.Context, logger *log.Logger, config Config) (*Client,
 	if config.UserFilter == "" {
 		config.UserFilter = defaultUserFilter
 	}
+	if config.GroupFilter == "" {
+		config.GroupFilter = defaultGroupFilter
+	}
+	if config.GroupNameAttr == "" {
+		config.GroupNameAttr = defaultGroupNameAttr
+	}
+	if config.GroupMemberAttr == "" {
+		config.GroupMemberAttr = defaultGroupMemberAttr
+	}
 
 	// 验证必需的配置
 	if config.ServerURL == "" {
@@ -77,6 +105,11 @@ func NewClient(ctx context.Context, logger *log.Logger, config Config) (*Client,
 
 // Authenticate 验证用户凭据并获取用户信息
 func (c *Client) Authenticate(username, password string) (*UserInfo, error) {
+	// 空密码会被服务器当作匿名绑定而成功
+	if username == "" || password == "" {
+		return nil, fmt.Errorf("authentication failed: invalid credentials")
+	}
+
 	// 连接到LDAP服务器
 	conn, err := ldap.DialURL(c.config.ServerURL)
 	if err != nil {
@@ -118,7 +151,7 @@ func (c *Client) Authenticate(username, password string) (*UserInfo, error) {
 // searchUser 搜索用户信息
 func (c *Client) searchUser(conn *ldap.Conn, username string) (*UserInfo, error) {
 	// 构建搜索过滤器
-	filter := fmt.Sprintf(c.config.UserFilter, username)
+	filter := fmt.Sprintf(c.config.UserFilter, ldap.EscapeFilter(username))
 
 	// 构建搜索请求
 	searchRequest := ldap.NewSearchRequest(
@@ -205,3 +238,99 @@ func (c *Client) TestConnection() error {
 	c.logger.Info("LDAP connection test successful")
 	return nil
 }
+
+// ListGroups 读取 GroupBaseDN 下的用户组及直属成员。成员 DN 对应用户时换算为用户 ID，
+// 对应用户组时该用户组作为下级，不是 DN 的值(如 posixGroup 的 memberUid)直接作为用户 ID
+func (c *Client) ListGroups() ([]Group, error) {
+	if c.config.GroupBaseDN == "" {
+		return nil, fmt.Errorf("group base DN is required")
+	}
+
+	conn, err := ldap.DialURL(c.config.ServerURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to LDAP server: %w", err)
+	}
+	defer conn.Close()
+
+	if err := conn.Bind(c.config.BindDN, c.config.BindPassword); err != nil {
+		return nil, fmt.Errorf("failed to bind with admin credentials: %w", err)
+	}
+
+	// 用户 DN 到用户 ID，与登录时的 ID 取法一致
+	users, err := conn.SearchWithPaging(ldap.NewSearchRequest(
+		c.config.UserBaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		0, 0, false,
+		fmt.Sprintf(c.config.UserFilter, "*"),
+		[]string{c.config.UserIDAttr},
+		nil,
+	), searchPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("user search failed: %w", err)
+	}
+	userIDs := make(map[string]string, len(users.Entries))
+	for _, entry := range users.Entries {
+		id := c.getAttributeValue(entry, c.config.UserIDAttr)
+		if id == "" {
+			id = entry.DN
+		}
+		userIDs[normalizeDN(entry.DN)] = id
+	}
+
+	entries, err := conn.SearchWithPaging(ldap.NewSearchRequest(
+		c.config.GroupBaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		0, 0, false,
+		c.config.GroupFilter,
+		[]string{c.config.GroupNameAttr, c.config.GroupMemberAttr},
+		nil,
+	), searchPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("group search failed: %w", err)
+	}
+
+	groups := make([]Group, 0, len(entries.Entries))
+	groupIndex := make(map[string]int, len(entries.Entries))
+	for _, entry := range entries.Entries {
+		groupIndex[normalizeDN(entry.DN)] = len(groups)
+		groups = append(groups, Group{
+			DN:   entry.DN,
+			Name: c.getAttributeValue(entry, c.config.GroupNameAttr),
+		})
+	}
+	for i, entry := range entries.Entries {
+		for _, member := range entry.GetAttributeValues(c.config.GroupMemberAttr) {
+			member = strings.TrimSpace(member)
+			if member == "" {
+				continue
+			}
+			if !strings.Contains(member, "=") {
+				groups[i].MemberIDs = append(groups[i].MemberIDs, member)
+				continue
+			}
+			dn := normalizeDN(member)
+			if id, ok := userIDs[dn]; ok {
+				groups[i].MemberIDs = append(groups[i].MemberIDs, id)
+			} else if child, ok := groupIndex[dn]; ok && groups[child].ParentDN == "" {
+				groups[child].ParentDN = entry.DN
+			}
+		}
+	}
+
+	c.logger.Info("list LDAP groups",
+		log.Int("groups", len(groups)),
+		log.Int("users", len(userIDs)))
+
+	return groups, nil
+}
+
+// normalizeDN 规范化 DN 以便比较
+func normalizeDN(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(dn))
+	}
+	return strings.ToLower(parsed.String())
+}
@@ -0,0 +1,188 @@
+package linkcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var (
+	markdownLinkRe = regexp.MustCompile(`\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	htmlHrefRe     = regexp.MustCompile(`(?i)<a\s[^>]*href\s*=\s*["']([^"']+)["']`)
+)
+
+// ExtractLinks returns the distinct link targets of markdown links and html
+// anchors in content, images are not links and are skipped
+func ExtractLinks(content string) []string {
+	seen := make(map[string]struct{})
+	links := make([]string, 0)
+	add := func(link string) {
+		link = strings.TrimSpace(link)
+		if link == "" || strings.HasPrefix(link, "#") {
+			return
+		}
+		lower := strings.ToLower(link)
+		for _, prefix := range []string{"mailto:", "tel:", "javascript:", "data:"} {
+			if strings.HasPrefix(lower, prefix) {
+				return
+			}
+		}
+		if _, ok := seen[link]; ok {
+			return
+		}
+		seen[link] = struct{}{}
+		links = append(links, link)
+	}
+	for _, m := range markdownLinkRe.FindAllStringSubmatchIndex(content, -1) {
+		// ![alt](src) is an image
+		open := strings.LastIndex(content[:m[0]], "[")
+		if open > 0 && content[open-1] == '!' {
+			continue
+		}
+		add(content[m[2]:m[3]])
+	}
+	for _, m := range htmlHrefRe.FindAllStringSubmatch(content, -1) {
+		add(m[1])
+	}
+	return links
+}
+
+type Result struct {
+	URL        string
+	StatusCode int
+	Error      string
+	Broken     bool
+}
+
+type Checker struct {
+	client      *http.Client
+	concurrency int
+}
+
+// ErrAddressNotAllowed is returned when a link resolves to an internal address
+var ErrAddressNotAllowed = errors.New("destination address is not allowed")
+
+// cgnatNet 100.64.0.0/10, also used by cloud metadata services
+var cgnatNet = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+func isInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() || cgnatNet.Contains(ip)
+}
+
+// denyInternal runs after DNS resolution for every connection, including the
+// ones made while following redirects, so links cannot reach internal services
+func denyInternal(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || isInternalIP(ip) {
+		return fmt.Errorf("%w: %s", ErrAddressNotAllowed, host)
+	}
+	return nil
+}
+
+// NewDefaultClient only connects to public addresses and ignores proxy
+// settings, a proxy would connect to internal addresses on our behalf
+func NewDefaultClient() *http.Client {
+	return newClient(denyInternal)
+}
+
+func newClient(control func(network, address string, c syscall.RawConn) error) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: control,
+	}
+	return &http.Client{
+		Timeout: 15 * time.Second,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			MaxIdleConns:        32,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+}
+
+// NewChecker creates a checker, a nil client uses NewDefaultClient.
+// Tests inject their own client to reach local servers
+func NewChecker(client *http.Client, concurrency int) *Checker {
+	if client == nil {
+		client = NewDefaultClient()
+	}
+	if concurrency <= 0 {
+		concurrency = 8
+	}
+	return &Checker{client: client, concurrency: concurrency}
+}
+
+// Check requests url with HEAD, falling back to GET for servers that
+// reject HEAD. Any transport error or 4xx/5xx response except 429 is broken.
+func (c *Checker) Check(ctx context.Context, url string) Result {
+	res := Result{URL: url}
+	resp, err := c.do(ctx, http.MethodHead, url)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotImplemented) {
+		resp, err = c.do(ctx, http.MethodGet, url)
+	}
+	if err != nil {
+		res.Error = err.Error()
+		res.Broken = true
+		return res
+	}
+	res.StatusCode = resp.StatusCode
+	res.Broken = resp.StatusCode >= 400 && resp.StatusCode != http.StatusTooManyRequests
+	return res
+}
+
+func (c *Checker) do(ctx context.Context, method, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "PandaWiki-LinkChecker/1.0")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	resp.Body.Close()
+	return resp, nil
+}
+
+// CheckAll checks urls concurrently, results keep the order of urls
+func (c *Checker) CheckAll(ctx context.Context, urls []string) []Result {
+	results := make([]Result, len(urls))
+	sem := make(chan struct{}, c.concurrency)
+	var wg sync.WaitGroup
+	for i, url := range urls {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, url string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = c.Check(ctx, url)
+		}(i, url)
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,149 @@
+package linkcheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"syscall"
+	"testing"
+)
+
+func TestExtractLinks(t *testing.T) {
+	content := `see [guide](/node/0197a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5b) and [site](https://example.com/a "title")
+![logo](/static-file/logo.png) [mail](mailto:a@b.c) [top](#intro)
+<p><a class="x" href="https://example.com/b">b</a> <img src="https://example.com/c.png"></p>
+again [site](https://example.com/a)`
+
+	got := ExtractLinks(content)
+	want := []string{
+		"/node/0197a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5b",
+		"https://example.com/a",
+		"https://example.com/b",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("ExtractLinks() = %v, want %v", got, want)
+	}
+}
+
+func TestChecker(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/no-head":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		case "/limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/error":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL + "/ok"
+	closed.Close()
+
+	cases := []struct {
+		url    string
+		status int
+		broken bool
+	}{
+		{srv.URL + "/ok", http.StatusOK, false},
+		{srv.URL + "/no-head", http.StatusOK, false},
+		{srv.URL + "/limited", http.StatusTooManyRequests, false},
+		{srv.URL + "/error", http.StatusInternalServerError, true},
+		{srv.URL + "/missing", http.StatusNotFound, true},
+		{closedURL, 0, true},
+	}
+	urls := make([]string, 0, len(cases))
+	for _, c := range cases {
+		urls = append(urls, c.url)
+	}
+
+	results := NewChecker(srv.Client(), 2).CheckAll(context.Background(), urls)
+	for i, c := range cases {
+		res := results[i]
+		if res.URL != c.url || res.StatusCode != c.status || res.Broken != c.broken {
+			t.Errorf("Check(%s) = %+v, want status %d broken %v", c.url, res, c.status, c.broken)
+		}
+		if c.status == 0 && res.Error == "" {
+			t.Errorf("Check(%s) expected transport error", c.url)
+		}
+	}
+}
+
+func TestDefaultClientDeniesInternalAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("internal server reached: %s", r.URL)
+	}))
+	defer srv.Close()
+
+	checker := NewChecker(nil, 0)
+	for _, url := range []string{
+		srv.URL + "/ok",
+		"http://localhost" + srv.URL[len("http://127.0.0.1"):] + "/ok",
+		"http://[::1]/",
+		"http://0.0.0.0/",
+		"http://10.0.0.1/",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://100.100.100.200/",
+	} {
+		res := checker.Check(context.Background(), url)
+		if !res.Broken || res.StatusCode != 0 {
+			t.Errorf("Check(%s) = %+v, want blocked", url, res)
+		}
+	}
+
+}
+
+func TestDefaultClientDeniesInternalRedirects(t *testing.T) {
+	srv := httptest.NewServer(http.RedirectHandler("http://169.254.169.254/latest/meta-data/", http.StatusFound))
+	defer srv.Close()
+	srvAddr := srv.Listener.Addr().String()
+
+	// only the test server itself is allowed, the redirect target must be checked again
+	client := newClient(func(network, address string, c syscall.RawConn) error {
+		if address == srvAddr {
+			return nil
+		}
+		return denyInternal(network, address, c)
+	})
+	_, err := client.Get(srv.URL)
+	if !errors.Is(err, ErrAddressNotAllowed) {
+		t.Fatalf("Get(%s) error = %v, want %v", srv.URL, err, ErrAddressNotAllowed)
+	}
+}
+
+func TestIsInternalIP(t *testing.T) {
+	cases := map[string]bool{
+		"127.0.0.1":       true,
+		"::1":             true,
+		"10.1.2.3":        true,
+		"172.16.0.1":      true,
+		"192.168.1.1":     true,
+		"169.254.169.254": true,
+		"fe80::1":         true,
+		"fd00::1":         true,
+		"0.0.0.0":         true,
+		"::":              true,
+		"100.100.100.200": true,
+		"::ffff:10.0.0.1": true,
+		"8.8.8.8":         false,
+		"2606:4700::1111": false,
+	}
+	for ip, want := range cases {
+		if got := isInternalIP(net.ParseIP(ip)); got != want {
+			t.Errorf("isInternalIP(%s) = %v, want %v", ip, got, want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	lark "github.com/larksuite/oapi-sdk-go/v3"
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/pkg/bot/render"
+)
+
+// messages to group chats are sent with the credentials of the bot apps, the markdown
+// content is rendered for each platform and cut to the size of one message
+
+// SendFeishuChat posts msg as a markdown card to a feishu group chat the bot app is a member of
+func SendFeishuChat(ctx context.Context, appID, appSecret, chatID string, msg *domain.NotifyMessage) error {
+	client := lark.NewClient(appID, appSecret)
+	content, err := json.Marshal(map[string]any{
+		"config": map[string]any{"wide_screen_mode": true},
+		"header": map[string]any{
+			"title": map[string]string{"tag": "plain_text", "content": msg.Title},
+		},
+		"elements": []any{
+			map[string]string{"tag": "markdown", "content": render.Feishu.Preview(msg.Content)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	res, err := client.Im.Message.Create(ctx, larkim.NewCreateMessageReqBuilder().
+		ReceiveIdType(larkim.ReceiveIdTypeChatId).
+		Body(larkim.NewCreateMessageReqBodyBuilder().
+			MsgType(larkim.MsgTypeInteractive).
+			ReceiveId(chatID).
+			Content(string(content)).
+			Build()).
+		Build())
+	if err != nil {
+		return err
+	}
+	if !res.Success() {
+		return fmt.Errorf("feishu send message failed: %d %s", res.Code, res.Msg)
+	}
+	return nil
+}
+
+// SendDingTalkGroup posts msg to a dingtalk group chat by the robot of the app
+func SendDingTalkGroup(ctx context.Context, clientID, clientSecret, openConversationID string, msg *domain.NotifyMessage) error {
+	var token struct {
+		AccessToken string `json:"accessToken"`
+	}
+	if err := postJSON(ctx, "https://api.dingtalk.com/v1.0/oauth2/accessToken", nil, map[string]string{
+		"appKey":    clientID,
+		"appSecret": clientSecret,
+	}, &token); err != nil {
+		return fmt.Errorf("get dingtalk access token: %w", err)
+	}
+	param, err := json.Marshal(map[string]string{
+		"title": msg.Title,
+		"text":  fmt.Sprintf("### %s\n\n%s", msg.Title, render.DingTalk.Preview(msg.Content)),
+	})
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, "https://api.dingtalk.com/v1.0/robot/groupMessages/send", map[string]string{
+		"x-acs-dingtalk-access-token": token.AccessToken,
+	}, map[string]string{
+		"msgKey":             "sampleMarkdown",
+		"msgParam":           string(param),
+		"openConversationId": openConversationID,
+		"robotCode":          clientID,
+	}, nil)
+}
+
+// SendWeComAppChat posts msg to a wecom group chat created by the app
+func SendWeComAppChat(ctx context.Context, corpID, secret, chatID string, msg *domain.NotifyMessage) error {
+	params := url.Values{}
+	params.Set("corpid", corpID)
+	params.Set("corpsecret", secret)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://qyapi.weixin.qq.com/cgi-bin/gettoken?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := do(req, &token); err != nil {
+		return fmt.Errorf("get wecom access token: %w", err)
+	}
+	return postJSON(ctx, "https://qyapi.weixin.qq.com/cgi-bin/appchat/send?access_token="+url.QueryEscape(token.AccessToken), nil, map[string]any{
+		"chatid":   chatID,
+		"msgtype":  "markdown",
+		"markdown": map[string]string{"content": fmt.Sprintf("### %s\n%s", msg.Title, render.WeCom.Preview(msg.Content))},
+	}, nil)
+}
+
+// SendDiscordChannel posts msg to a discord channel the bot can write to
+func SendDiscordChannel(ctx context.Context, botToken, channelID string, msg *domain.NotifyMessage) error {
+	return postJSON(ctx, fmt.Sprintf("https://discord.com/api/v10/channels/%s/messages", url.PathEscape(channelID)), map[string]string{
+		"Authorization": "Bot " + botToken,
+	}, map[string]string{
+		"content": render.Discord.Preview(fmt.Sprintf("## %s\n\n%s", msg.Title, msg.Content)),
+	}, nil)
+}
+
+// SendSlackChannel posts msg to a slack channel the bot is a member of
+func SendSlackChannel(ctx context.Context, botToken, channelID string, msg *domain.NotifyMessage) error {
+	var res struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := postJSON(ctx, "https://slack.com/api/chat.postMessage", map[string]string{
+		"Authorization": "Bearer " + botToken,
+	}, map[string]any{
+		"channel": channelID,
+		"text":    msg.Title,
+		"blocks": []any{map[string]string{
+			"type": "markdown",
+			"text": render.Slack.Preview(fmt.Sprintf("## %s\n\n%s", msg.Title, msg.Content)),
+		}},
+	}, &res); err != nil {
+		return err
+	}
+	if !res.OK {
+		return fmt.Errorf("slack post message failed: %s", res.Error)
+	}
+	return nil
+}
+
+func postJSON(ctx context.Context, target string, headers map[string]string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return do(req, out)
+}
+
+// do sends the request and decodes the response into out, responses carrying a non-zero
+// errcode or code are failures like those of the group robots
+func do(req *http.Request, out any) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("responded %d: %s", resp.StatusCode, body)
+	}
+	if err := checkRobotResponse(domain.NotifyWebhookTypeWeCom, body); err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	lark "github.com/larksuite/oapi-sdk-go/v3"
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+// SendFeishuDMByEmail sends msg as a private message from a feishu bot app
+// to the feishu user bound to email
+func SendFeishuDMByEmail(ctx context.Context, appID, appSecret, email string, msg *domain.NotifyMessage) error {
+	client := lark.NewClient(appID, appSecret)
+	content, err := json.Marshal(map[string]string{
+		"text": fmt.Sprintf("%s\n\n%s", msg.Title, msg.Content),
+	})
+	if err != nil {
+		return err
+	}
+	res, err := client.Im.Message.Create(ctx, larkim.NewCreateMessageReqBuilder().
+		ReceiveIdType(larkim.ReceiveIdTypeEmail).
+		Body(larkim.NewCreateMessageReqBodyBuilder().
+			MsgType(larkim.MsgTypeText).
+			ReceiveId(email).
+			Content(string(content)).
+			Build()).
+		Build())
+	if err != nil {
+		return err
+	}
+	if !res.Success() {
+		return fmt.Errorf("feishu send message failed: %d %s", res.Code, res.Msg)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// SendWebhook posts msg to a group robot webhook
+func SendWebhook(ctx context.Context, hook *domain.NotifyWebhook, msg *domain.NotifyMessage) error {
+	target := hook.URL
+	var body any
+	switch hook.Type {
+	case domain.NotifyWebhookTypeDingTalk:
+		if hook.Secret != "" {
+			ts := strconv.FormatInt(time.Now().UnixMilli(), 10)
+			u, err := url.Parse(hook.URL)
+			if err != nil {
+				return err
+			}
+			q := u.Query()
+			q.Set("timestamp", ts)
+			q.Set("sign", sign(hook.Secret, ts+"\n"+hook.Secret))
+			u.RawQuery = q.Encode()
+			target = u.String()
+		}
+		body = map[string]any{
+			"msgtype":  "markdown",
+			"markdown": map[string]string{"title": msg.Title, "text": fmt.Sprintf("### %s\n\n%s", msg.Title, msg.Content)},
+		}
+	case domain.NotifyWebhookTypeFeishu:
+		payload := map[string]any{
+			"msg_type": "text",
+			"content":  map[string]string{"text": fmt.Sprintf("%s\n\n%s", msg.Title, msg.Content)},
+		}
+		if hook.Secret != "" {
+			ts := strconv.FormatInt(time.Now().Unix(), 10)
+			payload["timestamp"] = ts
+			payload["sign"] = sign(ts+"\n"+hook.Secret, "")
+		}
+		body = payload
+	case domain.NotifyWebhookTypeWeCom:
+		body = map[string]any{
+			"msgtype":  "markdown",
+			"markdown": map[string]string{"content": fmt.Sprintf("### %s\n%s", msg.Title, msg.Content)},
+		}
+	default:
+		body = msg
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook responded %d: %s", resp.StatusCode, respBody)
+	}
+	return checkRobotResponse(hook.Type, respBody)
+}
+
+// sign computes base64(hmac_sha256(key, data))
+func sign(key, data string) string {
+	h := hmac.New(sha256.New, []byte(key))
+	h.Write([]byte(data))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// checkRobotResponse robots reply 200 with an error code in body on failures
+func checkRobotResponse(typ domain.NotifyWebhookType, body []byte) error {
+	var res struct {
+		ErrCode *int   `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+		Code    *int   `json:"code"`
+		Msg     string `json:"msg"`
+	}
+	if typ == domain.NotifyWebhookTypeGeneric || json.Unmarshal(body, &res) != nil {
+		return nil
+	}
+	if res.ErrCode != nil && *res.ErrCode != 0 {
+		return fmt.Errorf("robot error %d: %s", *res.ErrCode, res.ErrMsg)
+	}
+	if res.Code != nil && *res.Code != 0 {
+		return fmt.Errorf("robot error %d: %s", *res.Code, res.Msg)
+	}
+	return nil
+}
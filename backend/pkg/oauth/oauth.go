@@ -2,13 +2,16 @@ package oauth
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
 
 	"github.com/tidwall/gjson"
 	"golang.org/x/oauth2"
 
+	"github.com/chaitin/panda-wiki/consts"
 	"github.com/chaitin/panda-wiki/log"
 )
 
@@ -21,7 +24,8 @@ type Client struct {
 }
 
 const (
-	callbackPath = "/share/pro/v1/openapi/oauth/callback"
+	callbackPath    = "/share/v1/openapi/oauth/callback"
+	callbackPathPro = "/share/pro/v1/openapi/oauth/callback"
 )
 
 type Config struct {
@@ -36,6 +40,9 @@ type Config struct {
 	NameField    string   `json:"name_field,omitempty"`
 	AvatarField  string   `json:"avatar_field,omitempty"`
 	EmailField   string   `json:"email_field,omitempty"`
+	Issuer       string   `json:"issuer,omitempty"`   // OIDC issuer，配置后通过发现文档补全端点并校验 ID Token
+	JWKSURL      string   `json:"jwks_url,omitempty"` // OIDC 签名公钥地址，默认取发现文档中的 jwks_uri
+	Proxy        string   `json:"proxy,omitempty"`
 }
 type UserInfo struct {
 	ID        string `json:"id"`
@@ -44,12 +51,41 @@ type UserInfo struct {
 	AvatarUrl string `json:"avatar_url"`
 }
 
-// NewClient 创建OAuth客户端
+// NewClient 创建OAuth客户端，配置了 Issuer 时按 OIDC 处理
 func NewClient(ctx context.Context, logger *log.Logger, config Config) (*Client, error) {
-	redirectURL, _ := url.Parse(config.RedirectURI)
+	redirectURL, err := url.Parse(config.RedirectURI)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redirect URI: %w", err)
+	}
 	redirectURL.Path = callbackPath
+	redirectURL.RawQuery, redirectURL.Fragment = "", ""
+	if edition, _ := ctx.Value(consts.ContextKeyEdition).(consts.LicenseEdition); edition > consts.LicenseEditionFree {
+		redirectURL.Path = callbackPathPro
+	}
 	redirectURI := redirectURL.String()
 
+	var httpClient *http.Client
+	if config.Proxy != "" {
+		proxyURL, err := url.Parse(config.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		httpClient = &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+	}
+
+	if config.Issuer != "" {
+		if err := discover(ctx, &config); err != nil {
+			return nil, err
+		}
+		if !slices.Contains(config.Scopes, "openid") {
+			config.Scopes = append([]string{"openid"}, config.Scopes...)
+		}
+	}
+	if config.AuthorizeURL == "" || config.TokenURL == "" {
+		return nil, fmt.Errorf("authorize url and token url are required")
+	}
+
 	return &Client{
 		ctx:    ctx,
 		logger: logger.WithModule("pkg.oauth"),
@@ -63,7 +99,8 @@ func NewClient(ctx context.Context, logger *log.Logger, config Config) (*Client,
 			RedirectURL: redirectURI,
 			Scopes:      config.Scopes,
 		},
-		config: &config,
+		httpClient: httpClient,
+		config:     &config,
 	}, nil
 }
 
@@ -76,22 +113,11 @@ func (c *Client) GetUserInfo(code string) (*UserInfo, error) {
 	if err != nil {
 		return nil, err
 	}
-	client := c.oauth.Client(c.ctx, token)
-	res, err := client.Get(c.config.UserInfoURL)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	buf, err := io.ReadAll(res.Body)
+	jsonString, err := c.fetchUserInfo(token)
 	if err != nil {
 		return nil, err
 	}
 
-	c.logger.Info("oauth GetUserInfo:", log.Any("resp", string(buf)))
-
-	jsonString := string(buf)
-
 	email := gjson.Get(jsonString, c.config.EmailField).String()
 	if email == "" && c.config.UserInfoURL == githubUserInfoURL {
 		email, err = c.GetGithubPrimaryEmail(token)
@@ -107,3 +133,25 @@ func (c *Client) GetUserInfo(code string) (*UserInfo, error) {
 		Email:     email,
 	}, nil
 }
+
+// fetchUserInfo 请求用户信息端点，返回原始 JSON
+func (c *Client) fetchUserInfo(token *oauth2.Token) (string, error) {
+	client := c.oauth.Client(c.ctx, token)
+	res, err := client.Get(c.config.UserInfoURL)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	buf, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("user info responded %d: %s", res.StatusCode, buf)
+	}
+
+	c.logger.Info("oauth GetUserInfo:", log.Any("resp", string(buf)))
+
+	return string(buf), nil
+}
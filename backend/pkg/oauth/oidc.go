@@ -0,0 +1,296 @@
+package oauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tidwall/gjson"
+	"golang.org/x/oauth2"
+)
+
+const discoveryPath = "/.well-known/openid-configuration"
+
+// idTokenLeeway 容忍 IdP 与本机之间的时钟偏差
+const idTokenLeeway = time.Minute
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// discover 读取 issuer 的发现文档，补全未手动配置的端点及 OIDC 默认字段
+func discover(ctx context.Context, config *Config) error {
+	var doc discoveryDocument
+	if err := getJSON(ctx, strings.TrimSuffix(config.Issuer, "/")+discoveryPath, &doc); err != nil {
+		return fmt.Errorf("get discovery document failed: %w", err)
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != strings.TrimSuffix(config.Issuer, "/") {
+		return fmt.Errorf("discovery issuer %q does not match %q", doc.Issuer, config.Issuer)
+	}
+	// ID Token 的 iss 须与发现文档中的 issuer 完全一致
+	config.Issuer = doc.Issuer
+	if config.AuthorizeURL == "" {
+		config.AuthorizeURL = doc.AuthorizationEndpoint
+	}
+	if config.TokenURL == "" {
+		config.TokenURL = doc.TokenEndpoint
+	}
+	if config.UserInfoURL == "" {
+		config.UserInfoURL = doc.UserinfoEndpoint
+	}
+	if config.JWKSURL == "" {
+		config.JWKSURL = doc.JWKSURI
+	}
+	if config.JWKSURL == "" {
+		return fmt.Errorf("jwks uri is required for oidc")
+	}
+	if config.IDField == "" {
+		config.IDField = "sub"
+	}
+	if config.NameField == "" {
+		config.NameField = "name"
+	}
+	if config.AvatarField == "" {
+		config.AvatarField = "picture"
+	}
+	if config.EmailField == "" {
+		config.EmailField = "email"
+	}
+	return nil
+}
+
+// GetAuthorizeURLWithNonce 获取携带 nonce 的 OIDC 授权地址，回调时以同一 nonce 校验 ID Token
+func (c *Client) GetAuthorizeURLWithNonce(state, nonce string) string {
+	return c.oauth.AuthCodeURL(state, oauth2.SetAuthURLParam("nonce", nonce))
+}
+
+// GetOIDCUserInfo 用授权码换取令牌，校验 ID Token 后返回用户信息，
+// 配置了用户信息端点时以其补全 ID Token 中缺少的字段
+func (c *Client) GetOIDCUserInfo(code, nonce string) (*UserInfo, error) {
+	if c.config.Issuer == "" {
+		return nil, errors.New("issuer is not configured")
+	}
+	token, err := c.oauth.Exchange(c.ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	rawIDToken, _ := token.Extra("id_token").(string)
+	if rawIDToken == "" {
+		return nil, errors.New("id_token missing in token response")
+	}
+	claims, err := c.VerifyIDToken(rawIDToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	claim := func(key string) string {
+		v, _ := claims[key].(string)
+		return v
+	}
+	userInfo := &UserInfo{
+		ID:        claim("sub"),
+		Name:      claim("name"),
+		Email:     claim("email"),
+		AvatarUrl: claim("picture"),
+	}
+	if userInfo.Name == "" {
+		userInfo.Name = claim("preferred_username")
+	}
+
+	if c.config.UserInfoURL != "" {
+		jsonString, err := c.fetchUserInfo(token)
+		if err != nil {
+			return nil, err
+		}
+		if sub := gjson.Get(jsonString, "sub").String(); sub != "" && sub != userInfo.ID {
+			return nil, fmt.Errorf("user info subject %q does not match id token subject %q", sub, userInfo.ID)
+		}
+		if v := gjson.Get(jsonString, c.config.NameField).String(); v != "" {
+			userInfo.Name = v
+		}
+		if v := gjson.Get(jsonString, c.config.EmailField).String(); v != "" {
+			userInfo.Email = v
+		}
+		if v := gjson.Get(jsonString, c.config.AvatarField).String(); v != "" {
+			userInfo.AvatarUrl = v
+		}
+	}
+	if userInfo.Name == "" {
+		userInfo.Name = userInfo.ID
+	}
+
+	return userInfo, nil
+}
+
+// VerifyIDToken 校验 ID Token 的签名、iss、aud、exp 及 nonce，返回其声明
+func (c *Client) VerifyIDToken(rawIDToken, nonce string) (jwt.MapClaims, error) {
+	keys, err := c.fetchJWKS()
+	if err != nil {
+		return nil, err
+	}
+
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(c.config.Issuer),
+		jwt.WithAudience(c.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(idTokenLeeway),
+	)
+	claims := jwt.MapClaims{}
+	if _, err := parser.ParseWithClaims(rawIDToken, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		for _, key := range keys {
+			if kid != "" && key.Kid != kid {
+				continue
+			}
+			switch pub := key.pub.(type) {
+			case *rsa.PublicKey:
+				if strings.HasPrefix(t.Method.Alg(), "RS") || strings.HasPrefix(t.Method.Alg(), "PS") {
+					return pub, nil
+				}
+			case *ecdsa.PublicKey:
+				if strings.HasPrefix(t.Method.Alg(), "ES") {
+					return pub, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("no signing key for kid %q", kid)
+	}); err != nil {
+		return nil, fmt.Errorf("invalid id token: %w", err)
+	}
+
+	if sub, _ := claims["sub"].(string); sub == "" {
+		return nil, errors.New("invalid id token: sub is required")
+	}
+	if got, _ := claims["nonce"].(string); got != nonce {
+		return nil, errors.New("invalid id token: nonce mismatch")
+	}
+	// 多个受众时 azp 须为本客户端
+	if aud, _ := claims.GetAudience(); len(aud) > 1 {
+		if azp, _ := claims["azp"].(string); azp != c.config.ClientID {
+			return nil, errors.New("invalid id token: azp mismatch")
+		}
+	}
+	return claims, nil
+}
+
+type signingKey struct {
+	Kid string
+	pub any
+}
+
+// fetchJWKS 获取 IdP 的签名公钥，忽略不支持或非签名用途的密钥
+func (c *Client) fetchJWKS() ([]signingKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := getJSON(c.ctx, c.config.JWKSURL, &set); err != nil {
+		return nil, fmt.Errorf("get jwks failed: %w", err)
+	}
+	keys := make([]signingKey, 0, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			c.logger.Warn("skip jwk: " + err.Error())
+			continue
+		}
+		keys = append(keys, signingKey{Kid: k.Kid, pub: pub})
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no usable key in jwks")
+	}
+	return keys, nil
+}
+
+func (k jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rsa modulus of %q: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rsa exponent of %q: %w", k.Kid, err)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q of %q", k.Crv, k.Kid)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ec x of %q: %w", k.Kid, err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ec y of %q: %w", k.Kid, err)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q of %q", k.Kty, k.Kid)
+}
+
+// getJSON 使用 ctx 中 oauth2.HTTPClient 指定的客户端请求 JSON
+func getJSON(ctx context.Context, target string, out any) error {
+	client := http.DefaultClient
+	if c, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok && c != nil {
+		client = c
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded %d: %s", target, resp.StatusCode, body)
+	}
+	return json.Unmarshal(body, out)
+}
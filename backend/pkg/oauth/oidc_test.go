@@ -0,0 +1,228 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/chaitin/panda-wiki/config"
+	"github.com/chaitin/panda-wiki/log"
+)
+
+// stubIdP is a minimal OIDC provider issuing the id token built by claims for every code
+type stubIdP struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	claims func(issuer string) jwt.MapClaims
+}
+
+func newStubIdP(t *testing.T) *stubIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &stubIdP{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{
+			"issuer":                 idp.URL,
+			"authorization_endpoint": idp.URL + "/authorize",
+			"token_endpoint":         idp.URL + "/token",
+			"userinfo_endpoint":      idp.URL + "/userinfo",
+			"jwks_uri":               idp.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "k1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("code") != "good-code" {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, idp.claims(idp.URL))
+		token.Header["kid"] = "k1"
+		signed, err := token.SignedString(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, map[string]any{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     signed,
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, map[string]string{"sub": "user-1", "email": "alice@example.com"})
+	})
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func validClaims(issuer string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   issuer,
+		"aud":   "client-1",
+		"sub":   "user-1",
+		"name":  "Alice",
+		"nonce": "nonce-1",
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func newOIDCClient(t *testing.T, idp *stubIdP) *Client {
+	t.Helper()
+	cfg, _ := config.NewConfig()
+	client, err := NewClient(context.Background(), log.NewLogger(cfg), Config{
+		ClientID:     "client-1",
+		ClientSecret: "secret",
+		RedirectURI:  "https://wiki.example.com/some/page?x=1",
+		Issuer:       idp.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return client
+}
+
+func TestOIDCDiscovery(t *testing.T) {
+	idp := newStubIdP(t)
+	client := newOIDCClient(t, idp)
+
+	authURL, err := url.Parse(client.GetAuthorizeURLWithNonce("state-1", "nonce-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := authURL.Scheme + "://" + authURL.Host + authURL.Path; got != idp.URL+"/authorize" {
+		t.Errorf("authorize endpoint = %s, want %s", got, idp.URL+"/authorize")
+	}
+	q := authURL.Query()
+	if q.Get("nonce") != "nonce-1" || q.Get("state") != "state-1" {
+		t.Errorf("authorize query = %v, want state and nonce", q)
+	}
+	if !strings.Contains(q.Get("scope"), "openid") {
+		t.Errorf("scope = %q, want openid", q.Get("scope"))
+	}
+	if q.Get("redirect_uri") != "https://wiki.example.com/share/v1/openapi/oauth/callback" {
+		t.Errorf("redirect_uri = %q", q.Get("redirect_uri"))
+	}
+}
+
+func TestOIDCDiscoveryIssuerMismatch(t *testing.T) {
+	idp := newStubIdP(t)
+	cfg, _ := config.NewConfig()
+	_, err := NewClient(context.Background(), log.NewLogger(cfg), Config{
+		ClientID: "client-1",
+		Issuer:   idp.URL + "/other",
+	})
+	if err == nil {
+		t.Fatal("NewClient() with a foreign issuer succeeded")
+	}
+}
+
+func TestGetOIDCUserInfo(t *testing.T) {
+	idp := newStubIdP(t)
+	client := newOIDCClient(t, idp)
+
+	cases := []struct {
+		name    string
+		claims  func(issuer string) jwt.MapClaims
+		code    string
+		nonce   string
+		wantErr bool
+	}{
+		{name: "valid", claims: validClaims, code: "good-code", nonce: "nonce-1"},
+		{name: "bad code", claims: validClaims, code: "bad-code", nonce: "nonce-1", wantErr: true},
+		{name: "nonce mismatch", claims: validClaims, code: "good-code", nonce: "nonce-2", wantErr: true},
+		{name: "wrong audience", claims: func(issuer string) jwt.MapClaims {
+			c := validClaims(issuer)
+			c["aud"] = "client-2"
+			return c
+		}, code: "good-code", nonce: "nonce-1", wantErr: true},
+		{name: "wrong issuer", claims: func(issuer string) jwt.MapClaims {
+			c := validClaims(issuer)
+			c["iss"] = "https://evil.example.com"
+			return c
+		}, code: "good-code", nonce: "nonce-1", wantErr: true},
+		{name: "expired", claims: func(issuer string) jwt.MapClaims {
+			c := validClaims(issuer)
+			c["exp"] = time.Now().Add(-time.Hour).Unix()
+			return c
+		}, code: "good-code", nonce: "nonce-1", wantErr: true},
+		{name: "azp mismatch", claims: func(issuer string) jwt.MapClaims {
+			c := validClaims(issuer)
+			c["aud"] = []string{"client-1", "client-2"}
+			c["azp"] = "client-2"
+			return c
+		}, code: "good-code", nonce: "nonce-1", wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			idp.claims = tc.claims
+			info, err := client.GetOIDCUserInfo(tc.code, tc.nonce)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("GetOIDCUserInfo() = %+v, want error", info)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetOIDCUserInfo() error = %v", err)
+			}
+			want := UserInfo{ID: "user-1", Name: "Alice", Email: "alice@example.com"}
+			if *info != want {
+				t.Errorf("GetOIDCUserInfo() = %+v, want %+v", *info, want)
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenForgedKey(t *testing.T) {
+	idp := newStubIdP(t)
+	client := newOIDCClient(t, idp)
+
+	forged, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, validClaims(idp.URL))
+	token.Header["kid"] = "k1"
+	signed, err := token.SignedString(forged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.VerifyIDToken(signed, "nonce-1"); err == nil {
+		t.Fatal("VerifyIDToken() accepted a token signed by another key")
+	}
+}
@@ -0,0 +1,307 @@
+package saml
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	gosaml "github.com/crewjam/saml"
+	"github.com/crewjam/saml/samlsp"
+	dsig "github.com/russellhaering/goxmldsig"
+
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/log"
+)
+
+type Client struct {
+	logger *log.Logger
+	ctx    context.Context
+	config *Config
+	sp     *gosaml.ServiceProvider
+}
+
+type Config struct {
+	BaseURL           string `json:"base_url"`            // SP 对外地址，如 https://wiki.example.com
+	KBID              string `json:"kb_id"`               // 元数据与 ACS 地址按知识库区分
+	EntityID          string `json:"entity_id"`           // SP Entity ID，默认为元数据地址
+	IdPMetadataURL    string `json:"idp_metadata_url"`    // IdP 元数据地址
+	IdPMetadataXML    string `json:"idp_metadata_xml"`    // IdP 元数据内容，优先于地址
+	Certificate       string `json:"certificate"`         // SP 证书 PEM
+	PrivateKey        string `json:"private_key"`         // SP 私钥 PEM，用于签名请求及解密断言
+	SignRequest       bool   `json:"sign_request"`        // 是否签名 AuthnRequest
+	AllowIDPInitiated bool   `json:"allow_idp_initiated"` // 是否接受 IdP 发起的登录
+	UsernameAttr      string `json:"username_attr"`       // 用户名属性，默认 displayName
+	EmailAttr         string `json:"email_attr"`          // 邮箱属性，默认 email
+	AvatarAttr        string `json:"avatar_attr"`         // 头像属性
+	GroupsAttr        string `json:"groups_attr"`         // 用户组属性
+}
+
+type UserInfo struct {
+	NameID    string   `json:"name_id"`
+	Username  string   `json:"username"`
+	Email     string   `json:"email"`
+	AvatarUrl string   `json:"avatar_url"`
+	Groups    []string `json:"groups"`
+}
+
+const (
+	metadataPath    = "/share/v1/openapi/saml/metadata/"
+	acsPath         = "/share/v1/openapi/saml/acs/"
+	metadataPathPro = "/share/pro/v1/openapi/saml/metadata/"
+	acsPathPro      = "/share/pro/v1/openapi/saml/acs/"
+
+	defaultUsernameAttr = "displayName"
+	defaultEmailAttr    = "email"
+
+	metadataTimeout = 10 * time.Second
+)
+
+// NewClient 创建SAML SP客户端，会读取 IdP 元数据
+func NewClient(ctx context.Context, logger *log.Logger, config Config) (*Client, error) {
+	if config.UsernameAttr == "" {
+		config.UsernameAttr = defaultUsernameAttr
+	}
+	if config.EmailAttr == "" {
+		config.EmailAttr = defaultEmailAttr
+	}
+
+	baseURL, err := url.Parse(config.BaseURL)
+	if err != nil || baseURL.Scheme == "" || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid base URL: %q", config.BaseURL)
+	}
+	baseURL.Path, baseURL.RawQuery, baseURL.Fragment = "", "", ""
+	metadataURL, acsURL := *baseURL, *baseURL
+	metadataURL.Path, acsURL.Path = metadataPath+config.KBID, acsPath+config.KBID
+	if edition, _ := ctx.Value(consts.ContextKeyEdition).(consts.LicenseEdition); edition > consts.LicenseEditionFree {
+		metadataURL.Path, acsURL.Path = metadataPathPro+config.KBID, acsPathPro+config.KBID
+	}
+
+	idpMetadata, err := loadIdPMetadata(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	sp := &gosaml.ServiceProvider{
+		EntityID:          config.EntityID,
+		MetadataURL:       metadataURL,
+		AcsURL:            acsURL,
+		IDPMetadata:       idpMetadata,
+		AuthnNameIDFormat: gosaml.UnspecifiedNameIDFormat,
+		ValidateRequestID: validateRequestID,
+	}
+	if config.Certificate != "" || config.PrivateKey != "" {
+		pair, err := tls.X509KeyPair([]byte(config.Certificate), []byte(config.PrivateKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid SP key pair: %w", err)
+		}
+		sp.Certificate, err = x509.ParseCertificate(pair.Certificate[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid SP certificate: %w", err)
+		}
+		key, ok := pair.PrivateKey.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("SP private key must be RSA")
+		}
+		sp.Key = key
+	}
+	if config.SignRequest {
+		if sp.Key == nil {
+			return nil, errors.New("SP key pair is required to sign requests")
+		}
+		sp.SignatureMethod = dsig.RSASHA256SignatureMethod
+	}
+
+	return &Client{
+		ctx:    ctx,
+		logger: logger.WithModule("pkg.saml"),
+		config: &config,
+		sp:     sp,
+	}, nil
+}
+
+func loadIdPMetadata(ctx context.Context, config Config) (*gosaml.EntityDescriptor, error) {
+	if config.IdPMetadataXML != "" {
+		metadata, err := samlsp.ParseMetadata([]byte(config.IdPMetadataXML))
+		if err != nil {
+			return nil, fmt.Errorf("invalid IdP metadata: %w", err)
+		}
+		return metadata, nil
+	}
+	if config.IdPMetadataURL == "" {
+		return nil, errors.New("IdP metadata is required")
+	}
+	metadataURL, err := url.Parse(config.IdPMetadataURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IdP metadata URL: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, metadataTimeout)
+	defer cancel()
+	metadata, err := samlsp.FetchMetadata(ctx, http.DefaultClient, *metadataURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetch IdP metadata failed: %w", err)
+	}
+	return metadata, nil
+}
+
+// validateRequestID 由 SP 发起的登录须响应对应的请求，IdP 发起的登录不能携带 InResponseTo
+func validateRequestID(response gosaml.Response, possibleRequestIDs []string) error {
+	if len(possibleRequestIDs) == 0 {
+		if response.InResponseTo != "" {
+			return fmt.Errorf("unsolicited response must not have InResponseTo %q", response.InResponseTo)
+		}
+		return nil
+	}
+	if !slices.Contains(possibleRequestIDs, response.InResponseTo) {
+		return fmt.Errorf("InResponseTo %q does not match the request", response.InResponseTo)
+	}
+	return nil
+}
+
+// Metadata 生成 SP 元数据，仅声明 HTTP-POST 方式的 ACS
+func (c *Client) Metadata() ([]byte, error) {
+	metadata := c.sp.Metadata()
+	for i := range metadata.SPSSODescriptors {
+		descriptor := &metadata.SPSSODescriptors[i]
+		descriptor.AssertionConsumerServices = slices.DeleteFunc(descriptor.AssertionConsumerServices, func(e gosaml.IndexedEndpoint) bool {
+			return e.Binding != gosaml.HTTPPostBinding
+		})
+	}
+	buf, err := xml.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), buf...), nil
+}
+
+// GetLoginURL 生成 SP 发起登录的 IdP 地址及请求 ID，回调时以请求 ID 校验响应
+func (c *Client) GetLoginURL(relayState string) (string, string, error) {
+	location := c.sp.GetSSOBindingLocation(gosaml.HTTPRedirectBinding)
+	if location == "" {
+		return "", "", errors.New("IdP does not support HTTP-Redirect binding")
+	}
+	req, err := c.sp.MakeAuthenticationRequest(location, gosaml.HTTPRedirectBinding, gosaml.HTTPPostBinding)
+	if err != nil {
+		return "", "", err
+	}
+	loginURL, err := req.Redirect(url.QueryEscape(relayState), c.sp)
+	if err != nil {
+		return "", "", err
+	}
+	return loginURL.String(), req.ID, nil
+}
+
+// ParseResponse 校验 IdP 以 HTTP-POST 提交的响应及其签名，requestID 为空时按 IdP 发起的登录处理
+func (c *Client) ParseResponse(samlResponse, requestID string) (*UserInfo, error) {
+	raw, err := base64.StdEncoding.DecodeString(samlResponse)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SAMLResponse: %w", err)
+	}
+
+	var possibleRequestIDs []string
+	if requestID != "" {
+		possibleRequestIDs = []string{requestID}
+	} else if !c.config.AllowIDPInitiated {
+		return nil, errors.New("IdP initiated login is not allowed")
+	}
+	// 断言的 SubjectConfirmation 仅在 IdP 发起时不校验请求 ID
+	sp := *c.sp
+	sp.AllowIDPInitiated = requestID == ""
+
+	assertion, err := sp.ParseXMLResponse(raw, possibleRequestIDs, sp.AcsURL)
+	if err != nil {
+		var invalid *gosaml.InvalidResponseError
+		if errors.As(err, &invalid) {
+			c.logger.Warn("invalid SAML response", log.Error(invalid.PrivateErr))
+			return nil, fmt.Errorf("invalid SAML response: %w", invalid.PrivateErr)
+		}
+		return nil, err
+	}
+
+	if assertion.Subject == nil || assertion.Subject.NameID == nil || assertion.Subject.NameID.Value == "" {
+		return nil, errors.New("SAML assertion has no NameID")
+	}
+	userInfo := &UserInfo{
+		NameID:    assertion.Subject.NameID.Value,
+		Username:  attributeValue(assertion, c.config.UsernameAttr),
+		Email:     attributeValue(assertion, c.config.EmailAttr),
+		AvatarUrl: attributeValue(assertion, c.config.AvatarAttr),
+		Groups:    attributeValues(assertion, c.config.GroupsAttr),
+	}
+	if userInfo.Email == "" && assertion.Subject.NameID.Format == string(gosaml.EmailAddressNameIDFormat) {
+		userInfo.Email = userInfo.NameID
+	}
+	if userInfo.Username == "" {
+		userInfo.Username = userInfo.NameID
+	}
+	return userInfo, nil
+}
+
+func attributeValue(assertion *gosaml.Assertion, name string) string {
+	if values := attributeValues(assertion, name); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// attributeValues 按 Name 或 FriendlyName 查找属性
+func attributeValues(assertion *gosaml.Assertion, name string) []string {
+	if name == "" {
+		return nil
+	}
+	var values []string
+	for _, statement := range assertion.AttributeStatements {
+		for _, attr := range statement.Attributes {
+			if attr.Name != name && attr.FriendlyName != name {
+				continue
+			}
+			for _, v := range attr.Values {
+				if v := strings.TrimSpace(v.Value); v != "" {
+					values = append(values, v)
+				}
+			}
+		}
+	}
+	return values
+}
+
+// GenerateKeyPair 生成自签名的 SP 证书及私钥 PEM
+func GenerateKeyPair(commonName string) (string, string, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", "", err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return "", "", err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return "", "", err
+	}
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return string(cert), string(keyPEM), nil
+}
@@ -0,0 +1,246 @@
+package saml
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	gosaml "github.com/crewjam/saml"
+	"github.com/crewjam/saml/logger"
+	"github.com/crewjam/saml/samlsp"
+
+	"github.com/chaitin/panda-wiki/config"
+	"github.com/chaitin/panda-wiki/log"
+)
+
+// stubIdP signs assertions for a fixed user for the one service provider it knows
+type stubIdP struct {
+	idp *gosaml.IdentityProvider
+	sp  *gosaml.EntityDescriptor
+}
+
+func (s *stubIdP) GetSession(w http.ResponseWriter, r *http.Request, req *gosaml.IdpAuthnRequest) *gosaml.Session {
+	return &gosaml.Session{
+		ID:             "session-1",
+		NameID:         "alice@example.com",
+		NameIDFormat:   string(gosaml.EmailAddressNameIDFormat),
+		UserName:       "alice",
+		UserEmail:      "alice@example.com",
+		UserCommonName: "Alice",
+		Groups:         []string{"engineering", "staff"},
+	}
+}
+
+func (s *stubIdP) GetServiceProvider(r *http.Request, serviceProviderID string) (*gosaml.EntityDescriptor, error) {
+	if s.sp == nil || serviceProviderID != s.sp.EntityID {
+		return nil, os.ErrNotExist
+	}
+	return s.sp, nil
+}
+
+func newStubIdP(t *testing.T) *stubIdP {
+	t.Helper()
+	certPEM, keyPEM, err := GenerateKeyPair("stub idp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pair, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, _ := url.Parse("https://idp.example.com")
+	s := &stubIdP{}
+	s.idp = &gosaml.IdentityProvider{
+		Key:                     pair.PrivateKey,
+		Certificate:             cert,
+		Logger:                  logger.DefaultLogger,
+		MetadataURL:             *base.JoinPath("metadata"),
+		SSOURL:                  *base.JoinPath("sso"),
+		ServiceProviderProvider: s,
+		SessionProvider:         s,
+	}
+	return s
+}
+
+func (s *stubIdP) metadataXML(t *testing.T) string {
+	t.Helper()
+	buf, err := xml.Marshal(s.idp.Metadata())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(buf)
+}
+
+// register parses the SP metadata the way an administrator uploads it to the IdP
+func (s *stubIdP) register(t *testing.T, client *Client) {
+	t.Helper()
+	metadata, err := client.Metadata()
+	if err != nil {
+		t.Fatalf("Metadata() error = %v", err)
+	}
+	s.sp, err = samlsp.ParseMetadata(metadata)
+	if err != nil {
+		t.Fatalf("parse SP metadata: %v", err)
+	}
+}
+
+var samlResponseInput = regexp.MustCompile(`name="SAMLResponse" value="([^"]+)"`)
+
+func postedResponse(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	m := samlResponseInput.FindStringSubmatch(rec.Body.String())
+	if m == nil {
+		t.Fatalf("IdP did not post a response: %d %s", rec.Code, rec.Body.String())
+	}
+	return html.UnescapeString(m[1])
+}
+
+// newTestClient registers an SP at idp, with a key pair the IdP encrypts the assertions
+func newTestClient(t *testing.T, idp *stubIdP, allowIDPInitiated bool) *Client {
+	t.Helper()
+	certPEM, keyPEM, err := GenerateKeyPair("stub sp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newTestClientWithKeyPair(t, idp, allowIDPInitiated, certPEM, keyPEM)
+}
+
+func newTestClientWithKeyPair(t *testing.T, idp *stubIdP, allowIDPInitiated bool, certPEM, keyPEM string) *Client {
+	t.Helper()
+	cfg, _ := config.NewConfig()
+	client, err := NewClient(context.Background(), log.NewLogger(cfg), Config{
+		BaseURL:           "https://wiki.example.com/",
+		KBID:              "kb-1",
+		IdPMetadataXML:    idp.metadataXML(t),
+		Certificate:       certPEM,
+		PrivateKey:        keyPEM,
+		SignRequest:       keyPEM != "",
+		AllowIDPInitiated: allowIDPInitiated,
+		UsernameAttr:      "cn",
+		EmailAttr:         "mail",
+		GroupsAttr:        "eduPersonAffiliation",
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	idp.register(t, client)
+	return client
+}
+
+func TestMetadata(t *testing.T) {
+	idp := newStubIdP(t)
+	client := newTestClient(t, idp, false)
+
+	if idp.sp.EntityID != "https://wiki.example.com/share/v1/openapi/saml/metadata/kb-1" {
+		t.Errorf("EntityID = %q", idp.sp.EntityID)
+	}
+	acs := idp.sp.SPSSODescriptors[0].AssertionConsumerServices
+	if len(acs) != 1 || acs[0].Binding != gosaml.HTTPPostBinding || acs[0].Location != "https://wiki.example.com/share/v1/openapi/saml/acs/kb-1" {
+		t.Errorf("AssertionConsumerServices = %+v, want the POST endpoint only", acs)
+	}
+	if client.sp.Certificate == nil || len(idp.sp.SPSSODescriptors[0].KeyDescriptors) == 0 {
+		t.Error("metadata has no SP certificate")
+	}
+}
+
+func TestSPInitiatedLogin(t *testing.T) {
+	idp := newStubIdP(t)
+	client := newTestClient(t, idp, false)
+
+	loginURL, requestID, err := client.GetLoginURL("state-1")
+	if err != nil {
+		t.Fatalf("GetLoginURL() error = %v", err)
+	}
+	if !strings.HasPrefix(loginURL, "https://idp.example.com/sso?") || !strings.Contains(loginURL, "Signature=") {
+		t.Fatalf("login url = %s, want a signed redirect to the IdP", loginURL)
+	}
+
+	rec := httptest.NewRecorder()
+	idp.idp.ServeSSO(rec, httptest.NewRequest(http.MethodGet, loginURL, nil))
+	samlResponse := postedResponse(t, rec)
+
+	info, err := client.ParseResponse(samlResponse, requestID)
+	if err != nil {
+		t.Fatalf("ParseResponse() error = %v", err)
+	}
+	if info.NameID != "alice@example.com" || info.Username != "Alice" || info.Email != "alice@example.com" {
+		t.Errorf("ParseResponse() = %+v", info)
+	}
+	if !slices.Equal(info.Groups, []string{"engineering", "staff"}) {
+		t.Errorf("Groups = %v", info.Groups)
+	}
+
+	if _, err := client.ParseResponse(samlResponse, "other-request"); err == nil {
+		t.Error("ParseResponse() accepted a response to another request")
+	}
+	if _, err := client.ParseResponse(samlResponse, ""); err == nil {
+		t.Error("ParseResponse() accepted a solicited response as IdP initiated")
+	}
+}
+
+func TestIDPInitiatedLogin(t *testing.T) {
+	idp := newStubIdP(t)
+
+	for _, allow := range []bool{true, false} {
+		client := newTestClient(t, idp, allow)
+		rec := httptest.NewRecorder()
+		idp.idp.ServeIDPInitiated(rec, httptest.NewRequest(http.MethodGet, "https://idp.example.com/login", nil), idp.sp.EntityID, "")
+		samlResponse := postedResponse(t, rec)
+
+		info, err := client.ParseResponse(samlResponse, "")
+		if allow && (err != nil || info.NameID != "alice@example.com") {
+			t.Errorf("ParseResponse() = %+v, %v, want alice", info, err)
+		}
+		if !allow && err == nil {
+			t.Error("ParseResponse() accepted an IdP initiated login while it is disabled")
+		}
+	}
+}
+
+func TestTamperedResponse(t *testing.T) {
+	idp := newStubIdP(t)
+	// without an SP key pair the assertion is sent in plain text
+	client := newTestClientWithKeyPair(t, idp, true, "", "")
+
+	rec := httptest.NewRecorder()
+	idp.idp.ServeIDPInitiated(rec, httptest.NewRequest(http.MethodGet, "https://idp.example.com/login", nil), idp.sp.EntityID, "")
+	raw, err := base64.StdEncoding.DecodeString(postedResponse(t, rec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := strings.ReplaceAll(string(raw), "alice@example.com", "mallory@example.com")
+	if tampered == string(raw) {
+		t.Fatal("response does not contain the NameID")
+	}
+
+	if _, err := client.ParseResponse(base64.StdEncoding.EncodeToString([]byte(tampered)), ""); err == nil {
+		t.Fatal("ParseResponse() accepted a tampered assertion")
+	}
+}
+
+func TestUntrustedIdP(t *testing.T) {
+	trusted, rogue := newStubIdP(t), newStubIdP(t)
+	client := newTestClient(t, trusted, true)
+	rogue.register(t, client)
+
+	rec := httptest.NewRecorder()
+	rogue.idp.ServeIDPInitiated(rec, httptest.NewRequest(http.MethodGet, "https://idp.example.com/login", nil), rogue.sp.EntityID, "")
+	if _, err := client.ParseResponse(postedResponse(t, rec), ""); err == nil {
+		t.Fatal("ParseResponse() accepted an assertion signed by another key")
+	}
+}
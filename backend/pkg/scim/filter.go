@@ -0,0 +1,65 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+var filterRe = regexp.MustCompile(`(?i)^\s*([a-z][\w.:$-]*)\s+eq\s+(.+?)\s*$`)
+
+// Filter 仅支持 attr eq value，IdP 查找已有用户和用户组时只使用该形式
+type Filter struct {
+	Attr  string
+	Value string
+}
+
+// ParseFilter 解析 filter 查询参数，为空时返回 nil
+func ParseFilter(s string) (*Filter, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	m := filterRe.FindStringSubmatch(s)
+	if m == nil {
+		return nil, NewError(http.StatusBadRequest, ErrInvalidFilter, "only the eq operator is supported")
+	}
+	value, err := parseFilterValue(m[2])
+	if err != nil {
+		return nil, err
+	}
+	return &Filter{Attr: stripCoreSchema(m[1]), Value: value}, nil
+}
+
+// Is 属性名不区分大小写
+func (f *Filter) Is(attr string) bool {
+	return strings.EqualFold(f.Attr, attr)
+}
+
+func parseFilterValue(s string) (string, error) {
+	if strings.HasPrefix(s, `"`) {
+		var value string
+		if err := json.Unmarshal([]byte(s), &value); err != nil {
+			return "", NewError(http.StatusBadRequest, ErrInvalidFilter, "invalid string value "+s)
+		}
+		return value, nil
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "null":
+		return strings.ToLower(s), nil
+	}
+	if strings.ContainsAny(s, " \t()[]\"") {
+		return "", NewError(http.StatusBadRequest, ErrInvalidFilter, "invalid value "+s)
+	}
+	return s, nil
+}
+
+// stripCoreSchema 去掉属性名前的核心 schema，扩展 schema 保留
+func stripCoreSchema(attr string) string {
+	for _, schema := range []string{SchemaUser, SchemaGroup} {
+		if len(attr) > len(schema) && strings.EqualFold(attr[:len(schema)+1], schema+":") {
+			return attr[len(schema)+1:]
+		}
+	}
+	return attr
+}
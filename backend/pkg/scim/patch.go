@@ -0,0 +1,283 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var valuePathRe = regexp.MustCompile(`^([^\[\]]+)\[([^\[\]]+)\](?:\.([^\[\]]+))?$`)
+
+// patchPath PATCH 的 path，如 active、name.formatted、members[value eq "1"]、emails[type eq "work"].value，
+// 扩展属性以 schema 为前缀
+type patchPath struct {
+	schema string
+	attr   string
+	filter *Filter
+	sub    string
+}
+
+func parsePatchPath(path string) (*patchPath, error) {
+	path = stripCoreSchema(strings.TrimSpace(path))
+	p := &patchPath{}
+	if strings.HasPrefix(strings.ToLower(path), "urn:") {
+		i := strings.LastIndex(path, ":")
+		p.schema, path = path[:i], path[i+1:]
+	}
+	if m := valuePathRe.FindStringSubmatch(path); m != nil {
+		filter, err := ParseFilter(m[2])
+		if err != nil {
+			return nil, NewError(http.StatusBadRequest, ErrInvalidPath, "invalid value filter in path "+path)
+		}
+		p.attr, p.filter, p.sub = m[1], filter, m[3]
+	} else if attr, sub, ok := strings.Cut(path, "."); ok {
+		p.attr, p.sub = attr, sub
+	} else {
+		p.attr = path
+	}
+	if p.attr == "" || strings.ContainsAny(p.attr+p.sub, "[] ") {
+		return nil, NewError(http.StatusBadRequest, ErrInvalidPath, "invalid path "+path)
+	}
+	return p, nil
+}
+
+// ApplyPatch 按 RFC 7644 3.5.2 依次执行 add、replace、remove 操作，属性名不区分大小写
+func ApplyPatch[T any](resource *T, ops []PatchOperation) error {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	doc := make(map[string]any)
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	for _, op := range ops {
+		if err := applyOperation(doc, op); err != nil {
+			return err
+		}
+	}
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	var patched T
+	if err := json.Unmarshal(data, &patched); err != nil {
+		return NewError(http.StatusBadRequest, ErrInvalidValue, err.Error())
+	}
+	*resource = patched
+	return nil
+}
+
+func applyOperation(doc map[string]any, op PatchOperation) error {
+	kind := strings.ToLower(op.Op)
+	if kind != "add" && kind != "replace" && kind != "remove" {
+		return NewError(http.StatusBadRequest, ErrInvalidSyntax, "unsupported op "+op.Op)
+	}
+	var value any
+	if len(op.Value) > 0 {
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return NewError(http.StatusBadRequest, ErrInvalidSyntax, "invalid value")
+		}
+	}
+
+	if op.Path == "" {
+		if kind == "remove" {
+			return NewError(http.StatusBadRequest, ErrNoTarget, "path is required for remove")
+		}
+		values, ok := value.(map[string]any)
+		if !ok {
+			return NewError(http.StatusBadRequest, ErrInvalidValue, "value must be an object when path is empty")
+		}
+		for k, v := range values {
+			paths := map[string]any{k: v}
+			// 扩展 schema 作为键时值为其属性
+			if ext, ok := v.(map[string]any); ok && strings.HasPrefix(strings.ToLower(k), "urn:") {
+				paths = make(map[string]any, len(ext))
+				for attr, v := range ext {
+					paths[k+":"+attr] = v
+				}
+			}
+			for path, v := range paths {
+				if err := applyOperation(doc, PatchOperation{Op: kind, Path: path, Value: mustMarshal(v)}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	path, err := parsePatchPath(op.Path)
+	if err != nil {
+		return err
+	}
+	target := doc
+	if path.schema != "" {
+		ext, _ := doc[lookupKey(doc, path.schema)].(map[string]any)
+		if ext == nil {
+			if kind == "remove" {
+				return nil
+			}
+			ext = make(map[string]any)
+		}
+		doc[lookupKey(doc, path.schema)] = ext
+		target = ext
+	}
+	value = normalizeValue(path.attr, value)
+	key := lookupKey(target, path.attr)
+
+	if path.filter != nil {
+		return applyFiltered(target, key, path, kind, value)
+	}
+
+	if path.sub != "" {
+		parent, _ := target[key].(map[string]any)
+		if parent == nil {
+			if kind == "remove" {
+				return nil
+			}
+			parent = make(map[string]any)
+			target[key] = parent
+		}
+		if kind == "remove" {
+			delete(parent, lookupKey(parent, path.sub))
+		} else {
+			parent[lookupKey(parent, path.sub)] = value
+		}
+		return nil
+	}
+
+	switch kind {
+	case "add":
+		existing, isList := target[key].([]any)
+		if values, ok := value.([]any); ok && isList {
+			target[key] = append(existing, values...)
+		} else if ok {
+			target[key] = values
+		} else if isList {
+			target[key] = append(existing, value)
+		} else {
+			target[key] = value
+		}
+	case "replace":
+		target[key] = value
+	case "remove":
+		existing, isList := target[key].([]any)
+		values, ok := value.([]any)
+		if !isList || !ok {
+			delete(target, key)
+			return nil
+		}
+		// Azure AD 等以 value 指定要移除的成员
+		target[key] = removeElements(existing, func(elem map[string]any) bool {
+			for _, v := range values {
+				if v, ok := v.(map[string]any); ok && sameValue(elem["value"], v["value"]) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	return nil
+}
+
+// applyFiltered 作用于多值属性中满足过滤条件的元素
+func applyFiltered(target map[string]any, key string, path *patchPath, kind string, value any) error {
+	existing, _ := target[key].([]any)
+	match := func(elem map[string]any) bool {
+		return sameValue(elem[lookupKey(elem, path.filter.Attr)], path.filter.Value)
+	}
+
+	if kind == "remove" {
+		if path.sub == "" {
+			target[key] = removeElements(existing, match)
+			return nil
+		}
+		for _, elem := range existing {
+			if elem, ok := elem.(map[string]any); ok && match(elem) {
+				delete(elem, lookupKey(elem, path.sub))
+			}
+		}
+		return nil
+	}
+
+	matched := false
+	for _, elem := range existing {
+		elem, ok := elem.(map[string]any)
+		if !ok || !match(elem) {
+			continue
+		}
+		matched = true
+		if path.sub != "" {
+			elem[lookupKey(elem, path.sub)] = value
+			continue
+		}
+		values, ok := value.(map[string]any)
+		if !ok {
+			return NewError(http.StatusBadRequest, ErrInvalidValue, "value must be an object")
+		}
+		for k, v := range values {
+			elem[lookupKey(elem, k)] = v
+		}
+	}
+	if matched {
+		return nil
+	}
+	if path.sub == "" {
+		return NewError(http.StatusBadRequest, ErrNoTarget, "no value matches "+path.filter.Attr+" eq "+path.filter.Value)
+	}
+	// 如 emails[type eq "work"].value 在没有工作邮箱时新增
+	target[key] = append(existing, map[string]any{
+		path.filter.Attr: path.filter.Value,
+		path.sub:         value,
+	})
+	return nil
+}
+
+func removeElements(list []any, match func(map[string]any) bool) []any {
+	result := make([]any, 0, len(list))
+	for _, elem := range list {
+		if elem, ok := elem.(map[string]any); ok && match(elem) {
+			continue
+		}
+		result = append(result, elem)
+	}
+	return result
+}
+
+// lookupKey 返回 m 中与 name 仅大小写不同的已有键
+func lookupKey(m map[string]any, name string) string {
+	if _, ok := m[name]; ok {
+		return name
+	}
+	for k := range m {
+		if strings.EqualFold(k, name) {
+			return k
+		}
+	}
+	return name
+}
+
+func sameValue(a, b any) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	return strings.EqualFold(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// normalizeValue Azure AD 以字符串 "True"/"False" 传递 active
+func normalizeValue(attr string, value any) any {
+	if s, ok := value.(string); ok && strings.EqualFold(attr, "active") {
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+func mustMarshal(v any) json.RawMessage {
+	data, _ := json.Marshal(v)
+	return data
+}
@@ -0,0 +1,233 @@
+// Package scim 实现 SCIM 2.0 (RFC 7643/7644) 中读者开通所需的资源、过滤及 PATCH 语义
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ContentType = "application/scim+json"
+
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	// SchemaGroupExtension 用户组扩展，parent 为上级用户组的 id
+	SchemaGroupExtension = "urn:ietf:params:scim:schemas:extension:pandawiki:2.0:Group"
+)
+
+// scimType 错误类型
+const (
+	ErrInvalidFilter = "invalidFilter"
+	ErrInvalidSyntax = "invalidSyntax"
+	ErrInvalidPath   = "invalidPath"
+	ErrInvalidValue  = "invalidValue"
+	ErrNoTarget      = "noTarget"
+	ErrUniqueness    = "uniqueness"
+	ErrMutability    = "mutability"
+)
+
+type Meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location,omitempty"`
+}
+
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// MultiValue 多值属性的元素，如 emails、members
+type MultiValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type User struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	ExternalID  string       `json:"externalId,omitempty"`
+	UserName    string       `json:"userName"`
+	Name        *Name        `json:"name,omitempty"`
+	DisplayName string       `json:"displayName,omitempty"`
+	Emails      []MultiValue `json:"emails,omitempty"`
+	Active      *bool        `json:"active,omitempty"` // 未提供时视为启用
+	Meta        *Meta        `json:"meta,omitempty"`
+}
+
+// FormattedName 依次取 displayName、name.formatted、姓名拼接及 userName
+func (u *User) FormattedName() string {
+	if name := strings.TrimSpace(u.DisplayName); name != "" {
+		return name
+	}
+	if u.Name != nil {
+		if name := strings.TrimSpace(u.Name.Formatted); name != "" {
+			return name
+		}
+		if name := strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName); name != "" {
+			return name
+		}
+	}
+	return u.UserName
+}
+
+// PrimaryEmail 主邮箱，未标记时取第一个
+func (u *User) PrimaryEmail() string {
+	for _, email := range u.Emails {
+		if email.Primary {
+			return email.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return ""
+}
+
+func (u *User) IsActive() bool {
+	return u.Active == nil || *u.Active
+}
+
+type GroupExtension struct {
+	Parent string `json:"parent"`
+}
+
+type Group struct {
+	Schemas     []string        `json:"schemas"`
+	ID          string          `json:"id,omitempty"`
+	ExternalID  string          `json:"externalId,omitempty"`
+	DisplayName string          `json:"displayName"`
+	Members     []MultiValue    `json:"members,omitempty"`
+	Extension   *GroupExtension `json:"urn:ietf:params:scim:schemas:extension:pandawiki:2.0:Group,omitempty"`
+	Meta        *Meta           `json:"meta,omitempty"`
+}
+
+// ParentID 扩展中的上级用户组
+func (g *Group) ParentID() string {
+	if g.Extension == nil {
+		return ""
+	}
+	return g.Extension.Parent
+}
+
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    any      `json:"Resources"`
+}
+
+func NewListResponse[T any](resources []T, total, startIndex int) *ListResponse {
+	if resources == nil {
+		resources = []T{}
+	}
+	return &ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Error SCIM 错误响应，status 按规范为字符串
+type Error struct {
+	Status   int
+	ScimType string
+	Detail   string
+}
+
+func NewError(status int, scimType, detail string) *Error {
+	return &Error{Status: status, ScimType: scimType, Detail: detail}
+}
+
+func (e *Error) Error() string {
+	if e.ScimType == "" {
+		return fmt.Sprintf("scim: %d %s", e.Status, e.Detail)
+	}
+	return fmt.Sprintf("scim: %d %s: %s", e.Status, e.ScimType, e.Detail)
+}
+
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Schemas  []string `json:"schemas"`
+		Status   string   `json:"status"`
+		ScimType string   `json:"scimType,omitempty"`
+		Detail   string   `json:"detail,omitempty"`
+	}{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(e.Status),
+		ScimType: e.ScimType,
+		Detail:   e.Detail,
+	})
+}
+
+type supported struct {
+	Supported bool `json:"supported"`
+}
+
+type filterSupported struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+type bulkSupported struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+type AuthenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type ServiceProviderConfig struct {
+	Schemas               []string               `json:"schemas"`
+	Patch                 supported              `json:"patch"`
+	Bulk                  bulkSupported          `json:"bulk"`
+	Filter                filterSupported        `json:"filter"`
+	ChangePassword        supported              `json:"changePassword"`
+	Sort                  supported              `json:"sort"`
+	ETag                  supported              `json:"etag"`
+	AuthenticationSchemes []AuthenticationScheme `json:"authenticationSchemes"`
+}
+
+// NewServiceProviderConfig 支持 PATCH 及 eq 过滤，使用 Bearer Token 认证
+func NewServiceProviderConfig(maxResults int) *ServiceProviderConfig {
+	return &ServiceProviderConfig{
+		Schemas: []string{SchemaServiceProviderConfig},
+		Patch:   supported{Supported: true},
+		Filter:  filterSupported{Supported: true, MaxResults: maxResults},
+		AuthenticationSchemes: []AuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "Bearer Token",
+			Description: "Authentication with the SCIM token of the knowledge base",
+		}},
+	}
+}
@@ -0,0 +1,144 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		attr   string
+		value  string
+		err    bool
+	}{
+		{filter: ``},
+		{filter: `userName eq "alice@example.com"`, attr: "userName", value: "alice@example.com"},
+		{filter: `urn:ietf:params:scim:schemas:core:2.0:User:externalId EQ "a\"b"`, attr: "externalId", value: `a"b`},
+		{filter: `active eq True`, attr: "active", value: "true"},
+		{filter: `displayName co "sales"`, err: true},
+		{filter: `userName eq "a" and active eq true`, err: true},
+		{filter: `userName eq "unterminated`, err: true},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.filter)
+		if tt.err {
+			var scimErr *Error
+			if !errors.As(err, &scimErr) || scimErr.ScimType != ErrInvalidFilter {
+				t.Errorf("ParseFilter(%q) error = %v, want invalidFilter", tt.filter, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseFilter(%q) error = %v", tt.filter, err)
+			continue
+		}
+		if tt.attr == "" {
+			if f != nil {
+				t.Errorf("ParseFilter(%q) = %+v, want nil", tt.filter, f)
+			}
+			continue
+		}
+		if !f.Is(tt.attr) || f.Value != tt.value {
+			t.Errorf("ParseFilter(%q) = %+v, want %s eq %s", tt.filter, f, tt.attr, tt.value)
+		}
+	}
+}
+
+func parseOps(t *testing.T, s string) []PatchOperation {
+	t.Helper()
+	var req PatchRequest
+	if err := json.Unmarshal([]byte(s), &req); err != nil {
+		t.Fatal(err)
+	}
+	return req.Operations
+}
+
+func TestApplyPatchUser(t *testing.T) {
+	active := true
+	user := &User{
+		Schemas:  []string{SchemaUser},
+		ID:       "1",
+		UserName: "alice",
+		Emails:   []MultiValue{{Value: "alice@old.example.com", Type: "work", Primary: true}},
+		Active:   &active,
+	}
+
+	// Okta 使用带 path 的操作，Azure AD 使用无 path 的对象及字符串形式的布尔值
+	err := ApplyPatch(user, parseOps(t, `{"Operations":[
+		{"op":"replace","path":"emails[type eq \"work\"].value","value":"alice@example.com"},
+		{"op":"Replace","path":"name.formatted","value":"Alice"},
+		{"op":"replace","value":{"displayName":"Alice L","Active":"False"}},
+		{"op":"add","path":"emails[type eq \"home\"].value","value":"alice@home.example.com"}
+	]}`))
+	if err != nil {
+		t.Fatalf("ApplyPatch() error = %v", err)
+	}
+	if user.IsActive() {
+		t.Error("user is still active")
+	}
+	if user.DisplayName != "Alice L" || user.Name == nil || user.Name.Formatted != "Alice" {
+		t.Errorf("names = %q %+v", user.DisplayName, user.Name)
+	}
+	if len(user.Emails) != 2 || user.PrimaryEmail() != "alice@example.com" || user.Emails[1].Value != "alice@home.example.com" {
+		t.Errorf("emails = %+v", user.Emails)
+	}
+	if user.UserName != "alice" || user.ID != "1" {
+		t.Errorf("untouched attributes changed: %+v", user)
+	}
+
+	err = ApplyPatch(user, parseOps(t, `{"Operations":[{"op":"move","path":"active"}]}`))
+	var scimErr *Error
+	if !errors.As(err, &scimErr) || scimErr.Status != http.StatusBadRequest {
+		t.Errorf("ApplyPatch() with an unknown op error = %v", err)
+	}
+}
+
+func TestApplyPatchGroup(t *testing.T) {
+	group := &Group{
+		Schemas:     []string{SchemaGroup},
+		ID:          "7",
+		DisplayName: "Sales",
+		Members:     []MultiValue{{Value: "1"}, {Value: "2"}, {Value: "3"}},
+	}
+
+	err := ApplyPatch(group, parseOps(t, `{"Operations":[
+		{"op":"add","path":"members","value":[{"value":"4"}]},
+		{"op":"remove","path":"members[value eq \"1\"]"},
+		{"op":"remove","path":"members","value":[{"value":"2"}]},
+		{"op":"replace","path":"urn:ietf:params:scim:schemas:extension:pandawiki:2.0:Group:parent","value":"5"}
+	]}`))
+	if err != nil {
+		t.Fatalf("ApplyPatch() error = %v", err)
+	}
+	if len(group.Members) != 2 || group.Members[0].Value != "3" || group.Members[1].Value != "4" {
+		t.Errorf("members = %+v, want 3 and 4", group.Members)
+	}
+	if group.ParentID() != "5" {
+		t.Errorf("parent = %q, want 5", group.ParentID())
+	}
+
+	err = ApplyPatch(group, parseOps(t, `{"Operations":[
+		{"op":"replace","value":{"displayName":"Sales EMEA","urn:ietf:params:scim:schemas:extension:pandawiki:2.0:Group":{"parent":""}}},
+		{"op":"remove","path":"members"}
+	]}`))
+	if err != nil {
+		t.Fatalf("ApplyPatch() error = %v", err)
+	}
+	if group.DisplayName != "Sales EMEA" || group.ParentID() != "" || len(group.Members) != 0 {
+		t.Errorf("group = %+v", group)
+	}
+}
+
+func TestErrorJSON(t *testing.T) {
+	data, err := json.Marshal(NewError(http.StatusConflict, ErrUniqueness, "userName already exists"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":"409","scimType":"uniqueness","detail":"userName already exists"}`
+	if string(data) != want {
+		t.Errorf("json = %s, want %s", data, want)
+	}
+}
@@ -0,0 +1,59 @@
+// Package sharetoken 生成和校验带过期时间的 HMAC 签名 token，用于无需登录的分享链接
+package sharetoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sigSize 签名截取的字节数
+const sigSize = 16
+
+var (
+	ErrInvalid = errors.New("share token is invalid")
+	ErrExpired = errors.New("share token is expired")
+)
+
+var encoding = base64.RawURLEncoding
+
+// Sign 生成 subject.expires.signature 格式的 token，subject 和签名 base64url 编码
+func Sign(secret []byte, subject string, expiresAt time.Time) string {
+	payload := encoding.EncodeToString([]byte(subject)) + "." + strconv.FormatInt(expiresAt.Unix(), 36)
+	return payload + "." + encoding.EncodeToString(sign(secret, payload))
+}
+
+// Verify 校验签名和过期时间，返回 subject 和签名时的过期时间
+func Verify(secret []byte, token string, now time.Time) (string, time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", time.Time{}, ErrInvalid
+	}
+	sig, err := encoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, sign(secret, parts[0]+"."+parts[1])) {
+		return "", time.Time{}, ErrInvalid
+	}
+	subject, err := encoding.DecodeString(parts[0])
+	if err != nil {
+		return "", time.Time{}, ErrInvalid
+	}
+	expires, err := strconv.ParseInt(parts[1], 36, 64)
+	if err != nil {
+		return "", time.Time{}, ErrInvalid
+	}
+	expiresAt := time.Unix(expires, 0)
+	if !now.Before(expiresAt) {
+		return "", time.Time{}, ErrExpired
+	}
+	return string(subject), expiresAt, nil
+}
+
+func sign(secret []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)[:sigSize]
+}
@@ -0,0 +1,56 @@
+package sharetoken
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var secret = []byte("test-secret")
+
+func TestSignVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	expiresAt := now.Add(time.Hour)
+	token := Sign(secret, "link:abc.def", expiresAt)
+
+	subject, gotExpires, err := Verify(secret, token, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "link:abc.def" {
+		t.Errorf("subject = %q", subject)
+	}
+	if !gotExpires.Equal(expiresAt) {
+		t.Errorf("expiresAt = %v, want %v", gotExpires, expiresAt)
+	}
+}
+
+func TestVerifyExpired(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	token := Sign(secret, "id", now)
+	if _, _, err := Verify(secret, token, now); !errors.Is(err, ErrExpired) {
+		t.Errorf("err = %v, want ErrExpired", err)
+	}
+}
+
+func TestVerifyInvalid(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	token := Sign(secret, "id", now.Add(time.Hour))
+	parts := strings.Split(token, ".")
+
+	tests := map[string]string{
+		"wrong secret":     Sign([]byte("other"), "id", now.Add(time.Hour)),
+		"changed subject":  encoding.EncodeToString([]byte("other")) + "." + parts[1] + "." + parts[2],
+		"extended expires": parts[0] + "." + strconv.FormatInt(now.Add(48*time.Hour).Unix(), 36) + "." + parts[2],
+		"missing part":     parts[0] + "." + parts[1],
+		"bad encoding":     parts[0] + "." + parts[1] + ".!!",
+		"empty":            "",
+	}
+	for name, tok := range tests {
+		if _, _, err := Verify(secret, tok, now); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: err = %v, want ErrInvalid", name, err)
+		}
+	}
+}
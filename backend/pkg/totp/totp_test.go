@@ -0,0 +1,64 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret RFC 6238 附录 B 中 SHA1 的测试密钥
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// RFC 6238 附录 B 的 8 位结果取后 6 位
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+	if _, err := Code("not base32!", 1); err == nil {
+		t.Error("Code() with an invalid secret succeeded")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	previous, _ := Code(secret, Step(now)-1)
+	stale, _ := Code(secret, Step(now)-2)
+
+	if step, ok := Validate(secret, previous[:3]+" "+previous[3:], now); !ok || step != Step(now)-1 {
+		t.Errorf("Validate(previous window) = %d %v", step, ok)
+	}
+	if _, ok := Validate(secret, stale, now); ok {
+		t.Error("Validate() accepted a code two windows old")
+	}
+	if _, ok := Validate(secret, "12345", now); ok {
+		t.Error("Validate() accepted a short code")
+	}
+}
+
+func TestURL(t *testing.T) {
+	u := URL("PandaWiki", "admin", "ABC")
+	if !strings.HasPrefix(u, "otpauth://totp/PandaWiki:admin?") || !strings.Contains(u, "secret=ABC") || !strings.Contains(u, "issuer=PandaWiki") {
+		t.Errorf("URL() = %s", u)
+	}
+}
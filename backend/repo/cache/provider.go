@@ -10,4 +10,5 @@ var ProviderSet = wire.NewSet(
 	cache.NewCache,
 	NewKBRepo,
 	NewGeoCache,
+	NewUserSessionRepo,
 )
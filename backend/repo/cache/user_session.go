@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/store/cache"
+)
+
+// sessionTouchInterval 最近活动时间的刷新间隔，避免每个请求都写 redis
+const sessionTouchInterval = time.Minute
+
+// UserSessionRepo 会话保存在 user-session:<id>，每个用户的会话 ID 按过期时间存入有序集合 user-sessions:<user_id>
+type UserSessionRepo struct {
+	cache *cache.Cache
+}
+
+func NewUserSessionRepo(cache *cache.Cache) *UserSessionRepo {
+	return &UserSessionRepo{cache: cache}
+}
+
+func userSessionKey(id string) string {
+	return "user-session:" + id
+}
+
+func userSessionsKey(userID string) string {
+	return "user-sessions:" + userID
+}
+
+func (r *UserSessionRepo) CreateSession(ctx context.Context, session *domain.UserSession) error {
+	value, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	ttl := time.Until(session.ExpiresAt)
+	pipe := r.cache.TxPipeline()
+	pipe.Set(ctx, userSessionKey(session.ID), value, ttl)
+	pipe.ZAdd(ctx, userSessionsKey(session.UserID), redis.Z{
+		Score:  float64(session.ExpiresAt.Unix()),
+		Member: session.ID,
+	})
+	// 会话时长相同，最新会话的过期时间即集合的过期时间
+	pipe.Expire(ctx, userSessionsKey(session.UserID), ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// GetSession 会话不存在或已过期时返回 nil
+func (r *UserSessionRepo) GetSession(ctx context.Context, id string) (*domain.UserSession, error) {
+	value, err := r.cache.Get(ctx, userSessionKey(id)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	session := &domain.UserSession{}
+	if err := json.Unmarshal(value, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// TouchSession 更新最近活动时间和 IP
+func (r *UserSessionRepo) TouchSession(ctx context.Context, session *domain.UserSession, ip string) error {
+	now := time.Now()
+	if now.Sub(session.LastActiveAt) < sessionTouchInterval && session.IP == ip {
+		return nil
+	}
+	session.LastActiveAt = now
+	session.IP = ip
+	value, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	return r.cache.SetArgs(ctx, userSessionKey(session.ID), value, redis.SetArgs{KeepTTL: true, Mode: "XX"}).Err()
+}
+
+// ListSessions 按创建时间倒序，顺带清理已过期的会话 ID
+func (r *UserSessionRepo) ListSessions(ctx context.Context, userID string) ([]*domain.UserSession, error) {
+	key := userSessionsKey(userID)
+	if err := r.cache.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(time.Now().Unix(), 10)).Err(); err != nil {
+		return nil, err
+	}
+	ids, err := r.cache.ZRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]*domain.UserSession, 0, len(ids))
+	for _, id := range ids {
+		session, err := r.GetSession(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if session == nil {
+			// 已被删除
+			if err := r.cache.ZRem(ctx, key, id).Err(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+	slices.SortFunc(sessions, func(a, b *domain.UserSession) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return sessions, nil
+}
+
+func (r *UserSessionRepo) DeleteSession(ctx context.Context, userID, id string) error {
+	pipe := r.cache.TxPipeline()
+	pipe.Del(ctx, userSessionKey(id))
+	pipe.ZRem(ctx, userSessionsKey(userID), id)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// DeleteUserSessions 删除用户的全部会话，exceptID 非空时保留该会话
+func (r *UserSessionRepo) DeleteUserSessions(ctx context.Context, userID, exceptID string) (int, error) {
+	key := userSessionsKey(userID)
+	ids, err := r.cache.ZRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	pipe := r.cache.TxPipeline()
+	for _, id := range ids {
+		if id == exceptID {
+			continue
+		}
+		pipe.Del(ctx, userSessionKey(id))
+		pipe.ZRem(ctx, key, id)
+		count++
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,149 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/store/pg"
+)
+
+// auditLogLockID 追加审计日志时的 advisory lock，保证哈希链按 ID 顺序串行写入
+const auditLogLockID = 0x70776175646974
+
+type AuditLogRepository struct {
+	db     *pg.DB
+	logger *log.Logger
+}
+
+func NewAuditLogRepository(db *pg.DB, logger *log.Logger) *AuditLogRepository {
+	return &AuditLogRepository{
+		db:     db,
+		logger: logger.WithModule("repo.pg.audit_log"),
+	}
+}
+
+// AppendAuditLog 以最后一条记录的 hash 作为 PrevHash 写入
+func (r *AuditLogRepository) AppendAuditLog(ctx context.Context, entry *domain.AuditLog) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditLogLockID).Error; err != nil {
+			return err
+		}
+		var last domain.AuditLog
+		err := tx.Select("hash").Order("id DESC").First(&last).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		entry.PrevHash = last.Hash
+		entry.CreatedAt = time.Now().Truncate(time.Microsecond)
+		hash, err := entry.ComputeHash()
+		if err != nil {
+			return err
+		}
+		entry.Hash = hash
+		return tx.Create(entry).Error
+	})
+}
+
+func (r *AuditLogRepository) filter(ctx context.Context, filter *domain.AuditLogFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&domain.AuditLog{})
+	if filter.KBID != "" {
+		query = query.Where("kb_id = ?", filter.KBID)
+	}
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if !filter.Start.IsZero() {
+		query = query.Where("created_at >= ?", filter.Start)
+	}
+	if !filter.End.IsZero() {
+		query = query.Where("created_at < ?", filter.End)
+	}
+	return query
+}
+
+func (r *AuditLogRepository) ListAuditLogs(ctx context.Context, filter *domain.AuditLogFilter, pager domain.Pager) ([]domain.AuditLog, uint64, error) {
+	query := r.filter(ctx, filter)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	logs := make([]domain.AuditLog, 0)
+	if err := query.
+		Order("id DESC").
+		Offset(pager.Offset()).
+		Limit(pager.Limit()).
+		Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+	return logs, uint64(total), nil
+}
+
+// IterateAuditLogs 按 ID 升序分批读取，filter 为 nil 时遍历全部
+func (r *AuditLogRepository) IterateAuditLogs(ctx context.Context, filter *domain.AuditLogFilter, batchSize int, fn func([]domain.AuditLog) error) error {
+	if filter == nil {
+		filter = &domain.AuditLogFilter{}
+	}
+	var lastID int64
+	for {
+		logs := make([]domain.AuditLog, 0, batchSize)
+		if err := r.filter(ctx, filter).
+			Where("id > ?", lastID).
+			Order("id ASC").
+			Limit(batchSize).
+			Find(&logs).Error; err != nil {
+			return err
+		}
+		if len(logs) == 0 {
+			return nil
+		}
+		if err := fn(logs); err != nil {
+			return err
+		}
+		if len(logs) < batchSize {
+			return nil
+		}
+		lastID = logs[len(logs)-1].ID
+	}
+}
+
+// DeleteAuditLogsBefore 只删除最早的连续记录，保留的记录仍可从第一条开始校验
+func (r *AuditLogRepository) DeleteAuditLogsBefore(ctx context.Context, before time.Time) (int64, error) {
+	var maxID int64
+	if err := r.db.WithContext(ctx).Model(&domain.AuditLog{}).
+		Where("created_at < ?", before).
+		Select("COALESCE(MAX(id), 0)").
+		Scan(&maxID).Error; err != nil {
+		return 0, err
+	}
+	if maxID == 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).Where("id <= ?", maxID).Delete(&domain.AuditLog{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *AuditLogRepository) GetAuditLogSetting(ctx context.Context) (*domain.AuditLogSetting, error) {
+	setting := &domain.AuditLogSetting{}
+	if err := getSettingValue(ctx, r.db, "", domain.SettingAuditLog, setting); err != nil {
+		return nil, err
+	}
+	return setting, nil
+}
+
+func (r *AuditLogRepository) UpsertAuditLogSetting(ctx context.Context, setting *domain.AuditLogSetting) error {
+	return upsertSettingValue(ctx, r.db, "", domain.SettingAuditLog, "audit log settings", setting)
+}
@@ -240,6 +240,18 @@ func (r *AuthRepo) GetAuthById(ctx context.Context, kbID string, id uint) (*doma
 	return &auth, nil
 }
 
+func (r *AuthRepo) GetAuthGroup(ctx context.Context, kbID string, id uint) (*domain.AuthGroup, error) {
+	var group domain.AuthGroup
+	if err := r.db.WithContext(ctx).
+		Model(&domain.AuthGroup{}).
+		Where("kb_id = ?", kbID).
+		Where("id = ?", id).
+		First(&group).Error; err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
 func (r *AuthRepo) GetAuthConfig(ctx context.Context, kbID string, sourceType consts.SourceType) (*domain.AuthConfig, error) {
 	var authConfig domain.AuthConfig
 
@@ -313,6 +325,10 @@ func (r *AuthRepo) GetOrCreateAuth(ctx context.Context, auth *domain.Auth, sourc
 			return err
 		}
 
+		if !existing.Active {
+			return domain.ErrAuthInactive
+		}
+
 		updateMap := map[string]interface{}{
 			"last_login_time": time.Now(),
 			"user_info":       auth.UserInfo,
@@ -337,3 +353,29 @@ func (r *AuthRepo) GetOrCreateAuth(ctx context.Context, auth *domain.Auth, sourc
 
 	return auth, nil
 }
+
+// SyncAuthGroupMembership makes the auth a member of groupIDs among the managed groups of the kb
+// and removes it from the other managed groups
+func (r *AuthRepo) SyncAuthGroupMembership(ctx context.Context, kbID string, authID uint, groupIDs, managedGroupIDs []uint) error {
+	leave, _ := lo.Difference(managedGroupIDs, groupIDs)
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(groupIDs) > 0 {
+			if err := tx.Model(&domain.AuthGroup{}).
+				Where("kb_id = ?", kbID).
+				Where("id IN ?", groupIDs).
+				Where("NOT (? = ANY(COALESCE(auth_ids, '{}')))", authID).
+				Update("auth_ids", gorm.Expr("array_append(COALESCE(auth_ids, '{}'), ?)", authID)).Error; err != nil {
+				return err
+			}
+		}
+		if len(leave) > 0 {
+			if err := tx.Model(&domain.AuthGroup{}).
+				Where("kb_id = ?", kbID).
+				Where("id IN ?", leave).
+				Update("auth_ids", gorm.Expr("array_remove(auth_ids, ?)", authID)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
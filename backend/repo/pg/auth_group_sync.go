@@ -0,0 +1,206 @@
+package pg
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+// GetGroupSyncAuthConfigs 开启了用户组同步的认证配置
+func (r *AuthRepo) GetGroupSyncAuthConfigs(ctx context.Context) ([]domain.AuthConfig, error) {
+	configs := make([]domain.AuthConfig, 0)
+	if err := r.db.WithContext(ctx).
+		Model(&domain.AuthConfig{}).
+		Where("source_type IN ?", consts.GroupSyncSourceTypes).
+		Where("auth_setting->>'group_sync' = ?", "true").
+		Find(&configs).Error; err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// GetSyncedAuthGroups 知识库中由 sourceType 同步而来的用户组
+func (r *AuthRepo) GetSyncedAuthGroups(ctx context.Context, kbID string, sourceType consts.SourceType) ([]domain.AuthGroup, error) {
+	groups := make([]domain.AuthGroup, 0)
+	if err := r.db.WithContext(ctx).
+		Model(&domain.AuthGroup{}).
+		Where("kb_id = ?", kbID).
+		Where("source_type = ?", sourceType).
+		Where("sync_id <> ''").
+		Order("id").
+		Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// GetAuthIDsByUnionID 知识库中 sourceType 读者的 union_id 到 auth id
+func (r *AuthRepo) GetAuthIDsByUnionID(ctx context.Context, kbID string, sourceType consts.SourceType) (map[string]uint, error) {
+	var auths []domain.Auth
+	if err := r.db.WithContext(ctx).
+		Model(&domain.Auth{}).
+		Select("id, union_id").
+		Where("kb_id = ?", kbID).
+		Where("source_type = ?", sourceType).
+		Find(&auths).Error; err != nil {
+		return nil, err
+	}
+	return lo.SliceToMap(auths, func(auth domain.Auth) (string, uint) {
+		return auth.UnionID, auth.ID
+	}), nil
+}
+
+// ApplyAuthGroupSync 在一个事务中应用同步差异，上级关系按 sync_parent_id 重建。
+// 返回因用户组被删除而需要更新可问答用户组的文档
+func (r *AuthRepo) ApplyAuthGroupSync(ctx context.Context, kbID string, sourceType consts.SourceType, diff *domain.AuthGroupSyncDiff) ([]string, error) {
+	nodeIDs := make([]string, 0)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		created := make(map[string]uint, len(diff.CreateGroups))
+		if len(diff.CreateGroups) > 0 {
+			var position float64
+			if err := tx.Model(&domain.AuthGroup{}).
+				Where("kb_id = ?", kbID).
+				Select("COALESCE(MAX(position), 0)").
+				Scan(&position).Error; err != nil {
+				return err
+			}
+			for _, g := range diff.CreateGroups {
+				position += 1000
+				group := &domain.AuthGroup{
+					Name:         g.Name,
+					KbID:         kbID,
+					Position:     position,
+					AuthIDs:      pq.Int64Array{},
+					SyncId:       g.SyncID,
+					SyncParentId: g.SyncParentID,
+					SourceType:   sourceType,
+				}
+				if err := tx.Create(group).Error; err != nil {
+					return err
+				}
+				created[g.SyncID] = group.ID
+			}
+		}
+
+		for _, g := range diff.UpdateGroups {
+			if err := tx.Model(&domain.AuthGroup{}).
+				Where("id = ?", g.ID).
+				Updates(map[string]any{
+					"name":           g.Name,
+					"sync_parent_id": g.SyncParentID,
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, m := range diff.AddMembers {
+			groupID := m.GroupID
+			if groupID == 0 {
+				groupID = created[m.GroupSyncID]
+			}
+			if err := tx.Model(&domain.AuthGroup{}).
+				Where("id = ?", groupID).
+				Where("NOT (? = ANY(COALESCE(auth_ids, '{}')))", m.AuthID).
+				Update("auth_ids", gorm.Expr("array_append(COALESCE(auth_ids, '{}'), ?)", m.AuthID)).Error; err != nil {
+				return err
+			}
+		}
+		for _, m := range diff.RemoveMembers {
+			if err := tx.Model(&domain.AuthGroup{}).
+				Where("id = ?", m.GroupID).
+				Update("auth_ids", gorm.Expr("array_remove(auth_ids, ?)", m.AuthID)).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(diff.DeleteGroups) > 0 {
+			ids := lo.Map(diff.DeleteGroups, func(g domain.AuthGroupSyncGroup, _ int) uint {
+				return g.ID
+			})
+			deleted, err := deleteAuthGroups(tx, ids)
+			if err != nil {
+				return err
+			}
+			nodeIDs = deleted
+		}
+
+		// 同步的用户组树由目录决定
+		if err := tx.Exec(`UPDATE auth_groups g SET parent_id = p.id
+			FROM auth_groups p
+			WHERE g.kb_id = ? AND g.source_type = ? AND g.sync_id <> ''
+				AND p.kb_id = g.kb_id AND p.source_type = g.source_type AND p.sync_id = g.sync_parent_id
+				AND g.parent_id IS DISTINCT FROM p.id`, kbID, sourceType).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.AuthGroup{}).
+			Where("kb_id = ?", kbID).
+			Where("source_type = ?", sourceType).
+			Where("sync_id <> ''").
+			Where("sync_parent_id = ''").
+			Where("parent_id IS NOT NULL").
+			Update("parent_id", nil).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nodeIDs, nil
+}
+
+// deleteAuthGroups 删除用户组及其文档权限，下级用户组成为顶级用户组。
+// 返回可问答权限涉及这些用户组的文档
+func deleteAuthGroups(tx *gorm.DB, ids []uint) ([]string, error) {
+	nodeIDs := make([]string, 0)
+	if err := tx.Model(&domain.NodeAuthGroup{}).
+		Where("auth_group_id IN ?", ids).
+		Where("perm = ?", consts.NodePermNameAnswerable).
+		Distinct().
+		Pluck("node_id", &nodeIDs).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("auth_group_id IN ?", ids).Delete(&domain.NodeAuthGroup{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Model(&domain.AuthGroup{}).
+		Where("parent_id IN ?", ids).
+		Update("parent_id", nil).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("id IN ?", ids).Delete(&domain.AuthGroup{}).Error; err != nil {
+		return nil, err
+	}
+	return nodeIDs, nil
+}
+
+func (r *AuthRepo) CreateAuthGroupSyncRun(ctx context.Context, run *domain.AuthGroupSyncRun) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+func (r *AuthRepo) GetAuthGroupSyncRuns(ctx context.Context, kbID string, sourceType consts.SourceType, pager domain.Pager) ([]domain.AuthGroupSyncRun, uint64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.AuthGroupSyncRun{}).Where("kb_id = ?", kbID)
+	if sourceType != "" {
+		query = query.Where("source_type = ?", sourceType)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	runs := make([]domain.AuthGroupSyncRun, 0)
+	if err := query.
+		Order("started_at DESC").
+		Offset(pager.Offset()).
+		Limit(pager.Limit()).
+		Find(&runs).Error; err != nil {
+		return nil, 0, err
+	}
+	return runs, uint64(total), nil
+}
+
+func (r *AuthRepo) DeleteAuthGroupSyncRunsBefore(ctx context.Context, before time.Time) error {
+	return r.db.WithContext(ctx).Where("started_at < ?", before).Delete(&domain.AuthGroupSyncRun{}).Error
+}
@@ -0,0 +1,209 @@
+package pg
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+// GetSCIMAuthConfigByTokenHash 按 token 的 sha256 查找 SCIM 配置
+func (r *AuthRepo) GetSCIMAuthConfigByTokenHash(ctx context.Context, tokenHash string) (*domain.AuthConfig, error) {
+	var authConfig domain.AuthConfig
+	if err := r.db.WithContext(ctx).
+		Model(&domain.AuthConfig{}).
+		Where("source_type = ?", consts.SourceTypeSCIM).
+		Where("auth_setting->'scim'->>'token_hash' = ?", tokenHash).
+		First(&authConfig).Error; err != nil {
+		return nil, err
+	}
+	return &authConfig, nil
+}
+
+func (r *AuthRepo) DeleteAuthConfig(ctx context.Context, kbID string, sourceType consts.SourceType) error {
+	return r.db.WithContext(ctx).
+		Where("kb_id = ?", kbID).
+		Where("source_type = ?", sourceType).
+		Delete(&domain.AuthConfig{}).Error
+}
+
+// GetAuthsByColumn 分页查询读者，column 为空时不过滤
+func (r *AuthRepo) GetAuthsByColumn(ctx context.Context, kbID string, sourceType consts.SourceType, column string, value any, offset, limit int) ([]domain.Auth, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&domain.Auth{}).
+		Where("kb_id = ?", kbID).
+		Where("source_type = ?", sourceType)
+	if column != "" {
+		query = query.Where(clause.Eq{Column: clause.Column{Name: column}, Value: value})
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	auths := make([]domain.Auth, 0)
+	if limit == 0 {
+		return auths, total, nil
+	}
+	if err := query.Order("id").Offset(offset).Limit(limit).Find(&auths).Error; err != nil {
+		return nil, 0, err
+	}
+	return auths, total, nil
+}
+
+func (r *AuthRepo) GetAuthsByIDs(ctx context.Context, kbID string, sourceType consts.SourceType, ids []uint) ([]domain.Auth, error) {
+	auths := make([]domain.Auth, 0, len(ids))
+	if len(ids) == 0 {
+		return auths, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Model(&domain.Auth{}).
+		Where("kb_id = ?", kbID).
+		Where("source_type = ?", sourceType).
+		Where("id IN ?", ids).
+		Find(&auths).Error; err != nil {
+		return nil, err
+	}
+	return auths, nil
+}
+
+// CreateProvisionedAuth 预先开通读者，与登录创建的读者一样受 maxAuth 限制
+func (r *AuthRepo) CreateProvisionedAuth(ctx context.Context, auth *domain.Auth, maxAuth int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&domain.Auth{}).
+			Where("kb_id = ?", auth.KBID).
+			Where("source_type = ?", auth.SourceType).
+			Where("union_id = ?", auth.UnionID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return domain.ErrAuthExists
+		}
+		if err := tx.Model(&domain.Auth{}).
+			Where("kb_id = ?", auth.KBID).
+			Where("source_type NOT IN (?)", consts.BotSourceTypes).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if int(count) >= maxAuth {
+			return domain.ErrMaxAuthLimitReached
+		}
+		active := auth.Active
+		if err := tx.Create(auth).Error; err != nil {
+			return err
+		}
+		// active 有默认值，创建时 false 会被忽略
+		if !active {
+			auth.Active = false
+			return tx.Model(&domain.Auth{}).Where("id = ?", auth.ID).Update("active", false).Error
+		}
+		return nil
+	})
+}
+
+// UpdateProvisionedAuth 更新读者的标识、资料及启用状态
+func (r *AuthRepo) UpdateProvisionedAuth(ctx context.Context, auth *domain.Auth) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&domain.Auth{}).
+			Where("kb_id = ?", auth.KBID).
+			Where("source_type = ?", auth.SourceType).
+			Where("union_id = ?", auth.UnionID).
+			Where("id <> ?", auth.ID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return domain.ErrAuthExists
+		}
+		return tx.Model(&domain.Auth{}).
+			Where("id = ?", auth.ID).
+			Updates(map[string]any{
+				"union_id":    auth.UnionID,
+				"external_id": auth.ExternalID,
+				"user_info":   auth.UserInfo,
+				"active":      auth.Active,
+			}).Error
+	})
+}
+
+// DeleteAuthWithGroups 删除读者并将其移出知识库中的所有用户组
+func (r *AuthRepo) DeleteAuthWithGroups(ctx context.Context, kbID string, authID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.AuthGroup{}).
+			Where("kb_id = ?", kbID).
+			Where("? = ANY(auth_ids)", authID).
+			Update("auth_ids", gorm.Expr("array_remove(auth_ids, ?)", authID)).Error; err != nil {
+			return err
+		}
+		return tx.Where("kb_id = ? AND id = ?", kbID, authID).Delete(&domain.Auth{}).Error
+	})
+}
+
+func (r *AuthRepo) GetAuthGroupsBySourceType(ctx context.Context, kbID string, sourceType consts.SourceType) ([]domain.AuthGroup, error) {
+	groups := make([]domain.AuthGroup, 0)
+	if err := r.db.WithContext(ctx).
+		Model(&domain.AuthGroup{}).
+		Where("kb_id = ?", kbID).
+		Where("source_type = ?", sourceType).
+		Order("id").
+		Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// CreateAuthGroup 新用户组排在最后
+func (r *AuthRepo) CreateAuthGroup(ctx context.Context, group *domain.AuthGroup) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var position float64
+		if err := tx.Model(&domain.AuthGroup{}).
+			Where("kb_id = ?", group.KbID).
+			Select("COALESCE(MAX(position), 0)").
+			Scan(&position).Error; err != nil {
+			return err
+		}
+		group.Position = position + 1000
+		return tx.Create(group).Error
+	})
+}
+
+func (r *AuthRepo) UpdateAuthGroup(ctx context.Context, group *domain.AuthGroup) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.AuthGroup{}).
+		Where("kb_id = ?", group.KbID).
+		Where("id = ?", group.ID).
+		Select("name", "parent_id", "auth_ids", "sync_id", "updated_at").
+		Updates(group).Error
+}
+
+// DeleteAuthGroup 删除用户组，返回可问答权限涉及该用户组的文档
+func (r *AuthRepo) DeleteAuthGroup(ctx context.Context, kbID string, id uint) ([]string, error) {
+	var nodeIDs []string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&domain.AuthGroup{}).
+			Where("kb_id = ?", kbID).
+			Where("id = ?", id).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		deleted, err := deleteAuthGroups(tx, []uint{id})
+		if err != nil {
+			return err
+		}
+		nodeIDs = deleted
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nodeIDs, nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/cloudwego/eino/schema"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/chaitin/panda-wiki/domain"
 	"github.com/chaitin/panda-wiki/log"
@@ -37,6 +38,27 @@ func (r *ConversationRepository) CreateConversation(ctx context.Context, convers
 	return r.db.WithContext(ctx).Create(conversation).Error
 }
 
+// CreateConversationIfNotExists keeps the existing conversation, channels like mail reuse one id for a whole thread
+func (r *ConversationRepository) CreateConversationIfNotExists(ctx context.Context, conversation *domain.Conversation) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(conversation).Error
+}
+
+// GetConversationKBID returns the kb of the conversation, empty when it does not exist yet
+func (r *ConversationRepository) GetConversationKBID(ctx context.Context, conversationID string) (string, error) {
+	var kbIDs []string
+	if err := r.db.WithContext(ctx).
+		Model(&domain.Conversation{}).
+		Where("id = ?", conversationID).
+		Limit(1).
+		Pluck("kb_id", &kbIDs).Error; err != nil {
+		return "", err
+	}
+	if len(kbIDs) == 0 {
+		return "", nil
+	}
+	return kbIDs[0], nil
+}
+
 func (r *ConversationRepository) GetConversationList(ctx context.Context, request *domain.ConversationListReq) ([]*domain.ConversationListItem, uint64, error) {
 	conversations := []*domain.ConversationListItem{}
 	query := r.db.WithContext(ctx).
@@ -52,6 +74,9 @@ func (r *ConversationRepository) GetConversationList(ctx context.Context, reques
 	if request.RemoteIP != nil && *request.RemoteIP != "" {
 		query = query.Where("conversations.remote_ip like ?", "%"+*request.RemoteIP+"%")
 	}
+	if request.EscalationStatus != nil && *request.EscalationStatus != "" {
+		query = query.Where("conversations.escalation_status = ?", *request.EscalationStatus)
+	}
 	var count int64
 	if err := query.Count(&count).Error; err != nil {
 		return nil, 0, err
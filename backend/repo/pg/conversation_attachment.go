@@ -0,0 +1,26 @@
+package pg
+
+import (
+	"context"
+
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+func (r *ConversationRepository) CreateConversationAttachments(ctx context.Context, attachments []*domain.ConversationAttachment) error {
+	if len(attachments) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&attachments).Error
+}
+
+// GetConversationAttachments returns the attachments of a conversation, oldest first
+func (r *ConversationRepository) GetConversationAttachments(ctx context.Context, conversationID string) ([]*domain.ConversationAttachment, error) {
+	var attachments []*domain.ConversationAttachment
+	if err := r.db.WithContext(ctx).
+		Where("conversation_id = ?", conversationID).
+		Order("created_at ASC").
+		Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
@@ -0,0 +1,58 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+// GetConversationIDByKeys returns the conversation of the most recently used key that is not older than since,
+// an empty id means none of the keys is known
+func (r *ConversationRepository) GetConversationIDByKeys(ctx context.Context, appID string, keys []string, since time.Time) (string, error) {
+	if len(keys) == 0 {
+		return "", nil
+	}
+	var key domain.ConversationKey
+	err := r.db.WithContext(ctx).
+		Model(&domain.ConversationKey{}).
+		Where("app_id = ? AND key IN ? AND updated_at >= ?", appID, keys, since).
+		Order("updated_at DESC").
+		First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return key.ConversationID, nil
+}
+
+// UpsertConversationKeys points the keys to the conversation and refreshes their window
+func (r *ConversationRepository) UpsertConversationKeys(ctx context.Context, appID string, keys []string, conversationID string, now time.Time) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	rows := make([]domain.ConversationKey, 0, len(keys))
+	for _, key := range keys {
+		rows = append(rows, domain.ConversationKey{
+			AppID:          appID,
+			Key:            key,
+			ConversationID: conversationID,
+			UpdatedAt:      now,
+		})
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "app_id"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"conversation_id", "updated_at"}),
+	}).Create(&rows).Error
+}
+
+// DeleteConversationKeysBefore drops keys that can no longer be matched by any window
+func (r *ConversationRepository) DeleteConversationKeysBefore(ctx context.Context, before time.Time) error {
+	return r.db.WithContext(ctx).Where("updated_at < ?", before).Delete(&domain.ConversationKey{}).Error
+}
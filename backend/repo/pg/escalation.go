@@ -0,0 +1,90 @@
+package pg
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+func (r *ConversationRepository) GetEscalationSettings(ctx context.Context, kbID string) (*domain.EscalationSettings, error) {
+	settings := &domain.EscalationSettings{
+		Keywords: make([]string, 0),
+		Webhooks: make([]domain.NotifyWebhook, 0),
+		Emails:   make([]string, 0),
+	}
+	if err := getSettingValue(ctx, r.db, kbID, domain.SettingEscalation, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (r *ConversationRepository) UpsertEscalationSettings(ctx context.Context, kbID string, settings *domain.EscalationSettings) error {
+	return upsertSettingValue(ctx, r.db, kbID, domain.SettingEscalation, "escalation settings", settings)
+}
+
+func (r *ConversationRepository) GetConversation(ctx context.Context, kbID, conversationID string) (*domain.Conversation, error) {
+	var conversation domain.Conversation
+	if err := r.db.WithContext(ctx).
+		Model(&domain.Conversation{}).
+		Where("kb_id = ?", kbID).
+		Where("id = ?", conversationID).
+		First(&conversation).Error; err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+// EscalateConversation opens an escalation, it reports false when the conversation is already waiting for an agent
+func (r *ConversationRepository) EscalateConversation(ctx context.Context, conversationID string, reason consts.EscalationReason, escalatedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.Conversation{}).
+		Where("id = ?", conversationID).
+		Where("escalation_status <> ?", consts.EscalationStatusOpen).
+		Updates(map[string]any{
+			"escalation_status": consts.EscalationStatusOpen,
+			"escalation_reason": reason,
+			"escalated_at":      escalatedAt,
+			"resolved_at":       nil,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *ConversationRepository) ResolveConversation(ctx context.Context, kbID, conversationID string, resolvedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.Conversation{}).
+		Where("kb_id = ?", kbID).
+		Where("id = ?", conversationID).
+		Where("escalation_status = ?", consts.EscalationStatusOpen).
+		Updates(map[string]any{
+			"escalation_status": consts.EscalationStatusResolved,
+			"resolved_at":       resolvedAt,
+		}).Error
+}
+
+// CountRecentDislikes counts disliked answers in the conversation and in other conversations
+// of the same user on the same app since the given time
+func (r *ConversationRepository) CountRecentDislikes(ctx context.Context, conversation *domain.Conversation, since time.Time) (int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&domain.ConversationMessage{}).
+		Joins("JOIN conversations c ON c.id = conversation_messages.conversation_id").
+		Where("conversation_messages.role = ?", schema.Assistant).
+		Where("conversation_messages.info->>'score' = ?", "-1").
+		Where("conversation_messages.created_at > ?", since)
+	if userID := conversation.Info.UserInfo.UserID; userID != "" {
+		query = query.Where("(c.id = ? OR (c.app_id = ? AND c.info->'user_info'->>'user_id' = ?))", conversation.ID, conversation.AppID, userID)
+	} else {
+		query = query.Where("c.id = ?", conversation.ID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
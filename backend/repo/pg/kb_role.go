@@ -0,0 +1,63 @@
+package pg
+
+import (
+	"context"
+
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/store/pg"
+)
+
+type KBRoleRepository struct {
+	db     *pg.DB
+	logger *log.Logger
+}
+
+func NewKBRoleRepository(db *pg.DB, logger *log.Logger) *KBRoleRepository {
+	return &KBRoleRepository{
+		db:     db,
+		logger: logger.WithModule("repo.pg.kb_role"),
+	}
+}
+
+func (r *KBRoleRepository) ListRoles(ctx context.Context) ([]domain.KBRole, error) {
+	roles := make([]domain.KBRole, 0)
+	if err := r.db.WithContext(ctx).Order("created_at ASC").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *KBRoleRepository) GetRole(ctx context.Context, id string) (*domain.KBRole, error) {
+	var role domain.KBRole
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&role).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *KBRoleRepository) CreateRole(ctx context.Context, role *domain.KBRole) error {
+	return r.db.WithContext(ctx).Create(role).Error
+}
+
+func (r *KBRoleRepository) UpdateRole(ctx context.Context, role *domain.KBRole) error {
+	return r.db.WithContext(ctx).Model(&domain.KBRole{}).Where("id = ?", role.ID).Updates(map[string]any{
+		"name":         role.Name,
+		"description":  role.Description,
+		"capabilities": role.Capabilities,
+		"updated_at":   role.UpdatedAt,
+	}).Error
+}
+
+func (r *KBRoleRepository) DeleteRole(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&domain.KBRole{}).Error
+}
+
+// CountRoleUsers 分配了该角色的知识库成员数
+func (r *KBRoleRepository) CountRoleUsers(ctx context.Context, id string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.KBUsers{}).Where("role_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
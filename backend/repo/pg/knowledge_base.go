@@ -10,6 +10,7 @@ import (
 	"maps"
 	"net"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
@@ -656,8 +657,9 @@ func (r *KnowledgeBaseRepository) GetKBUserlist(ctx context.Context, kbID string
 	var users []v1.KBUserListItemResp
 	err := r.db.WithContext(ctx).
 		Model(&domain.User{}).
-		Select("users.id, users.account, users.role, kbu.perm, kbu.created_at").
+		Select("users.id, users.account, users.role, kbu.perm, kbu.role_id, COALESCE(r.name, '') AS role_name, kbu.created_at").
 		Joins("INNER JOIN kb_users kbu ON users.id = kbu.user_id").
+		Joins("LEFT JOIN kb_roles r ON r.id = kbu.role_id").
 		Where("kbu.kb_id = ?", kbID).
 		Where("users.role = ?", consts.UserRoleUser).
 		Order("kbu.created_at DESC").
@@ -689,11 +691,15 @@ func (r *KnowledgeBaseRepository) CreateKBUser(ctx context.Context, kbUser *doma
 	return r.db.WithContext(ctx).Create(kbUser).Error
 }
 
-func (r *KnowledgeBaseRepository) UpdateKBUserPerm(ctx context.Context, kbId, userId string, perm consts.UserKBPermission) error {
+// UpdateKBUserPerm roleID 不为空时按自定义角色鉴权
+func (r *KnowledgeBaseRepository) UpdateKBUserPerm(ctx context.Context, kbId, userId string, perm consts.UserKBPermission, roleID string) error {
 	return r.db.WithContext(ctx).
 		Model(&domain.KBUsers{}).
 		Where("kb_id = ? AND user_id = ?", kbId, userId).
-		Update("perm", perm).Error
+		Updates(map[string]any{
+			"perm":    perm,
+			"role_id": roleID,
+		}).Error
 }
 
 func (r *KnowledgeBaseRepository) DeleteKBUser(ctx context.Context, kbId, userId string) error {
@@ -748,3 +754,41 @@ func (r *KnowledgeBaseRepository) GetKBPermByUserId(ctx context.Context, kbId st
 		return kbUser.Perm, nil
 	}
 }
+
+// GetKBCapabilities 当前用户或 API Token 在知识库内的能力
+func (r *KnowledgeBaseRepository) GetKBCapabilities(ctx context.Context, kbId string) ([]consts.KBCapability, error) {
+	authInfo := domain.GetAuthInfoFromCtx(ctx)
+	if authInfo == nil {
+		return nil, fmt.Errorf("authInfo not found in context")
+	}
+	if authInfo.IsToken {
+		if authInfo.KBId != kbId {
+			return nil, errors.New("token kb permission denied")
+		}
+		return authInfo.Permission.Capabilities(), nil
+	}
+	return kbUserCapabilities(r.db.WithContext(ctx), kbId, authInfo.UserId)
+}
+
+// GetKBUserIDsByCapability 拥有指定能力的管理员和知识库成员
+func (r *KnowledgeBaseRepository) GetKBUserIDsByCapability(ctx context.Context, kbID string, capability consts.KBCapability) ([]string, error) {
+	perms := make([]consts.UserKBPermission, 0)
+	for _, perm := range consts.UserKBPermissions {
+		if slices.Contains(perm.Capabilities(), capability) {
+			perms = append(perms, perm)
+		}
+	}
+	userIDs := make([]string, 0)
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT id FROM users WHERE role = ?
+		UNION
+		SELECT kbu.user_id FROM kb_users kbu
+		LEFT JOIN kb_roles r ON r.id = kbu.role_id
+		WHERE kbu.kb_id = ? AND (
+			(kbu.role_id = '' AND kbu.perm IN ?) OR ? = ANY(r.capabilities)
+		)`, consts.UserRoleAdmin, kbID, perms, capability).
+		Scan(&userIDs).Error; err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
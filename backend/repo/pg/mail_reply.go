@@ -0,0 +1,101 @@
+package pg
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/chaitin/panda-wiki/api/app/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/store/pg"
+)
+
+type MailReplyRepository struct {
+	db     *pg.DB
+	logger *log.Logger
+}
+
+func NewMailReplyRepository(db *pg.DB, logger *log.Logger) *MailReplyRepository {
+	return &MailReplyRepository{db: db, logger: logger.WithModule("repo.pg.mail_reply")}
+}
+
+func (r *MailReplyRepository) Create(ctx context.Context, reply *domain.MailReply) error {
+	return r.db.WithContext(ctx).Create(reply).Error
+}
+
+func (r *MailReplyRepository) GetList(ctx context.Context, req *v1.MailReplyListReq) (int64, []*domain.MailReply, error) {
+	query := r.db.WithContext(ctx).
+		Model(&domain.MailReply{}).
+		Where("kb_id = ?", req.KbId)
+	if req.AppID != "" {
+		query = query.Where("app_id = ?", req.AppID)
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, nil, err
+	}
+	replies := make([]*domain.MailReply, 0)
+	if err := query.
+		Order("created_at DESC").
+		Offset(req.Offset()).
+		Limit(req.Limit()).
+		Find(&replies).Error; err != nil {
+		return 0, nil, err
+	}
+	return total, replies, nil
+}
+
+func (r *MailReplyRepository) GetByID(ctx context.Context, kbID, id string) (*domain.MailReply, error) {
+	var reply domain.MailReply
+	if err := r.db.WithContext(ctx).
+		Model(&domain.MailReply{}).
+		Where("kb_id = ?", kbID).
+		Where("id = ?", id).
+		First(&reply).Error; err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// Update saves the reply only while it is still in one of the from statuses,
+// ErrMailReplyStatusInvalid is returned when another request changed it first
+func (r *MailReplyRepository) Update(ctx context.Context, reply *domain.MailReply, from ...consts.MailReplyStatus) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.MailReply{}).
+		Where("id = ?", reply.ID).
+		Where("status IN ?", from).
+		Select("subject", "answer", "status", "error", "reviewer_id", "updated_at", "sent_at").
+		Updates(reply)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrMailReplyStatusInvalid
+	}
+	return nil
+}
+
+// Claim marks a pending or failed reply as sending, only one request can claim it
+func (r *MailReplyRepository) Claim(ctx context.Context, kbID, id, reviewerID string) error {
+	result := r.db.WithContext(ctx).
+		Model(&domain.MailReply{}).
+		Where("kb_id = ? AND id = ?", kbID, id).
+		Where("status IN ?", []consts.MailReplyStatus{consts.MailReplyStatusPending, consts.MailReplyStatusFailed}).
+		Updates(map[string]any{
+			"status":      consts.MailReplyStatusSending,
+			"reviewer_id": reviewerID,
+			"updated_at":  time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrMailReplyStatusInvalid
+	}
+	return nil
+}
@@ -86,6 +86,17 @@ func (r *ModelRepository) GetChatModel(ctx context.Context) (*domain.Model, erro
 	return &model, nil
 }
 
+func (r *ModelRepository) GetModel(ctx context.Context, id string) (*domain.Model, error) {
+	var model domain.Model
+	if err := r.db.WithContext(ctx).
+		Model(&domain.Model{}).
+		Where("id = ?", id).
+		First(&model).Error; err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
 func (r *ModelRepository) GetModelByType(ctx context.Context, modelType domain.ModelType) (*domain.Model, error) {
 	var model domain.Model
 	if err := r.db.WithContext(ctx).
@@ -2,8 +2,11 @@ package pg
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 
@@ -81,6 +84,10 @@ func (r *NodeRepository) Create(ctx context.Context, req *domain.CreateNodeReq,
 		}
 
 		now := time.Now()
+		ownerID := req.OwnerID
+		if ownerID == "" {
+			ownerID = userId
+		}
 		meta := domain.NodeMeta{Emoji: req.Emoji}
 		if req.Summary != nil {
 			meta.Summary = *req.Summary
@@ -88,6 +95,17 @@ func (r *NodeRepository) Create(ctx context.Context, req *domain.CreateNodeReq,
 		if req.ContentType != nil {
 			meta.ContentType = *req.ContentType
 		}
+		meta.Tags = req.Tags
+		meta.Fields = req.Fields
+
+		permissions := domain.NodePermissions{
+			Answerable: consts.NodeAccessPermOpen,
+			Visitable:  consts.NodeAccessPermOpen,
+			Visible:    consts.NodeAccessPermOpen,
+		}
+		if req.Permissions != nil {
+			permissions = *req.Permissions
+		}
 
 		node := &domain.Node{
 			ID:        nodeIDStr,
@@ -104,15 +122,13 @@ func (r *NodeRepository) Create(ctx context.Context, req *domain.CreateNodeReq,
 			CreatedAt: now,
 			UpdatedAt: now,
 			EditTime:  now,
+			OwnerID:   ownerID,
 			RagInfo: domain.RagInfo{
 				Status:  consts.NodeRagStatusBasicPending,
 				Message: "",
 			},
-			Permissions: domain.NodePermissions{
-				Answerable: consts.NodeAccessPermOpen,
-				Visitable:  consts.NodeAccessPermOpen,
-				Visible:    consts.NodeAccessPermOpen,
-			},
+			Permissions:    permissions,
+			ReviewInterval: req.ReviewInterval,
 		}
 
 		return tx.Create(node).Error
@@ -124,18 +140,23 @@ func (r *NodeRepository) Create(ctx context.Context, req *domain.CreateNodeReq,
 	return nodeIDStr, nil
 }
 
-func (r *NodeRepository) GetList(ctx context.Context, req *domain.GetNodeListReq) ([]*domain.NodeListItemResp, error) {
+func (r *NodeRepository) GetList(ctx context.Context, req *domain.GetNodeListReq, filter *domain.NodeMetaFilter) ([]*domain.NodeListItemResp, error) {
 	var nodes []*domain.NodeListItemResp
 	query := r.db.WithContext(ctx).
 		Model(&domain.Node{}).
 		Joins("LEFT JOIN users cu ON nodes.creator_id = cu.id").
 		Joins("LEFT JOIN users eu ON nodes.editor_id = eu.id").
+		Joins("LEFT JOIN users ou ON nodes.owner_id = ou.id").
 		Where("nodes.kb_id = ?", req.KBID).
-		Select("cu.account AS creator, eu.account AS editor, nodes.editor_id, nodes.rag_info, nodes.creator_id, nodes.id, nodes.permissions, nodes.type, nodes.status, nodes.name, nodes.parent_id, nodes.position, nodes.created_at, nodes.edit_time as updated_at, nodes.meta->>'summary' as summary, nodes.meta->>'emoji' as emoji, nodes.meta->>'content_type' as content_type")
+		Select("cu.account AS creator, eu.account AS editor, ou.account AS owner, nodes.owner_id, nodes.review_interval, nodes.reviewed_at, nodes.editor_id, nodes.rag_info, nodes.creator_id, nodes.id, nodes.permissions, nodes.type, nodes.status, nodes.name, nodes.parent_id, nodes.position, nodes.created_at, nodes.edit_time as updated_at, nodes.meta->>'summary' as summary, nodes.meta->>'emoji' as emoji, nodes.meta->>'content_type' as content_type, nodes.meta->'tags' as tags, nodes.meta->'fields' as fields")
 	if req.Search != "" {
 		searchPattern := "%" + req.Search + "%"
 		query = query.Where("name LIKE ? OR content LIKE ?", searchPattern, searchPattern)
 	}
+	query, err := applyNodeMetaFilter(query, "nodes", filter)
+	if err != nil {
+		return nil, err
+	}
 	if err := query.Find(&nodes).Error; err != nil {
 		return nil, err
 	}
@@ -196,7 +217,7 @@ func (r *NodeRepository) UpdateNodeContent(ctx context.Context, req *domain.Upda
 		}
 
 		// Handle multiple meta field updates
-		if req.Emoji != nil || req.Summary != nil || req.ContentType != nil {
+		if req.Emoji != nil || req.Summary != nil || req.ContentType != nil || req.Tags != nil || req.Fields != nil {
 			metaExpr := "meta"
 			var args []any
 			metaUpdated := false
@@ -227,12 +248,40 @@ func (r *NodeRepository) UpdateNodeContent(ctx context.Context, req *domain.Upda
 				}
 			}
 
+			// Replace tags and fields as a whole
+			if req.Tags != nil && !slices.Equal(*req.Tags, currentNode.Meta.Tags) {
+				tags, err := json.Marshal(*req.Tags)
+				if err != nil {
+					return err
+				}
+				metaExpr = "jsonb_set(" + metaExpr + ", '{tags}', ?::jsonb)"
+				args = append(args, string(tags))
+				metaUpdated = true
+			}
+			if req.Fields != nil && !maps.Equal(*req.Fields, currentNode.Meta.Fields) {
+				fields, err := json.Marshal(*req.Fields)
+				if err != nil {
+					return err
+				}
+				metaExpr = "jsonb_set(" + metaExpr + ", '{fields}', ?::jsonb)"
+				args = append(args, string(fields))
+				metaUpdated = true
+			}
+
 			if metaUpdated {
 				updateMap["meta"] = gorm.Expr(metaExpr, args...)
 				updateStatus = true
 			}
 		}
 
+		// ownership and review interval are not part of the published content
+		if req.OwnerID != nil && *req.OwnerID != currentNode.OwnerID {
+			updateMap["owner_id"] = *req.OwnerID
+		}
+		if req.ReviewInterval != nil && *req.ReviewInterval != currentNode.ReviewInterval {
+			updateMap["review_interval"] = *req.ReviewInterval
+		}
+
 		// If any field is updated, set status to draft
 		if updateStatus {
 			updateMap["status"] = domain.NodeStatusDraft
@@ -642,8 +691,8 @@ func (r *NodeRepository) GetRecommendNodeListByParentIDs(ctx context.Context, kb
 	return nodesMap, nil
 }
 
-// GetNodeReleaseListByKBID get node list by kb id
-func (r *NodeRepository) GetNodeReleaseListByKBID(ctx context.Context, kbID string) ([]*domain.ShareNodeListItemResp, error) {
+// GetNodeReleaseListByKBID get node list by kb id, filter is optional
+func (r *NodeRepository) GetNodeReleaseListByKBID(ctx context.Context, kbID string, filter *domain.NodeMetaFilter) ([]*domain.ShareNodeListItemResp, error) {
 	// get kb release
 	var kbRelease *domain.KBRelease
 	if err := r.db.WithContext(ctx).
@@ -658,21 +707,34 @@ func (r *NodeRepository) GetNodeReleaseListByKBID(ctx context.Context, kbID stri
 	}
 
 	var nodes []*domain.ShareNodeListItemResp
-	if err := r.db.WithContext(ctx).
+	query := r.db.WithContext(ctx).
 		Model(&domain.KBReleaseNodeRelease{}).
 		Joins("LEFT JOIN node_releases ON node_releases.id = kb_release_node_releases.node_release_id").
 		Joins("LEFT JOIN nodes ON nodes.id = kb_release_node_releases.node_id").
 		Where("kb_release_node_releases.kb_id = ?", kbID).
 		Where("kb_release_node_releases.release_id = ?", kbRelease.ID).
 		Where("nodes.permissions->>'visible' != ?", consts.NodeAccessPermClosed).
-		Select("node_releases.node_id as id, node_releases.name, node_releases.type, node_releases.parent_id, node_releases.position, node_releases.meta->>'emoji' as emoji, node_releases.updated_at, nodes.permissions").
-		Find(&nodes).Error; err != nil {
+		Select("node_releases.node_id as id, node_releases.name, node_releases.type, node_releases.parent_id, node_releases.position, node_releases.meta->>'emoji' as emoji, node_releases.meta->'tags' as tags, node_releases.meta->'fields' as fields, node_releases.updated_at, nodes.permissions")
+	query, err := applyNodeMetaFilter(query, "node_releases", filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := query.Find(&nodes).Error; err != nil {
 		return nil, err
 	}
 	return nodes, nil
 }
 
 func (r *NodeRepository) GetNodeReleaseDetailByKBIDAndID(ctx context.Context, kbID, id string) (*v1.NodeDetailResp, error) {
+	return r.getNodeReleaseDetail(ctx, kbID, id, false)
+}
+
+// GetSharedNodeReleaseDetail 分享链接访问，不受文档访问权限限制
+func (r *NodeRepository) GetSharedNodeReleaseDetail(ctx context.Context, kbID, id string) (*v1.NodeDetailResp, error) {
+	return r.getNodeReleaseDetail(ctx, kbID, id, true)
+}
+
+func (r *NodeRepository) getNodeReleaseDetail(ctx context.Context, kbID, id string, includeClosed bool) (*v1.NodeDetailResp, error) {
 	// get kb release
 	var kbRelease *domain.KBRelease
 	if err := r.db.WithContext(ctx).
@@ -684,14 +746,17 @@ func (r *NodeRepository) GetNodeReleaseDetailByKBIDAndID(ctx context.Context, kb
 	}
 
 	var node *v1.NodeDetailResp
-	if err := r.db.WithContext(ctx).
+	query := r.db.WithContext(ctx).
 		Model(&domain.KBReleaseNodeRelease{}).
 		Joins("LEFT JOIN node_releases ON node_releases.id = kb_release_node_releases.node_release_id").
 		Joins("LEFT JOIN nodes ON nodes.id = kb_release_node_releases.node_id").
 		Where("kb_release_node_releases.release_id = ?", kbRelease.ID).
 		Where("node_releases.node_id = ?", id).
-		Where("node_releases.kb_id = ?", kbID).
-		Where("nodes.permissions->>'visitable' != ?", consts.NodeAccessPermClosed).
+		Where("node_releases.kb_id = ?", kbID)
+	if !includeClosed {
+		query = query.Where("nodes.permissions->>'visitable' != ?", consts.NodeAccessPermClosed)
+	}
+	if err := query.
 		Select("node_releases.*, nodes.permissions").
 		First(&node).Error; err != nil {
 		return nil, err
@@ -699,6 +764,62 @@ func (r *NodeRepository) GetNodeReleaseDetailByKBIDAndID(ctx context.Context, kb
 	return node, nil
 }
 
+// GetSubtreeNodeIDs 节点及其下所有子节点的 ID
+func (r *NodeRepository) GetSubtreeNodeIDs(ctx context.Context, kbID, rootID string) []string {
+	return r.collectAllChildNodeIDs(r.db.WithContext(ctx), kbID, []string{rootID})
+}
+
+// GetSharedNodeReleaseList 最新发布版本中的指定节点，不受文档访问权限限制
+func (r *NodeRepository) GetSharedNodeReleaseList(ctx context.Context, kbID string, ids []string) ([]*domain.ShareNodeListItemResp, error) {
+	kbRelease, err := r.latestKBRelease(ctx, kbID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var nodes []*domain.ShareNodeListItemResp
+	if err := r.db.WithContext(ctx).
+		Model(&domain.KBReleaseNodeRelease{}).
+		Joins("LEFT JOIN node_releases ON node_releases.id = kb_release_node_releases.node_release_id").
+		Joins("LEFT JOIN nodes ON nodes.id = kb_release_node_releases.node_id").
+		Where("kb_release_node_releases.kb_id = ?", kbID).
+		Where("kb_release_node_releases.release_id = ?", kbRelease.ID).
+		Where("node_releases.node_id IN ?", ids).
+		Select("node_releases.node_id as id, node_releases.name, node_releases.type, node_releases.parent_id, node_releases.position, node_releases.meta->>'emoji' as emoji, node_releases.meta->'tags' as tags, node_releases.meta->'fields' as fields, node_releases.updated_at, nodes.permissions").
+		Find(&nodes).Error; err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// GetDraftNodeList 节点当前的编辑内容，用于预览未发布的文档
+func (r *NodeRepository) GetDraftNodeList(ctx context.Context, kbID string, ids []string) ([]*domain.ShareNodeListItemResp, error) {
+	var nodes []*domain.ShareNodeListItemResp
+	if err := r.db.WithContext(ctx).
+		Model(&domain.Node{}).
+		Where("kb_id = ?", kbID).
+		Where("id IN ?", ids).
+		Select("id, name, type, parent_id, position, meta->>'emoji' as emoji, meta->'tags' as tags, meta->'fields' as fields, updated_at, permissions").
+		Find(&nodes).Error; err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+func (r *NodeRepository) latestKBRelease(ctx context.Context, kbID string) (*domain.KBRelease, error) {
+	var kbRelease domain.KBRelease
+	if err := r.db.WithContext(ctx).
+		Model(&domain.KBRelease{}).
+		Where("kb_id = ?", kbID).
+		Order("created_at DESC").
+		First(&kbRelease).Error; err != nil {
+		return nil, err
+	}
+	return &kbRelease, nil
+}
+
 func (r *NodeRepository) MoveNodeBetween(ctx context.Context, id, parentID, prevID, nextID, kbId string) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		var prevPos, maxPos float64 = 0, domain.MaxPosition
@@ -855,6 +976,70 @@ func (r *NodeRepository) CreateNodeReleases(ctx context.Context, kbID string, no
 	return releaseIDs, nil
 }
 
+// GetReleaseDigestNodes returns the documents among ids open to everyone with the ids of the folders they are in,
+// partially open and closed documents are never posted to group chats
+func (r *NodeRepository) GetReleaseDigestNodes(ctx context.Context, kbID string, ids []string) ([]*domain.ReleaseDigestNode, error) {
+	var nodes []*domain.Node
+	if err := r.db.WithContext(ctx).
+		Model(&domain.Node{}).
+		Where("kb_id = ?", kbID).
+		Where("id IN ?", ids).
+		Where("type = ?", domain.NodeTypeDocument).
+		Where("permissions->>'visitable' = ?", consts.NodeAccessPermOpen).
+		Select("id, name, meta, parent_id, permissions").
+		Order("position").
+		Find(&nodes).Error; err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+
+	var ancestors []struct {
+		NodeID      string         `gorm:"column:node_id"`
+		AncestorIDs pq.StringArray `gorm:"column:ancestor_ids;type:text[]"`
+	}
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT id AS node_id, parent_id, 1 AS depth
+			FROM nodes
+			WHERE kb_id = $1 AND id = ANY($2)
+
+			UNION ALL
+
+			SELECT a.node_id, n.parent_id, a.depth + 1
+			FROM nodes n
+			INNER JOIN ancestors a ON n.id = a.parent_id
+			WHERE a.depth < 20 AND n.kb_id = $1
+		)
+		SELECT node_id, array_agg(parent_id) AS ancestor_ids
+		FROM ancestors
+		WHERE parent_id != ''
+		GROUP BY node_id
+	`
+	if err := r.db.WithContext(ctx).
+		Raw(query, kbID, pq.Array(ids)).
+		Scan(&ancestors).Error; err != nil {
+		return nil, err
+	}
+	ancestorMap := make(map[string][]string, len(ancestors))
+	for _, a := range ancestors {
+		ancestorMap[a.NodeID] = a.AncestorIDs
+	}
+
+	result := make([]*domain.ReleaseDigestNode, 0, len(nodes))
+	for _, node := range nodes {
+		result = append(result, &domain.ReleaseDigestNode{
+			ID:          node.ID,
+			Name:        node.Name,
+			Summary:     node.Meta.Summary,
+			Visitable:   node.Permissions.Visitable,
+			AncestorIDs: ancestorMap[node.ID],
+		})
+	}
+	return result, nil
+}
+
 func (r *NodeRepository) GetOldNodeDocIDsByNodeID(ctx context.Context, nodeReleaseID, nodeID string) ([]string, error) {
 	var docIDs []string
 	if err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -0,0 +1,156 @@
+package pg
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/store/pg"
+)
+
+type NodeAccessRequestRepository struct {
+	db     *pg.DB
+	logger *log.Logger
+}
+
+func NewNodeAccessRequestRepository(db *pg.DB, logger *log.Logger) *NodeAccessRequestRepository {
+	return &NodeAccessRequestRepository{
+		db:     db,
+		logger: logger.WithModule("repo.pg.node_access_request"),
+	}
+}
+
+func (r *NodeAccessRequestRepository) CreateRequest(ctx context.Context, req *domain.NodeAccessRequest) error {
+	return r.db.WithContext(ctx).Create(req).Error
+}
+
+func (r *NodeAccessRequestRepository) GetRequest(ctx context.Context, kbID, id string) (*domain.NodeAccessRequest, error) {
+	var req domain.NodeAccessRequest
+	if err := r.db.WithContext(ctx).
+		Where("kb_id = ? AND id = ?", kbID, id).
+		First(&req).Error; err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func (r *NodeAccessRequestRepository) HasPendingRequest(ctx context.Context, nodeID string, authID uint, perm consts.NodePermName) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&domain.NodeAccessRequest{}).
+		Where("node_id = ? AND auth_id = ? AND perm = ?", nodeID, authID, perm).
+		Where("status = ?", consts.NodeAccessRequestStatusPending).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListRequests status 为空时返回全部状态，待处理的排在前面
+func (r *NodeAccessRequestRepository) ListRequests(ctx context.Context, kbID string, status consts.NodeAccessRequestStatus, pager domain.Pager) ([]domain.NodeAccessRequest, uint64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.NodeAccessRequest{}).Where("kb_id = ?", kbID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	requests := make([]domain.NodeAccessRequest, 0)
+	if err := query.
+		Order("CASE WHEN status = 'pending' THEN 0 ELSE 1 END, created_at DESC").
+		Offset(pager.Offset()).
+		Limit(pager.Limit()).
+		Find(&requests).Error; err != nil {
+		return nil, 0, err
+	}
+	return requests, uint64(total), nil
+}
+
+func (r *NodeAccessRequestRepository) ListRequestsByAuthID(ctx context.Context, kbID string, authID uint) ([]domain.NodeAccessRequest, error) {
+	requests := make([]domain.NodeAccessRequest, 0)
+	if err := r.db.WithContext(ctx).
+		Where("kb_id = ? AND auth_id = ?", kbID, authID).
+		Order("created_at DESC").
+		Limit(100).
+		Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// DecideRequest 记录处理结果，批准时同时加入用户组或写入文档授权。
+// 申请已被处理时返回 ErrAccessRequestStatusInvalid
+func (r *NodeAccessRequestRepository) DecideRequest(ctx context.Context, req *domain.NodeAccessRequest, grants []domain.NodeAuthGrant) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.NodeAccessRequest{}).
+			Where("id = ? AND status = ?", req.ID, consts.NodeAccessRequestStatusPending).
+			Updates(map[string]any{
+				"status":         req.Status,
+				"grant_type":     req.GrantType,
+				"auth_group_id":  req.AuthGroupID,
+				"reviewer_id":    req.ReviewerID,
+				"review_comment": req.ReviewComment,
+				"reviewed_at":    req.ReviewedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrAccessRequestStatusInvalid
+		}
+		if req.Status != consts.NodeAccessRequestStatusApproved {
+			return nil
+		}
+		switch req.GrantType {
+		case consts.NodeAccessGrantTypeAuthGroup:
+			return tx.Model(&domain.AuthGroup{}).
+				Where("kb_id = ? AND id = ?", req.KBID, req.AuthGroupID).
+				Where("NOT (? = ANY(COALESCE(auth_ids, '{}')))", req.AuthID).
+				Update("auth_ids", gorm.Expr("array_append(COALESCE(auth_ids, '{}'), ?)", req.AuthID)).Error
+		case consts.NodeAccessGrantTypeNode:
+			if len(grants) == 0 {
+				return nil
+			}
+			return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&grants).Error
+		}
+		return nil
+	})
+}
+
+func (r *NodeAccessRequestRepository) ListGrants(ctx context.Context, kbID, nodeID string) ([]domain.NodeAuthGrant, error) {
+	query := r.db.WithContext(ctx).Where("kb_id = ?", kbID)
+	if nodeID != "" {
+		query = query.Where("node_id = ?", nodeID)
+	}
+	grants := make([]domain.NodeAuthGrant, 0)
+	if err := query.Order("created_at DESC, id DESC").Find(&grants).Error; err != nil {
+		return nil, err
+	}
+	return grants, nil
+}
+
+func (r *NodeAccessRequestRepository) GetGrant(ctx context.Context, kbID string, id uint) (*domain.NodeAuthGrant, error) {
+	var grant domain.NodeAuthGrant
+	if err := r.db.WithContext(ctx).
+		Where("kb_id = ? AND id = ?", kbID, id).
+		First(&grant).Error; err != nil {
+		return nil, err
+	}
+	return &grant, nil
+}
+
+func (r *NodeAccessRequestRepository) DeleteGrant(ctx context.Context, kbID string, id uint) error {
+	result := r.db.WithContext(ctx).Where("kb_id = ? AND id = ?", kbID, id).Delete(&domain.NodeAuthGrant{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
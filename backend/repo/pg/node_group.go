@@ -46,3 +46,15 @@ func (r *NodeRepository) GetNodeAuthGroupIdsByNodeId(ctx context.Context, nodeId
 	}
 	return nil, nil
 }
+
+// GetGrantedNodeIDsByAuthId 单独授权给读者的文档
+func (r *NodeRepository) GetGrantedNodeIDsByAuthId(ctx context.Context, authId uint, perm consts.NodePermName) ([]string, error) {
+	nodeIDs := make([]string, 0)
+	if err := r.db.WithContext(ctx).
+		Model(&domain.NodeAuthGrant{}).
+		Where("auth_id = ? AND perm = ?", authId, perm).
+		Pluck("node_id", &nodeIDs).Error; err != nil {
+		return nil, err
+	}
+	return nodeIDs, nil
+}
@@ -0,0 +1,155 @@
+package pg
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	v1 "github.com/chaitin/panda-wiki/api/node/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/store/pg"
+)
+
+type NodeLinkRepository struct {
+	db     *pg.DB
+	logger *log.Logger
+}
+
+func NewNodeLinkRepository(db *pg.DB, logger *log.Logger) *NodeLinkRepository {
+	return &NodeLinkRepository{db: db, logger: logger.WithModule("repo.pg.node_link")}
+}
+
+// GetNodesForIndex returns id and content of documents, all documents of the kb when ids is empty
+func (r *NodeLinkRepository) GetNodesForIndex(ctx context.Context, kbID string, ids []string) ([]*domain.Node, error) {
+	var nodes []*domain.Node
+	query := r.db.WithContext(ctx).
+		Model(&domain.Node{}).
+		Select("id, kb_id, content").
+		Where("kb_id = ?", kbID).
+		Where("type = ?", domain.NodeTypeDocument)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	if err := query.Find(&nodes).Error; err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// ReplaceNodeLinks makes links the complete outbound link set of the source node
+func (r *NodeLinkRepository) ReplaceNodeLinks(ctx context.Context, kbID, sourceNodeID string, links []*domain.NodeLink) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("kb_id = ?", kbID).Where("source_node_id = ?", sourceNodeID)
+		if len(links) > 0 {
+			urls := make([]string, 0, len(links))
+			for _, link := range links {
+				urls = append(urls, link.URL)
+			}
+			query = query.Where("url NOT IN ?", urls)
+		}
+		if err := query.Delete(&domain.NodeLink{}).Error; err != nil {
+			return err
+		}
+		if len(links) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "source_node_id"}, {Name: "url"}},
+			DoUpdates: clause.AssignmentColumns([]string{"type", "target_node_id"}),
+		}).CreateInBatches(&links, 100).Error
+	})
+}
+
+// DeleteOrphanLinks removes links whose source node no longer exists
+func (r *NodeLinkRepository) DeleteOrphanLinks(ctx context.Context, kbID string) error {
+	return r.db.WithContext(ctx).
+		Where("kb_id = ?", kbID).
+		Where("NOT EXISTS (SELECT 1 FROM nodes WHERE nodes.id = node_links.source_node_id)").
+		Delete(&domain.NodeLink{}).Error
+}
+
+func (r *NodeLinkRepository) GetBrokenLinks(ctx context.Context, req *v1.BrokenLinkListReq) (int64, []v1.BrokenLinkItem, error) {
+	query := r.db.WithContext(ctx).
+		Model(&domain.NodeLink{}).
+		Joins("JOIN nodes s ON s.id = node_links.source_node_id").
+		Joins("LEFT JOIN nodes t ON node_links.type = ? AND t.id = node_links.target_node_id", consts.NodeLinkTypeInternal).
+		Joins("LEFT JOIN external_link_checks c ON node_links.type = ? AND c.url = node_links.url", consts.NodeLinkTypeExternal).
+		Where("node_links.kb_id = ?", req.KbId).
+		Where(`((node_links.type = ? AND (t.id IS NULL OR NOT EXISTS (
+			SELECT 1 FROM kb_release_node_releases r
+			WHERE r.node_id = t.id AND r.release_id = (
+				SELECT id FROM kb_releases WHERE kb_id = node_links.kb_id ORDER BY created_at DESC LIMIT 1
+			)
+		))) OR (node_links.type = ? AND c.broken))`, consts.NodeLinkTypeInternal, consts.NodeLinkTypeExternal)
+	if req.Type != "" {
+		query = query.Where("node_links.type = ?", req.Type)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, nil, err
+	}
+
+	items := make([]v1.BrokenLinkItem, 0)
+	if err := query.
+		Select("node_links.source_node_id, s.name as source_name, node_links.url, node_links.type, node_links.target_node_id, "+
+			"CASE WHEN node_links.type = ? THEN ? WHEN t.id IS NULL THEN ? ELSE ? END as reason, "+
+			"COALESCE(c.status_code, 0) as status_code, COALESCE(c.error, '') as error, c.checked_at",
+			consts.NodeLinkTypeExternal, consts.NodeLinkBrokenReasonUnreachable, consts.NodeLinkBrokenReasonDeleted, consts.NodeLinkBrokenReasonUnpublished).
+		Order("node_links.type, s.name, node_links.id").
+		Offset(req.Offset()).
+		Limit(req.Limit()).
+		Find(&items).Error; err != nil {
+		return 0, nil, err
+	}
+	return total, items, nil
+}
+
+func (r *NodeLinkRepository) GetBacklinks(ctx context.Context, kbID, nodeID string) ([]v1.NodeBacklinkItem, error) {
+	items := make([]v1.NodeBacklinkItem, 0)
+	if err := r.db.WithContext(ctx).
+		Model(&domain.NodeLink{}).
+		Joins("JOIN nodes s ON s.id = node_links.source_node_id").
+		Where("node_links.kb_id = ?", kbID).
+		Where("node_links.type = ?", consts.NodeLinkTypeInternal).
+		Where("node_links.target_node_id = ?", nodeID).
+		Where("node_links.source_node_id != ?", nodeID).
+		Select("s.id as node_id, s.name, s.meta->>'emoji' as emoji, s.status, node_links.url").
+		Order("s.name").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetExternalURLsToCheck returns external urls linked in the kb that were never checked or checked before checkedBefore
+func (r *NodeLinkRepository) GetExternalURLsToCheck(ctx context.Context, kbID string, checkedBefore time.Time) ([]string, error) {
+	urls := make([]string, 0)
+	if err := r.db.WithContext(ctx).
+		Model(&domain.NodeLink{}).
+		Joins("LEFT JOIN external_link_checks c ON c.url = node_links.url").
+		Where("node_links.kb_id = ?", kbID).
+		Where("node_links.type = ?", consts.NodeLinkTypeExternal).
+		Where("(c.url IS NULL OR c.checked_at < ?)", checkedBefore).
+		Distinct("node_links.url").
+		Pluck("node_links.url", &urls).Error; err != nil {
+		return nil, err
+	}
+	return urls, nil
+}
+
+func (r *NodeLinkRepository) UpsertExternalLinkChecks(ctx context.Context, checks []*domain.ExternalLinkCheck) error {
+	if len(checks) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "url"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status_code", "error", "broken", "checked_at"}),
+		}).
+		CreateInBatches(&checks, 100).Error
+}
@@ -0,0 +1,69 @@
+package pg
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+func (r *NodeRepository) GetNodeMetaSchema(ctx context.Context, kbID string) (*domain.NodeMetaSchema, error) {
+	schema := &domain.NodeMetaSchema{Tags: make([]string, 0), Fields: make([]domain.NodeMetaField, 0)}
+	if err := getSettingValue(ctx, r.db, kbID, domain.SettingNodeMetaSchema, schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+func (r *NodeRepository) UpsertNodeMetaSchema(ctx context.Context, kbID string, schema *domain.NodeMetaSchema) error {
+	return upsertSettingValue(ctx, r.db, kbID, domain.SettingNodeMetaSchema, "node tags and custom fields", schema)
+}
+
+// applyNodeMetaFilter filters documents by the meta column of table, folders are always kept
+func applyNodeMetaFilter(query *gorm.DB, table string, filter *domain.NodeMetaFilter) (*gorm.DB, error) {
+	if filter.IsEmpty() {
+		return query, nil
+	}
+	cond := query.Session(&gorm.Session{NewDB: true})
+	var conds *gorm.DB
+	if len(filter.Tags) > 0 {
+		tags, err := json.Marshal(filter.Tags)
+		if err != nil {
+			return nil, err
+		}
+		conds = cond.Where(table+".meta->'tags' @> ?::jsonb", string(tags))
+	}
+	keys := lo.Keys(filter.Fields)
+	slices.Sort(keys)
+	for _, key := range keys {
+		if conds == nil {
+			conds = cond.Where(table+".meta->'fields'->>? = ?", key, filter.Fields[key])
+		} else {
+			conds = conds.Where(table+".meta->'fields'->>? = ?", key, filter.Fields[key])
+		}
+	}
+	return query.Where(cond.Where(table+".type = ?", domain.NodeTypeFolder).Or(conds)), nil
+}
+
+// GetReleasedDocIDsByMetaFilter returns rag doc ids of documents in the latest release matching filter
+func (r *NodeRepository) GetReleasedDocIDsByMetaFilter(ctx context.Context, kbID string, filter *domain.NodeMetaFilter) ([]string, error) {
+	query := r.db.WithContext(ctx).
+		Model(&domain.KBReleaseNodeRelease{}).
+		Joins("JOIN node_releases ON node_releases.id = kb_release_node_releases.node_release_id").
+		Where("kb_release_node_releases.release_id = (SELECT id FROM kb_releases WHERE kb_id = ? ORDER BY created_at DESC LIMIT 1)", kbID).
+		Where("node_releases.type = ?", domain.NodeTypeDocument).
+		Where("node_releases.doc_id != ''")
+	query, err := applyNodeMetaFilter(query, "node_releases", filter)
+	if err != nil {
+		return nil, err
+	}
+	docIDs := make([]string, 0)
+	if err := query.Pluck("node_releases.doc_id", &docIDs).Error; err != nil {
+		return nil, err
+	}
+	return docIDs, nil
+}
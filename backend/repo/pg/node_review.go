@@ -0,0 +1,150 @@
+package pg
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	v1 "github.com/chaitin/panda-wiki/api/node/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/store/pg"
+)
+
+type NodeReviewRepository struct {
+	db     *pg.DB
+	logger *log.Logger
+}
+
+func NewNodeReviewRepository(db *pg.DB, logger *log.Logger) *NodeReviewRepository {
+	return &NodeReviewRepository{db: db, logger: logger.WithModule("repo.pg.node_review")}
+}
+
+func (r *NodeReviewRepository) GetPolicy(ctx context.Context, kbID string) (*domain.NodeReviewPolicy, error) {
+	policy := &domain.NodeReviewPolicy{ReviewerIDs: make([]string, 0)}
+	if err := getSettingValue(ctx, r.db, kbID, domain.SettingNodeReviewPolicy, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (r *NodeReviewRepository) UpsertPolicy(ctx context.Context, kbID string, policy *domain.NodeReviewPolicy) error {
+	return upsertSettingValue(ctx, r.db, kbID, domain.SettingNodeReviewPolicy, "node review policy", policy)
+}
+
+// SubmitReviews resets the review state of the given nodes to pending
+func (r *NodeReviewRepository) SubmitReviews(ctx context.Context, reviews []*domain.NodeReview, logs []*domain.NodeReviewLog) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "node_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"status", "submitter_id", "reviewer_id", "comment", "submitted_at", "reviewed_at", "updated_at",
+			}),
+		}).CreateInBatches(&reviews, 100).Error; err != nil {
+			return err
+		}
+		return tx.CreateInBatches(&logs, 100).Error
+	})
+}
+
+func (r *NodeReviewRepository) GetReviewByNodeID(ctx context.Context, kbID, nodeID string) (*domain.NodeReview, error) {
+	var review domain.NodeReview
+	if err := r.db.WithContext(ctx).
+		Model(&domain.NodeReview{}).
+		Where("kb_id = ?", kbID).
+		Where("node_id = ?", nodeID).
+		First(&review).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// UpdateReviewDecision stores the reviewer's decision if the review is still pending
+func (r *NodeReviewRepository) UpdateReviewDecision(ctx context.Context, review *domain.NodeReview, reviewLog *domain.NodeReviewLog) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&domain.NodeReview{}).
+			Where("id = ?", review.ID).
+			Where("status = ?", consts.NodeReviewStatusPending).
+			Updates(map[string]any{
+				"status":      review.Status,
+				"reviewer_id": review.ReviewerID,
+				"comment":     review.Comment,
+				"reviewed_at": review.ReviewedAt,
+				"updated_at":  time.Now(),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return domain.ErrNodeReviewStatusInvalid
+		}
+		return tx.Create(reviewLog).Error
+	})
+}
+
+func (r *NodeReviewRepository) GetReviewQueue(ctx context.Context, req *v1.NodeReviewQueueReq) (int64, []v1.NodeReviewQueueItem, error) {
+	status := req.Status
+	if status == consts.NodeReviewStatusNone {
+		status = consts.NodeReviewStatusPending
+	}
+	query := r.db.WithContext(ctx).
+		Model(&domain.NodeReview{}).
+		Joins("JOIN nodes ON nodes.id = node_reviews.node_id").
+		Joins("LEFT JOIN users ON users.id = node_reviews.submitter_id").
+		Where("node_reviews.kb_id = ?", req.KbId).
+		Where("node_reviews.status = ?", status)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, nil, err
+	}
+
+	items := make([]v1.NodeReviewQueueItem, 0)
+	if err := query.
+		Select("node_reviews.node_id, nodes.name, nodes.type, nodes.meta->>'emoji' as emoji, node_reviews.status, node_reviews.comment, node_reviews.submitter_id, users.account as submitter, node_reviews.submitted_at, nodes.edit_time").
+		Order("node_reviews.submitted_at ASC").
+		Offset(req.Offset()).
+		Limit(req.Limit()).
+		Find(&items).Error; err != nil {
+		return 0, nil, err
+	}
+	return total, items, nil
+}
+
+func (r *NodeReviewRepository) GetReviewLogs(ctx context.Context, kbID, nodeID string) ([]v1.NodeReviewLogItem, error) {
+	logs := make([]v1.NodeReviewLogItem, 0)
+	if err := r.db.WithContext(ctx).
+		Model(&domain.NodeReviewLog{}).
+		Joins("LEFT JOIN users ON users.id = node_review_logs.user_id").
+		Where("node_review_logs.kb_id = ?", kbID).
+		Where("node_review_logs.node_id = ?", nodeID).
+		Select("node_review_logs.id, node_review_logs.user_id, users.account, node_review_logs.action, node_review_logs.comment, node_review_logs.created_at").
+		Order("node_review_logs.id DESC").
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// GetUnapprovedNodeNames returns the names of documents among nodeIDs
+// that have no approval covering their latest edit
+func (r *NodeReviewRepository) GetUnapprovedNodeNames(ctx context.Context, kbID string, nodeIDs []string) ([]string, error) {
+	names := make([]string, 0)
+	if len(nodeIDs) == 0 {
+		return names, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Model(&domain.Node{}).
+		Joins("LEFT JOIN node_reviews ON node_reviews.node_id = nodes.id").
+		Where("nodes.kb_id = ?", kbID).
+		Where("nodes.id IN ?", nodeIDs).
+		Where("nodes.type = ?", domain.NodeTypeDocument).
+		Where("node_reviews.id IS NULL OR node_reviews.status != ? OR nodes.edit_time > node_reviews.submitted_at", consts.NodeReviewStatusApproved).
+		Pluck("nodes.name", &names).Error; err != nil {
+		return nil, err
+	}
+	return names, nil
+}
@@ -0,0 +1,72 @@
+package pg
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/store/pg"
+)
+
+type NodeShareLinkRepository struct {
+	db     *pg.DB
+	logger *log.Logger
+}
+
+func NewNodeShareLinkRepository(db *pg.DB, logger *log.Logger) *NodeShareLinkRepository {
+	return &NodeShareLinkRepository{
+		db:     db,
+		logger: logger.WithModule("repo.pg.node_share_link"),
+	}
+}
+
+func (r *NodeShareLinkRepository) CreateLink(ctx context.Context, link *domain.NodeShareLink) error {
+	return r.db.WithContext(ctx).Create(link).Error
+}
+
+func (r *NodeShareLinkRepository) GetLink(ctx context.Context, id string) (*domain.NodeShareLink, error) {
+	var link domain.NodeShareLink
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&link).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// ListLinks nodeID 为空时返回知识库下全部链接
+func (r *NodeShareLinkRepository) ListLinks(ctx context.Context, kbID, nodeID string) ([]domain.NodeShareLink, error) {
+	query := r.db.WithContext(ctx).Where("kb_id = ?", kbID)
+	if nodeID != "" {
+		query = query.Where("node_id = ?", nodeID)
+	}
+	links := make([]domain.NodeShareLink, 0)
+	if err := query.Order("created_at DESC").Find(&links).Error; err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// RevokeLink 保留记录以便查看访问次数
+func (r *NodeShareLinkRepository) RevokeLink(ctx context.Context, kbID, id string) error {
+	result := r.db.WithContext(ctx).Model(&domain.NodeShareLink{}).
+		Where("kb_id = ? AND id = ? AND revoked_at IS NULL", kbID, id).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *NodeShareLinkRepository) IncrViewCount(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&domain.NodeShareLink{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"view_count":     gorm.Expr("view_count + 1"),
+			"last_viewed_at": time.Now(),
+		}).Error
+}
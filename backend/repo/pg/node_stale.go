@@ -0,0 +1,93 @@
+package pg
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+	"gorm.io/gorm"
+
+	v1 "github.com/chaitin/panda-wiki/api/node/v1"
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+const nodeFreshSinceExpr = "GREATEST(nodes.edit_time, nodes.reviewed_at)"
+
+// staleNodeQuery selects documents whose review interval has elapsed since the
+// last edit or review, or that are cited by answers disliked after dislikeSince
+// and after the last edit or review
+func (r *NodeRepository) staleNodeQuery(ctx context.Context, kbID string, dislikeSince time.Time) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Model(&domain.Node{}).
+		Joins("LEFT JOIN users ou ON ou.id = nodes.owner_id").
+		Joins(`LEFT JOIN LATERAL (
+			SELECT COUNT(DISTINCT cm.id) AS dislike_count
+			FROM conversation_references cr
+			JOIN conversation_messages cm ON cm.conversation_id = cr.conversation_id
+			WHERE cr.node_id = nodes.id
+				AND cm.role = ?
+				AND cm.info->>'score' = ?
+				AND cm.created_at > ?
+				AND cm.created_at > `+nodeFreshSinceExpr+`
+		) d ON true`, schema.Assistant, "-1", dislikeSince).
+		Where("nodes.kb_id = ?", kbID).
+		Where("nodes.type = ?", domain.NodeTypeDocument).
+		Where("((nodes.review_interval > 0 AND " + nodeFreshSinceExpr + " + make_interval(days => nodes.review_interval) < NOW()) OR d.dislike_count > 0)")
+}
+
+func (r *NodeRepository) selectStaleNodes(query *gorm.DB) *gorm.DB {
+	return query.
+		Select("nodes.id, nodes.name, nodes.meta->>'emoji' as emoji, nodes.owner_id, ou.account as owner, nodes.review_interval, nodes.edit_time, nodes.reviewed_at, " +
+			"COALESCE(d.dislike_count, 0) as dislike_count, " +
+			"(nodes.review_interval > 0 AND " + nodeFreshSinceExpr + " + make_interval(days => nodes.review_interval) < NOW()) as expired").
+		Order("expired DESC, dislike_count DESC, " + nodeFreshSinceExpr + " ASC")
+}
+
+func (r *NodeRepository) GetStaleNodeList(ctx context.Context, req *v1.StaleNodeListReq, dislikeSince time.Time) (int64, []v1.StaleNodeItem, error) {
+	query := r.staleNodeQuery(ctx, req.KbId, dislikeSince)
+	if req.OwnerID != "" {
+		query = query.Where("nodes.owner_id = ?", req.OwnerID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, nil, err
+	}
+
+	items := make([]v1.StaleNodeItem, 0)
+	if err := r.selectStaleNodes(query).
+		Offset(req.Offset()).
+		Limit(req.Limit()).
+		Find(&items).Error; err != nil {
+		return 0, nil, err
+	}
+	return total, items, nil
+}
+
+// GetUnnotifiedStaleNodes returns stale documents whose owner has not been
+// notified since they were last edited or reviewed
+func (r *NodeRepository) GetUnnotifiedStaleNodes(ctx context.Context, kbID string, dislikeSince time.Time) ([]v1.StaleNodeItem, error) {
+	items := make([]v1.StaleNodeItem, 0)
+	if err := r.selectStaleNodes(r.staleNodeQuery(ctx, kbID, dislikeSince)).
+		Where("(nodes.stale_notified_at IS NULL OR nodes.stale_notified_at < " + nodeFreshSinceExpr + ")").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// MarkNodesReviewed resets the review clock without touching content or edit time
+func (r *NodeRepository) MarkNodesReviewed(ctx context.Context, kbID string, ids []string, reviewedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.Node{}).
+		Where("kb_id = ?", kbID).
+		Where("id IN ?", ids).
+		UpdateColumn("reviewed_at", reviewedAt).Error
+}
+
+func (r *NodeRepository) UpdateNodesStaleNotifiedAt(ctx context.Context, ids []string, notifiedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.Node{}).
+		Where("id IN ?", ids).
+		UpdateColumn("stale_notified_at", notifiedAt).Error
+}
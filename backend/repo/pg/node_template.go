@@ -0,0 +1,66 @@
+package pg
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/chaitin/panda-wiki/api/node/v1"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/store/pg"
+)
+
+type NodeTemplateRepository struct {
+	db     *pg.DB
+	logger *log.Logger
+}
+
+func NewNodeTemplateRepository(db *pg.DB, logger *log.Logger) *NodeTemplateRepository {
+	return &NodeTemplateRepository{db: db, logger: logger.WithModule("repo.pg.node_template")}
+}
+
+func (r *NodeTemplateRepository) Create(ctx context.Context, template *domain.NodeTemplate) error {
+	return r.db.WithContext(ctx).Create(template).Error
+}
+
+func (r *NodeTemplateRepository) GetList(ctx context.Context, kbID string) ([]v1.NodeTemplateListItem, error) {
+	items := make([]v1.NodeTemplateListItem, 0)
+	if err := r.db.WithContext(ctx).
+		Model(&domain.NodeTemplate{}).
+		Joins("LEFT JOIN users ON users.id = node_templates.creator_id").
+		Where("node_templates.kb_id = ?", kbID).
+		Select("node_templates.id, node_templates.name, node_templates.description, node_templates.emoji, node_templates.tags, node_templates.creator_id, users.account as creator, node_templates.updated_at").
+		Order("node_templates.created_at ASC").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *NodeTemplateRepository) GetByID(ctx context.Context, kbID, id string) (*domain.NodeTemplate, error) {
+	var template domain.NodeTemplate
+	if err := r.db.WithContext(ctx).
+		Model(&domain.NodeTemplate{}).
+		Where("kb_id = ?", kbID).
+		Where("id = ?", id).
+		First(&template).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *NodeTemplateRepository) Update(ctx context.Context, kbID, id string, updateMap map[string]any) error {
+	updateMap["updated_at"] = time.Now()
+	return r.db.WithContext(ctx).
+		Model(&domain.NodeTemplate{}).
+		Where("kb_id = ?", kbID).
+		Where("id = ?", id).
+		Updates(updateMap).Error
+}
+
+func (r *NodeTemplateRepository) Delete(ctx context.Context, kbID, id string) error {
+	return r.db.WithContext(ctx).
+		Where("kb_id = ?", kbID).
+		Where("id = ?", id).
+		Delete(&domain.NodeTemplate{}).Error
+}
@@ -0,0 +1,30 @@
+package pg
+
+import (
+	"context"
+
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/store/pg"
+)
+
+type NotifyRepository struct {
+	db     *pg.DB
+	logger *log.Logger
+}
+
+func NewNotifyRepository(db *pg.DB, logger *log.Logger) *NotifyRepository {
+	return &NotifyRepository{db: db, logger: logger.WithModule("repo.pg.notify")}
+}
+
+func (r *NotifyRepository) GetSettings(ctx context.Context, kbID string) (*domain.NotifySettings, error) {
+	settings := &domain.NotifySettings{Webhooks: make([]domain.NotifyWebhook, 0), Broadcasts: make([]domain.ReleaseBroadcast, 0)}
+	if err := getSettingValue(ctx, r.db, kbID, domain.SettingNotify, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (r *NotifyRepository) UpsertSettings(ctx context.Context, kbID string, settings *domain.NotifySettings) error {
+	return upsertSettingValue(ctx, r.db, kbID, domain.SettingNotify, "notify settings", settings)
+}
@@ -10,6 +10,10 @@ var ProviderSet = wire.NewSet(
 	pg.ProviderSet,
 
 	NewNodeRepository,
+	NewNodeReviewRepository,
+	NewNodeLinkRepository,
+	NewNodeTemplateRepository,
+	NewMailReplyRepository,
 	NewAppRepository,
 	NewConversationRepository,
 	NewUserRepository,
@@ -23,4 +27,9 @@ var ProviderSet = wire.NewSet(
 	NewAuthRepo,
 	NewWechatRepository,
 	NewAPITokenRepo,
+	NewNotifyRepository,
+	NewAuditLogRepository,
+	NewKBRoleRepository,
+	NewNodeShareLinkRepository,
+	NewNodeAccessRequestRepository,
 )
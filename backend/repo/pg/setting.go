@@ -0,0 +1,50 @@
+package pg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/store/pg"
+)
+
+// getSettingValue unmarshals the kb setting into v, v is left untouched if the setting does not exist
+func getSettingValue(ctx context.Context, db *pg.DB, kbID, key string, v any) error {
+	var setting domain.Setting
+	err := db.WithContext(ctx).Table("settings").
+		Where("kb_id = ? AND key = ?", kbID, key).
+		First(&setting).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	return json.Unmarshal(setting.Value, v)
+}
+
+func upsertSettingValue(ctx context.Context, db *pg.DB, kbID, key, description string, v any) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	return db.WithContext(ctx).Table("settings").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "kb_id"}, {Name: "key"}},
+			DoUpdates: clause.Assignments(map[string]any{"value": value, "updated_at": now}),
+		}).
+		Create(&domain.Setting{
+			KBID:        kbID,
+			Key:         key,
+			Value:       value,
+			Description: description,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}).Error
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/lib/pq"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 
@@ -121,6 +122,21 @@ func (r *UserRepository) UpdateUserPassword(ctx context.Context, userID string,
 	return r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", userID).Update("password", string(hashedPassword)).Error
 }
 
+func (r *UserRepository) UpdateUserEmail(ctx context.Context, userID string, email string) error {
+	return r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", userID).Update("email", email).Error
+}
+
+func (r *UserRepository) GetUsersByIDs(ctx context.Context, ids []string) ([]*domain.User, error) {
+	var users []*domain.User
+	if err := r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id IN ?", ids).
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 func (r *UserRepository) DeleteUser(ctx context.Context, userID string) error {
 	if err := r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", userID).Delete(&domain.User{}).Error; err != nil {
 		return err
@@ -131,3 +147,44 @@ func (r *UserRepository) DeleteUser(ctx context.Context, userID string) error {
 	}
 	return nil
 }
+
+// UpdateUserTOTP 开启时保存密钥及恢复码，关闭时一并清空
+func (r *UserRepository) UpdateUserTOTP(ctx context.Context, userID string, secret string, recoveryCodes []string) error {
+	if recoveryCodes == nil {
+		recoveryCodes = []string{}
+	}
+	return r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", userID).Updates(map[string]any{
+		"totp_secret":    secret,
+		"totp_enabled":   secret != "",
+		"recovery_codes": pq.StringArray(recoveryCodes),
+	}).Error
+}
+
+func (r *UserRepository) UpdateUserRecoveryCodes(ctx context.Context, userID string, recoveryCodes []string) error {
+	return r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", userID).
+		Update("recovery_codes", pq.StringArray(recoveryCodes)).Error
+}
+
+// UseRecoveryCode 恢复码只能使用一次，并发使用时只有一个成功
+func (r *UserRepository) UseRecoveryCode(ctx context.Context, userID string, codeHash string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ?", userID).
+		Where("? = ANY(recovery_codes)", codeHash).
+		Update("recovery_codes", gorm.Expr("array_remove(recovery_codes, ?)", codeHash))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *UserRepository) GetLoginSecurityPolicy(ctx context.Context) (*domain.LoginSecurityPolicy, error) {
+	policy := &domain.LoginSecurityPolicy{}
+	if err := getSettingValue(ctx, r.db, "", domain.SettingLoginSecurity, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (r *UserRepository) UpsertLoginSecurityPolicy(ctx context.Context, policy *domain.LoginSecurityPolicy) error {
+	return upsertSettingValue(ctx, r.db, "", domain.SettingLoginSecurity, "login security policy", policy)
+}
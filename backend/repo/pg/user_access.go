@@ -1,6 +1,7 @@
 package pg
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -120,28 +121,42 @@ func (r *UserAccessRepository) ValidateRole(userID string, role consts.UserRole)
 	return false, nil
 }
 
-func (r *UserAccessRepository) ValidateKBPerm(kbId, userId string, perm consts.UserKBPermission) (bool, error) {
+// GetKBCapabilities 用户在知识库内的能力，不是知识库成员时返回空
+func (r *UserAccessRepository) GetKBCapabilities(kbId, userId string) ([]consts.KBCapability, error) {
+	return kbUserCapabilities(r.db.DB, kbId, userId)
+}
+
+// kbUserCapabilities 管理员拥有全部能力，成员按自定义角色或内置权限解析
+func kbUserCapabilities(db *gorm.DB, kbId, userId string) ([]consts.KBCapability, error) {
 	var user domain.User
-	if err := r.db.Model(&domain.User{}).Where("id = ?", userId).First(&user).Error; err != nil {
-		return false, fmt.Errorf("get user failed %s", err)
+	if err := db.Model(&domain.User{}).Where("id = ?", userId).First(&user).Error; err != nil {
+		return nil, fmt.Errorf("get user failed %s", err)
 	}
 
 	if user.Role == consts.UserRoleAdmin {
-		return true, nil
+		return consts.KBCapabilities, nil
 	}
 
 	var kbUser domain.KBUsers
-	err := r.db.Model(&domain.KBUsers{}).
+	err := db.Model(&domain.KBUsers{}).
 		Where("kb_id = ? AND user_id = ?", kbId, userId).
 		First(&kbUser).Error
 	if err != nil {
-		return false, fmt.Errorf("get kb user failed %s", err)
-
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get kb user failed %s", err)
 	}
 
-	if kbUser.Perm == perm || kbUser.Perm == consts.UserKBPermissionFullControl {
-		return true, nil
+	if kbUser.RoleID == "" {
+		return kbUser.Perm.Capabilities(), nil
 	}
-
-	return false, nil
+	var role domain.KBRole
+	if err := db.Model(&domain.KBRole{}).Where("id = ?", kbUser.RoleID).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get kb role failed %s", err)
+	}
+	return role.KBCapabilities(), nil
 }
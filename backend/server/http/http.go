@@ -3,8 +3,10 @@ package http
 import (
 	"context"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/getsentry/sentry-go"
@@ -42,6 +44,7 @@ func NewEcho(
 	config *config.Config,
 	pwMiddleware *PWMiddleware.ReadOnlyMiddleware,
 	sessionMiddleware *PWMiddleware.SessionMiddleware,
+	auditMiddleware *PWMiddleware.AuditMiddleware,
 ) *echo.Echo {
 
 	// Initialize Sentry if enabled
@@ -63,6 +66,7 @@ func NewEcho(
 	e.HidePort = true
 
 	e.Binder = &MyBinder{}
+	e.IPExtractor = newIPExtractor(config.HTTP.TrustedProxies, logger)
 
 	if os.Getenv("ENV") == "local" {
 		e.Debug = true
@@ -123,10 +127,30 @@ func NewEcho(
 
 	e.Use(pwMiddleware.ReadOnly)
 	e.Use(sessionMiddleware.Session())
+	e.Use(auditMiddleware.Audit)
 
 	return e
 }
 
+// newIPExtractor takes the client ip from X-Forwarded-For only when the request came through a trusted proxy,
+// otherwise clients could rotate the header to get around the login limits keyed on their ip
+func newIPExtractor(trustedProxies []string, logger *log.Logger) echo.IPExtractor {
+	options := []echo.TrustOption{
+		echo.TrustLoopback(true),
+		echo.TrustLinkLocal(true),
+		echo.TrustPrivateNet(true),
+	}
+	for _, cidr := range trustedProxies {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			logger.Warn("skip invalid trusted proxy", log.String("cidr", cidr), log.Error(err))
+			continue
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
 type MyBinder struct {
 	echo.DefaultBinder
 }
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/chaitin/panda-wiki/config"
+	"github.com/chaitin/panda-wiki/log"
+)
+
+// newLimitedEcho serves a login that locks the client ip after 3 failures, like the simple auth login
+func newLimitedEcho(t *testing.T, trustedProxies []string) *echo.Echo {
+	t.Helper()
+	cfg, _ := config.NewConfig()
+	e := echo.New()
+	e.IPExtractor = newIPExtractor(trustedProxies, log.NewLogger(cfg))
+	failures := make(map[string]int)
+	e.POST("/login", func(c echo.Context) error {
+		key := "simple:kb:" + c.RealIP()
+		if failures[key] >= 3 {
+			return c.String(http.StatusTooManyRequests, key)
+		}
+		failures[key]++
+		return c.String(http.StatusUnauthorized, key)
+	})
+	return e
+}
+
+func login(e *echo.Echo, remoteAddr string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	req.RemoteAddr = remoteAddr
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSpoofedForwardedForDoesNotResetLock(t *testing.T) {
+	e := newLimitedEcho(t, nil)
+	for i := range 5 {
+		spoofed := fmt.Sprintf("198.51.100.%d", i+1)
+		rec := login(e, "203.0.113.7:40000", map[string]string{
+			echo.HeaderXForwardedFor: spoofed,
+			echo.HeaderXRealIP:       spoofed,
+		})
+		if rec.Body.String() != "simple:kb:203.0.113.7" {
+			t.Fatalf("request %d keyed on %q, want the connection address", i, rec.Body.String())
+		}
+		if i >= 3 && rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("request %d with a new X-Forwarded-For got %d, want the lock to hold", i, rec.Code)
+		}
+	}
+}
+
+func TestForwardedForThroughTrustedProxy(t *testing.T) {
+	e := newLimitedEcho(t, []string{"203.0.113.0/24"})
+	for i := range 5 {
+		// the proxy appends the address it saw to whatever the client sent
+		rec := login(e, "203.0.113.10:443", map[string]string{
+			echo.HeaderXForwardedFor: fmt.Sprintf("198.51.100.%d, 192.0.2.8, 169.254.15.2", i+1),
+		})
+		if rec.Body.String() != "simple:kb:192.0.2.8" {
+			t.Fatalf("request %d keyed on %q, want the address the proxy saw", i, rec.Body.String())
+		}
+		if i >= 3 && rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("request %d got %d, want the lock to hold", i, rec.Code)
+		}
+	}
+}
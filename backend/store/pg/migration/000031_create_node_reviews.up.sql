@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS node_reviews (
+    id TEXT PRIMARY KEY,
+    kb_id TEXT NOT NULL,
+    node_id TEXT NOT NULL,
+    status TEXT NOT NULL,
+    submitter_id TEXT NOT NULL DEFAULT '',
+    reviewer_id TEXT NOT NULL DEFAULT '',
+    comment TEXT NOT NULL DEFAULT '',
+    submitted_at TIMESTAMP NOT NULL,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE(node_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_node_reviews_kb_id_status ON node_reviews (kb_id, status);
+
+CREATE TABLE IF NOT EXISTS node_review_logs (
+    id SERIAL PRIMARY KEY,
+    kb_id TEXT NOT NULL,
+    node_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    action TEXT NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_node_review_logs_node_id ON node_review_logs (node_id);
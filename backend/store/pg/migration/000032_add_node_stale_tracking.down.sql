@@ -0,0 +1,9 @@
+ALTER TABLE users DROP COLUMN IF EXISTS email;
+
+DROP INDEX IF EXISTS idx_conversation_references_node_id;
+DROP INDEX IF EXISTS idx_nodes_owner_id;
+
+ALTER TABLE nodes DROP COLUMN IF EXISTS stale_notified_at;
+ALTER TABLE nodes DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE nodes DROP COLUMN IF EXISTS review_interval;
+ALTER TABLE nodes DROP COLUMN IF EXISTS owner_id;
@@ -0,0 +1,11 @@
+ALTER TABLE nodes ADD COLUMN IF NOT EXISTS owner_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE nodes ADD COLUMN IF NOT EXISTS review_interval INT NOT NULL DEFAULT 0;
+ALTER TABLE nodes ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP;
+ALTER TABLE nodes ADD COLUMN IF NOT EXISTS stale_notified_at TIMESTAMP;
+
+UPDATE nodes SET owner_id = creator_id WHERE owner_id = '';
+
+CREATE INDEX IF NOT EXISTS idx_nodes_owner_id ON nodes (owner_id);
+CREATE INDEX IF NOT EXISTS idx_conversation_references_node_id ON conversation_references (node_id);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS node_links (
+    id SERIAL PRIMARY KEY,
+    kb_id TEXT NOT NULL,
+    source_node_id TEXT NOT NULL,
+    url TEXT NOT NULL,
+    type TEXT NOT NULL,
+    target_node_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE(source_node_id, url)
+);
+
+CREATE INDEX IF NOT EXISTS idx_node_links_kb_id_type ON node_links (kb_id, type);
+CREATE INDEX IF NOT EXISTS idx_node_links_target_node_id ON node_links (target_node_id);
+
+CREATE TABLE IF NOT EXISTS external_link_checks (
+    url TEXT PRIMARY KEY,
+    status_code INT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    broken BOOLEAN NOT NULL DEFAULT FALSE,
+    checked_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS mail_replies (
+    id TEXT PRIMARY KEY,
+    kb_id TEXT NOT NULL,
+    app_id TEXT NOT NULL,
+    conversation_id TEXT NOT NULL DEFAULT '',
+    to_address TEXT NOT NULL,
+    to_name TEXT NOT NULL DEFAULT '',
+    subject TEXT NOT NULL DEFAULT '',
+    in_reply_to TEXT NOT NULL DEFAULT '',
+    "references" JSONB NOT NULL DEFAULT '[]',
+    question TEXT NOT NULL DEFAULT '',
+    answer TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    reviewer_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_mail_replies_kb_id_status ON mail_replies (kb_id, status);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_conversations_kb_id_escalation_status;
+
+ALTER TABLE conversation_messages DROP COLUMN IF EXISTS agent_id;
+
+ALTER TABLE conversations DROP COLUMN IF EXISTS resolved_at;
+ALTER TABLE conversations DROP COLUMN IF EXISTS escalated_at;
+ALTER TABLE conversations DROP COLUMN IF EXISTS escalation_reason;
+ALTER TABLE conversations DROP COLUMN IF EXISTS escalation_status;
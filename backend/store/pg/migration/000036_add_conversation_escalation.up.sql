@@ -0,0 +1,8 @@
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS escalation_status TEXT NOT NULL DEFAULT '';
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS escalation_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMP;
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS resolved_at TIMESTAMP;
+
+ALTER TABLE conversation_messages ADD COLUMN IF NOT EXISTS agent_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_conversations_kb_id_escalation_status ON conversations (kb_id, escalation_status);
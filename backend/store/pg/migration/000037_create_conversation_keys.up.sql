@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS conversation_keys (
+    app_id TEXT NOT NULL,
+    key TEXT NOT NULL,
+    conversation_id TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (app_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_conversation_keys_updated_at ON conversation_keys (updated_at);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS conversation_attachments (
+    id TEXT PRIMARY KEY,
+    conversation_id TEXT NOT NULL,
+    message_id TEXT NOT NULL,
+    kb_id TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    type TEXT NOT NULL,
+    content TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_conversation_attachments_conversation_id ON conversation_attachments (conversation_id);
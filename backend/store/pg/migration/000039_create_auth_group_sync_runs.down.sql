@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_auth_groups_kb_id_source_type_sync_id;
+DROP TABLE IF EXISTS auth_group_sync_runs;
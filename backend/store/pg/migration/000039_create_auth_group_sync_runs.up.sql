@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS auth_group_sync_runs (
+    id SERIAL PRIMARY KEY,
+    kb_id TEXT NOT NULL,
+    source_type TEXT NOT NULL,
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    trigger TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    diff JSONB NOT NULL DEFAULT '{}',
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_group_sync_runs_kb_id_started_at ON auth_group_sync_runs (kb_id, started_at);
+
+CREATE INDEX IF NOT EXISTS idx_auth_groups_kb_id_source_type_sync_id ON auth_groups (kb_id, source_type, sync_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_auths_kb_id_source_type_union_id;
+
+ALTER TABLE auths DROP COLUMN IF EXISTS active;
+ALTER TABLE auths DROP COLUMN IF EXISTS external_id;
@@ -0,0 +1,4 @@
+ALTER TABLE auths ADD COLUMN IF NOT EXISTS external_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE auths ADD COLUMN IF NOT EXISTS active BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE INDEX IF NOT EXISTS idx_auths_kb_id_source_type_union_id ON auths (kb_id, source_type, union_id);
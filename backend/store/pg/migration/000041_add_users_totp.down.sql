@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN IF EXISTS recovery_codes;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_enabled;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_secret;
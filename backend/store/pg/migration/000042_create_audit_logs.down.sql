@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_audit_logs_forbid_update ON audit_logs;
+DROP FUNCTION IF EXISTS audit_logs_forbid_update();
+DROP TABLE IF EXISTS audit_logs;
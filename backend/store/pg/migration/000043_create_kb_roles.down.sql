@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_kb_users_role_id;
+ALTER TABLE kb_users DROP COLUMN IF EXISTS role_id;
+DROP TABLE IF EXISTS kb_roles;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS kb_roles (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    capabilities TEXT[] NOT NULL DEFAULT ARRAY[]::TEXT[],
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_uniq_kb_roles_name ON kb_roles (name);
+
+-- role_id 不为空时按自定义角色鉴权，perm 不再生效
+ALTER TABLE kb_users ADD COLUMN IF NOT EXISTS role_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_kb_users_role_id ON kb_users (role_id);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS node_share_links (
+    id TEXT PRIMARY KEY,
+    kb_id TEXT NOT NULL,
+    node_id TEXT NOT NULL,
+    draft BOOLEAN NOT NULL DEFAULT FALSE,
+    password_hash TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ NOT NULL,
+    view_count BIGINT NOT NULL DEFAULT 0,
+    last_viewed_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_node_share_links_kb_id_node_id ON node_share_links (kb_id, node_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS node_auth_grants;
+DROP TABLE IF EXISTS node_access_requests;
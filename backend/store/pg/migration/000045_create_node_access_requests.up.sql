@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS node_access_requests (
+    id TEXT PRIMARY KEY,
+    kb_id TEXT NOT NULL,
+    node_id TEXT NOT NULL,
+    auth_id BIGINT NOT NULL,
+    perm TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    grant_type TEXT NOT NULL DEFAULT '',
+    auth_group_id BIGINT NOT NULL DEFAULT 0,
+    reviewer_id TEXT NOT NULL DEFAULT '',
+    review_comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reviewed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_node_access_requests_kb_id_status ON node_access_requests (kb_id, status);
+CREATE INDEX IF NOT EXISTS idx_node_access_requests_auth_id ON node_access_requests (auth_id);
+-- 同一读者对同一文档同一权限只能有一个待处理的申请
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_node_access_requests_pending ON node_access_requests (node_id, auth_id, perm) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS node_auth_grants (
+    id SERIAL PRIMARY KEY,
+    kb_id TEXT NOT NULL,
+    node_id TEXT NOT NULL,
+    auth_id BIGINT NOT NULL,
+    perm TEXT NOT NULL,
+    request_id TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (node_id, auth_id, perm)
+);
+
+CREATE INDEX IF NOT EXISTS idx_node_auth_grants_auth_id_perm ON node_auth_grants (auth_id, perm);
+CREATE INDEX IF NOT EXISTS idx_node_auth_grants_kb_id ON node_auth_grants (kb_id);
@@ -4,7 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
 	"github.com/cloudwego/eino/schema"
@@ -47,7 +51,7 @@ func (s *CTRAG) CreateKnowledgeBase(ctx context.Context) (string, error) {
 	return dataset.ID, nil
 }
 
-func (s *CTRAG) QueryRecords(ctx context.Context, datasetIDs []string, query string, groupIds []int, similarityThreshold float64, historyMsgs []*schema.Message) ([]*domain.NodeContentChunk, error) {
+func (s *CTRAG) QueryRecords(ctx context.Context, datasetIDs []string, docIDs []string, query string, groupIds []int, similarityThreshold float64, historyMsgs []*schema.Message) ([]*domain.NodeContentChunk, error) {
 	var chatMsgs []rag.ChatMessage
 	for _, msg := range historyMsgs {
 		switch msg.Role {
@@ -68,6 +72,7 @@ func (s *CTRAG) QueryRecords(ctx context.Context, datasetIDs []string, query str
 	s.logger.Debug("retrieving by history msgs", log.Any("history_msgs", historyMsgs), log.Any("chat_msgs", chatMsgs))
 	retrieveReq := rag.RetrievalRequest{
 		DatasetIDs:   datasetIDs,
+		DocumentIDs:  docIDs,
 		Question:     query,
 		TopK:         10,
 		UserGroupIDs: groupIds,
@@ -108,6 +113,8 @@ func (s *CTRAG) UpsertRecords(ctx context.Context, datasetID string, nodeRelease
 			return "", fmt.Errorf("convert html to markdown failed: %w", err)
 		}
 	}
+	// tags and custom fields are carried as front matter so they are indexed with the document
+	markdown = metaFrontMatter(&nodeRelease.Meta) + markdown
 	if _, err := tempFile.Write([]byte(markdown)); err != nil {
 		return "", fmt.Errorf("write temp file failed: %w", err)
 	}
@@ -236,3 +243,28 @@ func (s *CTRAG) ListDocuments(ctx context.Context, datasetID string, params map[
 	}
 	return docs, nil
 }
+
+// metaFrontMatter renders tags and custom fields as yaml front matter
+func metaFrontMatter(meta *domain.NodeMeta) string {
+	if len(meta.Tags) == 0 && len(meta.Fields) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	if len(meta.Tags) > 0 {
+		tags := make([]string, len(meta.Tags))
+		for i, tag := range meta.Tags {
+			tags[i] = strconv.Quote(tag)
+		}
+		sb.WriteString("tags: [" + strings.Join(tags, ", ") + "]\n")
+	}
+	keys := slices.Sorted(maps.Keys(meta.Fields))
+	for _, key := range keys {
+		if meta.Fields[key] == "" {
+			continue
+		}
+		sb.WriteString(key + ": " + strconv.Quote(meta.Fields[key]) + "\n")
+	}
+	sb.WriteString("---\n\n")
+	return sb.String()
+}
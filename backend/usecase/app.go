@@ -16,6 +16,10 @@ import (
 	"github.com/chaitin/panda-wiki/pkg/bot/discord"
 	"github.com/chaitin/panda-wiki/pkg/bot/feishu"
 	"github.com/chaitin/panda-wiki/pkg/bot/lark"
+	"github.com/chaitin/panda-wiki/pkg/bot/mail"
+	"github.com/chaitin/panda-wiki/pkg/bot/slack"
+	"github.com/chaitin/panda-wiki/pkg/bot/teams"
+	"github.com/chaitin/panda-wiki/pkg/bot/telegram"
 	"github.com/chaitin/panda-wiki/repo/pg"
 	"github.com/chaitin/panda-wiki/store/cache"
 )
@@ -37,6 +41,15 @@ type AppUsecase struct {
 	larkMutex     sync.RWMutex
 	discordBots   map[string]*discord.DiscordClient
 	discordMutex  sync.RWMutex
+	slackBots     map[string]*slack.SlackClient
+	slackMutex    sync.RWMutex
+	telegramBots  map[string]*telegram.TelegramClient
+	telegramMutex sync.RWMutex
+	teamsBots     map[string]*teams.TeamsClient
+	teamsMutex    sync.RWMutex
+	mailBots      map[string]*mail.MailClient
+	mailMutex     sync.RWMutex
+	mailReplyRepo *pg.MailReplyRepository
 }
 
 func NewAppUsecase(
@@ -48,24 +61,30 @@ func NewAppUsecase(
 	config *config.Config,
 	chatUsecase *ChatUsecase,
 	cache *cache.Cache,
+	mailReplyRepo *pg.MailReplyRepository,
 ) *AppUsecase {
 	u := &AppUsecase{
-		repo:         repo,
-		nodeUsecase:  nodeUsecase,
-		chatUsecase:  chatUsecase,
-		authRepo:     authRepo,
-		nodeRepo:     nodeRepo,
-		logger:       logger.WithModule("usecase.app"),
-		config:       config,
-		cache:        cache,
-		dingTalkBots: make(map[string]*dingtalk.DingTalkClient),
-		feishuBots:   make(map[string]*feishu.FeishuClient),
-		larkBots:     make(map[string]*lark.LarkClient),
-		discordBots:  make(map[string]*discord.DiscordClient),
-	}
-
-	// Initialize all valid DingTalkBot, FeishuBot, LarkBot and DiscordBot instances
-	apps, err := u.repo.GetAppsByTypes(context.Background(), []domain.AppType{domain.AppTypeDingTalkBot, domain.AppTypeFeishuBot, domain.AppTypeLarkBot, domain.AppTypeDisCordBot})
+		repo:          repo,
+		nodeUsecase:   nodeUsecase,
+		chatUsecase:   chatUsecase,
+		authRepo:      authRepo,
+		nodeRepo:      nodeRepo,
+		logger:        logger.WithModule("usecase.app"),
+		config:        config,
+		cache:         cache,
+		dingTalkBots:  make(map[string]*dingtalk.DingTalkClient),
+		feishuBots:    make(map[string]*feishu.FeishuClient),
+		larkBots:      make(map[string]*lark.LarkClient),
+		discordBots:   make(map[string]*discord.DiscordClient),
+		slackBots:     make(map[string]*slack.SlackClient),
+		telegramBots:  make(map[string]*telegram.TelegramClient),
+		teamsBots:     make(map[string]*teams.TeamsClient),
+		mailBots:      make(map[string]*mail.MailClient),
+		mailReplyRepo: mailReplyRepo,
+	}
+
+	// Initialize all valid DingTalkBot, FeishuBot, LarkBot, DiscordBot, SlackBot, TelegramBot, TeamsBot and MailBot instances
+	apps, err := u.repo.GetAppsByTypes(context.Background(), []domain.AppType{domain.AppTypeDingTalkBot, domain.AppTypeFeishuBot, domain.AppTypeLarkBot, domain.AppTypeDisCordBot, domain.AppTypeSlackBot, domain.AppTypeTelegramBot, domain.AppTypeTeamsBot, domain.AppTypeMailBot})
 	if err != nil {
 		u.logger.Error("failed to get dingtalk bot apps", log.Error(err))
 		return u
@@ -81,6 +100,14 @@ func NewAppUsecase(
 			u.updateLarkBot(app)
 		case domain.AppTypeDisCordBot:
 			u.updateDisCordBot(app)
+		case domain.AppTypeSlackBot:
+			u.updateSlackBot(app)
+		case domain.AppTypeTelegramBot:
+			u.updateTelegramBot(app)
+		case domain.AppTypeTeamsBot:
+			u.updateTeamsBot(app)
+		case domain.AppTypeMailBot:
+			u.updateMailBot(app)
 		}
 	}
 
@@ -119,7 +146,14 @@ func (u *AppUsecase) ValidateUpdateApp(ctx context.Context, id string, req *doma
 	return nil
 }
 
+func (u *AppUsecase) GetApp(ctx context.Context, id string) (*domain.App, error) {
+	return u.repo.GetAppDetail(ctx, id)
+}
+
 func (u *AppUsecase) UpdateApp(ctx context.Context, id string, appRequest *domain.UpdateAppReq) error {
+	if err := u.validateBotRoute(ctx, appRequest.Settings); err != nil {
+		return err
+	}
 	if err := u.handleBotAuths(ctx, id, appRequest.Settings); err != nil {
 		return err
 	}
@@ -142,6 +176,14 @@ func (u *AppUsecase) UpdateApp(ctx context.Context, id string, appRequest *domai
 			u.updateLarkBot(app)
 		case domain.AppTypeDisCordBot:
 			u.updateDisCordBot(app)
+		case domain.AppTypeSlackBot:
+			u.updateSlackBot(app)
+		case domain.AppTypeTelegramBot:
+			u.updateTelegramBot(app)
+		case domain.AppTypeTeamsBot:
+			u.updateTeamsBot(app)
+		case domain.AppTypeMailBot:
+			u.updateMailBot(app)
 		}
 	}
 	return nil
@@ -149,26 +191,56 @@ func (u *AppUsecase) UpdateApp(ctx context.Context, id string, appRequest *domai
 
 func (u *AppUsecase) getQAFunc(kbID string, appType domain.AppType) bot.GetQAFun {
 	return func(ctx context.Context, msg string, info domain.ConversationInfo, ConversationID string) (chan string, error) {
-		auth, err := u.authRepo.GetAuthByKBIDAndSourceType(ctx, kbID, appType.ToSourceType())
+		// group chat bots leave the conversation to the thread and chat the message was sent in
+		if key := bot.ConversationKeyFromContext(ctx); key != nil && ConversationID == "" {
+			app, err := u.repo.GetOrCreateAppByKBIDAndType(ctx, kbID, appType)
+			if err != nil {
+				u.logger.Error("get app failed", log.Error(err))
+				return nil, err
+			}
+			ConversationID = u.chatUsecase.conversationUsecase.ResolveBotConversation(ctx, app, key)
+		}
+
+		// the question is answered from the kb the routing settings of the bot pick
+		route := u.routeBotQuestion(ctx, kbID, appType, msg, info, ConversationID)
+
+		// routed questions use the bot user of the target kb when it has one
+		auth, err := u.authRepo.GetAuthByKBIDAndSourceType(ctx, route.KBID, appType.ToSourceType())
+		if err != nil && route.KBID != kbID {
+			auth, err = u.authRepo.GetAuthByKBIDAndSourceType(ctx, kbID, appType.ToSourceType())
+		}
 		if err != nil {
 			u.logger.Error("get auth failed", log.Error(err))
 			return nil, err
 		}
 		info.UserInfo.AuthUserID = auth.ID
 
+		var attachments []domain.ChatAttachment
+		for _, attachment := range bot.AttachmentsFromContext(ctx) {
+			attachments = append(attachments, domain.ChatAttachment{
+				Name:        attachment.Name,
+				Data:        attachment.Data,
+				ContentType: attachment.ContentType,
+			})
+		}
+
 		eventCh, err := u.chatUsecase.Chat(ctx, &domain.ChatRequest{
-			Message:        msg,
-			KBID:           kbID,
-			AppType:        appType,
-			RemoteIP:       "",
-			ConversationID: ConversationID,
-			Info:           info,
+			Message:           msg,
+			KBID:              route.KBID,
+			MergeKBIDs:        route.MergeKBIDs,
+			AppID:             route.AppID,
+			AppType:           appType,
+			RemoteIP:          "",
+			ConversationID:    ConversationID,
+			ReuseConversation: ConversationID != "",
+			Attachments:       attachments,
+			Info:              info,
 		})
 		if err != nil {
 			return nil, err
 		}
 		// check ai feedback. --> default is open
-		appinfo, err := u.GetAppDetailByKBIDAndAppType(ctx, kbID, domain.AppTypeWeb)
+		appinfo, err := u.GetAppDetailByKBIDAndAppType(ctx, route.KBID, domain.AppTypeWeb)
 		if err != nil {
 			u.logger.Error("wechat GetAppDetailByKBIDAndAppType failed", log.Error(err))
 		}
@@ -178,9 +250,27 @@ func (u *AppUsecase) getQAFunc(kbID string, appType domain.AppType) bot.GetQAFun
 		var dislikeUrl = "%s/feedback?score=-1&message_id=%s"
 		var messageId string
 		var kb *domain.KnowledgeBase
+		var sources []bot.Source
+		// documents of merged kbs link to their own site
+		baseURLs := make(map[string]string)
+		baseURL := func(id string) string {
+			if url, ok := baseURLs[id]; ok {
+				return url
+			}
+			if id == kb.ID {
+				baseURLs[id] = kb.AccessSettings.BaseURL
+			} else if other, err := u.chatUsecase.kbRepo.GetKnowledgeBaseByID(ctx, id); err != nil {
+				u.logger.Error("get source kb failed", log.String("kb_id", id), log.Error(err))
+				baseURLs[id] = kb.AccessSettings.BaseURL
+			} else {
+				baseURLs[id] = other.AccessSettings.BaseURL
+			}
+			return baseURLs[id]
+		}
 
-		if appinfo.Settings.AIFeedbackSettings.AIFeedbackIsEnabled == nil || *appinfo.Settings.AIFeedbackSettings.AIFeedbackIsEnabled { // open
-			kb, err = u.chatUsecase.llmUsecase.kbRepo.GetKnowledgeBaseByID(ctx, kbID)
+		sourcesHook := bot.SourcesHookFromContext(ctx)
+		if sourcesHook != nil || appinfo.Settings.AIFeedbackSettings.AIFeedbackIsEnabled == nil || *appinfo.Settings.AIFeedbackSettings.AIFeedbackIsEnabled { // open
+			kb, err = u.chatUsecase.llmUsecase.kbRepo.GetKnowledgeBaseByID(ctx, route.KBID)
 			if err != nil {
 				u.logger.Error("wechat GetKnowledgeBaseByID failed", log.Error(err))
 			}
@@ -200,6 +290,22 @@ func (u *AppUsecase) getQAFunc(kbID string, appType domain.AppType) bot.GetQAFun
 				if event.Type == "message_id" {
 					messageId = event.Content
 				}
+				if event.Type == "chunk_result" && event.ChunkResult != nil && kb != nil {
+					sources = append(sources, bot.Source{
+						Title: event.ChunkResult.Name,
+						URL:   fmt.Sprintf("%s/node/%s", baseURL(event.ChunkResult.KBID), event.ChunkResult.NodeID),
+					})
+				}
+			}
+			if sourcesHook != nil && len(sources) > 0 {
+				sourcesHook(sources)
+			}
+			// bots with their own feedback controls take the message id instead of the links
+			if hook := bot.FeedbackHookFromContext(ctx); hook != nil {
+				if messageId != "" && (appinfo.Settings.AIFeedbackSettings.AIFeedbackIsEnabled == nil || *appinfo.Settings.AIFeedbackSettings.AIFeedbackIsEnabled) {
+					hook(messageId)
+				}
+				return
 			}
 			// check again
 			// contact --> send
@@ -381,6 +487,278 @@ func (u *AppUsecase) updateDisCordBot(app *domain.App) {
 	u.discordBots[app.ID] = discordBots
 }
 
+func (u *AppUsecase) updateSlackBot(app *domain.App) {
+	u.slackMutex.Lock()
+	defer u.slackMutex.Unlock()
+
+	if bot, exists := u.slackBots[app.ID]; exists {
+		if bot != nil {
+			bot.Stop()
+			delete(u.slackBots, app.ID)
+		}
+	}
+
+	settings := app.Settings.SlackBotSettings
+	if (settings.IsEnabled != nil && !*settings.IsEnabled) || settings.BotToken == "" {
+		return
+	}
+	// events api callbacks can not be verified without the signing secret
+	if settings.AppToken == "" && settings.SigningSecret == "" {
+		u.logger.Warn("slack bot requires an app token or a signing secret", log.String("app_id", app.ID))
+		return
+	}
+
+	botCtx, cancel := context.WithCancel(context.Background())
+	slackClient := slack.NewSlackClient(
+		botCtx,
+		cancel,
+		settings.BotToken,
+		settings.AppToken,
+		settings.SigningSecret,
+		u.logger,
+		u.getQAFunc(app.KBID, app.Type),
+		u.feedbackFunc(),
+	)
+
+	go func() {
+		u.logger.Info("slack bot is starting", log.String("app_id", app.ID))
+		if err := slackClient.Start(); err != nil {
+			u.logger.Error("failed to start slack client", log.Error(err))
+			cancel()
+		}
+	}()
+
+	u.slackBots[app.ID] = slackClient
+}
+
+func (u *AppUsecase) updateTelegramBot(app *domain.App) {
+	u.telegramMutex.Lock()
+	defer u.telegramMutex.Unlock()
+
+	if bot, exists := u.telegramBots[app.ID]; exists {
+		if bot != nil {
+			bot.Stop()
+			delete(u.telegramBots, app.ID)
+		}
+	}
+
+	settings := app.Settings.TelegramBotSettings
+	if (settings.IsEnabled != nil && !*settings.IsEnabled) || settings.BotToken == "" {
+		return
+	}
+	// webhook requests can not be verified without the secret token
+	if settings.WebhookURL != "" && settings.SecretToken == "" {
+		u.logger.Warn("telegram bot webhook mode requires a secret token", log.String("app_id", app.ID))
+		return
+	}
+
+	botCtx, cancel := context.WithCancel(context.Background())
+	telegramClient := telegram.NewTelegramClient(
+		botCtx,
+		cancel,
+		settings.BotToken,
+		settings.WebhookURL,
+		settings.SecretToken,
+		u.logger,
+		u.getQAFunc(app.KBID, app.Type),
+	)
+
+	go func() {
+		u.logger.Info("telegram bot is starting", log.String("app_id", app.ID))
+		if err := telegramClient.Start(); err != nil {
+			u.logger.Error("failed to start telegram client", log.Error(err))
+			cancel()
+		}
+	}()
+
+	u.telegramBots[app.ID] = telegramClient
+}
+
+// GetTelegramBotClient returns the Telegram bot client for a given app ID
+func (u *AppUsecase) GetTelegramBotClient(appID string) (*telegram.TelegramClient, bool) {
+	u.telegramMutex.RLock()
+	defer u.telegramMutex.RUnlock()
+	client, ok := u.telegramBots[appID]
+	return client, ok
+}
+
+func (u *AppUsecase) updateTeamsBot(app *domain.App) {
+	u.teamsMutex.Lock()
+	defer u.teamsMutex.Unlock()
+
+	if bot, exists := u.teamsBots[app.ID]; exists {
+		if bot != nil {
+			bot.Stop()
+			delete(u.teamsBots, app.ID)
+		}
+	}
+
+	settings := app.Settings.TeamsBotSettings
+	if (settings.IsEnabled != nil && !*settings.IsEnabled) || settings.AppID == "" || settings.AppPassword == "" {
+		return
+	}
+
+	// teams pushes activities to the messaging endpoint, there is no connection to start
+	botCtx, cancel := context.WithCancel(context.Background())
+	u.teamsBots[app.ID] = teams.NewTeamsClient(
+		botCtx,
+		cancel,
+		settings.AppID,
+		settings.AppPassword,
+		settings.TenantID,
+		u.logger,
+		u.getQAFunc(app.KBID, app.Type),
+		u.feedbackFunc(),
+	)
+	u.logger.Info("teams bot is ready", log.String("app_id", app.ID))
+}
+
+// GetTeamsBotClient returns the Teams bot client for a given app ID
+func (u *AppUsecase) GetTeamsBotClient(appID string) (*teams.TeamsClient, bool) {
+	u.teamsMutex.RLock()
+	defer u.teamsMutex.RUnlock()
+	client, ok := u.teamsBots[appID]
+	return client, ok
+}
+
+func (u *AppUsecase) updateMailBot(app *domain.App) {
+	u.mailMutex.Lock()
+	defer u.mailMutex.Unlock()
+
+	if bot, exists := u.mailBots[app.ID]; exists {
+		if bot != nil {
+			bot.Stop()
+			delete(u.mailBots, app.ID)
+		}
+	}
+
+	settings := app.Settings.MailBotSettings
+	if (settings.IsEnabled != nil && !*settings.IsEnabled) || settings.SMTP.Host == "" {
+		return
+	}
+	if settings.IMAP.Host == "" && settings.InboundToken == "" {
+		return
+	}
+
+	botCtx, cancel := context.WithCancel(context.Background())
+	mailClient := mail.NewMailClient(
+		botCtx,
+		cancel,
+		app.KBID,
+		app.ID,
+		settings,
+		u.logger,
+		u.getQAFunc(app.KBID, app.Type),
+		u.mailReplyRepo.Create,
+	)
+
+	go func() {
+		if err := mailClient.Start(); err != nil {
+			u.logger.Error("failed to start mail client", log.Error(err))
+			cancel()
+		}
+	}()
+
+	u.mailBots[app.ID] = mailClient
+}
+
+// GetMailBotClient returns the mail bot client for a given app ID
+func (u *AppUsecase) GetMailBotClient(appID string) (*mail.MailClient, bool) {
+	u.mailMutex.RLock()
+	defer u.mailMutex.RUnlock()
+	client, ok := u.mailBots[appID]
+	return client, ok
+}
+
+// GetFeishuBotClient returns the Feishu bot client for a given app ID
+func (u *AppUsecase) GetFeishuBotClient(appID string) (*feishu.FeishuClient, bool) {
+	u.feishuMutex.RLock()
+	defer u.feishuMutex.RUnlock()
+	client, ok := u.feishuBots[appID]
+	return client, ok
+}
+
+// GetDiscordBotClient returns the Discord bot client for a given app ID
+func (u *AppUsecase) GetDiscordBotClient(appID string) (*discord.DiscordClient, bool) {
+	u.discordMutex.RLock()
+	defer u.discordMutex.RUnlock()
+	client, ok := u.discordBots[appID]
+	return client, ok
+}
+
+// humanReplier returns the running bot client of the app if it can push human replies
+func (u *AppUsecase) humanReplier(app *domain.App) (bot.HumanReplier, bool) {
+	switch app.Type {
+	case domain.AppTypeFeishuBot:
+		if client, ok := u.GetFeishuBotClient(app.ID); ok {
+			return client, true
+		}
+	case domain.AppTypeLarkBot:
+		if client, ok := u.GetLarkBotClient(app.ID); ok {
+			return client, true
+		}
+	case domain.AppTypeDisCordBot:
+		if client, ok := u.GetDiscordBotClient(app.ID); ok {
+			return client, true
+		}
+	case domain.AppTypeSlackBot:
+		if client, ok := u.GetSlackBotClient(app.ID); ok {
+			return client, true
+		}
+	case domain.AppTypeTelegramBot:
+		if client, ok := u.GetTelegramBotClient(app.ID); ok {
+			return client, true
+		}
+	case domain.AppTypeTeamsBot:
+		if client, ok := u.GetTeamsBotClient(app.ID); ok {
+			return client, true
+		}
+	case domain.AppTypeMailBot:
+		if client, ok := u.GetMailBotClient(app.ID); ok {
+			return client, true
+		}
+	}
+	return nil, false
+}
+
+// PushHumanReply sends the reply of a human agent to the channel the conversation came from,
+// it reports false when the channel can not receive pushed messages, e.g. the web widget,
+// and fails when the bot that owns the conversation is not running
+func (u *AppUsecase) PushHumanReply(ctx context.Context, conversation *domain.Conversation, content string) (bool, error) {
+	if len(conversation.Info.ReplyRoute) == 0 {
+		return false, nil
+	}
+	app, err := u.repo.GetAppDetail(ctx, conversation.AppID)
+	if err != nil {
+		return false, err
+	}
+	replier, ok := u.humanReplier(app)
+	if !ok {
+		return false, fmt.Errorf("bot of app %s is not running", app.ID)
+	}
+	if err := replier.SendHumanReply(ctx, conversation.Info.ReplyRoute, content); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (u *AppUsecase) feedbackFunc() bot.FeedbackFun {
+	return func(ctx context.Context, messageID string, score domain.ScoreType) error {
+		return u.chatUsecase.conversationUsecase.FeedBack(ctx, &domain.FeedbackRequest{
+			MessageId: messageID,
+			Score:     score,
+		})
+	}
+}
+
+// GetSlackBotClient returns the Slack bot client for a given app ID
+func (u *AppUsecase) GetSlackBotClient(appID string) (*slack.SlackClient, bool) {
+	u.slackMutex.RLock()
+	defer u.slackMutex.RUnlock()
+	client, ok := u.slackBots[appID]
+	return client, ok
+}
+
 func (u *AppUsecase) DeleteApp(ctx context.Context, id, kbID string) error {
 	return u.repo.DeleteApp(ctx, id, kbID)
 }
@@ -453,6 +831,17 @@ func (u *AppUsecase) GetAppDetailByKBIDAndAppType(ctx context.Context, kbID stri
 		FeishuBotAppSecret: app.Settings.FeishuBotAppSecret,
 		// LarkBot
 		LarkBotSettings: app.Settings.LarkBotSettings,
+		// SlackBot
+		SlackBotSettings: app.Settings.SlackBotSettings,
+		// TelegramBot
+		TelegramBotSettings: app.Settings.TelegramBotSettings,
+		// TeamsBot
+		TeamsBotSettings: app.Settings.TeamsBotSettings,
+		// MailBot
+		MailBotSettings: app.Settings.MailBotSettings,
+		// bot conversation
+		BotConversationSettings: app.Settings.BotConversationSettings,
+		BotRouteSettings:        app.Settings.BotRouteSettings,
 		// WechatBot
 		WeChatAppIsEnabled:      app.Settings.WeChatAppIsEnabled,
 		WeChatAppToken:          app.Settings.WeChatAppToken,
@@ -705,6 +1094,38 @@ func (u *AppUsecase) handleBotAuths(ctx context.Context, id string, newSettings
 		}
 	}
 
+	// Handle Slack Bot
+	if currentApp.Settings.SlackBotSettings.IsEnabled != newSettings.SlackBotSettings.IsEnabled {
+		if err := u.handleBotAuth(ctx, currentApp.KBID, currentApp.ID, currentApp.Settings.SlackBotSettings.IsEnabled,
+			newSettings.SlackBotSettings.IsEnabled, consts.SourceTypeSlackBot); err != nil {
+			u.logger.Error("failed to handle slack bot auth", log.Error(err))
+		}
+	}
+
+	// Handle Telegram Bot
+	if currentApp.Settings.TelegramBotSettings.IsEnabled != newSettings.TelegramBotSettings.IsEnabled {
+		if err := u.handleBotAuth(ctx, currentApp.KBID, currentApp.ID, currentApp.Settings.TelegramBotSettings.IsEnabled,
+			newSettings.TelegramBotSettings.IsEnabled, consts.SourceTypeTelegramBot); err != nil {
+			u.logger.Error("failed to handle telegram bot auth", log.Error(err))
+		}
+	}
+
+	// Handle Teams Bot
+	if currentApp.Settings.TeamsBotSettings.IsEnabled != newSettings.TeamsBotSettings.IsEnabled {
+		if err := u.handleBotAuth(ctx, currentApp.KBID, currentApp.ID, currentApp.Settings.TeamsBotSettings.IsEnabled,
+			newSettings.TeamsBotSettings.IsEnabled, consts.SourceTypeTeamsBot); err != nil {
+			u.logger.Error("failed to handle teams bot auth", log.Error(err))
+		}
+	}
+
+	// Handle Mail Bot
+	if currentApp.Settings.MailBotSettings.IsEnabled != newSettings.MailBotSettings.IsEnabled {
+		if err := u.handleBotAuth(ctx, currentApp.KBID, currentApp.ID, currentApp.Settings.MailBotSettings.IsEnabled,
+			newSettings.MailBotSettings.IsEnabled, consts.SourceTypeMailBot); err != nil {
+			u.logger.Error("failed to handle mail bot auth", log.Error(err))
+		}
+	}
+
 	// Handle WeChat Bot
 	if currentApp.Settings.WeChatAppIsEnabled != newSettings.WeChatAppIsEnabled {
 		if err := u.handleBotAuth(ctx, currentApp.KBID, currentApp.ID, currentApp.Settings.WeChatAppIsEnabled,
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/chaitin/panda-wiki/api/audit/v1"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/jsondiff"
+	"github.com/chaitin/panda-wiki/repo/pg"
+)
+
+const (
+	auditLogBatchSize = 500
+	auditMaskedValue  = "******"
+)
+
+// auditSensitiveField 密码、密钥等字段只记录是否变化，不记录内容
+var auditSensitiveField = regexp.MustCompile(`^(code|.*(^|_)(password|passwd|secret|token|token_hash|api_key|apikey|private_key|access_key|secret_key|encrypt_key|aes_key|encodingaeskey|recovery_codes))$`)
+
+type AuditUsecase struct {
+	repo     *pg.AuditLogRepository
+	userRepo *pg.UserRepository
+	logger   *log.Logger
+}
+
+func NewAuditUsecase(repo *pg.AuditLogRepository, userRepo *pg.UserRepository, logger *log.Logger) *AuditUsecase {
+	return &AuditUsecase{
+		repo:     repo,
+		userRepo: userRepo,
+		logger:   logger.WithModule("usecase.audit_log"),
+	}
+}
+
+// Record 计算 before 到 after 的差异并追加到哈希链，敏感字段脱敏
+func (u *AuditUsecase) Record(ctx context.Context, entry *domain.AuditLog, before, after any) error {
+	changes, err := jsondiff.Diff(before, after)
+	if err != nil {
+		return fmt.Errorf("diff audit changes failed: %w", err)
+	}
+	entry.Changes = make(domain.AuditChanges, 0, len(changes))
+	for _, change := range changes {
+		if isAuditSensitive(change.Path) {
+			change.Before = maskAuditValue(change.Before)
+			change.After = maskAuditValue(change.After)
+		}
+		entry.Changes = append(entry.Changes, domain.AuditChange{
+			Path:   change.Path,
+			Before: change.Before,
+			After:  change.After,
+		})
+	}
+	if entry.ActorName == "" && entry.ActorID != "" {
+		if user, err := u.userRepo.GetUser(ctx, entry.ActorID); err == nil {
+			entry.ActorName = user.Account
+		}
+	}
+	return u.repo.AppendAuditLog(ctx, entry)
+}
+
+func isAuditSensitive(path string) bool {
+	field := path[strings.LastIndex(path, ".")+1:]
+	return auditSensitiveField.MatchString(strings.ToLower(field))
+}
+
+func maskAuditValue(v any) any {
+	if v == nil || v == "" {
+		return v
+	}
+	return auditMaskedValue
+}
+
+func (u *AuditUsecase) ListAuditLogs(ctx context.Context, req *v1.AuditLogListReq) (*v1.AuditLogListResp, error) {
+	logs, total, err := u.repo.ListAuditLogs(ctx, req.Filter(), req.Pager)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPaginatedResult(logs, total), nil
+}
+
+// ExportAuditLogs 按 ID 升序导出，jsonl 每行一条记录，csv 中 changes 为 JSON 字符串
+func (u *AuditUsecase) ExportAuditLogs(ctx context.Context, req *v1.AuditLogExportReq, w io.Writer) error {
+	if req.Format == "jsonl" {
+		bw := bufio.NewWriter(w)
+		encoder := json.NewEncoder(bw)
+		if err := u.repo.IterateAuditLogs(ctx, req.Filter(), auditLogBatchSize, func(logs []domain.AuditLog) error {
+			for i := range logs {
+				if err := encoder.Encode(&logs[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		return bw.Flush()
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{
+		"id", "created_at", "actor_type", "actor_id", "actor_name", "kb_id", "action",
+		"target_type", "target_id", "changes", "ip", "user_agent", "prev_hash", "hash",
+	}); err != nil {
+		return err
+	}
+	if err := u.repo.IterateAuditLogs(ctx, req.Filter(), auditLogBatchSize, func(logs []domain.AuditLog) error {
+		for _, l := range logs {
+			changes, err := json.Marshal(l.Changes)
+			if err != nil {
+				return err
+			}
+			if err := cw.Write([]string{
+				strconv.FormatInt(l.ID, 10), l.CreatedAt.Format(time.RFC3339Nano), string(l.ActorType),
+				l.ActorID, l.ActorName, l.KBID, l.Action, l.TargetType, l.TargetID, string(changes),
+				l.IP, l.UserAgent, l.PrevHash, l.Hash,
+			}); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	}); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// VerifyAuditLogs 从最早的记录开始逐条校验 hash 和链接，清理后第一条的 PrevHash 作为起点
+func (u *AuditUsecase) VerifyAuditLogs(ctx context.Context) (*v1.AuditLogVerifyResp, error) {
+	resp := &v1.AuditLogVerifyResp{Valid: true}
+	prevHash := ""
+	errBroken := errors.New("audit log chain broken")
+	err := u.repo.IterateAuditLogs(ctx, nil, auditLogBatchSize, func(logs []domain.AuditLog) error {
+		for i := range logs {
+			l := &logs[i]
+			if resp.Checked > 0 && l.PrevHash != prevHash {
+				resp.Valid, resp.BrokenID, resp.Reason = false, l.ID, "prev_hash does not match the previous entry"
+				return errBroken
+			}
+			hash, err := l.ComputeHash()
+			if err != nil {
+				return err
+			}
+			if hash != l.Hash {
+				resp.Valid, resp.BrokenID, resp.Reason = false, l.ID, "hash does not match the entry content"
+				return errBroken
+			}
+			prevHash = l.Hash
+			resp.Checked++
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBroken) {
+		return nil, err
+	}
+	if !resp.Valid {
+		u.logger.Warn("audit log verification failed", log.Int64("id", resp.BrokenID), log.String("reason", resp.Reason))
+	}
+	return resp, nil
+}
+
+func (u *AuditUsecase) GetSetting(ctx context.Context) (*domain.AuditLogSetting, error) {
+	return u.repo.GetAuditLogSetting(ctx)
+}
+
+func (u *AuditUsecase) UpdateSetting(ctx context.Context, req *v1.AuditLogSettingReq) error {
+	return u.repo.UpsertAuditLogSetting(ctx, &domain.AuditLogSetting{
+		RetentionDays: req.RetentionDays,
+	})
+}
+
+// PruneAuditLogs 删除超过保留天数的记录，并把清理本身记入审计日志
+func (u *AuditUsecase) PruneAuditLogs(ctx context.Context) error {
+	setting, err := u.repo.GetAuditLogSetting(ctx)
+	if err != nil {
+		return err
+	}
+	if setting.RetentionDays <= 0 {
+		return nil
+	}
+	before := time.Now().AddDate(0, 0, -setting.RetentionDays)
+	deleted, err := u.repo.DeleteAuditLogsBefore(ctx, before)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return nil
+	}
+	u.logger.Info("pruned audit logs", log.Int64("deleted", deleted), log.Int("retention_days", setting.RetentionDays))
+	return u.Record(ctx, &domain.AuditLog{
+		ActorType:  domain.AuditActorSystem,
+		Action:     "audit_log.prune",
+		TargetType: "audit_log",
+	}, nil, map[string]any{
+		"deleted":        deleted,
+		"before":         before.UTC().Format(time.RFC3339),
+		"retention_days": setting.RetentionDays,
+	})
+}
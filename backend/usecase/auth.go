@@ -12,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/gorilla/sessions"
 	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 
 	v1 "github.com/chaitin/panda-wiki/api/auth/v1"
@@ -43,6 +44,8 @@ type StateInfo struct {
 	KbId        string `json:"kb_id"`
 	RedirectUrl string `json:"redirect_url"`
 	Verifier    string `json:"verifier"`
+	Nonce       string `json:"nonce,omitempty"`
+	RequestID   string `json:"request_id,omitempty"`
 }
 
 func (u *AuthUsecase) GetAuthBySourceType(ctx context.Context, sourceType consts.SourceType) (*domain.Auth, error) {
@@ -50,18 +53,42 @@ func (u *AuthUsecase) GetAuthBySourceType(ctx context.Context, sourceType consts
 }
 
 func (u *AuthUsecase) DeleteAuth(ctx context.Context, req v1.AuthDeleteReq) error {
-	return u.AuthRepo.DeleteAuth(ctx, req.KbID, req.ID)
+	if err := u.AuthRepo.DeleteAuth(ctx, req.KbID, req.ID); err != nil {
+		return err
+	}
+	return u.RevokeShareSessions(ctx, uint(req.ID))
 }
 
 func (u *AuthUsecase) SetAuth(ctx context.Context, req v1.AuthSetReq) error {
+	setting := domain.AuthSetting{
+		ClientID:     req.ClientID,
+		ClientSecret: req.ClientSecret,
+		Proxy:        req.Proxy,
+		GroupSync:    req.GroupSync,
+	}
+	if req.GroupSync && !slices.Contains(consts.GroupSyncSourceTypes, req.SourceType) {
+		return fmt.Errorf("group sync is not supported for %s", req.SourceType)
+	}
+	switch req.SourceType {
+	case consts.SourceTypeLDAP:
+		setting.LDAP = req.LDAP
+	case consts.SourceTypeCAS:
+		setting.CAS = req.CAS
+	case consts.SourceTypeOAuth:
+		setting.OAuth = req.OAuth
+	case consts.SourceTypeSAML:
+		if err := u.prepareSAMLKeyPair(ctx, req.KBID, req.SAML); err != nil {
+			return err
+		}
+		setting.SAML = req.SAML
+	}
+	if err := u.checkAuthSetting(ctx, req.KBID, req.SourceType, setting); err != nil {
+		return err
+	}
 	if err := u.AuthRepo.CreateAuthConfig(ctx, &domain.AuthConfig{
-		AuthSetting: domain.AuthSetting{
-			ClientID:     req.ClientID,
-			ClientSecret: req.ClientSecret,
-			Proxy:        req.Proxy,
-		},
-		KbID:       req.KBID,
-		SourceType: req.SourceType,
+		AuthSetting: setting,
+		KbID:        req.KBID,
+		SourceType:  req.SourceType,
 	}); err != nil {
 		return err
 	}
@@ -78,6 +105,18 @@ func (u *AuthUsecase) GetAuthInfo(ctx context.Context, kbId string, authId uint)
 	return auth, nil
 }
 
+// GetAuthConfig 未配置时返回 nil
+func (u *AuthUsecase) GetAuthConfig(ctx context.Context, kbID string, sourceType consts.SourceType) (*domain.AuthConfig, error) {
+	authConfig, err := u.AuthRepo.GetAuthConfig(ctx, kbID, sourceType)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return authConfig, nil
+}
+
 func (u *AuthUsecase) GetAuth(ctx context.Context, kbID string, sourceType consts.SourceType) (*v1.AuthGetResp, error) {
 	authConfig, err := u.AuthRepo.GetAuthConfig(ctx, kbID, sourceType)
 	if err != nil {
@@ -102,14 +141,27 @@ func (u *AuthUsecase) GetAuth(ctx context.Context, kbID string, sourceType const
 			SourceType:    auth.SourceType,
 			LastLoginTime: auth.LastLoginTime,
 			CreatedAt:     auth.CreatedAt,
+			Active:        auth.Active,
 		})
 	}
 
+	// SP 私钥不下发，保存时留空即沿用
+	if samlSetting := authConfig.AuthSetting.SAML; samlSetting != nil {
+		saml := *samlSetting
+		saml.SPPrivateKey = ""
+		authConfig.AuthSetting.SAML = &saml
+	}
+
 	resp := &v1.AuthGetResp{
 		ClientID:     authConfig.AuthSetting.ClientID,
 		ClientSecret: authConfig.AuthSetting.ClientSecret,
 		SourceType:   authConfig.SourceType,
 		Proxy:        authConfig.AuthSetting.Proxy,
+		LDAP:         authConfig.AuthSetting.LDAP,
+		CAS:          authConfig.AuthSetting.CAS,
+		OAuth:        authConfig.AuthSetting.OAuth,
+		SAML:         authConfig.AuthSetting.SAML,
+		GroupSync:    authConfig.AuthSetting.GroupSync,
 		Auths:        as,
 	}
 	return resp, nil
@@ -121,7 +173,10 @@ func (u *AuthUsecase) ValidateRedirectUrl(ctx context.Context, kbId, redirectUrl
 	if err != nil {
 		return false, err
 	}
-	redirectURL, _ := url.Parse(redirectUrl)
+	redirectURL, err := url.Parse(redirectUrl)
+	if err != nil {
+		return false, nil
+	}
 
 	if kb.AccessSettings.BaseURL != "" {
 		baseUrl, _ := url.Parse(kb.AccessSettings.BaseURL)
@@ -137,21 +192,70 @@ func (u *AuthUsecase) ValidateRedirectUrl(ctx context.Context, kbId, redirectUrl
 	return true, nil
 }
 
-func (u *AuthUsecase) genState(ctx context.Context, stateInfo StateInfo) (string, error) {
-	state := uuid.New().String()
+// checkAuthSetting 保存前连接 LDAP 服务器、读取 OIDC 发现文档及 IdP 元数据，尽早暴露配置错误
+func (u *AuthUsecase) checkAuthSetting(ctx context.Context, kbID string, sourceType consts.SourceType, setting domain.AuthSetting) error {
+	switch sourceType {
+	case consts.SourceTypeLDAP:
+		if setting.GroupSync && setting.LDAP.GroupBaseDN == "" {
+			return fmt.Errorf("group base DN is required for group sync")
+		}
+		client, err := u.newLDAPClient(ctx, setting)
+		if err != nil {
+			return err
+		}
+		return client.TestConnection()
+	case consts.SourceTypeCAS:
+		_, err := u.newCASClient(ctx, setting, "")
+		return err
+	case consts.SourceTypeOAuth:
+		kb, err := u.kbRepo.GetKnowledgeBaseByID(ctx, kbID)
+		if err != nil {
+			return err
+		}
+		_, err = u.newOAuthClient(ctx, setting, kb.AccessSettings.BaseURL)
+		return err
+	case consts.SourceTypeSAML:
+		kb, err := u.kbRepo.GetKnowledgeBaseByID(ctx, kbID)
+		if err != nil {
+			return err
+		}
+		_, err = u.newSAMLClient(ctx, kb, setting, "")
+		return err
+	}
+	return nil
+}
 
-	stateInfoBytes, err := json.Marshal(stateInfo)
+// checkEnterpriseAuth 确认知识库开启了企业认证且认证来源为 sourceType
+func (u *AuthUsecase) checkEnterpriseAuth(ctx context.Context, kbID string, sourceType consts.SourceType) error {
+	kb, err := u.kbRepo.GetKnowledgeBaseByID(ctx, kbID)
 	if err != nil {
-		return "", err
+		return err
+	}
+	if !kb.AccessSettings.EnterpriseAuth.Enabled || kb.AccessSettings.SourceType != sourceType {
+		return fmt.Errorf("%s auth is not enabled", sourceType)
 	}
+	return nil
+}
 
-	if err := u.cache.SetNX(ctx, state, stateInfoBytes, 15*time.Minute).Err(); err != nil {
+func (u *AuthUsecase) genState(ctx context.Context, stateInfo StateInfo) (string, error) {
+	state := uuid.New().String()
+
+	if err := u.saveState(ctx, state, stateInfo); err != nil {
 		return "", err
 	}
 
 	return state, nil
 }
 
+func (u *AuthUsecase) saveState(ctx context.Context, state string, stateInfo StateInfo) error {
+	stateInfoBytes, err := json.Marshal(stateInfo)
+	if err != nil {
+		return err
+	}
+
+	return u.cache.SetNX(ctx, state, stateInfoBytes, 15*time.Minute).Err()
+}
+
 func (u *AuthUsecase) SaveNewSession(c echo.Context, auth *domain.Auth) error {
 	s := c.Get(domain.SessionCacheKey)
 	if s == nil {
@@ -164,12 +268,13 @@ func (u *AuthUsecase) SaveNewSession(c echo.Context, auth *domain.Auth) error {
 
 	newSess.Options = &sessions.Options{
 		Path:     "/",
-		MaxAge:   86400 * 30,
+		MaxAge:   int(shareSessionMaxAge.Seconds()),
 		HttpOnly: true,
 	}
 
 	newSess.Values["user_id"] = auth.ID
 	newSess.Values["kb_id"] = auth.KBID
+	newSess.Values["login_at"] = time.Now().UnixNano()
 
 	if err := newSess.Save(c.Request(), c.Response()); err != nil {
 		return err
@@ -178,3 +283,27 @@ func (u *AuthUsecase) SaveNewSession(c echo.Context, auth *domain.Auth) error {
 	c.Logger().Info("session_saved:", newSess.Values)
 	return nil
 }
+
+// shareSessionMaxAge 读者会话有效期
+const shareSessionMaxAge = 30 * 24 * time.Hour
+
+func shareSessionRevokedKey(authID uint) string {
+	return fmt.Sprintf("share-session-revoked:%d", authID)
+}
+
+// RevokeShareSessions 使读者此前登录的会话全部失效，记录保留到这些会话过期为止
+func (u *AuthUsecase) RevokeShareSessions(ctx context.Context, authID uint) error {
+	return u.cache.Set(ctx, shareSessionRevokedKey(authID), time.Now().UnixNano(), shareSessionMaxAge).Err()
+}
+
+// IsShareSessionRevoked 会话登录时间早于吊销时间即失效，没有登录时间的旧会话视为早于吊销时间
+func (u *AuthUsecase) IsShareSessionRevoked(ctx context.Context, authID uint, loginAt int64) (bool, error) {
+	revokedAt, err := u.cache.Get(ctx, shareSessionRevokedKey(authID)).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return false, nil
+		}
+		return false, err
+	}
+	return loginAt <= revokedAt, nil
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	shareV1 "github.com/chaitin/panda-wiki/api/share/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/pkg/cas"
+)
+
+func (u *AuthUsecase) newCASClient(ctx context.Context, setting domain.AuthSetting, serviceURL string) (*cas.Client, error) {
+	if setting.CAS == nil {
+		return nil, fmt.Errorf("cas is not configured")
+	}
+	return cas.NewClient(ctx, u.logger, cas.Config{
+		ServerURL:    setting.CAS.ServerURL,
+		ServiceURL:   serviceURL,
+		LoginPath:    setting.CAS.LoginPath,
+		ValidatePath: setting.CAS.ValidatePath,
+		Version:      setting.CAS.Version,
+	})
+}
+
+func (u *AuthUsecase) getCASClient(ctx context.Context, kbId, serviceURL string) (*cas.Client, error) {
+	authConfig, err := u.AuthRepo.GetAuthConfig(ctx, kbId, consts.SourceTypeCAS)
+	if err != nil {
+		return nil, err
+	}
+	return u.newCASClient(ctx, authConfig.AuthSetting, serviceURL)
+}
+
+func (u *AuthUsecase) GenerateCASAuthUrl(ctx context.Context, req shareV1.AuthCASReq) (string, error) {
+	if err := u.checkEnterpriseAuth(ctx, req.KbID, consts.SourceTypeCAS); err != nil {
+		return "", err
+	}
+
+	state, err := u.genState(ctx, StateInfo{
+		KbId:        req.KbID,
+		RedirectUrl: req.RedirectUrl,
+	})
+	if err != nil {
+		return "", fmt.Errorf("gen state failed: %w", err)
+	}
+
+	casClient, err := u.getCASClient(ctx, req.KbID, req.RedirectUrl)
+	if err != nil {
+		return "", fmt.Errorf("get casClient failed: %w", err)
+	}
+
+	return casClient.GetLoginURL(state), nil
+}
+
+func (u *AuthUsecase) CASCallback(ctx context.Context, req shareV1.CASCallbackReq) (*domain.Auth, string, error) {
+	statInfo, err := u.getStateInfo(ctx, req.State)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// service 须与登录时一致，同样由 redirect url 生成
+	casClient, err := u.getCASClient(ctx, statInfo.KbId, statInfo.RedirectUrl)
+	if err != nil {
+		return nil, "", err
+	}
+
+	userInfo, err := casClient.ValidateTicket(req.Ticket, req.State)
+	if err != nil {
+		return nil, "", err
+	}
+
+	auth := &domain.Auth{
+		UserInfo: domain.AuthUserInfo{
+			Username:  userInfo.Attributes["name"],
+			AvatarUrl: userInfo.Attributes["avatar_url"],
+			Email:     userInfo.Attributes["email"],
+		},
+		KBID:       statInfo.KbId,
+		UnionID:    userInfo.Username,
+		SourceType: consts.SourceTypeCAS,
+	}
+
+	auth, err = u.AuthRepo.GetOrCreateAuth(ctx, auth, consts.SourceTypeCAS)
+	if err != nil {
+		return nil, "", fmt.Errorf("create auth failed: %w", err)
+	}
+
+	return auth, statInfo.RedirectUrl, nil
+}
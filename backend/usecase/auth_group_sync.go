@@ -0,0 +1,332 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	v1 "github.com/chaitin/panda-wiki/api/auth/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/dingtalk"
+	"github.com/chaitin/panda-wiki/pkg/dirsync"
+	"github.com/chaitin/panda-wiki/pkg/feishu"
+	"github.com/chaitin/panda-wiki/pkg/wecom"
+	"github.com/chaitin/panda-wiki/repo/mq"
+	"github.com/chaitin/panda-wiki/repo/pg"
+	"github.com/chaitin/panda-wiki/store/cache"
+)
+
+const (
+	// authGroupSyncLockTTL 同一知识库同一来源同时只运行一次同步
+	authGroupSyncLockTTL = 30 * time.Minute
+	// authGroupSyncRunRetention 运行报告保留时长
+	authGroupSyncRunRetention = 30 * 24 * time.Hour
+)
+
+var ErrAuthGroupSyncRunning = errors.New("group sync is already running")
+
+// AuthGroupSyncUsecase 从 LDAP、钉钉、飞书、企业微信同步部门/用户组及成员到读者用户组。
+// 用户组按 sync_id 对应目录中的部门，成员按读者认证的 union_id 对应目录用户，尚未登录过的用户在登录后的下次同步中加入。
+// 同步而来的用户组的名称、上级及成员均以目录为准，手动调整会在下次同步时被覆盖
+type AuthGroupSyncUsecase struct {
+	authUsecase *AuthUsecase
+	authRepo    *pg.AuthRepo
+	nodeRepo    *pg.NodeRepository
+	ragRepo     *mq.RAGRepository
+	cache       *cache.Cache
+	logger      *log.Logger
+}
+
+func NewAuthGroupSyncUsecase(
+	authUsecase *AuthUsecase,
+	authRepo *pg.AuthRepo,
+	nodeRepo *pg.NodeRepository,
+	ragRepo *mq.RAGRepository,
+	cache *cache.Cache,
+	logger *log.Logger,
+) *AuthGroupSyncUsecase {
+	return &AuthGroupSyncUsecase{
+		authUsecase: authUsecase,
+		authRepo:    authRepo,
+		nodeRepo:    nodeRepo,
+		ragRepo:     ragRepo,
+		cache:       cache,
+		logger:      logger.WithModule("usecase.auth_group_sync"),
+	}
+}
+
+// Sync 读取目录并同步用户组，试运行只计算差异。每次运行都保存运行报告，失败原因记录在报告中
+func (u *AuthGroupSyncUsecase) Sync(ctx context.Context, kbID string, sourceType consts.SourceType, dryRun bool, trigger consts.AuthGroupSyncTrigger) (*domain.AuthGroupSyncRun, error) {
+	lockKey := fmt.Sprintf("auth-group-sync:%s:%s", kbID, sourceType)
+	locked, err := u.cache.SetNX(ctx, lockKey, 1, authGroupSyncLockTTL).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, ErrAuthGroupSyncRunning
+	}
+	defer u.cache.Del(context.Background(), lockKey)
+
+	run := &domain.AuthGroupSyncRun{
+		KBID:       kbID,
+		SourceType: sourceType,
+		DryRun:     dryRun,
+		Trigger:    trigger,
+		Status:     consts.AuthGroupSyncStatusSuccess,
+		StartedAt:  time.Now(),
+	}
+	diff, err := u.sync(ctx, kbID, sourceType, dryRun)
+	if diff != nil {
+		run.Diff = *diff
+	}
+	if err != nil {
+		u.logger.Error("sync auth groups failed", log.String("kb_id", kbID), log.String("source_type", string(sourceType)), log.Error(err))
+		run.Status = consts.AuthGroupSyncStatusFailed
+		run.Error = err.Error()
+	}
+	run.FinishedAt = time.Now()
+
+	if err := u.authRepo.CreateAuthGroupSyncRun(ctx, run); err != nil {
+		return nil, fmt.Errorf("save sync run failed: %w", err)
+	}
+	return run, nil
+}
+
+func (u *AuthGroupSyncUsecase) sync(ctx context.Context, kbID string, sourceType consts.SourceType, dryRun bool) (*domain.AuthGroupSyncDiff, error) {
+	authConfig, err := u.authRepo.GetAuthConfig(ctx, kbID, sourceType)
+	if err != nil {
+		return nil, fmt.Errorf("get auth config failed: %w", err)
+	}
+	groups, err := u.readDirectory(ctx, sourceType, authConfig.AuthSetting)
+	if err != nil {
+		return nil, fmt.Errorf("read directory failed: %w", err)
+	}
+	existing, err := u.authRepo.GetSyncedAuthGroups(ctx, kbID, sourceType)
+	if err != nil {
+		return nil, err
+	}
+	// 目录为空多半是配置或接口权限问题，不据此删除全部用户组
+	if len(groups) == 0 && len(existing) > 0 {
+		return nil, errors.New("directory returned no groups, refusing to delete all synced groups")
+	}
+	members, err := u.authRepo.GetAuthIDsByUnionID(ctx, kbID, sourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := dirsync.Plan(existing, groups, members)
+	if dryRun || diff.Empty() {
+		return diff, nil
+	}
+
+	nodeIDs, err := u.authRepo.ApplyAuthGroupSync(ctx, kbID, sourceType, diff)
+	if err != nil {
+		return diff, fmt.Errorf("apply group changes failed: %w", err)
+	}
+	diff.UpdatedDocs, err = u.updateAnswerableGroups(ctx, kbID, nodeIDs)
+	return diff, err
+}
+
+// readDirectory 读取目录中的部门/用户组及直属成员
+func (u *AuthGroupSyncUsecase) readDirectory(ctx context.Context, sourceType consts.SourceType, setting domain.AuthSetting) ([]dirsync.Group, error) {
+	switch sourceType {
+	case consts.SourceTypeLDAP:
+		return u.readLDAP(ctx, setting)
+	case consts.SourceTypeDingTalk:
+		return u.readDingTalk(ctx, setting)
+	case consts.SourceTypeFeishu:
+		return u.readFeishu(ctx, setting)
+	case consts.SourceTypeWeCom:
+		return u.readWeCom(ctx, setting)
+	}
+	return nil, fmt.Errorf("group sync is not supported for %s", sourceType)
+}
+
+// readLDAP 用户组以 DN 标识，成员为用户 ID 属性，与 LDAP 登录一致
+func (u *AuthGroupSyncUsecase) readLDAP(ctx context.Context, setting domain.AuthSetting) ([]dirsync.Group, error) {
+	client, err := u.authUsecase.newLDAPClient(ctx, setting)
+	if err != nil {
+		return nil, err
+	}
+	ldapGroups, err := client.ListGroups()
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]dirsync.Group, 0, len(ldapGroups))
+	for _, g := range ldapGroups {
+		groups = append(groups, dirsync.Group{
+			ID:        g.DN,
+			ParentID:  g.ParentDN,
+			Name:      g.Name,
+			MemberIDs: g.MemberIDs,
+		})
+	}
+	return groups, nil
+}
+
+// readDingTalk 成员为 unionid，与钉钉登录一致
+func (u *AuthGroupSyncUsecase) readDingTalk(ctx context.Context, setting domain.AuthSetting) ([]dirsync.Group, error) {
+	client, err := dingtalk.NewDingTalkClient(ctx, u.logger, setting.ClientID, setting.ClientSecret, u.cache)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.GetDepartmentList()
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]dirsync.Group, 0, len(resp.Department))
+	for _, dept := range resp.Department {
+		users, err := client.GetAllUserList(dept.Id)
+		if err != nil {
+			return nil, fmt.Errorf("get users of department %d failed: %w", dept.Id, err)
+		}
+		group := dirsync.Group{
+			ID:   strconv.Itoa(dept.Id),
+			Name: dept.Name,
+		}
+		if dept.Parentid != 0 {
+			group.ParentID = strconv.Itoa(dept.Parentid)
+		}
+		for _, user := range users {
+			group.MemberIDs = append(group.MemberIDs, user.Unionid)
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// readFeishu 成员为 union_id，与飞书登录一致
+func (u *AuthGroupSyncUsecase) readFeishu(ctx context.Context, setting domain.AuthSetting) ([]dirsync.Group, error) {
+	client, err := feishu.NewClient(ctx, u.logger, setting.ClientID, setting.ClientSecret, "")
+	if err != nil {
+		return nil, err
+	}
+	token, err := client.GetTenantAccessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	departments, err := client.ListDepartments(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]dirsync.Group, 0, len(departments))
+	for _, dept := range departments {
+		users, err := client.ListDepartmentUsers(ctx, token, dept.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get users of department %s failed: %w", dept.ID, err)
+		}
+		group := dirsync.Group{
+			ID:   dept.ID,
+			Name: dept.Name,
+		}
+		if dept.ParentID != feishu.RootDepartmentID {
+			group.ParentID = dept.ParentID
+		}
+		for _, user := range users {
+			group.MemberIDs = append(group.MemberIDs, user.UnionID)
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// readWeCom client_id 为企业 ID，成员为 userid，与企业微信登录一致
+func (u *AuthGroupSyncUsecase) readWeCom(ctx context.Context, setting domain.AuthSetting) ([]dirsync.Group, error) {
+	client, err := wecom.NewClient(ctx, u.logger, setting.ClientID, setting.ClientSecret, "", "", u.cache)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.GetDepartmentList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]dirsync.Group, 0, len(resp.Department))
+	for _, dept := range resp.Department {
+		users, err := client.GetUserList(ctx, strconv.Itoa(dept.Id))
+		if err != nil {
+			return nil, fmt.Errorf("get users of department %d failed: %w", dept.Id, err)
+		}
+		group := dirsync.Group{
+			ID:   strconv.Itoa(dept.Id),
+			Name: dept.Name,
+		}
+		if dept.Parentid != 0 {
+			group.ParentID = strconv.Itoa(dept.Parentid)
+		}
+		for _, user := range users.Userlist {
+			group.MemberIDs = append(group.MemberIDs, user.Userid)
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// updateAnswerableGroups 用户组删除后，按文档剩余的可问答用户组更新 RAG 文档
+func (u *AuthGroupSyncUsecase) updateAnswerableGroups(ctx context.Context, kbID string, nodeIDs []string) (int, error) {
+	if len(nodeIDs) == 0 {
+		return 0, nil
+	}
+	nodeReleases, err := u.nodeRepo.GetLatestNodeReleaseByNodeIDs(ctx, kbID, nodeIDs)
+	if err != nil {
+		return 0, fmt.Errorf("get latest node release failed: %w", err)
+	}
+	requests := make([]*domain.NodeReleaseVectorRequest, 0, len(nodeReleases))
+	for _, nodeRelease := range nodeReleases {
+		if nodeRelease.DocID == "" {
+			continue
+		}
+		groupIds, err := u.nodeRepo.GetNodeAuthGroupIdsByNodeId(ctx, nodeRelease.NodeID, consts.NodePermNameAnswerable)
+		if err != nil {
+			return 0, err
+		}
+		requests = append(requests, &domain.NodeReleaseVectorRequest{
+			KBID:     kbID,
+			DocID:    nodeRelease.DocID,
+			Action:   "update_group_ids",
+			GroupIds: groupIds,
+		})
+	}
+	if len(requests) == 0 {
+		return 0, nil
+	}
+	if err := u.ragRepo.AsyncUpdateNodeReleaseVector(ctx, requests); err != nil {
+		return 0, err
+	}
+	return len(requests), nil
+}
+
+// SyncAll 定时同步所有开启了用户组同步的知识库，并清理过期的运行报告
+func (u *AuthGroupSyncUsecase) SyncAll(ctx context.Context) error {
+	authConfigs, err := u.authRepo.GetGroupSyncAuthConfigs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, authConfig := range authConfigs {
+		run, err := u.Sync(ctx, authConfig.KbID, authConfig.SourceType, false, consts.AuthGroupSyncTriggerCron)
+		if err != nil {
+			u.logger.Warn("skip auth group sync", log.String("kb_id", authConfig.KbID), log.String("source_type", string(authConfig.SourceType)), log.Error(err))
+			continue
+		}
+		u.logger.Info("auth group sync finished",
+			log.String("kb_id", authConfig.KbID),
+			log.String("source_type", string(authConfig.SourceType)),
+			log.String("status", string(run.Status)),
+			log.Int("created", len(run.Diff.CreateGroups)),
+			log.Int("deleted", len(run.Diff.DeleteGroups)),
+			log.Int("added_members", len(run.Diff.AddMembers)),
+			log.Int("removed_members", len(run.Diff.RemoveMembers)))
+	}
+	return u.authRepo.DeleteAuthGroupSyncRunsBefore(ctx, time.Now().Add(-authGroupSyncRunRetention))
+}
+
+func (u *AuthGroupSyncUsecase) ListRuns(ctx context.Context, req *v1.AuthGroupSyncRunListReq) (*v1.AuthGroupSyncRunListResp, error) {
+	runs, total, err := u.authRepo.GetAuthGroupSyncRuns(ctx, req.KBID, req.SourceType, req.Pager)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPaginatedResult(runs, total), nil
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	shareV1 "github.com/chaitin/panda-wiki/api/share/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/pkg/ldap"
+)
+
+func (u *AuthUsecase) newLDAPClient(ctx context.Context, setting domain.AuthSetting) (*ldap.Client, error) {
+	if setting.LDAP == nil {
+		return nil, fmt.Errorf("ldap is not configured")
+	}
+	return ldap.NewClient(ctx, u.logger, ldap.Config{
+		ServerURL:     setting.LDAP.ServerURL,
+		BindDN:        setting.LDAP.BindDN,
+		BindPassword:  setting.LDAP.BindPassword,
+		UserBaseDN:    setting.LDAP.UserBaseDN,
+		UserFilter:    setting.LDAP.UserFilter,
+		UserIDAttr:    setting.LDAP.UserIDAttr,
+		UserNameAttr:  setting.LDAP.UserNameAttr,
+		UserEmailAttr: setting.LDAP.UserEmailAttr,
+
+		GroupBaseDN:     setting.LDAP.GroupBaseDN,
+		GroupFilter:     setting.LDAP.GroupFilter,
+		GroupNameAttr:   setting.LDAP.GroupNameAttr,
+		GroupMemberAttr: setting.LDAP.GroupMemberAttr,
+	})
+}
+
+func (u *AuthUsecase) LDAPLogin(ctx context.Context, kbID string, req shareV1.AuthLDAPReq) (*domain.Auth, error) {
+	if err := u.checkEnterpriseAuth(ctx, kbID, consts.SourceTypeLDAP); err != nil {
+		return nil, err
+	}
+
+	authConfig, err := u.AuthRepo.GetAuthConfig(ctx, kbID, consts.SourceTypeLDAP)
+	if err != nil {
+		return nil, fmt.Errorf("get ldap config failed: %w", err)
+	}
+	ldapClient, err := u.newLDAPClient(ctx, authConfig.AuthSetting)
+	if err != nil {
+		return nil, err
+	}
+
+	userInfo, err := ldapClient.Authenticate(req.Username, req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	unionID := userInfo.ID
+	if unionID == "" {
+		unionID = userInfo.DN
+	}
+	auth := &domain.Auth{
+		UserInfo: domain.AuthUserInfo{
+			Username: userInfo.Username,
+			Email:    userInfo.Email,
+		},
+		KBID:       kbID,
+		UnionID:    unionID,
+		SourceType: consts.SourceTypeLDAP,
+	}
+
+	auth, err = u.AuthRepo.GetOrCreateAuth(ctx, auth, consts.SourceTypeLDAP)
+	if err != nil {
+		return nil, fmt.Errorf("create auth failed: %w", err)
+	}
+
+	return auth, nil
+}
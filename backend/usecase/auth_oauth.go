@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	shareV1 "github.com/chaitin/panda-wiki/api/share/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/pkg/oauth"
+)
+
+func (u *AuthUsecase) newOAuthClient(ctx context.Context, setting domain.AuthSetting, redirectURI string) (*oauth.Client, error) {
+	if setting.OAuth == nil {
+		return nil, fmt.Errorf("oauth is not configured")
+	}
+	return oauth.NewClient(ctx, u.logger, oauth.Config{
+		ClientID:     setting.ClientID,
+		ClientSecret: setting.ClientSecret,
+		RedirectURI:  redirectURI,
+		Scopes:       setting.OAuth.Scopes,
+		AuthorizeURL: setting.OAuth.AuthorizeURL,
+		TokenURL:     setting.OAuth.TokenURL,
+		UserInfoURL:  setting.OAuth.UserInfoURL,
+		IDField:      setting.OAuth.IDField,
+		NameField:    setting.OAuth.NameField,
+		AvatarField:  setting.OAuth.AvatarField,
+		EmailField:   setting.OAuth.EmailField,
+		Issuer:       setting.OAuth.Issuer,
+		JWKSURL:      setting.OAuth.JWKSURL,
+		Proxy:        setting.Proxy,
+	})
+}
+
+func (u *AuthUsecase) getOAuthClient(ctx context.Context, kbId, redirectURI string) (*oauth.Client, *domain.OAuthAuthSetting, error) {
+	authConfig, err := u.AuthRepo.GetAuthConfig(ctx, kbId, consts.SourceTypeOAuth)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := u.newOAuthClient(ctx, authConfig.AuthSetting, redirectURI)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, authConfig.AuthSetting.OAuth, nil
+}
+
+func (u *AuthUsecase) GenerateOAuthAuthUrl(ctx context.Context, req shareV1.AuthOAuthReq) (string, error) {
+	if err := u.checkEnterpriseAuth(ctx, req.KbID, consts.SourceTypeOAuth); err != nil {
+		return "", err
+	}
+
+	oauthClient, setting, err := u.getOAuthClient(ctx, req.KbID, req.RedirectUrl)
+	if err != nil {
+		return "", fmt.Errorf("get oauthClient failed: %w", err)
+	}
+
+	stateInfo := StateInfo{
+		KbId:        req.KbID,
+		RedirectUrl: req.RedirectUrl,
+	}
+	if setting.Issuer != "" {
+		stateInfo.Nonce = uuid.New().String()
+	}
+	state, err := u.genState(ctx, stateInfo)
+	if err != nil {
+		return "", fmt.Errorf("gen state failed: %w", err)
+	}
+
+	if setting.Issuer != "" {
+		return oauthClient.GetAuthorizeURLWithNonce(state, stateInfo.Nonce), nil
+	}
+	return oauthClient.GetAuthorizeURL(state), nil
+}
+
+func (u *AuthUsecase) OAuthCallback(ctx context.Context, req shareV1.OAuthCallbackReq) (*domain.Auth, string, error) {
+	statInfo, err := u.getStateInfo(ctx, req.State)
+	if err != nil {
+		return nil, "", err
+	}
+
+	oauthClient, setting, err := u.getOAuthClient(ctx, statInfo.KbId, statInfo.RedirectUrl)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var userInfo *oauth.UserInfo
+	if setting.Issuer != "" {
+		userInfo, err = oauthClient.GetOIDCUserInfo(req.Code, statInfo.Nonce)
+	} else {
+		userInfo, err = oauthClient.GetUserInfo(req.Code)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if userInfo.ID == "" {
+		return nil, "", fmt.Errorf("user id not found in oauth user info")
+	}
+
+	auth := &domain.Auth{
+		UserInfo: domain.AuthUserInfo{
+			Username:  userInfo.Name,
+			AvatarUrl: userInfo.AvatarUrl,
+			Email:     userInfo.Email,
+		},
+		KBID:       statInfo.KbId,
+		UnionID:    userInfo.ID,
+		SourceType: consts.SourceTypeOAuth,
+	}
+
+	auth, err = u.AuthRepo.GetOrCreateAuth(ctx, auth, consts.SourceTypeOAuth)
+	if err != nil {
+		return nil, "", fmt.Errorf("create auth failed: %w", err)
+	}
+
+	return auth, statInfo.RedirectUrl, nil
+}
@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	shareV1 "github.com/chaitin/panda-wiki/api/share/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/saml"
+)
+
+// prepareSAMLKeyPair keeps the SP key pair already registered at the IdP when the request leaves it
+// empty, and generates one for a new configuration
+func (u *AuthUsecase) prepareSAMLKeyPair(ctx context.Context, kbID string, setting *domain.SAMLAuthSetting) error {
+	if setting == nil || setting.SPCertificate != "" && setting.SPPrivateKey != "" {
+		return nil
+	}
+	authConfig, err := u.AuthRepo.GetAuthConfig(ctx, kbID, consts.SourceTypeSAML)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if authConfig != nil && authConfig.AuthSetting.SAML != nil && authConfig.AuthSetting.SAML.SPPrivateKey != "" &&
+		(setting.SPCertificate == "" || setting.SPCertificate == authConfig.AuthSetting.SAML.SPCertificate) {
+		setting.SPCertificate = authConfig.AuthSetting.SAML.SPCertificate
+		setting.SPPrivateKey = authConfig.AuthSetting.SAML.SPPrivateKey
+		return nil
+	}
+	if setting.SPCertificate != "" {
+		return fmt.Errorf("sp private key is required with the sp certificate")
+	}
+	setting.SPCertificate, setting.SPPrivateKey, err = saml.GenerateKeyPair("PandaWiki " + kbID)
+	return err
+}
+
+// samlBaseURL is the address the IdP reaches the SP at, the base url of the kb or else the
+// origin of the request
+func samlBaseURL(kb *domain.KnowledgeBase, origin string) string {
+	if kb.AccessSettings.BaseURL != "" {
+		return kb.AccessSettings.BaseURL
+	}
+	if origin != "" {
+		return origin
+	}
+	if len(kb.AccessSettings.Hosts) > 0 {
+		return "http://" + kb.AccessSettings.Hosts[0]
+	}
+	return ""
+}
+
+func (u *AuthUsecase) newSAMLClient(ctx context.Context, kb *domain.KnowledgeBase, setting domain.AuthSetting, origin string) (*saml.Client, error) {
+	if setting.SAML == nil {
+		return nil, fmt.Errorf("saml is not configured")
+	}
+	return saml.NewClient(ctx, u.logger, saml.Config{
+		BaseURL:           samlBaseURL(kb, origin),
+		KBID:              kb.ID,
+		EntityID:          setting.SAML.EntityID,
+		IdPMetadataURL:    setting.SAML.IdPMetadataURL,
+		IdPMetadataXML:    setting.SAML.IdPMetadataXML,
+		Certificate:       setting.SAML.SPCertificate,
+		PrivateKey:        setting.SAML.SPPrivateKey,
+		SignRequest:       setting.SAML.SignRequest,
+		AllowIDPInitiated: setting.SAML.AllowIDPInitiated,
+		UsernameAttr:      setting.SAML.UsernameAttr,
+		EmailAttr:         setting.SAML.EmailAttr,
+		AvatarAttr:        setting.SAML.AvatarAttr,
+		GroupsAttr:        setting.SAML.GroupsAttr,
+	})
+}
+
+func (u *AuthUsecase) getSAMLClient(ctx context.Context, kbID, origin string) (*saml.Client, *domain.SAMLAuthSetting, error) {
+	kb, err := u.kbRepo.GetKnowledgeBaseByID(ctx, kbID)
+	if err != nil {
+		return nil, nil, err
+	}
+	authConfig, err := u.AuthRepo.GetAuthConfig(ctx, kbID, consts.SourceTypeSAML)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := u.newSAMLClient(ctx, kb, authConfig.AuthSetting, origin)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, authConfig.AuthSetting.SAML, nil
+}
+
+// SAMLMetadata SP 元数据，供 IdP 注册
+func (u *AuthUsecase) SAMLMetadata(ctx context.Context, kbID, origin string) ([]byte, error) {
+	client, _, err := u.getSAMLClient(ctx, kbID, origin)
+	if err != nil {
+		return nil, err
+	}
+	return client.Metadata()
+}
+
+// GenerateSAMLAuthUrl SP 发起登录，请求 ID 随 state 保存，RelayState 即 state
+func (u *AuthUsecase) GenerateSAMLAuthUrl(ctx context.Context, req shareV1.AuthSAMLReq) (string, error) {
+	if err := u.checkEnterpriseAuth(ctx, req.KbID, consts.SourceTypeSAML); err != nil {
+		return "", err
+	}
+
+	origin := ""
+	if redirectURL, err := url.Parse(req.RedirectUrl); err == nil && redirectURL.Host != "" {
+		origin = redirectURL.Scheme + "://" + redirectURL.Host
+	}
+	client, _, err := u.getSAMLClient(ctx, req.KbID, origin)
+	if err != nil {
+		return "", fmt.Errorf("get samlClient failed: %w", err)
+	}
+
+	state := uuid.New().String()
+	loginURL, requestID, err := client.GetLoginURL(state)
+	if err != nil {
+		return "", fmt.Errorf("make saml request failed: %w", err)
+	}
+	if err := u.saveState(ctx, state, StateInfo{
+		KbId:        req.KbID,
+		RedirectUrl: req.RedirectUrl,
+		RequestID:   requestID,
+	}); err != nil {
+		return "", fmt.Errorf("save state failed: %w", err)
+	}
+
+	return loginURL, nil
+}
+
+// SAMLACS 校验 IdP 提交的响应并登录。RelayState 对应 SP 发起时保存的 state，
+// 否则按 IdP 发起处理，RelayState 为本知识库地址时跳转过去
+func (u *AuthUsecase) SAMLACS(ctx context.Context, kbID, origin string, req shareV1.SAMLACSReq) (*domain.Auth, string, error) {
+	if err := u.checkEnterpriseAuth(ctx, kbID, consts.SourceTypeSAML); err != nil {
+		return nil, "", err
+	}
+
+	var requestID, redirectUrl string
+	if req.RelayState != "" {
+		if stateInfo, err := u.getStateInfo(ctx, req.RelayState); err == nil && stateInfo.KbId == kbID && stateInfo.RequestID != "" {
+			// state 只能使用一次
+			u.cache.Del(ctx, req.RelayState)
+			requestID, redirectUrl = stateInfo.RequestID, stateInfo.RedirectUrl
+		}
+	}
+	if requestID == "" {
+		if valid, err := u.ValidateRedirectUrl(ctx, kbID, req.RelayState); err == nil && valid {
+			redirectUrl = req.RelayState
+		}
+	}
+
+	client, setting, err := u.getSAMLClient(ctx, kbID, origin)
+	if err != nil {
+		return nil, "", err
+	}
+	userInfo, err := client.ParseResponse(req.SAMLResponse, requestID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	auth := &domain.Auth{
+		UserInfo: domain.AuthUserInfo{
+			Username:  userInfo.Username,
+			AvatarUrl: userInfo.AvatarUrl,
+			Email:     userInfo.Email,
+		},
+		KBID:       kbID,
+		UnionID:    userInfo.NameID,
+		SourceType: consts.SourceTypeSAML,
+	}
+	auth, err = u.AuthRepo.GetOrCreateAuth(ctx, auth, consts.SourceTypeSAML)
+	if err != nil {
+		return nil, "", fmt.Errorf("create auth failed: %w", err)
+	}
+
+	if setting.GroupsAttr != "" && len(setting.GroupMappings) > 0 {
+		var groupIDs, managed []uint
+		for _, mapping := range setting.GroupMappings {
+			managed = append(managed, mapping.GroupID)
+			if slices.Contains(userInfo.Groups, mapping.Value) {
+				groupIDs = append(groupIDs, mapping.GroupID)
+			}
+		}
+		if err := u.AuthRepo.SyncAuthGroupMembership(ctx, kbID, auth.ID, groupIDs, managed); err != nil {
+			u.logger.Error("sync saml group membership failed", log.String("kb_id", kbID), log.Any("auth_id", auth.ID), log.Error(err))
+		}
+	}
+
+	if redirectUrl == "" {
+		kb, err := u.kbRepo.GetKnowledgeBaseByID(ctx, kbID)
+		if err != nil {
+			return nil, "", err
+		}
+		redirectUrl = samlBaseURL(kb, origin)
+	}
+	return auth, redirectUrl, nil
+}
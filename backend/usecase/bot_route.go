@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/samber/lo"
+
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/bot"
+	"github.com/chaitin/panda-wiki/pkg/bot/render"
+)
+
+const botRouteClassifierPrompt = `你是一个问题分类助手。下面是若干知识库及其说明，请判断用户的问题应当由哪个知识库回答。
+只输出知识库的编号；如果都不相关，输出 0。
+
+知识库：
+%s
+
+问题：%s`
+
+var botRouteNumber = regexp.MustCompile(`\d+`)
+
+// validateBotRoute checks the target kbs of the routing settings exist and the user fully controls them,
+// answers of the bot expose their documents
+func (u *AppUsecase) validateBotRoute(ctx context.Context, settings *domain.AppSettings) error {
+	if settings == nil || !settings.BotRouteSettings.IsEnabled {
+		return nil
+	}
+	route := settings.BotRouteSettings
+	kbIDs := append(lo.Map(route.Rules, func(rule domain.BotRouteRule, _ int) string {
+		return rule.KBID
+	}), route.FallbackKBIDs...)
+	for _, kbID := range lo.Uniq(kbIDs) {
+		if kbID == "" {
+			return fmt.Errorf("route kb id is required")
+		}
+		if _, err := u.chatUsecase.kbRepo.GetKnowledgeBaseByID(ctx, kbID); err != nil {
+			return fmt.Errorf("get route kb %s failed: %w", kbID, err)
+		}
+		capabilities, err := u.chatUsecase.kbRepo.GetKBCapabilities(ctx, kbID)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(capabilities, consts.KBCapabilityAppConfig) {
+			return domain.ErrPermissionDenied
+		}
+	}
+	return nil
+}
+
+// routeBotQuestion picks the kb answering a question received by the bot app of kbID. A conversation
+// stays in the kb it was started in, otherwise the rules are matched in order, then the classifier
+// decides and finally the fallback kbs are searched together with kbID.
+func (u *AppUsecase) routeBotQuestion(ctx context.Context, kbID string, appType domain.AppType, question string, info domain.ConversationInfo, conversationID string) domain.BotRoute {
+	route := domain.BotRoute{KBID: kbID}
+	app, err := u.repo.GetOrCreateAppByKBIDAndType(ctx, kbID, appType)
+	if err != nil {
+		u.logger.Error("get app failed", log.Error(err))
+		return route
+	}
+	route.AppID = app.ID
+	settings := app.Settings.BotRouteSettings
+	if !settings.IsEnabled {
+		return route
+	}
+
+	if conversationID != "" {
+		conversationKBID, err := u.chatUsecase.conversationUsecase.repo.GetConversationKBID(ctx, conversationID)
+		if err != nil {
+			u.logger.Error("get conversation kb failed", log.String("conversation_id", conversationID), log.Error(err))
+		}
+		if conversationKBID != "" {
+			route.KBID = conversationKBID
+			if conversationKBID == kbID {
+				route.MergeKBIDs = settings.FallbackKBIDs
+			}
+			return route
+		}
+	}
+
+	var chat string
+	if key := bot.ConversationKeyFromContext(ctx); key != nil {
+		chat = key.Chat
+	}
+	lower := strings.ToLower(question)
+	for _, rule := range settings.Rules {
+		if chat != "" && slices.Contains(rule.ChatIDs, chat) ||
+			lo.Some(rule.Departments, info.UserInfo.Departments) ||
+			lo.ContainsBy(rule.Keywords, func(keyword string) bool {
+				return keyword != "" && strings.Contains(lower, strings.ToLower(keyword))
+			}) {
+			route.KBID = rule.KBID
+			return route
+		}
+	}
+
+	if settings.Classifier && len(settings.Rules) > 0 {
+		target, err := u.classifyBotQuestion(ctx, kbID, settings.Rules, question)
+		if err != nil {
+			u.logger.Error("classify bot question failed", log.String("kb_id", kbID), log.Error(err))
+		}
+		if target != "" {
+			route.KBID = target
+			return route
+		}
+	}
+
+	route.MergeKBIDs = settings.FallbackKBIDs
+	return route
+}
+
+// classifyBotQuestion asks the chat model which kb of the rules answers the question, empty when none fits
+func (u *AppUsecase) classifyBotQuestion(ctx context.Context, kbID string, rules []domain.BotRouteRule, question string) (string, error) {
+	var (
+		candidates []string
+		lines      []string
+	)
+	for _, rule := range rules {
+		if rule.KBID == kbID || slices.Contains(candidates, rule.KBID) {
+			continue
+		}
+		kb, err := u.chatUsecase.kbRepo.GetKnowledgeBaseByID(ctx, rule.KBID)
+		if err != nil {
+			u.logger.Warn("skip route kb", log.String("kb_id", rule.KBID), log.Error(err))
+			continue
+		}
+		candidates = append(candidates, rule.KBID)
+		line := fmt.Sprintf("%d. %s", len(candidates), kb.Name)
+		if rule.Description != "" {
+			line += ": " + rule.Description
+		}
+		lines = append(lines, line)
+	}
+	if len(candidates) == 0 {
+		return "", nil
+	}
+
+	model, err := u.chatUsecase.modelUsecase.GetChatModel(ctx)
+	if err != nil {
+		return "", err
+	}
+	modelkitModel, err := model.ToModelkitModel()
+	if err != nil {
+		return "", err
+	}
+	chatModel, err := u.chatUsecase.modelkit.GetChatModel(ctx, modelkitModel)
+	if err != nil {
+		return "", err
+	}
+	answer, err := u.chatUsecase.llmUsecase.Generate(ctx, chatModel, []*schema.Message{
+		schema.UserMessage(fmt.Sprintf(botRouteClassifierPrompt, strings.Join(lines, "\n"), question)),
+	})
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(botRouteNumber.FindString(render.StripThink(answer)))
+	if err != nil || n < 1 || n > len(candidates) {
+		return "", nil
+	}
+	return candidates[n-1], nil
+}
@@ -20,6 +20,8 @@ import (
 type ChatUsecase struct {
 	llmUsecase          *LLMUsecase
 	conversationUsecase *ConversationUsecase
+	escalationUsecase   *EscalationUsecase
+	attachmentUsecase   *ChatAttachmentUsecase
 	modelUsecase        *ModelUsecase
 	appRepo             *pg.AppRepository
 	blockWordRepo       *pg.BlockWordRepo
@@ -30,11 +32,13 @@ type ChatUsecase struct {
 }
 
 func NewChatUsecase(llmUsecase *LLMUsecase, kbRepo *pg.KnowledgeBaseRepository, conversationUsecase *ConversationUsecase, modelUsecase *ModelUsecase, appRepo *pg.AppRepository,
-	blockWordRepo *pg.BlockWordRepo, authRepo *pg.AuthRepo, logger *log.Logger) (*ChatUsecase, error) {
+	blockWordRepo *pg.BlockWordRepo, authRepo *pg.AuthRepo, escalationUsecase *EscalationUsecase, attachmentUsecase *ChatAttachmentUsecase, logger *log.Logger) (*ChatUsecase, error) {
 	modelkit := modelkit.NewModelKit(logger.Logger)
 	u := &ChatUsecase{
 		llmUsecase:          llmUsecase,
 		conversationUsecase: conversationUsecase,
+		escalationUsecase:   escalationUsecase,
+		attachmentUsecase:   attachmentUsecase,
 		modelUsecase:        modelUsecase,
 		appRepo:             appRepo,
 		blockWordRepo:       blockWordRepo,
@@ -76,12 +80,21 @@ func (u *ChatUsecase) Chat(ctx context.Context, req *domain.ChatRequest) (<-chan
 	go func() {
 		defer close(eventCh)
 		// 1. get app detail and validate app
-		app, err := u.appRepo.GetOrCreateAppByKBIDAndType(ctx, req.KBID, req.AppType)
+		var app *domain.App
+		var err error
+		if req.AppID != "" {
+			// questions routed by a bot stay with the bot app, it owns the credentials to reply
+			app, err = u.appRepo.GetAppDetail(ctx, req.AppID)
+		} else {
+			app, err = u.appRepo.GetOrCreateAppByKBIDAndType(ctx, req.KBID, req.AppType)
+		}
 		if err != nil {
 			eventCh <- domain.SSEEvent{Type: "error", Content: "app not found"}
 			return
 		}
-		req.KBID = app.KBID
+		if req.AppID == "" {
+			req.KBID = app.KBID
+		}
 		req.AppID = app.ID
 		req.AppType = app.Type
 		// 2. get model and validate model
@@ -115,6 +128,23 @@ func (u *ChatUsecase) Chat(ctx context.Context, req *domain.ChatRequest) (<-chan
 				eventCh <- domain.SSEEvent{Type: "error", Content: "failed to create chat conversation"}
 				return
 			}
+		} else if req.ReuseConversation && req.ConversationID != "" { // bots continue the conversation of a thread or mail
+			eventCh <- domain.SSEEvent{Type: "conversation_id", Content: req.ConversationID}
+			err = u.conversationUsecase.CreateConversationIfNotExists(ctx, &domain.Conversation{
+				ID:        req.ConversationID,
+				Nonce:     uuid.New().String(),
+				AppID:     req.AppID,
+				KBID:      req.KBID,
+				Subject:   req.Message,
+				RemoteIP:  req.RemoteIP,
+				Info:      req.Info,
+				CreatedAt: time.Now(),
+			})
+			if err != nil {
+				u.logger.Error("failed to create chat conversation", log.Error(err))
+				eventCh <- domain.SSEEvent{Type: "error", Content: "failed to create chat conversation"}
+				return
+			}
 		} else if req.ConversationID == "" {
 			id, err := uuid.NewV7()
 			if err != nil {
@@ -204,6 +234,30 @@ func (u *ChatUsecase) Chat(ctx context.Context, req *domain.ChatRequest) (<-chan
 			}
 		}
 
+		// extra2. the user asked for a human or the conversation is waiting for one, do not answer
+		if handoff, ok := u.escalationUsecase.CheckHandoff(ctx, req.KBID, req.ConversationID, req.Message); ok {
+			eventCh <- domain.SSEEvent{Type: "data", Content: handoff}
+			if err := u.conversationUsecase.CreateChatConversationMessage(ctx, req.KBID, &domain.ConversationMessage{
+				ID:             messageId,
+				ConversationID: req.ConversationID,
+				KBID:           req.KBID,
+				AppID:          req.AppID,
+				Role:           schema.Assistant,
+				Content:        handoff,
+				RemoteIP:       req.RemoteIP,
+				ParentID:       userMessageId,
+			}); err != nil {
+				u.logger.Error("failed to save handoff message to conversation message", log.Error(err))
+			}
+			eventCh <- domain.SSEEvent{Type: "done"}
+			return
+		}
+
+		// extra3. images and documents attached to the question become context of the conversation
+		if len(req.Attachments) > 0 {
+			u.attachmentUsecase.Extract(ctx, req, userMessageId)
+		}
+
 		if req.Info.UserInfo.AuthUserID == 0 {
 			auth, _ := u.AuthRepo.GetAuthBySourceType(ctx, req.AppType.ToSourceType())
 			if auth != nil {
@@ -219,7 +273,7 @@ func (u *ChatUsecase) Chat(ctx context.Context, req *domain.ChatRequest) (<-chan
 		}
 
 		// 4. retrieve documents and format prompt
-		messages, rankedNodes, err := u.llmUsecase.FormatConversationMessages(ctx, req.ConversationID, req.KBID, groupIds)
+		messages, rankedNodes, err := u.llmUsecase.FormatConversationMessages(ctx, req.ConversationID, req.KBID, groupIds, req.Filter, req.MergeKBIDs)
 		if err != nil {
 			u.logger.Error("failed to format chat messages", log.Error(err))
 			eventCh <- domain.SSEEvent{Type: "error", Content: "failed to format chat messages"}
@@ -229,6 +283,7 @@ func (u *ChatUsecase) Chat(ctx context.Context, req *domain.ChatRequest) (<-chan
 		u.logger.Debug("message:", log.Any("schema", messages))
 		for _, node := range rankedNodes {
 			chunkResult := domain.NodeContentChunkSSE{
+				KBID:          node.KBID,
 				NodeID:        node.NodeID,
 				Name:          node.NodeName,
 				Summary:       node.NodeSummary,
@@ -296,6 +351,9 @@ func (u *ChatUsecase) Chat(ctx context.Context, req *domain.ChatRequest) (<-chan
 			eventCh <- domain.SSEEvent{Type: "error", Content: "对话失败，请稍后再试"}
 			return
 		}
+		if len(rankedNodes) == 0 {
+			u.escalationUsecase.OnNoResult(ctx, req.KBID, req.ConversationID, req.Message)
+		}
 		eventCh <- domain.SSEEvent{Type: "done"}
 	}()
 	return eventCh, nil
@@ -372,13 +430,14 @@ func (u *ChatUsecase) Search(ctx context.Context, req *domain.ChatSearchReq) (*d
 	if err != nil {
 		return nil, err
 	}
-	rankedNodes, err := u.llmUsecase.GetRankNodes(ctx, []string{kb.DatasetID}, req.Message, groupIds, 0.2, nil)
+	rankedNodes, err := u.llmUsecase.GetRankNodes(ctx, []*domain.KnowledgeBase{kb}, req.Filter, req.Message, groupIds, 0.2, nil)
 	if err != nil {
 		return nil, err
 	}
 	resp := domain.ChatSearchResp{}
 	for _, node := range rankedNodes {
 		chunkResult := domain.NodeContentChunkSSE{
+			KBID:          node.KBID,
 			NodeID:        node.NodeID,
 			Name:          node.NodeName,
 			Summary:       node.NodeSummary,
@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	modelkit "github.com/chaitin/ModelKit/v2/usecase"
+	"github.com/cloudwego/eino/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/repo/pg"
+	"github.com/chaitin/panda-wiki/utils"
+)
+
+const (
+	maxAttachmentImageSize    = 10 << 20
+	maxAttachmentDocumentSize = 20 << 20
+	// extracted text kept for one attachment
+	maxAttachmentContent = 20000
+	attachmentTimeout    = 2 * time.Minute
+)
+
+const attachmentImagePrompt = `请识别这张图片，按以下格式输出，不要输出其他内容：
+文字：
+{图片中的全部文字，保持原有的换行，报错信息、代码、命令要完整原样输出，没有文字则输出"无"}
+描述：
+{简要描述图片的内容，如界面、报错弹窗、图表等，以及其中的关键信息}`
+
+var ErrNoVisionModel = errors.New("未配置图像分析模型")
+
+// ChatAttachmentUsecase turns the images and documents attached to a question into text the answer can use
+type ChatAttachmentUsecase struct {
+	conversationRepo *pg.ConversationRepository
+	modelRepo        *pg.ModelRepository
+	fileUsecase      *FileUsecase
+	crawlerUsecase   *CrawlerUsecase
+	modelkit         *modelkit.ModelKit
+	logger           *log.Logger
+}
+
+func NewChatAttachmentUsecase(
+	conversationRepo *pg.ConversationRepository,
+	modelRepo *pg.ModelRepository,
+	fileUsecase *FileUsecase,
+	crawlerUsecase *CrawlerUsecase,
+	logger *log.Logger,
+) *ChatAttachmentUsecase {
+	return &ChatAttachmentUsecase{
+		conversationRepo: conversationRepo,
+		modelRepo:        modelRepo,
+		fileUsecase:      fileUsecase,
+		crawlerUsecase:   crawlerUsecase,
+		modelkit:         modelkit.NewModelKit(logger.Logger),
+		logger:           logger.WithModule("usecase.chat_attachment"),
+	}
+}
+
+// Extract reads the attachments of a question and records their text for the conversation,
+// an attachment that can not be read is recorded with its error so the answer can mention it
+func (u *ChatAttachmentUsecase) Extract(ctx context.Context, req *domain.ChatRequest, messageID string) {
+	attachments := make([]*domain.ConversationAttachment, len(req.Attachments))
+	var wg sync.WaitGroup
+	for i := range req.Attachments {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			attachments[i] = u.extract(ctx, req.KBID, &req.Attachments[i])
+			attachments[i].ConversationID = req.ConversationID
+			attachments[i].MessageID = messageID
+		}(i)
+	}
+	wg.Wait()
+	if err := u.conversationRepo.CreateConversationAttachments(ctx, attachments); err != nil {
+		u.logger.Error("save conversation attachments failed", log.String("conversation_id", req.ConversationID), log.Error(err))
+	}
+}
+
+func (u *ChatAttachmentUsecase) extract(ctx context.Context, kbID string, attachment *domain.ChatAttachment) *domain.ConversationAttachment {
+	ctx, cancel := context.WithTimeout(ctx, attachmentTimeout)
+	defer cancel()
+
+	result := &domain.ConversationAttachment{
+		ID:        uuid.New().String(),
+		KBID:      kbID,
+		Name:      attachment.Name,
+		Type:      consts.AttachmentTypeDocument,
+		CreatedAt: time.Now(),
+	}
+	data, contentType := attachment.Data, attachment.ContentType
+	if attachment.Key != "" {
+		// uploaded files are stored below the kb they belong to
+		if !strings.HasPrefix(attachment.Key, kbID+"/") {
+			result.Error = "附件不存在"
+			return result
+		}
+		var (
+			name string
+			err  error
+		)
+		data, contentType, name, err = u.fileUsecase.ReadFile(ctx, attachment.Key, maxAttachmentDocumentSize)
+		if err != nil {
+			u.logger.Warn("read attachment failed", log.String("key", attachment.Key), log.Error(err))
+			result.Error = "附件读取失败"
+			return result
+		}
+		if result.Name == "" {
+			result.Name = name
+		}
+	}
+	if result.Name == "" {
+		result.Name = filepath.Base(attachment.Key)
+	}
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = http.DetectContentType(data)
+	}
+
+	var (
+		content string
+		err     error
+	)
+	switch {
+	case strings.HasPrefix(contentType, "image/") || utils.IsImageFile(result.Name):
+		result.Type = consts.AttachmentTypeImage
+		content, err = u.describeImage(ctx, data, contentType)
+	case utils.IsPlainTextFile(result.Name) || strings.HasPrefix(contentType, "text/plain"):
+		if !utf8.Valid(data) {
+			err = errors.New("text attachment is not utf-8")
+			break
+		}
+		content = string(data)
+	case utils.IsDocumentFile(result.Name):
+		content, err = u.parseDocument(ctx, kbID, attachment.Key, result.Name, data)
+	default:
+		result.Error = "不支持的附件类型"
+		return result
+	}
+	if err != nil {
+		u.logger.Warn("extract attachment failed", log.String("name", result.Name), log.Error(err))
+		if errors.Is(err, ErrNoVisionModel) {
+			result.Error = ErrNoVisionModel.Error()
+		} else {
+			result.Error = "附件解析失败"
+		}
+		return result
+	}
+	result.Content = truncateRunes(strings.TrimSpace(content), maxAttachmentContent)
+	return result
+}
+
+func truncateRunes(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit]) + "..."
+}
+
+// describeImage asks the vision model for the text in the image and a short description
+func (u *ChatAttachmentUsecase) describeImage(ctx context.Context, data []byte, contentType string) (string, error) {
+	if len(data) > maxAttachmentImageSize {
+		return "", fmt.Errorf("image is larger than %d bytes", maxAttachmentImageSize)
+	}
+	model, err := u.modelRepo.GetModelByType(ctx, domain.ModelTypeAnalysisVL)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrNoVisionModel
+		}
+		return "", err
+	}
+	if !model.IsActive {
+		return "", ErrNoVisionModel
+	}
+	modelkitModel, err := model.ToModelkitModel()
+	if err != nil {
+		return "", err
+	}
+	chatModel, err := u.modelkit.GetChatModel(ctx, modelkitModel)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(contentType, "image/") {
+		contentType = http.DetectContentType(data)
+	}
+	resp, err := chatModel.Generate(ctx, []*schema.Message{{
+		Role: schema.User,
+		MultiContent: []schema.ChatMessagePart{
+			{Type: schema.ChatMessagePartTypeText, Text: attachmentImagePrompt},
+			{Type: schema.ChatMessagePartTypeImageURL, ImageURL: &schema.ChatMessageImageURL{
+				URL:      fmt.Sprintf("data:%s;base64,%s", contentType, base64.StdEncoding.EncodeToString(data)),
+				MIMEType: contentType,
+			}},
+		},
+	}})
+	if err != nil {
+		return "", fmt.Errorf("vision model failed: %w", err)
+	}
+	return resp.Content, nil
+}
+
+// parseDocument converts the document to markdown, files received by bots are uploaded first
+// and removed once parsed, they are context of the conversation rather than files of the kb
+func (u *ChatAttachmentUsecase) parseDocument(ctx context.Context, kbID, key, name string, data []byte) (string, error) {
+	if key == "" {
+		if len(data) > maxAttachmentDocumentSize {
+			return "", fmt.Errorf("document is larger than %d bytes", maxAttachmentDocumentSize)
+		}
+		var err error
+		if key, err = u.fileUsecase.UploadFileFromBytes(ctx, kbID, name, data); err != nil {
+			return "", err
+		}
+		defer func() {
+			if err := u.fileUsecase.DeleteFile(context.WithoutCancel(ctx), key); err != nil {
+				u.logger.Warn("delete bot attachment failed", log.String("key", key), log.Error(err))
+			}
+		}()
+	}
+	return u.crawlerUsecase.ParseFile(ctx, kbID, key)
+}
@@ -21,6 +21,8 @@ type ConversationUsecase struct {
 	logger       *log.Logger
 	ipRepo       *ipdb.IPAddressRepo
 	authRepo     *pg.AuthRepo
+
+	escalationUsecase *EscalationUsecase
 }
 
 func NewConversationUsecase(
@@ -30,6 +32,7 @@ func NewConversationUsecase(
 	logger *log.Logger,
 	ipRepo *ipdb.IPAddressRepo,
 	authRepo *pg.AuthRepo,
+	escalationUsecase *EscalationUsecase,
 ) *ConversationUsecase {
 	return &ConversationUsecase{
 		repo:         repo,
@@ -38,6 +41,8 @@ func NewConversationUsecase(
 		ipRepo:       ipRepo,
 		authRepo:     authRepo,
 		logger:       logger.WithModule("usecase.conversation"),
+
+		escalationUsecase: escalationUsecase,
 	}
 }
 
@@ -169,6 +174,10 @@ func (u *ConversationUsecase) ValidateConversationNonce(ctx context.Context, con
 	return u.repo.ValidateConversationNonce(ctx, conversationID, nonce)
 }
 
+func (u *ConversationUsecase) CreateConversationIfNotExists(ctx context.Context, conversation *domain.Conversation) error {
+	return u.repo.CreateConversationIfNotExists(ctx, conversation)
+}
+
 func (u *ConversationUsecase) CreateConversation(ctx context.Context, conversation *domain.Conversation) error {
 	if err := u.repo.CreateConversation(ctx, conversation); err != nil {
 		return err
@@ -200,6 +209,9 @@ func (u *ConversationUsecase) FeedBack(ctx context.Context, feedback *domain.Fee
 		if err := u.repo.UpdateMessageFeedback(ctx, feedback); err != nil {
 			return err
 		}
+		if feedback.Score == domain.DisLike {
+			u.escalationUsecase.OnDislike(ctx, messages)
+		}
 	} else {
 		return fmt.Errorf("already voted for this message, please do not vote again")
 	}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/bot"
+)
+
+// conversationLookupKeys lists the keys a message is matched by, no key means a new conversation
+func conversationLookupKeys(scope consts.ConversationScope, key *bot.ConversationKey) []string {
+	switch scope {
+	case consts.ConversationScopeNone:
+		return nil
+	case consts.ConversationScopeChat:
+		return []string{fmt.Sprintf("chat:%s", key.Chat)}
+	case consts.ConversationScopeChatUser:
+		return []string{fmt.Sprintf("user:%s:%s", key.Chat, key.User)}
+	}
+	var keys []string
+	if key.Thread != "" {
+		keys = append(keys, fmt.Sprintf("thread:%s:%s", key.Chat, key.Thread))
+	}
+	if key.ReplyTo != "" {
+		keys = append(keys, fmt.Sprintf("msg:%s:%s", key.Chat, key.ReplyTo))
+	}
+	// without thread or reply the message continues what the user asked in the chat recently
+	if len(keys) == 0 {
+		keys = append(keys, fmt.Sprintf("user:%s:%s", key.Chat, key.User))
+	}
+	return keys
+}
+
+// ResolveBotConversation picks the conversation a bot message continues, a new id is returned when
+// no key of the message was used within the window. Answers bound to the key are matched by replies later.
+func (u *ConversationUsecase) ResolveBotConversation(ctx context.Context, app *domain.App, key *bot.ConversationKey) string {
+	settings := app.Settings.BotConversationSettings
+	scope := settings.Scope
+	if scope == "" {
+		scope = consts.ConversationScopeThread
+	}
+	window := domain.DefaultBotConversationWindow
+	if settings.Window > 0 {
+		window = min(time.Duration(settings.Window)*time.Minute, domain.MaxBotConversationWindow)
+	}
+	now := time.Now()
+	keys := conversationLookupKeys(scope, key)
+
+	conversationID, err := u.repo.GetConversationIDByKeys(ctx, app.ID, keys, now.Add(-window))
+	if err != nil {
+		u.logger.Error("get conversation by keys failed", log.String("app_id", app.ID), log.Error(err))
+	}
+	if conversationID == "" {
+		id, err := uuid.NewV7()
+		if err != nil {
+			id = uuid.New()
+		}
+		conversationID = id.String()
+	}
+	if err := u.repo.UpsertConversationKeys(ctx, app.ID, keys, conversationID, now); err != nil {
+		u.logger.Error("upsert conversation keys failed", log.String("app_id", app.ID), log.Error(err))
+	}
+	if scope != consts.ConversationScopeNone {
+		ctx := context.WithoutCancel(ctx)
+		key.SetBinder(func(messageID string) {
+			msgKey := fmt.Sprintf("msg:%s:%s", key.Chat, messageID)
+			if err := u.repo.UpsertConversationKeys(ctx, app.ID, []string{msgKey}, conversationID, time.Now()); err != nil {
+				u.logger.Error("bind bot message failed", log.String("app_id", app.ID), log.Error(err))
+			}
+		})
+	}
+	return conversationID
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"slices"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -217,3 +218,58 @@ func (u *CrawlerUsecase) ScrapeGetResults(ctx context.Context, taskIds []string)
 		List:   list,
 	}, nil
 }
+
+func firstFileDoc(doc anydoc.Child) (anydoc.Value, bool) {
+	if doc.Value.File {
+		return doc.Value, true
+	}
+	for _, child := range doc.Children {
+		if value, ok := firstFileDoc(child); ok {
+			return value, true
+		}
+	}
+	return anydoc.Value{}, false
+}
+
+// ParseFile converts an uploaded file to markdown and waits until the export task is done
+func (u *CrawlerUsecase) ParseFile(ctx context.Context, kbID, key string) (string, error) {
+	id := utils.GetFileNameWithoutExt(key)
+	if !utils.IsUUID(id) {
+		id = uuid.New().String()
+	}
+	docs, err := u.anydocClient.GetUrlList(ctx, fmt.Sprintf("http://panda-wiki-minio:9000/static-file/%s", key), id)
+	if err != nil {
+		return "", err
+	}
+	doc, ok := firstFileDoc(docs.Data.Docs)
+	if !ok {
+		return "", fmt.Errorf("no document found in %s", key)
+	}
+	exportRes, err := u.anydocClient.UrlExport(ctx, id, doc.ID, kbID)
+	if err != nil {
+		return "", err
+	}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-ticker.C:
+		}
+		taskRes, err := u.anydocClient.TaskList(ctx, []string{exportRes.Data})
+		if err != nil {
+			return "", err
+		}
+		switch task := taskRes.Data[0]; task.Status {
+		case anydoc.StatusFailed:
+			return "", fmt.Errorf("file parse failed: %s", task.Err)
+		case anydoc.StatusCompleted:
+			content, err := u.anydocClient.DownloadDoc(ctx, task.Markdown)
+			if err != nil {
+				return "", err
+			}
+			return string(content), nil
+		}
+	}
+}
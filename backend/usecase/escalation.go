@@ -0,0 +1,289 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+
+	v1 "github.com/chaitin/panda-wiki/api/conversation/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/notify"
+	"github.com/chaitin/panda-wiki/repo/pg"
+)
+
+// escalationDislikeWindow only dislikes within this window count towards the threshold
+const escalationDislikeWindow = 24 * time.Hour
+
+// EscalationUsecase hands conversations over to human agents, independent of the channel they came from
+type EscalationUsecase struct {
+	repo       *pg.ConversationRepository
+	notifyRepo *pg.NotifyRepository
+	appRepo    *pg.AppRepository
+	logger     *log.Logger
+}
+
+func NewEscalationUsecase(
+	repo *pg.ConversationRepository,
+	notifyRepo *pg.NotifyRepository,
+	appRepo *pg.AppRepository,
+	logger *log.Logger,
+) *EscalationUsecase {
+	return &EscalationUsecase{
+		repo:       repo,
+		notifyRepo: notifyRepo,
+		appRepo:    appRepo,
+		logger:     logger.WithModule("usecase.escalation"),
+	}
+}
+
+func (u *EscalationUsecase) GetSettings(ctx context.Context, kbID string) (*domain.EscalationSettings, error) {
+	return u.repo.GetEscalationSettings(ctx, kbID)
+}
+
+func (u *EscalationUsecase) UpdateSettings(ctx context.Context, req *v1.UpdateEscalationSettingsReq) error {
+	settings := &domain.EscalationSettings{
+		Enabled:          req.Enabled,
+		Keywords:         make([]string, 0, len(req.Keywords)),
+		OnNoResult:       req.OnNoResult,
+		DislikeThreshold: req.DislikeThreshold,
+		HandoffMessage:   strings.TrimSpace(req.HandoffMessage),
+		Webhooks:         req.Webhooks,
+		Emails:           req.Emails,
+	}
+	for _, keyword := range req.Keywords {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			settings.Keywords = append(settings.Keywords, keyword)
+		}
+	}
+	if settings.Webhooks == nil {
+		settings.Webhooks = make([]domain.NotifyWebhook, 0)
+	}
+	if settings.Emails == nil {
+		settings.Emails = make([]string, 0)
+	}
+	return u.repo.UpsertEscalationSettings(ctx, req.KbId, settings)
+}
+
+func handoffMessage(settings *domain.EscalationSettings) string {
+	if settings.HandoffMessage != "" {
+		return settings.HandoffMessage
+	}
+	return domain.DefaultHandoffMessage
+}
+
+func matchEscalationKeyword(settings *domain.EscalationSettings, message string) bool {
+	keywords := settings.Keywords
+	if len(keywords) == 0 {
+		keywords = domain.DefaultEscalationKeywords
+	}
+	message = strings.ToLower(message)
+	for _, keyword := range keywords {
+		if strings.Contains(message, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+// HandoffKeywords returns the keywords users ask for a human with, nil when escalation is disabled.
+// WeChat customer service transfers to its own servicers when a message contains one of them
+func (u *EscalationUsecase) HandoffKeywords(ctx context.Context, kbID string) []string {
+	settings, err := u.repo.GetEscalationSettings(ctx, kbID)
+	if err != nil {
+		u.logger.Error("get escalation settings failed", log.String("kb_id", kbID), log.Error(err))
+		return nil
+	}
+	if !settings.Enabled {
+		return nil
+	}
+	if len(settings.Keywords) == 0 {
+		return domain.DefaultEscalationKeywords
+	}
+	return settings.Keywords
+}
+
+// CheckHandoff is called before a question is answered, it returns the message to reply instead of
+// an ai answer when the user asks for a human or the conversation is already waiting for one
+func (u *EscalationUsecase) CheckHandoff(ctx context.Context, kbID, conversationID, message string) (string, bool) {
+	settings, err := u.repo.GetEscalationSettings(ctx, kbID)
+	if err != nil {
+		u.logger.Error("get escalation settings failed", log.String("kb_id", kbID), log.Error(err))
+		return "", false
+	}
+	if !settings.Enabled {
+		return "", false
+	}
+	conversation, err := u.repo.GetConversation(ctx, kbID, conversationID)
+	if err != nil {
+		u.logger.Error("get conversation failed", log.String("conversation_id", conversationID), log.Error(err))
+		return "", false
+	}
+	if conversation.EscalationStatus == consts.EscalationStatusOpen {
+		return handoffMessage(settings), true
+	}
+	if !matchEscalationKeyword(settings, message) {
+		return "", false
+	}
+	if err := u.escalate(ctx, settings, conversation, consts.EscalationReasonUserRequest, message); err != nil {
+		if !errors.Is(err, domain.ErrEscalationUnsupported) {
+			u.logger.Error("escalate conversation failed", log.String("conversation_id", conversationID), log.Error(err))
+		}
+		return "", false
+	}
+	return handoffMessage(settings), true
+}
+
+// OnNoResult escalates the conversation when retrieval found no relevant document
+func (u *EscalationUsecase) OnNoResult(ctx context.Context, kbID, conversationID, question string) {
+	settings, err := u.repo.GetEscalationSettings(ctx, kbID)
+	if err != nil {
+		u.logger.Error("get escalation settings failed", log.String("kb_id", kbID), log.Error(err))
+		return
+	}
+	if !settings.Enabled || !settings.OnNoResult {
+		return
+	}
+	conversation, err := u.repo.GetConversation(ctx, kbID, conversationID)
+	if err != nil {
+		u.logger.Error("get conversation failed", log.String("conversation_id", conversationID), log.Error(err))
+		return
+	}
+	if err := u.escalate(ctx, settings, conversation, consts.EscalationReasonNoResult, question); err != nil && !errors.Is(err, domain.ErrEscalationUnsupported) {
+		u.logger.Error("escalate conversation failed", log.String("conversation_id", conversationID), log.Error(err))
+	}
+}
+
+// OnDislike escalates the conversation once the user disliked enough answers recently
+func (u *EscalationUsecase) OnDislike(ctx context.Context, message *domain.ConversationMessage) {
+	settings, err := u.repo.GetEscalationSettings(ctx, message.KBID)
+	if err != nil {
+		u.logger.Error("get escalation settings failed", log.String("kb_id", message.KBID), log.Error(err))
+		return
+	}
+	if !settings.Enabled || settings.DislikeThreshold <= 0 {
+		return
+	}
+	conversation, err := u.repo.GetConversation(ctx, message.KBID, message.ConversationID)
+	if err != nil {
+		u.logger.Error("get conversation failed", log.String("conversation_id", message.ConversationID), log.Error(err))
+		return
+	}
+	count, err := u.repo.CountRecentDislikes(ctx, conversation, time.Now().Add(-escalationDislikeWindow))
+	if err != nil {
+		u.logger.Error("count dislikes failed", log.String("conversation_id", conversation.ID), log.Error(err))
+		return
+	}
+	if count < int64(settings.DislikeThreshold) {
+		return
+	}
+	if err := u.escalate(ctx, settings, conversation, consts.EscalationReasonDislike, message.Content); err != nil && !errors.Is(err, domain.ErrEscalationUnsupported) {
+		u.logger.Error("escalate conversation failed", log.String("conversation_id", conversation.ID), log.Error(err))
+	}
+}
+
+// escalate marks the conversation as waiting for an agent and notifies the on-call targets once,
+// it returns domain.ErrEscalationUnsupported when the channel can not deliver the replies of the agent
+func (u *EscalationUsecase) escalate(ctx context.Context, settings *domain.EscalationSettings, conversation *domain.Conversation, reason consts.EscalationReason, content string) error {
+	app, err := u.appRepo.GetAppDetail(ctx, conversation.AppID)
+	if err != nil {
+		return err
+	}
+	if !app.Type.SupportsEscalation() {
+		u.logger.Debug("escalation skipped", log.String("conversation_id", conversation.ID), log.Int("app_type", int(app.Type)))
+		return domain.ErrEscalationUnsupported
+	}
+	opened, err := u.repo.EscalateConversation(ctx, conversation.ID, reason, time.Now())
+	if err != nil {
+		return err
+	}
+	if !opened {
+		return nil
+	}
+	u.logger.Info("conversation escalated", log.String("conversation_id", conversation.ID), log.String("reason", string(reason)))
+	// the user is answered right away, webhooks and mails may be slow
+	go func() {
+		_ = u.notifyOnCall(context.WithoutCancel(ctx), settings, app, conversation, reason, content)
+	}()
+	return nil
+}
+
+func (u *EscalationUsecase) notifyOnCall(ctx context.Context, settings *domain.EscalationSettings, app *domain.App, conversation *domain.Conversation, reason consts.EscalationReason, content string) error {
+	channel := app.Name
+	if channel == "" {
+		channel = app.Type.ToSourceType().Name()
+	}
+	userInfo := conversation.Info.UserInfo
+	user := lo.CoalesceOrEmpty(userInfo.NickName, userInfo.RealName, userInfo.Email, userInfo.UserID, "匿名用户")
+	msg := &domain.NotifyMessage{
+		Title: "会话需要人工处理",
+		Content: fmt.Sprintf("- 原因: %s\n- 渠道: %s\n- 用户: %s\n- 会话: %s\n- 会话 ID: %s\n\n%s\n\n请在管理后台的问答记录中回复, 回复会发送到用户所在渠道。",
+			reason.Name(), channel, user, conversation.Subject, conversation.ID, content),
+	}
+
+	var errs []error
+	for i := range settings.Webhooks {
+		hook := &settings.Webhooks[i]
+		if err := notify.SendWebhook(ctx, hook, msg); err != nil {
+			errs = append(errs, fmt.Errorf("send webhook %s: %w", hook.Name, err))
+		}
+	}
+	if len(settings.Emails) > 0 {
+		notifySettings, err := u.notifyRepo.GetSettings(ctx, conversation.KBID)
+		if err != nil {
+			errs = append(errs, err)
+		} else if notifySettings.SMTP.Enabled {
+			if err := notify.SendEmail(&notifySettings.SMTP, settings.Emails, msg); err != nil {
+				errs = append(errs, fmt.Errorf("send email: %w", err))
+			}
+		} else {
+			errs = append(errs, errors.New("smtp is not enabled in notify settings"))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		u.logger.Warn("notify on-call failed", log.String("conversation_id", conversation.ID), log.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Reply saves the answer of a human agent, the caller pushes it to the channel of the returned conversation.
+// Conversations of channels that can not deliver it are refused with ErrEscalationUnsupported
+func (u *EscalationUsecase) Reply(ctx context.Context, req *v1.EscalationReplyReq, userID string) (*domain.Conversation, string, error) {
+	conversation, err := u.repo.GetConversation(ctx, req.KbId, req.ConversationID)
+	if err != nil {
+		return nil, "", err
+	}
+	app, err := u.appRepo.GetAppDetail(ctx, conversation.AppID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !app.Type.SupportsEscalation() {
+		return nil, "", domain.ErrEscalationUnsupported
+	}
+	messageID := uuid.New().String()
+	if err := u.repo.CreateConversationMessage(ctx, &domain.ConversationMessage{
+		ID:             messageID,
+		ConversationID: conversation.ID,
+		KBID:           conversation.KBID,
+		AppID:          conversation.AppID,
+		Role:           schema.Assistant,
+		Content:        req.Content,
+		AgentID:        userID,
+		CreatedAt:      time.Now(),
+	}, nil); err != nil {
+		return nil, "", err
+	}
+	return conversation, messageID, nil
+}
+
+func (u *EscalationUsecase) Resolve(ctx context.Context, req *v1.ResolveEscalationReq) error {
+	return u.repo.ResolveConversation(ctx, req.KbId, req.ConversationID, time.Now())
+}
@@ -113,6 +113,35 @@ func (u *FileUsecase) UploadFileFromBytes(ctx context.Context, kbID string, file
 	return resp.Key, nil
 }
 
+// DeleteFile removes an uploaded file
+func (u *FileUsecase) DeleteFile(ctx context.Context, key string) error {
+	if err := u.s3Client.RemoveObject(ctx, domain.Bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("remove object failed: %w", err)
+	}
+	return nil
+}
+
+// ReadFile returns an uploaded file with its content type and original name, files larger than limit are refused
+func (u *FileUsecase) ReadFile(ctx context.Context, key string, limit int64) ([]byte, string, string, error) {
+	object, err := u.s3Client.GetObject(ctx, domain.Bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, "", "", fmt.Errorf("get object failed: %w", err)
+	}
+	defer object.Close()
+	info, err := object.Stat()
+	if err != nil {
+		return nil, "", "", fmt.Errorf("stat object failed: %w", err)
+	}
+	if info.Size > limit {
+		return nil, "", "", fmt.Errorf("file is larger than %d bytes", limit)
+	}
+	data, err := io.ReadAll(io.LimitReader(object, limit))
+	if err != nil {
+		return nil, "", "", fmt.Errorf("read object failed: %w", err)
+	}
+	return data, info.ContentType, info.UserMetadata["Originalname"], nil
+}
+
 func (u *FileUsecase) UploadFileFromReader(
 	ctx context.Context,
 	kbID string,
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	v1 "github.com/chaitin/panda-wiki/api/kb/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/repo/pg"
+)
+
+type KBRoleUsecase struct {
+	repo   *pg.KBRoleRepository
+	logger *log.Logger
+}
+
+func NewKBRoleUsecase(repo *pg.KBRoleRepository, logger *log.Logger) *KBRoleUsecase {
+	return &KBRoleUsecase{
+		repo:   repo,
+		logger: logger.WithModule("usecase.kb_role"),
+	}
+}
+
+func (u *KBRoleUsecase) ListRoles(ctx context.Context) (*v1.KBRoleListResp, error) {
+	roles, err := u.repo.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	capabilities := make([]domain.KBCapabilityInfo, 0, len(consts.KBCapabilities))
+	for _, capability := range consts.KBCapabilities {
+		info := domain.KBCapabilityInfo{
+			Capability:  capability,
+			Permissions: make([]consts.UserKBPermission, 0),
+		}
+		for _, perm := range consts.UserKBPermissions {
+			if slices.Contains(perm.Capabilities(), capability) {
+				info.Permissions = append(info.Permissions, perm)
+			}
+		}
+		capabilities = append(capabilities, info)
+	}
+	return &v1.KBRoleListResp{
+		Roles:        roles,
+		Capabilities: capabilities,
+	}, nil
+}
+
+func (u *KBRoleUsecase) GetRole(ctx context.Context, id string) (*domain.KBRole, error) {
+	return u.repo.GetRole(ctx, id)
+}
+
+func (u *KBRoleUsecase) CreateRole(ctx context.Context, req *v1.KBRoleCreateReq) (*domain.KBRole, error) {
+	now := time.Now()
+	role := &domain.KBRole{
+		ID:           uuid.New().String(),
+		Name:         req.Name,
+		Description:  req.Description,
+		Capabilities: capabilityArray(req.Capabilities),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := u.repo.CreateRole(ctx, role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// UpdateRole 修改后立即对所有分配了该角色的成员生效
+func (u *KBRoleUsecase) UpdateRole(ctx context.Context, req *v1.KBRoleUpdateReq) (*domain.KBRole, error) {
+	role, err := u.repo.GetRole(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	role.Name = req.Name
+	role.Description = req.Description
+	role.Capabilities = capabilityArray(req.Capabilities)
+	role.UpdatedAt = time.Now()
+	if err := u.repo.UpdateRole(ctx, role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// DeleteRole 仍分配给成员的角色不能删除
+func (u *KBRoleUsecase) DeleteRole(ctx context.Context, id string) error {
+	count, err := u.repo.CountRoleUsers(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return domain.ErrKBRoleInUse
+	}
+	return u.repo.DeleteRole(ctx, id)
+}
+
+func capabilityArray(capabilities []consts.KBCapability) pq.StringArray {
+	values := make(pq.StringArray, 0, len(capabilities))
+	for _, capability := range capabilities {
+		if !slices.Contains(values, string(capability)) {
+			values = append(values, string(capability))
+		}
+	}
+	return values
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,26 +20,34 @@ import (
 )
 
 type KnowledgeBaseUsecase struct {
-	repo     *pg.KnowledgeBaseRepository
-	nodeRepo *pg.NodeRepository
-	ragRepo  *mq.RAGRepository
-	userRepo *pg.UserRepository
-	rag      rag.RAGService
-	kbCache  *cache.KBRepo
-	logger   *log.Logger
-	config   *config.Config
+	repo        *pg.KnowledgeBaseRepository
+	nodeRepo    *pg.NodeRepository
+	reviewRepo  *pg.NodeReviewRepository
+	ragRepo     *mq.RAGRepository
+	linkUsecase *NodeLinkUsecase
+	broadcast   *ReleaseBroadcastUsecase
+	userRepo    *pg.UserRepository
+	roleRepo    *pg.KBRoleRepository
+	rag         rag.RAGService
+	kbCache     *cache.KBRepo
+	logger      *log.Logger
+	config      *config.Config
 }
 
-func NewKnowledgeBaseUsecase(repo *pg.KnowledgeBaseRepository, nodeRepo *pg.NodeRepository, ragRepo *mq.RAGRepository, userRepo *pg.UserRepository, rag rag.RAGService, kbCache *cache.KBRepo, logger *log.Logger, config *config.Config) (*KnowledgeBaseUsecase, error) {
+func NewKnowledgeBaseUsecase(repo *pg.KnowledgeBaseRepository, nodeRepo *pg.NodeRepository, reviewRepo *pg.NodeReviewRepository, ragRepo *mq.RAGRepository, linkUsecase *NodeLinkUsecase, broadcast *ReleaseBroadcastUsecase, userRepo *pg.UserRepository, roleRepo *pg.KBRoleRepository, rag rag.RAGService, kbCache *cache.KBRepo, logger *log.Logger, config *config.Config) (*KnowledgeBaseUsecase, error) {
 	u := &KnowledgeBaseUsecase{
-		repo:     repo,
-		nodeRepo: nodeRepo,
-		ragRepo:  ragRepo,
-		userRepo: userRepo,
-		rag:      rag,
-		logger:   logger.WithModule("usecase.knowledge_base"),
-		config:   config,
-		kbCache:  kbCache,
+		repo:        repo,
+		nodeRepo:    nodeRepo,
+		reviewRepo:  reviewRepo,
+		ragRepo:     ragRepo,
+		linkUsecase: linkUsecase,
+		broadcast:   broadcast,
+		userRepo:    userRepo,
+		roleRepo:    roleRepo,
+		rag:         rag,
+		logger:      logger.WithModule("usecase.knowledge_base"),
+		config:      config,
+		kbCache:     kbCache,
 	}
 	return u, nil
 }
@@ -132,6 +141,10 @@ func (u *KnowledgeBaseUsecase) GetKnowledgeBasePerm(ctx context.Context, kbID st
 	return perm, nil
 }
 
+func (u *KnowledgeBaseUsecase) GetKnowledgeBaseCapabilities(ctx context.Context, kbID string) ([]consts.KBCapability, error) {
+	return u.repo.GetKBCapabilities(ctx, kbID)
+}
+
 func (u *KnowledgeBaseUsecase) DeleteKnowledgeBase(ctx context.Context, kbID string) error {
 	if err := u.repo.DeleteKnowledgeBase(ctx, kbID); err != nil {
 		return err
@@ -147,6 +160,20 @@ func (u *KnowledgeBaseUsecase) DeleteKnowledgeBase(ctx context.Context, kbID str
 }
 
 func (u *KnowledgeBaseUsecase) CreateKBRelease(ctx context.Context, req *domain.CreateKBReleaseReq) (string, error) {
+	if err := u.validateReleaseReview(ctx, req.KBID, req.NodeIDs); err != nil {
+		return "", err
+	}
+	// documents published before are listed as updated in the broadcast
+	var releasedBefore []string
+	if req.Broadcast && len(req.NodeIDs) > 0 {
+		nodeReleases, err := u.nodeRepo.GetLatestNodeReleaseByNodeIDs(ctx, req.KBID, req.NodeIDs)
+		if err != nil {
+			return "", fmt.Errorf("failed to get published nodes: %w", err)
+		}
+		for _, nodeRelease := range nodeReleases {
+			releasedBefore = append(releasedBefore, nodeRelease.NodeID)
+		}
+	}
 	if len(req.NodeIDs) > 0 {
 		// create published nodes
 		releaseIDs, err := u.nodeRepo.CreateNodeReleases(ctx, req.KBID, req.NodeIDs)
@@ -167,6 +194,7 @@ func (u *KnowledgeBaseUsecase) CreateKBRelease(ctx context.Context, req *domain.
 				return "", err
 			}
 		}
+		u.linkUsecase.IndexNodesQuietly(ctx, req.KBID, req.NodeIDs)
 	}
 
 	release := &domain.KBRelease{
@@ -179,10 +207,36 @@ func (u *KnowledgeBaseUsecase) CreateKBRelease(ctx context.Context, req *domain.
 	if err := u.repo.CreateKBRelease(ctx, release); err != nil {
 		return "", fmt.Errorf("failed to create kb release: %w", err)
 	}
+	if req.Broadcast && len(req.NodeIDs) > 0 {
+		go u.broadcast.BroadcastReleaseQuietly(context.WithoutCancel(ctx), release, req.NodeIDs, releasedBefore)
+	}
 
 	return release.ID, nil
 }
 
+// validateReleaseReview makes sure every document to be released has been approved
+// when the knowledge base requires review before publishing
+func (u *KnowledgeBaseUsecase) validateReleaseReview(ctx context.Context, kbID string, nodeIDs []string) error {
+	if len(nodeIDs) == 0 {
+		return nil
+	}
+	policy, err := u.reviewRepo.GetPolicy(ctx, kbID)
+	if err != nil {
+		return err
+	}
+	if !policy.Enabled {
+		return nil
+	}
+	names, err := u.reviewRepo.GetUnapprovedNodeNames(ctx, kbID, nodeIDs)
+	if err != nil {
+		return err
+	}
+	if len(names) > 0 {
+		return fmt.Errorf("%w: %v", domain.ErrNodeReviewRequired, names)
+	}
+	return nil
+}
+
 func (u *KnowledgeBaseUsecase) GetKBReleaseList(ctx context.Context, req *domain.GetKBReleaseListReq) (*domain.GetKBReleaseListResp, error) {
 	total, releases, err := u.repo.GetKBReleaseList(ctx, req.KBID)
 	if err != nil {
@@ -209,11 +263,16 @@ func (u *KnowledgeBaseUsecase) KBUserInvite(ctx context.Context, req v1.KBUserIn
 	if user.Role == consts.UserRoleAdmin {
 		return fmt.Errorf("knowledge base can not invite to admin user")
 	}
+	perm, err := u.kbUserPerm(ctx, req.Perm, req.RoleID)
+	if err != nil {
+		return err
+	}
 
 	if err := u.repo.CreateKBUser(ctx, &domain.KBUsers{
 		KBId:      req.KBId,
 		UserId:    req.UserId,
-		Perm:      req.Perm,
+		Perm:      perm,
+		RoleID:    req.RoleID,
 		CreatedAt: time.Now(),
 	}); err != nil {
 		return err
@@ -222,60 +281,53 @@ func (u *KnowledgeBaseUsecase) KBUserInvite(ctx context.Context, req v1.KBUserIn
 	return nil
 }
 
+func (u *KnowledgeBaseUsecase) GetKBUser(ctx context.Context, kbID, userID string) (*domain.KBUsers, error) {
+	return u.repo.GetKBUser(ctx, kbID, userID)
+}
+
 func (u *KnowledgeBaseUsecase) UpdateUserKB(ctx context.Context, req v1.KBUserUpdateReq) error {
-	authInfo := domain.GetAuthInfoFromCtx(ctx)
-	if authInfo == nil {
-		return fmt.Errorf("authInfo not found in context")
+	if err := u.canManageKBUser(ctx, req.KBId); err != nil {
+		return err
 	}
-
-	kbUser, err := u.repo.GetKBUser(ctx, req.KBId, req.UserId)
-	if err != nil {
+	if _, err := u.repo.GetKBUser(ctx, req.KBId, req.UserId); err != nil {
 		return err
 	}
-	if authInfo.IsToken {
-		if authInfo.KBId != req.KBId {
-			return fmt.Errorf("invalid knowledge base token")
-		}
-		if authInfo.Permission != consts.UserKBPermissionFullControl {
-			return fmt.Errorf("only admin can update user from knowledge base")
-		}
-	} else {
-		user, err := u.userRepo.GetUser(ctx, authInfo.UserId)
-		if err != nil {
-			return err
-		}
-		if user.Role != consts.UserRoleAdmin && kbUser.Perm != consts.UserKBPermissionFullControl {
-			return fmt.Errorf("only admin can update user from knowledge base")
-		}
+	perm, err := u.kbUserPerm(ctx, req.Perm, req.RoleID)
+	if err != nil {
+		return err
 	}
-	return u.repo.UpdateKBUserPerm(ctx, req.KBId, req.UserId, req.Perm)
+	return u.repo.UpdateKBUserPerm(ctx, req.KBId, req.UserId, perm, req.RoleID)
 }
 
-func (u *KnowledgeBaseUsecase) KBUserDelete(ctx context.Context, req v1.KBUserDeleteReq) error {
-	authInfo := domain.GetAuthInfoFromCtx(ctx)
-	if authInfo == nil {
-		return fmt.Errorf("authInfo not found in context")
+// kbUserPerm 指定自定义角色时不保留内置权限
+func (u *KnowledgeBaseUsecase) kbUserPerm(ctx context.Context, perm consts.UserKBPermission, roleID string) (consts.UserKBPermission, error) {
+	if roleID == "" {
+		return perm, nil
 	}
+	if _, err := u.roleRepo.GetRole(ctx, roleID); err != nil {
+		return "", fmt.Errorf("get kb role failed: %w", err)
+	}
+	return consts.UserKBPermissionNull, nil
+}
 
-	kbUser, err := u.repo.GetKBUser(ctx, req.KBId, req.UserId)
+// canManageKBUser 管理员或拥有成员管理能力的用户才能修改知识库成员
+func (u *KnowledgeBaseUsecase) canManageKBUser(ctx context.Context, kbID string) error {
+	capabilities, err := u.repo.GetKBCapabilities(ctx, kbID)
 	if err != nil {
 		return err
 	}
-	if authInfo.IsToken {
-		if authInfo.KBId != req.KBId {
-			return fmt.Errorf("knowledge base can not delete user from knowledge base")
-		}
-		if authInfo.Permission != consts.UserKBPermissionFullControl {
-			return fmt.Errorf("only admin can delete user from knowledge base")
-		}
-	} else {
-		user, err := u.userRepo.GetUser(ctx, authInfo.UserId)
-		if err != nil {
-			return err
-		}
-		if user.Role != consts.UserRoleAdmin && kbUser.Perm != consts.UserKBPermissionFullControl {
-			return fmt.Errorf("only admin can delete user from knowledge base")
-		}
+	if !slices.Contains(capabilities, consts.KBCapabilityUserManage) {
+		return domain.ErrPermissionDenied
+	}
+	return nil
+}
+
+func (u *KnowledgeBaseUsecase) KBUserDelete(ctx context.Context, req v1.KBUserDeleteReq) error {
+	if err := u.canManageKBUser(ctx, req.KBId); err != nil {
+		return err
+	}
+	if _, err := u.repo.GetKBUser(ctx, req.KBId, req.UserId); err != nil {
+		return err
 	}
 	if err := u.repo.DeleteKBUser(ctx, req.KBId, req.UserId); err != nil {
 		return err
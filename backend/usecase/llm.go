@@ -27,6 +27,13 @@ import (
 	"github.com/chaitin/panda-wiki/utils"
 )
 
+const (
+	// text of the question attachments added to the retrieval query
+	maxAttachmentQuery = 500
+	// text of all conversation attachments added to the prompt
+	maxAttachmentPrompt = 30000
+)
+
 type LLMUsecase struct {
 	rag              rag.RAGService
 	conversationRepo *pg.ConversationRepository
@@ -60,6 +67,8 @@ func (u *LLMUsecase) FormatConversationMessages(
 	conversationID string,
 	kbID string,
 	groupIDs []int,
+	filter *domain.NodeMetaFilter,
+	mergeKBIDs []string,
 ) ([]*schema.Message, []*domain.RankedNodeChunks, error) {
 	messages := make([]*schema.Message, 0)
 	rankedNodes := make([]*domain.RankedNodeChunks, 0)
@@ -70,12 +79,14 @@ func (u *LLMUsecase) FormatConversationMessages(
 	}
 	if len(msgs) > 0 {
 		historyMessages := make([]*schema.Message, 0)
+		var questionID string
 		for _, msg := range msgs {
 			switch msg.Role {
 			case schema.Assistant:
 				historyMessages = append(historyMessages, schema.AssistantMessage(msg.Content, nil))
 			case schema.User:
 				historyMessages = append(historyMessages, schema.UserMessage(msg.Content))
+				questionID = msg.ID
 			default:
 				continue
 			}
@@ -83,6 +94,18 @@ func (u *LLMUsecase) FormatConversationMessages(
 		if len(historyMessages) > 0 {
 			question := historyMessages[len(historyMessages)-1].Content
 
+			attachments, err := u.conversationRepo.GetConversationAttachments(ctx, conversationID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("get conversation attachments failed: %w", err)
+			}
+			// the text of the files attached to this question is searched together with the question
+			query := question
+			for _, attachment := range attachments {
+				if attachment.MessageID == questionID && attachment.Content != "" {
+					query += "\n" + truncateRunes(attachment.Content, maxAttachmentQuery)
+				}
+			}
+
 			systemPrompt := domain.SystemPrompt
 			if prompt, err := u.promptRepo.GetPrompt(ctx, kbID); err != nil {
 				u.logger.Error("get prompt from settings failed", log.Error(err))
@@ -96,21 +119,32 @@ func (u *LLMUsecase) FormatConversationMessages(
 				schema.SystemMessage(systemPrompt),
 				schema.UserMessage(domain.UserQuestionFormatter),
 			)
-			kb, err := u.kbRepo.GetKnowledgeBaseByID(ctx, kbID)
-			if err != nil {
-				return nil, nil, fmt.Errorf("get kb failed: %w", err)
+			kbs := make([]*domain.KnowledgeBase, 0, 1+len(mergeKBIDs))
+			baseURLs := make(map[string]string)
+			for _, id := range lo.Uniq(append([]string{kbID}, mergeKBIDs...)) {
+				kb, err := u.kbRepo.GetKnowledgeBaseByID(ctx, id)
+				if err != nil {
+					if id != kbID {
+						u.logger.Warn("skip merged kb", log.String("kb_id", id), log.Error(err))
+						continue
+					}
+					return nil, nil, fmt.Errorf("get kb failed: %w", err)
+				}
+				kbs = append(kbs, kb)
+				baseURLs[kb.ID] = kb.AccessSettings.BaseURL
 			}
-			rankedNodes, err = u.GetRankNodes(ctx, []string{kb.DatasetID}, question, groupIDs, 0, historyMessages[:len(historyMessages)-1])
+			rankedNodes, err = u.GetRankNodes(ctx, kbs, filter, query, groupIDs, 0, historyMessages[:len(historyMessages)-1])
 			if err != nil {
 				return nil, nil, fmt.Errorf("get rank nodes failed: %w", err)
 			}
-			documents := domain.FormatNodeChunks(rankedNodes, kb.AccessSettings.BaseURL)
+			documents := domain.FormatNodeChunks(rankedNodes, baseURLs)
 			u.logger.Debug("documents", log.String("documents", documents))
 
 			formattedMessages, err := template.Format(ctx, map[string]any{
 				"CurrentDate": time.Now().Format("2006-01-02"),
 				"Question":    question,
 				"Documents":   documents,
+				"Attachments": domain.FormatAttachments(attachments, maxAttachmentPrompt),
 			})
 			if err != nil {
 				return nil, nil, fmt.Errorf("format messages failed: %w", err)
@@ -297,17 +331,36 @@ func (u *LLMUsecase) SplitByTokenLimit(text string, maxTokens int) ([]string, er
 	return result, nil
 }
 
+// GetRankNodes retrieves the documents related to the question, the datasets of all kbs are searched together
 func (u *LLMUsecase) GetRankNodes(
 	ctx context.Context,
-	datasetIDs []string,
+	kbs []*domain.KnowledgeBase,
+	filter *domain.NodeMetaFilter,
 	question string,
 	groupIDs []int,
 	similarityThreshold float64,
 	historyMessages []*schema.Message,
 ) ([]*domain.RankedNodeChunks, error) {
 	var rankedNodes []*domain.RankedNodeChunks
+	// scope retrieval to the published documents matching the filter
+	var scopeDocIDs []string
+	if !filter.IsEmpty() {
+		for _, kb := range kbs {
+			docIDs, err := u.nodeRepo.GetReleasedDocIDsByMetaFilter(ctx, kb.ID, filter)
+			if err != nil {
+				return nil, fmt.Errorf("get doc ids by meta filter failed: %w", err)
+			}
+			scopeDocIDs = append(scopeDocIDs, docIDs...)
+		}
+		if len(scopeDocIDs) == 0 {
+			return rankedNodes, nil
+		}
+	}
+	datasetIDs := lo.Map(kbs, func(kb *domain.KnowledgeBase, _ int) string {
+		return kb.DatasetID
+	})
 	// get related documents from raglite
-	records, err := u.rag.QueryRecords(ctx, datasetIDs, question, groupIDs, similarityThreshold, historyMessages)
+	records, err := u.rag.QueryRecords(ctx, datasetIDs, scopeDocIDs, question, groupIDs, similarityThreshold, historyMessages)
 	if err != nil {
 		return nil, fmt.Errorf("get records from raglite failed: %w", err)
 	}
@@ -328,6 +381,7 @@ func (u *LLMUsecase) GetRankNodes(
 			if nodeChunk, ok := rankedNodesMap[record.DocID]; !ok {
 				if docNode, ok := docIDNode[record.DocID]; ok {
 					rankNodeChunk := &domain.RankedNodeChunks{
+						KBID:          docNode.KBID,
 						NodeID:        docNode.NodeID,
 						NodeName:      docNode.Name,
 						NodeSummary:   docNode.Meta.Summary,
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/store/cache"
+)
+
+const (
+	// loginMaxFailures 连续失败达到该次数后开始锁定
+	loginMaxFailures = 5
+	// loginLockBase 首次锁定时长，之后每次失败翻倍，最长 loginLockMax
+	loginLockBase = time.Minute
+	loginLockMax  = time.Hour
+	// loginFailureWindow 最后一次失败后经过该时长清零失败次数
+	loginFailureWindow = 24 * time.Hour
+)
+
+// LoginLimiter 基于 redis 的登录失败计数，连续失败后逐步延长锁定时间
+type LoginLimiter struct {
+	cache  *cache.Cache
+	logger *log.Logger
+}
+
+func NewLoginLimiter(cache *cache.Cache, logger *log.Logger) *LoginLimiter {
+	return &LoginLimiter{
+		cache:  cache,
+		logger: logger.WithModule("usecase.login_limiter"),
+	}
+}
+
+func loginFailureKey(key string) string {
+	return "login-failures:" + key
+}
+
+func loginLockKey(key string) string {
+	return "login-lock:" + key
+}
+
+func loginLockDuration(failures int64) time.Duration {
+	d := loginLockBase
+	for i := int64(loginMaxFailures); i < failures && d < loginLockMax; i++ {
+		d *= 2
+	}
+	return min(d, loginLockMax)
+}
+
+// Check 锁定期间返回 domain.ErrLoginLocked
+func (l *LoginLimiter) Check(ctx context.Context, key string) error {
+	ttl, err := l.cache.TTL(ctx, loginLockKey(key)).Result()
+	if err != nil {
+		return err
+	}
+	if ttl > 0 {
+		return fmt.Errorf("%w, try again in %s", domain.ErrLoginLocked, ttl.Round(time.Second))
+	}
+	return nil
+}
+
+// Fail 记录一次失败，达到次数后锁定
+func (l *LoginLimiter) Fail(ctx context.Context, key string) error {
+	failures, err := l.cache.Incr(ctx, loginFailureKey(key)).Result()
+	if err != nil {
+		return err
+	}
+	if err := l.cache.Expire(ctx, loginFailureKey(key), loginFailureWindow).Err(); err != nil {
+		return err
+	}
+	if failures < loginMaxFailures {
+		return nil
+	}
+	lock := loginLockDuration(failures)
+	l.logger.Warn("login locked", log.String("key", key), log.Int64("failures", failures), log.String("duration", lock.String()))
+	return l.cache.Set(ctx, loginLockKey(key), failures, lock).Err()
+}
+
+// Reset 登录成功后清零
+func (l *LoginLimiter) Reset(ctx context.Context, key string) error {
+	return l.cache.Del(ctx, loginFailureKey(key), loginLockKey(key)).Err()
+}
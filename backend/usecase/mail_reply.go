@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	v1 "github.com/chaitin/panda-wiki/api/app/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/repo/pg"
+)
+
+// MailReplyUsecase reviews the answers of the mail bot, drafts are only sent once approved
+type MailReplyUsecase struct {
+	repo       *pg.MailReplyRepository
+	appUsecase *AppUsecase
+	logger     *log.Logger
+}
+
+func NewMailReplyUsecase(repo *pg.MailReplyRepository, appUsecase *AppUsecase, logger *log.Logger) *MailReplyUsecase {
+	return &MailReplyUsecase{
+		repo:       repo,
+		appUsecase: appUsecase,
+		logger:     logger.WithModule("usecase.mail_reply"),
+	}
+}
+
+func (u *MailReplyUsecase) GetList(ctx context.Context, req *v1.MailReplyListReq) (*v1.MailReplyListResp, error) {
+	total, replies, err := u.repo.GetList(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPaginatedResult(replies, uint64(total)), nil
+}
+
+// editableStatuses replies not sent yet, failed replies can be retried
+var editableStatuses = []consts.MailReplyStatus{consts.MailReplyStatusPending, consts.MailReplyStatusFailed}
+
+// getEditable returns a reply that has not been sent yet, failed replies can be retried
+func (u *MailReplyUsecase) getEditable(ctx context.Context, kbID, id string) (*domain.MailReply, error) {
+	reply, err := u.repo.GetByID(ctx, kbID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(editableStatuses, reply.Status) {
+		return nil, domain.ErrMailReplyStatusInvalid
+	}
+	return reply, nil
+}
+
+func (u *MailReplyUsecase) Update(ctx context.Context, req *v1.MailReplyUpdateReq, userID string) error {
+	reply, err := u.getEditable(ctx, req.KbId, req.ID)
+	if err != nil {
+		return err
+	}
+	reply.Subject = req.Subject
+	reply.Answer = req.Answer
+	reply.ReviewerID = userID
+	reply.UpdatedAt = time.Now()
+	return u.repo.Update(ctx, reply, editableStatuses...)
+}
+
+// Send approves the draft and delivers it, a failed delivery is recorded and returned.
+// The draft is claimed before delivery so concurrent sends or a discard can not deliver it twice
+func (u *MailReplyUsecase) Send(ctx context.Context, req *v1.MailReplyActionReq, userID string) error {
+	reply, err := u.getEditable(ctx, req.KbId, req.ID)
+	if err != nil {
+		return err
+	}
+	client, ok := u.appUsecase.GetMailBotClient(reply.AppID)
+	if !ok {
+		return domain.ErrMailBotNotRunning
+	}
+	if err := u.repo.Claim(ctx, req.KbId, req.ID, userID); err != nil {
+		return err
+	}
+	reply.ReviewerID = userID
+	// the result is recorded even if the request is canceled
+	sendErr := client.Send(context.WithoutCancel(ctx), reply)
+	if sendErr != nil {
+		u.logger.Error("send mail reply failed", log.String("id", reply.ID), log.Error(sendErr))
+	}
+	if err := u.repo.Update(context.WithoutCancel(ctx), reply, consts.MailReplyStatusSending); err != nil {
+		return err
+	}
+	return sendErr
+}
+
+func (u *MailReplyUsecase) Discard(ctx context.Context, req *v1.MailReplyActionReq, userID string) error {
+	reply, err := u.getEditable(ctx, req.KbId, req.ID)
+	if err != nil {
+		return err
+	}
+	reply.Status = consts.MailReplyStatusDiscarded
+	reply.ReviewerID = userID
+	reply.UpdatedAt = time.Now()
+	return u.repo.Update(ctx, reply, editableStatuses...)
+}
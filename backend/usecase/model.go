@@ -218,6 +218,10 @@ func (u *ModelUsecase) GetChatModel(ctx context.Context) (*domain.Model, error)
 	return u.modelRepo.GetChatModel(ctx)
 }
 
+func (u *ModelUsecase) GetModel(ctx context.Context, id string) (*domain.Model, error) {
+	return u.modelRepo.GetModel(ctx, id)
+}
+
 func (u *ModelUsecase) GetModelByType(ctx context.Context, modelType domain.ModelType) (*domain.Model, error) {
 	return u.modelRepo.GetModelByType(ctx, modelType)
 }
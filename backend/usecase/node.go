@@ -36,6 +36,9 @@ type NodeUsecase struct {
 	logger     *log.Logger
 	s3Client   *s3.MinioClient
 	rAGService rag.RAGService
+
+	linkUsecase     *NodeLinkUsecase
+	templateUsecase *NodeTemplateUsecase
 }
 
 func NewNodeUsecase(
@@ -49,6 +52,8 @@ func NewNodeUsecase(
 	s3Client *s3.MinioClient,
 	modelRepo *pg.ModelRepository,
 	authRepo *pg.AuthRepo,
+	linkUsecase *NodeLinkUsecase,
+	templateUsecase *NodeTemplateUsecase,
 ) *NodeUsecase {
 	return &NodeUsecase{
 		nodeRepo:   nodeRepo,
@@ -61,21 +66,41 @@ func NewNodeUsecase(
 		modelRepo:  modelRepo,
 		logger:     logger.WithModule("usecase.node"),
 		s3Client:   s3Client,
+
+		linkUsecase:     linkUsecase,
+		templateUsecase: templateUsecase,
 	}
 }
 
 const ragSyncChunkSize = 100
 
 func (u *NodeUsecase) Create(ctx context.Context, req *domain.CreateNodeReq, userId string) (string, error) {
+	if req.TemplateID != "" {
+		if err := u.templateUsecase.ApplyTemplate(ctx, req, userId); err != nil {
+			return "", err
+		}
+	}
+	if len(req.Tags) > 0 || len(req.Fields) > 0 {
+		if err := u.validateNodeMeta(ctx, req.KBID, req.Tags, req.Fields); err != nil {
+			return "", err
+		}
+	}
 	nodeID, err := u.nodeRepo.Create(ctx, req, userId)
 	if err != nil {
 		return "", err
 	}
+	if req.Content != "" {
+		u.linkUsecase.IndexNodesQuietly(ctx, req.KBID, []string{nodeID})
+	}
 	return nodeID, nil
 }
 
 func (u *NodeUsecase) GetList(ctx context.Context, req *domain.GetNodeListReq) ([]*domain.NodeListItemResp, error) {
-	nodes, err := u.nodeRepo.GetList(ctx, req)
+	filter, err := domain.ParseNodeMetaFilter(req.Tags, req.Fields)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := u.nodeRepo.GetList(ctx, req, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -121,10 +146,29 @@ func (u *NodeUsecase) NodeAction(ctx context.Context, req *domain.NodeActionReq)
 }
 
 func (u *NodeUsecase) Update(ctx context.Context, req *domain.UpdateNodeReq, userId string) error {
+	if req.Tags != nil || req.Fields != nil {
+		node, err := u.nodeRepo.GetNodeByID(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		tags, fields := node.Meta.Tags, node.Meta.Fields
+		if req.Tags != nil {
+			tags = *req.Tags
+		}
+		if req.Fields != nil {
+			fields = *req.Fields
+		}
+		if err := u.validateNodeMeta(ctx, req.KBID, tags, fields); err != nil {
+			return err
+		}
+	}
 	err := u.nodeRepo.UpdateNodeContent(ctx, req, userId)
 	if err != nil {
 		return err
 	}
+	if req.Content != nil {
+		u.linkUsecase.IndexNodesQuietly(ctx, req.KBID, []string{req.ID})
+	}
 	return nil
 }
 
@@ -139,28 +183,12 @@ func (u *NodeUsecase) ValidateNodePerm(ctx context.Context, kbID, nodeId string,
 	case consts.NodeAccessPermClosed:
 		return &domain.ErrCodePermissionDenied
 	case consts.NodeAccessPermPartial:
-		authGroups, err := u.authRepo.GetAuthGroupWithParentsByAuthId(ctx, authId)
+		nodeIds, err := u.GetNodeIdsByAuthId(ctx, authId, consts.NodePermNameVisitable)
 		if err != nil {
 			return &domain.ErrCodeInternalError
 		}
-
-		authGroupIds := lo.Map(authGroups, func(v domain.AuthGroup, i int) uint {
-			return v.ID
-		})
-
-		nodeGroupIds := make([]string, 0)
-		if len(authGroupIds) != 0 {
-			nodeGroups, err := u.nodeRepo.GetNodeGroupsByGroupIdsPerm(ctx, authGroupIds, consts.NodePermNameVisitable)
-			if err != nil {
-				return &domain.ErrCodeInternalError
-			}
-
-			nodeGroupIds = lo.Map(nodeGroups, func(v domain.NodeAuthGroup, i int) string {
-				return v.NodeID
-			})
-		}
-		if !slices.Contains(nodeGroupIds, nodeId) {
-			u.logger.Error("ValidateNodePerm failed", log.Any("node_group_ids", nodeGroupIds), log.Any("node_id", nodeId))
+		if !slices.Contains(nodeIds, nodeId) {
+			u.logger.Error("ValidateNodePerm failed", log.Any("node_ids", nodeIds), log.Any("node_id", nodeId))
 			return &domain.ErrCodePermissionDenied
 		}
 	default:
@@ -174,8 +202,29 @@ func (u *NodeUsecase) GetNodeReleaseDetailByKBIDAndID(ctx context.Context, kbID,
 	if err != nil {
 		return nil, err
 	}
+	return u.formatNodeDetail(node, format), nil
+}
+
+// GetSharedNodeDetail 分享链接访问的文档，draft 时返回当前编辑的内容
+func (u *NodeUsecase) GetSharedNodeDetail(ctx context.Context, kbID, nodeId, format string, draft bool) (*v1.NodeDetailResp, error) {
+	var (
+		node *v1.NodeDetailResp
+		err  error
+	)
+	if draft {
+		node, err = u.nodeRepo.GetByID(ctx, nodeId, kbID)
+	} else {
+		node, err = u.nodeRepo.GetSharedNodeReleaseDetail(ctx, kbID, nodeId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u.formatNodeDetail(node, format), nil
+}
+
+func (u *NodeUsecase) formatNodeDetail(node *v1.NodeDetailResp, format string) *v1.NodeDetailResp {
 	if node.Meta.ContentType == domain.ContentTypeMD {
-		return node, nil
+		return node
 	}
 	// just for info
 	if format != "raw" {
@@ -183,7 +232,7 @@ func (u *NodeUsecase) GetNodeReleaseDetailByKBIDAndID(ctx context.Context, kbID,
 			node.Content = u.convertMDToHTML(node.Content)
 		}
 	}
-	return node, nil
+	return node
 }
 
 func (u *NodeUsecase) MoveNode(ctx context.Context, req *domain.MoveNodeReq) error {
@@ -290,9 +339,9 @@ func (u *NodeUsecase) convertMDToHTML(mdStr string) string {
 	return string(html)
 }
 
-func (u *NodeUsecase) GetNodeReleaseListByKBID(ctx context.Context, kbID string, authId uint) ([]*domain.ShareNodeListItemResp, error) {
+func (u *NodeUsecase) GetNodeReleaseListByKBID(ctx context.Context, kbID string, authId uint, filter *domain.NodeMetaFilter) ([]*domain.ShareNodeListItemResp, error) {
 
-	nodes, err := u.nodeRepo.GetNodeReleaseListByKBID(ctx, kbID)
+	nodes, err := u.nodeRepo.GetNodeReleaseListByKBID(ctx, kbID, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -340,7 +389,13 @@ func (u *NodeUsecase) GetNodeIdsByAuthId(ctx context.Context, authId uint, PermN
 		})
 	}
 
-	return nodeGroupIds, nil
+	// 通过访问申请单独授权的文档, 只有访问和导航可见权限, 问答权限由 RAG 按用户组过滤
+	grantedNodeIds, err := u.nodeRepo.GetGrantedNodeIDsByAuthId(ctx, authId, PermName)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(nodeGroupIds, grantedNodeIds...), nil
 }
 func (u *NodeUsecase) GetNodePermissionsByID(ctx context.Context, id, kbID string) (*v1.NodePermissionResp, error) {
 	node, err := u.nodeRepo.GetByID(ctx, id, kbID)
@@ -544,3 +599,28 @@ func (u *NodeUsecase) SyncRagNodeStatus(ctx context.Context) error {
 
 	return nil
 }
+
+func (u *NodeUsecase) GetMetaSchema(ctx context.Context, kbID string) (*domain.NodeMetaSchema, error) {
+	return u.nodeRepo.GetNodeMetaSchema(ctx, kbID)
+}
+
+func (u *NodeUsecase) UpdateMetaSchema(ctx context.Context, kbID string, schema *domain.NodeMetaSchema) error {
+	if schema.Tags == nil {
+		schema.Tags = make([]string, 0)
+	}
+	if schema.Fields == nil {
+		schema.Fields = make([]domain.NodeMetaField, 0)
+	}
+	if err := schema.Check(); err != nil {
+		return err
+	}
+	return u.nodeRepo.UpsertNodeMetaSchema(ctx, kbID, schema)
+}
+
+func (u *NodeUsecase) validateNodeMeta(ctx context.Context, kbID string, tags []string, fields map[string]string) error {
+	schema, err := u.nodeRepo.GetNodeMetaSchema(ctx, kbID)
+	if err != nil {
+		return err
+	}
+	return schema.ValidateNodeMeta(tags, fields)
+}
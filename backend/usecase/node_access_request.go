@@ -0,0 +1,339 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+
+	v1 "github.com/chaitin/panda-wiki/api/node/v1"
+	shareV1 "github.com/chaitin/panda-wiki/api/share/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/repo/pg"
+)
+
+var nodePermLabels = map[consts.NodePermName]string{
+	consts.NodePermNameVisitable:  "访问",
+	consts.NodePermNameAnswerable: "问答",
+}
+
+type NodeAccessRequestUsecase struct {
+	repo          *pg.NodeAccessRequestRepository
+	nodeRepo      *pg.NodeRepository
+	authRepo      *pg.AuthRepo
+	kbRepo        *pg.KnowledgeBaseRepository
+	nodeUsecase   *NodeUsecase
+	notifyUsecase *NotifyUsecase
+	logger        *log.Logger
+}
+
+func NewNodeAccessRequestUsecase(
+	repo *pg.NodeAccessRequestRepository,
+	nodeRepo *pg.NodeRepository,
+	authRepo *pg.AuthRepo,
+	kbRepo *pg.KnowledgeBaseRepository,
+	nodeUsecase *NodeUsecase,
+	notifyUsecase *NotifyUsecase,
+	logger *log.Logger,
+) *NodeAccessRequestUsecase {
+	return &NodeAccessRequestUsecase{
+		repo:          repo,
+		nodeRepo:      nodeRepo,
+		authRepo:      authRepo,
+		kbRepo:        kbRepo,
+		nodeUsecase:   nodeUsecase,
+		notifyUsecase: notifyUsecase,
+		logger:        logger.WithModule("usecase.node_access_request"),
+	}
+}
+
+// CreateRequest 读者申请部分开放文档的访问或问答权限，并通知有成员管理能力的用户
+func (u *NodeAccessRequestUsecase) CreateRequest(ctx context.Context, kbID string, authID uint, req *shareV1.AccessRequestCreateReq) (*shareV1.AccessRequestItem, error) {
+	auth, err := u.authRepo.GetAuthById(ctx, kbID, authID)
+	if err != nil {
+		return nil, err
+	}
+	node, err := u.nodeRepo.GetNodeReleaseDetailByKBIDAndID(ctx, kbID, req.NodeID)
+	if err != nil {
+		return nil, err
+	}
+	access := node.Permissions.Visitable
+	if req.Perm == consts.NodePermNameAnswerable {
+		access = node.Permissions.Answerable
+	}
+	switch access {
+	case consts.NodeAccessPermOpen:
+		return nil, domain.ErrAccessRequestNotNeeded
+	case consts.NodeAccessPermPartial:
+	default:
+		return nil, domain.ErrPermissionDenied
+	}
+	nodeIDs, err := u.nodeUsecase.GetNodeIdsByAuthId(ctx, authID, req.Perm)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(nodeIDs, req.NodeID) {
+		return nil, domain.ErrAccessRequestNotNeeded
+	}
+	pending, err := u.repo.HasPendingRequest(ctx, req.NodeID, authID, req.Perm)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, domain.ErrAccessRequestPending
+	}
+
+	request := &domain.NodeAccessRequest{
+		ID:        uuid.New().String(),
+		KBID:      kbID,
+		NodeID:    req.NodeID,
+		AuthID:    authID,
+		Perm:      req.Perm,
+		Reason:    strings.TrimSpace(req.Reason),
+		Status:    consts.NodeAccessRequestStatusPending,
+		CreatedAt: time.Now(),
+	}
+	if err := u.repo.CreateRequest(ctx, request); err != nil {
+		return nil, err
+	}
+	// 读者无需等待邮件发送
+	go u.notifyManagers(context.WithoutCancel(ctx), request, node.Name, auth.UserInfo.Username)
+	return shareRequestItem(request, node.Name), nil
+}
+
+func (u *NodeAccessRequestUsecase) notifyManagers(ctx context.Context, request *domain.NodeAccessRequest, nodeName, authName string) {
+	kb, err := u.kbRepo.GetKnowledgeBaseByID(ctx, request.KBID)
+	if err != nil {
+		u.logger.Warn("get kb for access request notify failed", log.String("kb_id", request.KBID), log.Error(err))
+		return
+	}
+	userIDs, err := u.kbRepo.GetKBUserIDsByCapability(ctx, request.KBID, consts.KBCapabilityUserManage)
+	if err != nil {
+		u.logger.Warn("get kb managers failed", log.String("kb_id", request.KBID), log.Error(err))
+		return
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "读者 **%s** 申请%s文档 %s。\n", authName, nodePermLabels[request.Perm], nodeLink(kb, request.NodeID, nodeName))
+	if request.Reason != "" {
+		fmt.Fprintf(&sb, "\n申请理由: %s\n", request.Reason)
+	}
+	sb.WriteString("\n请在管理后台的访问申请中处理。")
+	_ = u.notifyUsecase.NotifyUsers(ctx, request.KBID, userIDs, &domain.NotifyMessage{
+		Title:   fmt.Sprintf("【%s】读者申请%s文档「%s」", kb.Name, nodePermLabels[request.Perm], nodeName),
+		Content: sb.String(),
+	}, false)
+}
+
+func nodeLink(kb *domain.KnowledgeBase, nodeID, name string) string {
+	if kb.AccessSettings.BaseURL == "" {
+		return fmt.Sprintf("「%s」", name)
+	}
+	return fmt.Sprintf("[%s](%s/node/%s)", name, kb.AccessSettings.BaseURL, nodeID)
+}
+
+// ListMyRequests 读者自己在知识库内的申请
+func (u *NodeAccessRequestUsecase) ListMyRequests(ctx context.Context, kbID string, authID uint) ([]*shareV1.AccessRequestItem, error) {
+	requests, err := u.repo.ListRequestsByAuthID(ctx, kbID, authID)
+	if err != nil {
+		return nil, err
+	}
+	nodeNames, err := u.nodeRepo.GetNodeNameByNodeIDs(ctx, lo.Uniq(lo.Map(requests, func(r domain.NodeAccessRequest, _ int) string { return r.NodeID })))
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*shareV1.AccessRequestItem, 0, len(requests))
+	for i := range requests {
+		items = append(items, shareRequestItem(&requests[i], nodeNames[requests[i].NodeID]))
+	}
+	return items, nil
+}
+
+func shareRequestItem(request *domain.NodeAccessRequest, nodeName string) *shareV1.AccessRequestItem {
+	return &shareV1.AccessRequestItem{
+		ID:            request.ID,
+		NodeID:        request.NodeID,
+		NodeName:      nodeName,
+		Perm:          request.Perm,
+		Reason:        request.Reason,
+		Status:        request.Status,
+		ReviewComment: request.ReviewComment,
+		CreatedAt:     request.CreatedAt,
+		ReviewedAt:    request.ReviewedAt,
+	}
+}
+
+func (u *NodeAccessRequestUsecase) ListRequests(ctx context.Context, req *v1.AccessRequestListReq) (*v1.AccessRequestListResp, error) {
+	requests, total, err := u.repo.ListRequests(ctx, req.KbId, req.Status, req.Pager)
+	if err != nil {
+		return nil, err
+	}
+	nodeNames, err := u.nodeRepo.GetNodeNameByNodeIDs(ctx, lo.Uniq(lo.Map(requests, func(r domain.NodeAccessRequest, _ int) string { return r.NodeID })))
+	if err != nil {
+		return nil, err
+	}
+	auths, err := u.authRepo.GetAuthUserinfoByIDs(ctx, lo.Uniq(lo.Map(requests, func(r domain.NodeAccessRequest, _ int) uint { return r.AuthID })))
+	if err != nil {
+		return nil, err
+	}
+	items := make([]v1.AccessRequestItem, 0, len(requests))
+	for _, request := range requests {
+		item := v1.AccessRequestItem{
+			NodeAccessRequest: request,
+			NodeName:          nodeNames[request.NodeID],
+		}
+		if auth, ok := auths[request.AuthID]; ok {
+			item.AuthName = auth.AuthUserInfo.Username
+			item.AuthAvatarUrl = auth.AuthUserInfo.AvatarUrl
+		}
+		items = append(items, item)
+	}
+	return domain.NewPaginatedResult(items, total), nil
+}
+
+func (u *NodeAccessRequestUsecase) GetRequest(ctx context.Context, kbID, id string) (*domain.NodeAccessRequest, error) {
+	return u.repo.GetRequest(ctx, kbID, id)
+}
+
+// Decide 批准时将读者加入用户组，或单独授权该文档的访问和导航可见，处理结果通知读者
+func (u *NodeAccessRequestUsecase) Decide(ctx context.Context, req *v1.AccessRequestDecideReq, userID string) (*domain.NodeAccessRequest, error) {
+	request, err := u.repo.GetRequest(ctx, req.KbId, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != consts.NodeAccessRequestStatusPending {
+		return nil, domain.ErrAccessRequestStatusInvalid
+	}
+
+	now := time.Now()
+	request.ReviewerID = userID
+	request.ReviewComment = strings.TrimSpace(req.Comment)
+	request.ReviewedAt = &now
+	var grants []domain.NodeAuthGrant
+	switch req.Action {
+	case consts.NodeAccessRequestActionApprove:
+		request.Status = consts.NodeAccessRequestStatusApproved
+		request.GrantType = req.GrantType
+		switch req.GrantType {
+		case consts.NodeAccessGrantTypeAuthGroup:
+			group, err := u.authRepo.GetAuthGroup(ctx, req.KbId, req.AuthGroupID)
+			if err != nil {
+				return nil, err
+			}
+			// 同步的用户组成员会在下次同步时被覆盖
+			if group.SyncId != "" {
+				return nil, domain.ErrAuthGroupSynced
+			}
+			request.AuthGroupID = group.ID
+		case consts.NodeAccessGrantTypeNode:
+			// 问答检索在 RAG 服务中按写入文档的用户组 ID 过滤, 只认用户组, 不认单个读者。
+			// 单独授权的可问答权限不会生效, 只能拒绝并提示管理者改为加入用户组, 不能静默批准
+			if request.Perm != consts.NodePermNameVisitable {
+				return nil, domain.ErrAccessGrantRequiresGroup
+			}
+			for _, perm := range []consts.NodePermName{consts.NodePermNameVisitable, consts.NodePermNameVisible} {
+				grants = append(grants, domain.NodeAuthGrant{
+					KBID:      request.KBID,
+					NodeID:    request.NodeID,
+					AuthID:    request.AuthID,
+					Perm:      perm,
+					RequestID: request.ID,
+					CreatedBy: userID,
+					CreatedAt: now,
+				})
+			}
+		default:
+			return nil, domain.ErrAccessRequestStatusInvalid
+		}
+	case consts.NodeAccessRequestActionReject:
+		request.Status = consts.NodeAccessRequestStatusRejected
+	default:
+		return nil, domain.ErrAccessRequestStatusInvalid
+	}
+
+	if err := u.repo.DecideRequest(ctx, request, grants); err != nil {
+		return nil, err
+	}
+	go u.notifyReader(context.WithoutCancel(ctx), request)
+	return request, nil
+}
+
+func (u *NodeAccessRequestUsecase) notifyReader(ctx context.Context, request *domain.NodeAccessRequest) {
+	auth, err := u.authRepo.GetAuthById(ctx, request.KBID, request.AuthID)
+	if err != nil {
+		u.logger.Warn("get auth for access request notify failed", log.Any("auth_id", request.AuthID), log.Error(err))
+		return
+	}
+	if auth.UserInfo.Email == "" {
+		return
+	}
+	kb, err := u.kbRepo.GetKnowledgeBaseByID(ctx, request.KBID)
+	if err != nil {
+		u.logger.Warn("get kb for access request notify failed", log.String("kb_id", request.KBID), log.Error(err))
+		return
+	}
+	nodeNames, err := u.nodeRepo.GetNodeNameByNodeIDs(ctx, []string{request.NodeID})
+	if err != nil {
+		u.logger.Warn("get node for access request notify failed", log.String("node_id", request.NodeID), log.Error(err))
+		return
+	}
+	nodeName := nodeNames[request.NodeID]
+
+	result := "已通过"
+	if request.Status == consts.NodeAccessRequestStatusRejected {
+		result = "未通过"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "你申请%s文档 %s 的请求%s。\n", nodePermLabels[request.Perm], nodeLink(kb, request.NodeID, nodeName), result)
+	if request.ReviewComment != "" {
+		fmt.Fprintf(&sb, "\n处理意见: %s\n", request.ReviewComment)
+	}
+	_ = u.notifyUsecase.Notify(ctx, request.KBID, []*domain.NotifyRecipient{{
+		Account: auth.UserInfo.Username,
+		Email:   auth.UserInfo.Email,
+	}}, &domain.NotifyMessage{
+		Title:   fmt.Sprintf("【%s】文档「%s」的%s申请%s", kb.Name, nodeName, nodePermLabels[request.Perm], result),
+		Content: sb.String(),
+	}, false)
+}
+
+func (u *NodeAccessRequestUsecase) ListGrants(ctx context.Context, req *v1.AccessGrantListReq) ([]v1.AccessGrantItem, error) {
+	grants, err := u.repo.ListGrants(ctx, req.KbId, req.NodeID)
+	if err != nil {
+		return nil, err
+	}
+	nodeNames, err := u.nodeRepo.GetNodeNameByNodeIDs(ctx, lo.Uniq(lo.Map(grants, func(g domain.NodeAuthGrant, _ int) string { return g.NodeID })))
+	if err != nil {
+		return nil, err
+	}
+	auths, err := u.authRepo.GetAuthUserinfoByIDs(ctx, lo.Uniq(lo.Map(grants, func(g domain.NodeAuthGrant, _ int) uint { return g.AuthID })))
+	if err != nil {
+		return nil, err
+	}
+	items := make([]v1.AccessGrantItem, 0, len(grants))
+	for _, grant := range grants {
+		item := v1.AccessGrantItem{
+			NodeAuthGrant: grant,
+			NodeName:      nodeNames[grant.NodeID],
+		}
+		if auth, ok := auths[grant.AuthID]; ok {
+			item.AuthName = auth.AuthUserInfo.Username
+			item.AuthAvatarUrl = auth.AuthUserInfo.AvatarUrl
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (u *NodeAccessRequestUsecase) GetGrant(ctx context.Context, kbID string, id uint) (*domain.NodeAuthGrant, error) {
+	return u.repo.GetGrant(ctx, kbID, id)
+}
+
+func (u *NodeAccessRequestUsecase) DeleteGrant(ctx context.Context, req *v1.AccessGrantDeleteReq) error {
+	return u.repo.DeleteGrant(ctx, req.KbId, req.ID)
+}
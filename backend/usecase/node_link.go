@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	v1 "github.com/chaitin/panda-wiki/api/node/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/linkcheck"
+	"github.com/chaitin/panda-wiki/repo/pg"
+)
+
+// externalLinkCheckInterval external links are rechecked after this interval
+const externalLinkCheckInterval = 24 * time.Hour
+
+var nodePathRe = regexp.MustCompile(`^/node/([0-9A-Za-z-]+)/?$`)
+
+type NodeLinkUsecase struct {
+	linkRepo *pg.NodeLinkRepository
+	kbRepo   *pg.KnowledgeBaseRepository
+	checker  *linkcheck.Checker
+	logger   *log.Logger
+}
+
+func NewNodeLinkUsecase(
+	linkRepo *pg.NodeLinkRepository,
+	kbRepo *pg.KnowledgeBaseRepository,
+	logger *log.Logger,
+) *NodeLinkUsecase {
+	return &NodeLinkUsecase{
+		linkRepo: linkRepo,
+		kbRepo:   kbRepo,
+		checker:  linkcheck.NewChecker(nil, 0),
+		logger:   logger.WithModule("usecase.node_link"),
+	}
+}
+
+// IndexNodes rebuilds the outbound link index of the given documents,
+// all documents of the kb when nodeIDs is empty
+func (u *NodeLinkUsecase) IndexNodes(ctx context.Context, kbID string, nodeIDs []string) error {
+	kb, err := u.kbRepo.GetKnowledgeBaseByID(ctx, kbID)
+	if err != nil {
+		return err
+	}
+	nodes, err := u.linkRepo.GetNodesForIndex(ctx, kbID, nodeIDs)
+	if err != nil {
+		return err
+	}
+	hosts := kbHosts(kb)
+	for _, node := range nodes {
+		links := make([]*domain.NodeLink, 0)
+		for _, link := range linkcheck.ExtractLinks(node.Content) {
+			linkType, targetID, ok := classifyLink(link, hosts)
+			if !ok {
+				continue
+			}
+			links = append(links, &domain.NodeLink{
+				KBID:         kbID,
+				SourceNodeID: node.ID,
+				URL:          link,
+				Type:         linkType,
+				TargetNodeID: targetID,
+			})
+		}
+		if err := u.linkRepo.ReplaceNodeLinks(ctx, kbID, node.ID, links); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IndexNodesQuietly indexes links on node save and release, failures must not block editing
+func (u *NodeLinkUsecase) IndexNodesQuietly(ctx context.Context, kbID string, nodeIDs []string) {
+	if err := u.IndexNodes(ctx, kbID, nodeIDs); err != nil {
+		u.logger.Warn("index node links failed", log.String("kb_id", kbID), log.Any("node_ids", nodeIDs), log.Error(err))
+	}
+}
+
+func (u *NodeLinkUsecase) GetBrokenLinks(ctx context.Context, req *v1.BrokenLinkListReq) (*v1.BrokenLinkListResp, error) {
+	total, items, err := u.linkRepo.GetBrokenLinks(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPaginatedResult(items, uint64(total)), nil
+}
+
+func (u *NodeLinkUsecase) GetBacklinks(ctx context.Context, req *v1.NodeBacklinksReq) (*v1.NodeBacklinksResp, error) {
+	items, err := u.linkRepo.GetBacklinks(ctx, req.KbId, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.NodeBacklinksResp{Backlinks: items}, nil
+}
+
+// CheckKBLinks reindexes all documents of the kb and checks external links
+// that have not been checked recently
+func (u *NodeLinkUsecase) CheckKBLinks(ctx context.Context, kbID string) error {
+	if err := u.linkRepo.DeleteOrphanLinks(ctx, kbID); err != nil {
+		return err
+	}
+	if err := u.IndexNodes(ctx, kbID, nil); err != nil {
+		return err
+	}
+	urls, err := u.linkRepo.GetExternalURLsToCheck(ctx, kbID, time.Now().Add(-externalLinkCheckInterval))
+	if err != nil {
+		return err
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+	now := time.Now()
+	results := u.checker.CheckAll(ctx, urls)
+	checks := make([]*domain.ExternalLinkCheck, 0, len(results))
+	for _, res := range results {
+		checks = append(checks, &domain.ExternalLinkCheck{
+			URL:        res.URL,
+			StatusCode: res.StatusCode,
+			Error:      res.Error,
+			Broken:     res.Broken,
+			CheckedAt:  now,
+		})
+	}
+	return u.linkRepo.UpsertExternalLinkChecks(ctx, checks)
+}
+
+// CheckAllLinks runs CheckKBLinks for every knowledge base
+func (u *NodeLinkUsecase) CheckAllLinks(ctx context.Context) error {
+	kbIDs, err := u.kbRepo.GetKnowledgeBaseIds(ctx)
+	if err != nil {
+		return err
+	}
+	for _, kbID := range kbIDs {
+		if err := u.CheckKBLinks(ctx, kbID); err != nil {
+			u.logger.Error("check kb links failed", log.String("kb_id", kbID), log.Error(err))
+		}
+	}
+	return nil
+}
+
+func kbHosts(kb *domain.KnowledgeBase) []string {
+	hosts := slices.Clone(kb.AccessSettings.Hosts)
+	if kb.AccessSettings.BaseURL != "" {
+		if u, err := url.Parse(kb.AccessSettings.BaseURL); err == nil && u.Hostname() != "" {
+			hosts = append(hosts, u.Hostname())
+		}
+	}
+	return hosts
+}
+
+// classifyLink resolves /node/<id> links, relative or on one of the kb hosts,
+// as internal links, and links to other hosts as external links
+func classifyLink(link string, hosts []string) (consts.NodeLinkType, string, bool) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", "", false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "" && scheme != "http" && scheme != "https" {
+		return "", "", false
+	}
+	if u.Host == "" || slices.Contains(hosts, u.Hostname()) {
+		if m := nodePathRe.FindStringSubmatch(u.Path); m != nil {
+			return consts.NodeLinkTypeInternal, m[1], true
+		}
+		// other pages and files of the wiki itself are not indexed
+		return "", "", false
+	}
+	return consts.NodeLinkTypeExternal, "", true
+}
@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	v1 "github.com/chaitin/panda-wiki/api/node/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/repo/pg"
+)
+
+type NodeReviewUsecase struct {
+	reviewRepo     *pg.NodeReviewRepository
+	nodeRepo       *pg.NodeRepository
+	userAccessRepo *pg.UserAccessRepository
+	logger         *log.Logger
+}
+
+func NewNodeReviewUsecase(
+	reviewRepo *pg.NodeReviewRepository,
+	nodeRepo *pg.NodeRepository,
+	userAccessRepo *pg.UserAccessRepository,
+	logger *log.Logger,
+) *NodeReviewUsecase {
+	return &NodeReviewUsecase{
+		reviewRepo:     reviewRepo,
+		nodeRepo:       nodeRepo,
+		userAccessRepo: userAccessRepo,
+		logger:         logger.WithModule("usecase.node_review"),
+	}
+}
+
+func (u *NodeReviewUsecase) GetPolicy(ctx context.Context, kbID string) (*v1.NodeReviewPolicyResp, error) {
+	policy, err := u.reviewRepo.GetPolicy(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.NodeReviewPolicyResp{
+		Enabled:     policy.Enabled,
+		ReviewerIDs: policy.ReviewerIDs,
+	}, nil
+}
+
+func (u *NodeReviewUsecase) UpdatePolicy(ctx context.Context, req *v1.NodeReviewPolicyUpdateReq) error {
+	reviewerIDs := req.ReviewerIDs
+	if reviewerIDs == nil {
+		reviewerIDs = make([]string, 0)
+	}
+	return u.reviewRepo.UpsertPolicy(ctx, req.KbId, &domain.NodeReviewPolicy{
+		Enabled:     req.Enabled,
+		ReviewerIDs: reviewerIDs,
+	})
+}
+
+func (u *NodeReviewUsecase) Submit(ctx context.Context, req *v1.NodeReviewSubmitReq, userID string) error {
+	now := time.Now()
+	reviews := make([]*domain.NodeReview, 0, len(req.IDs))
+	logs := make([]*domain.NodeReviewLog, 0, len(req.IDs))
+	for _, id := range slices.Compact(slices.Sorted(slices.Values(req.IDs))) {
+		node, err := u.nodeRepo.GetByID(ctx, id, req.KbId)
+		if err != nil {
+			return fmt.Errorf("get node %s failed: %w", id, err)
+		}
+		// folders carry no content, they are published without review
+		if node.Type == domain.NodeTypeFolder {
+			continue
+		}
+		reviews = append(reviews, &domain.NodeReview{
+			ID:          uuid.New().String(),
+			KBID:        req.KbId,
+			NodeID:      id,
+			Status:      consts.NodeReviewStatusPending,
+			SubmitterID: userID,
+			Comment:     req.Comment,
+			SubmittedAt: now,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+		logs = append(logs, &domain.NodeReviewLog{
+			KBID:      req.KbId,
+			NodeID:    id,
+			UserID:    userID,
+			Action:    consts.NodeReviewActionSubmit,
+			Comment:   req.Comment,
+			CreatedAt: now,
+		})
+	}
+	if len(reviews) == 0 {
+		return nil
+	}
+	return u.reviewRepo.SubmitReviews(ctx, reviews, logs)
+}
+
+func (u *NodeReviewUsecase) Decide(ctx context.Context, req *v1.NodeReviewDecideReq, authInfo *domain.CtxAuthInfo) error {
+	canReview, err := u.canReview(ctx, req.KbId, authInfo)
+	if err != nil {
+		return err
+	}
+	if !canReview {
+		return domain.ErrPermissionDenied
+	}
+
+	review, err := u.reviewRepo.GetReviewByNodeID(ctx, req.KbId, req.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrNodeReviewStatusInvalid
+		}
+		return err
+	}
+	if err := checkDecidable(review, authInfo.UserId); err != nil {
+		return err
+	}
+	node, err := u.nodeRepo.GetNodeByID(ctx, review.NodeID)
+	if err != nil {
+		return err
+	}
+	if review.IsOutdated(node.EditTime) {
+		return domain.ErrNodeReviewOutdated
+	}
+
+	now := time.Now()
+	review.ReviewerID = authInfo.UserId
+	review.Comment = req.Comment
+	review.ReviewedAt = &now
+	switch req.Action {
+	case consts.NodeReviewActionApprove:
+		review.Status = consts.NodeReviewStatusApproved
+	case consts.NodeReviewActionRequestChanges:
+		review.Status = consts.NodeReviewStatusChangesRequested
+	default:
+		return domain.ErrNodeReviewStatusInvalid
+	}
+
+	return u.reviewRepo.UpdateReviewDecision(ctx, review, &domain.NodeReviewLog{
+		KBID:      req.KbId,
+		NodeID:    req.ID,
+		UserID:    authInfo.UserId,
+		Action:    req.Action,
+		Comment:   req.Comment,
+		CreatedAt: now,
+	})
+}
+
+// checkDecidable 只能处理待审核的提交, 提交人不能审核自己的提交
+func checkDecidable(review *domain.NodeReview, userID string) error {
+	if review.Status != consts.NodeReviewStatusPending {
+		return domain.ErrNodeReviewStatusInvalid
+	}
+	if review.SubmitterID == userID {
+		return domain.ErrNodeReviewSelfDecide
+	}
+	return nil
+}
+
+// canReview 审核人列表为空时, 拥有审核能力的用户均可审核
+func (u *NodeReviewUsecase) canReview(ctx context.Context, kbID string, authInfo *domain.CtxAuthInfo) (bool, error) {
+	policy, err := u.reviewRepo.GetPolicy(ctx, kbID)
+	if err != nil {
+		return false, err
+	}
+	if len(policy.ReviewerIDs) > 0 {
+		return slices.Contains(policy.ReviewerIDs, authInfo.UserId), nil
+	}
+	if authInfo.IsToken {
+		return slices.Contains(authInfo.Permission.Capabilities(), consts.KBCapabilityNodeReview), nil
+	}
+	capabilities, err := u.userAccessRepo.GetKBCapabilities(kbID, authInfo.UserId)
+	if err != nil {
+		u.logger.Warn("validate reviewer kb perm failed", log.String("kb_id", kbID), log.String("user_id", authInfo.UserId), log.Error(err))
+		return false, nil
+	}
+	return slices.Contains(capabilities, consts.KBCapabilityNodeReview), nil
+}
+
+func (u *NodeReviewUsecase) GetQueue(ctx context.Context, req *v1.NodeReviewQueueReq) (*v1.NodeReviewQueueResp, error) {
+	total, items, err := u.reviewRepo.GetReviewQueue(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPaginatedResult(items, uint64(total)), nil
+}
+
+func (u *NodeReviewUsecase) GetDetail(ctx context.Context, req *v1.NodeReviewDetailReq) (*v1.NodeReviewDetailResp, error) {
+	node, err := u.nodeRepo.GetNodeByID(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if node.KBID != req.KbId {
+		return nil, gorm.ErrRecordNotFound
+	}
+	logs, err := u.reviewRepo.GetReviewLogs(ctx, req.KbId, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &v1.NodeReviewDetailResp{
+		NodeID: node.ID,
+		Status: consts.NodeReviewStatusNone,
+		Logs:   logs,
+	}
+
+	review, err := u.reviewRepo.GetReviewByNodeID(ctx, req.KbId, req.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return resp, nil
+		}
+		return nil, err
+	}
+	resp.Status = review.Status
+	resp.Outdated = review.IsOutdated(node.EditTime)
+	resp.SubmitterID = review.SubmitterID
+	resp.ReviewerID = review.ReviewerID
+	resp.Comment = review.Comment
+	resp.SubmittedAt = &review.SubmittedAt
+	resp.ReviewedAt = review.ReviewedAt
+	return resp, nil
+}
@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+func TestCheckDecidable(t *testing.T) {
+	tests := []struct {
+		name   string
+		review domain.NodeReview
+		userID string
+		want   error
+	}{
+		{"reviewer", domain.NodeReview{Status: consts.NodeReviewStatusPending, SubmitterID: "editor"}, "reviewer", nil},
+		{"submitter", domain.NodeReview{Status: consts.NodeReviewStatusPending, SubmitterID: "editor"}, "editor", domain.ErrPermissionDenied},
+		{"decided", domain.NodeReview{Status: consts.NodeReviewStatusApproved, SubmitterID: "editor"}, "reviewer", domain.ErrNodeReviewStatusInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkDecidable(&tt.review, tt.userID)
+			if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("checkDecidable() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	v1 "github.com/chaitin/panda-wiki/api/node/v1"
+	shareV1 "github.com/chaitin/panda-wiki/api/share/v1"
+	"github.com/chaitin/panda-wiki/config"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/sharetoken"
+	"github.com/chaitin/panda-wiki/repo/pg"
+)
+
+// shareLinkAccessTTL 输入密码后的访问凭证有效期，不超过链接本身的有效期
+const shareLinkAccessTTL = 12 * time.Hour
+
+type NodeShareLinkUsecase struct {
+	repo        *pg.NodeShareLinkRepository
+	nodeRepo    *pg.NodeRepository
+	nodeUsecase *NodeUsecase
+	limiter     *LoginLimiter
+	secret      []byte
+	logger      *log.Logger
+}
+
+func NewNodeShareLinkUsecase(
+	repo *pg.NodeShareLinkRepository,
+	nodeRepo *pg.NodeRepository,
+	nodeUsecase *NodeUsecase,
+	limiter *LoginLimiter,
+	config *config.Config,
+	logger *log.Logger,
+) *NodeShareLinkUsecase {
+	return &NodeShareLinkUsecase{
+		repo:        repo,
+		nodeRepo:    nodeRepo,
+		nodeUsecase: nodeUsecase,
+		limiter:     limiter,
+		secret:      []byte(config.Auth.JWT.Secret),
+		logger:      logger.WithModule("usecase.node_share_link"),
+	}
+}
+
+func (u *NodeShareLinkUsecase) CreateLink(ctx context.Context, req *v1.ShareLinkCreateReq, userID string) (*v1.ShareLinkItem, error) {
+	if _, err := u.nodeRepo.GetByID(ctx, req.NodeID, req.KbId); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	link := &domain.NodeShareLink{
+		ID:     uuid.New().String(),
+		KBID:   req.KbId,
+		NodeID: req.NodeID,
+		Draft:  req.Draft,
+		// token 中的过期时间精确到秒
+		ExpiresAt: now.Add(time.Duration(req.ExpiresIn) * time.Hour).Truncate(time.Second),
+		CreatedBy: userID,
+		CreatedAt: now,
+	}
+	if req.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		link.PasswordHash = string(hash)
+	}
+	if err := u.repo.CreateLink(ctx, link); err != nil {
+		return nil, err
+	}
+	return u.linkItem(link, now), nil
+}
+
+func (u *NodeShareLinkUsecase) ListLinks(ctx context.Context, req *v1.ShareLinkListReq) ([]*v1.ShareLinkItem, error) {
+	links, err := u.repo.ListLinks(ctx, req.KbId, req.NodeID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	items := make([]*v1.ShareLinkItem, 0, len(links))
+	for i := range links {
+		items = append(items, u.linkItem(&links[i], now))
+	}
+	return items, nil
+}
+
+func (u *NodeShareLinkUsecase) GetLink(ctx context.Context, kbID, id string) (*domain.NodeShareLink, error) {
+	link, err := u.repo.GetLink(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if link.KBID != kbID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return link, nil
+}
+
+func (u *NodeShareLinkUsecase) RevokeLink(ctx context.Context, req *v1.ShareLinkRevokeReq) error {
+	return u.repo.RevokeLink(ctx, req.KbId, req.ID)
+}
+
+func (u *NodeShareLinkUsecase) linkItem(link *domain.NodeShareLink, now time.Time) *v1.ShareLinkItem {
+	return &v1.ShareLinkItem{
+		NodeShareLink: *link,
+		HasPassword:   link.PasswordHash != "",
+		Token:         sharetoken.Sign(u.secret, link.ID, link.ExpiresAt),
+		Active:        link.Active(now),
+	}
+}
+
+// resolve 校验 token 签名、所属知识库，以及链接未被撤销、未过期
+func (u *NodeShareLinkUsecase) resolve(ctx context.Context, kbID, token string) (*domain.NodeShareLink, error) {
+	now := time.Now()
+	id, expiresAt, err := sharetoken.Verify(u.secret, token, now)
+	if err != nil {
+		return nil, domain.ErrShareLinkInvalid
+	}
+	link, err := u.repo.GetLink(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrShareLinkInvalid
+		}
+		return nil, err
+	}
+	if link.KBID != kbID || link.ExpiresAt.Unix() != expiresAt.Unix() || !link.Active(now) {
+		return nil, domain.ErrShareLinkInvalid
+	}
+	return link, nil
+}
+
+// accessSubject 包含密码哈希的摘要，修改密码后旧凭证失效
+func accessSubject(link *domain.NodeShareLink) string {
+	sum := sha256.Sum256([]byte(link.PasswordHash))
+	return "access:" + link.ID + ":" + hex.EncodeToString(sum[:8])
+}
+
+func (u *NodeShareLinkUsecase) checkAccess(link *domain.NodeShareLink, access string) error {
+	if link.PasswordHash == "" {
+		return nil
+	}
+	if access == "" {
+		return domain.ErrShareLinkPasswordRequired
+	}
+	subject, _, err := sharetoken.Verify(u.secret, access, time.Now())
+	if err != nil || subject != accessSubject(link) {
+		return domain.ErrShareLinkPasswordRequired
+	}
+	return nil
+}
+
+func (u *NodeShareLinkUsecase) GetLinkInfo(ctx context.Context, kbID, token string) (*shareV1.ShareLinkInfoResp, error) {
+	link, err := u.resolve(ctx, kbID, token)
+	if err != nil {
+		return nil, err
+	}
+	node, err := u.nodeRepo.GetNodeByID(ctx, link.NodeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrShareLinkInvalid
+		}
+		return nil, err
+	}
+	return &shareV1.ShareLinkInfoResp{
+		KBID:         link.KBID,
+		NodeID:       link.NodeID,
+		Name:         node.Name,
+		Draft:        link.Draft,
+		NeedPassword: link.PasswordHash != "",
+		ExpiresAt:    link.ExpiresAt,
+	}, nil
+}
+
+// Authenticate 校验分享密码，按链接和来源 IP 限制失败次数
+func (u *NodeShareLinkUsecase) Authenticate(ctx context.Context, kbID string, req *shareV1.ShareLinkAuthReq, ip string) (*shareV1.ShareLinkAuthResp, error) {
+	link, err := u.resolve(ctx, kbID, req.Token)
+	if err != nil {
+		return nil, err
+	}
+	if link.PasswordHash == "" {
+		return &shareV1.ShareLinkAuthResp{}, nil
+	}
+	limitKey := "share_link:" + link.ID + ":" + ip
+	if err := u.limiter.Check(ctx, limitKey); err != nil {
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(req.Password)); err != nil {
+		if err := u.limiter.Fail(ctx, limitKey); err != nil {
+			u.logger.Error("record share link failure failed", log.Error(err))
+		}
+		return nil, domain.ErrShareLinkPasswordIncorrect
+	}
+	if err := u.limiter.Reset(ctx, limitKey); err != nil {
+		u.logger.Error("reset share link failures failed", log.Error(err))
+	}
+	expiresAt := time.Now().Add(shareLinkAccessTTL)
+	if link.ExpiresAt.Before(expiresAt) {
+		expiresAt = link.ExpiresAt
+	}
+	return &shareV1.ShareLinkAuthResp{
+		Access: sharetoken.Sign(u.secret, accessSubject(link), expiresAt),
+	}, nil
+}
+
+func (u *NodeShareLinkUsecase) GetNodeList(ctx context.Context, kbID, token, access string) ([]*domain.ShareNodeListItemResp, error) {
+	link, err := u.resolve(ctx, kbID, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkAccess(link, access); err != nil {
+		return nil, err
+	}
+	ids := u.nodeRepo.GetSubtreeNodeIDs(ctx, link.KBID, link.NodeID)
+	if link.Draft {
+		return u.nodeRepo.GetDraftNodeList(ctx, link.KBID, ids)
+	}
+	return u.nodeRepo.GetSharedNodeReleaseList(ctx, link.KBID, ids)
+}
+
+// GetNodeDetail 只允许访问链接对应节点及其子节点，每次访问计数
+func (u *NodeShareLinkUsecase) GetNodeDetail(ctx context.Context, kbID string, req *shareV1.ShareLinkNodeDetailReq, access string) (*v1.NodeDetailResp, error) {
+	link, err := u.resolve(ctx, kbID, req.Token)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkAccess(link, access); err != nil {
+		return nil, err
+	}
+	if req.ID != link.NodeID && !slices.Contains(u.nodeRepo.GetSubtreeNodeIDs(ctx, link.KBID, link.NodeID), req.ID) {
+		return nil, domain.ErrPermissionDenied
+	}
+	node, err := u.nodeUsecase.GetSharedNodeDetail(ctx, link.KBID, req.ID, req.Format, link.Draft)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo.IncrViewCount(ctx, link.ID); err != nil {
+		u.logger.Error("increase share link view count failed", log.String("id", link.ID), log.Error(err))
+	}
+	return node, nil
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+
+	v1 "github.com/chaitin/panda-wiki/api/node/v1"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/repo/pg"
+)
+
+// staleDislikeWindow only dislikes within this window mark cited documents as stale
+const staleDislikeWindow = 30 * 24 * time.Hour
+
+type NodeStaleUsecase struct {
+	nodeRepo      *pg.NodeRepository
+	kbRepo        *pg.KnowledgeBaseRepository
+	notifyUsecase *NotifyUsecase
+	logger        *log.Logger
+}
+
+func NewNodeStaleUsecase(
+	nodeRepo *pg.NodeRepository,
+	kbRepo *pg.KnowledgeBaseRepository,
+	notifyUsecase *NotifyUsecase,
+	logger *log.Logger,
+) *NodeStaleUsecase {
+	return &NodeStaleUsecase{
+		nodeRepo:      nodeRepo,
+		kbRepo:        kbRepo,
+		notifyUsecase: notifyUsecase,
+		logger:        logger.WithModule("usecase.node_stale"),
+	}
+}
+
+func (u *NodeStaleUsecase) GetStaleList(ctx context.Context, req *v1.StaleNodeListReq) (*v1.StaleNodeListResp, error) {
+	total, items, err := u.nodeRepo.GetStaleNodeList(ctx, req, time.Now().Add(-staleDislikeWindow))
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].DueAt = staleNodeDueAt(&items[i])
+	}
+	return domain.NewPaginatedResult(items, uint64(total)), nil
+}
+
+func (u *NodeStaleUsecase) MarkReviewed(ctx context.Context, req *v1.MarkNodeReviewedReq) error {
+	return u.nodeRepo.MarkNodesReviewed(ctx, req.KbId, req.IDs, time.Now())
+}
+
+// NotifyStaleNodes notifies owners of stale documents in all knowledge bases,
+// each document is notified once until it is edited or reviewed again
+func (u *NodeStaleUsecase) NotifyStaleNodes(ctx context.Context) error {
+	kbIDs, err := u.kbRepo.GetKnowledgeBaseIds(ctx)
+	if err != nil {
+		return err
+	}
+	for _, kbID := range kbIDs {
+		if err := u.notifyKBStaleNodes(ctx, kbID); err != nil {
+			u.logger.Error("notify stale nodes failed", log.String("kb_id", kbID), log.Error(err))
+		}
+	}
+	return nil
+}
+
+func (u *NodeStaleUsecase) notifyKBStaleNodes(ctx context.Context, kbID string) error {
+	items, err := u.nodeRepo.GetUnnotifiedStaleNodes(ctx, kbID, time.Now().Add(-staleDislikeWindow))
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	kb, err := u.kbRepo.GetKnowledgeBaseByID(ctx, kbID)
+	if err != nil {
+		return err
+	}
+
+	for ownerID, ownerItems := range lo.GroupBy(items, func(item v1.StaleNodeItem) string { return item.OwnerID }) {
+		if ownerID == "" {
+			continue
+		}
+		msg := buildStaleNodeMessage(kb, ownerItems)
+		if err := u.notifyUsecase.NotifyUsers(ctx, kbID, []string{ownerID}, msg, false); err != nil {
+			u.logger.Warn("notify stale node owner failed", log.String("kb_id", kbID), log.String("owner_id", ownerID), log.Error(err))
+		}
+	}
+	// group robots get one digest for the whole knowledge base
+	if err := u.notifyUsecase.Notify(ctx, kbID, nil, buildStaleNodeMessage(kb, items), true); err != nil {
+		u.logger.Warn("broadcast stale nodes failed", log.String("kb_id", kbID), log.Error(err))
+	}
+
+	return u.nodeRepo.UpdateNodesStaleNotifiedAt(ctx, lo.Map(items, func(item v1.StaleNodeItem, _ int) string { return item.ID }), time.Now())
+}
+
+func staleNodeDueAt(item *v1.StaleNodeItem) *time.Time {
+	if item.ReviewInterval <= 0 {
+		return nil
+	}
+	freshSince := item.EditTime
+	if item.ReviewedAt != nil && item.ReviewedAt.After(freshSince) {
+		freshSince = *item.ReviewedAt
+	}
+	dueAt := freshSince.AddDate(0, 0, item.ReviewInterval)
+	return &dueAt
+}
+
+func buildStaleNodeMessage(kb *domain.KnowledgeBase, items []v1.StaleNodeItem) *domain.NotifyMessage {
+	var sb strings.Builder
+	for _, item := range items {
+		reasons := make([]string, 0, 2)
+		if item.Expired {
+			reasons = append(reasons, fmt.Sprintf("超过 %d 天未更新", item.ReviewInterval))
+		}
+		if item.DislikeCount > 0 {
+			reasons = append(reasons, fmt.Sprintf("引用该文档的回答最近收到 %d 个点踩", item.DislikeCount))
+		}
+		name := item.Name
+		if kb.AccessSettings.BaseURL != "" {
+			name = fmt.Sprintf("[%s](%s/node/%s)", item.Name, kb.AccessSettings.BaseURL, item.ID)
+		}
+		if item.Owner != "" {
+			fmt.Fprintf(&sb, "- %s (%s, 负责人: %s)\n", name, strings.Join(reasons, ", "), item.Owner)
+		} else {
+			fmt.Fprintf(&sb, "- %s (%s)\n", name, strings.Join(reasons, ", "))
+		}
+	}
+	sb.WriteString("\n请复查以上文档, 更新内容或标记为已复查。")
+	return &domain.NotifyMessage{
+		Title:   fmt.Sprintf("【%s】%d 篇文档可能已过时", kb.Name, len(items)),
+		Content: sb.String(),
+	}
+}
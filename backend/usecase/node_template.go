@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	v1 "github.com/chaitin/panda-wiki/api/node/v1"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/repo/pg"
+)
+
+type NodeTemplateUsecase struct {
+	templateRepo *pg.NodeTemplateRepository
+	nodeRepo     *pg.NodeRepository
+	kbRepo       *pg.KnowledgeBaseRepository
+	userRepo     *pg.UserRepository
+	logger       *log.Logger
+}
+
+func NewNodeTemplateUsecase(
+	templateRepo *pg.NodeTemplateRepository,
+	nodeRepo *pg.NodeRepository,
+	kbRepo *pg.KnowledgeBaseRepository,
+	userRepo *pg.UserRepository,
+	logger *log.Logger,
+) *NodeTemplateUsecase {
+	return &NodeTemplateUsecase{
+		templateRepo: templateRepo,
+		nodeRepo:     nodeRepo,
+		kbRepo:       kbRepo,
+		userRepo:     userRepo,
+		logger:       logger.WithModule("usecase.node_template"),
+	}
+}
+
+func (u *NodeTemplateUsecase) GetList(ctx context.Context, kbID string) ([]v1.NodeTemplateListItem, error) {
+	return u.templateRepo.GetList(ctx, kbID)
+}
+
+func (u *NodeTemplateUsecase) GetDetail(ctx context.Context, kbID, id string) (*domain.NodeTemplate, error) {
+	return u.templateRepo.GetByID(ctx, kbID, id)
+}
+
+func (u *NodeTemplateUsecase) Create(ctx context.Context, req *v1.NodeTemplateCreateReq, userID string) (string, error) {
+	if err := u.validateMeta(ctx, req.KbId, req.Tags, req.Fields); err != nil {
+		return "", err
+	}
+	contentType := req.ContentType
+	if contentType == "" {
+		contentType = domain.ContentTypeMD
+	}
+	now := time.Now()
+	template := &domain.NodeTemplate{
+		ID:          uuid.New().String(),
+		KBID:        req.KbId,
+		Name:        req.Name,
+		Description: req.Description,
+		Title:       req.Title,
+		Content:     req.Content,
+		ContentType: contentType,
+		Emoji:       req.Emoji,
+		Tags:        domain.StringList(req.Tags),
+		Fields:      domain.StringMap(req.Fields),
+		CreatorID:   userID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if template.Tags == nil {
+		template.Tags = domain.StringList{}
+	}
+	if template.Fields == nil {
+		template.Fields = domain.StringMap{}
+	}
+	if req.Permissions != nil {
+		template.Permissions = *req.Permissions
+	}
+	if err := u.templateRepo.Create(ctx, template); err != nil {
+		return "", err
+	}
+	return template.ID, nil
+}
+
+func (u *NodeTemplateUsecase) Update(ctx context.Context, req *v1.NodeTemplateUpdateReq) error {
+	template, err := u.templateRepo.GetByID(ctx, req.KbId, req.ID)
+	if err != nil {
+		return err
+	}
+	updateMap := make(map[string]any)
+	if req.Name != nil {
+		updateMap["name"] = *req.Name
+	}
+	if req.Description != nil {
+		updateMap["description"] = *req.Description
+	}
+	if req.Title != nil {
+		updateMap["title"] = *req.Title
+	}
+	if req.Content != nil {
+		updateMap["content"] = *req.Content
+	}
+	if req.Emoji != nil {
+		updateMap["emoji"] = *req.Emoji
+	}
+	if req.Tags != nil || req.Fields != nil {
+		tags, fields := []string(template.Tags), map[string]string(template.Fields)
+		if req.Tags != nil {
+			tags = *req.Tags
+		}
+		if req.Fields != nil {
+			fields = *req.Fields
+		}
+		if err := u.validateMeta(ctx, req.KbId, tags, fields); err != nil {
+			return err
+		}
+		if tags == nil {
+			tags = make([]string, 0)
+		}
+		if fields == nil {
+			fields = make(map[string]string)
+		}
+		updateMap["tags"] = domain.StringList(tags)
+		updateMap["fields"] = domain.StringMap(fields)
+	}
+	if req.Permissions != nil {
+		updateMap["permissions"] = req.Permissions
+	}
+	if len(updateMap) == 0 {
+		return nil
+	}
+	return u.templateRepo.Update(ctx, req.KbId, req.ID, updateMap)
+}
+
+func (u *NodeTemplateUsecase) Delete(ctx context.Context, kbID, id string) error {
+	return u.templateRepo.Delete(ctx, kbID, id)
+}
+
+// CreateFromNode saves an existing document as a template
+func (u *NodeTemplateUsecase) CreateFromNode(ctx context.Context, req *v1.NodeTemplateFromNodeReq, userID string) (string, error) {
+	node, err := u.nodeRepo.GetNodeByID(ctx, req.NodeID)
+	if err != nil {
+		return "", err
+	}
+	if node.KBID != req.KbId {
+		return "", gorm.ErrRecordNotFound
+	}
+	if node.Type != domain.NodeTypeDocument {
+		return "", domain.ErrNodeTemplateTypeInvalid
+	}
+	permissions := node.Permissions
+	return u.Create(ctx, &v1.NodeTemplateCreateReq{
+		KbId:        req.KbId,
+		Name:        req.Name,
+		Description: req.Description,
+		Title:       node.Name,
+		Content:     node.Content,
+		ContentType: node.Meta.ContentType,
+		Emoji:       node.Meta.Emoji,
+		Tags:        node.Meta.Tags,
+		Fields:      node.Meta.Fields,
+		Permissions: &permissions,
+	}, userID)
+}
+
+// ApplyTemplate fills the create request with the template,
+// values given in the request take precedence over the template
+func (u *NodeTemplateUsecase) ApplyTemplate(ctx context.Context, req *domain.CreateNodeReq, userID string) error {
+	template, err := u.templateRepo.GetByID(ctx, req.KBID, req.TemplateID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("template %s not found: %w", req.TemplateID, err)
+		}
+		return err
+	}
+	if req.Type != domain.NodeTypeDocument {
+		return domain.ErrNodeTemplateTypeInvalid
+	}
+
+	vars, err := u.placeholderVars(ctx, req, userID)
+	if err != nil {
+		return err
+	}
+	if req.Name == "" {
+		req.Name = template.Title
+	}
+	req.Name = domain.FillTemplatePlaceholders(req.Name, vars)
+	if req.Name == "" {
+		req.Name = template.Name
+	}
+	vars[domain.TemplatePlaceholderTitle] = req.Name
+
+	if req.Content == "" {
+		req.Content = domain.FillTemplatePlaceholders(template.Content, vars)
+	}
+	if req.Emoji == "" {
+		req.Emoji = template.Emoji
+	}
+	if req.ContentType == nil && template.ContentType != "" {
+		req.ContentType = &template.ContentType
+	}
+	if req.Tags == nil {
+		req.Tags = template.Tags
+	}
+	if req.Fields == nil {
+		req.Fields = make(map[string]string, len(template.Fields))
+		for k, v := range template.Fields {
+			req.Fields[k] = domain.FillTemplatePlaceholders(v, vars)
+		}
+	}
+	if template.HasPermissions() {
+		req.Permissions = &template.Permissions
+	}
+	return nil
+}
+
+func (u *NodeTemplateUsecase) placeholderVars(ctx context.Context, req *domain.CreateNodeReq, userID string) (map[string]string, error) {
+	now := time.Now()
+	vars := map[string]string{
+		domain.TemplatePlaceholderDate:     now.Format(time.DateOnly),
+		domain.TemplatePlaceholderDatetime: now.Format("2006-01-02 15:04"),
+		domain.TemplatePlaceholderAuthor:   "",
+		domain.TemplatePlaceholderParent:   "",
+	}
+	if userID != "" {
+		// api tokens are not bound to a user account, leave the author empty
+		if user, err := u.userRepo.GetUser(ctx, userID); err != nil {
+			u.logger.Warn("get template author failed", log.String("user_id", userID), log.Error(err))
+		} else {
+			vars[domain.TemplatePlaceholderAuthor] = user.Account
+		}
+	}
+	if req.ParentID != "" {
+		parent, err := u.nodeRepo.GetNodeByID(ctx, req.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("get parent node failed: %w", err)
+		}
+		vars[domain.TemplatePlaceholderParent] = parent.Name
+	}
+	kb, err := u.kbRepo.GetKnowledgeBaseByID(ctx, req.KBID)
+	if err != nil {
+		return nil, fmt.Errorf("get kb failed: %w", err)
+	}
+	vars[domain.TemplatePlaceholderKB] = kb.Name
+	return vars, nil
+}
+
+func (u *NodeTemplateUsecase) validateMeta(ctx context.Context, kbID string, tags []string, fields map[string]string) error {
+	if len(tags) == 0 && len(fields) == 0 {
+		return nil
+	}
+	schema, err := u.nodeRepo.GetNodeMetaSchema(ctx, kbID)
+	if err != nil {
+		return err
+	}
+	return schema.ValidateNodeMeta(tags, fields)
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	v1 "github.com/chaitin/panda-wiki/api/notify/v1"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/notify"
+	"github.com/chaitin/panda-wiki/repo/pg"
+)
+
+type NotifyUsecase struct {
+	notifyRepo *pg.NotifyRepository
+	appRepo    *pg.AppRepository
+	userRepo   *pg.UserRepository
+	logger     *log.Logger
+}
+
+func NewNotifyUsecase(
+	notifyRepo *pg.NotifyRepository,
+	appRepo *pg.AppRepository,
+	userRepo *pg.UserRepository,
+	logger *log.Logger,
+) *NotifyUsecase {
+	return &NotifyUsecase{
+		notifyRepo: notifyRepo,
+		appRepo:    appRepo,
+		userRepo:   userRepo,
+		logger:     logger.WithModule("usecase.notify"),
+	}
+}
+
+func (u *NotifyUsecase) GetSettings(ctx context.Context, kbID string) (*domain.NotifySettings, error) {
+	return u.notifyRepo.GetSettings(ctx, kbID)
+}
+
+func (u *NotifyUsecase) UpdateSettings(ctx context.Context, req *v1.UpdateNotifySettingsReq) error {
+	webhooks := req.Webhooks
+	if webhooks == nil {
+		webhooks = make([]domain.NotifyWebhook, 0)
+	}
+	broadcasts := req.Broadcasts
+	if broadcasts == nil {
+		broadcasts = make([]domain.ReleaseBroadcast, 0)
+	}
+	return u.notifyRepo.UpsertSettings(ctx, req.KbId, &domain.NotifySettings{
+		SMTP:        req.SMTP,
+		Webhooks:    webhooks,
+		FeishuBotDM: req.FeishuBotDM,
+		Broadcasts:  broadcasts,
+	})
+}
+
+// Test sends a test message to the current user and all webhooks
+func (u *NotifyUsecase) Test(ctx context.Context, kbID, userID string) error {
+	users, err := u.userRepo.GetUsersByIDs(ctx, []string{userID})
+	if err != nil {
+		return err
+	}
+	return u.Notify(ctx, kbID, usersToRecipients(users), &domain.NotifyMessage{
+		Title:   "PandaWiki 通知测试",
+		Content: "收到这条消息说明通知渠道配置正确。",
+	}, true)
+}
+
+// NotifyUsers sends msg to the given admin users
+func (u *NotifyUsecase) NotifyUsers(ctx context.Context, kbID string, userIDs []string, msg *domain.NotifyMessage, broadcast bool) error {
+	users, err := u.userRepo.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+	return u.Notify(ctx, kbID, usersToRecipients(users), msg, broadcast)
+}
+
+// Notify delivers msg to every recipient by email and by feishu bot private
+// message when configured, and posts it to all group webhooks if broadcast is set.
+// Delivery continues on failures, the joined error is returned.
+func (u *NotifyUsecase) Notify(ctx context.Context, kbID string, recipients []*domain.NotifyRecipient, msg *domain.NotifyMessage, broadcast bool) error {
+	settings, err := u.notifyRepo.GetSettings(ctx, kbID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	emails := make([]string, 0, len(recipients))
+	for _, r := range recipients {
+		if r.Email != "" {
+			emails = append(emails, r.Email)
+		}
+	}
+
+	if settings.SMTP.Enabled && len(emails) > 0 {
+		if err := notify.SendEmail(&settings.SMTP, emails, msg); err != nil {
+			errs = append(errs, fmt.Errorf("send email: %w", err))
+		}
+	}
+
+	if settings.FeishuBotDM && len(emails) > 0 {
+		app, err := u.appRepo.GetOrCreateAppByKBIDAndType(ctx, kbID, domain.AppTypeFeishuBot)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("get feishu bot app: %w", err))
+		} else if app.Settings.FeishuBotIsEnabled != nil && *app.Settings.FeishuBotIsEnabled && app.Settings.FeishuBotAppID != "" {
+			for _, email := range emails {
+				if err := notify.SendFeishuDMByEmail(ctx, app.Settings.FeishuBotAppID, app.Settings.FeishuBotAppSecret, email, msg); err != nil {
+					errs = append(errs, fmt.Errorf("send feishu message to %s: %w", email, err))
+				}
+			}
+		}
+	}
+
+	if broadcast {
+		for i := range settings.Webhooks {
+			hook := &settings.Webhooks[i]
+			if err := notify.SendWebhook(ctx, hook, msg); err != nil {
+				errs = append(errs, fmt.Errorf("send webhook %s: %w", hook.Name, err))
+			}
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		u.logger.Warn("notify failed", log.String("kb_id", kbID), log.String("title", msg.Title), log.Error(err))
+		return err
+	}
+	return nil
+}
+
+func usersToRecipients(users []*domain.User) []*domain.NotifyRecipient {
+	recipients := make([]*domain.NotifyRecipient, 0, len(users))
+	for _, user := range users {
+		recipients = append(recipients, &domain.NotifyRecipient{
+			UserID:  user.ID,
+			Account: user.Account,
+			Email:   user.Email,
+		})
+	}
+	return recipients
+}
@@ -19,12 +19,22 @@ var ProviderSet = wire.NewSet(
 
 	NewLLMUsecase,
 	NewNodeUsecase,
+	NewNodeReviewUsecase,
+	NewNotifyUsecase,
+	NewReleaseBroadcastUsecase,
+	NewNodeStaleUsecase,
+	NewNodeLinkUsecase,
+	NewNodeTemplateUsecase,
 	NewAppUsecase,
+	NewMailReplyUsecase,
 	NewConversationUsecase,
+	NewEscalationUsecase,
 	NewUserUsecase,
+	NewLoginLimiter,
 	NewModelUsecase,
 	NewKnowledgeBaseUsecase,
 	NewChatUsecase,
+	NewChatAttachmentUsecase,
 	NewCrawlerUsecase,
 	NewCreationUsecase,
 	NewFileUsecase,
@@ -35,4 +45,10 @@ var ProviderSet = wire.NewSet(
 	NewWecomUsecase,
 	NewWechatAppUsecase,
 	NewAuthUsecase,
+	NewAuthGroupSyncUsecase,
+	NewSCIMUsecase,
+	NewAuditUsecase,
+	NewKBRoleUsecase,
+	NewNodeShareLinkUsecase,
+	NewNodeAccessRequestUsecase,
 )
@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/notify"
+	"github.com/chaitin/panda-wiki/repo/pg"
+)
+
+const (
+	// documents listed in one digest, the rest are counted
+	maxDigestNodes = 20
+	// runes of the summary of each document
+	maxDigestSummary = 120
+)
+
+type ReleaseBroadcastUsecase struct {
+	notifyRepo  *pg.NotifyRepository
+	appRepo     *pg.AppRepository
+	kbRepo      *pg.KnowledgeBaseRepository
+	nodeRepo    *pg.NodeRepository
+	nodeUsecase *NodeUsecase
+	logger      *log.Logger
+}
+
+func NewReleaseBroadcastUsecase(
+	notifyRepo *pg.NotifyRepository,
+	appRepo *pg.AppRepository,
+	kbRepo *pg.KnowledgeBaseRepository,
+	nodeRepo *pg.NodeRepository,
+	nodeUsecase *NodeUsecase,
+	logger *log.Logger,
+) *ReleaseBroadcastUsecase {
+	return &ReleaseBroadcastUsecase{
+		notifyRepo:  notifyRepo,
+		appRepo:     appRepo,
+		kbRepo:      kbRepo,
+		nodeRepo:    nodeRepo,
+		nodeUsecase: nodeUsecase,
+		logger:      logger.WithModule("usecase.release_broadcast"),
+	}
+}
+
+// BroadcastRelease posts a digest of the documents published by the release to every subscribed
+// group chat whose folders contain one of them. releasedBefore are the nodes published by earlier releases.
+func (u *ReleaseBroadcastUsecase) BroadcastRelease(ctx context.Context, release *domain.KBRelease, nodeIDs, releasedBefore []string) error {
+	settings, err := u.notifyRepo.GetSettings(ctx, release.KBID)
+	if err != nil {
+		return err
+	}
+	if len(settings.Broadcasts) == 0 {
+		return nil
+	}
+	nodes, err := u.nodeRepo.GetReleaseDigestNodes(ctx, release.KBID, nodeIDs)
+	if err != nil {
+		return fmt.Errorf("get release nodes: %w", err)
+	}
+	// titles and summaries of restricted documents must not reach the chats
+	nodes = openDigestNodes(nodes)
+	if len(nodes) == 0 {
+		return nil
+	}
+	for _, node := range nodes {
+		node.IsNew = !slices.Contains(releasedBefore, node.ID)
+	}
+	kb, err := u.kbRepo.GetKnowledgeBaseByID(ctx, release.KBID)
+	if err != nil {
+		return err
+	}
+
+	summarized := make(map[string]bool)
+	var errs []error
+	for i := range settings.Broadcasts {
+		broadcast := &settings.Broadcasts[i]
+		var matched []*domain.ReleaseDigestNode
+		for _, node := range nodes {
+			if len(broadcast.NodeIDs) == 0 || slices.Contains(broadcast.NodeIDs, node.ID) ||
+				slices.ContainsFunc(node.AncestorIDs, func(id string) bool { return slices.Contains(broadcast.NodeIDs, id) }) {
+				matched = append(matched, node)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		// documents without a summary are summarized once for all chats
+		for _, node := range matched[:min(len(matched), maxDigestNodes)] {
+			if node.Summary != "" || summarized[node.ID] {
+				continue
+			}
+			summarized[node.ID] = true
+			summary, err := u.nodeUsecase.SummaryNode(ctx, &domain.NodeSummaryReq{KBID: release.KBID, IDs: []string{node.ID}})
+			if err != nil {
+				u.logger.Warn("summary release node failed", log.String("node_id", node.ID), log.Error(err))
+				continue
+			}
+			node.Summary = summary
+		}
+		msg := releaseDigest(kb, release, matched)
+		if err := u.send(ctx, release.KBID, broadcast, msg); err != nil {
+			errs = append(errs, fmt.Errorf("broadcast to %s: %w", broadcast.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// BroadcastReleaseQuietly logs failures of BroadcastRelease, publishing does not wait for the chats
+func (u *ReleaseBroadcastUsecase) BroadcastReleaseQuietly(ctx context.Context, release *domain.KBRelease, nodeIDs, releasedBefore []string) {
+	if err := u.BroadcastRelease(ctx, release, nodeIDs, releasedBefore); err != nil {
+		u.logger.Warn("broadcast release failed", log.String("kb_id", release.KBID), log.String("release_id", release.ID), log.Error(err))
+	}
+}
+
+// openDigestNodes keeps the documents everyone may visit
+func openDigestNodes(nodes []*domain.ReleaseDigestNode) []*domain.ReleaseDigestNode {
+	return slices.DeleteFunc(nodes, func(node *domain.ReleaseDigestNode) bool {
+		return node.Visitable != consts.NodeAccessPermOpen
+	})
+}
+
+func releaseDigest(kb *domain.KnowledgeBase, release *domain.KBRelease, nodes []*domain.ReleaseDigestNode) *domain.NotifyMessage {
+	var sb strings.Builder
+	if release.Message != "" {
+		sb.WriteString(release.Message + "\n\n")
+	}
+	listed := nodes[:min(len(nodes), maxDigestNodes)]
+	for _, isNew := range []bool{true, false} {
+		var lines []string
+		for _, node := range listed {
+			if node.IsNew != isNew {
+				continue
+			}
+			line := fmt.Sprintf("- [%s](%s/node/%s)", node.Name, kb.AccessSettings.BaseURL, node.ID)
+			if summary := strings.Join(strings.Fields(node.Summary), " "); summary != "" {
+				line += "：" + truncateRunes(summary, maxDigestSummary)
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		if isNew {
+			sb.WriteString("**新增文档**\n")
+		} else {
+			sb.WriteString("**更新文档**\n")
+		}
+		sb.WriteString(strings.Join(lines, "\n") + "\n\n")
+	}
+	if rest := len(nodes) - len(listed); rest > 0 {
+		sb.WriteString(fmt.Sprintf("另有 %d 篇文档更新，请前往 [%s](%s) 查看。", rest, kb.Name, kb.AccessSettings.BaseURL))
+	}
+	title := fmt.Sprintf("%s 文档更新", kb.Name)
+	if release.Tag != "" {
+		title += " " + release.Tag
+	}
+	return &domain.NotifyMessage{Title: title, Content: strings.TrimSpace(sb.String())}
+}
+
+// send posts msg with the credentials of the bot app the broadcast is configured for
+func (u *ReleaseBroadcastUsecase) send(ctx context.Context, kbID string, broadcast *domain.ReleaseBroadcast, msg *domain.NotifyMessage) error {
+	app, err := u.appRepo.GetOrCreateAppByKBIDAndType(ctx, kbID, broadcast.AppType)
+	if err != nil {
+		return err
+	}
+	settings := app.Settings
+	switch broadcast.AppType {
+	case domain.AppTypeFeishuBot:
+		if settings.FeishuBotAppID == "" {
+			return errBotNotConfigured
+		}
+		return notify.SendFeishuChat(ctx, settings.FeishuBotAppID, settings.FeishuBotAppSecret, broadcast.ChatID, msg)
+	case domain.AppTypeDingTalkBot:
+		if settings.DingTalkBotClientID == "" {
+			return errBotNotConfigured
+		}
+		return notify.SendDingTalkGroup(ctx, settings.DingTalkBotClientID, settings.DingTalkBotClientSecret, broadcast.ChatID, msg)
+	case domain.AppTypeWechatBot:
+		if settings.WeChatAppCorpID == "" {
+			return errBotNotConfigured
+		}
+		return notify.SendWeComAppChat(ctx, settings.WeChatAppCorpID, settings.WeChatAppSecret, broadcast.ChatID, msg)
+	case domain.AppTypeDisCordBot:
+		if settings.DiscordBotToken == "" {
+			return errBotNotConfigured
+		}
+		return notify.SendDiscordChannel(ctx, settings.DiscordBotToken, broadcast.ChatID, msg)
+	case domain.AppTypeSlackBot:
+		if settings.SlackBotSettings.BotToken == "" {
+			return errBotNotConfigured
+		}
+		return notify.SendSlackChannel(ctx, settings.SlackBotSettings.BotToken, broadcast.ChatID, msg)
+	}
+	return fmt.Errorf("unsupported app type %d", broadcast.AppType)
+}
+
+var errBotNotConfigured = errors.New("bot is not configured")
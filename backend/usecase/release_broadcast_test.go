@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+)
+
+func TestReleaseDigestSkipsRestrictedNodes(t *testing.T) {
+	kb := &domain.KnowledgeBase{Name: "Wiki", AccessSettings: domain.AccessSettings{BaseURL: "https://wiki.example.com"}}
+	release := &domain.KBRelease{KBID: "kb", Tag: "v1"}
+	nodes := []*domain.ReleaseDigestNode{
+		{ID: "open", Name: "Install guide", Summary: "how to install", Visitable: consts.NodeAccessPermOpen, IsNew: true},
+		{ID: "partial", Name: "Salary bands", Summary: "internal pay levels", Visitable: consts.NodeAccessPermPartial, IsNew: true},
+		{ID: "closed", Name: "Incident report", Summary: "root cause", Visitable: consts.NodeAccessPermClosed},
+	}
+
+	nodes = openDigestNodes(nodes)
+	if len(nodes) != 1 || nodes[0].ID != "open" {
+		t.Fatalf("openDigestNodes() kept %d nodes, want only the open one", len(nodes))
+	}
+	msg := releaseDigest(kb, release, nodes)
+	if !strings.Contains(msg.Content, "Install guide") {
+		t.Errorf("digest misses the open document: %q", msg.Content)
+	}
+	for _, leaked := range []string{"Salary bands", "internal pay levels", "/node/partial", "Incident report", "/node/closed"} {
+		if strings.Contains(msg.Content, leaked) {
+			t.Errorf("digest leaks %q: %q", leaked, msg.Content)
+		}
+	}
+}
@@ -0,0 +1,608 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+
+	v1 "github.com/chaitin/panda-wiki/api/auth/v1"
+	"github.com/chaitin/panda-wiki/consts"
+	"github.com/chaitin/panda-wiki/domain"
+	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/scim"
+	"github.com/chaitin/panda-wiki/repo/pg"
+)
+
+const (
+	SCIMMaxResults  = 200
+	scimTokenPrefix = "pwscim_"
+	// scimGroupNameMaxLen auth_groups.name 的长度限制
+	scimGroupNameMaxLen = 100
+)
+
+// SCIMUsecase 通过 SCIM 2.0 开通读者及用户组。
+// 用户的 userName 对应读者登录时的 union_id，开通的读者使用配置中的登录方式登录；
+// 用户组以 scim 为来源，上级通过扩展属性 parent 指定。停用或删除读者时立即吊销其会话，
+// 删除用户组时更新相关文档的可问答用户组
+type SCIMUsecase struct {
+	authUsecase *AuthUsecase
+	authRepo    *pg.AuthRepo
+	groupSync   *AuthGroupSyncUsecase
+	logger      *log.Logger
+}
+
+func NewSCIMUsecase(
+	authUsecase *AuthUsecase,
+	authRepo *pg.AuthRepo,
+	groupSync *AuthGroupSyncUsecase,
+	logger *log.Logger,
+) *SCIMUsecase {
+	return &SCIMUsecase{
+		authUsecase: authUsecase,
+		authRepo:    authRepo,
+		groupSync:   groupSync,
+		logger:      logger.WithModule("usecase.scim"),
+	}
+}
+
+func hashSCIMToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newSCIMToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return scimTokenPrefix + hex.EncodeToString(b), nil
+}
+
+// Authenticate 根据 Bearer Token 找到知识库的 SCIM 配置
+func (u *SCIMUsecase) Authenticate(ctx context.Context, token string) (*domain.AuthConfig, error) {
+	if !strings.HasPrefix(token, scimTokenPrefix) {
+		return nil, scim.NewError(http.StatusUnauthorized, "", "invalid token")
+	}
+	authConfig, err := u.authRepo.GetSCIMAuthConfigByTokenHash(ctx, hashSCIMToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, scim.NewError(http.StatusUnauthorized, "", "invalid token")
+		}
+		return nil, err
+	}
+	if authConfig.AuthSetting.SCIM == nil {
+		return nil, scim.NewError(http.StatusUnauthorized, "", "invalid token")
+	}
+	return authConfig, nil
+}
+
+func (u *SCIMUsecase) GetConfig(ctx context.Context, kbID string) (*v1.AuthSCIMGetResp, error) {
+	authConfig, err := u.authRepo.GetAuthConfig(ctx, kbID, consts.SourceTypeSCIM)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &v1.AuthSCIMGetResp{}, nil
+		}
+		return nil, err
+	}
+	setting := authConfig.AuthSetting.SCIM
+	if setting == nil {
+		return &v1.AuthSCIMGetResp{}, nil
+	}
+	return &v1.AuthSCIMGetResp{
+		Enabled:        true,
+		SourceType:     setting.SourceType,
+		TokenCreatedAt: &setting.TokenCreatedAt,
+	}, nil
+}
+
+// SetConfig 首次开启或要求重置时生成 token，token 只在此时返回
+func (u *SCIMUsecase) SetConfig(ctx context.Context, req v1.AuthSCIMSetReq) (*v1.AuthSCIMSetResp, error) {
+	if !slices.Contains(consts.SCIMSourceTypes, req.SourceType) {
+		return nil, fmt.Errorf("scim is not supported for %s", req.SourceType)
+	}
+	setting := &domain.SCIMAuthSetting{SourceType: req.SourceType}
+	authConfig, err := u.authRepo.GetAuthConfig(ctx, req.KBID, consts.SourceTypeSCIM)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil && authConfig.AuthSetting.SCIM != nil {
+		setting.TokenHash = authConfig.AuthSetting.SCIM.TokenHash
+		setting.TokenCreatedAt = authConfig.AuthSetting.SCIM.TokenCreatedAt
+	}
+
+	resp := &v1.AuthSCIMSetResp{}
+	if setting.TokenHash == "" || req.ResetToken {
+		token, err := newSCIMToken()
+		if err != nil {
+			return nil, err
+		}
+		setting.TokenHash = hashSCIMToken(token)
+		setting.TokenCreatedAt = time.Now()
+		resp.Token = token
+	}
+	if err := u.authRepo.CreateAuthConfig(ctx, &domain.AuthConfig{
+		KbID:        req.KBID,
+		SourceType:  consts.SourceTypeSCIM,
+		AuthSetting: domain.AuthSetting{SCIM: setting},
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DeleteConfig 关闭 SCIM，已开通的读者及用户组保留
+func (u *SCIMUsecase) DeleteConfig(ctx context.Context, kbID string) error {
+	return u.authRepo.DeleteAuthConfig(ctx, kbID, consts.SourceTypeSCIM)
+}
+
+func scimNotFound(resource, id string) error {
+	return scim.NewError(http.StatusNotFound, "", fmt.Sprintf("%s %s not found", resource, id))
+}
+
+// scimPage startIndex 从 1 开始
+func scimPage(startIndex, count int) (int, int, int) {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	count = min(max(count, 0), SCIMMaxResults)
+	return startIndex, startIndex - 1, count
+}
+
+func (u *SCIMUsecase) toUser(auth *domain.Auth) *scim.User {
+	active := auth.Active
+	user := &scim.User{
+		Schemas:     []string{scim.SchemaUser},
+		ID:          strconv.FormatUint(uint64(auth.ID), 10),
+		ExternalID:  auth.ExternalID,
+		UserName:    auth.UnionID,
+		DisplayName: auth.UserInfo.Username,
+		Active:      &active,
+		Meta: &scim.Meta{
+			ResourceType: "User",
+			Created:      auth.CreatedAt,
+			LastModified: auth.UpdatedAt,
+		},
+	}
+	if auth.UserInfo.Username != "" {
+		user.Name = &scim.Name{Formatted: auth.UserInfo.Username}
+	}
+	if auth.UserInfo.Email != "" {
+		user.Emails = []scim.MultiValue{{Value: auth.UserInfo.Email, Type: "work", Primary: true}}
+	}
+	return user
+}
+
+func (u *SCIMUsecase) getAuth(ctx context.Context, authConfig *domain.AuthConfig, id string) (*domain.Auth, error) {
+	authID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, scimNotFound("User", id)
+	}
+	auth, err := u.authRepo.GetAuthById(ctx, authConfig.KbID, uint(authID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, scimNotFound("User", id)
+		}
+		return nil, err
+	}
+	if auth.SourceType != authConfig.AuthSetting.SCIM.SourceType {
+		return nil, scimNotFound("User", id)
+	}
+	return auth, nil
+}
+
+func (u *SCIMUsecase) ListUsers(ctx context.Context, authConfig *domain.AuthConfig, filter *scim.Filter, startIndex, count int) (*scim.ListResponse, error) {
+	var column string
+	var value any
+	if filter != nil {
+		switch {
+		case filter.Is("userName"):
+			column, value = "union_id", filter.Value
+		case filter.Is("externalId"):
+			column, value = "external_id", filter.Value
+		case filter.Is("id"):
+			id, err := strconv.ParseUint(filter.Value, 10, 64)
+			if err != nil {
+				return scim.NewListResponse[*scim.User](nil, 0, 1), nil
+			}
+			column, value = "id", id
+		default:
+			return nil, scim.NewError(http.StatusBadRequest, scim.ErrInvalidFilter, "unsupported filter attribute "+filter.Attr)
+		}
+	}
+	startIndex, offset, limit := scimPage(startIndex, count)
+	auths, total, err := u.authRepo.GetAuthsByColumn(ctx, authConfig.KbID, authConfig.AuthSetting.SCIM.SourceType, column, value, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	users := lo.Map(auths, func(auth domain.Auth, _ int) *scim.User {
+		return u.toUser(&auth)
+	})
+	return scim.NewListResponse(users, int(total), startIndex), nil
+}
+
+func (u *SCIMUsecase) GetUser(ctx context.Context, authConfig *domain.AuthConfig, id string) (*scim.User, error) {
+	auth, err := u.getAuth(ctx, authConfig, id)
+	if err != nil {
+		return nil, err
+	}
+	return u.toUser(auth), nil
+}
+
+func (u *SCIMUsecase) CreateUser(ctx context.Context, authConfig *domain.AuthConfig, user *scim.User) (*scim.User, error) {
+	userName := strings.TrimSpace(user.UserName)
+	if userName == "" {
+		return nil, scim.NewError(http.StatusBadRequest, scim.ErrInvalidValue, "userName is required")
+	}
+	sourceType := authConfig.AuthSetting.SCIM.SourceType
+	auth := &domain.Auth{
+		KBID:       authConfig.KbID,
+		UnionID:    userName,
+		SourceType: sourceType,
+		ExternalID: user.ExternalID,
+		Active:     user.IsActive(),
+		UserInfo: domain.AuthUserInfo{
+			Username: user.FormattedName(),
+			Email:    user.PrimaryEmail(),
+		},
+	}
+	licenseEdition, _ := ctx.Value(consts.ContextKeyEdition).(consts.LicenseEdition)
+	if err := u.authRepo.CreateProvisionedAuth(ctx, auth, licenseEdition.GetMaxAuth(sourceType)); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrAuthExists):
+			return nil, scim.NewError(http.StatusConflict, scim.ErrUniqueness, "userName already exists")
+		case errors.Is(err, domain.ErrMaxAuthLimitReached):
+			return nil, scim.NewError(http.StatusForbidden, "", "exceed max auth limit of the license")
+		}
+		return nil, err
+	}
+	return u.GetUser(ctx, authConfig, strconv.FormatUint(uint64(auth.ID), 10))
+}
+
+func (u *SCIMUsecase) ReplaceUser(ctx context.Context, authConfig *domain.AuthConfig, id string, user *scim.User) (*scim.User, error) {
+	auth, err := u.getAuth(ctx, authConfig, id)
+	if err != nil {
+		return nil, err
+	}
+	return u.saveUser(ctx, authConfig, auth, user)
+}
+
+func (u *SCIMUsecase) PatchUser(ctx context.Context, authConfig *domain.AuthConfig, id string, ops []scim.PatchOperation) (*scim.User, error) {
+	auth, err := u.getAuth(ctx, authConfig, id)
+	if err != nil {
+		return nil, err
+	}
+	user := u.toUser(auth)
+	if err := scim.ApplyPatch(user, ops); err != nil {
+		return nil, err
+	}
+	return u.saveUser(ctx, authConfig, auth, user)
+}
+
+func (u *SCIMUsecase) saveUser(ctx context.Context, authConfig *domain.AuthConfig, auth *domain.Auth, user *scim.User) (*scim.User, error) {
+	userName := strings.TrimSpace(user.UserName)
+	if userName == "" {
+		return nil, scim.NewError(http.StatusBadRequest, scim.ErrInvalidValue, "userName is required")
+	}
+	deactivated := auth.Active && !user.IsActive()
+	auth.UnionID = userName
+	auth.ExternalID = user.ExternalID
+	auth.Active = user.IsActive()
+	auth.UserInfo.Username = user.FormattedName()
+	auth.UserInfo.Email = user.PrimaryEmail()
+	if err := u.authRepo.UpdateProvisionedAuth(ctx, auth); err != nil {
+		if errors.Is(err, domain.ErrAuthExists) {
+			return nil, scim.NewError(http.StatusConflict, scim.ErrUniqueness, "userName already exists")
+		}
+		return nil, err
+	}
+	if deactivated {
+		if err := u.authUsecase.RevokeShareSessions(ctx, auth.ID); err != nil {
+			return nil, err
+		}
+		u.logger.Info("scim deactivated auth", log.String("kb_id", auth.KBID), log.Any("auth_id", auth.ID))
+	}
+	return u.GetUser(ctx, authConfig, strconv.FormatUint(uint64(auth.ID), 10))
+}
+
+// DeleteUser 删除读者并移出所有用户组，已有会话立即失效
+func (u *SCIMUsecase) DeleteUser(ctx context.Context, authConfig *domain.AuthConfig, id string) error {
+	auth, err := u.getAuth(ctx, authConfig, id)
+	if err != nil {
+		return err
+	}
+	if err := u.authRepo.DeleteAuthWithGroups(ctx, auth.KBID, auth.ID); err != nil {
+		return err
+	}
+	if err := u.authUsecase.RevokeShareSessions(ctx, auth.ID); err != nil {
+		return err
+	}
+	u.logger.Info("scim deleted auth", log.String("kb_id", auth.KBID), log.Any("auth_id", auth.ID))
+	return nil
+}
+
+func (u *SCIMUsecase) toGroups(ctx context.Context, authConfig *domain.AuthConfig, groups []domain.AuthGroup, withMembers bool) ([]*scim.Group, error) {
+	names := make(map[uint]string)
+	if withMembers {
+		ids := make([]uint, 0)
+		for _, group := range groups {
+			for _, id := range group.AuthIDs {
+				ids = append(ids, uint(id))
+			}
+		}
+		auths, err := u.authRepo.GetAuthsByIDs(ctx, authConfig.KbID, authConfig.AuthSetting.SCIM.SourceType, lo.Uniq(ids))
+		if err != nil {
+			return nil, err
+		}
+		for _, auth := range auths {
+			names[auth.ID] = lo.CoalesceOrEmpty(auth.UserInfo.Username, auth.UnionID)
+		}
+	}
+
+	result := make([]*scim.Group, 0, len(groups))
+	for _, group := range groups {
+		g := &scim.Group{
+			Schemas:     []string{scim.SchemaGroup},
+			ID:          strconv.FormatUint(uint64(group.ID), 10),
+			ExternalID:  group.SyncId,
+			DisplayName: group.Name,
+			Meta: &scim.Meta{
+				ResourceType: "Group",
+				Created:      group.CreatedAt,
+				LastModified: group.UpdatedAt,
+			},
+		}
+		if group.ParentID != nil {
+			g.Schemas = append(g.Schemas, scim.SchemaGroupExtension)
+			g.Extension = &scim.GroupExtension{Parent: strconv.FormatUint(uint64(*group.ParentID), 10)}
+		}
+		for _, id := range group.AuthIDs {
+			// 其他来源的读者不属于 SCIM 管理范围
+			if name, ok := names[uint(id)]; ok {
+				g.Members = append(g.Members, scim.MultiValue{
+					Value:   strconv.FormatInt(id, 10),
+					Display: name,
+					Type:    "User",
+				})
+			}
+		}
+		result = append(result, g)
+	}
+	return result, nil
+}
+
+func (u *SCIMUsecase) getGroups(ctx context.Context, kbID string) ([]domain.AuthGroup, error) {
+	return u.authRepo.GetAuthGroupsBySourceType(ctx, kbID, consts.SourceTypeSCIM)
+}
+
+func findGroup(groups []domain.AuthGroup, id string) (*domain.AuthGroup, error) {
+	groupID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, scimNotFound("Group", id)
+	}
+	group, ok := lo.Find(groups, func(g domain.AuthGroup) bool {
+		return g.ID == uint(groupID)
+	})
+	if !ok {
+		return nil, scimNotFound("Group", id)
+	}
+	return &group, nil
+}
+
+func (u *SCIMUsecase) ListGroups(ctx context.Context, authConfig *domain.AuthConfig, filter *scim.Filter, startIndex, count int, withMembers bool) (*scim.ListResponse, error) {
+	groups, err := u.getGroups(ctx, authConfig.KbID)
+	if err != nil {
+		return nil, err
+	}
+	if filter != nil {
+		var match func(g domain.AuthGroup) bool
+		switch {
+		case filter.Is("displayName"):
+			match = func(g domain.AuthGroup) bool { return strings.EqualFold(g.Name, filter.Value) }
+		case filter.Is("externalId"):
+			match = func(g domain.AuthGroup) bool { return g.SyncId == filter.Value }
+		case filter.Is("id"):
+			match = func(g domain.AuthGroup) bool { return strconv.FormatUint(uint64(g.ID), 10) == filter.Value }
+		default:
+			return nil, scim.NewError(http.StatusBadRequest, scim.ErrInvalidFilter, "unsupported filter attribute "+filter.Attr)
+		}
+		groups = lo.Filter(groups, func(g domain.AuthGroup, _ int) bool { return match(g) })
+	}
+	startIndex, offset, limit := scimPage(startIndex, count)
+	page := lo.Subset(groups, offset, uint(limit))
+	resources, err := u.toGroups(ctx, authConfig, page, withMembers)
+	if err != nil {
+		return nil, err
+	}
+	return scim.NewListResponse(resources, len(groups), startIndex), nil
+}
+
+func (u *SCIMUsecase) GetGroup(ctx context.Context, authConfig *domain.AuthConfig, id string) (*scim.Group, error) {
+	groups, err := u.getGroups(ctx, authConfig.KbID)
+	if err != nil {
+		return nil, err
+	}
+	group, err := findGroup(groups, id)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := u.toGroups(ctx, authConfig, []domain.AuthGroup{*group}, true)
+	if err != nil {
+		return nil, err
+	}
+	return resources[0], nil
+}
+
+func (u *SCIMUsecase) CreateGroup(ctx context.Context, authConfig *domain.AuthConfig, req *scim.Group) (*scim.Group, error) {
+	groups, err := u.getGroups(ctx, authConfig.KbID)
+	if err != nil {
+		return nil, err
+	}
+	group := &domain.AuthGroup{
+		KbID:       authConfig.KbID,
+		SourceType: consts.SourceTypeSCIM,
+	}
+	if err := u.fillGroup(ctx, authConfig, groups, group, req); err != nil {
+		return nil, err
+	}
+	if err := u.authRepo.CreateAuthGroup(ctx, group); err != nil {
+		return nil, err
+	}
+	return u.GetGroup(ctx, authConfig, strconv.FormatUint(uint64(group.ID), 10))
+}
+
+func (u *SCIMUsecase) ReplaceGroup(ctx context.Context, authConfig *domain.AuthConfig, id string, req *scim.Group) (*scim.Group, error) {
+	groups, err := u.getGroups(ctx, authConfig.KbID)
+	if err != nil {
+		return nil, err
+	}
+	group, err := findGroup(groups, id)
+	if err != nil {
+		return nil, err
+	}
+	return u.saveGroup(ctx, authConfig, groups, group, req)
+}
+
+func (u *SCIMUsecase) PatchGroup(ctx context.Context, authConfig *domain.AuthConfig, id string, ops []scim.PatchOperation) (*scim.Group, error) {
+	groups, err := u.getGroups(ctx, authConfig.KbID)
+	if err != nil {
+		return nil, err
+	}
+	group, err := findGroup(groups, id)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := u.toGroups(ctx, authConfig, []domain.AuthGroup{*group}, true)
+	if err != nil {
+		return nil, err
+	}
+	req := resources[0]
+	if err := scim.ApplyPatch(req, ops); err != nil {
+		return nil, err
+	}
+	return u.saveGroup(ctx, authConfig, groups, group, req)
+}
+
+func (u *SCIMUsecase) saveGroup(ctx context.Context, authConfig *domain.AuthConfig, groups []domain.AuthGroup, group *domain.AuthGroup, req *scim.Group) (*scim.Group, error) {
+	if err := u.fillGroup(ctx, authConfig, groups, group, req); err != nil {
+		return nil, err
+	}
+	if err := u.authRepo.UpdateAuthGroup(ctx, group); err != nil {
+		return nil, err
+	}
+	return u.GetGroup(ctx, authConfig, strconv.FormatUint(uint64(group.ID), 10))
+}
+
+// fillGroup 校验名称、上级及成员后写入 group。成员只能是开通范围内的读者，
+// 不属于 SCIM 管理范围的已有成员保留
+func (u *SCIMUsecase) fillGroup(ctx context.Context, authConfig *domain.AuthConfig, groups []domain.AuthGroup, group *domain.AuthGroup, req *scim.Group) error {
+	name := strings.TrimSpace(req.DisplayName)
+	if name == "" {
+		return scim.NewError(http.StatusBadRequest, scim.ErrInvalidValue, "displayName is required")
+	}
+	if len([]rune(name)) > scimGroupNameMaxLen {
+		return scim.NewError(http.StatusBadRequest, scim.ErrInvalidValue, fmt.Sprintf("displayName exceeds %d characters", scimGroupNameMaxLen))
+	}
+
+	var parentID *uint
+	if parent := req.ParentID(); parent != "" {
+		p, err := findGroup(groups, parent)
+		if err != nil {
+			return scim.NewError(http.StatusBadRequest, scim.ErrInvalidValue, "parent group "+parent+" not found")
+		}
+		// 沿上级查找，回到当前用户组即成环
+		byID := lo.KeyBy(groups, func(g domain.AuthGroup) uint { return g.ID })
+		for ancestor := p; ; {
+			if group.ID != 0 && ancestor.ID == group.ID {
+				return scim.NewError(http.StatusBadRequest, scim.ErrInvalidValue, "parent group would create a cycle")
+			}
+			if ancestor.ParentID == nil {
+				break
+			}
+			next, ok := byID[*ancestor.ParentID]
+			if !ok {
+				break
+			}
+			ancestor = &next
+		}
+		parentID = &p.ID
+	}
+
+	memberIDs := make([]uint, 0, len(req.Members))
+	for _, member := range req.Members {
+		if strings.EqualFold(member.Type, "Group") {
+			return scim.NewError(http.StatusBadRequest, scim.ErrInvalidValue, "nested group members are not supported, set the parent group with "+scim.SchemaGroupExtension)
+		}
+		id, err := strconv.ParseUint(member.Value, 10, 64)
+		if err != nil {
+			return scim.NewError(http.StatusBadRequest, scim.ErrInvalidValue, "member "+member.Value+" not found")
+		}
+		memberIDs = append(memberIDs, uint(id))
+	}
+	memberIDs = lo.Uniq(memberIDs)
+	auths, err := u.authRepo.GetAuthsByIDs(ctx, authConfig.KbID, authConfig.AuthSetting.SCIM.SourceType, memberIDs)
+	if err != nil {
+		return err
+	}
+	if len(auths) != len(memberIDs) {
+		found := lo.Map(auths, func(auth domain.Auth, _ int) uint { return auth.ID })
+		missing, _ := lo.Difference(memberIDs, found)
+		return scim.NewError(http.StatusBadRequest, scim.ErrInvalidValue, fmt.Sprintf("member %d not found", missing[0]))
+	}
+
+	authIDs := pq.Int64Array{}
+	if len(group.AuthIDs) > 0 {
+		managed, err := u.authRepo.GetAuthsByIDs(ctx, authConfig.KbID, authConfig.AuthSetting.SCIM.SourceType, lo.Map(group.AuthIDs, func(id int64, _ int) uint { return uint(id) }))
+		if err != nil {
+			return err
+		}
+		managedIDs := lo.Map(managed, func(auth domain.Auth, _ int) int64 { return int64(auth.ID) })
+		for _, id := range group.AuthIDs {
+			if !slices.Contains(managedIDs, id) {
+				authIDs = append(authIDs, id)
+			}
+		}
+	}
+	for _, id := range memberIDs {
+		authIDs = append(authIDs, int64(id))
+	}
+
+	group.Name = name
+	group.SyncId = req.ExternalID
+	group.ParentID = parentID
+	group.AuthIDs = authIDs
+	return nil
+}
+
+// DeleteGroup 删除用户组，下级用户组成为顶级用户组，并更新可问答权限涉及该用户组的文档
+func (u *SCIMUsecase) DeleteGroup(ctx context.Context, authConfig *domain.AuthConfig, id string) error {
+	groups, err := u.getGroups(ctx, authConfig.KbID)
+	if err != nil {
+		return err
+	}
+	group, err := findGroup(groups, id)
+	if err != nil {
+		return err
+	}
+	nodeIDs, err := u.authRepo.DeleteAuthGroup(ctx, authConfig.KbID, group.ID)
+	if err != nil {
+		return err
+	}
+	updated, err := u.groupSync.updateAnswerableGroups(ctx, authConfig.KbID, nodeIDs)
+	if err != nil {
+		return fmt.Errorf("update answerable groups failed: %w", err)
+	}
+	u.logger.Info("scim deleted auth group", log.String("kb_id", authConfig.KbID), log.Any("group_id", group.ID), log.Int("updated_docs", updated))
+	return nil
+}
@@ -2,27 +2,57 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 
 	v1 "github.com/chaitin/panda-wiki/api/user/v1"
 	"github.com/chaitin/panda-wiki/config"
 	"github.com/chaitin/panda-wiki/consts"
 	"github.com/chaitin/panda-wiki/domain"
 	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/totp"
+	cacheRepo "github.com/chaitin/panda-wiki/repo/cache"
 	"github.com/chaitin/panda-wiki/repo/pg"
+	"github.com/chaitin/panda-wiki/store/cache"
+)
+
+const (
+	totpIssuer = "PandaWiki"
+
+	loginChallengeTTL = 5 * time.Minute
+	totpSetupTTL      = 10 * time.Minute
+	recoveryCodeCount = 10
+	sessionTTL        = 24 * time.Hour
 )
 
 type UserUsecase struct {
-	repo   *pg.UserRepository
-	logger *log.Logger
-	config *config.Config
+	repo        *pg.UserRepository
+	sessionRepo *cacheRepo.UserSessionRepo
+	logger      *log.Logger
+	config      *config.Config
+	cache       *cache.Cache
+	limiter     *LoginLimiter
 }
 
-func NewUserUsecase(repo *pg.UserRepository, logger *log.Logger, config *config.Config) (*UserUsecase, error) {
+// loginChallenge 密码校验通过、等待两步验证的登录
+type loginChallenge struct {
+	UserID string `json:"user_id"`
+	Setup  bool   `json:"setup"`            // 策略要求但尚未绑定，需要先绑定
+	Secret string `json:"secret,omitempty"` // 绑定中的密钥
+}
+
+func NewUserUsecase(repo *pg.UserRepository, sessionRepo *cacheRepo.UserSessionRepo, logger *log.Logger, config *config.Config, cache *cache.Cache, limiter *LoginLimiter) (*UserUsecase, error) {
 	if config.AdminPassword != "" {
 		if err := repo.UpsertDefaultUser(context.Background(), &domain.User{
 			ID:       uuid.New().String(),
@@ -34,9 +64,12 @@ func NewUserUsecase(repo *pg.UserRepository, logger *log.Logger, config *config.
 		}
 	}
 	return &UserUsecase{
-		repo:   repo,
-		logger: logger.WithModule("usecase.user"),
-		config: config,
+		repo:        repo,
+		sessionRepo: sessionRepo,
+		logger:      logger.WithModule("usecase.user"),
+		config:      config,
+		cache:       cache,
+		limiter:     limiter,
 	}, nil
 }
 
@@ -44,21 +77,375 @@ func (u *UserUsecase) CreateUser(ctx context.Context, user *domain.User, edition
 	return u.repo.CreateUser(ctx, user, edition)
 }
 
-func (u *UserUsecase) VerifyUserAndGenerateToken(ctx context.Context, req v1.LoginReq) (string, error) {
-	var user *domain.User
-	var err error
-	user, err = u.repo.VerifyUser(ctx, req.Account, req.Password)
+// VerifyUserAndGenerateToken 校验账号密码，开启两步验证的用户返回验证凭据而不是 token
+func (u *UserUsecase) VerifyUserAndGenerateToken(ctx context.Context, req v1.LoginReq, client *domain.UserSession) (*v1.LoginResp, error) {
+	limitKey := "user:" + strings.ToLower(req.Account)
+	if err := u.limiter.Check(ctx, limitKey); err != nil {
+		return nil, err
+	}
+	user, err := u.repo.VerifyUser(ctx, req.Account, req.Password)
+	if err != nil {
+		if failErr := u.limiter.Fail(ctx, limitKey); failErr != nil {
+			u.logger.Error("record login failure failed", log.Error(failErr))
+		}
+		return nil, err
+	}
+	if err := u.limiter.Reset(ctx, limitKey); err != nil {
+		u.logger.Error("reset login failures failed", log.Error(err))
+	}
+
+	challenge := &loginChallenge{UserID: user.ID}
+	if !user.TOTPEnabled {
+		policy, err := u.repo.GetLoginSecurityPolicy(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !policy.RequireAdmin2FA || user.Role != consts.UserRoleAdmin {
+			token, err := u.generateToken(ctx, user, client)
+			if err != nil {
+				return nil, err
+			}
+			return &v1.LoginResp{Token: token}, nil
+		}
+		challenge.Setup = true
+	}
+
+	challengeToken := uuid.New().String()
+	if err := u.saveLoginChallenge(ctx, challengeToken, challenge, loginChallengeTTL); err != nil {
+		return nil, err
+	}
+	return &v1.LoginResp{
+		TwoFactorRequired:      !challenge.Setup,
+		TwoFactorSetupRequired: challenge.Setup,
+		TwoFactorToken:         challengeToken,
+	}, nil
+}
+
+// SetupLoginTwoFactor 登录时按策略强制绑定，生成待确认的密钥
+func (u *UserUsecase) SetupLoginTwoFactor(ctx context.Context, req *v1.LoginTwoFactorSetupReq) (*v1.TwoFactorSetupResp, error) {
+	challenge, err := u.getLoginChallenge(ctx, req.TwoFactorToken)
+	if err != nil {
+		return nil, err
+	}
+	if !challenge.Setup {
+		return nil, fmt.Errorf("two-factor authentication is already enabled")
+	}
+	user, err := u.repo.GetUser(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	challenge.Secret = secret
+	if err := u.saveLoginChallenge(ctx, req.TwoFactorToken, challenge, redis.KeepTTL); err != nil {
+		return nil, err
+	}
+	return &v1.TwoFactorSetupResp{
+		Secret:     secret,
+		OTPAuthURL: totp.URL(totpIssuer, user.Account, secret),
+	}, nil
+}
+
+// VerifyLoginTwoFactor 校验验证码或恢复码后签发 token，绑定流程同时开启两步验证并返回恢复码
+func (u *UserUsecase) VerifyLoginTwoFactor(ctx context.Context, req *v1.LoginTwoFactorReq, client *domain.UserSession) (*v1.LoginTwoFactorResp, error) {
+	challenge, err := u.getLoginChallenge(ctx, req.TwoFactorToken)
+	if err != nil {
+		return nil, err
+	}
+	user, err := u.repo.GetUser(ctx, challenge.UserID)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	resp := &v1.LoginTwoFactorResp{}
+	if challenge.Setup {
+		if challenge.Secret == "" {
+			return nil, fmt.Errorf("two-factor setup not started")
+		}
+		user.TOTPSecret = challenge.Secret
+		if err := u.verifyTOTP(ctx, user, req.Code); err != nil {
+			return nil, err
+		}
+		codes, err := u.enableTwoFactor(ctx, user.ID, challenge.Secret)
+		if err != nil {
+			return nil, err
+		}
+		resp.RecoveryCodes = codes
+	} else if err := u.verifyTwoFactor(ctx, user, req.Code); err != nil {
+		return nil, err
+	}
+
+	// 凭据只能使用一次
+	deleted, err := u.cache.Del(ctx, loginChallengeKey(req.TwoFactorToken)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 {
+		return nil, domain.ErrLoginChallengeExpired
+	}
+	resp.Token, err = u.generateToken(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// generateToken 登记会话后签发 token，client 只需填写 IP 和 UserAgent
+func (u *UserUsecase) generateToken(ctx context.Context, user *domain.User, client *domain.UserSession) (string, error) {
+	now := time.Now()
+	session := &domain.UserSession{
+		ID:           uuid.New().String(),
+		UserID:       user.ID,
+		UserAgent:    client.UserAgent,
+		IP:           client.IP,
+		CreatedAt:    now,
+		LastActiveAt: now,
+		ExpiresAt:    now.Add(sessionTTL),
+	}
+	if err := u.sessionRepo.CreateSession(ctx, session); err != nil {
+		return "", fmt.Errorf("create session failed: %w", err)
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"id":  user.ID,
-		"exp": time.Now().Add(time.Hour * 24).Unix(),
+		"sid": session.ID,
+		"exp": session.ExpiresAt.Unix(),
 	})
 
 	return token.SignedString([]byte(u.config.Auth.JWT.Secret))
 }
 
+func loginChallengeKey(token string) string {
+	return "login-2fa:" + token
+}
+
+func (u *UserUsecase) saveLoginChallenge(ctx context.Context, token string, challenge *loginChallenge, ttl time.Duration) error {
+	value, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+	return u.cache.Set(ctx, loginChallengeKey(token), value, ttl).Err()
+}
+
+func (u *UserUsecase) getLoginChallenge(ctx context.Context, token string) (*loginChallenge, error) {
+	value, err := u.cache.Get(ctx, loginChallengeKey(token)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, domain.ErrLoginChallengeExpired
+		}
+		return nil, err
+	}
+	challenge := &loginChallenge{}
+	if err := json.Unmarshal(value, challenge); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// verifyTwoFactor 接受验证码或未使用过的恢复码，失败次数与密码分开计数
+func (u *UserUsecase) verifyTwoFactor(ctx context.Context, user *domain.User, code string) error {
+	err := u.verifyTOTP(ctx, user, code)
+	if !errors.Is(err, domain.ErrTwoFactorCodeInvalid) || len(normalizeRecoveryCode(code)) == totp.Digits {
+		return err
+	}
+	used, err := u.repo.UseRecoveryCode(ctx, user.ID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return u.twoFactorFailed(ctx, user.ID)
+	}
+	u.logger.Info("recovery code used", log.String("user_id", user.ID))
+	return u.limiter.Reset(ctx, "2fa:"+user.ID)
+}
+
+func (u *UserUsecase) verifyTOTP(ctx context.Context, user *domain.User, code string) error {
+	limitKey := "2fa:" + user.ID
+	if err := u.limiter.Check(ctx, limitKey); err != nil {
+		return err
+	}
+	step, ok := totp.Validate(user.TOTPSecret, code, time.Now())
+	if !ok {
+		if len(normalizeRecoveryCode(code)) != totp.Digits {
+			return domain.ErrTwoFactorCodeInvalid
+		}
+		return u.twoFactorFailed(ctx, user.ID)
+	}
+	// 同一时间窗口的验证码只能使用一次
+	fresh, err := u.cache.SetNX(ctx, fmt.Sprintf("totp-used:%s:%d", user.ID, step), 1, (2*totp.Skew+1)*totp.Period).Result()
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return u.twoFactorFailed(ctx, user.ID)
+	}
+	return u.limiter.Reset(ctx, limitKey)
+}
+
+func (u *UserUsecase) twoFactorFailed(ctx context.Context, userID string) error {
+	if err := u.limiter.Fail(ctx, "2fa:"+userID); err != nil {
+		u.logger.Error("record two-factor failure failed", log.Error(err))
+	}
+	return domain.ErrTwoFactorCodeInvalid
+}
+
+func (u *UserUsecase) enableTwoFactor(ctx context.Context, userID, secret string) ([]string, error) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo.UpdateUserTOTP(ctx, userID, secret, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// generateRecoveryCodes 返回明文恢复码及其 hash，明文只展示一次
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	for range recoveryCodeCount {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(encoding.EncodeToString(b))[:10]
+		code := raw[:5] + "-" + raw[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(normalizeRecoveryCode(code)))
+	return hex.EncodeToString(sum[:])
+}
+
+func totpSetupKey(userID string) string {
+	return "totp-setup:" + userID
+}
+
+// SetupTwoFactor 生成待确认的密钥，用验证码确认后才生效
+func (u *UserUsecase) SetupTwoFactor(ctx context.Context, userID string) (*v1.TwoFactorSetupResp, error) {
+	user, err := u.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, fmt.Errorf("two-factor authentication is already enabled")
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.cache.Set(ctx, totpSetupKey(userID), secret, totpSetupTTL).Err(); err != nil {
+		return nil, err
+	}
+	return &v1.TwoFactorSetupResp{
+		Secret:     secret,
+		OTPAuthURL: totp.URL(totpIssuer, user.Account, secret),
+	}, nil
+}
+
+func (u *UserUsecase) EnableTwoFactor(ctx context.Context, userID string, code string) (*v1.TwoFactorRecoveryCodesResp, error) {
+	secret, err := u.cache.Get(ctx, totpSetupKey(userID)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, fmt.Errorf("two-factor setup expired, please start again")
+		}
+		return nil, err
+	}
+	user, err := u.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	user.TOTPSecret = secret
+	if err := u.verifyTOTP(ctx, user, code); err != nil {
+		return nil, err
+	}
+	codes, err := u.enableTwoFactor(ctx, userID, secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.cache.Del(ctx, totpSetupKey(userID)).Err(); err != nil {
+		u.logger.Error("delete totp setup failed", log.Error(err))
+	}
+	return &v1.TwoFactorRecoveryCodesResp{RecoveryCodes: codes}, nil
+}
+
+// DisableTwoFactor 策略要求管理员开启时不允许关闭
+func (u *UserUsecase) DisableTwoFactor(ctx context.Context, userID string, code string) error {
+	user, err := u.repo.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		return nil
+	}
+	policy, err := u.repo.GetLoginSecurityPolicy(ctx)
+	if err != nil {
+		return err
+	}
+	if policy.RequireAdmin2FA && user.Role == consts.UserRoleAdmin {
+		return domain.ErrTwoFactorRequired
+	}
+	if err := u.verifyTwoFactor(ctx, user, code); err != nil {
+		return err
+	}
+	return u.repo.UpdateUserTOTP(ctx, userID, "", nil)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，旧的全部失效
+func (u *UserUsecase) RegenerateRecoveryCodes(ctx context.Context, userID string, code string) (*v1.TwoFactorRecoveryCodesResp, error) {
+	user, err := u.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TOTPEnabled {
+		return nil, fmt.Errorf("two-factor authentication is not enabled")
+	}
+	if err := u.verifyTOTP(ctx, user, code); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo.UpdateUserRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return &v1.TwoFactorRecoveryCodesResp{RecoveryCodes: codes}, nil
+}
+
+// ResetTwoFactor 管理员为丢失设备的用户关闭两步验证，受策略约束的管理员下次登录时重新绑定
+func (u *UserUsecase) ResetTwoFactor(ctx context.Context, userID string) error {
+	if _, err := u.repo.GetUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := u.repo.UpdateUserTOTP(ctx, userID, "", nil); err != nil {
+		return err
+	}
+	return u.limiter.Reset(ctx, "2fa:"+userID)
+}
+
+func (u *UserUsecase) GetLoginSecurityPolicy(ctx context.Context) (*domain.LoginSecurityPolicy, error) {
+	return u.repo.GetLoginSecurityPolicy(ctx)
+}
+
+func (u *UserUsecase) SetLoginSecurityPolicy(ctx context.Context, req *v1.SetLoginSecurityPolicyReq) error {
+	return u.repo.UpsertLoginSecurityPolicy(ctx, &domain.LoginSecurityPolicy{
+		RequireAdmin2FA: req.RequireAdmin2FA,
+	})
+}
+
 func (u *UserUsecase) GetUser(ctx context.Context, userID string) (*domain.User, error) {
 	return u.repo.GetUser(ctx, userID)
 }
@@ -72,10 +459,55 @@ func (u *UserUsecase) ListUsers(ctx context.Context) (*v1.UserListResp, error) {
 	return &v1.UserListResp{Users: users}, nil
 }
 
-func (u *UserUsecase) ResetPassword(ctx context.Context, req *v1.ResetPasswordReq) error {
-	return u.repo.UpdateUserPassword(ctx, req.ID, req.NewPassword)
+// ResetPassword 修改密码后注销该用户的其他会话，keepSessionID 为当前会话
+func (u *UserUsecase) ResetPassword(ctx context.Context, req *v1.ResetPasswordReq, keepSessionID string) error {
+	if err := u.repo.UpdateUserPassword(ctx, req.ID, req.NewPassword); err != nil {
+		return err
+	}
+	_, err := u.sessionRepo.DeleteUserSessions(ctx, req.ID, keepSessionID)
+	return err
+}
+
+func (u *UserUsecase) UpdateUserEmail(ctx context.Context, req *v1.UpdateUserEmailReq) error {
+	return u.repo.UpdateUserEmail(ctx, req.ID, req.Email)
 }
 
 func (u *UserUsecase) DeleteUser(ctx context.Context, userID string) error {
-	return u.repo.DeleteUser(ctx, userID)
+	if err := u.repo.DeleteUser(ctx, userID); err != nil {
+		return err
+	}
+	_, err := u.sessionRepo.DeleteUserSessions(ctx, userID, "")
+	return err
+}
+
+func (u *UserUsecase) ListSessions(ctx context.Context, userID, currentSessionID string) (*v1.UserSessionListResp, error) {
+	sessions, err := u.sessionRepo.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]v1.UserSessionItem, 0, len(sessions))
+	for _, session := range sessions {
+		items = append(items, v1.UserSessionItem{
+			UserSession: session,
+			Current:     session.ID == currentSessionID,
+		})
+	}
+	return &v1.UserSessionListResp{Sessions: items}, nil
+}
+
+// RevokeSession 只能注销属于 userID 的会话
+func (u *UserUsecase) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	session, err := u.sessionRepo.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil || session.UserID != userID {
+		return fmt.Errorf("session not found")
+	}
+	return u.sessionRepo.DeleteSession(ctx, userID, sessionID)
+}
+
+// RevokeUserSessions 注销用户的全部会话，keepSessionID 非空时保留
+func (u *UserUsecase) RevokeUserSessions(ctx context.Context, userID, keepSessionID string) (int, error) {
+	return u.sessionRepo.DeleteUserSessions(ctx, userID, keepSessionID)
 }
@@ -81,9 +81,12 @@ func (u *WechatAppUsecase) getQAFunc(kbID string, appType domain.AppType) bot.Ge
 		}
 		info.UserInfo.AuthUserID = auth.ID
 
+		route := u.AppUsecase.routeBotQuestion(ctx, kbID, appType, msg, info, ConversationID)
 		eventCh, err := u.chatUsecase.Chat(ctx, &domain.ChatRequest{
 			Message:        msg,
-			KBID:           kbID,
+			KBID:           route.KBID,
+			MergeKBIDs:     route.MergeKBIDs,
+			AppID:          route.AppID,
 			AppType:        appType,
 			RemoteIP:       "",
 			ConversationID: ConversationID,
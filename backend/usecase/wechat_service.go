@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"slices"
 
 	"github.com/chaitin/panda-wiki/domain"
 	"github.com/chaitin/panda-wiki/log"
@@ -51,6 +52,8 @@ func (u *WechatUsecase) WechatService(ctx context.Context, msg *wechatservice.We
 }
 
 func (u *WechatUsecase) NewWechatServiceConfig(ctx context.Context, appInfo *domain.AppDetailResp, kbID string) (*wechatservice.WechatServiceConfig, error) {
+	// the escalation keywords of the kb transfer to a servicer as well
+	containKeywords := slices.Concat(appInfo.Settings.WechatServiceContainKeywords, u.chatUsecase.escalationUsecase.HandoffKeywords(ctx, kbID))
 	return wechatservice.NewWechatServiceConfig(
 		ctx,
 		u.logger,
@@ -59,7 +62,7 @@ func (u *WechatUsecase) NewWechatServiceConfig(ctx context.Context, appInfo *dom
 		appInfo.Settings.WeChatServiceEncodingAESKey,
 		kbID,
 		appInfo.Settings.WeChatServiceSecret,
-		appInfo.Settings.WechatServiceContainKeywords,
+		containKeywords,
 		appInfo.Settings.WechatServiceEqualKeywords,
 	)
 }
@@ -4,12 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/chaitin/panda-wiki/domain"
 	"github.com/chaitin/panda-wiki/log"
+	"github.com/chaitin/panda-wiki/pkg/bot"
+	"github.com/chaitin/panda-wiki/pkg/bot/render"
 	"github.com/chaitin/panda-wiki/pkg/bot/wecom"
 	"github.com/chaitin/panda-wiki/repo/pg"
 	"github.com/chaitin/panda-wiki/store/cache"
@@ -119,20 +122,27 @@ func (u *WecomUsecase) HandleMsg(ctx context.Context, kbID, signature, timestamp
 				go func() {
 					bgCtx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 					defer cancel()
+					info := domain.ConversationInfo{
+						UserInfo: domain.UserInfo{
+							AuthUserID: auth.ID,
+							UserID:     req.From.Userid,
+							NickName:   req.From.Userid,
+							From:       domain.MessageFromPrivate,
+						},
+					}
+					if req.Chatid != "" {
+						bgCtx = bot.WithConversationKey(bgCtx, &bot.ConversationKey{Chat: req.Chatid, User: req.From.Userid})
+					}
+					route := u.AppUsecase.routeBotQuestion(bgCtx, kbID, domain.AppTypeWecomAIBot, req.Text.Content, info, conversationID)
 					eventCh, err := u.chatUsecase.Chat(bgCtx, &domain.ChatRequest{
 						Message:        req.Text.Content,
-						KBID:           kbID,
+						KBID:           route.KBID,
+						MergeKBIDs:     route.MergeKBIDs,
+						AppID:          route.AppID,
 						AppType:        domain.AppTypeWecomAIBot,
 						RemoteIP:       "",
 						ConversationID: conversationID,
-						Info: domain.ConversationInfo{
-							UserInfo: domain.UserInfo{
-								AuthUserID: auth.ID,
-								UserID:     req.From.Userid,
-								NickName:   req.From.Userid,
-								From:       domain.MessageFromPrivate,
-							},
-						},
+						Info:           info,
 					})
 					if err != nil {
 						u.logger.Error("failed to create chat", log.Error(err))
@@ -185,10 +195,10 @@ func (u *WecomUsecase) HandleMsg(ctx context.Context, kbID, signature, timestamp
 
 		state := val.(*domain.ConversationState)
 		state.Mutex.Lock()
-		content := state.Buffer.String()
+		content := render.WeCom.Preview(state.Buffer.String())
 		state.Mutex.Unlock()
 
-		if content == "" {
+		if strings.TrimSpace(content) == "" {
 			content = "<think>正在思考您的问题,请稍候...</think>"
 		}
 
@@ -14,3 +14,24 @@ func IsImageFile(filename string) bool {
 
 	return slices.Contains(supportedImageExts, ext)
 }
+
+// IsDocumentFile reports whether the file can be parsed to text, e.g. as a chat attachment
+func IsDocumentFile(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	supportedDocumentExts := []string{
+		".pdf", ".doc", ".docx", ".ppt", ".pptx", ".xls", ".xlsx", ".epub", ".html", ".htm",
+		".txt", ".md", ".markdown", ".csv", ".json", ".log", ".yaml", ".yml", ".xml",
+	}
+
+	return slices.Contains(supportedDocumentExts, ext)
+}
+
+// IsPlainTextFile reports whether the file content is readable text without conversion
+func IsPlainTextFile(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	plainTextExts := []string{
+		".txt", ".md", ".markdown", ".csv", ".json", ".log", ".yaml", ".yml", ".xml",
+	}
+
+	return slices.Contains(plainTextExts, ext)
+}